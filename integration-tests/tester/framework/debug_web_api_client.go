@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/peering/peer"
 	"github.com/gohornet/hornet/plugins/webapi"
 )
@@ -147,6 +148,24 @@ func (api *WebAPI) Info() (*webapi.GetNodeInfoReturn, error) {
 
 }
 
+// LedgerState returns the ledger state of the node at the given milestone index.
+// If no target index is given, the ledger state of the latest solid milestone is returned.
+func (api *WebAPI) LedgerState(targetIndex ...milestone.Index) (*webapi.GetLedgerStateReturn, error) {
+	var index milestone.Index
+	if len(targetIndex) > 0 {
+		index = targetIndex[0]
+	}
+
+	res := &webapi.GetLedgerStateReturn{}
+	if err := api.do(http.MethodPost, struct {
+		Command     string          `json:"command"`
+		TargetIndex milestone.Index `json:"targetIndex,omitempty"`
+	}{Command: "getLedgerState", TargetIndex: index}, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 // BaseURL returns the baseURL of the API.
 func (api *WebAPI) BaseURL() string {
 	return api.baseURL
@@ -26,6 +26,8 @@ var (
 	ErrNodesDidNotPeerInTime = errors.New("nodes did not peer in time")
 	ErrNodesDidNotSyncInTime = errors.New("nodes did not sync in time")
 	ErrNodesNotOnlineInTime  = errors.New("nodes did not become online in time")
+	ErrLedgerNotConverged    = errors.New("ledger did not converge in time")
+	ErrLedgerStatesDiverged  = errors.New("ledger states of the nodes diverged")
 )
 
 // Framework is a wrapper that provides the integration testing functionality.
@@ -0,0 +1,112 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+// LedgerStateHash computes a hash over the given balances which is independent of the order of the addresses.
+// Two nodes with an identical ledger state produce identical hashes.
+func LedgerStateHash(balances map[trinary.Hash]uint64) []byte {
+	addresses := make([]trinary.Hash, 0, len(balances))
+	for address := range balances {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	h := sha256.New()
+	balanceBytes := make([]byte, 8)
+	for _, address := range addresses {
+		binary.LittleEndian.PutUint64(balanceBytes, balances[address])
+		h.Write([]byte(address))
+		h.Write(balanceBytes)
+	}
+	return h.Sum(nil)
+}
+
+// LedgerStateHash returns the hash of the ledger state of the node at the given milestone index.
+func (p *Node) LedgerStateHash(index milestone.Index) ([]byte, error) {
+	ledgerState, err := p.DebugWebAPI.LedgerState(index)
+	if err != nil {
+		return nil, err
+	}
+	if ledgerState.MilestoneIndex != index {
+		return nil, fmt.Errorf("%s: ledger state returned for milestone %d instead of %d", p.Name, ledgerState.MilestoneIndex, index)
+	}
+	return LedgerStateHash(ledgerState.Balances), nil
+}
+
+// AwaitSolidMilestone awaits until all nodes reached at least the given solid milestone index.
+func (n *Network) AwaitSolidMilestone(ctx context.Context, index milestone.Index) error {
+	log.Printf("waiting for nodes to reach solid milestone %d...", index)
+	for _, node := range n.Nodes {
+		for {
+			if err := returnErrIfCtxDone(ctx, ErrLedgerNotConverged); err != nil {
+				return err
+			}
+			// an unreachable node is polled again after the interval as well
+			info, err := node.DebugWebAPI.Info()
+			if err == nil && info.LatestSolidSubtangleMilestoneIndex >= index {
+				break
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+	}
+	return nil
+}
+
+// AwaitBalance awaits until the given address has the expected balance on all nodes.
+func (n *Network) AwaitBalance(ctx context.Context, address trinary.Hash, expected uint64) error {
+	log.Printf("waiting for address %s to have a balance of %d on all nodes...", address, expected)
+	for _, node := range n.Nodes {
+		for {
+			if err := returnErrIfCtxDone(ctx, ErrLedgerNotConverged); err != nil {
+				return err
+			}
+			ledgerState, err := node.DebugWebAPI.LedgerState()
+			if err == nil && ledgerState.Balances[address] == expected {
+				break
+			}
+			time.Sleep(time.Second)
+		}
+	}
+	return nil
+}
+
+// AwaitLedgerConvergence awaits until all nodes reached the given solid milestone index
+// and then checks that the ledger states of all nodes at that index are identical.
+func (n *Network) AwaitLedgerConvergence(ctx context.Context, index milestone.Index) error {
+	if err := n.AwaitSolidMilestone(ctx, index); err != nil {
+		return err
+	}
+
+	log.Printf("comparing ledger states of all nodes at milestone %d...", index)
+	var refHash []byte
+	var refNode *Node
+	for _, node := range n.Nodes {
+		ledgerHash, err := node.LedgerStateHash(index)
+		if err != nil {
+			return err
+		}
+
+		if refNode == nil {
+			refHash, refNode = ledgerHash, node
+			continue
+		}
+
+		if !bytes.Equal(refHash, ledgerHash) {
+			return fmt.Errorf("%w: %s (%x) != %s (%x) at milestone %d", ErrLedgerStatesDiverged, refNode.Name, refHash, node.Name, ledgerHash, index)
+		}
+	}
+	return nil
+}
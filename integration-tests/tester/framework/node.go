@@ -13,6 +13,7 @@ import (
 	"github.com/iotaledger/iota.go/bundle"
 	"github.com/iotaledger/iota.go/checksum"
 	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/transaction"
 	"github.com/iotaledger/iota.go/trinary"
 )
 
@@ -147,3 +148,43 @@ func (p *Node) Spam(dur time.Duration, depth int, parallelism int, batchSize ...
 	wg.Wait()
 	return spammed, nil
 }
+
+// SendValue issues a value bundle spending the given inputs of the seed to the given transfers.
+// The remainder of the inputs is sent to the remainder address. Returns the attached bundle.
+func (p *Node) SendValue(seed trinary.Trytes, inputs []api.Input, remainderAddress trinary.Hash, transfers bundle.Transfers) (bundle.Bundle, error) {
+	remainderAddress, err := checksum.AddChecksum(remainderAddress, true, consts.AddressChecksumTrytesSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range transfers {
+		if transfers[i].Address, err = checksum.AddChecksum(transfers[i].Address, true, consts.AddressChecksumTrytesSize); err != nil {
+			return nil, err
+		}
+	}
+
+	bndlTrytes, err := p.WebAPI.PrepareTransfers(seed, transfers, api.PrepareTransfersOptions{
+		Inputs:           inputs,
+		RemainderAddress: &remainderAddress,
+		Security:         consts.SecurityLevelMedium,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tips, err := p.WebAPI.GetTransactionsToApprove(3)
+	if err != nil {
+		return nil, err
+	}
+
+	readyTrytes, err := p.WebAPI.AttachToTangle(tips.TrunkTransaction, tips.BranchTransaction, uint64(p.Config.Coordinator.MWM), bndlTrytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.WebAPI.BroadcastTransactions(readyTrytes...); err != nil {
+		return nil, err
+	}
+
+	return transaction.AsTransactionObjects(readyTrytes, nil)
+}
@@ -0,0 +1,61 @@
+package value
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/iota.go/address"
+	"github.com/iotaledger/iota.go/api"
+	"github.com/iotaledger/iota.go/bundle"
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/integration-tests/tester/framework"
+)
+
+// TestValue boots up a statically peered network, issues a value transfer from the genesis address
+// and then checks that all nodes confirmed the transfer and converged to an identical ledger state.
+func TestValue(t *testing.T) {
+	n, err := f.CreateStaticNetwork("test_value", framework.DefaultStaticPeeringLayout)
+	require.NoError(t, err)
+	defer framework.ShutdownNetwork(t, n)
+
+	syncCtx, syncCtxCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer syncCtxCancel()
+	assert.NoError(t, n.AwaitAllSync(syncCtx))
+
+	ledgerState, err := n.Coordinator().DebugWebAPI.LedgerState()
+	require.NoError(t, err)
+	genesisBalance := ledgerState.Balances[framework.GenesisAddress]
+	require.NotZero(t, genesisBalance)
+
+	remainderAddress, err := address.GenerateAddress(framework.GenesisSeed, 1, consts.SecurityLevelMedium, false)
+	require.NoError(t, err)
+	targetAddress, err := address.GenerateAddress(framework.GenesisSeed, 2, consts.SecurityLevelMedium, false)
+	require.NoError(t, err)
+
+	const value = 1000000
+	inputs := []api.Input{{
+		Address:  framework.GenesisAddress,
+		KeyIndex: 0,
+		Security: consts.SecurityLevelMedium,
+		Balance:  genesisBalance,
+	}}
+	transfers := bundle.Transfers{{Address: targetAddress, Value: value}}
+
+	// issue the transfer on a random node, so that it needs to be gossiped to the coordinator
+	_, err = n.RandomNode().SendValue(framework.GenesisSeed, inputs, remainderAddress, transfers)
+	require.NoError(t, err)
+
+	confirmCtx, confirmCtxCancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer confirmCtxCancel()
+	require.NoError(t, n.AwaitBalance(confirmCtx, targetAddress, value))
+	require.NoError(t, n.AwaitBalance(confirmCtx, remainderAddress, genesisBalance-value))
+	require.NoError(t, n.AwaitBalance(confirmCtx, framework.GenesisAddress, 0))
+
+	info, err := n.Coordinator().DebugWebAPI.Info()
+	require.NoError(t, err)
+	assert.NoError(t, n.AwaitLedgerConvergence(confirmCtx, info.LatestSolidSubtangleMilestoneIndex))
+}
@@ -3,15 +3,20 @@ package main
 import (
 	"github.com/iotaledger/hive.go/node"
 
+	"github.com/gohornet/hornet/pkg/app"
 	"github.com/gohornet/hornet/pkg/config"
 	"github.com/gohornet/hornet/pkg/toolset"
+	"github.com/gohornet/hornet/plugins/archiver"
 	"github.com/gohornet/hornet/plugins/autopeering"
+	"github.com/gohornet/hornet/plugins/backup"
 	"github.com/gohornet/hornet/plugins/cli"
 	"github.com/gohornet/hornet/plugins/coordinator"
 	"github.com/gohornet/hornet/plugins/dashboard"
 	"github.com/gohornet/hornet/plugins/database"
+	"github.com/gohornet/hornet/plugins/eventlog"
 	"github.com/gohornet/hornet/plugins/gossip"
 	"github.com/gohornet/hornet/plugins/gracefulshutdown"
+	"github.com/gohornet/hornet/plugins/grpcapi"
 	"github.com/gohornet/hornet/plugins/metrics"
 	"github.com/gohornet/hornet/plugins/mqtt"
 	"github.com/gohornet/hornet/plugins/peering"
@@ -46,6 +51,7 @@ func main() {
 
 	if !config.NodeConfig.GetBool(config.CfgNetAutopeeringRunAsEntryNode) {
 		plugins = append(plugins, []*node.Plugin{
+			eventlog.PLUGIN,
 			pow.PLUGIN,
 			gossip.PLUGIN,
 			tangle.PLUGIN,
@@ -54,14 +60,17 @@ func main() {
 			urts.PLUGIN,
 			metrics.PLUGIN,
 			snapshot.PLUGIN,
+			archiver.PLUGIN,
+			backup.PLUGIN,
 			dashboard.PLUGIN,
 			zmq.PLUGIN,
 			mqtt.PLUGIN,
+			grpcapi.PLUGIN,
 			spammer.PLUGIN,
 			coordinator.PLUGIN,
 			prometheus.PLUGIN,
 		}...)
 	}
 
-	node.Run(node.Plugins(plugins...))
+	app.Run(plugins...)
 }
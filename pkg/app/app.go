@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/hive.go/node"
+)
+
+var (
+	// the constructors of the objects the plugins provide.
+	providers = make(map[*node.Plugin][]interface{})
+	// the functions which receive the dependencies of the plugins.
+	depsFuncs = make(map[*node.Plugin][]interface{})
+)
+
+// Provide registers a constructor of an object the given plugin provides to other plugins.
+// The constructor is only registered if the plugin is enabled. See Container.Provide for the signature of constructors.
+func Provide(plugin *node.Plugin, constructor interface{}) {
+	providers[plugin] = append(providers[plugin], constructor)
+}
+
+// Inject registers a function which is invoked with the dependencies of the given plugin before the plugins are configured.
+// The function is only invoked if the plugin is enabled. See Container.Invoke for the signature of the function.
+func Inject(plugin *node.Plugin, depsFunc interface{}) {
+	depsFuncs[plugin] = append(depsFuncs[plugin], depsFunc)
+}
+
+// Run runs the node with the given plugins.
+// First the constructors of all enabled plugins are registered (provide stage), then the dependencies of all
+// enabled plugins are constructed and injected (inject stage). Afterwards the plugins are configured and run.
+// The node runs until it gets shut down, the background workers of the plugins are stopped in the order of their shutdown priorities.
+func Run(plugins ...*node.Plugin) {
+	container := NewContainer()
+
+	// provide
+	for _, plugin := range plugins {
+		if node.IsSkipped(plugin) {
+			continue
+		}
+
+		for _, constructor := range providers[plugin] {
+			if err := container.Provide(constructor); err != nil {
+				panic(fmt.Sprintf("plugin %s: %s", plugin.Name, err))
+			}
+		}
+	}
+
+	// inject
+	for _, plugin := range plugins {
+		if node.IsSkipped(plugin) {
+			continue
+		}
+
+		for _, depsFunc := range depsFuncs[plugin] {
+			if err := container.Invoke(depsFunc); err != nil {
+				panic(fmt.Sprintf("plugin %s: unable to inject the dependencies: %s", plugin.Name, err))
+			}
+		}
+	}
+
+	// configure and run
+	node.Run(node.Plugins(plugins...))
+}
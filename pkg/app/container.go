@@ -0,0 +1,217 @@
+package app
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidConstructor is returned when a constructor is not a function which returns at least one object.
+	ErrInvalidConstructor = errors.New("invalid constructor")
+	// ErrInvalidFunction is returned when a function which should be invoked is not a function.
+	ErrInvalidFunction = errors.New("invalid function")
+	// ErrAlreadyProvided is returned when an object of the same type was already provided.
+	ErrAlreadyProvided = errors.New("already provided")
+	// ErrMissingDependency is returned when no object of a requested type was provided.
+	ErrMissingDependency = errors.New("missing dependency")
+	// ErrCyclicDependency is returned when the constructors of objects depend on each other.
+	ErrCyclicDependency = errors.New("cyclic dependency")
+
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	inType    = reflect.TypeOf(In{})
+)
+
+// In can be embedded into a struct to request all exported fields of the struct as dependencies.
+// Fields tagged with `optional:"true"` are left empty if no object of their type was provided.
+type In struct{}
+
+// provider constructs the objects of a constructor.
+type provider struct {
+	constructor reflect.Value
+	resolving   bool
+	resolved    bool
+	results     []reflect.Value
+	err         error
+}
+
+// Container holds the constructors of the objects of the node and resolves the dependencies between them.
+// Every object is constructed once when it is requested for the first time.
+type Container struct {
+	sync.Mutex
+	providers map[reflect.Type]*provider
+}
+
+// NewContainer creates a new Container.
+func NewContainer() *Container {
+	return &Container{
+		providers: make(map[reflect.Type]*provider),
+	}
+}
+
+// Provide registers the given constructor.
+// The constructor is a function whose parameters are its dependencies and which returns the objects it provides,
+// optionally followed by an error.
+func (c *Container) Provide(constructor interface{}) error {
+	c.Lock()
+	defer c.Unlock()
+
+	value := reflect.ValueOf(constructor)
+	if value.Kind() != reflect.Func {
+		return errors.Wrapf(ErrInvalidConstructor, "%T is not a function", constructor)
+	}
+
+	resultTypes := providedTypes(value.Type())
+	if len(resultTypes) == 0 {
+		return errors.Wrapf(ErrInvalidConstructor, "%T does not return an object", constructor)
+	}
+
+	for _, resultType := range resultTypes {
+		if _, exists := c.providers[resultType]; exists {
+			return errors.Wrapf(ErrAlreadyProvided, "%v", resultType)
+		}
+	}
+
+	p := &provider{constructor: value}
+	for _, resultType := range resultTypes {
+		c.providers[resultType] = p
+	}
+	return nil
+}
+
+// Invoke calls the given function with its dependencies as parameters.
+// If the last result of the function is an error, it is returned.
+func (c *Container) Invoke(function interface{}) error {
+	c.Lock()
+	defer c.Unlock()
+
+	value := reflect.ValueOf(function)
+	if value.Kind() != reflect.Func {
+		return errors.Wrapf(ErrInvalidFunction, "%T is not a function", function)
+	}
+
+	args, err := c.resolveParams(value.Type())
+	if err != nil {
+		return err
+	}
+
+	results := value.Call(args)
+	if len(results) > 0 && value.Type().Out(len(results)-1) == errorType {
+		if err, _ := results[len(results)-1].Interface().(error); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// returns the types of the objects the given constructor provides.
+func providedTypes(constructorType reflect.Type) []reflect.Type {
+	var resultTypes []reflect.Type
+	for i := 0; i < constructorType.NumOut(); i++ {
+		resultType := constructorType.Out(i)
+		if i == constructorType.NumOut()-1 && resultType == errorType {
+			break
+		}
+		resultTypes = append(resultTypes, resultType)
+	}
+	return resultTypes
+}
+
+func (c *Container) resolveParams(funcType reflect.Type) ([]reflect.Value, error) {
+	args := make([]reflect.Value, funcType.NumIn())
+	for i := range args {
+		arg, err := c.resolve(funcType.In(i))
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// returns the object of the given type and constructs it if needed.
+func (c *Container) resolve(t reflect.Type) (reflect.Value, error) {
+	if isInStruct(t) {
+		return c.resolveInStruct(t)
+	}
+
+	p, exists := c.providers[t]
+	if !exists {
+		return reflect.Value{}, errors.Wrapf(ErrMissingDependency, "%v", t)
+	}
+
+	if !p.resolved {
+		if p.resolving {
+			return reflect.Value{}, errors.Wrapf(ErrCyclicDependency, "%v", t)
+		}
+
+		p.resolving = true
+		p.results, p.err = c.construct(p.constructor)
+		p.resolving = false
+		p.resolved = true
+	}
+
+	if p.err != nil {
+		return reflect.Value{}, errors.Wrapf(p.err, "constructing %v failed", t)
+	}
+
+	for _, result := range p.results {
+		if result.Type() == t {
+			return result, nil
+		}
+	}
+	return reflect.Value{}, errors.Wrapf(ErrMissingDependency, "%v", t)
+}
+
+// calls the given constructor with its dependencies and returns the provided objects.
+func (c *Container) construct(constructor reflect.Value) ([]reflect.Value, error) {
+	args, err := c.resolveParams(constructor.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	results := constructor.Call(args)
+	resultTypes := providedTypes(constructor.Type())
+	if len(results) > len(resultTypes) {
+		if err, _ := results[len(results)-1].Interface().(error); err != nil {
+			return nil, err
+		}
+	}
+	return results[:len(resultTypes)], nil
+}
+
+// fills all exported fields of a struct which embeds In.
+func (c *Container) resolveInStruct(t reflect.Type) (reflect.Value, error) {
+	value := reflect.New(t).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type == inType || field.PkgPath != "" {
+			// the marker or an unexported field
+			continue
+		}
+
+		if _, provided := c.providers[field.Type]; !provided && field.Tag.Get("optional") == "true" {
+			continue
+		}
+
+		fieldValue, err := c.resolve(field.Type)
+		if err != nil {
+			return reflect.Value{}, errors.Wrapf(err, "field %s of %v", field.Name, t)
+		}
+		value.Field(i).Set(fieldValue)
+	}
+	return value, nil
+}
+
+func isInStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == inType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStorage struct {
+	name string
+}
+
+type testManager struct {
+	storage *testStorage
+}
+
+type testDeps struct {
+	In
+	Storage  *testStorage
+	Manager  *testManager
+	Optional *testing.T `optional:"true"`
+}
+
+func TestContainer(t *testing.T) {
+	c := NewContainer()
+
+	storageConstructions := 0
+	require.NoError(t, c.Provide(func() *testStorage {
+		storageConstructions++
+		return &testStorage{name: "storage"}
+	}))
+	require.NoError(t, c.Provide(func(storage *testStorage) (*testManager, error) {
+		return &testManager{storage: storage}, nil
+	}))
+
+	assert.True(t, errors.Is(c.Provide(func() *testStorage { return nil }), ErrAlreadyProvided))
+	assert.True(t, errors.Is(c.Provide(func() error { return nil }), ErrInvalidConstructor))
+	assert.True(t, errors.Is(c.Provide("no function"), ErrInvalidConstructor))
+
+	var deps testDeps
+	require.NoError(t, c.Invoke(func(d testDeps) {
+		deps = d
+	}))
+	assert.Equal(t, "storage", deps.Storage.name)
+	assert.Same(t, deps.Storage, deps.Manager.storage)
+	assert.Nil(t, deps.Optional)
+
+	// every object is only constructed once
+	require.NoError(t, c.Invoke(func(*testStorage) {}))
+	assert.Equal(t, 1, storageConstructions)
+
+	// errors of the invoked function are returned
+	errTest := errors.New("test")
+	assert.True(t, errors.Is(c.Invoke(func() error { return errTest }), errTest))
+}
+
+func TestContainerDependencyErrors(t *testing.T) {
+	c := NewContainer()
+
+	assert.True(t, errors.Is(c.Invoke(func(*testStorage) {}), ErrMissingDependency))
+
+	require.NoError(t, c.Provide(func(*testManager) *testStorage { return nil }))
+	require.NoError(t, c.Provide(func(*testStorage) *testManager { return nil }))
+	assert.True(t, errors.Is(c.Invoke(func(*testStorage) {}), ErrCyclicDependency))
+
+	errConstruction := errors.New("construction failed")
+	require.NoError(t, c.Provide(func() (int, error) { return 0, errConstruction }))
+	assert.True(t, errors.Is(c.Invoke(func(int) {}), errConstruction))
+}
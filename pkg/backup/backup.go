@@ -0,0 +1,138 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+const (
+	// the key below the prefix of a target the local snapshot files are stored at.
+	snapshotsLocalKey = "snapshots/local"
+	// the key below the prefix of a target the delta snapshot files are stored at.
+	snapshotsDeltaKey = "snapshots/delta"
+	// the key below the prefix of a target the database backups are stored at.
+	databaseKey = "database"
+
+	// the layout of the names of the database backup sets, which sorts chronologically.
+	databaseSetLayout = "20060102T150405Z"
+)
+
+var (
+	// ErrIntegrityCheckFailed is returned if an uploaded object doesn't match the local file.
+	ErrIntegrityCheckFailed = errors.New("integrity check failed")
+)
+
+// Object is an object stored in a backup target.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Target is a remote storage backups are uploaded to.
+type Target interface {
+	// Upload stores the content of r with the given size at the given key.
+	// The upload fails with ErrIntegrityCheckFailed if the target didn't store the content unaltered.
+	Upload(ctx context.Context, key string, r io.ReaderAt, size int64) error
+	// List returns all objects whose key starts with the given prefix.
+	List(ctx context.Context, prefix string) ([]*Object, error)
+	// Delete removes the object with the given key.
+	Delete(ctx context.Context, key string) error
+}
+
+// UploadFile uploads the file at the given path to the given key of the target.
+func UploadFile(ctx context.Context, target Target, key string, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := target.Upload(ctx, key, f, info.Size()); err != nil {
+		return fmt.Errorf("upload of %s to %s failed: %w", filePath, key, err)
+	}
+	return nil
+}
+
+// SnapshotSetPrefix returns the key prefix of the snapshot files of the given kind.
+func SnapshotSetPrefix(prefix string, isDelta bool) string {
+	if isDelta {
+		return path.Join(prefix, snapshotsDeltaKey) + "/"
+	}
+	return path.Join(prefix, snapshotsLocalKey) + "/"
+}
+
+// SnapshotKey returns the key a snapshot file is uploaded to.
+// Every snapshot is a set of its own, named after its zero padded target index.
+func SnapshotKey(prefix string, isDelta bool, targetIndex milestone.Index, filePath string) string {
+	return SnapshotSetPrefix(prefix, isDelta) + fmt.Sprintf("%010d/%s", targetIndex, path.Base(filePath))
+}
+
+// DatabaseSetPrefix returns the key prefix of the database backups.
+func DatabaseSetPrefix(prefix string) string {
+	return path.Join(prefix, databaseKey) + "/"
+}
+
+// DatabaseKey returns the key a database backup file created at the given time is uploaded to.
+// The databases backed up at the same time form a set.
+func DatabaseKey(prefix string, created time.Time, filePath string) string {
+	return DatabaseSetPrefix(prefix) + created.UTC().Format(databaseSetLayout) + "/" + path.Base(filePath)
+}
+
+// ApplyRetention deletes all but the newest keep sets below the given set prefix.
+// A set are all objects whose keys share the same element after the set prefix, the sets are ordered by that element.
+// It returns the keys of the deleted objects, keep values below 1 keep all sets.
+func ApplyRetention(ctx context.Context, target Target, setPrefix string, keep int) ([]string, error) {
+	if keep < 1 {
+		return nil, nil
+	}
+
+	objects, err := target.List(ctx, setPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make(map[string][]string)
+	for _, obj := range objects {
+		set := strings.TrimPrefix(obj.Key, setPrefix)
+		if i := strings.Index(set, "/"); i >= 0 {
+			set = set[:i]
+		}
+		sets[set] = append(sets[set], obj.Key)
+	}
+
+	if len(sets) <= keep {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(sets))
+	for name := range sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var deleted []string
+	for _, name := range names[:len(names)-keep] {
+		for _, key := range sets[name] {
+			if err := target.Delete(ctx, key); err != nil {
+				return deleted, err
+			}
+			deleted = append(deleted, key)
+		}
+	}
+	return deleted, nil
+}
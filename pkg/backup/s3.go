@@ -0,0 +1,469 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// objects up to this size are uploaded with a single request, bigger ones in parts of this size.
+	defaultS3PartSize = 64 << 20
+	// the maximum amount of parts of a multipart upload.
+	s3MaxParts = 10000
+
+	s3SignAlgorithm = "AWS4-HMAC-SHA256"
+	s3DateLayout    = "20060102T150405Z"
+	// the user metadata the SHA-256 hash of an uploaded file is stored in, so it can be verified after a download.
+	s3MetaSHA256Header = "X-Amz-Meta-Sha256"
+	// the SHA-256 hash of the payload of requests without body.
+	s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+var (
+	// ErrInvalidS3Options is returned if the options of an S3 target are incomplete.
+	ErrInvalidS3Options = errors.New("invalid S3 options")
+)
+
+// S3Options define the S3 compatible storage a target uploads to.
+type S3Options struct {
+	// the URL of the S3 API, e.g. "https://s3.eu-central-1.amazonaws.com".
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// whether the bucket is addressed in the path instead of the host name, which most S3 compatible storages need.
+	PathStyle bool
+}
+
+// S3Error is the error response of an S3 request.
+type S3Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *S3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("S3 request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("S3 request failed with status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// S3Target uploads backups to an S3 compatible storage.
+// Requests are signed with AWS signature version 4, the payload of every request is verified
+// by the storage with its MD5 and SHA-256 hashes.
+type S3Target struct {
+	opts     S3Options
+	endpoint *url.URL
+	client   *http.Client
+	partSize int64
+	now      func() time.Time
+}
+
+// NewS3Target creates a target for the given S3 compatible storage.
+func NewS3Target(opts S3Options) (*S3Target, error) {
+	if opts.Endpoint == "" || opts.Region == "" || opts.Bucket == "" {
+		return nil, fmt.Errorf("%w: endpoint, region and bucket are required", ErrInvalidS3Options)
+	}
+
+	endpoint, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidS3Options, err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("%w: endpoint %s is no http(s) URL", ErrInvalidS3Options, opts.Endpoint)
+	}
+
+	return &S3Target{
+		opts:     opts,
+		endpoint: endpoint,
+		client:   &http.Client{},
+		partSize: defaultS3PartSize,
+		now:      time.Now,
+	}, nil
+}
+
+// Upload stores the content of r at the given key.
+// Bigger contents are uploaded in parts, an incomplete multipart upload is aborted.
+func (t *S3Target) Upload(ctx context.Context, key string, r io.ReaderAt, size int64) error {
+	if size <= t.partSize {
+		if err := t.putObject(ctx, key, r, size); err != nil {
+			return err
+		}
+	} else {
+		if err := t.multipartUpload(ctx, key, r, size); err != nil {
+			return err
+		}
+	}
+
+	return t.verifySize(ctx, key, size)
+}
+
+// List returns all objects whose key starts with the given prefix.
+func (t *S3Target) List(ctx context.Context, prefix string) ([]*Object, error) {
+	var objects []*Object
+
+	continuationToken := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		res, err := t.do(ctx, http.MethodGet, "", query, nil, nil, 0, s3EmptyPayloadHash)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &struct {
+			Contents []struct {
+				Key          string
+				Size         int64
+				LastModified time.Time
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}{}
+		if err := decodeResponse(res, result); err != nil {
+			return nil, err
+		}
+
+		for _, content := range result.Contents {
+			objects = append(objects, &Object{Key: content.Key, Size: content.Size, LastModified: content.LastModified})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// Delete removes the object with the given key.
+func (t *S3Target) Delete(ctx context.Context, key string) error {
+	res, err := t.do(ctx, http.MethodDelete, key, nil, nil, nil, 0, s3EmptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (t *S3Target) putObject(ctx context.Context, key string, r io.ReaderAt, size int64) error {
+	md5Hash, sha256Hash, err := hashSection(r, 0, size)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Hash))
+	header.Set(s3MetaSHA256Header, hex.EncodeToString(sha256Hash))
+
+	res, err := t.do(ctx, http.MethodPut, key, nil, header, io.NewSectionReader(r, 0, size), size, hex.EncodeToString(sha256Hash))
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (t *S3Target) multipartUpload(ctx context.Context, key string, r io.ReaderAt, size int64) error {
+	partCount := (size + t.partSize - 1) / t.partSize
+	if partCount > s3MaxParts {
+		return fmt.Errorf("object of %d bytes exceeds the maximum of %d parts", size, s3MaxParts)
+	}
+
+	// the hash of the whole file is stored in the metadata, which is set when the upload is created
+	_, sha256Hash, err := hashSection(r, 0, size)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set(s3MetaSHA256Header, hex.EncodeToString(sha256Hash))
+
+	res, err := t.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, header, nil, 0, s3EmptyPayloadHash)
+	if err != nil {
+		return err
+	}
+
+	initiated := &struct {
+		UploadId string
+	}{}
+	if err := decodeResponse(res, initiated); err != nil {
+		return err
+	}
+
+	if err := t.uploadParts(ctx, key, initiated.UploadId, r, size, partCount); err != nil {
+		// the already uploaded parts would be charged until the upload is aborted
+		abortCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if res, abortErr := t.do(abortCtx, http.MethodDelete, key, url.Values{"uploadId": {initiated.UploadId}}, nil, nil, 0, s3EmptyPayloadHash); abortErr == nil {
+			res.Body.Close()
+		}
+		return err
+	}
+	return nil
+}
+
+func (t *S3Target) uploadParts(ctx context.Context, key string, uploadID string, r io.ReaderAt, size int64, partCount int64) error {
+	type part struct {
+		PartNumber int64
+		ETag       string
+	}
+	completion := &struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []part   `xml:"Part"`
+	}{}
+
+	for partNumber := int64(1); partNumber <= partCount; partNumber++ {
+		offset := (partNumber - 1) * t.partSize
+		partSize := t.partSize
+		if offset+partSize > size {
+			partSize = size - offset
+		}
+
+		md5Hash, sha256Hash, err := hashSection(r, offset, partSize)
+		if err != nil {
+			return err
+		}
+
+		header := http.Header{}
+		header.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Hash))
+
+		query := url.Values{}
+		query.Set("partNumber", strconv.FormatInt(partNumber, 10))
+		query.Set("uploadId", uploadID)
+
+		res, err := t.do(ctx, http.MethodPut, key, query, header, io.NewSectionReader(r, offset, partSize), partSize, hex.EncodeToString(sha256Hash))
+		if err != nil {
+			return fmt.Errorf("upload of part %d failed: %w", partNumber, err)
+		}
+		res.Body.Close()
+
+		completion.Parts = append(completion.Parts, part{PartNumber: partNumber, ETag: res.Header.Get("ETag")})
+	}
+
+	body, err := xml.Marshal(completion)
+	if err != nil {
+		return err
+	}
+	bodyHash := sha256.Sum256(body)
+
+	res, err := t.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, nil, bytes.NewReader(body), int64(len(body)), hex.EncodeToString(bodyHash[:]))
+	if err != nil {
+		return err
+	}
+
+	// the completion can fail after the status was already sent, the error is then contained in the body
+	completed := &struct {
+		XMLName xml.Name
+		Code    string
+		Message string
+	}{}
+	if err := decodeResponse(res, completed); err != nil {
+		return err
+	}
+	if completed.XMLName.Local == "Error" {
+		return &S3Error{StatusCode: res.StatusCode, Code: completed.Code, Message: completed.Message}
+	}
+	return nil
+}
+
+// verifySize checks whether the stored object has the size of the uploaded content.
+func (t *S3Target) verifySize(ctx context.Context, key string, size int64) error {
+	res, err := t.do(ctx, http.MethodHead, key, nil, nil, nil, 0, s3EmptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	if res.ContentLength != size {
+		return fmt.Errorf("%w: %s has %d bytes instead of %d", ErrIntegrityCheckFailed, key, res.ContentLength, size)
+	}
+	return nil
+}
+
+// objectURL returns the URL of the object with the given key, the bucket itself if the key is empty.
+func (t *S3Target) objectURL(key string, query url.Values) *url.URL {
+	u := *t.endpoint
+	basePath := strings.TrimSuffix(u.Path, "/")
+
+	if t.opts.PathStyle {
+		u.Path = basePath + "/" + t.opts.Bucket + "/" + key
+		u.RawPath = uriEncode(basePath, false) + "/" + uriEncode(t.opts.Bucket, true) + "/" + uriEncode(key, false)
+	} else {
+		u.Host = t.opts.Bucket + "." + u.Host
+		u.Path = basePath + "/" + key
+		u.RawPath = uriEncode(basePath, false) + "/" + uriEncode(key, false)
+	}
+	u.RawQuery = canonicalQuery(query)
+
+	return &u
+}
+
+// do sends a signed request and returns the response if it was successful.
+func (t *S3Target) do(ctx context.Context, method string, key string, query url.Values, header http.Header, body io.Reader, contentLength int64, payloadHash string) (*http.Response, error) {
+	if body == nil || contentLength == 0 {
+		body = http.NoBody
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.endpoint.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.URL = t.objectURL(key, query)
+	req.Host = req.URL.Host
+	req.ContentLength = contentLength
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	t.sign(req, payloadHash, t.now())
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 300 {
+		defer res.Body.Close()
+
+		// responses to HEAD requests have no body
+		errorResponse := &struct {
+			Code    string
+			Message string
+		}{}
+		if data, err := ioutil.ReadAll(res.Body); err == nil && len(data) > 0 {
+			_ = xml.Unmarshal(data, errorResponse)
+		}
+		return nil, &S3Error{StatusCode: res.StatusCode, Code: errorResponse.Code, Message: errorResponse.Message}
+	}
+
+	return res, nil
+}
+
+// sign adds the AWS signature version 4 of the request, covering all headers set so far.
+func (t *S3Target) sign(req *http.Request, payloadHash string, now time.Time) {
+	date := now.UTC().Format(s3DateLayout)
+	req.Header.Set("X-Amz-Date", date)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.Host}
+	for name, values := range req.Header {
+		trimmed := make([]string, len(values))
+		for i, value := range values {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date[:8] + "/" + t.opts.Region + "/s3/aws4_request"
+	stringToSign := s3SignAlgorithm + "\n" + date + "\n" + scope + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+t.opts.SecretAccessKey), date[:8])
+	for _, element := range []string{t.opts.Region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, element)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", s3SignAlgorithm, t.opts.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// hashSection returns the MD5 and SHA-256 hashes of the given section of r.
+func hashSection(r io.ReaderAt, offset int64, size int64) ([]byte, []byte, error) {
+	md5Hash := md5.New()
+	sha256Hash := sha256.New()
+
+	if _, err := io.Copy(io.MultiWriter(md5Hash, sha256Hash), io.NewSectionReader(r, offset, size)); err != nil {
+		return nil, nil, err
+	}
+	return md5Hash.Sum(nil), sha256Hash.Sum(nil), nil
+}
+
+// uriEncode encodes everything except the unreserved characters, as required by the signature.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// canonicalQuery encodes the query sorted by parameter names, as required by the signature.
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var params []string
+	for _, name := range names {
+		values := append([]string{}, query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// decodeResponse decodes the XML body of a response and closes it.
+func decodeResponse(res *http.Response, v interface{}) error {
+	defer res.Body.Close()
+	return xml.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,229 @@
+package budget
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/gohornet/hornet/pkg/config"
+)
+
+const (
+	// ResourceWorkers is the name of the worker resource.
+	ResourceWorkers = "workers"
+	// ResourceGoroutines is the name of the goroutine resource.
+	ResourceGoroutines = "goroutines"
+	// ResourceMemory is the name of the tracked memory resource.
+	ResourceMemory = "memory"
+	// ResourceCPU is the name of the CPU resource.
+	ResourceCPU = "cpu"
+)
+
+var (
+	budgetsLock sync.RWMutex
+	budgets     = make(map[string]*Budget)
+	budgetOpts  = make(map[string]*Opts)
+	loadOnce    sync.Once
+)
+
+// Opts defines the resource limits of a plugin. A zero value means unlimited.
+type Opts struct {
+	// MaxWorkers is the maximum amount of workers per worker pool of the plugin.
+	MaxWorkers int `mapstructure:"maxWorkers"`
+	// MaxQueueSize is the maximum queue size per worker pool of the plugin.
+	MaxQueueSize int `mapstructure:"maxQueueSize"`
+	// MaxGoroutines is the maximum amount of goroutines spawned via the budget at the same time.
+	MaxGoroutines int `mapstructure:"maxGoroutines"`
+	// MaxMemoryBytes is the maximum amount of tracked allocated memory in bytes.
+	MaxMemoryBytes int64 `mapstructure:"maxMemoryBytes"`
+	// MaxCPUUsage is the maximum share of one CPU core spent in tracked tasks (e.g. 0.5 = 50%).
+	MaxCPUUsage float64 `mapstructure:"maxCPUUsage"`
+}
+
+// Budget tracks the resource usage of a plugin against its configured limits.
+type Budget struct {
+	name string
+	opts Opts
+
+	goroutines atomic.Int32
+	memory     atomic.Int64
+	busyTime   atomic.Int64
+	exceeded   atomic.Uint32
+
+	lastCheck    time.Time
+	lastBusyTime int64
+	cpuUsage     atomic.Float64
+}
+
+// loadOpts reads the configured budgets from the node config.
+// Plugin names are case insensitive.
+func loadOpts() {
+	if !config.NodeConfig.IsSet(config.CfgNodePluginBudgets) {
+		return
+	}
+
+	opts := make(map[string]*Opts)
+	if err := config.NodeConfig.UnmarshalKey(config.CfgNodePluginBudgets, &opts); err != nil {
+		panic(err)
+	}
+
+	for name, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		budgetOpts[strings.ToLower(name)] = opt
+	}
+}
+
+// ForPlugin returns the budget of the plugin with the given name.
+// If no budget was configured for this plugin, the returned budget is unlimited.
+func ForPlugin(name string) *Budget {
+	key := strings.ToLower(name)
+
+	loadOnce.Do(loadOpts)
+
+	budgetsLock.Lock()
+	defer budgetsLock.Unlock()
+
+	if b, exists := budgets[key]; exists {
+		return b
+	}
+
+	b := &Budget{name: name, lastCheck: time.Now()}
+	if opts, exists := budgetOpts[key]; exists {
+		b.opts = *opts
+	}
+	budgets[key] = b
+
+	return b
+}
+
+// ForEach calls the consumer for every budget that was requested by a plugin.
+func ForEach(consumer func(b *Budget)) {
+	budgetsLock.RLock()
+	defer budgetsLock.RUnlock()
+
+	for _, b := range budgets {
+		consumer(b)
+	}
+}
+
+// Name returns the name of the plugin the budget belongs to.
+func (b *Budget) Name() string {
+	return b.name
+}
+
+// Opts returns the configured limits of the budget.
+func (b *Budget) Opts() Opts {
+	return b.opts
+}
+
+// WorkerCount caps the requested worker count of a worker pool to the budget.
+func (b *Budget) WorkerCount(requested int) int {
+	if b.opts.MaxWorkers > 0 && requested > b.opts.MaxWorkers {
+		return b.opts.MaxWorkers
+	}
+	return requested
+}
+
+// QueueSize caps the requested queue size of a worker pool to the budget.
+func (b *Budget) QueueSize(requested int) int {
+	if b.opts.MaxQueueSize > 0 && requested > b.opts.MaxQueueSize {
+		return b.opts.MaxQueueSize
+	}
+	return requested
+}
+
+// Go runs the given function in a new goroutine if the goroutine budget is not exhausted.
+// The execution time of the function is accounted to the CPU budget.
+func (b *Budget) Go(f func()) bool {
+	if goroutines := int(b.goroutines.Inc()); b.opts.MaxGoroutines > 0 && goroutines > b.opts.MaxGoroutines {
+		b.goroutines.Dec()
+		b.markExceeded(ResourceGoroutines, float64(goroutines), float64(b.opts.MaxGoroutines))
+		return false
+	}
+
+	go func() {
+		defer b.goroutines.Dec()
+		b.Track(f)
+	}()
+
+	return true
+}
+
+// Track runs the given function and accounts its execution time to the CPU budget.
+func (b *Budget) Track(f func()) {
+	ts := time.Now()
+	defer func() {
+		b.busyTime.Add(int64(time.Since(ts)))
+	}()
+	f()
+}
+
+// Alloc accounts the given amount of bytes to the memory budget.
+// It returns false and does not account the bytes if the budget would be exceeded.
+func (b *Budget) Alloc(bytes int64) bool {
+	if newMemory := b.memory.Add(bytes); b.opts.MaxMemoryBytes > 0 && newMemory > b.opts.MaxMemoryBytes {
+		b.memory.Sub(bytes)
+		b.markExceeded(ResourceMemory, float64(newMemory), float64(b.opts.MaxMemoryBytes))
+		return false
+	}
+	return true
+}
+
+// Free releases the given amount of bytes from the memory budget.
+func (b *Budget) Free(bytes int64) {
+	b.memory.Sub(bytes)
+}
+
+// Goroutines returns the amount of goroutines currently running via the budget.
+func (b *Budget) Goroutines() int {
+	return int(b.goroutines.Load())
+}
+
+// Memory returns the currently tracked allocated memory in bytes.
+func (b *Budget) Memory() int64 {
+	return b.memory.Load()
+}
+
+// CPUUsage returns the share of one CPU core spent in tracked tasks between the last two checks.
+func (b *Budget) CPUUsage() float64 {
+	return b.cpuUsage.Load()
+}
+
+// ExceededCount returns how often the budget was exceeded.
+func (b *Budget) ExceededCount() uint32 {
+	return b.exceeded.Load()
+}
+
+// check updates the CPU usage of the budget and verifies it against the limit.
+// it must only be called by a single goroutine.
+func (b *Budget) check() {
+	now := time.Now()
+	busyTime := b.busyTime.Load()
+
+	if elapsed := now.Sub(b.lastCheck); elapsed > 0 {
+		b.cpuUsage.Store(float64(busyTime-b.lastBusyTime) / float64(elapsed))
+	}
+	b.lastCheck = now
+	b.lastBusyTime = busyTime
+
+	if b.opts.MaxCPUUsage > 0 && b.CPUUsage() > b.opts.MaxCPUUsage {
+		b.markExceeded(ResourceCPU, b.CPUUsage(), b.opts.MaxCPUUsage)
+	}
+}
+
+func (b *Budget) markExceeded(resource string, usage float64, limit float64) {
+	b.exceeded.Inc()
+	Events.BudgetExceeded.Trigger(b, resource, usage, limit)
+}
+
+// CheckAll updates the usage of all budgets and triggers BudgetExceeded events for exceeded CPU limits.
+// It is meant to be called periodically by a single background worker.
+func CheckAll() {
+	ForEach(func(b *Budget) {
+		b.check()
+	})
+}
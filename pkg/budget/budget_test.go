@@ -0,0 +1,116 @@
+package budget
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/events"
+)
+
+func newTestBudget(opts Opts) *Budget {
+	return &Budget{name: "test", opts: opts, lastCheck: time.Now()}
+}
+
+// records the resources of the BudgetExceeded events of the given budget.
+func recordExceeded(t *testing.T, b *Budget) func() []string {
+	var lock sync.Mutex
+	var resources []string
+
+	closure := events.NewClosure(func(exceeded *Budget, resource string, _ float64, _ float64) {
+		if exceeded != b {
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		resources = append(resources, resource)
+	})
+	Events.BudgetExceeded.Attach(closure)
+	t.Cleanup(func() { Events.BudgetExceeded.Detach(closure) })
+
+	return func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string{}, resources...)
+	}
+}
+
+func TestWorkerPoolLimits(t *testing.T) {
+	b := newTestBudget(Opts{MaxWorkers: 2, MaxQueueSize: 100})
+	assert.Equal(t, 1, b.WorkerCount(1))
+	assert.Equal(t, 2, b.WorkerCount(8))
+	assert.Equal(t, 100, b.QueueSize(1000))
+
+	unlimited := newTestBudget(Opts{})
+	assert.Equal(t, 8, unlimited.WorkerCount(8))
+	assert.Equal(t, 1000, unlimited.QueueSize(1000))
+}
+
+func TestGoroutineLimit(t *testing.T) {
+	b := newTestBudget(Opts{MaxGoroutines: 2})
+	exceeded := recordExceeded(t, b)
+
+	release := make(chan struct{})
+	var done sync.WaitGroup
+	done.Add(2)
+	for i := 0; i < 2; i++ {
+		require.True(t, b.Go(func() {
+			defer done.Done()
+			<-release
+		}))
+	}
+	assert.Equal(t, 2, b.Goroutines())
+
+	// the budget is exhausted, so the function is not run
+	assert.False(t, b.Go(func() { t.Error("function of an exceeded budget was run") }))
+	assert.Equal(t, 2, b.Goroutines())
+	assert.Equal(t, uint32(1), b.ExceededCount())
+	assert.Equal(t, []string{ResourceGoroutines}, exceeded())
+
+	close(release)
+	done.Wait()
+	require.Eventually(t, func() bool { return b.Goroutines() == 0 }, time.Second, time.Millisecond)
+
+	ran := make(chan struct{})
+	require.True(t, b.Go(func() { close(ran) }))
+	<-ran
+}
+
+func TestMemoryLimit(t *testing.T) {
+	b := newTestBudget(Opts{MaxMemoryBytes: 100})
+	exceeded := recordExceeded(t, b)
+
+	require.True(t, b.Alloc(60))
+	require.True(t, b.Alloc(40))
+
+	// a rejected allocation is not accounted
+	assert.False(t, b.Alloc(1))
+	assert.Equal(t, int64(100), b.Memory())
+	assert.Equal(t, []string{ResourceMemory}, exceeded())
+
+	b.Free(60)
+	assert.True(t, b.Alloc(50))
+	assert.Equal(t, int64(90), b.Memory())
+}
+
+func TestCPULimit(t *testing.T) {
+	b := newTestBudget(Opts{MaxCPUUsage: 0.5})
+	exceeded := recordExceeded(t, b)
+
+	// the tracked function is busy for the whole time between the checks
+	b.lastCheck = time.Now()
+	b.Track(func() { time.Sleep(20 * time.Millisecond) })
+	b.check()
+
+	assert.Greater(t, b.CPUUsage(), 0.5)
+	assert.Equal(t, []string{ResourceCPU}, exceeded())
+
+	// no tracked work since the last check
+	time.Sleep(10 * time.Millisecond)
+	b.check()
+	assert.Zero(t, b.CPUUsage())
+	assert.Len(t, exceeded(), 1)
+}
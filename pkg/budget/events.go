@@ -0,0 +1,18 @@
+package budget
+
+import (
+	"github.com/iotaledger/hive.go/events"
+)
+
+var Events = packageEvents{
+	BudgetExceeded: events.NewEvent(BudgetExceededCaller),
+}
+
+type packageEvents struct {
+	// BudgetExceeded is triggered when a plugin exceeds one of the resources of its budget.
+	BudgetExceeded *events.Event
+}
+
+func BudgetExceededCaller(handler interface{}, params ...interface{}) {
+	handler.(func(b *Budget, resource string, usage float64, limit float64))(params[0].(*Budget), params[1].(string), params[2].(float64), params[3].(float64))
+}
@@ -0,0 +1,32 @@
+package compressed
+
+import (
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/iotaledger/hive.go/batchhasher"
+	"github.com/iotaledger/hive.go/lru_cache"
+)
+
+const (
+	// The amount of computed transaction hashes kept in the cache.
+	TransactionHashCacheSize = 10000
+)
+
+var (
+	txHashCache = lru_cache.NewLRUCache(TransactionHashCacheSize)
+)
+
+// TransactionHash computes the hash of the given transaction trits with the batched CurlP81 hasher.
+// The computed hashes are cached by the transaction bytes, so a transaction which passes
+// several subsystems (gossip, API, coordinator) is only hashed once.
+func TransactionHash(txTrits trinary.Trits) trinary.Hash {
+	txBytes, err := trinary.TritsToBytes(txTrits)
+	if err != nil {
+		// trits which can't be converted are not cached
+		return trinary.MustTritsToTrytes(batchhasher.CURLP81.Hash(txTrits))
+	}
+
+	return txHashCache.ComputeIfAbsent(string(txBytes), func() interface{} {
+		return trinary.MustTritsToTrytes(batchhasher.CURLP81.Hash(txTrits))
+	}).(trinary.Hash)
+}
@@ -7,8 +7,6 @@ import (
 	"github.com/iotaledger/iota.go/math"
 	"github.com/iotaledger/iota.go/transaction"
 	"github.com/iotaledger/iota.go/trinary"
-
-	"github.com/iotaledger/hive.go/batchhasher"
 )
 
 const (
@@ -78,8 +76,7 @@ func TransactionFromCompressedBytes(transactionData []byte, txHash ...trinary.Ha
 	// calculate the transaction hash with the batched hasher if not given
 	skipHashCalc := len(txHash) > 0
 	if !skipHashCalc {
-		hashTrits := batchhasher.CURLP81.Hash(txDataTrits)
-		txHash = []trinary.Hash{trinary.MustTritsToTrytes(hashTrits)}
+		txHash = []trinary.Hash{TransactionHash(txDataTrits)}
 	}
 
 	tx, err := transaction.ParseTransaction(txDataTrits, true)
@@ -0,0 +1,16 @@
+package config
+
+const (
+	// the path to the directory the message archive files are written to
+	CfgArchiverPath = "archiver.path"
+	// the amount of milestones which are stored in a single archive file
+	CfgArchiverMilestonesPerFile = "archiver.milestonesPerFile"
+	// the archive files which are imported into the database on startup
+	CfgArchiverImportFiles = "archiver.importFiles"
+)
+
+func init() {
+	configFlagSet.String(CfgArchiverPath, "archive", "the path to the directory the message archive files are written to")
+	configFlagSet.Int(CfgArchiverMilestonesPerFile, 10000, "the amount of milestones which are stored in a single archive file")
+	configFlagSet.StringSlice(CfgArchiverImportFiles, []string{}, "the archive files which are imported into the database on startup")
+}
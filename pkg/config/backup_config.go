@@ -0,0 +1,46 @@
+package config
+
+const (
+	// the URL of the S3 compatible storage the backups are uploaded to
+	CfgBackupS3Endpoint = "backup.s3.endpoint"
+	// the region of the bucket
+	CfgBackupS3Region = "backup.s3.region"
+	// the bucket the backups are uploaded to
+	CfgBackupS3Bucket = "backup.s3.bucket"
+	// the access key ID of the storage
+	CfgBackupS3AccessKeyID = "backup.s3.accessKeyID"
+	// the secret access key of the storage
+	CfgBackupS3SecretAccessKey = "backup.s3.secretAccessKey"
+	// whether the bucket is addressed in the path instead of the host name
+	CfgBackupS3PathStyle = "backup.s3.pathStyle"
+	// the prefix of the keys of all uploaded backups
+	CfgBackupPrefix = "backup.prefix"
+	// whether the created snapshot files are uploaded
+	CfgBackupSnapshotsEnabled = "backup.snapshots.enabled"
+	// the amount of uploaded local and delta snapshot files which are kept (0 keeps all)
+	CfgBackupSnapshotsRetention = "backup.snapshots.retention"
+	// whether backups of the databases are uploaded
+	CfgBackupDatabaseEnabled = "backup.database.enabled"
+	// the interval in hours at which the databases are backed up
+	CfgBackupDatabaseIntervalHours = "backup.database.intervalHours"
+	// the directory the database backups are written to before they are uploaded
+	CfgBackupDatabasePath = "backup.database.path"
+	// the amount of uploaded database backups which are kept (0 keeps all)
+	CfgBackupDatabaseRetention = "backup.database.retention"
+)
+
+func init() {
+	configFlagSet.String(CfgBackupS3Endpoint, "https://s3.amazonaws.com", "the URL of the S3 compatible storage the backups are uploaded to")
+	configFlagSet.String(CfgBackupS3Region, "us-east-1", "the region of the bucket")
+	configFlagSet.String(CfgBackupS3Bucket, "", "the bucket the backups are uploaded to")
+	configFlagSet.String(CfgBackupS3AccessKeyID, "", "the access key ID of the storage")
+	configFlagSet.String(CfgBackupS3SecretAccessKey, "", "the secret access key of the storage")
+	configFlagSet.Bool(CfgBackupS3PathStyle, false, "whether the bucket is addressed in the path instead of the host name")
+	configFlagSet.String(CfgBackupPrefix, "hornet", "the prefix of the keys of all uploaded backups")
+	configFlagSet.Bool(CfgBackupSnapshotsEnabled, true, "whether the created snapshot files are uploaded")
+	configFlagSet.Int(CfgBackupSnapshotsRetention, 3, "the amount of uploaded local and delta snapshot files which are kept (0 keeps all)")
+	configFlagSet.Bool(CfgBackupDatabaseEnabled, false, "whether backups of the databases are uploaded")
+	configFlagSet.Int(CfgBackupDatabaseIntervalHours, 24, "the interval in hours at which the databases are backed up")
+	configFlagSet.String(CfgBackupDatabasePath, "backup", "the directory the database backups are written to before they are uploaded")
+	configFlagSet.Int(CfgBackupDatabaseRetention, 3, "the amount of uploaded database backups which are kept (0 keeps all)")
+}
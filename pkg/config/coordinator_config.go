@@ -1,8 +1,20 @@
 package config
 
+// MilestoneKeyRangeConfig defines a coordinator address and the milestone index range it is valid for.
+type MilestoneKeyRangeConfig struct {
+	Address    string `json:"address" mapstructure:"address"`
+	StartIndex uint32 `json:"startIndex" mapstructure:"startIndex"`
+	EndIndex   uint32 `json:"endIndex" mapstructure:"endIndex"`
+}
+
 const (
 	// the address of the coordinator
 	CfgCoordinatorAddress = "coordinator.address"
+	// CfgCoordinatorKeyRanges defines the coordinator addresses and the milestone index ranges they are valid for (list of MilestoneKeyRangeConfig).
+	// if empty, the coordinator address is valid for all milestones.
+	// if set, only the key ranges are used to verify the milestones and the coordinator address must be the address
+	// of the latest key range, since it is still used for the handshakes, the snapshots and by the coordinator plugin.
+	CfgCoordinatorKeyRanges = "coordinator.keyRanges"
 	// the security level used in coordinator signatures
 	CfgCoordinatorSecurityLevel = "coordinator.securityLevel"
 	// the depth of the Merkle tree which in turn determines the number of leaves (private keys) that the coordinator can use to sign a message.
@@ -32,10 +44,14 @@ const (
 	CfgCoordinatorTipselectRandomTipsPerCheckpoint = "coordinator.tipsel.randomTipsPerCheckpoint"
 	// the maximum duration to select the heaviest branch tips in milliseconds
 	CfgCoordinatorTipselectHeaviestBranchSelectionDeadlineMilliseconds = "coordinator.tipsel.heaviestBranchSelectionDeadlineMilliseconds"
+	// the maximum amount of tip evaluations to select the heaviest branch tips (0 = unlimited)
+	// selecting a heaviest branch tip evaluates the remaining tips about twice
+	CfgCoordinatorTipselectHeaviestBranchSelectionMaxTipEvaluations = "coordinator.tipsel.heaviestBranchSelectionMaxTipEvaluations"
 )
 
 func init() {
 	configFlagSet.String(CfgCoordinatorAddress, "UDYXTZBE9GZGPM9SSQV9LTZNDLJIZMPUVVXYXFYVBLIEUHLSEWFTKZZLXYRHHWVQV9MNNX9KZC9D9UZWZ", "the address of the coordinator")
+	NodeConfig.SetDefault(CfgCoordinatorKeyRanges, []MilestoneKeyRangeConfig{})
 	configFlagSet.Int(CfgCoordinatorSecurityLevel, 2, "the security level used in coordinator signatures")
 	configFlagSet.Int(CfgCoordinatorMerkleTreeDepth, 24, "the depth of the Merkle tree which in turn determines the number of leaves (private keys) that the coordinator can use to sign a message.")
 	configFlagSet.Int(CfgCoordinatorMWM, 14, "the minimum weight magnitude is the number of trailing 0s that must appear in the end of a transaction hash. "+
@@ -49,4 +65,5 @@ func init() {
 	configFlagSet.Int(CfgCoordinatorTipselectMaxHeaviestBranchTipsPerCheckpoint, 10, "maximum amount of checkpoint transactions with heaviest branch tips")
 	configFlagSet.Int(CfgCoordinatorTipselectRandomTipsPerCheckpoint, 3, "amount of checkpoint transactions with random tips")
 	configFlagSet.Int(CfgCoordinatorTipselectHeaviestBranchSelectionDeadlineMilliseconds, 100, "the maximum duration to select the heaviest branch tips in milliseconds")
+	configFlagSet.Int(CfgCoordinatorTipselectHeaviestBranchSelectionMaxTipEvaluations, 200000, "the maximum amount of tip evaluations to select the heaviest branch tips (0 = unlimited)")
 }
@@ -15,6 +15,8 @@ const (
 	CfgDashboardBasicAuthPasswordHash = "dashboard.basicauth.passwordhash" // config key must be lower cased (for hiding passwords in PrintConfig)
 	// the HTTP basic auth salt used for hashing the password
 	CfgDashboardBasicAuthPasswordSalt = "dashboard.basicauth.passwordsalt" // config key must be lower cased (for hiding passwords in PrintConfig)
+	// whether to accept the JSON web tokens of the HTTP API instead of the HTTP basic auth credentials
+	CfgDashboardJWTAuthEnabled = "dashboard.jwtAuth.enabled"
 )
 
 func init() {
@@ -24,5 +26,6 @@ func init() {
 	configFlagSet.String(CfgDashboardBasicAuthUsername, "", "the HTTP basic auth username")
 	configFlagSet.String(CfgDashboardBasicAuthPasswordHash, "", "the HTTP basic auth username")
 	configFlagSet.String(CfgDashboardBasicAuthPasswordSalt, "", "the HTTP basic auth password+salt as a sha256 hash")
+	configFlagSet.Bool(CfgDashboardJWTAuthEnabled, false, "whether to accept the JSON web tokens of the HTTP API instead of the HTTP basic auth credentials")
 	configFlagSet.String(CfgDashboardTheme, "default", "the theme for the dashboard to use (default or dark)")
 }
@@ -0,0 +1,10 @@
+package config
+
+const (
+	// the maximum number of entries in the event log, older entries are removed
+	CfgEventLogCapacity = "eventLog.capacity"
+)
+
+func init() {
+	configFlagSet.Int(CfgEventLogCapacity, 1000, "the maximum number of entries in the event log, older entries are removed")
+}
@@ -0,0 +1,10 @@
+package config
+
+const (
+	// the bind address of the gRPC API
+	CfgGRPCBindAddress = "grpc.bindAddress"
+)
+
+func init() {
+	configFlagSet.String(CfgGRPCBindAddress, "localhost:14266", "the bind address of the gRPC API")
+}
@@ -0,0 +1,16 @@
+package config
+
+const (
+	// the maximum amount of milestones the latest solid milestone may be behind the latest milestone for the node to be healthy
+	CfgHealthMaxMilestoneDelta = "health.maxMilestoneDelta"
+	// the minimum amount of connected peers for the node to be healthy
+	CfgHealthMinConnectedPeers = "health.minConnectedPeers"
+	// the maximum age of the latest milestone in seconds for the node to be healthy
+	CfgHealthMaxMilestoneAgeSeconds = "health.maxMilestoneAgeSeconds"
+)
+
+func init() {
+	configFlagSet.Int(CfgHealthMaxMilestoneDelta, 2, "the maximum amount of milestones the latest solid milestone may be behind the latest milestone for the node to be healthy")
+	configFlagSet.Int(CfgHealthMinConnectedPeers, 1, "the minimum amount of connected peers for the node to be healthy")
+	configFlagSet.Int(CfgHealthMaxMilestoneAgeSeconds, 300, "the maximum age of the latest milestone in seconds for the node to be healthy")
+}
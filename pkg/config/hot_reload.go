@@ -0,0 +1,101 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/syncutils"
+)
+
+var (
+	// the parameters of the node config which are applied without a restart of the node.
+	// they are safe to change at runtime, because they don't affect the stored state.
+	hotReloadableParameters = []string{
+		logger.ViperKeyLevel,
+		CfgLoggerComponentLevels,
+		CfgSpammerTPSRateLimit,
+		CfgNetGossipRateLimitPeerTransactions,
+		CfgNetGossipRateLimitPeerRequests,
+		CfgNetGossipRateLimitPeerBytes,
+		CfgNetGossipRateLimitGlobalTransactions,
+		CfgNetGossipRateLimitGlobalRequests,
+		CfgNetGossipRateLimitGlobalBytes,
+	}
+
+	// the values of the hot reloadable parameters at the last reload.
+	hotReloadValues map[string]interface{}
+	hotReloadLock   syncutils.Mutex
+
+	// Events are the events of the node config.
+	Events = nodeConfigEvents{
+		ParameterChanged: events.NewEvent(events.StringCaller),
+	}
+)
+
+type nodeConfigEvents struct {
+	// ParameterChanged is triggered with the key of a hot reloadable parameter whose value changed in the node config file.
+	// Handlers read the new value with the typed getters of NodeConfig.
+	ParameterChanged *events.Event
+}
+
+// HotReloadableParameters returns the parameters of the node config which are applied without a restart of the node.
+func HotReloadableParameters() []string {
+	return append([]string{}, hotReloadableParameters...)
+}
+
+// IsHotReloadable returns whether the given parameter of the node config is applied without a restart of the node.
+func IsHotReloadable(key string) bool {
+	for _, parameter := range hotReloadableParameters {
+		if parameter == key {
+			return true
+		}
+	}
+	return false
+}
+
+// WatchNodeConfig watches the node config file and triggers Events.ParameterChanged for every changed hot reloadable parameter.
+// Changes of other parameters are ignored until the node is restarted.
+func WatchNodeConfig() {
+	hotReloadLock.Lock()
+	hotReloadValues = hotReloadableValues()
+	hotReloadLock.Unlock()
+
+	NodeConfig.OnConfigChange(func(_ fsnotify.Event) {
+		ReloadNodeConfig()
+	})
+	NodeConfig.WatchConfig()
+}
+
+// ReloadNodeConfig compares the hot reloadable parameters with their values at the last reload,
+// triggers Events.ParameterChanged for the changed ones and returns their keys.
+func ReloadNodeConfig() []string {
+	hotReloadLock.Lock()
+	values := hotReloadableValues()
+
+	var changed []string
+	for _, key := range hotReloadableParameters {
+		if !reflect.DeepEqual(hotReloadValues[key], values[key]) {
+			changed = append(changed, key)
+		}
+	}
+	hotReloadValues = values
+	hotReloadLock.Unlock()
+
+	for _, key := range changed {
+		Events.ParameterChanged.Trigger(key)
+	}
+
+	return changed
+}
+
+// returns the current values of the hot reloadable parameters.
+func hotReloadableValues() map[string]interface{} {
+	values := make(map[string]interface{}, len(hotReloadableParameters))
+	for _, key := range hotReloadableParameters {
+		values[key] = NodeConfig.Get(key)
+	}
+	return values
+}
@@ -0,0 +1,16 @@
+package config
+
+const (
+	// the levels of single components (e.g. "Gossip": "debug"), the other components use logger.level
+	CfgLoggerComponentLevels = "logger.componentLevels"
+	// the size in megabytes at which the log files of "rotate:<path>" output paths are rotated
+	CfgLoggerRotationMaxSize = "logger.rotation.maxSize"
+	// the number of rotated log files which are kept
+	CfgLoggerRotationMaxBackups = "logger.rotation.maxBackups"
+)
+
+func init() {
+	configFlagSet.StringToString(CfgLoggerComponentLevels, map[string]string{}, "the levels of single components (e.g. \"Gossip\": \"debug\"), the other components use logger.level")
+	configFlagSet.Int(CfgLoggerRotationMaxSize, 100, "the size in megabytes at which the log files of \"rotate:<path>\" output paths are rotated")
+	configFlagSet.Int(CfgLoggerRotationMaxBackups, 5, "the number of rotated log files which are kept")
+}
@@ -3,8 +3,11 @@ package config
 const (
 	// path to the MQTT broker config file
 	CfgMQTTConfig = "mqtt.config"
+	// the maximum number of addresses a MQTT client may watch
+	CfgMQTTWatchAddresses = "mqtt.watchAddresses"
 )
 
 func init() {
 	configFlagSet.String(CfgMQTTConfig, "mqtt_config.json", "path to the MQTT broker config file")
+	configFlagSet.Int(CfgMQTTWatchAddresses, 1000, "the maximum number of addresses a MQTT client may watch")
 }
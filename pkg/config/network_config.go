@@ -5,15 +5,56 @@ type PeerConfig struct {
 	ID         string `json:"identity" mapstructure:"identity"`
 	Alias      string `json:"alias" mapstructure:"alias"`
 	PreferIPv6 bool   `json:"preferIPv6" mapstructure:"preferIPv6"`
+	// the base58 encoded ed25519 public key of the peer. if it is set, the connection is encrypted.
+	PublicKey string `json:"publicKey,omitempty" mapstructure:"publicKey"`
 }
 
 const (
+	// the name of the network the node participates in, peers and snapshot files of other networks are refused
+	CfgNetNetworkID = "network.networkID"
 	// Defines if IPv6 is preferred for peers added through the API
 	CfgNetPreferIPv6 = "network.preferIPv6"
 	// the bind address of the gossip TCP server
 	CfgNetGossipBindAddress = "network.gossip.bindAddress"
 	// the number of seconds to wait before trying to reconnect to a disconnected peer
 	CfgNetGossipReconnectAttemptIntervalSeconds = "network.gossip.reconnectAttemptIntervalSeconds"
+	// the maximum number of seconds to wait before trying to reconnect to a peer whose connection attempts failed repeatedly
+	CfgNetGossipMaxReconnectBackoffSeconds = "network.gossip.maxReconnectBackoffSeconds"
+	// the misbehavior score at which a peer gets banned (0 = disable)
+	CfgNetGossipMaxMisbehaviorScore = "network.gossip.maxMisbehaviorScore"
+	// the number of seconds a misbehaving peer is banned
+	CfgNetGossipBanDurationSeconds = "network.gossip.banDurationSeconds"
+	// the maximum number of transactions per second a single peer may send (0 = unlimited)
+	CfgNetGossipRateLimitPeerTransactions = "network.gossip.rateLimit.peerTransactionsPerSecond"
+	// the maximum number of transaction and milestone requests per second a single peer may send (0 = unlimited)
+	CfgNetGossipRateLimitPeerRequests = "network.gossip.rateLimit.peerRequestsPerSecond"
+	// the maximum number of bytes per second a single peer may send (0 = unlimited)
+	CfgNetGossipRateLimitPeerBytes = "network.gossip.rateLimit.peerBytesPerSecond"
+	// the maximum number of transactions per second all peers together may send (0 = unlimited)
+	CfgNetGossipRateLimitGlobalTransactions = "network.gossip.rateLimit.globalTransactionsPerSecond"
+	// the maximum number of transaction and milestone requests per second all peers together may send (0 = unlimited)
+	CfgNetGossipRateLimitGlobalRequests = "network.gossip.rateLimit.globalRequestsPerSecond"
+	// the maximum number of bytes per second all peers together may send (0 = unlimited)
+	CfgNetGossipRateLimitGlobalBytes = "network.gossip.rateLimit.globalBytesPerSecond"
+	// the number of neighbors which must report a newer latest milestone in their heartbeats before it is requested from them (0 = disable)
+	CfgNetGossipSyncQuorum = "network.gossip.syncQuorum"
+	// the number of times a transaction is requested before the request is dropped, if it is not needed to solidify a milestone (0 = unlimited)
+	CfgNetGossipMaxRequestAttempts = "network.gossip.maxRequestAttempts"
+	// the number of seconds a broadcasted transaction is not broadcasted again (0 = disable)
+	CfgNetGossipBroadcastDedupWindowSeconds = "network.gossip.broadcastDedupWindowSeconds"
+	// the minimum amount of trailing zero trits of the hashes of gossiped transactions (0 = coordinator.mwm)
+	CfgNetGossipSpamFilterMinPoWScore = "network.gossip.spamFilter.minPoWScore"
+	// the maximum size of the truncated bytes of gossiped transactions (0 = unlimited)
+	CfgNetGossipSpamFilterMaxTransactionBytes = "network.gossip.spamFilter.maxTransactionBytes"
+	// the tag prefixes of gossiped transactions which are dropped
+	CfgNetGossipSpamFilterBlockedTags = "network.gossip.spamFilter.blockedTags"
+	// the maximum number of new transactions accepted from a single peer per minute (0 = unlimited)
+	CfgNetGossipSpamFilterPeerTransactionsPerMinute = "network.gossip.spamFilter.peerTransactionsPerMinute"
+	// private key seed of the identity used to encrypt gossip connections; optional base58 encoded 256-bit string.
+	// if it is empty, the autopeering seed is used.
+	CfgNetGossipEncryptionSeed = "network.gossip.encryption.seed"
+	// whether to encrypt the connections to autopeered neighbors
+	CfgNetGossipEncryptionAutopeering = "network.gossip.encryption.autopeering"
 
 	// enable inbound connections from unknown peers
 	CfgPeeringAcceptAnyConnection = "acceptAnyConnection"
@@ -45,9 +86,28 @@ const (
 func init() {
 
 	// gossip
+	configFlagSet.String(CfgNetNetworkID, "mainnet", "the name of the network the node participates in, peers and snapshot files of other networks are refused")
 	configFlagSet.Bool(CfgNetPreferIPv6, false, "defines if IPv6 is preferred for peers added through the API")
 	configFlagSet.String(CfgNetGossipBindAddress, "0.0.0.0:15600", "the bind address of the gossip TCP server")
 	configFlagSet.Int(CfgNetGossipReconnectAttemptIntervalSeconds, 60, "the number of seconds to wait before trying to reconnect to a disconnected peer")
+	configFlagSet.Int(CfgNetGossipMaxReconnectBackoffSeconds, 900, "the maximum number of seconds to wait before trying to reconnect to a peer whose connection attempts failed repeatedly")
+	configFlagSet.Int(CfgNetGossipMaxMisbehaviorScore, 30, "the misbehavior score at which a peer gets banned (0 = disable)")
+	configFlagSet.Int(CfgNetGossipBanDurationSeconds, 1800, "the number of seconds a misbehaving peer is banned")
+	configFlagSet.Int(CfgNetGossipRateLimitPeerTransactions, 1000, "the maximum number of transactions per second a single peer may send (0 = unlimited)")
+	configFlagSet.Int(CfgNetGossipRateLimitPeerRequests, 1000, "the maximum number of transaction and milestone requests per second a single peer may send (0 = unlimited)")
+	configFlagSet.Int(CfgNetGossipRateLimitPeerBytes, 2097152, "the maximum number of bytes per second a single peer may send (0 = unlimited)")
+	configFlagSet.Int(CfgNetGossipRateLimitGlobalTransactions, 0, "the maximum number of transactions per second all peers together may send (0 = unlimited)")
+	configFlagSet.Int(CfgNetGossipRateLimitGlobalRequests, 0, "the maximum number of transaction and milestone requests per second all peers together may send (0 = unlimited)")
+	configFlagSet.Int(CfgNetGossipRateLimitGlobalBytes, 0, "the maximum number of bytes per second all peers together may send (0 = unlimited)")
+	configFlagSet.Int(CfgNetGossipSyncQuorum, 0, "the number of neighbors which must report a newer latest milestone in their heartbeats before it is requested from them (0 = disable)")
+	configFlagSet.Int(CfgNetGossipMaxRequestAttempts, 40, "the number of times a transaction is requested before the request is dropped, if it is not needed to solidify a milestone (0 = unlimited)")
+	configFlagSet.Int(CfgNetGossipBroadcastDedupWindowSeconds, 30, "the number of seconds a broadcasted transaction is not broadcasted again (0 = disable)")
+	configFlagSet.Int(CfgNetGossipSpamFilterMinPoWScore, 0, "the minimum amount of trailing zero trits of the hashes of gossiped transactions (0 = coordinator.mwm)")
+	configFlagSet.Int(CfgNetGossipSpamFilterMaxTransactionBytes, 0, "the maximum size of the truncated bytes of gossiped transactions (0 = unlimited)")
+	configFlagSet.StringSlice(CfgNetGossipSpamFilterBlockedTags, []string{}, "the tag prefixes of gossiped transactions which are dropped")
+	configFlagSet.Int(CfgNetGossipSpamFilterPeerTransactionsPerMinute, 0, "the maximum number of new transactions accepted from a single peer per minute (0 = unlimited)")
+	configFlagSet.String(CfgNetGossipEncryptionSeed, "", "private key seed of the identity used to encrypt gossip connections; optional base58 encoded 256-bit string")
+	configFlagSet.Bool(CfgNetGossipEncryptionAutopeering, false, "whether to encrypt the connections to autopeered neighbors")
 
 	// peering
 	peeringFlagSet.Bool(CfgPeeringAcceptAnyConnection, false, "enable inbound connections from unknown peers")
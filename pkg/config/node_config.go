@@ -5,6 +5,8 @@ const (
 	CfgNodeAlias = "node.alias"
 	// CfgNodeShowAliasInGetNodeInfo defines whether to show the alias in getNodeInfo
 	CfgNodeShowAliasInGetNodeInfo = "node.showAliasInGetNodeInfo"
+	// CfgNodePluginBudgets defines the resource budgets of the plugins (map of plugin name to budget)
+	CfgNodePluginBudgets = "node.pluginBudgets"
 )
 
 func init() {
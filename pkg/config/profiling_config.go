@@ -3,8 +3,23 @@ package config
 const (
 	// the bind address on which the profiler listens on
 	CfgProfilingBindAddress = "profiling.bindAddress"
+	// whether to use HTTP basic auth for the profiler
+	CfgProfilingBasicAuthEnabled = "profiling.basicAuth.enabled"
+	// the HTTP basic auth username
+	CfgProfilingBasicAuthUsername = "profiling.basicAuth.username"
+	// the HTTP basic auth password+salt as a sha256 hash
+	CfgProfilingBasicAuthPasswordHash = "profiling.basicauth.passwordhash" // config key must be lower cased (for hiding passwords in PrintConfig)
+	// the HTTP basic auth salt used for hashing the password
+	CfgProfilingBasicAuthPasswordSalt = "profiling.basicauth.passwordsalt" // config key must be lower cased (for hiding passwords in PrintConfig)
+	// whether to accept the JSON web tokens of the HTTP API instead of the HTTP basic auth credentials
+	CfgProfilingJWTAuthEnabled = "profiling.jwtAuth.enabled"
 )
 
 func init() {
 	configFlagSet.String(CfgProfilingBindAddress, "localhost:6060", "the bind address on which the profiler listens on")
+	configFlagSet.Bool(CfgProfilingBasicAuthEnabled, false, "whether to use HTTP basic auth for the profiler")
+	configFlagSet.String(CfgProfilingBasicAuthUsername, "", "the HTTP basic auth username")
+	configFlagSet.String(CfgProfilingBasicAuthPasswordHash, "", "the HTTP basic auth password+salt as a sha256 hash")
+	configFlagSet.String(CfgProfilingBasicAuthPasswordSalt, "", "the HTTP basic auth salt used for hashing the password")
+	configFlagSet.Bool(CfgProfilingJWTAuthEnabled, false, "whether to accept the JSON web tokens of the HTTP API instead of the HTTP basic auth credentials")
 }
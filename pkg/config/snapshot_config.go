@@ -13,6 +13,10 @@ const (
 	CfgLocalSnapshotsPath = "snapshots.local.path"
 	// URL to load the local snapshot file from
 	CfgLocalSnapshotsDownloadURLs = "snapshots.local.downloadURLs"
+	// interval, in milestone transactions, at which a new local snapshot file is created instead of a delta snapshot file (0 to disable delta snapshots)
+	CfgLocalSnapshotsFullInterval = "snapshots.local.fullInterval"
+	// path to the delta snapshot file
+	CfgLocalSnapshotsDeltaPath = "snapshots.local.deltaPath"
 	// path to the global snapshot file containing the ledger state
 	CfgGlobalSnapshotPath = "snapshots.global.path"
 	// paths to the spent addresses files
@@ -23,6 +27,11 @@ const (
 	CfgPruningEnabled = "snapshots.pruning.enabled"
 	// amount of milestone transactions to keep in the database
 	CfgPruningDelay = "snapshots.pruning.delay"
+	// the size of the live data of the database (e.g. "30GB") above which additional milestones are pruned, regardless of the pruning delay (empty to disable).
+	// the space freed by pruning is not released by the bolt files, they need to be compacted via the db-migration tool.
+	CfgPruningTargetDatabaseSize = "snapshots.pruning.targetDatabaseSize"
+	// the rules which keep the bundles with matching tags for a number of days after their confirmation, even if their milestone is pruned
+	CfgPruningRetentionRules = "snapshots.pruning.retentionRules"
 	// enable support for wereAddressesSpentFrom (needed for Trinity, but local snapshots are much bigger)
 	CfgSpentAddressesEnabled = "spentAddresses.enabled"
 )
@@ -34,6 +43,8 @@ func init() {
 	configFlagSet.Int(CfgLocalSnapshotsIntervalUnsynced, 1000, "interval, in milestone transactions, at which snapshot files are created if the ledger is not fully synchronized")
 	configFlagSet.String(CfgLocalSnapshotsPath, "snapshots/mainnet/export.bin", "path to the local snapshot file")
 	configFlagSet.StringSlice(CfgLocalSnapshotsDownloadURLs, []string{}, "URLs to load the local snapshot file from. Provide multiple URLs as fall back sources")
+	configFlagSet.Int(CfgLocalSnapshotsFullInterval, 0, "interval, in milestone transactions, at which a new local snapshot file is created instead of a delta snapshot file (0 to disable delta snapshots)")
+	configFlagSet.String(CfgLocalSnapshotsDeltaPath, "snapshots/mainnet/delta_export.bin", "path to the delta snapshot file")
 	configFlagSet.String(CfgGlobalSnapshotPath, "snapshotMainnet.txt", "path to the global snapshot file containing the ledger state")
 	configFlagSet.StringSlice(CfgGlobalSnapshotSpentAddressesPaths, []string{
 		"previousEpochsSpentAddresses1.txt",
@@ -43,5 +54,6 @@ func init() {
 	configFlagSet.Int(CfgGlobalSnapshotIndex, 1050000, "milestone index of the global snapshot")
 	configFlagSet.Bool(CfgPruningEnabled, true, "whether to delete old transaction data from the database")
 	configFlagSet.Int(CfgPruningDelay, 60480, "amount of milestone transactions to keep in the database")
+	configFlagSet.String(CfgPruningTargetDatabaseSize, "", "the size of the live data of the database (e.g. \"30GB\") above which additional milestones are pruned, regardless of the pruning delay (empty to disable)")
 	configFlagSet.Bool(CfgSpentAddressesEnabled, true, "enable support for wereAddressesSpentFrom (needed for Trinity, but local snapshots are much bigger)")
 }
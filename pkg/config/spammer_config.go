@@ -19,6 +19,10 @@ const (
 	CfgSpammerValueSpam = "spammer.valueSpam"
 	// the amount of parallel running spammers
 	CfgSpammerWorkers = "spammer.workers"
+	// the amount of goroutines all spammers together may use for the PoW (0 = one per spammer)
+	CfgSpammerPoWWorkers = "spammer.powWorkers"
+	// whether to lower the rate while the node is not synced or the cpu usage is over the limit
+	CfgSpammerAdaptiveRate = "spammer.adaptiveRate"
 	// CfgSpammerAutostart automatically starts the spammer on node startup
 	CfgSpammerAutostart = "spammer.autostart"
 )
@@ -33,5 +37,7 @@ func init() {
 	configFlagSet.Int(CfgSpammerBundleSize, 1, "the size of the spam bundles")
 	configFlagSet.Bool(CfgSpammerValueSpam, false, "should be spammed with value bundles")
 	configFlagSet.Int(CfgSpammerWorkers, 1, "the amount of parallel running spammers")
+	configFlagSet.Int(CfgSpammerPoWWorkers, 0, "the amount of goroutines all spammers together may use for the PoW (0 = one per spammer)")
+	configFlagSet.Bool(CfgSpammerAdaptiveRate, true, "whether to lower the rate while the node is not synced or the cpu usage is over the limit")
 	configFlagSet.Bool(CfgSpammerAutostart, false, "automatically start the spammer on node startup")
 }
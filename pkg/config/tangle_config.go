@@ -3,11 +3,38 @@ package config
 const (
 	// the path to the database folder
 	CfgDatabasePath = "db.path"
+	// the engine of the database (bolt or badger)
+	CfgDatabaseEngine = "db.engine"
 	// ignore the check for corrupted databases (should only be used for debug reasons)
 	CfgDatabaseDebug = "db.debug"
+	// whether to record every confirmed transaction touching an address ("explorer mode")
+	CfgDatabaseAddressHistoryEnabled = "db.addressHistory.enabled"
+	// whether to keep the address history of transactions which were pruned from the database
+	CfgDatabaseAddressHistoryKeepPruned = "db.addressHistory.keepPruned"
+	// whether to maintain an index of the balances which answers the richest addresses and the supply distribution
+	CfgDatabaseLedgerAnalyticsEnabled = "db.ledgerAnalytics.enabled"
+	// whether to skip corrupted database entries while loading them instead of crashing the node
+	CfgDatabaseQuarantineEnabled = "db.quarantine.enabled"
+	// whether to delete the skipped corrupted database entries
+	CfgDatabaseQuarantineDeleteEntries = "db.quarantine.deleteEntries"
+	// whether to measure the count and the duration of the operations on the stores of the databases
+	CfgDatabaseMetricsEnabled = "db.metrics.enabled"
+	// when the ledger changes are synced to disk (periodic or confirmation)
+	CfgDatabaseDurabilityMode = "db.durability.mode"
+	// the interval in seconds at which the databases are synced to disk in the periodic mode (0 to only sync at shutdown)
+	CfgDatabaseDurabilitySyncIntervalSeconds = "db.durability.syncIntervalSeconds"
 )
 
 func init() {
 	configFlagSet.String(CfgDatabasePath, "mainnetdb", "the path to the database folder")
+	configFlagSet.String(CfgDatabaseEngine, "bolt", "the engine of the database (bolt or badger)")
 	configFlagSet.Bool(CfgDatabaseDebug, false, "ignore the check for corrupted databases (should only be used for debug reasons)")
+	configFlagSet.Bool(CfgDatabaseAddressHistoryEnabled, false, "whether to record every confirmed transaction touching an address (\"explorer mode\", only milestones confirmed afterwards are recorded)")
+	configFlagSet.Bool(CfgDatabaseAddressHistoryKeepPruned, false, "whether to keep the address history of transactions which were pruned from the database")
+	configFlagSet.Bool(CfgDatabaseLedgerAnalyticsEnabled, false, "whether to maintain an index of the balances which answers the richest addresses and the supply distribution (built from the ledger at startup)")
+	configFlagSet.Bool(CfgDatabaseQuarantineEnabled, false, "whether to skip corrupted database entries while loading them instead of crashing the node")
+	configFlagSet.Bool(CfgDatabaseQuarantineDeleteEntries, false, "whether to delete the skipped corrupted database entries")
+	configFlagSet.String(CfgDatabaseDurabilityMode, "periodic", "when the ledger changes are synced to disk (\"periodic\" saves IOPS but may lose the latest confirmations on a crash, \"confirmation\" syncs after every milestone)")
+	configFlagSet.Int(CfgDatabaseDurabilitySyncIntervalSeconds, 60, "the interval in seconds at which the databases are synced to disk in the periodic mode (0 to only sync at shutdown)")
+	configFlagSet.Bool(CfgDatabaseMetricsEnabled, false, "whether to measure the count and the duration of the operations on the stores of the databases")
 }
@@ -10,6 +10,8 @@ const (
 	// CfgTipSelBelowMaxDepth is the maximum allowed delta
 	// value between OTRSI of a given transaction in relation to the current LSMI before it gets lazy.
 	CfgTipSelBelowMaxDepth = "tipsel.belowMaxDepth"
+	// CfgTipSelStrategy is the strategy used to pick the tips out of the tip pools ("uniform", "ageWeighted" or "belowMaxDepth").
+	CfgTipSelStrategy = "tipsel.strategy"
 	// the config group used for the non-lazy tip-pool
 	CfgTipSelNonLazy = "tipsel.nonLazy."
 	// the config group used for the semi-lazy tip-pool
@@ -27,6 +29,11 @@ const (
 	// CfgTipSelSpammerTipsThreshold is the maximum amount of tips in a tip-pool before the spammer tries to reduce these (0 = disable (semi-lazy), 0 = always (non-lazy))
 	// this is used to support the network if someone attacks the tangle by spamming a lot of tips
 	CfgTipSelSpammerTipsThreshold = "spammerTipsThreshold"
+	// CfgTipSelRootSnapshotIndexesBatchSize is the amount of tips for which the root snapshot indexes
+	// are recalculated in one batch after a milestone was confirmed.
+	CfgTipSelRootSnapshotIndexesBatchSize = "tipsel.rootSnapshotIndexes.batchSize"
+	// CfgTipSelRootSnapshotIndexesBatchDelayMs is the delay between two batches of the root snapshot index recalculation.
+	CfgTipSelRootSnapshotIndexesBatchDelayMs = "tipsel.rootSnapshotIndexes.batchDelayMs"
 )
 
 func init() {
@@ -36,6 +43,7 @@ func init() {
 		"value between OTRSI of a given transaction in relation to the current LSMI before it gets semi-lazy")
 	configFlagSet.Int(CfgTipSelBelowMaxDepth, 15, "the maximum allowed delta "+
 		"value for the OTRSI of a given transaction in relation to the current LSMI before it gets lazy")
+	configFlagSet.String(CfgTipSelStrategy, "uniform", "the strategy used to pick the tips out of the tip pools (uniform, ageWeighted or belowMaxDepth)")
 	configFlagSet.Int(CfgTipSelNonLazy+CfgTipSelRetentionRulesTipsLimit, 100, "the maximum number of current tips for which the retention rules are checked (non-lazy)")
 	configFlagSet.Int(CfgTipSelNonLazy+CfgTipSelMaxReferencedTipAgeSeconds, 3, "the maximum time a tip remains in the tip pool "+
 		"after it was referenced by the first transaction (non-lazy)")
@@ -50,4 +58,7 @@ func init() {
 		"before the tip is removed from the tip pool (semi-lazy)")
 	configFlagSet.Int(CfgTipSelSemiLazy+CfgTipSelSpammerTipsThreshold, 30, "the maximum amount of tips in a tip-pool (semi-lazy) before "+
 		"the spammer tries to reduce these (0 = disable)")
+	configFlagSet.Int(CfgTipSelRootSnapshotIndexesBatchSize, 100, "the amount of tips for which the root snapshot indexes "+
+		"are recalculated in one batch after a milestone was confirmed (0 = all at once)")
+	configFlagSet.Int(CfgTipSelRootSnapshotIndexesBatchDelayMs, 10, "the delay in milliseconds between two batches of the root snapshot index recalculation")
 }
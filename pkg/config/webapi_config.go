@@ -19,6 +19,12 @@ const (
 	CfgWebAPIBasicAuthPasswordHash = "httpapi.basicauth.passwordhash" // must be lower cased
 	// the HTTP basic auth salt used for hashing the password
 	CfgWebAPIBasicAuthPasswordSalt = "httpapi.basicauth.passwordsalt" // must be lower cased
+	// whether to accept JSON web tokens for routes and API calls which are not permitted for non whitelisted addresses
+	CfgWebAPIJWTAuthEnabled = "httpAPI.jwtAuth.enabled"
+	// the secret the JSON web tokens are signed with (derived from the node identity if empty)
+	CfgWebAPIJWTAuthSecret = "httpapi.jwtauth.secret" // must be lower cased
+	// the time in seconds after which issued JSON web tokens expire (0 = never)
+	CfgWebAPIJWTAuthSessionTimeoutSeconds = "httpAPI.jwtAuth.sessionTimeoutSeconds"
 	// the maximum number of characters that the body of an API call may contain
 	CfgWebAPILimitsMaxBodyLengthBytes = "httpAPI.limits.bodyLengthBytes"
 	// the maximum number of transactions that may be returned by the findTransactions endpoint
@@ -27,6 +33,37 @@ const (
 	CfgWebAPILimitsMaxGetTrytes = "httpAPI.limits.getTrytes"
 	// the maximum number of parameters in an API call
 	CfgWebAPILimitsMaxRequestsList = "httpAPI.limits.requestsList"
+	// the maximum number of transaction submissions of the APIs which may wait to be processed
+	CfgWebAPILimitsSubmissionQueueSize = "httpAPI.limits.submissionQueueSize"
+	// the maximum number of addresses a websocket client may watch
+	CfgWebAPILimitsWatchAddresses = "httpAPI.limits.watchAddresses"
+	// the maximum number of REST API requests per second of a non whitelisted address (0 = no limit)
+	CfgWebAPILimitsRESTRequestsPerSecond = "httpAPI.limits.restAPI.requestsPerSecond"
+	// the maximum number of REST API requests a non whitelisted address may send at once
+	CfgWebAPILimitsRESTBurst = "httpAPI.limits.restAPI.burst"
+	// whether the node does the PoW for transactions submitted via the REST API if requested
+	CfgWebAPIPoWEnabled = "httpAPI.pow.enabled"
+	// the minimum weight magnitude the node uses for the PoW of submitted transactions (0 = coordinator.mwm)
+	CfgWebAPIPoWTargetMWM = "httpAPI.pow.targetMWM"
+	// the amount of goroutines used for the PoW of submitted transactions (0 = all CPU cores)
+	CfgWebAPIPoWWorkerCount = "httpAPI.pow.workerCount"
+	// the maximum number of PoW jobs the node does at once for the REST API, further requests are rejected
+	CfgWebAPIPoWMaxConcurrentJobs = "httpAPI.pow.maxConcurrentJobs"
+	// whether the node tracks the bundles submitted via the APIs until they are confirmed.
+	// the reattachments are done with the PoW of the node, so it should only be enabled if the API is restricted to trusted clients.
+	CfgWebAPIOutboxEnabled = "httpAPI.outbox.enabled"
+	// the maximum number of bundles tracked by the outbox
+	CfgWebAPIOutboxMaxItems = "httpAPI.outbox.maxItems"
+	// the interval in seconds in which unconfirmed bundles of the outbox are sent to the neighbors again
+	CfgWebAPIOutboxRebroadcastIntervalSeconds = "httpAPI.outbox.rebroadcastIntervalSeconds"
+	// the time in seconds after which unconfirmed bundles of the outbox are reattached, even if they are not lazy (0 = only lazy bundles)
+	CfgWebAPIOutboxReattachAfterSeconds = "httpAPI.outbox.reattachAfterSeconds"
+	// the maximum number of reattachments of a bundle of the outbox
+	CfgWebAPIOutboxMaxReattachments = "httpAPI.outbox.maxReattachments"
+	// the time in seconds after which unconfirmed bundles are removed from the outbox
+	CfgWebAPIOutboxMaxAgeSeconds = "httpAPI.outbox.maxAgeSeconds"
+	// the duration in milliseconds after which a request is logged as slow, including its parameters (0 = disabled)
+	CfgWebAPISlowRequestsThresholdMilliseconds = "httpAPI.slowRequests.thresholdMilliseconds"
 )
 
 func init() {
@@ -47,6 +84,14 @@ func init() {
 	configFlagSet.StringSlice(CfgWebAPIPermittedRoutes,
 		[]string{
 			"healthz",
+			"health",
+			"ready",
+			"api/v1/info",
+			"api/v1/transactions",
+			"api/v1/milestones",
+			"api/v1/addresses",
+			"api/v1/tags",
+			"api/v1/ws",
 		}, "the allowed HTTP REST routes which can be called from non whitelisted addresses")
 	configFlagSet.StringSlice(CfgWebAPIWhitelistedAddresses, []string{}, "the whitelist of addresses which are allowed to access the HTTP API")
 	configFlagSet.Bool(CfgWebAPIExcludeHealthCheckFromAuth, false, "whether to allow the health check route anyways")
@@ -54,8 +99,26 @@ func init() {
 	configFlagSet.String(CfgWebAPIBasicAuthUsername, "", "the username of the HTTP basic auth")
 	configFlagSet.String(CfgWebAPIBasicAuthPasswordHash, "", "the HTTP basic auth password+salt as a sha256 hash")
 	configFlagSet.String(CfgWebAPIBasicAuthPasswordSalt, "", "the HTTP basic auth salt used for hashing the password")
+	configFlagSet.Bool(CfgWebAPIJWTAuthEnabled, false, "whether to accept JSON web tokens for routes and API calls which are not permitted for non whitelisted addresses")
+	configFlagSet.String(CfgWebAPIJWTAuthSecret, "", "the secret the JSON web tokens are signed with (derived from the node identity if empty)")
+	configFlagSet.Int(CfgWebAPIJWTAuthSessionTimeoutSeconds, 86400, "the time in seconds after which issued JSON web tokens expire (0 = never)")
 	configFlagSet.Int(CfgWebAPILimitsMaxBodyLengthBytes, 1000000, "the maximum number of characters that the body of an API call may contain")
 	configFlagSet.Int(CfgWebAPILimitsMaxFindTransactions, 1000, "the maximum number of transactions that may be returned by the findTransactions endpoint")
 	configFlagSet.Int(CfgWebAPILimitsMaxGetTrytes, 1000, "the maximum number of trytes that may be returned by the getTrytes endpoint")
 	configFlagSet.Int(CfgWebAPILimitsMaxRequestsList, 1000, "the maximum number of parameters in an API call")
+	configFlagSet.Int(CfgWebAPILimitsSubmissionQueueSize, 100, "the maximum number of transaction submissions of the APIs which may wait to be processed")
+	configFlagSet.Int(CfgWebAPILimitsWatchAddresses, 1000, "the maximum number of addresses a websocket client may watch")
+	configFlagSet.Int(CfgWebAPILimitsRESTRequestsPerSecond, 20, "the maximum number of REST API requests per second of a non whitelisted address (0 = no limit)")
+	configFlagSet.Int(CfgWebAPILimitsRESTBurst, 40, "the maximum number of REST API requests a non whitelisted address may send at once")
+	configFlagSet.Bool(CfgWebAPIPoWEnabled, false, "whether the node does the PoW for transactions submitted via the REST API if requested")
+	configFlagSet.Int(CfgWebAPIPoWTargetMWM, 0, "the minimum weight magnitude the node uses for the PoW of submitted transactions (0 = coordinator.mwm)")
+	configFlagSet.Int(CfgWebAPIPoWWorkerCount, 0, "the amount of goroutines used for the PoW of submitted transactions (0 = all CPU cores)")
+	configFlagSet.Int(CfgWebAPIPoWMaxConcurrentJobs, 1, "the maximum number of PoW jobs the node does at once for the REST API, further requests are rejected")
+	configFlagSet.Bool(CfgWebAPIOutboxEnabled, false, "whether the node tracks the bundles submitted via the APIs until they are confirmed")
+	configFlagSet.Int(CfgWebAPIOutboxMaxItems, 1000, "the maximum number of bundles tracked by the outbox")
+	configFlagSet.Int(CfgWebAPIOutboxRebroadcastIntervalSeconds, 60, "the interval in seconds in which unconfirmed bundles of the outbox are sent to the neighbors again (0 = disabled)")
+	configFlagSet.Int(CfgWebAPIOutboxReattachAfterSeconds, 0, "the time in seconds after which unconfirmed bundles of the outbox are reattached, even if they are not lazy (0 = only lazy bundles)")
+	configFlagSet.Int(CfgWebAPIOutboxMaxReattachments, 3, "the maximum number of reattachments of a bundle of the outbox (requires httpAPI.pow.enabled)")
+	configFlagSet.Int(CfgWebAPIOutboxMaxAgeSeconds, 3600, "the time in seconds after which unconfirmed bundles are removed from the outbox (0 = never)")
+	configFlagSet.Int(CfgWebAPISlowRequestsThresholdMilliseconds, 2000, "the duration in milliseconds after which a request is logged as slow, including its parameters (0 = disabled)")
 }
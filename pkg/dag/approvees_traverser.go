@@ -1,7 +1,6 @@
 package dag
 
 import (
-	"bytes"
 	"container/list"
 	"fmt"
 	"sync"
@@ -105,6 +104,15 @@ func (t *ApproveesTraverser) Traverse(startTxHash hornet.Hash, traverseSolidEntr
 // It is a DFS with trunk / branch.
 // Caution: condition func is not in DFS order
 func (t *ApproveesTraverser) TraverseTrunkAndBranch(trunkTxHash hornet.Hash, branchTxHash hornet.Hash, traverseSolidEntryPoints bool, traverseTailsOnly bool) error {
+	return t.TraverseParents(hornet.Hashes{trunkTxHash, branchTxHash}, traverseSolidEntryPoints, traverseTailsOnly)
+}
+
+// TraverseParents starts to traverse the approvees (past cone) of the given parents one after another until
+// the traversal stops due to no more transactions passing the given condition.
+// The processed transactions are shared between the parents, so every transaction is only consumed once.
+// It is a DFS with the parents in the given order.
+// Caution: condition func is not in DFS order
+func (t *ApproveesTraverser) TraverseParents(parents hornet.Hashes, traverseSolidEntryPoints bool, traverseTailsOnly bool) error {
 
 	// make sure only one traversal is running
 	t.traverserLock.Lock()
@@ -117,22 +125,14 @@ func (t *ApproveesTraverser) TraverseTrunkAndBranch(trunkTxHash hornet.Hash, bra
 
 	defer t.cleanup(true)
 
-	t.stack.PushFront(trunkTxHash)
-	for t.stack.Len() > 0 {
-		if err := t.processStackApprovees(); err != nil {
-			return err
-		}
-	}
-
-	// since we first feed the stack the trunk,
-	// we need to make sure that we also examine the branch path.
-	// however, we only need to do it if the branch wasn't processed yet.
-	// the referenced branch transaction could for example already be processed
-	// if it is directly/indirectly approved by the trunk.
-	t.stack.PushFront(branchTxHash)
-	for t.stack.Len() > 0 {
-		if err := t.processStackApprovees(); err != nil {
-			return err
+	for _, parent := range parents {
+		// the referenced parent could already be processed
+		// if it is directly/indirectly approved by one of the former parents.
+		t.stack.PushFront(parent)
+		for t.stack.Len() > 0 {
+			if err := t.processStackApprovees(); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -219,11 +219,10 @@ func (t *ApproveesTraverser) processStackApprovees() error {
 		return nil
 	}
 
-	var trunkHash, branchHash hornet.Hash
+	var approveeHashes hornet.Hashes
 
 	if !t.traverseTailsOnly {
-		trunkHash = cachedTxMeta.GetMetadata().GetTrunkHash()
-		branchHash = cachedTxMeta.GetMetadata().GetBranchHash()
+		approveeHashes = cachedTxMeta.GetMetadata().GetParents()
 	} else {
 		// load up bundle to retrieve trunk and branch of the head tx
 		cachedBundle, exists := t.cachedBundles[string(currentTxHash)]
@@ -235,13 +234,7 @@ func (t *ApproveesTraverser) processStackApprovees() error {
 			t.cachedBundles[string(currentTxHash)] = cachedBundle
 		}
 
-		trunkHash = cachedBundle.GetBundle().GetTrunkHash(true)
-		branchHash = cachedBundle.GetBundle().GetBranchHash(true)
-	}
-
-	approveeHashes := hornet.Hashes{trunkHash}
-	if !bytes.Equal(trunkHash, branchHash) {
-		approveeHashes = append(approveeHashes, branchHash)
+		approveeHashes = hornet.Hashes{cachedBundle.GetBundle().GetTrunkHash(true), cachedBundle.GetBundle().GetBranchHash(true)}
 	}
 
 	for _, approveeHash := range approveeHashes {
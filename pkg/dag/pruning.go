@@ -0,0 +1,105 @@
+package dag
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+)
+
+// MilestoneConeFilterFunc is called with the transactions referenced by a milestone before they are deleted.
+// Transactions which are removed from the map are kept, e.g. because of retention rules.
+type MilestoneConeFilterFunc func(msIndex milestone.Index, txHashes map[string]struct{})
+
+// MilestonePrunedFunc is called after the transactions referenced by a milestone were deleted.
+// The error is set if the cone of the milestone could not be walked, nothing was deleted in that case.
+type MilestonePrunedFunc func(msIndex milestone.Index, txCountDeleted int, txCountChecked int, err error)
+
+// GetMilestoneCone returns the hashes of all transactions referenced by the milestone with the given index,
+// including the transactions which were already confirmed by older milestones.
+func GetMilestoneCone(msIndex milestone.Index, abortSignal <-chan struct{}) (map[string]struct{}, error) {
+
+	cachedMs := tangle.GetCachedMilestoneOrNil(msIndex) // milestone +1
+	if cachedMs == nil {
+		return nil, errors.Wrapf(tangle.ErrMilestoneNotFound, "index: %d", msIndex)
+	}
+	defer cachedMs.Release(true) // milestone -1
+
+	txHashes := make(map[string]struct{})
+
+	err := TraverseApprovees(cachedMs.GetMilestone().ID.Hash(),
+		// traversal stops if no more transactions pass the given condition
+		// Caution: condition func is not in DFS order
+		func(cachedTxMeta *tangle.CachedMetadata) (bool, error) { // meta +1
+			defer cachedTxMeta.Release(true) // meta -1
+			// everything that was referenced by that milestone is part of the cone (even transactions of older milestones)
+			return true, nil
+		},
+		// consumer
+		func(cachedTxMeta *tangle.CachedMetadata) error { // meta +1
+			defer cachedTxMeta.Release(true) // meta -1
+			txHashes[string(cachedTxMeta.GetMetadata().GetTxHash())] = struct{}{}
+			return nil
+		},
+		// called on missing approvees
+		func(approveeHash hornet.Hash) error { return nil },
+		// called on solid entry points
+		// Ignore solid entry points (snapshot milestone included)
+		nil,
+		// the milestone itself could be a solid entry point => traverse it anyways
+		true,
+		false,
+		abortSignal)
+	if err != nil {
+		return nil, err
+	}
+
+	return txHashes, nil
+}
+
+// DeleteTransactionMetadataBelowIndex deletes the transactions referenced by the milestones from startIndex up to,
+// but excluding, msIndex together with their metadata, approver, bundle, tag and address entries.
+// The transactions are found by walking the cones of the milestones, so the metadata storage is not iterated.
+// The milestones are pruned in ascending order, the deletions of a cone are done in batches of the given size
+// and the progress func is called after every batch. onPruned is called after every milestone, so the caller
+// can advance its pruning index. Milestones whose cone could not be walked are reported to onPruned and skipped.
+// It returns tangle.ErrOperationAborted if the abort signal was received, the milestone which was pruned at that moment
+// was not reported to onPruned.
+func DeleteTransactionMetadataBelowIndex(startIndex milestone.Index, msIndex milestone.Index, deleteAddressHistory bool, batchSize int, filter MilestoneConeFilterFunc, progress tangle.MetadataPruningProgressFunc, onPruned MilestonePrunedFunc, abortSignal <-chan struct{}) error {
+
+	for index := startIndex; index < msIndex; index++ {
+		select {
+		case <-abortSignal:
+			return tangle.ErrOperationAborted
+		default:
+		}
+
+		txHashes, err := GetMilestoneCone(index, abortSignal)
+		if err != nil {
+			if errors.Is(err, tangle.ErrOperationAborted) {
+				return err
+			}
+			if onPruned != nil {
+				onPruned(index, 0, 0, err)
+			}
+			continue
+		}
+
+		txCountChecked := len(txHashes)
+		if filter != nil {
+			filter(index, txHashes)
+		}
+
+		txCountDeleted, err := tangle.DeleteTransactions(txHashes, deleteAddressHistory, batchSize, progress, abortSignal)
+		if err != nil {
+			return err
+		}
+
+		if onPruned != nil {
+			onPruned(index, txCountDeleted, txCountChecked, nil)
+		}
+	}
+
+	return nil
+}
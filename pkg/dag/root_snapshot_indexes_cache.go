@@ -0,0 +1,84 @@
+package dag
+
+import (
+	"sync"
+
+	"github.com/iotaledger/hive.go/lru_cache"
+
+	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+const (
+	// The amount of computed transaction root snapshot indexes kept in the cache.
+	RootSnapshotIndexesCacheSize = 50000
+)
+
+var (
+	// the cached indexes are only valid for the solid milestone index they were calculated for.
+	rootSnapshotIndexesCacheLock sync.Mutex
+	rootSnapshotIndexesCacheLSMI milestone.Index
+	rootSnapshotIndexesCache     = lru_cache.NewLRUCache(RootSnapshotIndexesCacheSize)
+)
+
+type rootSnapshotIndexes struct {
+	yrtsi milestone.Index
+	ortsi milestone.Index
+}
+
+// getCachedRootSnapshotIndexes returns the root snapshot indexes of the given transaction calculated for the given LSMI.
+func getCachedRootSnapshotIndexes(txHash hornet.Hash, lsmi milestone.Index) (yrtsi milestone.Index, ortsi milestone.Index, exists bool) {
+	rootSnapshotIndexesCacheLock.Lock()
+	defer rootSnapshotIndexesCacheLock.Unlock()
+
+	if lsmi != rootSnapshotIndexesCacheLSMI {
+		metrics.SharedServerMetrics.RootSnapshotIndexesCacheMisses.Inc()
+		return 0, 0, false
+	}
+
+	cached := rootSnapshotIndexesCache.Get(string(txHash))
+	if cached == nil {
+		metrics.SharedServerMetrics.RootSnapshotIndexesCacheMisses.Inc()
+		return 0, 0, false
+	}
+
+	metrics.SharedServerMetrics.RootSnapshotIndexesCacheHits.Inc()
+	indexes := cached.(*rootSnapshotIndexes)
+	return indexes.yrtsi, indexes.ortsi, true
+}
+
+// cacheRootSnapshotIndexes stores the root snapshot indexes of the given transaction calculated for the given LSMI.
+// The whole cache is invalidated if the indexes were calculated for a newer LSMI, since the indexes of
+// all unconfirmed transactions have to be calculated again after a milestone got solid.
+func cacheRootSnapshotIndexes(txHash hornet.Hash, lsmi milestone.Index, yrtsi milestone.Index, ortsi milestone.Index) {
+	rootSnapshotIndexesCacheLock.Lock()
+	defer rootSnapshotIndexesCacheLock.Unlock()
+
+	if lsmi < rootSnapshotIndexesCacheLSMI {
+		// calculated for an older milestone while the indexes of the newer one were already cached
+		return
+	}
+
+	if lsmi > rootSnapshotIndexesCacheLSMI {
+		rootSnapshotIndexesCache.DeleteAll()
+		rootSnapshotIndexesCacheLSMI = lsmi
+	}
+
+	rootSnapshotIndexesCache.Set(string(txHash), &rootSnapshotIndexes{yrtsi: yrtsi, ortsi: ortsi})
+}
+
+// ResetRootSnapshotIndexesCache removes all cached root snapshot indexes,
+// e.g. if the solid milestone index was reset.
+func ResetRootSnapshotIndexesCache() {
+	rootSnapshotIndexesCacheLock.Lock()
+	defer rootSnapshotIndexesCacheLock.Unlock()
+
+	rootSnapshotIndexesCache.DeleteAll()
+	rootSnapshotIndexesCacheLSMI = 0
+}
+
+// GetRootSnapshotIndexesCacheSize returns the amount of transactions whose root snapshot indexes are cached.
+func GetRootSnapshotIndexesCacheSize() int {
+	return rootSnapshotIndexesCache.GetSize()
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	_ "golang.org/x/crypto/blake2b"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite"
+)
+
+func TestDeleteTransactionMetadataBelowIndex(t *testing.T) {
+	te := testsuite.SetupTestEnvironment(t, make(map[string]uint64), 3, false)
+	defer te.CleanupTestEnvironment(true)
+
+	te.BuildTopology(testsuite.Topology{
+		{Name: "A", Trunk: "ms3", Branch: "ms4"},
+		{Name: "B", Trunk: "A", Branch: "ms4"},
+	})
+	conf := te.ConfirmMilestoneOn("B")
+
+	ms2Tail, ms3Tail, ms4Tail := te.TailOf("ms2"), te.TailOf("ms3"), te.TailOf("ms4")
+
+	var prunedIndexes []milestone.Index
+	progressCalls := 0
+
+	// the tail of milestone 3 is retained, it is part of the cones of milestone 3 and 4
+	err := dag.DeleteTransactionMetadataBelowIndex(2, conf.Index, true, 1,
+		func(msIndex milestone.Index, txHashes map[string]struct{}) {
+			delete(txHashes, string(ms3Tail))
+		},
+		func(deletedCount int, totalCount int) {
+			progressCalls++
+		},
+		func(msIndex milestone.Index, txCountDeleted int, txCountChecked int, err error) {
+			require.NoError(t, err)
+			require.LessOrEqual(t, txCountDeleted, txCountChecked)
+			prunedIndexes = append(prunedIndexes, msIndex)
+		},
+		nil)
+	require.NoError(t, err)
+	require.Equal(t, []milestone.Index{2, 3, 4}, prunedIndexes)
+	require.NotZero(t, progressCalls)
+
+	require.False(t, tangle.ContainsTransaction(ms2Tail))
+	require.False(t, tangle.ContainsTransaction(ms4Tail))
+	require.True(t, tangle.ContainsTransaction(ms3Tail))
+
+	// the cone of the milestone at the target index is kept
+	require.True(t, tangle.ContainsTransaction(te.TailOf("A")))
+	require.True(t, tangle.ContainsTransaction(te.TailOf("B")))
+}
+
+func TestDeleteTransactionMetadataBelowIndexAborted(t *testing.T) {
+	te := testsuite.SetupTestEnvironment(t, make(map[string]uint64), 3, false)
+	defer te.CleanupTestEnvironment(true)
+
+	ms2Tail := te.TailOf("ms2")
+
+	abortSignal := make(chan struct{})
+	close(abortSignal)
+
+	err := dag.DeleteTransactionMetadataBelowIndex(2, 4, true, 1, nil, nil,
+		func(msIndex milestone.Index, txCountDeleted int, txCountChecked int, err error) {
+			require.Fail(t, "no milestone is pruned after the abort signal", "index: %d", msIndex)
+		},
+		abortSignal)
+	require.True(t, errors.Is(err, tangle.ErrOperationAborted), "error: %v", err)
+	require.True(t, tangle.ContainsTransaction(ms2Tail))
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"testing"
+
+	_ "golang.org/x/crypto/blake2b"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/gohornet/hornet/pkg/testsuite/utils"
+)
+
+// attachTips attaches the given amount of zero value bundles on top of the latest milestones and returns their tails.
+func attachTips(tb testing.TB, te *testsuite.TestEnvironment, count int) hornet.Hashes {
+	trunk := te.Milestones[len(te.Milestones)-1].GetBundle().GetTailHash()
+	branch := te.Milestones[len(te.Milestones)-2].GetBundle().GetTailHash()
+
+	tails := make(hornet.Hashes, count)
+	for i := 0; i < count; i++ {
+		tails[i] = te.AttachAndStoreBundle(trunk, branch, utils.ZeroValueTx(tb, "TIP")).GetBundle().GetTailHash()
+		trunk = tails[i]
+	}
+	return tails
+}
+
+func TestRootSnapshotIndexesCache(t *testing.T) {
+	te := testsuite.SetupTestEnvironment(t, make(map[string]uint64), 3, false)
+	defer te.CleanupTestEnvironment(true)
+
+	tails := attachTips(t, te, 3)
+	lsmi := tangle.GetSolidMilestoneIndex()
+
+	hits := metrics.SharedServerMetrics.RootSnapshotIndexesCacheHits.Load()
+	misses := metrics.SharedServerMetrics.RootSnapshotIndexesCacheMisses.Load()
+
+	yrtsi, ortsi, exists := dag.GetTransactionRootSnapshotIndexesByHash(tails[2], lsmi)
+	require.True(t, exists)
+	require.Equal(t, lsmi, yrtsi)
+	require.Equal(t, lsmi-1, ortsi)
+	require.Equal(t, misses+1, metrics.SharedServerMetrics.RootSnapshotIndexesCacheMisses.Load())
+
+	// the indexes are cached for the current solid milestone
+	cachedYrtsi, cachedOrtsi, exists := dag.GetTransactionRootSnapshotIndexesByHash(tails[2], lsmi)
+	require.True(t, exists)
+	require.Equal(t, yrtsi, cachedYrtsi)
+	require.Equal(t, ortsi, cachedOrtsi)
+	require.Equal(t, hits+1, metrics.SharedServerMetrics.RootSnapshotIndexesCacheHits.Load())
+
+	_, _, exists = dag.GetTransactionRootSnapshotIndexesByHash(hornet.NullHashBytes[:49], lsmi)
+	require.False(t, exists)
+
+	// a new solid milestone invalidates the cached indexes, the youngest root is the new milestone now
+	te.IssueAndConfirmMilestoneOnTip(tails[0], false)
+	newLsmi := tangle.GetSolidMilestoneIndex()
+	require.Equal(t, lsmi+1, newLsmi)
+
+	yrtsi, ortsi, exists = dag.GetTransactionRootSnapshotIndexesByHash(tails[2], newLsmi)
+	require.True(t, exists)
+	require.Equal(t, newLsmi, yrtsi)
+	require.Equal(t, lsmi-1, ortsi)
+}
+
+// BenchmarkRootSnapshotIndexes compares the lookups of the tip selection via the cache with loading the metadata,
+// which is removed from the object storage after every lookup.
+func BenchmarkRootSnapshotIndexes(b *testing.B) {
+	te := testsuite.SetupTestEnvironment(b, make(map[string]uint64), 3, false)
+	defer te.CleanupTestEnvironment(true)
+
+	tails := attachTips(b, te, 100)
+	lsmi := tangle.GetSolidMilestoneIndex()
+
+	// the indexes are calculated once, both variants only look them up afterwards
+	for _, tail := range tails {
+		dag.GetTransactionRootSnapshotIndexesByHash(tail, lsmi)
+	}
+
+	b.Run("metadata", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dag.GetTransactionRootSnapshotIndexes(tangle.GetCachedTxMetadataOrNil(tails[i%len(tails)]), lsmi) // meta pass +1
+		}
+	})
+
+	b.Run("cache", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			dag.GetTransactionRootSnapshotIndexesByHash(tails[i%len(tails)], lsmi)
+		}
+	})
+}
@@ -20,6 +20,24 @@ func UpdateOutdatedRootSnapshotIndexes(outdatedTransactions hornet.Hashes, lsmi
 	}
 }
 
+// GetTransactionRootSnapshotIndexesByHash returns the transaction root snapshot indexes for the transaction with the given hash.
+// The indexes of recent transactions are requested repeatedly by the tip selection, so they are taken from the cache
+// if possible, which avoids loading the metadata of the transaction.
+// It returns false if the transaction doesn't exist.
+func GetTransactionRootSnapshotIndexesByHash(txHash hornet.Hash, lsmi milestone.Index) (youngestTxRootSnapshotIndex milestone.Index, oldestTxRootSnapshotIndex milestone.Index, exists bool) {
+	if yrtsi, ortsi, cached := getCachedRootSnapshotIndexes(txHash, lsmi); cached {
+		return yrtsi, ortsi, true
+	}
+
+	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(txHash) // meta +1
+	if cachedTxMeta == nil {
+		return 0, 0, false
+	}
+
+	yrtsi, ortsi := GetTransactionRootSnapshotIndexes(cachedTxMeta, lsmi) // meta pass +1
+	return yrtsi, ortsi, true
+}
+
 // GetTransactionRootSnapshotIndexes searches the transaction root snapshot indexes for a given transaction.
 func GetTransactionRootSnapshotIndexes(cachedTxMeta *tangle.CachedMetadata, lsmi milestone.Index) (youngestTxRootSnapshotIndex milestone.Index, oldestTxRootSnapshotIndex milestone.Index) {
 	defer cachedTxMeta.Release(true) // meta -1
@@ -28,6 +46,7 @@ func GetTransactionRootSnapshotIndexes(cachedTxMeta *tangle.CachedMetadata, lsmi
 	// information about yrtsi and ortsi, return that info
 	yrtsi, ortsi, rtsci := cachedTxMeta.GetMetadata().GetRootSnapshotIndexes()
 	if rtsci == lsmi {
+		cacheRootSnapshotIndexes(cachedTxMeta.GetMetadata().GetTxHash(), lsmi, yrtsi, ortsi)
 		return yrtsi, ortsi
 	}
 
@@ -120,6 +139,7 @@ func GetTransactionRootSnapshotIndexes(cachedTxMeta *tangle.CachedMetadata, lsmi
 
 	// set the new transaction root snapshot indexes in the metadata of the transaction
 	cachedTxMeta.GetMetadata().SetRootSnapshotIndexes(youngestTxRootSnapshotIndex, oldestTxRootSnapshotIndex, lsmi)
+	cacheRootSnapshotIndexes(startTxHash, lsmi, youngestTxRootSnapshotIndex, oldestTxRootSnapshotIndex)
 
 	return youngestTxRootSnapshotIndex, oldestTxRootSnapshotIndex
 }
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"bytes"
+	"path/filepath"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/iotaledger/hive.go/byteutils"
+	"github.com/iotaledger/hive.go/kvstore"
+	hivebadger "github.com/iotaledger/hive.go/kvstore/badger"
+)
+
+// badgerStore adds the ordered iteration of keys to the badger store of hive.go.
+// The realms of the store are prefixed to the keys of the badger database.
+type badgerStore struct {
+	kvstore.KVStore
+	db *badger.DB
+}
+
+func (s *badgerStore) WithRealm(realm kvstore.Realm) kvstore.KVStore {
+	return &badgerStore{KVStore: s.KVStore.WithRealm(realm), db: s.db}
+}
+
+func (s *badgerStore) IterateKeysFrom(prefix kvstore.KeyPrefix, start kvstore.Key, consumerFunc kvstore.IteratorKeyConsumerFunc) error {
+	realm := s.Realm()
+
+	seek := start
+	if bytes.Compare(seek, prefix) < 0 {
+		seek = prefix
+	}
+
+	return s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = byteutils.ConcatBytes(realm, prefix)
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(byteutils.ConcatBytes(realm, seek)); it.ValidForPrefix(opts.Prefix); it.Next() {
+			if !consumerFunc(it.Item().KeyCopy(nil)[len(realm):]) {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+const (
+	// the ratio of discardable data in a value log file above which the file is rewritten.
+	badgerValueLogGCDiscardRatio = 0.7
+	// the maximum duration of a cleanup, since every rewrite of a value log file blocks the writes to it.
+	badgerCleanupMaxDuration = 5 * time.Minute
+)
+
+// rewrites the value log files of the badger database until no file contains enough discardable data anymore.
+func cleanupBadger(db *badger.DB) error {
+	cleaned := false
+	for start := time.Now(); time.Since(start) < badgerCleanupMaxDuration; {
+		err := db.RunValueLogGC(badgerValueLogGCDiscardRatio)
+		if err == badger.ErrNoRewrite {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		cleaned = true
+	}
+
+	if !cleaned {
+		return ErrNothingToCleanUp
+	}
+	return nil
+}
+
+func openBadger(directory string, name string) (*Database, error) {
+	path := filepath.Join(directory, name)
+
+	db, err := hivebadger.CreateDB(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Database{
+		engine: EngineBadger,
+		path:   path,
+		store:  &badgerStore{KVStore: hivebadger.New(db), db: db},
+		flush:  db.Sync,
+		close:  db.Close,
+		// the backups of badger are streams of the entries, not a database directory which could be opened again.
+		backup: nil,
+		// the deleted values stay in the value log files until they are rewritten by the cleanup.
+		freeSize: nil,
+		cleanup: func() error {
+			return cleanupBadger(db)
+		},
+	}, nil
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/bolt"
+)
+
+// boltStore adds the ordered iteration of keys to the bolt store of hive.go.
+// The realms of the store are the buckets of the bolt database.
+type boltStore struct {
+	kvstore.KVStore
+	db *bbolt.DB
+}
+
+func (s *boltStore) WithRealm(realm kvstore.Realm) kvstore.KVStore {
+	return &boltStore{KVStore: s.KVStore.WithRealm(realm), db: s.db}
+}
+
+func (s *boltStore) IterateKeysFrom(prefix kvstore.KeyPrefix, start kvstore.Key, consumerFunc kvstore.IteratorKeyConsumerFunc) error {
+	return s.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(s.Realm())
+		if bucket == nil {
+			return nil
+		}
+
+		seek := start
+		if bytes.Compare(seek, prefix) < 0 {
+			seek = prefix
+		}
+
+		cursor := bucket.Cursor()
+		for key, _ := cursor.Seek(seek); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+			// the key is only valid during the transaction
+			if !consumerFunc(append(kvstore.Key{}, key...)) {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+func openBolt(directory string, name string) (*Database, error) {
+	opts := &bbolt.Options{
+		NoSync: true,
+	}
+	db, err := bolt.CreateDB(directory, name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Database{
+		engine: EngineBolt,
+		path:   filepath.Join(directory, name),
+		store:  &boltStore{KVStore: bolt.New(db), db: db},
+		flush:  db.Sync,
+		close:  db.Close,
+		backup: func(w io.Writer) (int64, error) {
+			var written int64
+			// a read transaction sees a consistent state of the database, writers are not blocked
+			err := db.View(func(tx *bbolt.Tx) error {
+				var err error
+				written, err = tx.WriteTo(w)
+				return err
+			})
+			return written, err
+		},
+		freeSize: func() int64 {
+			// the pages on the freelist are reused for new data, but the file never shrinks
+			return int64(db.Stats().FreeAlloc)
+		},
+	}, nil
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+)
+
+const (
+	// the amount of entries which are written to the target in one batch.
+	copyBatchSize = 10000
+)
+
+// CopyConsumer is called with the amount of copied entries after every batch.
+type CopyConsumer func(copied int)
+
+// Copy copies all entries of the source store into the target store.
+// All stores of the node live in single byte realms, therefore every one of those realms is copied
+// separately, since some engines (e.g. bolt) do not iterate over the entries of other realms.
+func Copy(source kvstore.KVStore, target kvstore.KVStore, progress CopyConsumer) (int, error) {
+	copied := 0
+
+	for realm := 0; realm <= 255; realm++ {
+		sourceRealm := source.WithRealm([]byte{byte(realm)})
+		targetRealm := target.WithRealm([]byte{byte(realm)})
+
+		batch := targetRealm.Batched()
+		batchSize := 0
+
+		var innerErr error
+		if err := sourceRealm.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+			if innerErr = batch.Set(key, value); innerErr != nil {
+				return false
+			}
+			copied++
+
+			if batchSize++; batchSize < copyBatchSize {
+				return true
+			}
+
+			if innerErr = batch.Commit(); innerErr != nil {
+				return false
+			}
+			if progress != nil {
+				progress(copied)
+			}
+			batch = targetRealm.Batched()
+			batchSize = 0
+			return true
+		}); err != nil {
+			return copied, errors.Wrapf(err, "unable to read realm %d", realm)
+		}
+
+		if innerErr != nil {
+			batch.Cancel()
+			return copied, errors.Wrapf(innerErr, "unable to write realm %d", realm)
+		}
+
+		if err := batch.Commit(); err != nil {
+			return copied, errors.Wrapf(err, "unable to write realm %d", realm)
+		}
+	}
+
+	if progress != nil {
+		progress(copied)
+	}
+	return copied, nil
+}
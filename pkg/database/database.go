@@ -0,0 +1,170 @@
+package database
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+)
+
+// Engine is a key-value database engine.
+type Engine string
+
+const (
+	// EngineBolt is the bbolt engine, a single file B+tree database.
+	EngineBolt Engine = "bolt"
+	// EngineBadger is the BadgerDB engine, a LSM tree database.
+	EngineBadger Engine = "badger"
+)
+
+var (
+	// ErrUnknownEngine is returned if the name of an engine is unknown.
+	ErrUnknownEngine = errors.New("unknown database engine")
+	// ErrBackupNotSupported is returned if the engine of a database can't write a backup while it is in use.
+	ErrBackupNotSupported = errors.New("database engine does not support backups")
+	// ErrNothingToCleanUp is returned if the cleanup of a database didn't find anything to clean up.
+	ErrNothingToCleanUp = errors.New("nothing to clean up in the database")
+
+	// the probe key which is read to check the health of a database.
+	healthProbeKey = []byte("healthProbe")
+)
+
+// opens a database with the given name in the given directory.
+type openFunc func(directory string, name string) (*Database, error)
+
+var (
+	// the supported engines.
+	openFuncs = map[Engine]openFunc{
+		EngineBolt:   openBolt,
+		EngineBadger: openBadger,
+	}
+)
+
+// EngineFromString parses the given engine name.
+func EngineFromString(name string) (Engine, error) {
+	engine := Engine(strings.ToLower(name))
+	if _, exists := openFuncs[engine]; !exists {
+		return "", errors.Wrapf(ErrUnknownEngine, "%s", name)
+	}
+	return engine, nil
+}
+
+// Database is a key-value database opened with one of the engines.
+type Database struct {
+	engine Engine
+	// the file or directory the database is stored in.
+	path  string
+	store kvstore.KVStore
+	flush func() error
+	close func() error
+	// writes a consistent copy of the database, nil if the engine doesn't support it.
+	backup func(w io.Writer) (int64, error)
+	// returns the bytes on disk which are free to be reused by the engine, nil if the engine releases them itself.
+	freeSize func() int64
+	// frees the space of deleted data, nil if the engine doesn't need it.
+	cleanup func() error
+}
+
+// New opens the database with the given name in the given directory with the given engine.
+// The database is created if it does not exist yet.
+func New(engine Engine, directory string, name string) (*Database, error) {
+	open, exists := openFuncs[engine]
+	if !exists {
+		return nil, errors.Wrapf(ErrUnknownEngine, "%s", engine)
+	}
+
+	db, err := open(directory, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open the %s database %s", engine, filepath.Join(directory, name))
+	}
+	return db, nil
+}
+
+// Engine returns the engine of the database.
+func (db *Database) Engine() Engine {
+	return db.engine
+}
+
+// Name returns the file or directory name of the database.
+func (db *Database) Name() string {
+	return filepath.Base(db.path)
+}
+
+// KVStore returns the key-value store of the database.
+func (db *Database) KVStore() kvstore.KVStore {
+	return db.store
+}
+
+// Size returns the size of the database on disk in bytes.
+func (db *Database) Size() (int64, error) {
+	var size int64
+	err := filepath.Walk(db.path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// LiveSize returns the size of the live data of the database in bytes.
+// Unlike Size, it doesn't include space which was freed by deletions, but is still occupied on disk
+// (e.g. bolt files never shrink, they need to be compacted via the db-migration tool).
+func (db *Database) LiveSize() (int64, error) {
+	size, err := db.Size()
+	if err != nil || db.freeSize == nil {
+		return size, err
+	}
+	return size - db.freeSize(), nil
+}
+
+// SupportsCleanup returns whether the engine of the database needs to be cleaned up to free the space of deleted data.
+func (db *Database) SupportsCleanup() bool {
+	return db.cleanup != nil
+}
+
+// Cleanup frees the space of deleted data.
+// It returns ErrNothingToCleanUp if there was nothing to free.
+func (db *Database) Cleanup() error {
+	if db.cleanup == nil {
+		return ErrNothingToCleanUp
+	}
+	return db.cleanup()
+}
+
+// CheckHealth checks whether the database can be read from.
+func (db *Database) CheckHealth() error {
+	if _, err := db.store.Get(healthProbeKey); err != nil && err != kvstore.ErrKeyNotFound {
+		return errors.Wrapf(err, "%s database %s is not healthy", db.engine, db.path)
+	}
+	return nil
+}
+
+// Backup writes a consistent copy of the database to w while the database stays in use.
+// It returns the amount of written bytes.
+func (db *Database) Backup(w io.Writer) (int64, error) {
+	if db.backup == nil {
+		return 0, errors.Wrapf(ErrBackupNotSupported, "%s", db.engine)
+	}
+	return db.backup(w)
+}
+
+// Flush writes all pending changes of the database to disk.
+func (db *Database) Flush() error {
+	return db.flush()
+}
+
+// Close flushes and closes the database.
+func (db *Database) Close() error {
+	if err := db.flush(); err != nil {
+		return err
+	}
+	return db.close()
+}
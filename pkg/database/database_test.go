@@ -0,0 +1,163 @@
+package database
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+)
+
+func TestEngineFromString(t *testing.T) {
+	engine, err := EngineFromString("Bolt")
+	require.NoError(t, err)
+	assert.Equal(t, EngineBolt, engine)
+
+	engine, err = EngineFromString("badger")
+	require.NoError(t, err)
+	assert.Equal(t, EngineBadger, engine)
+
+	_, err = EngineFromString("leveldb")
+	assert.True(t, errors.Is(err, ErrUnknownEngine))
+
+	_, err = New(Engine("leveldb"), t.TempDir(), "test.db")
+	assert.True(t, errors.Is(err, ErrUnknownEngine))
+}
+
+func TestCopy(t *testing.T) {
+	source := mapdb.NewMapDB()
+	require.NoError(t, source.WithRealm([]byte{1}).Set([]byte("a"), []byte("1")))
+	require.NoError(t, source.WithRealm([]byte{2}).Set([]byte("b"), []byte("2")))
+
+	db, err := New(EngineBolt, t.TempDir(), "test.db")
+	require.NoError(t, err)
+	defer db.Close()
+
+	copied, err := Copy(source, db.KVStore(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, copied)
+
+	value, err := db.KVStore().WithRealm([]byte{2}).Get([]byte("b"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("2"), []byte(value))
+
+	require.NoError(t, db.CheckHealth())
+	require.NoError(t, db.Flush())
+
+	size, err := db.Size()
+	require.NoError(t, err)
+	assert.True(t, size > 0)
+}
+
+func TestBadger(t *testing.T) {
+	db, err := New(EngineBadger, t.TempDir(), "test")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.KVStore().WithRealm([]byte{1}).Set([]byte("a"), []byte("1")))
+	require.NoError(t, db.KVStore().WithRealm([]byte{2}).Set([]byte("a"), []byte("2")))
+
+	value, err := db.KVStore().WithRealm([]byte{2}).Get([]byte("a"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("2"), []byte(value))
+
+	require.NoError(t, db.CheckHealth())
+	require.NoError(t, db.Flush())
+
+	size, err := db.Size()
+	require.NoError(t, err)
+	assert.True(t, size > 0)
+
+	_, err = db.Backup(ioutil.Discard)
+	assert.True(t, errors.Is(err, ErrBackupNotSupported))
+
+	// the value log files are only rewritten if they contain enough deleted data
+	assert.True(t, db.SupportsCleanup())
+	assert.Equal(t, ErrNothingToCleanUp, db.Cleanup())
+}
+
+func TestBackup(t *testing.T) {
+	db, err := New(EngineBolt, t.TempDir(), "test.db")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.KVStore().WithRealm([]byte{1}).Set([]byte("a"), []byte("1")))
+
+	var buf bytes.Buffer
+	written, err := db.Backup(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), written)
+
+	// the backup is a database file itself
+	directory := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(directory, db.Name()), buf.Bytes(), 0600))
+
+	restored, err := New(EngineBolt, directory, db.Name())
+	require.NoError(t, err)
+	defer restored.Close()
+
+	value, err := restored.KVStore().WithRealm([]byte{1}).Get([]byte("a"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), []byte(value))
+}
+
+func TestLiveSize(t *testing.T) {
+	db, err := New(EngineBolt, t.TempDir(), "test.db")
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := db.KVStore().WithRealm([]byte{1})
+	value := bytes.Repeat([]byte{1}, 1024)
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, store.Set([]byte{byte(i >> 8), byte(i)}, value))
+	}
+	require.NoError(t, store.Clear())
+	require.NoError(t, db.Flush())
+
+	// the file doesn't shrink, but the freed pages are not counted as live data
+	size, err := db.Size()
+	require.NoError(t, err)
+	liveSize, err := db.LiveSize()
+	require.NoError(t, err)
+	assert.True(t, size-liveSize > 500*1024, "size: %d, live size: %d", size, liveSize)
+}
+
+func TestIterateKeysFrom(t *testing.T) {
+	boltDB, err := New(EngineBolt, t.TempDir(), "test.db")
+	require.NoError(t, err)
+	defer boltDB.Close()
+
+	badgerDB, err := New(EngineBadger, t.TempDir(), "test")
+	require.NoError(t, err)
+	defer badgerDB.Close()
+
+	for _, store := range []kvstore.KVStore{
+		boltDB.KVStore().WithRealm([]byte{1}),
+		badgerDB.KVStore().WithRealm([]byte{1}),
+		mapdb.NewMapDB().WithRealm([]byte{1}),
+	} {
+		for _, key := range [][]byte{{1, 3}, {1, 1}, {2, 1}, {1, 2}, {0, 9}, {1, 4}} {
+			require.NoError(t, store.Set(key, []byte{0}))
+		}
+
+		var keys []kvstore.Key
+		require.NoError(t, IterateKeysFrom(store, []byte{1}, []byte{1, 2}, func(key kvstore.Key) bool {
+			keys = append(keys, key)
+			return len(keys) < 2
+		}))
+		assert.Equal(t, []kvstore.Key{{1, 2}, {1, 3}}, keys)
+
+		keys = nil
+		require.NoError(t, IterateKeysFrom(store, []byte{1}, nil, func(key kvstore.Key) bool {
+			keys = append(keys, key)
+			return true
+		}))
+		assert.Equal(t, []kvstore.Key{{1, 1}, {1, 2}, {1, 3}, {1, 4}}, keys)
+	}
+}
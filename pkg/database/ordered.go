@@ -0,0 +1,45 @@
+package database
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/iotaledger/hive.go/kvstore"
+)
+
+// OrderedKeyIterator is implemented by stores which can iterate their keys in ascending order, starting at a given key.
+type OrderedKeyIterator interface {
+	// IterateKeysFrom passes the keys with the given prefix which are equal to or greater than start
+	// in ascending order to the consumer, until the consumer returns false.
+	IterateKeysFrom(prefix kvstore.KeyPrefix, start kvstore.Key, consumerFunc kvstore.IteratorKeyConsumerFunc) error
+}
+
+// IterateKeysFrom passes the keys of the given store with the given prefix which are equal to or greater than start
+// in ascending order to the consumer, until the consumer returns false.
+// Stores which don't implement OrderedKeyIterator are fully iterated and their keys are sorted in memory.
+func IterateKeysFrom(store kvstore.KVStore, prefix kvstore.KeyPrefix, start kvstore.Key, consumerFunc kvstore.IteratorKeyConsumerFunc) error {
+	if iterator, ok := store.(OrderedKeyIterator); ok {
+		return iterator.IterateKeysFrom(prefix, start, consumerFunc)
+	}
+
+	var keys []kvstore.Key
+	if err := store.IterateKeys(prefix, func(key kvstore.Key) bool {
+		if bytes.Compare(key, start) >= 0 {
+			keys = append(keys, key)
+		}
+		return true
+	}); err != nil {
+		return err
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+
+	for _, key := range keys {
+		if !consumerFunc(key) {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/config"
+)
+
+const (
+	// APISubject is the subject of the JWTs which authenticate at the HTTP API and the dashboard.
+	APISubject = "HORNET-API"
+)
+
+// NewAPIAuthFromConfig creates the JWTAuth for the HTTP API and the dashboard out of the node config.
+func NewAPIAuthFromConfig() (*JWTAuth, error) {
+	secret, err := SecretFromConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	sessionTimeout := time.Duration(config.NodeConfig.GetInt(config.CfgWebAPIJWTAuthSessionTimeoutSeconds)) * time.Second
+	return New(APISubject, sessionTimeout, secret)
+}
+
+// SecretFromConfig returns the secret the JWTs are signed with.
+// If no secret is configured, the secret is derived from the seed of the node identity,
+// so that the tokens stay valid after a restart of the node.
+func SecretFromConfig() ([]byte, error) {
+	if secret := config.NodeConfig.GetString(config.CfgWebAPIJWTAuthSecret); secret != "" {
+		return []byte(secret), nil
+	}
+
+	for _, seedKey := range []string{config.CfgNetGossipEncryptionSeed, config.CfgNetAutopeeringSeed} {
+		seedStr := config.NodeConfig.GetString(seedKey)
+		if seedStr == "" {
+			continue
+		}
+
+		seed, err := base58.Decode(seedStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", seedKey)
+		}
+
+		secret := sha256.Sum256(append([]byte("jwt"), seed...))
+		return secret[:], nil
+	}
+
+	return nil, errors.Wrapf(ErrNoSecret, "configure '%s' or an identity seed", config.CfgWebAPIJWTAuthSecret)
+}
@@ -0,0 +1,93 @@
+package jwt
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	bearerPrefix = "Bearer "
+)
+
+var (
+	// ErrNoSecret is returned when a JWTAuth is created without a secret.
+	ErrNoSecret = errors.New("no secret for signing the JWTs given")
+	// ErrInvalidJWT is returned when a JWT is not valid.
+	ErrInvalidJWT = errors.New("invalid JWT")
+)
+
+// JWTAuth issues and verifies the JSON web tokens used to authenticate at the APIs of the node.
+type JWTAuth struct {
+	subject        string
+	sessionTimeout time.Duration
+	secret         []byte
+}
+
+// New creates a new JWTAuth which signs the tokens for the given subject with the given secret.
+// The tokens expire after the given session timeout, a session timeout of zero issues tokens which never expire.
+func New(subject string, sessionTimeout time.Duration, secret []byte) (*JWTAuth, error) {
+	if len(secret) == 0 {
+		return nil, ErrNoSecret
+	}
+
+	return &JWTAuth{
+		subject:        subject,
+		sessionTimeout: sessionTimeout,
+		secret:         secret,
+	}, nil
+}
+
+// IssueJWT issues a new signed token.
+func (j *JWTAuth) IssueJWT() (string, error) {
+	now := time.Now()
+
+	claims := &jwt.StandardClaims{
+		Subject:   j.subject,
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+	}
+	if j.sessionTimeout != 0 {
+		claims.ExpiresAt = now.Add(j.sessionTimeout).Unix()
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(j.secret)
+}
+
+// VerifyJWT checks whether the given token was issued for the subject of the JWTAuth, is signed with its secret and did not expire.
+func (j *JWTAuth) VerifyJWT(token string) error {
+	claims := &jwt.StandardClaims{}
+
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return j.secret, nil
+	})
+	if err != nil {
+		return errors.Wrapf(ErrInvalidJWT, "%v", err)
+	}
+
+	if !parsed.Valid {
+		return ErrInvalidJWT
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Subject), []byte(j.subject)) != 1 {
+		return errors.Wrapf(ErrInvalidJWT, "wrong subject: %s", claims.Subject)
+	}
+
+	return nil
+}
+
+// VerifyAuthorizationHeader verifies the bearer token of the given value of an "Authorization" HTTP header.
+func (j *JWTAuth) VerifyAuthorizationHeader(header string) error {
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return errors.Wrap(ErrInvalidJWT, "no bearer token given")
+	}
+
+	return j.VerifyJWT(strings.TrimPrefix(header, bearerPrefix))
+}
@@ -0,0 +1,40 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTAuth(t *testing.T) {
+	_, err := New("API", 0, nil)
+	assert.True(t, errors.Is(err, ErrNoSecret))
+
+	auth, err := New("API", time.Hour, []byte("secret"))
+	require.NoError(t, err)
+
+	token, err := auth.IssueJWT()
+	require.NoError(t, err)
+	assert.NoError(t, auth.VerifyJWT(token))
+	assert.NoError(t, auth.VerifyAuthorizationHeader("Bearer "+token))
+	assert.True(t, errors.Is(auth.VerifyAuthorizationHeader(token), ErrInvalidJWT))
+
+	// tokens of other nodes or for other subjects are not valid
+	otherSecret, err := New("API", time.Hour, []byte("other secret"))
+	require.NoError(t, err)
+	assert.True(t, errors.Is(otherSecret.VerifyJWT(token), ErrInvalidJWT))
+
+	otherSubject, err := New("Dashboard", time.Hour, []byte("secret"))
+	require.NoError(t, err)
+	assert.True(t, errors.Is(otherSubject.VerifyJWT(token), ErrInvalidJWT))
+
+	// expired tokens are not valid
+	expired, err := New("API", -time.Minute, []byte("secret"))
+	require.NoError(t, err)
+	expiredToken, err := expired.IssueJWT()
+	require.NoError(t, err)
+	assert.True(t, errors.Is(auth.VerifyJWT(expiredToken), ErrInvalidJWT))
+}
@@ -0,0 +1,202 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/syncutils"
+)
+
+var (
+	// the level of all components without an own level.
+	defaultLevel = logger.LevelInfo
+	// the components which created a logger, by name.
+	components     = make(map[string]*component)
+	componentsLock syncutils.Mutex
+)
+
+// component is a subsystem of the node with a named logger.
+type component struct {
+	// the level which is currently applied to the logger of the component.
+	level zap.AtomicLevel
+	// whether the component has an own level, otherwise the default level applies.
+	hasOwnLevel bool
+}
+
+// ComponentLevel is the level of a component.
+type ComponentLevel struct {
+	Name        string       `json:"name"`
+	Level       logger.Level `json:"level"`
+	HasOwnLevel bool         `json:"hasOwnLevel"`
+}
+
+// ParseLevel parses the textual representation of a level, e.g. "debug" or "WARN".
+func ParseLevel(text string) (logger.Level, error) {
+	var level logger.Level
+	if err := level.UnmarshalText([]byte(text)); err != nil {
+		return level, err
+	}
+	return level, nil
+}
+
+// ParseComponentLevels parses the textual representations of the levels of the given components.
+func ParseComponentLevels(levels map[string]string) (map[string]logger.Level, error) {
+	parsed := make(map[string]logger.Level, len(levels))
+	for name, text := range levels {
+		level, err := ParseLevel(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid level of component %s: %w", name, err)
+		}
+		parsed[name] = level
+	}
+	return parsed, nil
+}
+
+// NewLogger returns a new named child of the global root logger for the given component.
+// The level of the logger can be changed at runtime with SetComponentLevel.
+func NewLogger(name string) *logger.Logger {
+	componentsLock.Lock()
+	c := getOrCreateComponent(name)
+	componentsLock.Unlock()
+
+	return newComponentLogger(logger.NewLogger(name).Desugar(), c.level).Sugar()
+}
+
+// wraps the core of the given logger, so that only entries of the given level or above are written.
+func newComponentLogger(log *zap.Logger, level zap.AtomicLevel) *zap.Logger {
+	return log.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &componentCore{Core: core, level: level}
+	}))
+}
+
+// returns the component with the given name, a new component follows the default level.
+// componentsLock must be held.
+func getOrCreateComponent(name string) *component {
+	if c, exists := components[name]; exists {
+		return c
+	}
+
+	c := &component{level: zap.NewAtomicLevelAt(defaultLevel)}
+	components[name] = c
+	return c
+}
+
+// SetLevel sets the level of all components without an own level.
+func SetLevel(level logger.Level) {
+	componentsLock.Lock()
+	defer componentsLock.Unlock()
+
+	defaultLevel = level
+	for _, c := range components {
+		if !c.hasOwnLevel {
+			c.level.SetLevel(level)
+		}
+	}
+	applyRootLevel()
+}
+
+// SetComponentLevel sets an own level for the component with the given name.
+// Components which create their logger afterwards start with this level.
+func SetComponentLevel(name string, level logger.Level) {
+	componentsLock.Lock()
+	defer componentsLock.Unlock()
+
+	c := getOrCreateComponent(name)
+	c.hasOwnLevel = true
+	c.level.SetLevel(level)
+	applyRootLevel()
+}
+
+// ResetComponentLevel removes the own level of the component with the given name, so that the default level applies again.
+func ResetComponentLevel(name string) {
+	componentsLock.Lock()
+	defer componentsLock.Unlock()
+
+	c, exists := components[name]
+	if !exists {
+		return
+	}
+
+	c.hasOwnLevel = false
+	c.level.SetLevel(defaultLevel)
+	applyRootLevel()
+}
+
+// SetComponentLevels replaces the own levels of all components with the given levels by component name.
+func SetComponentLevels(levels map[string]logger.Level) {
+	componentsLock.Lock()
+	defer componentsLock.Unlock()
+
+	for name, c := range components {
+		if _, exists := levels[name]; !exists {
+			c.hasOwnLevel = false
+			c.level.SetLevel(defaultLevel)
+		}
+	}
+
+	for name, level := range levels {
+		c := getOrCreateComponent(name)
+		c.hasOwnLevel = true
+		c.level.SetLevel(level)
+	}
+	applyRootLevel()
+}
+
+// Levels returns the default level and the levels of all known components sorted by name.
+func Levels() (logger.Level, []*ComponentLevel) {
+	componentsLock.Lock()
+	defer componentsLock.Unlock()
+
+	levels := make([]*ComponentLevel, 0, len(components))
+	for name, c := range components {
+		levels = append(levels, &ComponentLevel{
+			Name:        name,
+			Level:       c.level.Level(),
+			HasOwnLevel: c.hasOwnLevel,
+		})
+	}
+
+	sort.Slice(levels, func(i int, j int) bool {
+		return levels[i].Name < levels[j].Name
+	})
+
+	return defaultLevel, levels
+}
+
+// the root logger filters all entries before the components do,
+// so its level is set to the lowest level of the default and all components.
+// componentsLock must be held.
+func applyRootLevel() {
+	rootLevel := defaultLevel
+	for _, c := range components {
+		if c.hasOwnLevel && c.level.Level() < rootLevel {
+			rootLevel = c.level.Level()
+		}
+	}
+	logger.SetLevel(rootLevel)
+}
+
+// componentCore only passes the entries of the level of a component or above to the wrapped core.
+type componentCore struct {
+	zapcore.Core
+	level zap.AtomicLevel
+}
+
+func (c *componentCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level) && c.Core.Enabled(level)
+}
+
+func (c *componentCore) With(fields []zapcore.Field) zapcore.Core {
+	return &componentCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *componentCore) Check(entry zapcore.Entry, checkedEntry *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(entry.Level) {
+		return checkedEntry
+	}
+	return c.Core.Check(entry, checkedEntry)
+}
@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/iotaledger/hive.go/logger"
+)
+
+func TestComponentLevels(t *testing.T) {
+	core, logs := observer.New(logger.LevelDebug)
+
+	componentsLock.Lock()
+	gossip := getOrCreateComponent("Gossip")
+	tangle := getOrCreateComponent("Tangle")
+	componentsLock.Unlock()
+
+	gossipLog := newComponentLogger(zap.New(core), gossip.level).Sugar()
+	tangleLog := newComponentLogger(zap.New(core), tangle.level).Sugar().Named("Child")
+
+	SetLevel(logger.LevelInfo)
+	SetComponentLevel("Gossip", logger.LevelDebug)
+
+	gossipLog.Debug("gossip debug")
+	tangleLog.Debug("tangle debug")
+	tangleLog.Info("tangle info")
+	require.Equal(t, []string{"gossip debug", "tangle info"}, messages(logs))
+
+	defaultLevel, levels := Levels()
+	require.Equal(t, logger.LevelInfo, defaultLevel)
+	require.Equal(t, []*ComponentLevel{
+		{Name: "Gossip", Level: logger.LevelDebug, HasOwnLevel: true},
+		{Name: "Tangle", Level: logger.LevelInfo},
+	}, levels)
+
+	// the default level does not apply to components with an own level
+	SetLevel(logger.LevelWarn)
+	gossipLog.Debug("gossip debug")
+	tangleLog.Info("tangle info")
+	require.Equal(t, []string{"gossip debug"}, messages(logs))
+
+	ResetComponentLevel("Gossip")
+	gossipLog.Info("gossip info")
+	gossipLog.Warn("gossip warn")
+	require.Equal(t, []string{"gossip warn"}, messages(logs))
+
+	componentLevels, err := ParseComponentLevels(map[string]string{"Tangle": "error"})
+	require.NoError(t, err)
+	SetComponentLevels(componentLevels)
+	tangleLog.Warn("tangle warn")
+	tangleLog.Error("tangle error")
+	gossipLog.Warn("gossip warn")
+	require.Equal(t, []string{"tangle error", "gossip warn"}, messages(logs))
+
+	_, err = ParseComponentLevels(map[string]string{"Tangle": "verbose"})
+	require.Error(t, err)
+}
+
+// returns and clears the messages of the observed logs.
+func messages(logs *observer.ObservedLogs) []string {
+	var msgs []string
+	for _, entry := range logs.TakeAll() {
+		msgs = append(msgs, entry.Message)
+	}
+	return msgs
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logging")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "hornet.log")
+	file, err := newRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		_, err := file.Write([]byte(fmt.Sprintf("entry %d\n", i)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, file.Close())
+
+	for path, expected := range map[string]string{
+		path:        "entry 3\n",
+		path + ".1": "entry 2\n",
+		path + ".2": "entry 1\n",
+	} {
+		content, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(content))
+	}
+
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRotatingFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logging")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sink, _, err := zap.Open(RotationScheme + "://" + filepath.Join(dir, "hornet.log"))
+	require.NoError(t, err)
+
+	_, err = sink.Write([]byte("entry\n"))
+	require.NoError(t, err)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "hornet.log"))
+	require.NoError(t, err)
+	require.Equal(t, "entry\n", string(content))
+
+	_, _, err = zap.Open(RotationScheme + ":")
+	require.Error(t, err)
+}
@@ -0,0 +1,179 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// RotationScheme is the scheme of the output paths of the logger which are written to rotated files,
+	// e.g. "rotate:hornet.log" or "rotate:///var/log/hornet.log".
+	RotationScheme = "rotate"
+
+	megabyte = 1024 * 1024
+)
+
+var (
+	// ErrInvalidRotationPath is returned if the output path of a rotated file is empty.
+	ErrInvalidRotationPath = errors.New("invalid rotation path")
+
+	// the size of a log file in bytes at which it is rotated.
+	rotationMaxSize int64 = 100 * megabyte
+	// the number of rotated log files which are kept.
+	rotationMaxBackups = 5
+	rotationLock       sync.Mutex
+)
+
+func init() {
+	if err := zap.RegisterSink(RotationScheme, newRotatingFileSink); err != nil {
+		panic(err)
+	}
+}
+
+// ConfigureRotation sets the size in megabytes at which log files are rotated and the number of rotated files which are kept.
+// It has to be called before the global logger is initialized.
+func ConfigureRotation(maxSizeMegabytes int, maxBackups int) {
+	rotationLock.Lock()
+	defer rotationLock.Unlock()
+
+	if maxSizeMegabytes > 0 {
+		rotationMaxSize = int64(maxSizeMegabytes) * megabyte
+	}
+	if maxBackups >= 0 {
+		rotationMaxBackups = maxBackups
+	}
+}
+
+func newRotatingFileSink(u *url.URL) (zap.Sink, error) {
+	// "rotate:hornet.log" is an opaque URL, "rotate:///var/log/hornet.log" and "rotate://hornet.log" are not
+	path := u.Opaque
+	if path == "" {
+		path = u.Host + u.Path
+	}
+	if path == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRotationPath, u.String())
+	}
+
+	rotationLock.Lock()
+	maxSize, maxBackups := rotationMaxSize, rotationMaxBackups
+	rotationLock.Unlock()
+
+	return newRotatingFile(filepath.Clean(path), maxSize, maxBackups)
+}
+
+// rotatingFile is a log file which is renamed to "<path>.1" once it reached its maximum size.
+// Older rotated files are shifted to "<path>.2" and so on, files beyond the maximum number of backups are removed.
+type rotatingFile struct {
+	sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func newRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// opens the log file for appending.
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// closes the log file, shifts the rotated files and opens a new log file.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	backupPath := func(index int) string {
+		return fmt.Sprintf("%s.%d", r.path, index)
+	}
+
+	if r.maxBackups == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return r.open()
+	}
+
+	if err := os.Remove(backupPath(r.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backupPath(i), backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := os.Rename(r.path, backupPath(1)); err != nil {
+		return err
+	}
+
+	return r.open()
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	// a single entry which is bigger than the maximum size is written into an own file
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) Sync() error {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.file.Sync()
+}
+
+func (r *rotatingFile) Close() error {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.file.Close()
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// the upper bounds of the request duration histogram buckets of the API routes.
+	apiRequestDurationBounds = []time.Duration{
+		5 * time.Millisecond,
+		10 * time.Millisecond,
+		25 * time.Millisecond,
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+		250 * time.Millisecond,
+		500 * time.Millisecond,
+		1 * time.Second,
+		2500 * time.Millisecond,
+		5 * time.Second,
+		10 * time.Second,
+		30 * time.Second,
+	}
+
+	SharedAPIMetrics = NewAPIMetrics()
+)
+
+// APIMetrics aggregates the requests of the API routes.
+type APIMetrics struct {
+	sync.RWMutex
+	routes map[string]*apiRouteMetrics
+}
+
+type apiRouteMetrics struct {
+	sync.Mutex
+	durations   *LatencyHistogram
+	statusCodes map[int]uint64
+}
+
+// APIRouteMetrics are the metrics of the requests of an API route.
+type APIRouteMetrics struct {
+	// The name of the route.
+	Route string
+	// The durations of the requests of the route over the last minutes.
+	Durations *LatencyHistogramSnapshot
+	// The number of requests per response status code since the start of the node.
+	StatusCodes map[int]uint64
+}
+
+// NewAPIMetrics creates a new APIMetrics instance.
+func NewAPIMetrics() *APIMetrics {
+	return &APIMetrics{routes: make(map[string]*apiRouteMetrics)}
+}
+
+func (m *APIMetrics) routeMetrics(route string) *apiRouteMetrics {
+	m.RLock()
+	routeMetrics, exists := m.routes[route]
+	m.RUnlock()
+	if exists {
+		return routeMetrics
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	if routeMetrics, exists = m.routes[route]; !exists {
+		routeMetrics = &apiRouteMetrics{
+			durations:   NewLatencyHistogram(apiRequestDurationBounds, latencyHistogramWindow, latencyHistogramSlots),
+			statusCodes: make(map[int]uint64),
+		}
+		m.routes[route] = routeMetrics
+	}
+	return routeMetrics
+}
+
+// Observe adds a request of the given route, which was answered with the given status code after the given duration.
+// A negative duration only counts the status code, e.g. for long-lived connections.
+func (m *APIMetrics) Observe(route string, statusCode int, duration time.Duration) {
+	routeMetrics := m.routeMetrics(route)
+
+	if duration >= 0 {
+		routeMetrics.durations.Observe(duration)
+	}
+
+	routeMetrics.Lock()
+	routeMetrics.statusCodes[statusCode]++
+	routeMetrics.Unlock()
+}
+
+// Snapshot returns the metrics of all routes which received requests, sorted by route.
+func (m *APIMetrics) Snapshot() []*APIRouteMetrics {
+	m.RLock()
+	defer m.RUnlock()
+
+	result := make([]*APIRouteMetrics, 0, len(m.routes))
+	for route, routeMetrics := range m.routes {
+		statusCodes := make(map[int]uint64)
+
+		routeMetrics.Lock()
+		for statusCode, count := range routeMetrics.statusCodes {
+			statusCodes[statusCode] = count
+		}
+		routeMetrics.Unlock()
+
+		result = append(result, &APIRouteMetrics{
+			Route:       route,
+			Durations:   routeMetrics.durations.Snapshot(),
+			StatusCodes: statusCodes,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Route < result[j].Route })
+	return result
+}
@@ -46,6 +46,18 @@ type ServerMetrics struct {
 	SentHeartbeats atomic.Uint32
 	// The number of dropped messages.
 	DroppedMessages atomic.Uint32
+	// The number of received messages which were dropped because they exceeded a rate limit.
+	RateLimitedMessages atomic.Uint32
+	// The number of transaction broadcasts which were suppressed, because the transaction was already broadcasted recently.
+	SuppressedBroadcasts atomic.Uint32
+	// The number of peers a broadcasted transaction was not sent to, because it was received from them.
+	ExcludedBroadcastPeers atomic.Uint32
+	// The number of transaction requests which were sent again because they were not answered in time.
+	RetriedTransactionRequests atomic.Uint32
+	// The number of transaction requests which were discarded without being answered.
+	DiscardedTransactionRequests atomic.Uint32
+	// The number of future cone solidifications which were dropped because the queue was full.
+	DroppedFutureConeSolidifications atomic.Uint32
 	// The number of sent spam transactions.
 	SentSpamTransactions atomic.Uint32
 	// The number of validated bundles.
@@ -56,4 +68,8 @@ type ServerMetrics struct {
 	TipsNonLazy atomic.Uint32
 	// The number of semi-lazy tips.
 	TipsSemiLazy atomic.Uint32
+	// The number of root snapshot index lookups which were answered by the cache.
+	RootSnapshotIndexesCacheHits atomic.Uint32
+	// The number of root snapshot index lookups which were not answered by the cache.
+	RootSnapshotIndexesCacheMisses atomic.Uint32
 }
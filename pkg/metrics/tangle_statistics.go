@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// the time span covered by the rolling latency histograms.
+	latencyHistogramWindow = 10 * time.Minute
+	// the number of slots the window of the rolling latency histograms is split into.
+	latencyHistogramSlots = 20
+)
+
+var (
+	// the upper bounds of the latency histogram buckets. Observations above the last bound are counted in an overflow bucket.
+	latencyHistogramBounds = []time.Duration{
+		1 * time.Second,
+		2 * time.Second,
+		5 * time.Second,
+		10 * time.Second,
+		20 * time.Second,
+		30 * time.Second,
+		1 * time.Minute,
+		2 * time.Minute,
+		5 * time.Minute,
+		10 * time.Minute,
+	}
+
+	SharedTangleStatistics = NewTangleStatistics()
+)
+
+// TangleStatistics aggregates latency statistics of the transactions in the tangle.
+type TangleStatistics struct {
+	// The time between the arrival of tail transactions at the node and their solidification.
+	TimeToSolid *LatencyHistogram
+	// The time between the arrival of tail transactions at the node and the confirmation of the milestone which referenced them.
+	TimeToConfirmation *LatencyHistogram
+}
+
+// NewTangleStatistics creates a new TangleStatistics instance.
+func NewTangleStatistics() *TangleStatistics {
+	return &TangleStatistics{
+		TimeToSolid:        NewLatencyHistogram(latencyHistogramBounds, latencyHistogramWindow, latencyHistogramSlots),
+		TimeToConfirmation: NewLatencyHistogram(latencyHistogramBounds, latencyHistogramWindow, latencyHistogramSlots),
+	}
+}
+
+type latencyHistogramSlot struct {
+	epoch  int64
+	counts []uint64
+	sum    time.Duration
+}
+
+// LatencyHistogram is a histogram of latencies that only contains the observations of the last window.
+// The window is split into slots, which are reset once they are reused.
+type LatencyHistogram struct {
+	sync.Mutex
+	bounds       []time.Duration
+	slotDuration time.Duration
+	slots        []*latencyHistogramSlot
+}
+
+// NewLatencyHistogram creates a new LatencyHistogram with the given bucket bounds, window and number of slots.
+func NewLatencyHistogram(bounds []time.Duration, window time.Duration, slotCount int) *LatencyHistogram {
+	h := &LatencyHistogram{
+		bounds:       bounds,
+		slotDuration: window / time.Duration(slotCount),
+		slots:        make([]*latencyHistogramSlot, slotCount),
+	}
+	for i := range h.slots {
+		h.slots[i] = &latencyHistogramSlot{epoch: -1, counts: make([]uint64, len(bounds)+1)}
+	}
+	return h
+}
+
+func (h *LatencyHistogram) currentEpoch(now time.Time) int64 {
+	return now.UnixNano() / int64(h.slotDuration)
+}
+
+// Observe adds the given latency to the histogram.
+func (h *LatencyHistogram) Observe(latency time.Duration) {
+	if latency < 0 {
+		latency = 0
+	}
+
+	bucket := len(h.bounds)
+	for i, bound := range h.bounds {
+		if latency <= bound {
+			bucket = i
+			break
+		}
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	epoch := h.currentEpoch(time.Now())
+	slot := h.slots[epoch%int64(len(h.slots))]
+	if slot.epoch != epoch {
+		// the slot is reused => drop the outdated observations
+		slot.epoch = epoch
+		slot.sum = 0
+		for i := range slot.counts {
+			slot.counts[i] = 0
+		}
+	}
+	slot.counts[bucket]++
+	slot.sum += latency
+}
+
+// LatencyBucket is a bucket of a LatencyHistogramSnapshot.
+type LatencyBucket struct {
+	// The upper bound of the bucket. Zero for the overflow bucket.
+	UpperBound time.Duration
+	// The number of observations in the bucket.
+	Count uint64
+}
+
+// LatencyHistogramSnapshot is the state of a LatencyHistogram at a certain point in time.
+type LatencyHistogramSnapshot struct {
+	Buckets []LatencyBucket
+	// The total number of observations.
+	Count uint64
+	// The sum of all observed latencies.
+	Sum time.Duration
+}
+
+// Mean returns the mean of the observed latencies.
+func (s *LatencyHistogramSnapshot) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// Quantile returns the upper bound of the bucket which contains the given quantile (0.0-1.0).
+// If the quantile lies in the overflow bucket, the largest bound is returned.
+func (s *LatencyHistogramSnapshot) Quantile(q float64) time.Duration {
+	if s.Count == 0 || len(s.Buckets) == 0 {
+		return 0
+	}
+
+	rank := uint64(q * float64(s.Count))
+	var seen uint64
+	for _, bucket := range s.Buckets {
+		seen += bucket.Count
+		if seen > rank && bucket.UpperBound != 0 {
+			return bucket.UpperBound
+		}
+	}
+
+	if len(s.Buckets) > 1 {
+		return s.Buckets[len(s.Buckets)-2].UpperBound
+	}
+	return 0
+}
+
+// Snapshot returns the aggregated observations of the current window.
+func (h *LatencyHistogram) Snapshot() *LatencyHistogramSnapshot {
+	snapshot := &LatencyHistogramSnapshot{Buckets: make([]LatencyBucket, len(h.bounds)+1)}
+	for i, bound := range h.bounds {
+		snapshot.Buckets[i].UpperBound = bound
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	oldestEpoch := h.currentEpoch(time.Now()) - int64(len(h.slots)) + 1
+	for _, slot := range h.slots {
+		if slot.epoch < oldestEpoch {
+			// outdated slot
+			continue
+		}
+		for i, count := range slot.counts {
+			snapshot.Buckets[i].Count += count
+			snapshot.Count += count
+		}
+		snapshot.Sum += slot.sum
+	}
+
+	return snapshot
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	h := NewLatencyHistogram([]time.Duration{time.Second, 2 * time.Second, 5 * time.Second}, time.Minute, 10)
+
+	empty := h.Snapshot()
+	assert.Zero(t, empty.Count)
+	assert.Zero(t, empty.Mean())
+	assert.Zero(t, empty.Quantile(0.5))
+
+	for _, latency := range []time.Duration{
+		500 * time.Millisecond,
+		time.Second,  // the bounds are inclusive
+		-time.Second, // negative latencies are counted as zero
+		1500 * time.Millisecond,
+		4 * time.Second,
+		10 * time.Second, // overflow
+	} {
+		h.Observe(latency)
+	}
+
+	snapshot := h.Snapshot()
+	require.Len(t, snapshot.Buckets, 4)
+
+	var counts []uint64
+	for _, bucket := range snapshot.Buckets {
+		counts = append(counts, bucket.Count)
+	}
+	assert.Equal(t, []uint64{3, 1, 1, 1}, counts)
+	assert.Zero(t, snapshot.Buckets[3].UpperBound)
+
+	assert.Equal(t, uint64(6), snapshot.Count)
+	assert.Equal(t, 17*time.Second, snapshot.Sum)
+	assert.Equal(t, 17*time.Second/6, snapshot.Mean())
+
+	assert.Equal(t, 2*time.Second, snapshot.Quantile(0.5))
+	// the quantile in the overflow bucket is capped at the largest bound
+	assert.Equal(t, 5*time.Second, snapshot.Quantile(0.95))
+}
+
+func TestLatencyHistogramWindow(t *testing.T) {
+	h := NewLatencyHistogram([]time.Duration{time.Second}, 100*time.Millisecond, 2)
+
+	h.Observe(time.Second)
+	assert.Equal(t, uint64(1), h.Snapshot().Count)
+
+	// the observation drops out of the window once its slot is outdated
+	time.Sleep(200 * time.Millisecond)
+	assert.Zero(t, h.Snapshot().Count)
+
+	h.Observe(time.Second)
+	assert.Equal(t, uint64(1), h.Snapshot().Count)
+}
@@ -36,6 +36,10 @@ var (
 	ErrNoTipsGiven = errors.New("no tips given")
 	// ErrNetworkBootstrapped is returned when the flag for bootstrap network was given, but a state file already exists.
 	ErrNetworkBootstrapped = errors.New("network already bootstrapped")
+	// ErrMilestoneKeyNotValid is returned when the Merkle tree of the coordinator is not valid for the next milestone index.
+	ErrMilestoneKeyNotValid = errors.New("coordinator address is not valid for the milestone index")
+	// ErrUnsupportedMerkleTreeHashFunc is returned when the configured Merkle tree hash function is not supported or not available.
+	ErrUnsupportedMerkleTreeHashFunc = errors.New("unsupported merkle tree hash func")
 )
 
 // CoordinatorEvents are the events issued by the coordinator.
@@ -71,8 +75,8 @@ type Coordinator struct {
 }
 
 // MilestoneMerkleTreeHashFuncWithName maps the passed name to one of the supported crypto.Hash hashing functions.
-// Also verifies that the available function is available or else panics.
-func MilestoneMerkleTreeHashFuncWithName(name string) crypto.Hash {
+// Also verifies that the function is available or else returns an error.
+func MilestoneMerkleTreeHashFuncWithName(name string) (crypto.Hash, error) {
 	//TODO: golang 1.15 will include a String() method to get the string from the crypto.Hash, so we could iterate over them instead
 	var hashFunc crypto.Hash
 	switch strings.ToLower(name) {
@@ -85,13 +89,13 @@ func MilestoneMerkleTreeHashFuncWithName(name string) crypto.Hash {
 	case "blake2s-256":
 		hashFunc = crypto.BLAKE2s_256
 	default:
-		panic(fmt.Sprintf("Unsupported merkle tree hash func '%s'", name))
+		return 0, fmt.Errorf("%w: '%s'", ErrUnsupportedMerkleTreeHashFunc, name)
 	}
 
 	if !hashFunc.Available() {
-		panic(fmt.Sprintf("Merkle tree hash func '%s' not available. Please check the package imports", name))
+		return 0, fmt.Errorf("%w: '%s' not available, please check the package imports", ErrUnsupportedMerkleTreeHashFunc, name)
 	}
-	return hashFunc
+	return hashFunc, nil
 }
 
 // New creates a new coordinator instance.
@@ -159,7 +163,9 @@ func (coo *Coordinator) InitState(bootstrap bool, startIndex milestone.Index) er
 
 		if startIndex == 1 {
 			// if we bootstrap a network, NullHash has to be set as a solid entry point
+			tangle.WriteLockSolidEntryPoints()
 			tangle.SolidEntryPointsAdd(hornet.NullHashBytes, startIndex)
+			tangle.WriteUnlockSolidEntryPoints()
 		}
 
 		latestMilestoneHash := hornet.NullHashBytes
@@ -211,6 +217,11 @@ func (coo *Coordinator) InitState(bootstrap bool, startIndex milestone.Index) er
 // createAndSendMilestone creates a milestone, sends it to the network and stores a new coordinator state file.
 func (coo *Coordinator) createAndSendMilestone(trunkHash hornet.Hash, branchHash hornet.Hash, newMilestoneIndex milestone.Index) error {
 
+	// the nodes would not accept a milestone signed with a key that is not valid for its index
+	if cooAddress := tangle.GetCoordinatorAddressForMilestoneIndex(newMilestoneIndex); cooAddress == nil || cooAddress.Trytes() != coo.merkleTree.Root {
+		return fmt.Errorf("%w: %s, index %d", ErrMilestoneKeyNotValid, coo.merkleTree.Root, newMilestoneIndex)
+	}
+
 	cachedTxMetas := make(map[string]*tangle.CachedMetadata)
 	cachedBundles := make(map[string]*tangle.CachedBundle)
 
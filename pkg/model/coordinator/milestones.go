@@ -5,7 +5,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/iotaledger/hive.go/batchhasher"
 	"github.com/iotaledger/iota.go/bundle"
 	"github.com/iotaledger/iota.go/consts"
 	"github.com/iotaledger/iota.go/kerl"
@@ -13,6 +12,7 @@ import (
 	"github.com/iotaledger/iota.go/transaction"
 	"github.com/iotaledger/iota.go/trinary"
 
+	"github.com/gohornet/hornet/pkg/compressed"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/pow"
@@ -160,8 +160,7 @@ func createMilestone(seed trinary.Hash, index milestone.Index, securityLvl const
 
 	// validate bundle semantics and signatures
 	if err := bundle.ValidBundle(iotaGoBundle); err != nil {
-		fmt.Println(err.Error())
-		return nil, err
+		return nil, fmt.Errorf("created milestone bundle is invalid: %w", err)
 	}
 
 	return b, nil
@@ -195,8 +194,7 @@ func doPow(tx *transaction.Transaction, mwm int, powHandler *pow.Handler) error
 // transactionHash makes a transaction hash from the given transaction.
 func transactionHash(t *transaction.Transaction) trinary.Hash {
 	trits, _ := transaction.TransactionToTrits(t)
-	hashTrits := batchhasher.CURLP81.Hash(trits)
-	return trinary.MustTritsToTrytes(hashTrits)
+	return compressed.TransactionHash(trits)
 }
 
 // finalizeInsecure sets the bundle hash for all transactions in the bundle.
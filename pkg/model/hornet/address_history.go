@@ -0,0 +1,62 @@
+package hornet
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/iotaledger/hive.go/objectstorage"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+// AddressHistoryEntry records that a transaction touching an address was confirmed by a milestone.
+type AddressHistoryEntry struct {
+	objectstorage.StorableObjectFlags
+	address        Hash
+	milestoneIndex milestone.Index
+	txHash         Hash
+}
+
+func NewAddressHistoryEntry(address Hash, msIndex milestone.Index, txHash Hash) *AddressHistoryEntry {
+	return &AddressHistoryEntry{
+		address:        address,
+		milestoneIndex: msIndex,
+		txHash:         txHash,
+	}
+}
+
+func (a *AddressHistoryEntry) GetAddress() Hash {
+	return a.address
+}
+
+// GetMilestoneIndex returns the index of the milestone which confirmed the transaction.
+func (a *AddressHistoryEntry) GetMilestoneIndex() milestone.Index {
+	return a.milestoneIndex
+}
+
+func (a *AddressHistoryEntry) GetTxHash() Hash {
+	return a.txHash
+}
+
+// ObjectStorage interface
+
+func (a *AddressHistoryEntry) Update(_ objectstorage.StorableObject) {
+	panic(fmt.Sprintf("AddressHistoryEntry should never be updated: %v, TxHash: %v", a.address.Trytes(), a.txHash.Trytes()))
+}
+
+func (a *AddressHistoryEntry) ObjectStorageKey() []byte {
+	key := make([]byte, 49, 102)
+	copy(key, a.address[:49])
+	// big endian, so that the entries of an address are sorted by milestone index in the database
+	key = append(key, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(key[49:53], uint32(a.milestoneIndex))
+	return append(key, a.txHash[:49]...)
+}
+
+func (a *AddressHistoryEntry) ObjectStorageValue() (_ []byte) {
+	return nil
+}
+
+func (a *AddressHistoryEntry) UnmarshalObjectStorageValue(_ []byte) (consumedBytes int, err error) {
+	return 0, nil
+}
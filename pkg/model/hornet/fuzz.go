@@ -0,0 +1,26 @@
+//go:build gofuzz
+// +build gofuzz
+
+package hornet
+
+// FuzzTransactionMetadata is the go-fuzz target for parsing the stored transaction metadata.
+// Build with "go-fuzz-build -func FuzzTransactionMetadata".
+func FuzzTransactionMetadata(data []byte) int {
+	m := NewTransactionMetadata(make(Hash, 49))
+	if _, err := m.UnmarshalObjectStorageValue(data); err != nil {
+		return 0
+	}
+
+	if m.GetBundleHash() == nil {
+		// the additional tx info is added from the transaction afterwards
+		return 1
+	}
+
+	// metadata that was parsed successfully has to survive a round trip
+	restored := NewTransactionMetadata(m.GetTxHash())
+	if _, err := restored.UnmarshalObjectStorageValue(m.ObjectStorageValue()); err != nil {
+		panic(err)
+	}
+
+	return 1
+}
@@ -1,14 +1,43 @@
 package hornet
 
 import (
-	"fmt"
+	"bytes"
+	"encoding/hex"
 
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/iota.go/address"
+	"github.com/iotaledger/iota.go/guards"
 	"github.com/iotaledger/iota.go/trinary"
 )
 
+const (
+	// HashBinarySize is the size of a binary transaction, bundle, milestone or address hash.
+	HashBinarySize = 49
+	// HashTrytesSize is the size of a transaction, bundle, milestone or address hash in trytes.
+	HashTrytesSize = 81
+	// TagBinarySize is the size of a binary tag.
+	TagBinarySize = 17
+	// TagTrytesSize is the size of a tag in trytes.
+	TagTrytesSize = 27
+)
+
 var (
 	// NullHashBytes is the binary hash of the genesis transaction.
-	NullHashBytes = make(Hash, 49)
+	NullHashBytes = make(Hash, HashBinarySize)
+
+	// ErrInvalidHashLength is returned if a binary hash has an unexpected length.
+	ErrInvalidHashLength = errors.New("invalid hash length")
+	// ErrInvalidHashTrytes is returned if trytes are not a valid hash.
+	ErrInvalidHashTrytes = errors.New("invalid hash trytes")
+	// ErrInvalidHashHex is returned if a hex string is not a valid hash.
+	ErrInvalidHashHex = errors.New("invalid hash hex")
+
+	// trytesSizes maps the expected binary hash sizes to their size in trytes.
+	trytesSizes = map[int]int{
+		HashBinarySize: HashTrytesSize,
+		TagBinarySize:  TagTrytesSize,
+	}
 )
 
 // Hash is the binary representation of a trinary Hash.
@@ -18,6 +47,37 @@ func HashFromAddressTrytes(trytes trinary.Trytes) Hash {
 	return trinary.MustTrytesToBytes(trytes[:81])[:49]
 }
 
+// AddressFromTrytes converts the given address trytes, with or without checksum, to a binary Hash.
+// The address and, if given, the checksum are validated.
+func AddressFromTrytes(trytes trinary.Trytes) (Hash, error) {
+	if err := address.ValidAddress(trytes); err != nil {
+		return nil, errors.Wrapf(err, "%s", trytes)
+	}
+	return HashFromAddressTrytes(trytes), nil
+}
+
+// AddressToTrytes converts the given binary address to its trinary representation, optionally with checksum.
+func AddressToTrytes(addr Hash, withChecksum bool) (trinary.Trytes, error) {
+	if len(addr) != HashBinarySize {
+		return "", errors.Wrapf(ErrInvalidHashLength, "%d bytes", len(addr))
+	}
+
+	addrTrytes, err := addr.ToTrytes()
+	if err != nil {
+		return "", err
+	}
+
+	if !withChecksum {
+		return addrTrytes, nil
+	}
+
+	checksum, err := address.Checksum(addrTrytes)
+	if err != nil {
+		return "", err
+	}
+	return addrTrytes + checksum, nil
+}
+
 func HashFromHashTrytes(trytes trinary.Trytes) Hash {
 	return trinary.MustTrytesToBytes(trytes[:81])[:49]
 }
@@ -26,15 +86,55 @@ func HashFromTagTrytes(trytes trinary.Trytes) Hash {
 	return trinary.MustTrytesToBytes(trytes[:27])[:17]
 }
 
+// ToTrytes converts the binary Hash to its trinary representation.
+// It returns an error if the Hash has none of the expected lengths.
+func (h Hash) ToTrytes() (trinary.Trytes, error) {
+	trytesSize, ok := trytesSizes[len(h)]
+	if !ok {
+		return "", errors.Wrapf(ErrInvalidHashLength, "%d bytes", len(h))
+	}
+	return trinary.BytesToTrytes(h, trytesSize)
+}
+
+// HashFromTrytes converts the given transaction, bundle, milestone or address hash trytes to a binary Hash.
+// Unlike HashFromHashTrytes, the trytes must be exactly HashTrytesSize long and are validated.
+func HashFromTrytes(trytes trinary.Hash) (Hash, error) {
+	if !guards.IsTrytesOfExactLength(trytes, HashTrytesSize) {
+		return nil, errors.Wrapf(ErrInvalidHashTrytes, "%s", trytes)
+	}
+	hash, err := trinary.TrytesToBytes(trytes)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidHashTrytes, "%s: %v", trytes, err)
+	}
+	return hash[:HashBinarySize], nil
+}
+
+// HashFromHexString converts the given hex encoded binary hash to a Hash.
+// The decoded Hash must have one of the expected lengths.
+func HashFromHexString(s string) (Hash, error) {
+	hash, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidHashHex, "%s: %v", s, err)
+	}
+	if _, ok := trytesSizes[len(hash)]; !ok {
+		return nil, errors.Wrapf(ErrInvalidHashLength, "%d bytes", len(hash))
+	}
+	return hash, nil
+}
+
+// Hex returns the hex encoding of the binary Hash.
+func (h Hash) Hex() string {
+	return hex.EncodeToString(h)
+}
+
 // Trytes converts the binary Hash to its trinary representation.
+// It panics if the Hash has none of the expected lengths, use ToTrytes for data of unknown integrity.
 func (h Hash) Trytes() trinary.Trytes {
-	if len(h) == 49 {
-		return trinary.MustBytesToTrytes(h, 81)
+	trytes, err := h.ToTrytes()
+	if err != nil {
+		panic(err)
 	}
-	if len(h) == 17 {
-		return trinary.MustBytesToTrytes(h, 27)
-	}
-	panic(fmt.Sprintf("Unknown hash length (%d)", len(h)))
+	return trytes
 }
 
 // Hashes is a slice of Hash.
@@ -48,3 +148,41 @@ func (h Hashes) Trytes() []trinary.Trytes {
 	}
 	return results
 }
+
+// ToTrytes converts the binary Hashes to their trinary representation.
+// It returns an error if any Hash has none of the expected lengths.
+func (h Hashes) ToTrytes() ([]trinary.Trytes, error) {
+	results := make([]trinary.Trytes, 0, len(h))
+	for _, hash := range h {
+		trytes, err := hash.ToTrytes()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, trytes)
+	}
+	return results, nil
+}
+
+// Unique returns the Hashes without duplicates, in the order of their first occurrence.
+func (h Hashes) Unique() Hashes {
+	seen := make(map[string]struct{}, len(h))
+	results := make(Hashes, 0, len(h))
+	for _, hash := range h {
+		if _, exists := seen[string(hash)]; exists {
+			continue
+		}
+		seen[string(hash)] = struct{}{}
+		results = append(results, hash)
+	}
+	return results
+}
+
+// Contains returns whether the given hash is part of the Hashes.
+func (h Hashes) Contains(hash Hash) bool {
+	for _, entry := range h {
+		if bytes.Equal(entry, hash) {
+			return true
+		}
+	}
+	return false
+}
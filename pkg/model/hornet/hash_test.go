@@ -0,0 +1,100 @@
+package hornet
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashToTrytes(t *testing.T) {
+	trytes, err := NullHashBytes.ToTrytes()
+	require.NoError(t, err)
+	assert.Len(t, trytes, HashTrytesSize)
+
+	trytes, err = make(Hash, TagBinarySize).ToTrytes()
+	require.NoError(t, err)
+	assert.Len(t, trytes, TagTrytesSize)
+
+	_, err = make(Hash, HashBinarySize-1).ToTrytes()
+	assert.True(t, errors.Is(err, ErrInvalidHashLength))
+
+	_, err = Hashes{NullHashBytes, Hash{}}.ToTrytes()
+	assert.True(t, errors.Is(err, ErrInvalidHashLength))
+
+	assert.Panics(t, func() { _ = Hash{}.Trytes() })
+}
+
+func TestMessageIDConversion(t *testing.T) {
+	hash := HashFromHashTrytes("PZQAFJPX9ORMHMGTZHWXJSOTCLFPZLKIAKOATNCOSITJPXIVXOQKGQEXAP9GWDEGQGDKQVPLGRVHAA999")
+
+	messageID, err := MessageIDFromHash(hash)
+	require.NoError(t, err)
+	assert.Equal(t, hash, messageID.Hash())
+
+	fromTrytes, err := MessageIDFromTrytes(messageID.Trytes())
+	require.NoError(t, err)
+	assert.Equal(t, messageID, fromTrytes)
+
+	_, err = MessageIDFromHash(hash[:HashBinarySize-1])
+	assert.True(t, errors.Is(err, ErrInvalidHashLength))
+
+	_, err = MilestoneIDFromTrytes("999")
+	assert.True(t, errors.Is(err, ErrInvalidHashTrytes))
+}
+
+func TestHashParsing(t *testing.T) {
+	trytes := NullHashBytes.Trytes()
+
+	hash, err := HashFromTrytes(trytes)
+	require.NoError(t, err)
+	assert.Equal(t, NullHashBytes, hash)
+
+	_, err = HashFromTrytes(trytes[:HashTrytesSize-1])
+	assert.True(t, errors.Is(err, ErrInvalidHashTrytes))
+
+	_, err = HashFromTrytes(trytes[:HashTrytesSize-1] + "a")
+	assert.True(t, errors.Is(err, ErrInvalidHashTrytes))
+
+	hash, err = HashFromHexString(NullHashBytes.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, NullHashBytes, hash)
+
+	_, err = HashFromHexString("zz")
+	assert.True(t, errors.Is(err, ErrInvalidHashHex))
+
+	_, err = HashFromHexString("00ff")
+	assert.True(t, errors.Is(err, ErrInvalidHashLength))
+}
+
+func TestHashesUnique(t *testing.T) {
+	a, b := randomHash(), randomHash()
+
+	unique := Hashes{a, b, a, b, a}.Unique()
+	assert.Equal(t, Hashes{a, b}, unique)
+	assert.True(t, unique.Contains(b))
+	assert.False(t, unique.Contains(NullHashBytes))
+}
+
+func TestAddressTrytes(t *testing.T) {
+	addrTrytes := "PZQAFJPX9ORMHMGTZHWXJSOTCLFPZLKIAKOATNCOSITJPXIVXOQKGQEXAP9GWDEGQGDKQVPLGRVHAA999"
+
+	addr, err := AddressFromTrytes(addrTrytes)
+	require.NoError(t, err)
+
+	withChecksum, err := AddressToTrytes(addr, true)
+	require.NoError(t, err)
+	assert.Len(t, withChecksum, HashTrytesSize+9)
+
+	fromChecksum, err := AddressFromTrytes(withChecksum)
+	require.NoError(t, err)
+	assert.Equal(t, addr, fromChecksum)
+
+	withoutChecksum, err := AddressToTrytes(addr, false)
+	require.NoError(t, err)
+	assert.Equal(t, addrTrytes, withoutChecksum)
+
+	_, err = AddressFromTrytes(withChecksum[:HashTrytesSize] + "999999999")
+	assert.Error(t, err)
+}
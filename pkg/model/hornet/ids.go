@@ -0,0 +1,101 @@
+package hornet
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/iota.go/trinary"
+)
+
+// MessageID is the hash of a single transaction in the tangle.
+type MessageID [HashBinarySize]byte
+
+// TransactionID is the bundle hash of a value transfer, which is shared by all transactions of the bundle.
+type TransactionID [HashBinarySize]byte
+
+// MilestoneID is the hash of the tail transaction of a milestone bundle.
+type MilestoneID [HashBinarySize]byte
+
+// hashArrayFromHash copies the given binary hash into an array.
+func hashArrayFromHash(hash Hash) (array [HashBinarySize]byte, err error) {
+	if len(hash) != HashBinarySize {
+		return array, errors.Wrapf(ErrInvalidHashLength, "%d bytes", len(hash))
+	}
+	copy(array[:], hash)
+	return array, nil
+}
+
+// hashArrayFromTrytes converts the given hash trytes into an array.
+func hashArrayFromTrytes(trytes trinary.Hash) (array [HashBinarySize]byte, err error) {
+	hash, err := HashFromTrytes(trytes)
+	if err != nil {
+		return array, err
+	}
+	copy(array[:], hash)
+	return array, nil
+}
+
+// MessageIDFromHash creates a MessageID from the given binary hash.
+func MessageIDFromHash(hash Hash) (MessageID, error) {
+	array, err := hashArrayFromHash(hash)
+	return MessageID(array), err
+}
+
+// MessageIDFromTrytes creates a MessageID from the given transaction hash trytes.
+func MessageIDFromTrytes(trytes trinary.Hash) (MessageID, error) {
+	array, err := hashArrayFromTrytes(trytes)
+	return MessageID(array), err
+}
+
+// Hash returns a copy of the MessageID as binary Hash.
+func (id MessageID) Hash() Hash {
+	return append(Hash{}, id[:]...)
+}
+
+// Trytes converts the MessageID to its trinary representation.
+func (id MessageID) Trytes() trinary.Hash {
+	return trinary.MustBytesToTrytes(id[:], HashTrytesSize)
+}
+
+// TransactionIDFromHash creates a TransactionID from the given binary bundle hash.
+func TransactionIDFromHash(hash Hash) (TransactionID, error) {
+	array, err := hashArrayFromHash(hash)
+	return TransactionID(array), err
+}
+
+// TransactionIDFromTrytes creates a TransactionID from the given bundle hash trytes.
+func TransactionIDFromTrytes(trytes trinary.Hash) (TransactionID, error) {
+	array, err := hashArrayFromTrytes(trytes)
+	return TransactionID(array), err
+}
+
+// Hash returns a copy of the TransactionID as binary Hash.
+func (id TransactionID) Hash() Hash {
+	return append(Hash{}, id[:]...)
+}
+
+// Trytes converts the TransactionID to its trinary representation.
+func (id TransactionID) Trytes() trinary.Hash {
+	return trinary.MustBytesToTrytes(id[:], HashTrytesSize)
+}
+
+// MilestoneIDFromHash creates a MilestoneID from the given binary milestone hash.
+func MilestoneIDFromHash(hash Hash) (MilestoneID, error) {
+	array, err := hashArrayFromHash(hash)
+	return MilestoneID(array), err
+}
+
+// MilestoneIDFromTrytes creates a MilestoneID from the given milestone hash trytes.
+func MilestoneIDFromTrytes(trytes trinary.Hash) (MilestoneID, error) {
+	array, err := hashArrayFromTrytes(trytes)
+	return MilestoneID(array), err
+}
+
+// Hash returns a copy of the MilestoneID as binary Hash.
+func (id MilestoneID) Hash() Hash {
+	return append(Hash{}, id[:]...)
+}
+
+// Trytes converts the MilestoneID to its trinary representation.
+func (id MilestoneID) Trytes() trinary.Hash {
+	return trinary.MustBytesToTrytes(id[:], HashTrytesSize)
+}
@@ -41,6 +41,16 @@ func (s *SolidEntryPoints) Index(txHash Hash) (milestone.Index, bool) {
 	return index, exists
 }
 
+// ForEach calls the consumer for every solid entry point in the order they were added.
+// The iteration is stopped if the consumer returns false.
+func (s *SolidEntryPoints) ForEach(consumer func(txHash Hash, milestoneIndex milestone.Index) bool) {
+	for _, txHash := range s.entryPointsSlice {
+		if !consumer(txHash, s.entryPointsMap[string(txHash)]) {
+			return
+		}
+	}
+}
+
 func (s *SolidEntryPoints) Add(txHash Hash, milestoneIndex milestone.Index) {
 	if _, exists := s.entryPointsMap[string(txHash)]; !exists {
 		s.entryPointsMap[string(txHash)] = milestoneIndex
@@ -87,6 +87,11 @@ func (tx *Transaction) GetBranchHash() Hash {
 	return tx.branchHash
 }
 
+// GetParents returns the hashes of the transactions referenced by the transaction (trunk first, then branch).
+func (tx *Transaction) GetParents() Hashes {
+	return Hashes{tx.GetTrunkHash(), tx.GetBranchHash()}
+}
+
 func (tx *Transaction) GetBundleHash() Hash {
 	tx.bundleHashOnce.Do(func() {
 		tx.bundleHash = HashFromHashTrytes(tx.Tx.Bundle)
@@ -2,6 +2,7 @@ package hornet
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"time"
 
@@ -19,20 +20,103 @@ const (
 	TransactionMetadataIsHead      = 3
 	TransactionMetadataIsTail      = 4
 	TransactionMetadataIsValue     = 5
+	TransactionMetadataIncluded    = 6
+	TransactionMetadataNoTx        = 7
+)
+
+const (
+	// the metadata bitmask is full, further flags are stored in the extended metadata bitmask
+	TransactionMetadataExtIsMilestone    = 0
+	TransactionMetadataExtUnsolidifiable = 1
+)
+
+// LedgerInclusionState describes how a transaction referenced by a milestone was handled by the white-flag confirmation.
+type LedgerInclusionState string
+
+const (
+	// LedgerInclusionStateNotReferenced is the state of transactions which were not referenced by a milestone yet.
+	LedgerInclusionStateNotReferenced LedgerInclusionState = "notReferenced"
+	// LedgerInclusionStateNoTransaction is the state of referenced transactions which did not mutate the ledger (zero value bundles).
+	LedgerInclusionStateNoTransaction LedgerInclusionState = "noTransaction"
+	// LedgerInclusionStateIncluded is the state of referenced transactions whose ledger mutations were applied.
+	LedgerInclusionStateIncluded LedgerInclusionState = "included"
+	// LedgerInclusionStateConflicting is the state of referenced transactions which were ignored because they conflicted with the ledger.
+	LedgerInclusionStateConflicting LedgerInclusionState = "conflicting"
+)
+
+// ConflictReason describes why a referenced transaction was marked as conflicting by the white-flag confirmation.
+type ConflictReason uint8
+
+const (
+	// ConflictReasonNone is set if the transaction is not conflicting.
+	ConflictReasonNone ConflictReason = 0
+	// ConflictReasonInsufficientBalance is set if an input address of the bundle had an insufficient balance,
+	// e.g. because the funds were already spent by another bundle.
+	ConflictReasonInsufficientBalance ConflictReason = 1
+	// ConflictReasonBalanceExceedsTotalSupply is set if the balance of an address would exceed the total supply.
+	ConflictReasonBalanceExceedsTotalSupply ConflictReason = 2
+)
+
+const (
+	// MinParentsCount is the minimum amount of parents a transaction references.
+	MinParentsCount = 1
+	// MaxParentsCount is the maximum amount of parents a transaction references.
+	MaxParentsCount = 8
+)
+
+// MetadataChange describes which state of a TransactionMetadata was changed by a setter.
+type MetadataChange byte
+
+const (
+	// MetadataChangeSolid is reported when the transaction became solid.
+	MetadataChangeSolid MetadataChange = iota
+	// MetadataChangeConfirmed is reported when the transaction was confirmed (referenced) by a milestone.
+	MetadataChangeConfirmed
+	// MetadataChangeConflicting is reported when the transaction was marked as conflicting.
+	MetadataChangeConflicting
+	// MetadataChangeRootSnapshotIndexes is reported when the youngest or oldest root snapshot index changed.
+	MetadataChangeRootSnapshotIndexes
+)
+
+// MetadataChangedFunc is called after a setter flipped a state of a TransactionMetadata.
+// It is called without holding the lock of the metadata.
+type MetadataChangedFunc func(metadata *TransactionMetadata, change MetadataChange)
+
+var metadataChangedFunc MetadataChangedFunc
+
+// SetMetadataChangedFunc sets the function that is called whenever a setter flipped a state of a TransactionMetadata.
+// The tangle package uses it to trigger its metadata events (the hornet package can't import tangle).
+func SetMetadataChangedFunc(f MetadataChangedFunc) {
+	metadataChangedFunc = f
+}
+
+var (
+	// ErrInvalidParentsCount is returned when the amount of parents in the stored metadata is out of range.
+	ErrInvalidParentsCount = errors.New("invalid parents count")
+	// ErrInvalidMetadataLength is returned when the stored metadata has an unexpected length.
+	ErrInvalidMetadataLength = errors.New("invalid metadata length")
 )
 
 type TransactionMetadata struct {
 	objectstorage.StorableObjectFlags
 	syncutils.RWMutex
 
-	txHash Hash
+	// txHashData holds the interned transaction hash, so the metadata doesn't keep the key buffer of the caller alive
+	txHashData [49]byte
+	txHash     Hash
 
 	// Metadata
 	metadata bitmask.BitMask
 
+	// Extended metadata
+	extendedMetadata bitmask.BitMask
+
 	// Unix time when the Tx became solid (needed for local modifiers for tipselection)
 	solidificationTimestamp int32
 
+	// Unix time when the Tx was stored by the node (0 if it was stored before the arrival was recorded)
+	arrivalTimestamp int32
+
 	// The index of the milestone which confirmed this tx
 	confirmationIndex milestone.Index
 
@@ -45,32 +129,68 @@ type TransactionMetadata struct {
 	// rootSnapshotCalculationIndex is the solid index yrtsi and ortsi were calculated at
 	rootSnapshotCalculationIndex milestone.Index
 
-	// trunkHash is the trunk of the transaction
-	trunkHash Hash
+	// The index of the milestone this transaction is part of (only set for milestone transactions)
+	milestoneIndex milestone.Index
 
-	// branchHash is the branch of the transaction
-	branchHash Hash
+	// The reason why the transaction was marked as conflicting
+	conflictReason ConflictReason
+
+	// parents are the transactions referenced by the transaction (trunk first, then branch)
+	parents Hashes
 
 	// bundleHash is the bundle of the transaction
 	bundleHash Hash
 }
 
-func NewTransactionMetadata(txHash Hash) *TransactionMetadata {
-	return &TransactionMetadata{
-		txHash: txHash,
+func (m *TransactionMetadata) metadataChanged(change MetadataChange) {
+	if metadataChangedFunc != nil {
+		metadataChangedFunc(m, change)
 	}
 }
 
+func NewTransactionMetadata(txHash Hash) *TransactionMetadata {
+	m := &TransactionMetadata{}
+	copy(m.txHashData[:], txHash)
+	m.txHash = m.txHashData[:len(txHash)]
+	return m
+}
+
 func (m *TransactionMetadata) GetTxHash() Hash {
 	return m.txHash
 }
 
+// GetParents returns the hashes of the transactions referenced by the transaction.
+func (m *TransactionMetadata) GetParents() Hashes {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.parents
+}
+
+// GetTrunkHash returns the first parent of the transaction.
 func (m *TransactionMetadata) GetTrunkHash() Hash {
-	return m.trunkHash
+	m.RLock()
+	defer m.RUnlock()
+
+	if len(m.parents) < 1 {
+		return nil
+	}
+	return m.parents[0]
 }
 
+// GetBranchHash returns the second parent of the transaction, or the first one if only a single parent exists.
 func (m *TransactionMetadata) GetBranchHash() Hash {
-	return m.branchHash
+	m.RLock()
+	defer m.RUnlock()
+
+	switch len(m.parents) {
+	case 0:
+		return nil
+	case 1:
+		return m.parents[0]
+	default:
+		return m.parents[1]
+	}
 }
 
 func (m *TransactionMetadata) GetBundleHash() Hash {
@@ -105,6 +225,23 @@ func (m *TransactionMetadata) GetSolidificationTimestamp() int32 {
 	return m.solidificationTimestamp
 }
 
+// GetArrivalTimestamp returns the unix time when the transaction was stored by the node (0 if unknown).
+func (m *TransactionMetadata) GetArrivalTimestamp() int32 {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.arrivalTimestamp
+}
+
+// SetArrivalTimestamp sets the unix time when the transaction was stored by the node.
+func (m *TransactionMetadata) SetArrivalTimestamp(arrivalTimestamp int32) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.arrivalTimestamp = arrivalTimestamp
+	m.SetModified(true)
+}
+
 func (m *TransactionMetadata) IsSolid() bool {
 	m.RLock()
 	defer m.RUnlock()
@@ -114,16 +251,23 @@ func (m *TransactionMetadata) IsSolid() bool {
 
 func (m *TransactionMetadata) SetSolid(solid bool) {
 	m.Lock()
-	defer m.Unlock()
 
-	if solid != m.metadata.HasBit(TransactionMetadataSolid) {
-		if solid {
-			m.solidificationTimestamp = int32(time.Now().Unix())
-		} else {
-			m.solidificationTimestamp = 0
-		}
-		m.metadata = m.metadata.ModifyBit(TransactionMetadataSolid, solid)
-		m.SetModified(true)
+	if solid == m.metadata.HasBit(TransactionMetadataSolid) {
+		m.Unlock()
+		return
+	}
+
+	if solid {
+		m.solidificationTimestamp = int32(time.Now().Unix())
+	} else {
+		m.solidificationTimestamp = 0
+	}
+	m.metadata = m.metadata.ModifyBit(TransactionMetadataSolid, solid)
+	m.SetModified(true)
+	m.Unlock()
+
+	if solid {
+		m.metadataChanged(MetadataChangeSolid)
 	}
 }
 
@@ -143,19 +287,99 @@ func (m *TransactionMetadata) GetConfirmed() (bool, milestone.Index) {
 
 func (m *TransactionMetadata) SetConfirmed(confirmed bool, confirmationIndex milestone.Index) {
 	m.Lock()
+
+	if confirmed == m.metadata.HasBit(TransactionMetadataConfirmed) {
+		m.Unlock()
+		return
+	}
+
+	if confirmed {
+		m.confirmationIndex = confirmationIndex
+	} else {
+		m.confirmationIndex = 0
+	}
+	m.metadata = m.metadata.ModifyBit(TransactionMetadataConfirmed, confirmed)
+	m.SetModified(true)
+	m.Unlock()
+
+	if confirmed {
+		m.metadataChanged(MetadataChangeConfirmed)
+	}
+}
+
+// IsReferenced returns whether the transaction was referenced by a milestone.
+// A referenced transaction is not necessarily included in the ledger (see GetLedgerInclusionState).
+func (m *TransactionMetadata) IsReferenced() bool {
+	return m.IsConfirmed()
+}
+
+// GetReferenced returns whether the transaction was referenced by a milestone and the index of this milestone.
+func (m *TransactionMetadata) GetReferenced() (bool, milestone.Index) {
+	return m.GetConfirmed()
+}
+
+// SetReferenced marks the transaction as referenced by the milestone with the given index.
+func (m *TransactionMetadata) SetReferenced(referenced bool, referencedIndex milestone.Index) {
+	m.SetConfirmed(referenced, referencedIndex)
+}
+
+// IsIncluded returns whether the ledger mutations of the transaction's bundle were applied by the white-flag confirmation.
+func (m *TransactionMetadata) IsIncluded() bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.metadata.HasBit(TransactionMetadataIncluded)
+}
+
+// SetIncluded marks whether the ledger mutations of the transaction's bundle were applied by the white-flag confirmation.
+func (m *TransactionMetadata) SetIncluded(included bool) {
+	m.Lock()
 	defer m.Unlock()
 
-	if confirmed != m.metadata.HasBit(TransactionMetadataConfirmed) {
-		if confirmed {
-			m.confirmationIndex = confirmationIndex
-		} else {
-			m.confirmationIndex = 0
-		}
-		m.metadata = m.metadata.ModifyBit(TransactionMetadataConfirmed, confirmed)
+	if included != m.metadata.HasBit(TransactionMetadataIncluded) {
+		m.metadata = m.metadata.ModifyBit(TransactionMetadataIncluded, included)
 		m.SetModified(true)
 	}
 }
 
+// IsNoTransaction returns whether the transaction was referenced by a milestone without mutating the ledger,
+// because its bundle was a zero value or spam bundle.
+func (m *TransactionMetadata) IsNoTransaction() bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.metadata.HasBit(TransactionMetadataNoTx)
+}
+
+// SetNoTransaction marks whether the transaction was referenced by a milestone without mutating the ledger.
+func (m *TransactionMetadata) SetNoTransaction(noTx bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	if noTx != m.metadata.HasBit(TransactionMetadataNoTx) {
+		m.metadata = m.metadata.ModifyBit(TransactionMetadataNoTx, noTx)
+		m.SetModified(true)
+	}
+}
+
+// GetLedgerInclusionState returns the combined white-flag state of the transaction.
+func (m *TransactionMetadata) GetLedgerInclusionState() LedgerInclusionState {
+	m.RLock()
+	defer m.RUnlock()
+
+	switch {
+	case !m.metadata.HasBit(TransactionMetadataConfirmed):
+		return LedgerInclusionStateNotReferenced
+	case m.metadata.HasBit(TransactionMetadataConflicting):
+		return LedgerInclusionStateConflicting
+	case m.metadata.HasBit(TransactionMetadataNoTx):
+		return LedgerInclusionStateNoTransaction
+	default:
+		// transactions referenced before the inclusion flags were introduced are treated as included
+		return LedgerInclusionStateIncluded
+	}
+}
+
 func (m *TransactionMetadata) IsConflicting() bool {
 	m.RLock()
 	defer m.RUnlock()
@@ -163,24 +387,109 @@ func (m *TransactionMetadata) IsConflicting() bool {
 	return m.metadata.HasBit(TransactionMetadataConflicting)
 }
 
-func (m *TransactionMetadata) SetConflicting(conflicting bool) {
+// GetConflictReason returns the reason why the transaction was marked as conflicting.
+func (m *TransactionMetadata) GetConflictReason() ConflictReason {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.conflictReason
+}
+
+// SetConflicting marks the transaction as conflicting for the given reason.
+// The reason is reset if the transaction is not conflicting.
+func (m *TransactionMetadata) SetConflicting(conflicting bool, reason ConflictReason) {
+	m.Lock()
+
+	if !conflicting {
+		reason = ConflictReasonNone
+	}
+
+	if conflicting == m.metadata.HasBit(TransactionMetadataConflicting) && reason == m.conflictReason {
+		m.Unlock()
+		return
+	}
+
+	wasConflicting := m.metadata.HasBit(TransactionMetadataConflicting)
+	m.metadata = m.metadata.ModifyBit(TransactionMetadataConflicting, conflicting)
+	m.conflictReason = reason
+	m.SetModified(true)
+	m.Unlock()
+
+	if wasConflicting {
+		return
+	}
+
+	if conflicting {
+		m.metadataChanged(MetadataChangeConflicting)
+	}
+}
+
+// IsMilestone returns whether the transaction is part of a valid milestone bundle.
+func (m *TransactionMetadata) IsMilestone() bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.extendedMetadata.HasBit(TransactionMetadataExtIsMilestone)
+}
+
+// GetMilestone returns whether the transaction is part of a valid milestone bundle and the index of this milestone.
+func (m *TransactionMetadata) GetMilestone() (bool, milestone.Index) {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.extendedMetadata.HasBit(TransactionMetadataExtIsMilestone), m.milestoneIndex
+}
+
+// SetMilestone marks the transaction as part of the milestone bundle with the given index.
+func (m *TransactionMetadata) SetMilestone(isMilestone bool, milestoneIndex milestone.Index) {
 	m.Lock()
 	defer m.Unlock()
 
-	if conflicting != m.metadata.HasBit(TransactionMetadataConflicting) {
-		m.metadata = m.metadata.ModifyBit(TransactionMetadataConflicting, conflicting)
+	if isMilestone != m.extendedMetadata.HasBit(TransactionMetadataExtIsMilestone) {
+		if isMilestone {
+			m.milestoneIndex = milestoneIndex
+		} else {
+			m.milestoneIndex = 0
+		}
+		m.extendedMetadata = m.extendedMetadata.ModifyBit(TransactionMetadataExtIsMilestone, isMilestone)
 		m.SetModified(true)
 	}
 }
 
-func (m *TransactionMetadata) SetRootSnapshotIndexes(yrtsi milestone.Index, ortsi milestone.Index, rtsci milestone.Index) {
+// IsUnsolidifiable returns whether the past cone of the transaction references a transaction
+// which could not be requested from any neighbor, so the transaction will never become solid.
+func (m *TransactionMetadata) IsUnsolidifiable() bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.extendedMetadata.HasBit(TransactionMetadataExtUnsolidifiable)
+}
+
+// SetUnsolidifiable marks the transaction as unsolidifiable.
+// The flag is kept if the transaction becomes solid later on, e.g. because it is part of a milestone cone.
+func (m *TransactionMetadata) SetUnsolidifiable(unsolidifiable bool) {
 	m.Lock()
 	defer m.Unlock()
 
+	if unsolidifiable != m.extendedMetadata.HasBit(TransactionMetadataExtUnsolidifiable) {
+		m.extendedMetadata = m.extendedMetadata.ModifyBit(TransactionMetadataExtUnsolidifiable, unsolidifiable)
+		m.SetModified(true)
+	}
+}
+
+func (m *TransactionMetadata) SetRootSnapshotIndexes(yrtsi milestone.Index, ortsi milestone.Index, rtsci milestone.Index) {
+	m.Lock()
+
+	changed := m.youngestRootSnapshotIndex != yrtsi || m.oldestRootSnapshotIndex != ortsi
 	m.youngestRootSnapshotIndex = yrtsi
 	m.oldestRootSnapshotIndex = ortsi
 	m.rootSnapshotCalculationIndex = rtsci
 	m.SetModified(true)
+	m.Unlock()
+
+	if changed {
+		m.metadataChanged(MetadataChangeRootSnapshotIndexes)
+	}
 }
 
 func (m *TransactionMetadata) GetRootSnapshotIndexes() (yrtsi milestone.Index, ortsi milestone.Index, rtsci milestone.Index) {
@@ -190,12 +499,11 @@ func (m *TransactionMetadata) GetRootSnapshotIndexes() (yrtsi milestone.Index, o
 	return m.youngestRootSnapshotIndex, m.oldestRootSnapshotIndex, m.rootSnapshotCalculationIndex
 }
 
-func (m *TransactionMetadata) SetAdditionalTxInfo(trunkHash Hash, branchHash Hash, bundleHash Hash, isHead bool, isTail bool, isValue bool) {
+func (m *TransactionMetadata) SetAdditionalTxInfo(parents Hashes, bundleHash Hash, isHead bool, isTail bool, isValue bool) {
 	m.Lock()
 	defer m.Unlock()
 
-	m.trunkHash = trunkHash
-	m.branchHash = branchHash
+	m.parents = parents
 	m.bundleHash = bundleHash
 	m.metadata = m.metadata.ModifyBit(TransactionMetadataIsHead, isHead).ModifyBit(TransactionMetadataIsTail, isTail).ModifyBit(TransactionMetadataIsValue, isValue)
 	m.SetModified(true)
@@ -229,25 +537,93 @@ func (m *TransactionMetadata) ObjectStorageValue() (data []byte) {
 		4 bytes uint32 youngestRootSnapshotIndex
 		4 bytes uint32 oldestRootSnapshotIndex
 		4 bytes uint32 rootSnapshotCalculationIndex
-		49 bytes hash trunk
-		49 bytes hash branch
 		49 bytes hash bundle
+		1 byte  parents count
+		parents count * 49 bytes hash parents
+		1 byte  extended metadata bitmask
+		4 bytes uint32 milestoneIndex
+		1 byte  conflict reason
+		4 bytes uint32 arrivalTimestamp
 	*/
 
-	value := make([]byte, 21)
+	value := make([]byte, 21, 21+49+1+len(m.parents)*49+10)
 	value[0] = byte(m.metadata)
 	binary.LittleEndian.PutUint32(value[1:], uint32(m.solidificationTimestamp))
 	binary.LittleEndian.PutUint32(value[5:], uint32(m.confirmationIndex))
 	binary.LittleEndian.PutUint32(value[9:], uint32(m.youngestRootSnapshotIndex))
 	binary.LittleEndian.PutUint32(value[13:], uint32(m.oldestRootSnapshotIndex))
 	binary.LittleEndian.PutUint32(value[17:], uint32(m.rootSnapshotCalculationIndex))
-	value = append(value, m.trunkHash...)
-	value = append(value, m.branchHash...)
 	value = append(value, m.bundleHash...)
+	value = append(value, byte(len(m.parents)))
+	for _, parent := range m.parents {
+		value = append(value, parent...)
+	}
+	value = append(value, byte(m.extendedMetadata))
+	value = append(value, make([]byte, 4)...)
+	binary.LittleEndian.PutUint32(value[len(value)-4:], uint32(m.milestoneIndex))
+	value = append(value, byte(m.conflictReason))
+	value = append(value, make([]byte, 4)...)
+	binary.LittleEndian.PutUint32(value[len(value)-4:], uint32(m.arrivalTimestamp))
 
 	return value
 }
 
+// the layouts of the stored transaction metadata.
+type metadataLayout int
+
+const (
+	// the additional tx info is added from the transaction afterwards.
+	metadataLayoutWithoutTxInfo metadataLayout = iota
+	// fixed trunk and branch.
+	metadataLayoutLegacy
+	// variable amount of parents, optionally followed by the milestone index, the conflict reason and the arrival timestamp.
+	metadataLayoutParents
+)
+
+const (
+	// the length of the stored metadata without the root snapshot calculation index.
+	metadataLengthWithoutCalculationIndex = 17
+	// the length of the stored metadata without the tx info.
+	metadataLengthWithoutTxInfo = 21
+	// the length of the stored metadata with fixed trunk and branch.
+	metadataLengthLegacy = metadataLengthWithoutTxInfo + 49 + 49 + 49
+	// the offset of the parents count.
+	metadataParentsCountOffset = metadataLengthWithoutTxInfo + 49
+)
+
+// checkMetadataLength checks the length of the stored metadata and returns its layout and the amount of parents.
+func checkMetadataLength(data []byte) (metadataLayout, int, error) {
+	switch {
+	case len(data) == metadataLengthWithoutCalculationIndex || len(data) == metadataLengthWithoutTxInfo:
+		return metadataLayoutWithoutTxInfo, 0, nil
+
+	case len(data) == metadataLengthLegacy:
+		return metadataLayoutLegacy, 2, nil
+
+	case len(data) > metadataParentsCountOffset:
+		parentsCount := int(data[metadataParentsCountOffset])
+		if parentsCount < MinParentsCount || parentsCount > MaxParentsCount {
+			return 0, 0, fmt.Errorf("%w: %d", ErrInvalidParentsCount, parentsCount)
+		}
+
+		parentsEnd := metadataParentsCountOffset + 1 + parentsCount*49
+		if len(data) != parentsEnd && len(data) != parentsEnd+5 && len(data) != parentsEnd+6 && len(data) != parentsEnd+10 {
+			return 0, 0, fmt.Errorf("%w: %d", ErrInvalidMetadataLength, len(data))
+		}
+		return metadataLayoutParents, parentsCount, nil
+
+	default:
+		return 0, 0, fmt.Errorf("%w: %d", ErrInvalidMetadataLength, len(data))
+	}
+}
+
+// ValidateTransactionMetadataValue checks whether the given stored metadata can be unmarshaled,
+// without allocating the metadata.
+func ValidateTransactionMetadataValue(data []byte) error {
+	_, _, err := checkMetadataLength(data)
+	return err
+}
+
 func (m *TransactionMetadata) UnmarshalObjectStorageValue(data []byte) (consumedBytes int, err error) {
 	m.Lock()
 	defer m.Unlock()
@@ -259,11 +635,22 @@ func (m *TransactionMetadata) UnmarshalObjectStorageValue(data []byte) (consumed
 		4 bytes uint32 youngestRootSnapshotIndex
 		4 bytes uint32 oldestRootSnapshotIndex
 		4 bytes uint32 rootSnapshotCalculationIndex
-		49 bytes hash trunk
-		49 bytes hash branch
 		49 bytes hash bundle
+		1 byte  parents count
+		parents count * 49 bytes hash parents
+		1 byte  extended metadata bitmask (optional)
+		4 bytes uint32 milestoneIndex	 (optional)
+		1 byte  conflict reason          (optional)
+		4 bytes uint32 arrivalTimestamp  (optional)
+
+		the legacy layout stored 49 bytes hash trunk, 49 bytes hash branch and 49 bytes hash bundle instead.
 	*/
 
+	layout, parentsCount, err := checkMetadataLength(data)
+	if err != nil {
+		return 0, err
+	}
+
 	m.metadata = bitmask.BitMask(data[0])
 	m.solidificationTimestamp = int32(binary.LittleEndian.Uint32(data[1:5]))
 	m.confirmationIndex = milestone.Index(binary.LittleEndian.Uint32(data[5:9]))
@@ -271,14 +658,42 @@ func (m *TransactionMetadata) UnmarshalObjectStorageValue(data []byte) (consumed
 	m.oldestRootSnapshotIndex = milestone.Index(binary.LittleEndian.Uint32(data[13:17]))
 	m.rootSnapshotCalculationIndex = 0
 
-	if len(data) > 17 {
-		// ToDo: Remove at next DbVersion update
-		m.rootSnapshotCalculationIndex = milestone.Index(binary.LittleEndian.Uint32(data[17:21]))
+	if len(data) == metadataLengthWithoutCalculationIndex {
+		return len(data), nil
+	}
+
+	// ToDo: Remove at next DbVersion update
+	m.rootSnapshotCalculationIndex = milestone.Index(binary.LittleEndian.Uint32(data[17:21]))
+
+	switch layout {
+	case metadataLayoutWithoutTxInfo:
+		// additional tx info is added from the transaction afterwards
+
+	case metadataLayoutLegacy:
+		m.parents = Hashes{Hash(data[21 : 21+49]), Hash(data[21+49 : 21+49+49])}
+		m.bundleHash = Hash(data[21+49+49 : 21+49+49+49])
+
+	case metadataLayoutParents:
+		m.bundleHash = Hash(data[21 : 21+49])
+		m.parents = make(Hashes, parentsCount)
+		offset := metadataParentsCountOffset + 1
+		for i := 0; i < parentsCount; i++ {
+			m.parents[i] = Hash(data[offset : offset+49])
+			offset += 49
+		}
+
+		parentsEnd := offset
+		if len(data) >= parentsEnd+5 {
+			m.extendedMetadata = bitmask.BitMask(data[parentsEnd])
+			m.milestoneIndex = milestone.Index(binary.LittleEndian.Uint32(data[parentsEnd+1 : parentsEnd+5]))
+		}
+
+		if len(data) >= parentsEnd+6 {
+			m.conflictReason = ConflictReason(data[parentsEnd+5])
+		}
 
-		if len(data) == 21+49+49+49 {
-			m.trunkHash = Hash(data[21 : 21+49])
-			m.branchHash = Hash(data[21+49 : 21+49+49])
-			m.bundleHash = Hash(data[21+49+49 : 21+49+49+49])
+		if len(data) == parentsEnd+10 {
+			m.arrivalTimestamp = int32(binary.LittleEndian.Uint32(data[parentsEnd+6 : parentsEnd+10]))
 		}
 	}
 
@@ -0,0 +1,147 @@
+package hornet
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+func randomHash() Hash {
+	hash := make(Hash, 49)
+	rand.Read(hash)
+	return hash
+}
+
+func randomTransactionMetadata() *TransactionMetadata {
+	m := NewTransactionMetadata(randomHash())
+	m.SetAdditionalTxInfo(Hashes{randomHash(), randomHash()}, randomHash(), true, true, true)
+	m.SetSolid(true)
+	m.SetConfirmed(true, 1337)
+	m.SetRootSnapshotIndexes(1337, 1330, 1340)
+	m.SetMilestone(true, 1337)
+	m.SetUnsolidifiable(true)
+	m.SetArrivalTimestamp(1600000000)
+	return m
+}
+
+func TestTransactionMetadataSerialization(t *testing.T) {
+	m := randomTransactionMetadata()
+
+	restored := NewTransactionMetadata(m.GetTxHash())
+	_, err := restored.UnmarshalObjectStorageValue(m.ObjectStorageValue())
+	require.NoError(t, err)
+
+	require.Equal(t, m.GetTxHash(), restored.GetTxHash())
+	require.Equal(t, m.GetBundleHash(), restored.GetBundleHash())
+	require.Equal(t, m.GetParents(), restored.GetParents())
+	require.Equal(t, m.GetMetadata(), restored.GetMetadata())
+
+	confirmed, confirmationIndex := restored.GetConfirmed()
+	require.True(t, confirmed)
+	require.Equal(t, milestone.Index(1337), confirmationIndex)
+
+	isMilestone, milestoneIndex := restored.GetMilestone()
+	require.True(t, isMilestone)
+	require.Equal(t, milestone.Index(1337), milestoneIndex)
+
+	require.True(t, restored.IsUnsolidifiable())
+	require.Equal(t, int32(1600000000), restored.GetArrivalTimestamp())
+}
+
+func TestTransactionMetadataInternsTxHash(t *testing.T) {
+	key := randomHash()
+	expected := append(Hash{}, key...)
+
+	m := NewTransactionMetadata(key)
+
+	// modifying the key buffer of the storage layer must not modify the metadata
+	key[0]++
+	require.Equal(t, expected, m.GetTxHash())
+}
+
+func BenchmarkTransactionMetadataUnmarshal(b *testing.B) {
+	m := randomTransactionMetadata()
+	data := m.ObjectStorageValue()
+	txHash := m.GetTxHash()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		restored := NewTransactionMetadata(txHash)
+		if _, err := restored.UnmarshalObjectStorageValue(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTransactionMetadataRetainedHeap measures the heap which stays in use for every cached metadata object,
+// including the key and value buffers which are allocated by the storage layer for every loaded object.
+func BenchmarkTransactionMetadataRetainedHeap(b *testing.B) {
+	m := randomTransactionMetadata()
+	data := m.ObjectStorageValue()
+	txHash := m.GetTxHash()
+
+	cached := make([]*TransactionMetadata, b.N)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		key := append(Hash{}, txHash...)
+		value := append([]byte{}, data...)
+
+		cached[i] = NewTransactionMetadata(key)
+		if _, err := cached[i].UnmarshalObjectStorageValue(value); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.StopTimer()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/float64(b.N), "retained-B/op")
+	runtime.KeepAlive(cached)
+}
+
+func BenchmarkTransactionMetadataMarshal(b *testing.B) {
+	m := randomTransactionMetadata()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = m.ObjectStorageValue()
+	}
+}
+
+func BenchmarkTransactionMetadataSetAdditionalTxInfo(b *testing.B) {
+	parents := Hashes{randomHash(), randomHash()}
+	bundleHash := randomHash()
+	txHash := randomHash()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		m := NewTransactionMetadata(txHash)
+		m.SetAdditionalTxInfo(parents, bundleHash, true, true, false)
+	}
+}
+
+func TestTransactionMetadataInvalidLength(t *testing.T) {
+	data := randomTransactionMetadata().ObjectStorageValue()
+
+	for _, length := range []int{0, 16, 18, 20, 21 + 49, len(data) - 2} {
+		restored := NewTransactionMetadata(randomHash())
+		_, err := restored.UnmarshalObjectStorageValue(data[:length])
+		require.True(t, errors.Is(err, ErrInvalidMetadataLength), "length %d", length)
+	}
+}
@@ -0,0 +1,99 @@
+package hornet
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/iotaledger/hive.go/objectstorage"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+// TransactionType is the kind of content a transaction carries.
+type TransactionType byte
+
+const (
+	// TransactionTypeData is a zero value transaction which only carries data (and its tag as the index key).
+	TransactionTypeData TransactionType = iota
+	// TransactionTypeValue is a transaction which transfers tokens.
+	TransactionTypeValue
+	// TransactionTypeMilestone is a transaction of a verified milestone bundle.
+	TransactionTypeMilestone
+)
+
+// TransactionTypes are all known transaction types.
+var TransactionTypes = []TransactionType{TransactionTypeData, TransactionTypeValue, TransactionTypeMilestone}
+
+var transactionTypeNames = map[TransactionType]string{
+	TransactionTypeData:      "data",
+	TransactionTypeValue:     "value",
+	TransactionTypeMilestone: "milestone",
+}
+
+func (t TransactionType) String() string {
+	if name, exists := transactionTypeNames[t]; exists {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", t)
+}
+
+// TransactionTypeFromString parses the name of a transaction type.
+func TransactionTypeFromString(name string) (TransactionType, error) {
+	for txType, typeName := range transactionTypeNames {
+		if typeName == name {
+			return txType, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown transaction type: %s", name)
+}
+
+// TransactionTypeEntry is an entry of the index of transactions by their type.
+// transactions are grouped by the latest milestone index at the time they were first seen,
+// so that the most recent transactions of a type can be listed without scanning all transactions.
+type TransactionTypeEntry struct {
+	objectstorage.StorableObjectFlags
+	txType               TransactionType
+	latestMilestoneIndex milestone.Index
+	txHash               Hash
+}
+
+func NewTransactionTypeEntry(txType TransactionType, msIndex milestone.Index, txHash Hash) *TransactionTypeEntry {
+	return &TransactionTypeEntry{
+		txType:               txType,
+		latestMilestoneIndex: msIndex,
+		txHash:               txHash,
+	}
+}
+
+func (t *TransactionTypeEntry) GetTransactionType() TransactionType {
+	return t.txType
+}
+
+func (t *TransactionTypeEntry) GetLatestMilestoneIndex() milestone.Index {
+	return t.latestMilestoneIndex
+}
+
+func (t *TransactionTypeEntry) GetTxHash() Hash {
+	return t.txHash
+}
+
+// ObjectStorage interface
+
+func (t *TransactionTypeEntry) Update(_ objectstorage.StorableObject) {
+	panic(fmt.Sprintf("TransactionTypeEntry should never be updated: %v, TxHash: %v", t.txType, t.txHash.Trytes()))
+}
+
+func (t *TransactionTypeEntry) ObjectStorageKey() []byte {
+	key := make([]byte, 5, 54)
+	key[0] = byte(t.txType)
+	binary.LittleEndian.PutUint32(key[1:5], uint32(t.latestMilestoneIndex))
+	return append(key, t.txHash...)
+}
+
+func (t *TransactionTypeEntry) ObjectStorageValue() (_ []byte) {
+	return nil
+}
+
+func (t *TransactionTypeEntry) UnmarshalObjectStorageValue(_ []byte) (consumedBytes int, err error) {
+	return 0, nil
+}
@@ -4,6 +4,7 @@ import (
 	"container/list"
 	"context"
 	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -29,19 +30,27 @@ type HeaviestSelector struct {
 	maxHeaviestBranchTipsPerCheckpoint                int
 	randomTipsPerCheckpoint                           int
 	heaviestBranchSelectionDeadline                   time.Duration
+	heaviestBranchSelectionMaxTipEvaluations          int
+
+	// returns a random number in [0, n), it is replaced in tests to get a reproducible selection
+	randomIndex func(n int) int
 
 	trackedTails map[string]*bundleTail // map of all tracked bundle transaction tails
 	tips         *list.List             // list of available tips
 }
 
 type bundleTail struct {
-	hash hornet.Hash    // hash of the corresponding tail transaction
-	tip  *list.Element  // pointer to the element in the tip list
-	refs *bitset.BitSet // BitSet of all the referenced transactions
+	hash  hornet.Hash    // hash of the corresponding tail transaction
+	index uint           // the bit of the tail in the bitsets, tails tracked earlier have lower indexes
+	tip   *list.Element  // pointer to the element in the tip list
+	refs  *bitset.BitSet // BitSet of all the referenced transactions
 }
 
 type bundleTailList struct {
 	tails map[string]*bundleTail
+
+	// the amount of tips that were evaluated while selecting the tips from this list
+	evaluations int
 }
 
 // Len returns the length of the inner tails slice.
@@ -50,23 +59,19 @@ func (il *bundleTailList) Len() int {
 }
 
 // randomTip selects a random tip item from the bundleTailList.
-func (il *bundleTailList) randomTip() (*bundleTail, error) {
+// the tips are ordered by their index, so the selected tip only depends on the given random function.
+func (il *bundleTailList) randomTip(randomIndex func(n int) int) (*bundleTail, error) {
 	if len(il.tails) == 0 {
 		return nil, ErrNoTipsAvailable
 	}
 
-	randomTailIndex := utils.RandomInsecure(0, len(il.tails)-1)
-
+	tips := make([]*bundleTail, 0, len(il.tails))
 	for _, tip := range il.tails {
-		randomTailIndex--
-
-		// if randomTailIndex is below zero, we return the given tip
-		if randomTailIndex < 0 {
-			return tip, nil
-		}
+		tips = append(tips, tip)
 	}
+	sort.Slice(tips, func(i, j int) bool { return tips[i].index < tips[j].index })
 
-	return nil, ErrNoTipsAvailable
+	return tips[randomIndex(len(tips))], nil
 }
 
 // referenceTip removes the tip and set all bits of all referenced
@@ -81,6 +86,7 @@ func (il *bundleTailList) referenceTip(tip *bundleTail) {
 	for _, otherTip := range il.tails {
 		otherTip.refs.InPlaceDifference(tip.refs)
 	}
+	il.evaluations += len(il.tails)
 }
 
 // removeTip removes the tip from the map.
@@ -89,12 +95,18 @@ func (il *bundleTailList) removeTip(tip *bundleTail) {
 }
 
 // New creates a new HeaviestSelector instance.
-func New(minHeaviestBranchUnconfirmedTransactionsThreshold int, maxHeaviestBranchTipsPerCheckpoint int, randomTipsPerCheckpoint int, heaviestBranchSelectionDeadline time.Duration) *HeaviestSelector {
+// the heaviest branch tips are selected until the deadline passed or the given amount of tip evaluations
+// was spent (0 disables the limit), whatever comes first.
+func New(minHeaviestBranchUnconfirmedTransactionsThreshold int, maxHeaviestBranchTipsPerCheckpoint int, randomTipsPerCheckpoint int, heaviestBranchSelectionDeadline time.Duration, heaviestBranchSelectionMaxTipEvaluations int) *HeaviestSelector {
 	s := &HeaviestSelector{
 		minHeaviestBranchUnconfirmedTransactionsThreshold: minHeaviestBranchUnconfirmedTransactionsThreshold,
 		maxHeaviestBranchTipsPerCheckpoint:                maxHeaviestBranchTipsPerCheckpoint,
 		randomTipsPerCheckpoint:                           randomTipsPerCheckpoint,
 		heaviestBranchSelectionDeadline:                   heaviestBranchSelectionDeadline,
+		heaviestBranchSelectionMaxTipEvaluations:          heaviestBranchSelectionMaxTipEvaluations,
+		randomIndex: func(n int) int {
+			return utils.RandomInsecure(0, n-1)
+		},
 	}
 	s.reset()
 	return s
@@ -115,43 +127,38 @@ func (s *HeaviestSelector) reset() {
 // selectTip selects a tip to be used for the next checkpoint.
 // it returns a tip, confirming the most transactions in the future cone,
 // and the amount of referenced transactions of this tip, that were not referenced by previously chosen tips.
+// if several tips reference the same amount of transactions, the tip which was tracked first is selected,
+// since it is the closest one to become lazy.
 func (s *HeaviestSelector) selectTip(tipsList *bundleTailList) (*bundleTail, uint, error) {
 
 	if tipsList.Len() == 0 {
 		return nil, 0, ErrNoTipsAvailable
 	}
 
-	var best = struct {
-		tips  []*bundleTail
-		count uint
-	}{
-		tips:  []*bundleTail{},
-		count: 0,
-	}
+	var best *bundleTail
+	var bestCount uint
 
 	// loop through all tips and find the one with the most referenced transactions
 	for _, tip := range tipsList.tails {
 		c := tip.refs.Count()
-		if c > best.count {
-			// tip with heavier branch found
-			best.tips = []*bundleTail{
-				tip,
-			}
-			best.count = c
-		} else if c == best.count {
-			// add the tip to the slice of currently best tips
-			best.tips = append(best.tips, tip)
+		if best == nil || c > bestCount || (c == bestCount && tip.index < best.index) {
+			best = tip
+			bestCount = c
 		}
 	}
+	tipsList.evaluations += tipsList.Len()
 
-	if len(best.tips) == 0 {
-		return nil, 0, ErrNoTipsAvailable
-	}
-
-	// select a random tip from the provided slice of tips.
-	selected := best.tips[utils.RandomInsecure(0, len(best.tips)-1)]
+	return best, bestCount, nil
+}
 
-	return selected, best.count, nil
+// budgetExceeded returns whether selecting another heaviest branch tip from the given list
+// would exceed the amount of tip evaluations.
+func (s *HeaviestSelector) budgetExceeded(tipsList *bundleTailList) bool {
+	if s.heaviestBranchSelectionMaxTipEvaluations <= 0 {
+		return false
+	}
+	// selecting a tip evaluates all remaining tips, referencing it all others
+	return tipsList.evaluations+2*tipsList.Len()-1 > s.heaviestBranchSelectionMaxTipEvaluations
 }
 
 // SelectTips tries to collect tips that confirm the most recent transactions since the last reset of the selector.
@@ -165,7 +172,8 @@ func (s *HeaviestSelector) selectTip(tipsList *bundleTailList) (*bundleTail, uin
 // "minHeaviestBranchUnconfirmedTransactionsThreshold" criteria.
 // if at least one heaviest branch tip was found, "randomTipsPerCheckpoint" random tips are added
 // to add some additional randomness to prevent parasite chain attacks.
-// the selection is cancelled after a fixed deadline. in this case, it returns the current collected tips.
+// the selection is cancelled after a fixed deadline or if the amount of tip evaluations is spent.
+// in this case, it returns the current collected tips.
 func (s *HeaviestSelector) SelectTips(minRequiredTips int) (hornet.Hashes, error) {
 
 	// create a working list with the current tips to release the lock to allow faster iteration
@@ -195,6 +203,11 @@ func (s *HeaviestSelector) SelectTips(minRequiredTips int) (hornet.Hashes, error
 		default:
 		}
 
+		// the evaluations of a round grow with the amount of tips, which the deadline can't bound
+		if s.budgetExceeded(tipsList) {
+			deadlineExceeded = true
+		}
+
 		tip, count, err := s.selectTip(tipsList)
 		if err != nil {
 			break
@@ -216,7 +229,7 @@ func (s *HeaviestSelector) SelectTips(minRequiredTips int) (hornet.Hashes, error
 
 	// also pick random tips if at least one heaviest branch tip was found
 	for i := 0; i < s.randomTipsPerCheckpoint; i++ {
-		item, err := tipsList.randomTip()
+		item, err := tipsList.randomTip(s.randomIndex)
 		if err != nil {
 			break
 		}
@@ -235,23 +248,28 @@ func (s *HeaviestSelector) SelectTips(minRequiredTips int) (hornet.Hashes, error
 // The bundle must be solid and OnNewSolidBundle must be called in the order of solidification.
 // The bundle must also not be below max depth.
 func (s *HeaviestSelector) OnNewSolidBundle(bndl *tangle.Bundle) (trackedTailsCount int) {
+	return s.onNewSolidTail(bndl.GetTailHash(), bndl.GetTrunkHash(true), bndl.GetBranchHash(true))
+}
+
+// onNewSolidTail adds the tail of a new bundle which approves the given trunk and branch.
+func (s *HeaviestSelector) onNewSolidTail(tailHash hornet.Hash, trunkHash hornet.Hash, branchHash hornet.Hash) (trackedTailsCount int) {
 	s.Lock()
 	defer s.Unlock()
 
 	// filter duplicate transaction
-	if _, contains := s.trackedTails[string(bndl.GetTailHash())]; contains {
+	if _, contains := s.trackedTails[string(tailHash)]; contains {
 		return
 	}
 
-	trunkItem := s.trackedTails[string(bndl.GetTrunkHash(true))]
-	branchItem := s.trackedTails[string(bndl.GetBranchHash(true))]
+	trunkItem := s.trackedTails[string(trunkHash)]
+	branchItem := s.trackedTails[string(branchHash)]
 
 	// compute the referenced transactions
 	// all the known approvers in the HeaviestSelector are represented by a unique bit in a bitset.
 	// if a new approver is added, we expand the bitset by 1 bit and store the Union of the bitsets
 	// of trunk and branch for this approver, to know which parts of the cone are referenced by this approver.
 	idx := uint(len(s.trackedTails))
-	it := &bundleTail{hash: bndl.GetTailHash(), refs: bitset.New(idx + 1).Set(idx)}
+	it := &bundleTail{hash: tailHash, index: idx, refs: bitset.New(idx + 1).Set(idx)}
 	if trunkItem != nil {
 		it.refs.InPlaceUnion(trunkItem.refs)
 	}
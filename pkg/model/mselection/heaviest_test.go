@@ -1,162 +1,122 @@
 package mselection
 
-/*
-
 import (
-	"context"
-	"errors"
-	"math/rand"
-	"sync"
+	"fmt"
 	"testing"
 	"time"
 
-	"github.com/gohornet/hornet/pkg/model/hornet"
-	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
-	"github.com/iotaledger/iota.go/consts"
-	"github.com/iotaledger/iota.go/transaction"
-	"github.com/iotaledger/iota.go/trinary"
+	"github.com/gohornet/hornet/pkg/model/hornet"
 )
 
-const (
-	numTestTxs      = 32 * 100
-	numBenchmarkTxs = 5000
-)
+func testTailHash(idx int) hornet.Hash {
+	return hornet.Hash(fmt.Sprintf("tail%d", idx))
+}
 
-func init() {
-	rand.Seed(0)
+// addChain adds a chain of the given length on top of the given hash and returns the hash of its tip.
+func addChain(s *HeaviestSelector, firstIdx int, length int, root hornet.Hash) hornet.Hash {
+	last := root
+	for i := firstIdx; i < firstIdx+length; i++ {
+		s.onNewSolidTail(testTailHash(i), last, last)
+		last = testTailHash(i)
+	}
+	return last
 }
 
 func TestHeaviestSelector_SelectTipsChain(t *testing.T) {
-	hps := New()
-	// create a chain
-	var lastHash = hornet.NullHashBytes
-	for i := 1; i <= numTestTxs; i++ {
-		bndl := newTestBundle(i, lastHash, lastHash)
-		hps.OnNewSolidBundle(bndl)
-		lastHash = bndl.GetTailHash()
-	}
+	s := New(1, 10, 0, time.Second, 0)
+	tip := addChain(s, 0, 100, hornet.NullHashBytes)
 
-	tip, err := hps.selectTip(context.Background())
-	assert.NoError(t, err)
-	assert.ElementsMatch(t, lastHash, tip)
+	tips, err := s.SelectTips(0)
+	require.NoError(t, err)
+	assert.Equal(t, hornet.Hashes{tip}, tips)
+
+	// the selector is reset after the selection
+	assert.Equal(t, 0, s.GetTrackedTailsCount())
+	_, err = s.SelectTips(0)
+	assert.Equal(t, ErrNoTipsAvailable, err)
 }
 
 func TestHeaviestSelector_SelectTipsChains(t *testing.T) {
-	hps := New()
-
-	var lastHash = [2]hornet.Hash{}
-	for i := 0; i < 2; i++ {
-		lastHash[i] = hornet.NullHashBytes
-		for j := 1; j <= numTestTxs; j++ {
-			bndl := newTestBundle(i*numTestTxs+j, lastHash[i], lastHash[i])
-			hps.OnNewSolidBundle(bndl)
-			lastHash[i] = bndl.GetTailHash()
-		}
-	}
+	s := New(1, 10, 0, time.Second, 0)
+	shortTip := addChain(s, 0, 10, hornet.NullHashBytes)
+	longTip := addChain(s, 10, 20, hornet.NullHashBytes)
 
-	tip, err := hps.selectTip(context.Background())
-	assert.NoError(t, err)
-	assert.ElementsMatch(t, lastHash, tip)
+	tips, err := s.SelectTips(0)
+	require.NoError(t, err)
+	assert.Equal(t, hornet.Hashes{longTip, shortTip}, tips)
 }
 
-func TestHeaviestSelector_SelectTipsCancel(t *testing.T) {
-	hps := New()
-	// create a very large blow ball
-	for i := 1; i <= 10000; i++ {
-		bndl := newTestBundle(i, hornet.NullHashBytes, hornet.NullHashBytes)
-		hps.OnNewSolidBundle(bndl)
-	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	var wg sync.WaitGroup
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		_, err := hps.selectTip(ctx)
-		assert.Truef(t, errors.Is(err, context.Canceled), "unexpected error: %v", err)
-	}()
-
-	time.Sleep(10 * time.Millisecond)
-	cancel()
-	wg.Wait()
+func TestHeaviestSelector_SelectTipsOverlappingCones(t *testing.T) {
+	s := New(5, 10, 0, time.Second, 0)
+	base := addChain(s, 0, 20, hornet.NullHashBytes)
+	// both tips reference the base, the second one adds only a few transactions on top of it
+	heavyTip := addChain(s, 20, 5, base)
+	lightTip := addChain(s, 25, 3, base)
+
+	tips, err := s.SelectTips(0)
+	require.NoError(t, err)
+	// the light tip only references 3 transactions which are not referenced by the heavy tip, which is below the threshold
+	assert.Equal(t, hornet.Hashes{heavyTip}, tips)
+	assert.NotContains(t, tips, lightTip)
 }
 
-func TestHeaviestSelector_Concurrent(t *testing.T) {
-	hps := New()
-	hashes := []hornet.Hash{hornet.NullHashBytes}
-	for i := 0; i < 1000; i++ {
-		bndl := newTestBundle(i, hashes[rand.Intn(len(hashes))], hashes[rand.Intn(len(hashes))])
-		hps.OnNewSolidBundle(bndl)
-		hashes = append(hashes, bndl.GetTailHash())
-	}
+func TestHeaviestSelector_DeterministicTieBreaking(t *testing.T) {
+	for run := 0; run < 10; run++ {
+		s := New(1, 10, 0, time.Second, 0)
+		// a blow ball of tips with the same weight
+		for i := 0; i < 5; i++ {
+			s.onNewSolidTail(testTailHash(i), hornet.NullHashBytes, hornet.NullHashBytes)
+		}
 
-	var wg sync.WaitGroup
-	selector := func() {
-		defer wg.Done()
-		_, err := hps.selectTip(context.Background())
-		assert.NoError(t, err)
+		tips, err := s.SelectTips(0)
+		require.NoError(t, err)
+		// the tips which were tracked first are selected first
+		assert.Equal(t, hornet.Hashes{testTailHash(0), testTailHash(1), testTailHash(2), testTailHash(3), testTailHash(4)}, tips)
 	}
+}
 
-	wg.Add(2)
-	go selector()
-	go selector()
+func TestHeaviestSelector_RandomTips(t *testing.T) {
+	s := New(1, 1, 2, time.Second, 0)
+	s.randomIndex = func(n int) int { return n - 1 }
 
-	for i := 1000; i < 2000; i++ {
-		bndl := newTestBundle(i, hashes[rand.Intn(len(hashes))], hashes[rand.Intn(len(hashes))])
-		hps.OnNewSolidBundle(bndl)
-		hashes = append(hashes, bndl.GetTailHash())
+	heaviestTip := addChain(s, 0, 10, hornet.NullHashBytes)
+	for i := 10; i < 15; i++ {
+		s.onNewSolidTail(testTailHash(i), hornet.NullHashBytes, hornet.NullHashBytes)
 	}
-	wg.Wait()
-}
 
-func BenchmarkHeaviestSelector_OnNewSolidTransaction(b *testing.B) {
-	hps := New()
-	hashes := []hornet.Hash{hornet.NullHashBytes}
-	data := make([]*tangle.Bundle, numBenchmarkTxs)
-	for i := 0; i < numBenchmarkTxs; i++ {
-		data[i] = newTestBundle(i, hashes[rand.Intn(len(hashes))], hashes[rand.Intn(len(hashes))])
-		hashes = append(hashes, data[i].GetTailHash())
-	}
-	b.ResetTimer()
+	tips, err := s.SelectTips(0)
+	require.NoError(t, err)
+	// the random tips are picked from the remaining tips ordered by their index
+	assert.Equal(t, hornet.Hashes{heaviestTip, testTailHash(14), testTailHash(13)}, tips)
+}
 
-	for i := 0; i < b.N; i++ {
-		hps.OnNewSolidTransaction(data[i%numBenchmarkTxs])
-		if i%numBenchmarkTxs == numBenchmarkTxs-1 {
-			hps.SetRoot(hornet.NullHashBytes)
+func TestHeaviestSelector_TipEvaluationBudget(t *testing.T) {
+	newSelector := func(maxTipEvaluations int) *HeaviestSelector {
+		s := New(1, 10, 0, time.Second, maxTipEvaluations)
+		for i := 0; i < 10; i++ {
+			s.onNewSolidTail(testTailHash(i), hornet.NullHashBytes, hornet.NullHashBytes)
 		}
+		return s
 	}
-}
 
-func BenchmarkHeaviestSelector_SelectTips(b *testing.B) {
-	hps := New()
-	hashes := []hornet.Hash{hornet.NullHashBytes}
-	for i := 0; i < numBenchmarkTxs; i++ {
-		bndl := newTestBundle(i, hashes[rand.Intn(len(hashes))], hashes[rand.Intn(len(hashes))])
-		hps.OnNewSolidBundle(bndl)
-		hashes = append(hashes, bndl.GetTailHash())
-	}
-	b.ResetTimer()
+	tips, err := newSelector(0).SelectTips(0)
+	require.NoError(t, err)
+	assert.Len(t, tips, 10)
 
-	for i := 0; i < b.N; i++ {
-		_, _ = hps.selectTip(context.Background())
-	}
-}
+	// the first round evaluates 19 tips, the second one would need 17 more
+	tips, err = newSelector(36).SelectTips(0)
+	require.NoError(t, err)
+	assert.Len(t, tips, 2)
 
-func newTestBundle(idx int, trunk, branch hornet.Hash) *tangle.Bundle {
-	bndl := tangle.Bundle{
+	tips, err = newSelector(35).SelectTips(0)
+	require.NoError(t, err)
+	assert.Equal(t, hornet.Hashes{testTailHash(0)}, tips)
 
-	}
-	tx := &transaction.Transaction{
-		Hash:              trinary.IntToTrytes(int64(idx), consts.HashTrytesSize),
-		Value:             0,
-		Timestamp:         uint64(idx),
-		TrunkTransaction:  trunk.Trytes(),
-		BranchTransaction: branch.Trytes(),
-	}
-	return hornet.NewTransactionFromTx(tx, nil)
+	// the budget doesn't prevent the minimum amount of tips
+	tips, err = newSelector(1).SelectTips(2)
+	require.NoError(t, err)
+	assert.Len(t, tips, 3)
 }
-*/
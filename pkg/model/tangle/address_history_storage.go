@@ -0,0 +1,134 @@
+package tangle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/objectstorage"
+
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/profile"
+)
+
+var (
+	addressHistoryStore   kvstore.KVStore
+	addressHistoryStorage *objectstorage.ObjectStorage
+
+	// whether the address history is maintained at confirmation ("explorer mode").
+	addressHistoryEnabled bool
+)
+
+func addressHistoryFactory(key []byte) (objectstorage.StorableObject, int, error) {
+	entry := hornet.NewAddressHistoryEntry(key[:49], milestone.Index(binary.BigEndian.Uint32(key[49:53])), key[53:102])
+	return entry, 102, nil
+}
+
+func GetAddressHistoryStorageSize() int {
+	return addressHistoryStorage.GetSize()
+}
+
+func configureAddressHistoryStorage(store kvstore.KVStore, opts profile.CacheOpts) {
+
+	addressHistoryStore = store.WithRealm([]byte{StorePrefixAddressHistory})
+
+	addressHistoryStorage = objectstorage.New(
+		addressHistoryStore,
+		addressHistoryFactory,
+		objectstorage.CacheTime(time.Duration(opts.CacheTimeMs)*time.Millisecond),
+		objectstorage.PersistenceEnabled(true),
+		objectstorage.PartitionKey(49, 4, 49),
+		objectstorage.KeysOnly(true),
+		objectstorage.StoreOnCreation(true),
+		objectstorage.LeakDetectionEnabled(opts.LeakDetectionOptions.Enabled,
+			objectstorage.LeakDetectionOptions{
+				MaxConsumersPerObject: opts.LeakDetectionOptions.MaxConsumersPerObject,
+				MaxConsumerHoldTime:   time.Duration(opts.LeakDetectionOptions.MaxConsumerHoldTimeSec) * time.Second,
+			}),
+	)
+}
+
+// EnableAddressHistory enables the address history, which records every confirmed transaction touching an address.
+func EnableAddressHistory() {
+	addressHistoryEnabled = true
+}
+
+// IsAddressHistoryEnabled returns whether the address history is maintained at confirmation.
+func IsAddressHistoryEnabled() bool {
+	return addressHistoryEnabled
+}
+
+// GetAddressHistory returns up to limit entries of the given address, which were confirmed by milestones
+// within the given range (both inclusive) and follow the given cursor entry. The entries are sorted by milestone index,
+// so the last returned entry can be used as the cursor for the next page. The returned cursor is nil if there are no more entries.
+// the keys of the persisted entries are iterated in order starting at the cursor, so only the requested page is read.
+func GetAddressHistory(address hornet.Hash, fromIndex milestone.Index, toIndex milestone.Index, cursor *hornet.AddressHistoryEntry, limit int) (entries []*hornet.AddressHistoryEntry, nextCursor *hornet.AddressHistoryEntry) {
+
+	prefix := address[:49]
+	start := make([]byte, 53)
+	copy(start, prefix)
+	binary.BigEndian.PutUint32(start[49:53], uint32(fromIndex))
+
+	var cursorKey []byte
+	if cursor != nil {
+		cursorKey = hornet.NewAddressHistoryEntry(address, cursor.GetMilestoneIndex(), cursor.GetTxHash()).ObjectStorageKey()
+		if bytes.Compare(cursorKey, start) > 0 {
+			start = cursorKey
+		}
+	}
+
+	_ = database.IterateKeysFrom(addressHistoryStore, prefix, start, func(key kvstore.Key) bool {
+		msIndex := milestone.Index(binary.BigEndian.Uint32(key[49:53]))
+		if msIndex > toIndex {
+			return false
+		}
+
+		if cursorKey != nil && bytes.Equal(key, cursorKey) {
+			return true
+		}
+
+		if len(entries) == limit {
+			// there is at least one more entry
+			nextCursor = entries[limit-1]
+			return false
+		}
+
+		entries = append(entries, hornet.NewAddressHistoryEntry(key[:49], msIndex, key[53:102]))
+		return true
+	})
+
+	return entries, nextCursor
+}
+
+// AddressHistoryConsumer consumes the given address history entry during looping through all entries in the persistence layer.
+type AddressHistoryConsumer func(address hornet.Hash, msIndex milestone.Index, txHash hornet.Hash) bool
+
+// ForEachAddressHistoryEntry loops over all address history entries.
+func ForEachAddressHistoryEntry(consumer AddressHistoryConsumer, skipCache bool) {
+	addressHistoryStorage.ForEachKeyOnly(func(key []byte) bool {
+		return consumer(key[:49], milestone.Index(binary.BigEndian.Uint32(key[49:53])), key[53:102])
+	}, skipCache)
+}
+
+// StoreAddressHistoryEntry records that the given transaction touching the address was confirmed by the milestone.
+// addressHistory +-0
+func StoreAddressHistoryEntry(address hornet.Hash, msIndex milestone.Index, txHash hornet.Hash) {
+	addressHistoryStorage.Store(hornet.NewAddressHistoryEntry(address, msIndex, txHash)).Release(true)
+}
+
+// DeleteAddressHistoryEntry deletes the address history entry of the given transaction.
+// addressHistory +-0
+func DeleteAddressHistoryEntry(address hornet.Hash, msIndex milestone.Index, txHash hornet.Hash) {
+	addressHistoryStorage.Delete(hornet.NewAddressHistoryEntry(address, msIndex, txHash).ObjectStorageKey())
+}
+
+func ShutdownAddressHistoryStorage() {
+	addressHistoryStorage.Shutdown()
+}
+
+func FlushAddressHistoryStorage() {
+	addressHistoryStorage.Flush()
+}
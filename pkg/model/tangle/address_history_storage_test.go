@@ -0,0 +1,80 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/profile"
+)
+
+func TestAddressHistory(t *testing.T) {
+	configureAddressHistoryStorage(mapdb.NewMapDB(), profile.CacheOpts{})
+	defer ShutdownAddressHistoryStorage()
+
+	address := hornet.HashFromAddressTrytes("GYISMBVRKSCEXXTUPBWTIHRCZIKIRPDYAHAYKMNTPZSCSDNADDWAEUNHKUERZCTVAYJCNFXGTNUH9OGTW")
+	otherAddress := hornet.HashFromAddressTrytes("UDYXTZBE9GZGPM9SSQV9LTZNDLJIZMPUVVXYXFYVBLIEUHLSEWFTKZZLXYRHHWVQV9MNNX9KZC9D9UZWZ")
+
+	txHashes := hornet.Hashes{
+		hornet.HashFromHashTrytes("HZDFGXEBKOBTTEVBAABTDQKXBBAWFXZYBS9WZHHRGUNHBNXBHPCXAEDDWRKUISHKPTYHJQQPGFOXZ9999"),
+		hornet.HashFromHashTrytes("PWMVVQSDHAZBMXYXVLAHMQZFXOSOTRKRVFANFDKDUTPTKDLXGHFBRWSLBJEZMWTUWTGJUDBVFVZRZ9999"),
+		hornet.HashFromHashTrytes("TMVWMOEXTVQMBEOXIUOKGQSCZRXVSHFMDNTYDCXNFJCYDYWGHXTVGQESGSMRGBMYPDHHFNLUKOGVZ9999"),
+	}
+
+	// stored out of order, milestone 256 checks the byte order of the index
+	StoreAddressHistoryEntry(address, 256, txHashes[2])
+	StoreAddressHistoryEntry(address, 2, txHashes[0])
+	StoreAddressHistoryEntry(address, 3, txHashes[1])
+	StoreAddressHistoryEntry(otherAddress, 2, txHashes[0])
+
+	// only the persisted entries are paginated
+	FlushAddressHistoryStorage()
+
+	indexes := func(entries []*hornet.AddressHistoryEntry) []milestone.Index {
+		var result []milestone.Index
+		for _, entry := range entries {
+			assert.Equal(t, address, entry.GetAddress())
+			result = append(result, entry.GetMilestoneIndex())
+		}
+		return result
+	}
+
+	history := func(address hornet.Hash, fromIndex milestone.Index, toIndex milestone.Index) []*hornet.AddressHistoryEntry {
+		entries, nextCursor := GetAddressHistory(address, fromIndex, toIndex, nil, 10)
+		assert.Nil(t, nextCursor)
+		return entries
+	}
+
+	assert.Equal(t, []milestone.Index{2, 3, 256}, indexes(history(address, 0, 1000)))
+	assert.Equal(t, []milestone.Index{3, 256}, indexes(history(address, 3, 256)))
+
+	entries := history(address, 256, 256)
+	require.Len(t, entries, 1)
+	assert.Equal(t, txHashes[2], entries[0].GetTxHash())
+
+	// the pages continue after the cursor
+	page, nextCursor := GetAddressHistory(address, 0, 1000, nil, 2)
+	assert.Equal(t, []milestone.Index{2, 3}, indexes(page))
+	require.NotNil(t, nextCursor)
+	assert.Equal(t, txHashes[1], nextCursor.GetTxHash())
+
+	page, nextCursor = GetAddressHistory(address, 0, 1000, nextCursor, 2)
+	assert.Equal(t, []milestone.Index{256}, indexes(page))
+	assert.Nil(t, nextCursor)
+
+	// the range limits the pages as well
+	page, nextCursor = GetAddressHistory(address, 0, 3, hornet.NewAddressHistoryEntry(address, 2, txHashes[0]), 2)
+	assert.Equal(t, []milestone.Index{3}, indexes(page))
+	assert.Nil(t, nextCursor)
+
+	DeleteAddressHistoryEntry(address, 3, txHashes[1])
+	FlushAddressHistoryStorage()
+
+	assert.Equal(t, []milestone.Index{2, 256}, indexes(history(address, 0, 1000)))
+	assert.Len(t, history(otherAddress, 0, 1000), 1)
+}
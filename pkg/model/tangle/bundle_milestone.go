@@ -19,6 +19,20 @@ func (bundle *Bundle) setMilestone(milestone bool) {
 	}
 }
 
+// setMilestoneTxsMetadata marks all transactions of a valid milestone bundle as milestone transactions.
+func (bundle *Bundle) setMilestoneTxsMetadata() {
+	msIndex := bundle.GetMilestoneIndex()
+
+	for _, txHash := range bundle.GetTxHashes() {
+		cachedTxMeta := GetCachedTxMetadataOrNil(txHash) // meta +1
+		if cachedTxMeta == nil {
+			continue
+		}
+		cachedTxMeta.GetMetadata().SetMilestone(true, msIndex)
+		cachedTxMeta.Release(true) // meta -1
+	}
+}
+
 func (bundle *Bundle) IsMilestone() bool {
 	bundle.RLock()
 	defer bundle.RUnlock()
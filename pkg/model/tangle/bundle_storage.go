@@ -302,16 +302,27 @@ func AddTransactionToStorage(hornetTx *hornet.Transaction, latestMilestoneIndex
 	// Store the tx in the bundleTransactionsStorage
 	StoreBundleTransaction(cachedTx.GetTransaction().GetBundleHash(), cachedTx.GetTransaction().GetTxHash(), cachedTx.GetTransaction().IsTail()).Release(forceRelease)
 
-	StoreApprover(cachedTx.GetTransaction().GetTrunkHash(), cachedTx.GetTransaction().GetTxHash()).Release(forceRelease)
-	if !bytes.Equal(cachedTx.GetTransaction().GetTrunkHash(), cachedTx.GetTransaction().GetBranchHash()) {
-		StoreApprover(cachedTx.GetTransaction().GetBranchHash(), cachedTx.GetTransaction().GetTxHash()).Release(forceRelease)
+	// Store the reverse edges (parent -> approver) for every distinct parent of the tx
+	parents := cachedTx.GetTransaction().GetParents()
+	for i, parent := range parents {
+		if parents[:i].Contains(parent) {
+			continue
+		}
+		StoreApprover(parent, cachedTx.GetTransaction().GetTxHash()).Release(forceRelease)
 	}
 
-	// Force release Tag, Address, UnconfirmedTx since its not needed for solidification/confirmation
+	// Force release Tag, Address, TransactionType, UnconfirmedTx since its not needed for solidification/confirmation
 	StoreTag(cachedTx.GetTransaction().GetTag(), cachedTx.GetTransaction().GetTxHash()).Release(true)
 
 	StoreAddress(cachedTx.GetTransaction().GetAddress(), cachedTx.GetTransaction().GetTxHash(), cachedTx.GetTransaction().IsValue()).Release(true)
 
+	// the type of possible milestone transactions is stored after their bundle was checked,
+	// unless the transaction is reapplied and already known to be part of a verified milestone.
+	maybeMilestoneTx := IsMaybeMilestoneTx(cachedTx.Retain()) // tx pass +1
+	if !maybeMilestoneTx || cachedTx.GetMetadata().IsMilestone() {
+		StoreTransactionType(GetTransactionType(cachedTx.GetTransaction(), cachedTx.GetMetadata()), latestMilestoneIndex, cachedTx.GetTransaction().GetTxHash()).Release(true)
+	}
+
 	// Store only non-requested transactions, since all requested transactions are confirmed by a milestone anyway
 	// This is only used to delete unconfirmed transactions from the database at pruning
 	if !requested {
@@ -320,16 +331,16 @@ func AddTransactionToStorage(hornetTx *hornet.Transaction, latestMilestoneIndex
 
 	// If the transaction is part of a milestone, the bundle must be created here
 	// Otherwise, bundles are created if tailTx becomes solid
-	if IsMaybeMilestoneTx(cachedTx.Retain()) { // tx pass +1
-		tryConstructBundle(cachedTx.Retain(), false)
+	if maybeMilestoneTx {
+		tryConstructBundle(cachedTx.Retain(), false, latestMilestoneIndex)
 	}
 
 	return cachedTx, false
 }
 
 // tryConstructBundle tries to construct a bundle (maybe txs are still missing in the DB)
-// isSolidTail should only be false for possible milestone txs
-func tryConstructBundle(cachedTx *CachedTransaction, isSolidTail bool) {
+// isSolidTail should only be false for possible milestone txs, latestMilestoneIndex is only used in that case.
+func tryConstructBundle(cachedTx *CachedTransaction, isSolidTail bool, latestMilestoneIndex milestone.Index) {
 	defer cachedTx.Release() // tx -1
 
 	if ContainsBundle(cachedTx.GetTransaction().GetTxHash()) {
@@ -346,8 +357,8 @@ func tryConstructBundle(cachedTx *CachedTransaction, isSolidTail bool) {
 				continue
 			}
 
-			tryConstructBundle(cachedTailTx.Retain(), false) // tx pass +1
-			cachedTailTx.Release()                           // tx -1
+			tryConstructBundle(cachedTailTx.Retain(), false, latestMilestoneIndex) // tx pass +1
+			cachedTailTx.Release()                                                 // tx -1
 		}
 		return
 	}
@@ -376,6 +387,11 @@ func tryConstructBundle(cachedTx *CachedTransaction, isSolidTail bool) {
 		}
 	}
 
+	if !isSolidTail {
+		// the possible milestone transactions of the complete bundle are typed after the milestone was stored
+		defer storeBundleTransactionTypes(bndl.GetTxHashes(), latestMilestoneIndex)
+	}
+
 	isMilestone, err := CheckIfMilestone(bndl)
 	if err != nil {
 		// Invalid milestone
@@ -478,5 +494,5 @@ func constructBundle(bndl *Bundle, cachedStartTxMeta *CachedMetadata) bool {
 
 // Create a new bundle instance as soon as a tailTx gets solid
 func OnTailTransactionSolid(cachedTx *CachedTransaction) {
-	tryConstructBundle(cachedTx, true) // tx +-0 (it has +1 and will be released in tryConstructBundle)
+	tryConstructBundle(cachedTx, true, 0) // tx +-0 (it has +1 and will be released in tryConstructBundle)
 }
@@ -0,0 +1,49 @@
+package tangle
+
+import (
+	"github.com/gohornet/hornet/pkg/profile"
+)
+
+var (
+	// the cache options the object storages were configured with.
+	cacheOpts profile.Caches
+)
+
+// CacheStatistics holds the size and the configuration of the cache of an object storage.
+type CacheStatistics struct {
+	// Name is the name of the object storage.
+	Name string `json:"name"`
+	// Size is the amount of objects in the cache.
+	Size int `json:"size"`
+	// CacheTimeMs is the time objects are kept in the cache after they were released.
+	CacheTimeMs uint64 `json:"cacheTimeMs"`
+	// LeakDetection is true if the leak detection is enabled.
+	LeakDetection bool `json:"leakDetection"`
+}
+
+func newCacheStatistics(name string, size int, opts profile.CacheOpts) *CacheStatistics {
+	return &CacheStatistics{
+		Name:          name,
+		Size:          size,
+		CacheTimeMs:   opts.CacheTimeMs,
+		LeakDetection: opts.LeakDetectionOptions.Enabled,
+	}
+}
+
+// GetCacheStatistics returns the statistics of the caches of all tangle object storages.
+func GetCacheStatistics() []*CacheStatistics {
+	return []*CacheStatistics{
+		newCacheStatistics("transactions", GetTransactionStorageSize(), cacheOpts.Transactions),
+		newCacheStatistics("transaction_metadata", GetTransactionMetadataStorageSize(), cacheOpts.Transactions),
+		newCacheStatistics("bundles", GetBundleStorageSize(), cacheOpts.Bundles),
+		newCacheStatistics("bundle_transactions", GetBundleTransactionsStorageSize(), cacheOpts.BundleTransactions),
+		newCacheStatistics("approvers", GetApproversStorageSize(), cacheOpts.Approvers),
+		newCacheStatistics("tags", GetTagsStorageSize(), cacheOpts.Tags),
+		newCacheStatistics("addresses", GetAddressesStorageSize(), cacheOpts.Addresses),
+		newCacheStatistics("address_history", GetAddressHistoryStorageSize(), cacheOpts.Addresses),
+		newCacheStatistics("milestones", GetMilestoneStorageSize(), cacheOpts.Milestones),
+		newCacheStatistics("unconfirmed_transactions", GetUnconfirmedTxStorageSize(), cacheOpts.UnconfirmedTx),
+		newCacheStatistics("transaction_types", GetTransactionTypesStorageSize(), cacheOpts.UnconfirmedTx),
+		newCacheStatistics("spent_addresses", GetSpentAddressesStorageSize(), cacheOpts.SpentAddresses),
+	}
+}
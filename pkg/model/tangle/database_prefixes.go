@@ -1,5 +1,14 @@
 package tangle
 
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+)
+
 const (
 	StorePrefixHealth                  byte = 0
 	StorePrefixTransactions            byte = 1
@@ -18,4 +27,156 @@ const (
 	StorePrefixUnconfirmedTransactions byte = 14
 	StorePrefixSpentAddresses          byte = 15
 	StorePrefixAutopeering             byte = 16
+	StorePrefixTransactionTypes        byte = 17
+	StorePrefixAddressHistory          byte = 18
+	StorePrefixEventLog                byte = 19
+	StorePrefixRetainedBundles         byte = 20
+)
+
+const (
+	// AutopeeringDbFilename is the name of the database of the autopeering plugin.
+	AutopeeringDbFilename = "peer.db"
 )
+
+var (
+	// ErrStorePrefixCollision is returned if two stores are registered with the same prefix or name.
+	ErrStorePrefixCollision = errors.New("store prefix collision")
+
+	// the registry of all stores of the node.
+	// new stores must be added here, so that their prefix is checked against the prefixes of the other stores.
+	storePrefixes = []*StorePrefix{
+		{StorePrefixHealth, "health", TangleDbFilename},
+		{StorePrefixTransactions, "transactions", TangleDbFilename},
+		{StorePrefixTransactionMetadata, "transactionMetadata", TangleDbFilename},
+		{StorePrefixBundleTransactions, "bundleTransactions", TangleDbFilename},
+		{StorePrefixBundles, "bundles", TangleDbFilename},
+		{StorePrefixAddresses, "addresses", TangleDbFilename},
+		{StorePrefixMilestones, "milestones", TangleDbFilename},
+		{StorePrefixLedgerState, "ledgerState", TangleDbFilename},
+		{StorePrefixLedgerBalance, "ledgerBalance", TangleDbFilename},
+		{StorePrefixLedgerDiff, "ledgerDiff", TangleDbFilename},
+		{StorePrefixApprovers, "approvers", TangleDbFilename},
+		{StorePrefixTags, "tags", TangleDbFilename},
+		{StorePrefixSnapshot, "snapshot", SnapshotDbFilename},
+		{StorePrefixSnapshotLedger, "snapshotLedger", SnapshotDbFilename},
+		{StorePrefixUnconfirmedTransactions, "unconfirmedTransactions", TangleDbFilename},
+		{StorePrefixSpentAddresses, "spentAddresses", SpentAddressesDbFilename},
+		{StorePrefixAutopeering, "autopeering", AutopeeringDbFilename},
+		{StorePrefixTransactionTypes, "transactionTypes", TangleDbFilename},
+		{StorePrefixAddressHistory, "addressHistory", TangleDbFilename},
+		{StorePrefixEventLog, "eventLog", TangleDbFilename},
+		{StorePrefixRetainedBundles, "retainedBundles", TangleDbFilename},
+	}
+
+	// the prefixes with entries which are not registered for the database they were found in, keyed by database.
+	unknownStorePrefixes map[string][]byte
+)
+
+// StorePrefix describes a store of the node, which lives in a single byte realm of one of the databases.
+type StorePrefix struct {
+	Prefix byte
+	Name   string
+	// the file name of the database the store lives in.
+	Database string
+}
+
+func (p *StorePrefix) String() string {
+	return fmt.Sprintf("%d (%s in %s)", p.Prefix, p.Name, p.Database)
+}
+
+// StorePrefixes returns the registered stores ordered by their prefix.
+func StorePrefixes() []*StorePrefix {
+	prefixes := make([]*StorePrefix, len(storePrefixes))
+	copy(prefixes, storePrefixes)
+	sort.Slice(prefixes, func(i, j int) bool { return prefixes[i].Prefix < prefixes[j].Prefix })
+	return prefixes
+}
+
+// UnknownStorePrefixes returns the prefixes with entries which are not registered for the database they were found in,
+// keyed by the file name of the database. It is only set after CheckStorePrefixes was called.
+func UnknownStorePrefixes() map[string][]byte {
+	return unknownStorePrefixes
+}
+
+// checks that every prefix and every name is only registered once.
+// the prefixes are unique across all databases, so that the stores can be moved between the databases.
+func validateStorePrefixes(prefixes []*StorePrefix) error {
+	byPrefix := make(map[byte]*StorePrefix)
+	byName := make(map[string]*StorePrefix)
+
+	for _, prefix := range prefixes {
+		if other, exists := byPrefix[prefix.Prefix]; exists {
+			return errors.Wrapf(ErrStorePrefixCollision, "prefix %d is used by %s and %s", prefix.Prefix, other.Name, prefix.Name)
+		}
+		if other, exists := byName[prefix.Name]; exists {
+			return errors.Wrapf(ErrStorePrefixCollision, "name %s is used by prefix %d and %d", prefix.Name, other.Prefix, prefix.Prefix)
+		}
+		byPrefix[prefix.Prefix] = prefix
+		byName[prefix.Name] = prefix
+	}
+	return nil
+}
+
+// returns the prefixes of the realms of the given store which contain entries, but are not registered for the database.
+func findUnknownStorePrefixes(store kvstore.KVStore, database string, prefixes []*StorePrefix) ([]byte, error) {
+	registered := make(map[byte]struct{})
+	for _, prefix := range prefixes {
+		if prefix.Database == database {
+			registered[prefix.Prefix] = struct{}{}
+		}
+	}
+
+	var unknown []byte
+	for realm := 0; realm <= 255; realm++ {
+		if _, exists := registered[byte(realm)]; exists {
+			continue
+		}
+
+		hasEntries := false
+		if err := store.WithRealm([]byte{byte(realm)}).IterateKeys(kvstore.EmptyPrefix, func(_ kvstore.Key) bool {
+			hasEntries = true
+			return false
+		}); err != nil {
+			return nil, errors.Wrapf(err, "unable to read realm %d of %s", realm, database)
+		}
+
+		if hasEntries {
+			unknown = append(unknown, byte(realm))
+		}
+	}
+	return unknown, nil
+}
+
+// CheckStorePrefixes validates the registry of the store prefixes and searches the opened databases
+// for entries with prefixes which are not registered for them, e.g. written by another version of the node.
+// Returns an error if the registry contains collisions. The unknown prefixes are returned keyed by database.
+func CheckStorePrefixes() (map[string][]byte, error) {
+	if err := validateStorePrefixes(storePrefixes); err != nil {
+		return nil, err
+	}
+
+	stores := map[string]kvstore.KVStore{}
+	if tangleDb != nil {
+		stores[TangleDbFilename] = tangleDb.KVStore()
+	}
+	if snapshotDb != nil {
+		stores[SnapshotDbFilename] = snapshotDb.KVStore()
+	}
+	if spentDb != nil {
+		stores[SpentAddressesDbFilename] = spentDb.KVStore()
+	}
+
+	unknown := make(map[string][]byte)
+	for database, store := range stores {
+		prefixes, err := findUnknownStorePrefixes(store, database, storePrefixes)
+		if err != nil {
+			return nil, err
+		}
+		if len(prefixes) > 0 {
+			unknown[database] = prefixes
+		}
+	}
+	unknownStorePrefixes = unknown
+
+	return unknown, nil
+}
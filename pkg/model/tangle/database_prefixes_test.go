@@ -0,0 +1,34 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+)
+
+func TestStorePrefixes(t *testing.T) {
+	require.NoError(t, validateStorePrefixes(storePrefixes))
+
+	collision := append(StorePrefixes(), &StorePrefix{StorePrefixEventLog, "newStore", TangleDbFilename})
+	assert.True(t, errors.Is(validateStorePrefixes(collision), ErrStorePrefixCollision))
+
+	collision = append(StorePrefixes(), &StorePrefix{200, "eventLog", TangleDbFilename})
+	assert.True(t, errors.Is(validateStorePrefixes(collision), ErrStorePrefixCollision))
+
+	store := mapdb.NewMapDB()
+	require.NoError(t, store.WithRealm([]byte{StorePrefixTransactions}).Set([]byte("tx"), []byte{1}))
+	unknown, err := findUnknownStorePrefixes(store, TangleDbFilename, storePrefixes)
+	require.NoError(t, err)
+	assert.Empty(t, unknown)
+
+	// the snapshot store doesn't belong into the tangle database
+	require.NoError(t, store.WithRealm([]byte{StorePrefixSnapshot}).Set([]byte("snapshotInfo"), []byte{1}))
+	require.NoError(t, store.WithRealm([]byte{200}).Set([]byte("unknown"), []byte{1}))
+	unknown, err = findUnknownStorePrefixes(store, TangleDbFilename, storePrefixes)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{StorePrefixSnapshot, 200}, unknown)
+}
@@ -0,0 +1,61 @@
+package tangle
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DurabilityMode defines when the changes to the ledger are synced to disk.
+type DurabilityMode string
+
+const (
+	// DurabilityModePeriodic syncs the databases to disk in an interval.
+	// Confirmations since the last sync may be lost on a power loss or an OS crash,
+	// the ledger is then revalidated and the lost milestones are solidified again.
+	DurabilityModePeriodic DurabilityMode = "periodic"
+	// DurabilityModeConfirmation syncs the database to disk after the ledger changes of every confirmed milestone.
+	// No confirmation is lost, at the cost of at least one sync per milestone.
+	DurabilityModeConfirmation DurabilityMode = "confirmation"
+)
+
+var (
+	// ErrUnknownDurabilityMode is returned if the name of a durability mode is unknown.
+	ErrUnknownDurabilityMode = errors.New("unknown durability mode")
+
+	durabilityMode = DurabilityModePeriodic
+)
+
+// DurabilityModeFromString parses the given durability mode name.
+func DurabilityModeFromString(name string) (DurabilityMode, error) {
+	mode := DurabilityMode(strings.ToLower(name))
+	switch mode {
+	case DurabilityModePeriodic, DurabilityModeConfirmation:
+		return mode, nil
+	default:
+		return "", errors.Wrapf(ErrUnknownDurabilityMode, "%s", name)
+	}
+}
+
+// SetDurabilityMode sets when the changes to the ledger are synced to disk.
+func SetDurabilityMode(mode DurabilityMode) {
+	durabilityMode = mode
+}
+
+// GetDurabilityMode returns when the changes to the ledger are synced to disk.
+func GetDurabilityMode() DurabilityMode {
+	return durabilityMode
+}
+
+// SyncLedgerAfterConfirmation syncs the tangle database which holds the ledger to disk,
+// if the durability mode requires every confirmation to be persisted.
+func SyncLedgerAfterConfirmation() error {
+	if durabilityMode != DurabilityModeConfirmation || tangleDb == nil {
+		return nil
+	}
+
+	if err := tangleDb.Flush(); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to sync the ledger to disk")
+	}
+	return nil
+}
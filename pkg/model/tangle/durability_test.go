@@ -0,0 +1,22 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurabilityModeFromString(t *testing.T) {
+	mode, err := DurabilityModeFromString("Confirmation")
+	require.NoError(t, err)
+	assert.Equal(t, DurabilityModeConfirmation, mode)
+
+	mode, err = DurabilityModeFromString("periodic")
+	require.NoError(t, err)
+	assert.Equal(t, DurabilityModePeriodic, mode)
+
+	_, err = DurabilityModeFromString("never")
+	assert.True(t, errors.Is(err, ErrUnknownDurabilityMode))
+}
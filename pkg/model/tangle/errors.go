@@ -1,6 +1,8 @@
 package tangle
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 )
 
@@ -11,8 +13,16 @@ var (
 	ErrTransactionNotFound = errors.New("transaction not found")
 	// ErrBundleNotFound is returned when a bundle was not found.
 	ErrBundleNotFound = errors.New("bundle not found")
+	// ErrMilestoneNotFound is returned when a milestone was not found.
+	ErrMilestoneNotFound = errors.New("milestone not found")
 	// ErrNodeNotSynced is returned when the node is not synchronized.
 	ErrNodeNotSynced = errors.New("node is not synchronized")
+	// ErrInvalidKeyLength is returned when a key loaded from the database has an unexpected length.
+	ErrInvalidKeyLength = errors.New("invalid database key length")
+	// ErrInvalidValueLength is returned when a value loaded from the database has an unexpected length.
+	ErrInvalidValueLength = errors.New("invalid database value length")
+	// ErrCorruptedEntry is matched by every CorruptedEntryError.
+	ErrCorruptedEntry = errors.New("corrupted database entry")
 )
 
 func NewDatabaseError(cause error) *ErrDatabaseError {
@@ -30,3 +40,35 @@ func (e ErrDatabaseError) Cause() error {
 func (e ErrDatabaseError) Error() string {
 	return "database error: " + e.Inner.Error()
 }
+
+// NewCorruptedEntryError returns an error for the given corrupted entry of a storage.
+func NewCorruptedEntryError(storage string, key []byte, cause error) *CorruptedEntryError {
+	return &CorruptedEntryError{Storage: storage, Key: append([]byte{}, key...), Inner: cause}
+}
+
+// CorruptedEntryError is returned when an entry loaded from the database can't be parsed.
+type CorruptedEntryError struct {
+	// the name of the storage the entry belongs to.
+	Storage string
+	// the key of the entry.
+	Key []byte
+	// the reason why the entry can't be parsed.
+	Inner error
+}
+
+func (e CorruptedEntryError) Cause() error {
+	return e.Inner
+}
+
+func (e CorruptedEntryError) Unwrap() error {
+	return e.Inner
+}
+
+// Is reports whether the target is ErrCorruptedEntry.
+func (e CorruptedEntryError) Is(target error) bool {
+	return target == ErrCorruptedEntry
+}
+
+func (e CorruptedEntryError) Error() string {
+	return fmt.Sprintf("corrupted %s entry %x: %s", e.Storage, e.Key, e.Inner.Error())
+}
@@ -0,0 +1,197 @@
+package tangle
+
+import (
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/syncutils"
+)
+
+const (
+	// the length of the index of an event log entry in the key.
+	eventLogKeyLength = 8
+	// the length of the timestamp and the length of the type of an event log entry in the value.
+	eventLogValueMinLength = 8 + 1
+	// the maximum length of the type of an event log entry.
+	eventLogMaxTypeLength = 255
+)
+
+var (
+	// eventLogStore contains the event log entries by index in a ring buffer of eventLogCapacity entries.
+	eventLogStore     kvstore.KVStore
+	eventLogNextIndex uint64
+	eventLogCapacity  uint64 = 1000
+	eventLogLock      syncutils.Mutex
+)
+
+// EventLogEntry is an operational incident of the node stored in the event log, e.g. a dropped peer or a pruning run.
+type EventLogEntry struct {
+	// Index is the sequence number of the entry, which increases with every added entry.
+	Index     uint64
+	Timestamp time.Time
+	Type      string
+	Message   string
+}
+
+func configureEventLogStore(store kvstore.KVStore) {
+	eventLogStore = store.WithRealm([]byte{StorePrefixEventLog})
+
+	eventLogLock.Lock()
+	defer eventLogLock.Unlock()
+
+	eventLogNextIndex = 0
+	if err := eventLogStore.IterateKeys([]byte{}, func(key kvstore.Key) bool {
+		if len(key) != eventLogKeyLength {
+			return true
+		}
+		if index := binary.BigEndian.Uint64(key); index >= eventLogNextIndex {
+			eventLogNextIndex = index + 1
+		}
+		return true
+	}); err != nil {
+		panic(errors.Wrap(NewDatabaseError(err), "failed to load event log"))
+	}
+}
+
+func eventLogKey(index uint64) []byte {
+	key := make([]byte, eventLogKeyLength)
+	binary.BigEndian.PutUint64(key, index)
+	return key
+}
+
+func (e *EventLogEntry) value() []byte {
+	value := make([]byte, eventLogValueMinLength, eventLogValueMinLength+len(e.Type)+len(e.Message))
+	binary.LittleEndian.PutUint64(value[:8], uint64(e.Timestamp.UnixNano()))
+	value[8] = byte(len(e.Type))
+	value = append(value, e.Type...)
+	return append(value, e.Message...)
+}
+
+func eventLogEntryFromDatabase(key []byte, value []byte) (*EventLogEntry, error) {
+	if len(key) != eventLogKeyLength {
+		return nil, errors.Wrapf(ErrInvalidKeyLength, "%d bytes", len(key))
+	}
+	if len(value) < eventLogValueMinLength || len(value) < eventLogValueMinLength+int(value[8]) {
+		return nil, errors.Wrapf(ErrInvalidValueLength, "%d bytes", len(value))
+	}
+
+	typeEnd := eventLogValueMinLength + int(value[8])
+	return &EventLogEntry{
+		Index:     binary.BigEndian.Uint64(key),
+		Timestamp: time.Unix(0, int64(binary.LittleEndian.Uint64(value[:8]))),
+		Type:      string(value[eventLogValueMinLength:typeEnd]),
+		Message:   string(value[typeEnd:]),
+	}, nil
+}
+
+// SetEventLogCapacity sets the maximum number of entries in the event log, older entries are removed.
+func SetEventLogCapacity(capacity int) error {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	eventLogLock.Lock()
+	defer eventLogLock.Unlock()
+
+	eventLogCapacity = uint64(capacity)
+	if eventLogNextIndex <= eventLogCapacity {
+		return nil
+	}
+
+	var obsoleteKeys [][]byte
+	oldestIndex := eventLogNextIndex - eventLogCapacity
+	if err := eventLogStore.IterateKeys([]byte{}, func(key kvstore.Key) bool {
+		if len(key) != eventLogKeyLength || binary.BigEndian.Uint64(key) < oldestIndex {
+			obsoleteKeys = append(obsoleteKeys, append([]byte{}, key...))
+		}
+		return true
+	}); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to trim event log")
+	}
+
+	for _, key := range obsoleteKeys {
+		if err := eventLogStore.Delete(key); err != nil {
+			return errors.Wrap(NewDatabaseError(err), "failed to trim event log")
+		}
+	}
+
+	return nil
+}
+
+// AddEventLogEntry adds an entry of the given type to the event log and removes the oldest entry if the event log is full.
+func AddEventLogEntry(entryType string, message string) (*EventLogEntry, error) {
+	if len(entryType) > eventLogMaxTypeLength {
+		entryType = entryType[:eventLogMaxTypeLength]
+	}
+
+	eventLogLock.Lock()
+	defer eventLogLock.Unlock()
+
+	entry := &EventLogEntry{
+		Index:     eventLogNextIndex,
+		Timestamp: time.Now(),
+		Type:      entryType,
+		Message:   message,
+	}
+
+	if err := eventLogStore.Set(eventLogKey(entry.Index), entry.value()); err != nil {
+		return nil, errors.Wrap(NewDatabaseError(err), "failed to store event log entry")
+	}
+	eventLogNextIndex++
+
+	if entry.Index >= eventLogCapacity {
+		if err := eventLogStore.Delete(eventLogKey(entry.Index - eventLogCapacity)); err != nil {
+			return nil, errors.Wrap(NewDatabaseError(err), "failed to remove event log entry")
+		}
+	}
+
+	return entry, nil
+}
+
+// GetEventLogEntries returns the entries of the event log which were added at or after the given time, ordered by index.
+// Only entries of the given types are returned, if any are given.
+func GetEventLogEntries(since time.Time, entryTypes ...string) ([]*EventLogEntry, error) {
+	types := make(map[string]struct{}, len(entryTypes))
+	for _, entryType := range entryTypes {
+		types[entryType] = struct{}{}
+	}
+
+	eventLogLock.Lock()
+	defer eventLogLock.Unlock()
+
+	var entries []*EventLogEntry
+	var innerErr error
+	if err := eventLogStore.Iterate([]byte{}, func(key kvstore.Key, value kvstore.Value) bool {
+		entry, err := eventLogEntryFromDatabase(key, value)
+		if err != nil {
+			innerErr = err
+			return false
+		}
+
+		if entry.Timestamp.Before(since) {
+			return true
+		}
+		if _, exists := types[entry.Type]; len(types) > 0 && !exists {
+			return true
+		}
+
+		entries = append(entries, entry)
+		return true
+	}); err != nil {
+		return nil, errors.Wrap(NewDatabaseError(err), "failed to read event log")
+	}
+
+	if innerErr != nil {
+		return nil, errors.Wrap(NewDatabaseError(innerErr), "failed to read event log")
+	}
+
+	sort.Slice(entries, func(i int, j int) bool {
+		return entries[i].Index < entries[j].Index
+	})
+
+	return entries, nil
+}
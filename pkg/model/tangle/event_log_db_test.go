@@ -0,0 +1,67 @@
+package tangle
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+)
+
+func TestEventLog(t *testing.T) {
+	store := mapdb.NewMapDB()
+	configureEventLogStore(store)
+	require.NoError(t, SetEventLogCapacity(3))
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		entryType := "peer"
+		if i%2 == 1 {
+			entryType = "pruning"
+		}
+		entry, err := AddEventLogEntry(entryType, fmt.Sprintf("entry %d", i))
+		require.NoError(t, err)
+		require.Equal(t, uint64(i), entry.Index)
+	}
+
+	// only the newest entries are kept
+	entries, err := GetEventLogEntries(start)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for i, entry := range entries {
+		assert.Equal(t, uint64(i+2), entry.Index)
+		assert.Equal(t, fmt.Sprintf("entry %d", i+2), entry.Message)
+	}
+
+	entries, err = GetEventLogEntries(start, "pruning")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "entry 3", entries[0].Message)
+
+	entries, err = GetEventLogEntries(time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// the index continues after a restart and lowering the capacity removes the oldest entries
+	configureEventLogStore(store)
+	require.NoError(t, SetEventLogCapacity(2))
+
+	entry, err := AddEventLogEntry("peer", "entry 5")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), entry.Index)
+
+	entries, err = GetEventLogEntries(start)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "entry 4", entries[0].Message)
+	assert.Equal(t, "entry 5", entries[1].Message)
+
+	// corrupted entries are returned as errors
+	require.NoError(t, eventLogStore.Set(eventLogKey(6), []byte{1, 2, 3}))
+	_, err = GetEventLogEntries(start)
+	assert.True(t, errors.Is(errors.Cause(err), ErrInvalidValueLength))
+}
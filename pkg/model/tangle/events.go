@@ -2,16 +2,57 @@ package tangle
 
 import (
 	"github.com/iotaledger/hive.go/events"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
 )
 
+func init() {
+	hornet.SetMetadataChangedFunc(triggerMetadataEvent)
+}
+
+// TransactionMetadataCaller is the caller of the metadata events.
+// The metadata is only valid during the execution of the handler, it must not be retained.
+func TransactionMetadataCaller(handler interface{}, params ...interface{}) {
+	handler.(func(metadata *hornet.TransactionMetadata))(params[0].(*hornet.TransactionMetadata))
+}
+
 var Events = packageEvents{
-	ReceivedValidMilestone:   events.NewEvent(BundleCaller),
-	ReceivedInvalidMilestone: events.NewEvent(events.ErrorCaller),
-	AddressSpent:             events.NewEvent(events.StringCaller),
+	ReceivedValidMilestone:         events.NewEvent(BundleCaller),
+	ReceivedInvalidMilestone:       events.NewEvent(events.ErrorCaller),
+	AddressSpent:                   events.NewEvent(events.StringCaller),
+	TransactionMetadataSolid:       events.NewEvent(TransactionMetadataCaller),
+	TransactionMetadataConfirmed:   events.NewEvent(TransactionMetadataCaller),
+	TransactionMetadataConflicting: events.NewEvent(TransactionMetadataCaller),
+	RootSnapshotIndexesUpdated:     events.NewEvent(TransactionMetadataCaller),
+	CorruptedEntryQuarantined:      events.NewEvent(CorruptedEntryCaller),
 }
 
 type packageEvents struct {
 	ReceivedValidMilestone   *events.Event
 	ReceivedInvalidMilestone *events.Event
 	AddressSpent             *events.Event
+	// TransactionMetadataSolid is triggered when a transaction became solid.
+	TransactionMetadataSolid *events.Event
+	// TransactionMetadataConfirmed is triggered when a transaction was confirmed (referenced) by a milestone.
+	TransactionMetadataConfirmed *events.Event
+	// TransactionMetadataConflicting is triggered when a transaction was marked as conflicting by the white-flag confirmation.
+	TransactionMetadataConflicting *events.Event
+	// RootSnapshotIndexesUpdated is triggered when the youngest or oldest root snapshot index of a transaction changed.
+	RootSnapshotIndexesUpdated *events.Event
+	// CorruptedEntryQuarantined is triggered when a corrupted entry was skipped while loading it from the database.
+	CorruptedEntryQuarantined *events.Event
+}
+
+// triggerMetadataEvent triggers the event matching the state change of the metadata.
+func triggerMetadataEvent(metadata *hornet.TransactionMetadata, change hornet.MetadataChange) {
+	switch change {
+	case hornet.MetadataChangeSolid:
+		Events.TransactionMetadataSolid.Trigger(metadata)
+	case hornet.MetadataChangeConfirmed:
+		Events.TransactionMetadataConfirmed.Trigger(metadata)
+	case hornet.MetadataChangeConflicting:
+		Events.TransactionMetadataConflicting.Trigger(metadata)
+	case hornet.MetadataChangeRootSnapshotIndexes:
+		Events.RootSnapshotIndexesUpdated.Trigger(metadata)
+	}
 }
@@ -0,0 +1,86 @@
+//go:build gofuzz
+// +build gofuzz
+
+package tangle
+
+import (
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+// splits the fuzzer input into a key and a value, the first byte is the length of the key.
+func splitFuzzInput(data []byte) (key []byte, value []byte, ok bool) {
+	if len(data) == 0 || int(data[0]) > len(data)-1 {
+		return nil, nil, false
+	}
+	keyLength := int(data[0])
+	return data[1 : 1+keyLength], data[1+keyLength:], true
+}
+
+// FuzzMetadataFactory is the go-fuzz target for loading transaction metadata from the database.
+// Build with "go-fuzz-build -func FuzzMetadataFactory".
+func FuzzMetadataFactory(data []byte) int {
+	key, value, ok := splitFuzzInput(data)
+	if !ok {
+		return -1
+	}
+
+	object, _, err := metadataFactory(key)
+	if err != nil {
+		return 0
+	}
+
+	if _, err := object.UnmarshalObjectStorageValue(value); err != nil {
+		return 0
+	}
+
+	return 1
+}
+
+// FuzzSpentAddressFactory is the go-fuzz target for loading spent addresses from the database.
+// Build with "go-fuzz-build -func FuzzSpentAddressFactory".
+func FuzzSpentAddressFactory(data []byte) int {
+	if _, _, err := spentAddressFactory(data); err != nil {
+		return 0
+	}
+	return 1
+}
+
+// FuzzLedgerEntry is the go-fuzz target for loading the ledger balances and diffs from the database.
+// Build with "go-fuzz-build -func FuzzLedgerEntry".
+func FuzzLedgerEntry(data []byte) int {
+	key, value, ok := splitFuzzInput(data)
+	if !ok || len(key) == 0 {
+		return -1
+	}
+
+	store := mapdb.NewMapDB()
+	m, err := NewLedgerManager(store)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := m.ledgerBalanceStore.Set(key, value); err != nil {
+		panic(err)
+	}
+	if err := m.ledgerDiffStore.Set(key, value); err != nil {
+		panic(err)
+	}
+
+	result := 1
+	if _, _, err := m.readBalancesWithoutLocking(nil); err != nil {
+		result = 0
+	}
+
+	if len(key) >= 4 {
+		if _, _, err := m.readLedgerDiffWithoutLocking(milestoneIndexFromBytes(key[:4]), nil); err != nil {
+			result = 0
+		}
+	}
+
+	m.ForEachLedgerDiffHash(func(_ milestone.Index, _ hornet.Hash) bool { return true }, true)
+
+	return result
+}
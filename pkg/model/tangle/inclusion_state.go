@@ -0,0 +1,131 @@
+package tangle
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+// BundleInclusionState is the inclusion state of a single bundle instance (reattachment) of a transfer.
+type BundleInclusionState struct {
+	// TailHash is the hash of the tail transaction of the bundle instance.
+	TailHash hornet.Hash
+	// LedgerInclusionState is the state of the bundle instance after the white-flag confirmation.
+	LedgerInclusionState hornet.LedgerInclusionState
+	// ConflictReason is the reason why the bundle instance was marked as conflicting.
+	ConflictReason hornet.ConflictReason
+	// ReferencedByMilestoneIndex is the index of the milestone which referenced the bundle instance.
+	ReferencedByMilestoneIndex milestone.Index
+}
+
+// TransferInclusionState is the combined inclusion state of all bundle instances of a transfer.
+type TransferInclusionState struct {
+	// BundleHash is the hash of the bundle the transaction belongs to.
+	BundleHash hornet.Hash
+	// LedgerInclusionState is "included" (or "noTransaction" for zero value transfers) if any bundle instance was included,
+	// "conflicting" if all referenced bundle instances were conflicting, and "notReferenced" if no bundle instance was referenced yet.
+	LedgerInclusionState hornet.LedgerInclusionState
+	// ConflictReason is the reason of the first conflicting bundle instance if the transfer is conflicting.
+	ConflictReason hornet.ConflictReason
+	// ReferencedByMilestoneIndex is the index of the milestone which referenced the included or conflicting bundle instance.
+	ReferencedByMilestoneIndex milestone.Index
+	// InputsSpent is true if the transfer was not referenced yet, but its inputs were already spent by other transfers,
+	// which means that it will be conflicting.
+	InputsSpent bool
+	// Bundles are the inclusion states of the known bundle instances of the transfer.
+	// it is empty if the bundle of the transaction is not complete yet.
+	Bundles []*BundleInclusionState
+}
+
+// GetTransferInclusionState returns the inclusion state of the transfer the given transaction belongs to.
+// the states of all bundle instances (reattachments) of the transfer are combined with the current ledger state.
+func GetTransferInclusionState(txHash hornet.Hash) (*TransferInclusionState, error) {
+
+	cachedTx := GetCachedTransactionOrNil(txHash) // tx +1
+	if cachedTx == nil {
+		return nil, errors.Wrapf(ErrTransactionNotFound, "transaction %s", txHash.Trytes())
+	}
+	bundleHash := cachedTx.GetTransaction().GetBundleHash()
+	cachedTx.Release(true) // tx -1
+
+	state := &TransferInclusionState{
+		BundleHash:           bundleHash,
+		LedgerInclusionState: hornet.LedgerInclusionStateNotReferenced,
+	}
+
+	// the ledger must not change while the inputs are checked
+	Ledger().RLock()
+	defer Ledger().RUnlock()
+
+	cachedBndls := GetBundles(bundleHash, true) // bundle +1
+	defer cachedBndls.Release(true)             // bundle -1
+
+	var unreferencedBundle *Bundle
+	for _, cachedBndl := range cachedBndls {
+		bndl := cachedBndl.GetBundle()
+
+		cachedTailMeta := bndl.GetTailMetadata() // meta +1
+		tailMeta := cachedTailMeta.GetMetadata()
+		_, referencedByIndex := tailMeta.GetReferenced()
+		bundleState := &BundleInclusionState{
+			TailHash:                   bndl.GetTailHash(),
+			LedgerInclusionState:       tailMeta.GetLedgerInclusionState(),
+			ConflictReason:             tailMeta.GetConflictReason(),
+			ReferencedByMilestoneIndex: referencedByIndex,
+		}
+		cachedTailMeta.Release(true) // meta -1
+
+		state.Bundles = append(state.Bundles, bundleState)
+
+		switch bundleState.LedgerInclusionState {
+		case hornet.LedgerInclusionStateIncluded, hornet.LedgerInclusionStateNoTransaction:
+			// an included bundle instance decides the state of the transfer
+			state.LedgerInclusionState = bundleState.LedgerInclusionState
+			state.ConflictReason = hornet.ConflictReasonNone
+			state.ReferencedByMilestoneIndex = bundleState.ReferencedByMilestoneIndex
+
+		case hornet.LedgerInclusionStateConflicting:
+			if state.LedgerInclusionState == hornet.LedgerInclusionStateNotReferenced {
+				state.LedgerInclusionState = bundleState.LedgerInclusionState
+				state.ConflictReason = bundleState.ConflictReason
+				state.ReferencedByMilestoneIndex = bundleState.ReferencedByMilestoneIndex
+			}
+
+		default:
+			if unreferencedBundle == nil && bndl.IsValid() {
+				unreferencedBundle = bndl
+			}
+		}
+	}
+
+	if state.LedgerInclusionState == hornet.LedgerInclusionStateNotReferenced && unreferencedBundle != nil {
+		inputsSpent, err := inputsSpentWithoutLocking(unreferencedBundle.GetLedgerChanges())
+		if err != nil {
+			return nil, err
+		}
+		state.InputsSpent = inputsSpent
+	}
+
+	return state, nil
+}
+
+// checks whether the current balance of any input address of the given ledger changes is not sufficient anymore.
+func inputsSpentWithoutLocking(ledgerChanges map[string]int64) (bool, error) {
+	for addr, change := range ledgerChanges {
+		if change >= 0 {
+			continue
+		}
+
+		balance, _, err := Ledger().GetBalanceForAddressWithoutLocking(hornet.Hash(addr))
+		if err != nil {
+			return false, err
+		}
+
+		if balance < uint64(-change) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,35 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/iota.go/consts"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+func TestInputsSpent(t *testing.T) {
+	genesis := hornet.HashFromAddressTrytes("UDYXTZBE9GZGPM9SSQV9LTZNDLJIZMPUVVXYXFYVBLIEUHLSEWFTKZZLXYRHHWVQV9MNNX9KZC9D9UZWZ")
+	receiver := hornet.HashFromAddressTrytes("GYISMBVRKSCEXXTUPBWTIHRCZIKIRPDYAHAYKMNTPZSCSDNADDWAEUNHKUERZCTVAYJCNFXGTNUH9OGTW")
+
+	manager, err := NewLedgerManager(mapdb.NewMapDB())
+	require.NoError(t, err)
+	require.NoError(t, manager.StoreLedgerBalancesInDatabase(map[string]uint64{string(genesis): consts.TotalSupply - 100, string(receiver): 100}, 1))
+
+	previousLedger := ledger
+	ledger = manager
+	defer func() { ledger = previousLedger }()
+
+	spent, err := inputsSpentWithoutLocking(map[string]int64{string(receiver): -100, string(genesis): 100})
+	require.NoError(t, err)
+	assert.False(t, spent)
+
+	// the receiver doesn't hold enough funds anymore
+	spent, err = inputsSpentWithoutLocking(map[string]int64{string(receiver): -101, string(genesis): 101})
+	require.NoError(t, err)
+	assert.True(t, spent)
+}
@@ -0,0 +1,196 @@
+package tangle
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+const (
+	// the amount of supply distribution buckets, one per decimal magnitude of the balance.
+	// the total supply has 16 decimal digits.
+	supplyDistributionBucketCount = 16
+)
+
+var (
+	// ErrLedgerAnalyticsDisabled is returned if the ledger analytics are queried but not maintained.
+	ErrLedgerAnalyticsDisabled = errors.New("ledger analytics are disabled")
+
+	// whether the ledger analytics of the node's ledger are maintained.
+	ledgerAnalyticsEnabled bool
+)
+
+// EnableLedgerAnalytics maintains an index of the balances of the node's ledger on every confirmation,
+// which answers the richest addresses and the supply distribution without scanning the ledger.
+// It has to be called before the databases are configured.
+func EnableLedgerAnalytics() {
+	ledgerAnalyticsEnabled = true
+}
+
+// IsLedgerAnalyticsEnabled returns whether the ledger analytics of the node's ledger are maintained.
+func IsLedgerAnalyticsEnabled() bool {
+	return ledgerAnalyticsEnabled
+}
+
+// AddressBalance is the balance of an address in the ledger.
+type AddressBalance struct {
+	Address hornet.Hash
+	Balance uint64
+}
+
+// SupplyDistributionBucket holds the addresses whose balance is within a decimal magnitude.
+type SupplyDistributionBucket struct {
+	// The smallest balance of the bucket (inclusive).
+	MinBalance uint64
+	// The biggest balance of the bucket (inclusive).
+	MaxBalance uint64
+	// The amount of addresses in the bucket.
+	AddressCount int
+	// The sum of the balances of the addresses in the bucket.
+	TotalBalance uint64
+}
+
+// ledgerAnalytics indexes the balances of a ledger by their decimal magnitude.
+// The richest addresses are found in the highest buckets, which only hold a few addresses,
+// so a query doesn't have to look at the whole ledger.
+// It is not safe for concurrent use, the lock of the ledger protects it.
+type ledgerAnalytics struct {
+	buckets [supplyDistributionBucketCount]*ledgerAnalyticsBucket
+}
+
+type ledgerAnalyticsBucket struct {
+	balances     map[string]uint64
+	totalBalance uint64
+}
+
+func newLedgerAnalytics(balances map[string]uint64) *ledgerAnalytics {
+	a := &ledgerAnalytics{}
+	for i := range a.buckets {
+		a.buckets[i] = &ledgerAnalyticsBucket{balances: make(map[string]uint64)}
+	}
+
+	for address, balance := range balances {
+		a.update(address, 0, balance)
+	}
+	return a
+}
+
+// returns the index of the bucket of the given balance, which must not be zero.
+func supplyDistributionBucketIndex(balance uint64) int {
+	index := 0
+	for balance >= 10 && index < supplyDistributionBucketCount-1 {
+		balance /= 10
+		index++
+	}
+	return index
+}
+
+// update moves the given address from the bucket of its previous balance to the one of its new balance.
+// Addresses with a zero balance are not part of the ledger.
+func (a *ledgerAnalytics) update(address string, previousBalance uint64, newBalance uint64) {
+	if previousBalance != 0 {
+		bucket := a.buckets[supplyDistributionBucketIndex(previousBalance)]
+		delete(bucket.balances, address)
+		bucket.totalBalance -= previousBalance
+	}
+
+	if newBalance != 0 {
+		bucket := a.buckets[supplyDistributionBucketIndex(newBalance)]
+		bucket.balances[address] = newBalance
+		bucket.totalBalance += newBalance
+	}
+}
+
+// richestAddresses returns up to the given amount of addresses with the highest balances,
+// sorted by balance descending and by address for equal balances.
+func (a *ledgerAnalytics) richestAddresses(count int) []*AddressBalance {
+	result := make([]*AddressBalance, 0)
+
+	for i := len(a.buckets) - 1; i >= 0 && len(result) < count; i-- {
+		bucketBalances := make([]*AddressBalance, 0, len(a.buckets[i].balances))
+		for address, balance := range a.buckets[i].balances {
+			bucketBalances = append(bucketBalances, &AddressBalance{Address: hornet.Hash(address), Balance: balance})
+		}
+
+		// every balance of a bucket is lower than the balances of the buckets above
+		sort.Slice(bucketBalances, func(i, j int) bool {
+			if bucketBalances[i].Balance != bucketBalances[j].Balance {
+				return bucketBalances[i].Balance > bucketBalances[j].Balance
+			}
+			return bytes.Compare(bucketBalances[i].Address, bucketBalances[j].Address) < 0
+		})
+
+		if missing := count - len(result); len(bucketBalances) > missing {
+			bucketBalances = bucketBalances[:missing]
+		}
+		result = append(result, bucketBalances...)
+	}
+
+	return result
+}
+
+// supplyDistribution returns all buckets of the supply distribution, sorted by balance ascending.
+func (a *ledgerAnalytics) supplyDistribution() []*SupplyDistributionBucket {
+	result := make([]*SupplyDistributionBucket, len(a.buckets))
+
+	minBalance := uint64(1)
+	for i, bucket := range a.buckets {
+		result[i] = &SupplyDistributionBucket{
+			MinBalance:   minBalance,
+			MaxBalance:   minBalance*10 - 1,
+			AddressCount: len(bucket.balances),
+			TotalBalance: bucket.totalBalance,
+		}
+		minBalance *= 10
+	}
+
+	return result
+}
+
+// EnableAnalytics builds the analytics index from the balances of the ledger,
+// afterwards it is maintained on every change of the ledger.
+func (m *LedgerManager) EnableAnalytics(abortSignal <-chan struct{}) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	balances, _, err := m.readBalancesWithoutLocking(abortSignal)
+	if err != nil {
+		return errors.Wrap(err, "failed to build ledger analytics")
+	}
+
+	m.analytics = newLedgerAnalytics(balances)
+	return nil
+}
+
+// GetRichestAddresses returns up to the given amount of addresses with the highest balances
+// and the milestone index of the ledger they belong to.
+func (m *LedgerManager) GetRichestAddresses(count int) ([]*AddressBalance, milestone.Index, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	if m.analytics == nil {
+		return nil, m.ledgerMilestoneIndex, ErrLedgerAnalyticsDisabled
+	}
+
+	return m.analytics.richestAddresses(count), m.ledgerMilestoneIndex, nil
+}
+
+// GetSupplyDistribution returns the amount of addresses and their balances per decimal magnitude of the balance
+// and the milestone index of the ledger they belong to.
+func (m *LedgerManager) GetSupplyDistribution() ([]*SupplyDistributionBucket, milestone.Index, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	if m.analytics == nil {
+		return nil, m.ledgerMilestoneIndex, ErrLedgerAnalyticsDisabled
+	}
+
+	return m.analytics.supplyDistribution(), m.ledgerMilestoneIndex, nil
+}
@@ -0,0 +1,87 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/iota.go/consts"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+func TestLedgerAnalytics(t *testing.T) {
+	genesis := hornet.HashFromAddressTrytes("UDYXTZBE9GZGPM9SSQV9LTZNDLJIZMPUVVXYXFYVBLIEUHLSEWFTKZZLXYRHHWVQV9MNNX9KZC9D9UZWZ")
+	receiver := hornet.HashFromAddressTrytes("GYISMBVRKSCEXXTUPBWTIHRCZIKIRPDYAHAYKMNTPZSCSDNADDWAEUNHKUERZCTVAYJCNFXGTNUH9OGTW")
+	other := hornet.HashFromAddressTrytes("ZQISMBVRKSCEXXTUPBWTIHRCZIKIRPDYAHAYKMNTPZSCSDNADDWAEUNHKUERZCTVAYJCNFXGTNUH9OGTW")
+
+	manager, err := NewLedgerManager(mapdb.NewMapDB())
+	require.NoError(t, err)
+
+	_, _, err = manager.GetRichestAddresses(10)
+	assert.True(t, errors.Is(err, ErrLedgerAnalyticsDisabled))
+
+	require.NoError(t, manager.StoreLedgerBalancesInDatabase(map[string]uint64{string(genesis): consts.TotalSupply - 1000, string(receiver): 1000}, 1))
+
+	// the index is built from the stored balances
+	require.NoError(t, manager.EnableAnalytics(nil))
+
+	richest, index, err := manager.GetRichestAddresses(10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, index)
+	assert.Equal(t, []*AddressBalance{
+		{Address: genesis, Balance: consts.TotalSupply - 1000},
+		{Address: receiver, Balance: 1000},
+	}, richest)
+
+	// the index is maintained on every ledger diff, addresses without balance are removed
+	manager.Lock()
+	require.NoError(t, manager.ApplyLedgerDiffWithoutLocking(map[string]int64{string(receiver): -1000, string(other): 1000}, 2))
+	require.NoError(t, manager.ApplyLedgerDiffWithoutLocking(map[string]int64{string(genesis): -5, string(other): 5}, 3))
+	manager.Unlock()
+
+	richest, index, err = manager.GetRichestAddresses(1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, index)
+	assert.Equal(t, []*AddressBalance{{Address: genesis, Balance: consts.TotalSupply - 1005}}, richest)
+
+	richest, _, err = manager.GetRichestAddresses(10)
+	require.NoError(t, err)
+	assert.Equal(t, []*AddressBalance{
+		{Address: genesis, Balance: consts.TotalSupply - 1005},
+		{Address: other, Balance: 1005},
+	}, richest)
+
+	distribution, _, err := manager.GetSupplyDistribution()
+	require.NoError(t, err)
+	require.Len(t, distribution, supplyDistributionBucketCount)
+
+	var addressCount int
+	var totalBalance uint64
+	for _, bucket := range distribution {
+		addressCount += bucket.AddressCount
+		totalBalance += bucket.TotalBalance
+	}
+	assert.Equal(t, 2, addressCount)
+	assert.EqualValues(t, consts.TotalSupply, totalBalance)
+
+	assert.Equal(t, &SupplyDistributionBucket{MinBalance: 1000, MaxBalance: 9999, AddressCount: 1, TotalBalance: 1005}, distribution[3])
+	assert.Equal(t, 1, distribution[supplyDistributionBucketIndex(consts.TotalSupply-1005)].AddressCount)
+
+	// replacing the ledger rebuilds the index
+	require.NoError(t, manager.StoreLedgerBalancesInDatabase(map[string]uint64{string(genesis): consts.TotalSupply}, 4))
+	richest, _, err = manager.GetRichestAddresses(10)
+	require.NoError(t, err)
+	assert.Equal(t, []*AddressBalance{{Address: genesis, Balance: consts.TotalSupply}}, richest)
+}
+
+func TestSupplyDistributionBucketIndex(t *testing.T) {
+	assert.Equal(t, 0, supplyDistributionBucketIndex(1))
+	assert.Equal(t, 0, supplyDistributionBucketIndex(9))
+	assert.Equal(t, 1, supplyDistributionBucketIndex(10))
+	assert.Equal(t, 6, supplyDistributionBucketIndex(1000000))
+	assert.Equal(t, supplyDistributionBucketCount-1, supplyDistributionBucketIndex(consts.TotalSupply))
+}
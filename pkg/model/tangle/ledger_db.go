@@ -19,38 +19,73 @@ const (
 )
 
 var (
-	ledgerStore           kvstore.KVStore
-	ledgerBalanceStore    kvstore.KVStore
-	ledgerDiffStore       kvstore.KVStore
-	ledgerTransactionLock sync.RWMutex
+	// ErrLedgerStateInvalid is returned if the stored balances and ledger diffs are inconsistent.
+	ErrLedgerStateInvalid = errors.New("ledger state is invalid")
 
-	ledgerMilestoneIndex milestone.Index
+	// the ledger of the node.
+	ledger *LedgerManager
 )
 
-func ReadLockLedger() {
-	ledgerTransactionLock.RLock()
-}
+// LedgerManager holds the balances of all addresses and the ledger diffs of the milestones.
+// Every manager owns its stores and its lock, so several ledgers can coexist in one process.
+type LedgerManager struct {
+	// the lock must be held while reading or modifying the ledger state.
+	sync.RWMutex
 
-func ReadUnlockLedger() {
-	ledgerTransactionLock.RUnlock()
+	ledgerStore        kvstore.KVStore
+	ledgerBalanceStore kvstore.KVStore
+	ledgerDiffStore    kvstore.KVStore
+
+	// the milestone index the balances in the ledger belong to.
+	ledgerMilestoneIndex milestone.Index
+	// the milestone index of an incomplete ledger diff which was rolled back while loading the ledger (0 if none).
+	rolledBackMilestoneIndex milestone.Index
+
+	// the index of the balances for the ledger analytics, nil if they are not maintained.
+	analytics *ledgerAnalytics
 }
 
-func WriteLockLedger() {
-	ledgerTransactionLock.Lock()
+// NewLedgerManager creates a new LedgerManager on top of the given store
+// and loads the ledger milestone index from it.
+func NewLedgerManager(store kvstore.KVStore) (*LedgerManager, error) {
+	m := &LedgerManager{
+		ledgerStore:        store.WithRealm([]byte{StorePrefixLedgerState}),
+		ledgerBalanceStore: store.WithRealm([]byte{StorePrefixLedgerBalance}),
+		ledgerDiffStore:    store.WithRealm([]byte{StorePrefixLedgerDiff}),
+	}
+
+	if err := m.readLedgerMilestoneIndexFromDatabase(); err != nil {
+		return nil, err
+	}
+
+	if err := m.rollbackLedgerDiffIntent(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
 }
 
-func WriteUnlockLedger() {
-	ledgerTransactionLock.Unlock()
+// Ledger returns the ledger of the node.
+func Ledger() *LedgerManager {
+	return ledger
 }
 
 func configureLedgerStore(store kvstore.KVStore) {
-	ledgerStore = store.WithRealm([]byte{StorePrefixLedgerState})
-	ledgerBalanceStore = store.WithRealm([]byte{StorePrefixLedgerBalance})
-	ledgerDiffStore = store.WithRealm([]byte{StorePrefixLedgerDiff})
-
-	if err := readLedgerMilestoneIndexFromDatabase(); err != nil {
+	var err error
+	if ledger, err = NewLedgerManager(store); err != nil {
 		panic(err)
 	}
+
+	if ledgerAnalyticsEnabled {
+		if err := ledger.EnableAnalytics(nil); err != nil {
+			panic(err)
+		}
+	}
+
+	// set the solid milestone index based on the ledger milestone
+	if ledgerIndex := ledger.MilestoneIndex(); ledgerIndex != 0 {
+		SetSolidMilestoneIndex(ledgerIndex, false)
+	}
 }
 
 func databaseKeyForAddress(address hornet.Hash) []byte {
@@ -81,53 +116,80 @@ func diffFromBytes(bytes []byte) int64 {
 	return int64(balanceFromBytes(bytes))
 }
 
-func readLedgerMilestoneIndexFromDatabase() error {
+// checks the lengths of a stored ledger entry, which consists of an address and a balance or a diff.
+func checkLedgerEntryLength(address []byte, value []byte) error {
+	if len(address) < 49 {
+		return errors.Wrapf(ErrInvalidKeyLength, "ledger entry address, %d bytes", len(address))
+	}
+	if len(value) != 8 {
+		return errors.Wrapf(ErrInvalidValueLength, "ledger entry value, %d bytes", len(value))
+	}
+	return nil
+}
+
+func (m *LedgerManager) readLedgerMilestoneIndexFromDatabase() error {
 
-	ReadLockLedger()
-	defer ReadUnlockLedger()
+	m.RLock()
+	defer m.RUnlock()
 
-	value, err := ledgerStore.Get([]byte(ledgerMilestoneIndexKey))
+	value, err := m.ledgerStore.Get([]byte(ledgerMilestoneIndexKey))
 	if err != nil {
 		if err != kvstore.ErrKeyNotFound {
 			return errors.Wrap(NewDatabaseError(err), "failed to load ledger milestone index")
 		}
 		return nil
 	}
-	ledgerMilestoneIndex = milestoneIndexFromBytes(value)
-
-	// set the solid milestone index based on the ledger milestone
-	SetSolidMilestoneIndex(ledgerMilestoneIndex, false)
+	if len(value) != 4 {
+		return errors.Wrapf(NewDatabaseError(ErrInvalidValueLength), "failed to load ledger milestone index, %d bytes", len(value))
+	}
+	m.ledgerMilestoneIndex = milestoneIndexFromBytes(value)
 
 	return nil
 }
 
-func GetBalanceForAddressWithoutLocking(address hornet.Hash) (uint64, milestone.Index, error) {
+// MilestoneIndex returns the milestone index the balances in the ledger belong to.
+func (m *LedgerManager) MilestoneIndex() milestone.Index {
+	m.RLock()
+	defer m.RUnlock()
 
-	value, err := ledgerBalanceStore.Get(databaseKeyForAddress(address))
+	return m.ledgerMilestoneIndex
+}
+
+// GetBalanceForAddressWithoutLocking returns the balance of the given address and the ledger milestone index.
+// The read lock of the manager must be held while entering this function.
+func (m *LedgerManager) GetBalanceForAddressWithoutLocking(address hornet.Hash) (uint64, milestone.Index, error) {
+
+	value, err := m.ledgerBalanceStore.Get(databaseKeyForAddress(address))
 	if err != nil {
 		if err != kvstore.ErrKeyNotFound {
-			return 0, ledgerMilestoneIndex, errors.Wrap(NewDatabaseError(err), "failed to retrieve balance")
+			return 0, m.ledgerMilestoneIndex, errors.Wrap(NewDatabaseError(err), "failed to retrieve balance")
 		}
-		return 0, ledgerMilestoneIndex, nil
+		return 0, m.ledgerMilestoneIndex, nil
 	}
 
-	return balanceFromBytes(value), ledgerMilestoneIndex, err
+	if len(value) != 8 {
+		return 0, m.ledgerMilestoneIndex, errors.Wrapf(NewDatabaseError(ErrInvalidValueLength), "failed to retrieve balance, %d bytes", len(value))
+	}
+
+	return balanceFromBytes(value), m.ledgerMilestoneIndex, nil
 }
 
-func GetBalanceForAddress(address hornet.Hash) (uint64, milestone.Index, error) {
+// GetBalanceForAddress returns the balance of the given address and the ledger milestone index.
+func (m *LedgerManager) GetBalanceForAddress(address hornet.Hash) (uint64, milestone.Index, error) {
 
-	ReadLockLedger()
-	defer ReadUnlockLedger()
+	m.RLock()
+	defer m.RUnlock()
 
-	return GetBalanceForAddressWithoutLocking(address)
+	return m.GetBalanceForAddressWithoutLocking(address)
 }
 
-func DeleteLedgerDiffForMilestone(index milestone.Index) error {
+// DeleteLedgerDiffForMilestone deletes the ledger changes of that specific milestone.
+func (m *LedgerManager) DeleteLedgerDiffForMilestone(index milestone.Index) error {
 
-	WriteLockLedger()
-	defer WriteUnlockLedger()
+	m.Lock()
+	defer m.Unlock()
 
-	if err := ledgerDiffStore.DeletePrefix(databaseKeyForMilestoneIndex(index)); err != nil {
+	if err := m.ledgerDiffStore.DeletePrefix(databaseKeyForMilestoneIndex(index)); err != nil {
 		return errors.Wrap(NewDatabaseError(err), "failed to delete ledger diff")
 	}
 
@@ -135,15 +197,31 @@ func DeleteLedgerDiffForMilestone(index milestone.Index) error {
 }
 
 // GetLedgerDiffForMilestoneWithoutLocking returns the ledger changes of that specific milestone.
-// ReadLockLedger must be held while entering this function.
-func GetLedgerDiffForMilestoneWithoutLocking(index milestone.Index, abortSignal <-chan struct{}) (map[string]int64, error) {
+// The read lock of the manager must be held while entering this function.
+func (m *LedgerManager) GetLedgerDiffForMilestoneWithoutLocking(index milestone.Index, abortSignal <-chan struct{}) (map[string]int64, error) {
+
+	diff, diffSum, err := m.readLedgerDiffWithoutLocking(index, abortSignal)
+	if err != nil {
+		return nil, err
+	}
+
+	if diffSum != 0 {
+		panic(fmt.Sprintf("GetLedgerDiffForMilestone(): Ledger diff for milestone %d does not sum up to zero", index))
+	}
+
+	return diff, nil
+}
+
+// reads the ledger changes of that specific milestone and returns them together with their sum.
+func (m *LedgerManager) readLedgerDiffWithoutLocking(index milestone.Index, abortSignal <-chan struct{}) (map[string]int64, int64, error) {
 
 	diff := make(map[string]int64)
 
 	keyPrefix := databaseKeyForMilestoneIndex(index)
 
 	aborted := false
-	err := ledgerDiffStore.Iterate(keyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+	var innerErr error
+	err := m.ledgerDiffStore.Iterate(keyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
 		select {
 		case <-abortSignal:
 			aborted = true
@@ -151,16 +229,24 @@ func GetLedgerDiffForMilestoneWithoutLocking(index milestone.Index, abortSignal
 		default:
 		}
 		// Remove prefix from key
-		diff[string(key[len(keyPrefix):len(keyPrefix)+49])] = diffFromBytes(value)
+		address := key[len(keyPrefix):]
+		if innerErr = checkLedgerEntryLength(address, value); innerErr != nil {
+			return false
+		}
+		diff[string(address[:49])] = diffFromBytes(value)
 		return true
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	if innerErr != nil {
+		return nil, 0, errors.Wrapf(NewDatabaseError(innerErr), "failed to read ledger diff of milestone %d", index)
 	}
 
 	if aborted {
-		return nil, ErrOperationAborted
+		return nil, 0, ErrOperationAborted
 	}
 
 	var diffSum int64
@@ -168,47 +254,39 @@ func GetLedgerDiffForMilestoneWithoutLocking(index milestone.Index, abortSignal
 		diffSum += change
 	}
 
-	if diffSum != 0 {
-		panic(fmt.Sprintf("GetLedgerDiffForMilestone(): Ledger diff for milestone %d does not sum up to zero", index))
-	}
+	return diff, diffSum, nil
+}
 
-	return diff, nil
+// GetLedgerDiffForMilestone returns the ledger changes of that specific milestone.
+func (m *LedgerManager) GetLedgerDiffForMilestone(index milestone.Index, abortSignal <-chan struct{}) (map[string]int64, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.GetLedgerDiffForMilestoneWithoutLocking(index, abortSignal)
 }
 
 // LedgerDiffHashConsumer consumes the given ledger diff addresses during looping through all ledger diffs in the persistence layer.
 type LedgerDiffHashConsumer func(msIndex milestone.Index, address hornet.Hash) bool
 
 // ForEachLedgerDiffHash loops over all ledger diffs.
-func ForEachLedgerDiffHash(consumer LedgerDiffHashConsumer, skipCache bool) {
-	ledgerDiffStore.IterateKeys([]byte{}, func(key kvstore.Key) bool {
+func (m *LedgerManager) ForEachLedgerDiffHash(consumer LedgerDiffHashConsumer, skipCache bool) {
+	m.ledgerDiffStore.IterateKeys([]byte{}, func(key kvstore.Key) bool {
+		if len(key) < 53 {
+			// skip corrupted keys
+			return true
+		}
 		return consumer(milestone.Index(binary.LittleEndian.Uint32(key[:4])), key[4:53])
 	})
 }
 
-func GetLedgerDiffForMilestone(index milestone.Index, abortSignal <-chan struct{}) (map[string]int64, error) {
-
-	ReadLockLedger()
-	defer ReadUnlockLedger()
-
-	return GetLedgerDiffForMilestoneWithoutLocking(index, abortSignal)
-}
-
-func GetLedgerStateForMilestoneWithoutLocking(targetIndex milestone.Index, abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
-
-	solidMilestoneIndex := GetSolidMilestoneIndex()
-	if targetIndex == 0 {
-		targetIndex = solidMilestoneIndex
-	}
-
-	if targetIndex > solidMilestoneIndex {
-		return nil, 0, fmt.Errorf("target index is too new. maximum: %d, actual: %d", solidMilestoneIndex, targetIndex)
-	}
-
-	if targetIndex <= snapshot.PruningIndex {
-		return nil, 0, fmt.Errorf("target index is too old. minimum: %d, actual: %d", snapshot.PruningIndex+1, targetIndex)
-	}
+// GetLedgerStateForMilestoneWithoutLocking returns all balances for the given milestone index
+// by rolling back the ledger diffs starting at the ledger milestone index.
+// If targetIndex is 0, the balances of the ledger milestone index are returned.
+// The read lock of the manager must be held while entering this function.
+func (m *LedgerManager) GetLedgerStateForMilestoneWithoutLocking(targetIndex milestone.Index, abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
 
-	balances, ledgerMilestone, err := GetLedgerStateForLSMIWithoutLocking(abortSignal)
+	balances, ledgerMilestone, err := m.GetLedgerStateForLSMIWithoutLocking(abortSignal)
 	if err != nil {
 		if err == ErrOperationAborted {
 			return nil, 0, err
@@ -216,13 +294,17 @@ func GetLedgerStateForMilestoneWithoutLocking(targetIndex milestone.Index, abort
 		return nil, 0, fmt.Errorf("GetLedgerStateForLSMI failed! %v", err)
 	}
 
-	if ledgerMilestone != solidMilestoneIndex {
-		return nil, 0, fmt.Errorf("LedgerMilestone wrong! %d/%d", ledgerMilestone, solidMilestoneIndex)
+	if targetIndex == 0 {
+		targetIndex = ledgerMilestone
+	}
+
+	if targetIndex > ledgerMilestone {
+		return nil, 0, fmt.Errorf("target index is too new. maximum: %d, actual: %d", ledgerMilestone, targetIndex)
 	}
 
 	// Calculate balances for targetIndex
-	for milestoneIndex := solidMilestoneIndex; milestoneIndex > targetIndex; milestoneIndex-- {
-		diff, err := GetLedgerDiffForMilestoneWithoutLocking(milestoneIndex, abortSignal)
+	for milestoneIndex := ledgerMilestone; milestoneIndex > targetIndex; milestoneIndex-- {
+		diff, err := m.GetLedgerDiffForMilestoneWithoutLocking(milestoneIndex, abortSignal)
 		if err != nil {
 			if err == ErrOperationAborted {
 				return nil, 0, err
@@ -251,29 +333,35 @@ func GetLedgerStateForMilestoneWithoutLocking(targetIndex milestone.Index, abort
 	return balances, targetIndex, nil
 }
 
-func GetLedgerStateForMilestone(targetIndex milestone.Index, abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
+// GetLedgerStateForMilestone returns all balances for the given milestone index.
+// If targetIndex is 0, the balances of the ledger milestone index are returned.
+func (m *LedgerManager) GetLedgerStateForMilestone(targetIndex milestone.Index, abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
 
-	ReadLockLedger()
-	defer ReadUnlockLedger()
+	m.RLock()
+	defer m.RUnlock()
 
-	return GetLedgerStateForMilestoneWithoutLocking(targetIndex, abortSignal)
+	return m.GetLedgerStateForMilestoneWithoutLocking(targetIndex, abortSignal)
 }
 
 // ApplyLedgerDiffWithoutLocking applies the changes to the ledger.
-// WriteLockLedger must be held while entering this function.
-func ApplyLedgerDiffWithoutLocking(diff map[string]int64, index milestone.Index) error {
+// The write lock of the manager must be held while entering this function.
+func (m *LedgerManager) ApplyLedgerDiffWithoutLocking(diff map[string]int64, index milestone.Index) error {
+
+	balanceBatch := m.ledgerBalanceStore.Batched()
+	diffBatch := m.ledgerDiffStore.Batched()
 
-	balanceBatch := ledgerBalanceStore.Batched()
-	diffBatch := ledgerDiffStore.Batched()
+	// the balances before the changes are applied, they are needed to roll back an incomplete ledger diff.
+	previousBalances := make(map[string]uint64, len(diff))
 
 	var diffSum int64
 
 	for address, change := range diff {
 
-		balance, _, err := GetBalanceForAddressWithoutLocking(hornet.Hash(address))
+		balance, _, err := m.GetBalanceForAddressWithoutLocking(hornet.Hash(address))
 		if err != nil {
 			panic(fmt.Sprintf("GetBalanceForAddressWithoutLocking() returned error for address %s: %v", address, err))
 		}
+		previousBalances[address] = balance
 
 		newBalance := int64(balance) + change
 
@@ -297,6 +385,12 @@ func ApplyLedgerDiffWithoutLocking(diff map[string]int64, index milestone.Index)
 		panic(fmt.Sprintf("Ledger diff for milestone %d does not sum up to zero", index))
 	}
 
+	// the diff and the balances are stored in different realms, which can't be written atomically.
+	// the intent is stored first, so that an incomplete ledger diff can be rolled back at the next startup.
+	if err := m.storeLedgerDiffIntent(index, previousBalances); err != nil {
+		return err
+	}
+
 	if err := diffBatch.Commit(); err != nil {
 		return errors.Wrap(NewDatabaseError(err), "failed to store ledger diff")
 	}
@@ -305,25 +399,37 @@ func ApplyLedgerDiffWithoutLocking(diff map[string]int64, index milestone.Index)
 		return errors.Wrap(NewDatabaseError(err), "failed to store ledger balance")
 	}
 
-	if err := ledgerStore.Set([]byte(ledgerMilestoneIndexKey), bytesFromMilestoneIndex(index)); err != nil {
+	// the new ledger index and the removal of the intent are committed together
+	ledgerBatch := m.ledgerStore.Batched()
+	ledgerBatch.Set([]byte(ledgerMilestoneIndexKey), bytesFromMilestoneIndex(index))
+	ledgerBatch.Delete([]byte(ledgerDiffIntentKey))
+
+	if err := ledgerBatch.Commit(); err != nil {
 		return errors.Wrap(NewDatabaseError(err), "failed to store ledger index")
 	}
 
-	ledgerMilestoneIndex = index
+	if m.analytics != nil {
+		for address, change := range diff {
+			m.analytics.update(address, previousBalances[address], uint64(int64(previousBalances[address])+change))
+		}
+	}
+
+	m.ledgerMilestoneIndex = index
 	return nil
 }
 
-func StoreLedgerBalancesInDatabase(balances map[string]uint64, index milestone.Index) error {
+// StoreLedgerBalancesInDatabase replaces all balances in the ledger with the given ones.
+func (m *LedgerManager) StoreLedgerBalancesInDatabase(balances map[string]uint64, index milestone.Index) error {
 
-	WriteLockLedger()
-	defer WriteUnlockLedger()
+	m.Lock()
+	defer m.Unlock()
 
 	// Delete all ledger balances
-	if err := ledgerBalanceStore.Clear(); err != nil {
+	if err := m.ledgerBalanceStore.Clear(); err != nil {
 		return errors.Wrap(NewDatabaseError(err), "failed to delete ledger balances")
 	}
 
-	balanceBatch := ledgerBalanceStore.Batched()
+	balanceBatch := m.ledgerBalanceStore.Batched()
 
 	for address, balance := range balances {
 		if balance == 0 {
@@ -337,22 +443,42 @@ func StoreLedgerBalancesInDatabase(balances map[string]uint64, index milestone.I
 		return errors.Wrap(NewDatabaseError(err), "failed to store ledger state")
 	}
 
-	if err := ledgerStore.Set([]byte(ledgerMilestoneIndexKey), bytesFromMilestoneIndex(index)); err != nil {
+	if err := m.ledgerStore.Set([]byte(ledgerMilestoneIndexKey), bytesFromMilestoneIndex(index)); err != nil {
 		return errors.Wrap(NewDatabaseError(err), "failed to store ledger index")
 	}
 
-	ledgerMilestoneIndex = index
+	if m.analytics != nil {
+		m.analytics = newLedgerAnalytics(balances)
+	}
+
+	m.ledgerMilestoneIndex = index
 	return nil
 }
 
-// GetLedgerStateForLSMIWithoutLocking returns all balances for the current solid milestone.
-// ReadLockLedger must be held while entering this function.
-func GetLedgerStateForLSMIWithoutLocking(abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
+// GetLedgerStateForLSMIWithoutLocking returns all balances for the ledger milestone index.
+// The read lock of the manager must be held while entering this function.
+func (m *LedgerManager) GetLedgerStateForLSMIWithoutLocking(abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
+
+	balances, total, err := m.readBalancesWithoutLocking(abortSignal)
+	if err != nil {
+		return nil, m.ledgerMilestoneIndex, err
+	}
+
+	if total != consts.TotalSupply {
+		panic(fmt.Sprintf("total does not match supply: %d != %d", total, consts.TotalSupply))
+	}
+
+	return balances, m.ledgerMilestoneIndex, nil
+}
+
+// reads all balances of the ledger and returns them together with their sum.
+func (m *LedgerManager) readBalancesWithoutLocking(abortSignal <-chan struct{}) (map[string]uint64, uint64, error) {
 
 	balances := make(map[string]uint64)
 
 	aborted := false
-	err := ledgerBalanceStore.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+	var innerErr error
+	err := m.ledgerBalanceStore.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
 		select {
 		case <-abortSignal:
 			aborted = true
@@ -360,15 +486,22 @@ func GetLedgerStateForLSMIWithoutLocking(abortSignal <-chan struct{}) (map[strin
 		default:
 		}
 
+		if innerErr = checkLedgerEntryLength(key, value); innerErr != nil {
+			return false
+		}
 		balances[string(key[:49])] = balanceFromBytes(value)
 		return true
 	})
 	if err != nil {
-		return nil, ledgerMilestoneIndex, err
+		return nil, 0, err
+	}
+
+	if innerErr != nil {
+		return nil, 0, errors.Wrap(NewDatabaseError(innerErr), "failed to read ledger balances")
 	}
 
 	if aborted {
-		return nil, ledgerMilestoneIndex, ErrOperationAborted
+		return nil, 0, ErrOperationAborted
 	}
 
 	var total uint64
@@ -376,18 +509,200 @@ func GetLedgerStateForLSMIWithoutLocking(abortSignal <-chan struct{}) (map[strin
 		total += value
 	}
 
+	return balances, total, nil
+}
+
+// VerifyLedgerStateWithoutLocking rolls back the balances of the ledger milestone index to the given base index
+// using the stored ledger diffs and compares the result with the given balances of the base index.
+// It returns the balances of the target index, which has to lie between the base index and the ledger milestone index,
+// or ErrLedgerStateInvalid if the stored balances and ledger diffs are inconsistent.
+// The read lock of the manager must be held while entering this function.
+func (m *LedgerManager) VerifyLedgerStateWithoutLocking(targetIndex milestone.Index, baseBalances map[string]uint64, baseIndex milestone.Index, abortSignal <-chan struct{}) (map[string]uint64, error) {
+
+	if targetIndex < baseIndex || targetIndex > m.ledgerMilestoneIndex {
+		return nil, errors.Wrapf(ErrLedgerStateInvalid, "target index %d is not between the base index %d and the ledger milestone index %d", targetIndex, baseIndex, m.ledgerMilestoneIndex)
+	}
+
+	balances, total, err := m.readBalancesWithoutLocking(abortSignal)
+	if err != nil {
+		return nil, err
+	}
+
 	if total != consts.TotalSupply {
-		panic(fmt.Sprintf("total does not match supply: %d != %d", total, consts.TotalSupply))
+		return nil, errors.Wrapf(ErrLedgerStateInvalid, "total does not match supply: %d != %d", total, consts.TotalSupply)
+	}
+
+	var targetBalances map[string]uint64
+	if targetIndex == m.ledgerMilestoneIndex {
+		targetBalances = copyBalances(balances)
+	}
+
+	for milestoneIndex := m.ledgerMilestoneIndex; milestoneIndex > baseIndex; milestoneIndex-- {
+		diff, diffSum, err := m.readLedgerDiffWithoutLocking(milestoneIndex, abortSignal)
+		if err != nil {
+			return nil, err
+		}
+
+		if diffSum != 0 {
+			return nil, errors.Wrapf(ErrLedgerStateInvalid, "ledger diff for milestone %d does not sum up to zero", milestoneIndex)
+		}
+
+		for address, change := range diff {
+			newBalance := int64(balances[address]) - change
+
+			if newBalance < 0 {
+				return nil, errors.Wrapf(ErrLedgerStateInvalid, "ledger diff for milestone %d creates negative balance for address %s", milestoneIndex, hornet.Hash(address).Trytes())
+			} else if newBalance == 0 {
+				delete(balances, address)
+			} else {
+				balances[address] = uint64(newBalance)
+			}
+		}
+
+		if milestoneIndex-1 == targetIndex {
+			targetBalances = copyBalances(balances)
+		}
 	}
 
-	return balances, ledgerMilestoneIndex, err
+	if len(balances) != len(baseBalances) {
+		return nil, errors.Wrapf(ErrLedgerStateInvalid, "balances of milestone %d do not match the base balances", baseIndex)
+	}
+
+	for address, balance := range baseBalances {
+		if balances[address] != balance {
+			return nil, errors.Wrapf(ErrLedgerStateInvalid, "balance of address %s does not match the base balance", hornet.Hash(address).Trytes())
+		}
+	}
+
+	return targetBalances, nil
 }
 
-// GetLedgerStateForLSMI returns all balances for the current solid milestone.
-func GetLedgerStateForLSMI(abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
+func copyBalances(balances map[string]uint64) map[string]uint64 {
+	result := make(map[string]uint64, len(balances))
+	for address, balance := range balances {
+		result[address] = balance
+	}
+	return result
+}
+
+// GetLedgerStateForLSMI returns all balances for the ledger milestone index.
+func (m *LedgerManager) GetLedgerStateForLSMI(abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.GetLedgerStateForLSMIWithoutLocking(abortSignal)
+}
+
+// ReadLockLedger acquires the read lock of the ledger of the node.
+//
+// Deprecated: use Ledger().RLock instead.
+func ReadLockLedger() {
+	ledger.RLock()
+}
 
-	ReadLockLedger()
-	defer ReadUnlockLedger()
+// ReadUnlockLedger releases the read lock of the ledger of the node.
+//
+// Deprecated: use Ledger().RUnlock instead.
+func ReadUnlockLedger() {
+	ledger.RUnlock()
+}
 
-	return GetLedgerStateForLSMIWithoutLocking(abortSignal)
+// WriteLockLedger acquires the write lock of the ledger of the node.
+//
+// Deprecated: use Ledger().Lock instead.
+func WriteLockLedger() {
+	ledger.Lock()
+}
+
+// WriteUnlockLedger releases the write lock of the ledger of the node.
+//
+// Deprecated: use Ledger().Unlock instead.
+func WriteUnlockLedger() {
+	ledger.Unlock()
+}
+
+// Deprecated: use Ledger().GetBalanceForAddressWithoutLocking instead.
+func GetBalanceForAddressWithoutLocking(address hornet.Hash) (uint64, milestone.Index, error) {
+	return ledger.GetBalanceForAddressWithoutLocking(address)
+}
+
+// Deprecated: use Ledger().GetBalanceForAddress instead.
+func GetBalanceForAddress(address hornet.Hash) (uint64, milestone.Index, error) {
+	return ledger.GetBalanceForAddress(address)
+}
+
+// Deprecated: use Ledger().DeleteLedgerDiffForMilestone instead.
+func DeleteLedgerDiffForMilestone(index milestone.Index) error {
+	return ledger.DeleteLedgerDiffForMilestone(index)
+}
+
+// Deprecated: use Ledger().GetLedgerDiffForMilestoneWithoutLocking instead.
+func GetLedgerDiffForMilestoneWithoutLocking(index milestone.Index, abortSignal <-chan struct{}) (map[string]int64, error) {
+	return ledger.GetLedgerDiffForMilestoneWithoutLocking(index, abortSignal)
+}
+
+// Deprecated: use Ledger().GetLedgerDiffForMilestone instead.
+func GetLedgerDiffForMilestone(index milestone.Index, abortSignal <-chan struct{}) (map[string]int64, error) {
+	return ledger.GetLedgerDiffForMilestone(index, abortSignal)
+}
+
+// Deprecated: use Ledger().ForEachLedgerDiffHash instead.
+func ForEachLedgerDiffHash(consumer LedgerDiffHashConsumer, skipCache bool) {
+	ledger.ForEachLedgerDiffHash(consumer, skipCache)
+}
+
+// GetLedgerStateForMilestoneWithoutLocking returns all balances for the given milestone index,
+// which must lie between the pruning index and the solid milestone index.
+// ReadLockLedger must be held while entering this function.
+func GetLedgerStateForMilestoneWithoutLocking(targetIndex milestone.Index, abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
+
+	solidMilestoneIndex := GetSolidMilestoneIndex()
+	if targetIndex == 0 {
+		targetIndex = solidMilestoneIndex
+	}
+
+	if targetIndex > solidMilestoneIndex {
+		return nil, 0, fmt.Errorf("target index is too new. maximum: %d, actual: %d", solidMilestoneIndex, targetIndex)
+	}
+
+	if targetIndex <= snapshot.PruningIndex {
+		return nil, 0, fmt.Errorf("target index is too old. minimum: %d, actual: %d", snapshot.PruningIndex+1, targetIndex)
+	}
+
+	if ledgerMilestone := ledger.ledgerMilestoneIndex; ledgerMilestone != solidMilestoneIndex {
+		return nil, 0, fmt.Errorf("LedgerMilestone wrong! %d/%d", ledgerMilestone, solidMilestoneIndex)
+	}
+
+	return ledger.GetLedgerStateForMilestoneWithoutLocking(targetIndex, abortSignal)
+}
+
+// GetLedgerStateForMilestone returns all balances for the given milestone index,
+// which must lie between the pruning index and the solid milestone index.
+func GetLedgerStateForMilestone(targetIndex milestone.Index, abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
+
+	ledger.RLock()
+	defer ledger.RUnlock()
+
+	return GetLedgerStateForMilestoneWithoutLocking(targetIndex, abortSignal)
+}
+
+// Deprecated: use Ledger().ApplyLedgerDiffWithoutLocking instead.
+func ApplyLedgerDiffWithoutLocking(diff map[string]int64, index milestone.Index) error {
+	return ledger.ApplyLedgerDiffWithoutLocking(diff, index)
+}
+
+// Deprecated: use Ledger().StoreLedgerBalancesInDatabase instead.
+func StoreLedgerBalancesInDatabase(balances map[string]uint64, index milestone.Index) error {
+	return ledger.StoreLedgerBalancesInDatabase(balances, index)
+}
+
+// Deprecated: use Ledger().GetLedgerStateForLSMIWithoutLocking instead.
+func GetLedgerStateForLSMIWithoutLocking(abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
+	return ledger.GetLedgerStateForLSMIWithoutLocking(abortSignal)
+}
+
+// Deprecated: use Ledger().GetLedgerStateForLSMI instead.
+func GetLedgerStateForLSMI(abortSignal <-chan struct{}) (map[string]uint64, milestone.Index, error) {
+	return ledger.GetLedgerStateForLSMI(abortSignal)
 }
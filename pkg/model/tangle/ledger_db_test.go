@@ -0,0 +1,142 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/iota.go/consts"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+func TestLedgerManager(t *testing.T) {
+	genesis := hornet.HashFromAddressTrytes("UDYXTZBE9GZGPM9SSQV9LTZNDLJIZMPUVVXYXFYVBLIEUHLSEWFTKZZLXYRHHWVQV9MNNX9KZC9D9UZWZ")
+	receiver := hornet.HashFromAddressTrytes("GYISMBVRKSCEXXTUPBWTIHRCZIKIRPDYAHAYKMNTPZSCSDNADDWAEUNHKUERZCTVAYJCNFXGTNUH9OGTW")
+
+	store := mapdb.NewMapDB()
+	manager, err := NewLedgerManager(store)
+	require.NoError(t, err)
+	require.NoError(t, manager.StoreLedgerBalancesInDatabase(map[string]uint64{string(genesis): consts.TotalSupply}, 1))
+
+	manager.Lock()
+	require.NoError(t, manager.ApplyLedgerDiffWithoutLocking(map[string]int64{string(genesis): -100, string(receiver): 100}, 2))
+	manager.Unlock()
+
+	balance, index, err := manager.GetBalanceForAddress(receiver)
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, balance)
+	assert.EqualValues(t, 2, index)
+
+	// the balances of older milestones are calculated by rolling back the ledger diffs
+	balances, index, err := manager.GetLedgerStateForMilestone(1, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, index)
+	assert.Equal(t, map[string]uint64{string(genesis): consts.TotalSupply}, balances)
+
+	_, _, err = manager.GetLedgerStateForMilestone(3, nil)
+	assert.Error(t, err)
+
+	// a second ledger does not share any state with the first one
+	other, err := NewLedgerManager(mapdb.NewMapDB())
+	require.NoError(t, err)
+	balance, index, err = other.GetBalanceForAddress(receiver)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, balance)
+	assert.EqualValues(t, 0, index)
+
+	// the ledger milestone index is loaded from the store
+	reloaded, err := NewLedgerManager(store)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, reloaded.MilestoneIndex())
+}
+
+func TestLedgerManagerVerifyLedgerState(t *testing.T) {
+	genesis := hornet.HashFromAddressTrytes("UDYXTZBE9GZGPM9SSQV9LTZNDLJIZMPUVVXYXFYVBLIEUHLSEWFTKZZLXYRHHWVQV9MNNX9KZC9D9UZWZ")
+	receiver := hornet.HashFromAddressTrytes("GYISMBVRKSCEXXTUPBWTIHRCZIKIRPDYAHAYKMNTPZSCSDNADDWAEUNHKUERZCTVAYJCNFXGTNUH9OGTW")
+
+	baseBalances := map[string]uint64{string(genesis): consts.TotalSupply}
+
+	manager, err := NewLedgerManager(mapdb.NewMapDB())
+	require.NoError(t, err)
+	require.NoError(t, manager.StoreLedgerBalancesInDatabase(baseBalances, 1))
+
+	manager.Lock()
+	require.NoError(t, manager.ApplyLedgerDiffWithoutLocking(map[string]int64{string(genesis): -100, string(receiver): 100}, 2))
+	require.NoError(t, manager.ApplyLedgerDiffWithoutLocking(map[string]int64{string(genesis): -50, string(receiver): 50}, 3))
+	manager.Unlock()
+
+	balances, err := manager.VerifyLedgerStateWithoutLocking(2, baseBalances, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{string(genesis): consts.TotalSupply - 100, string(receiver): 100}, balances)
+
+	// the rolled back ledger does not match other base balances
+	_, err = manager.VerifyLedgerStateWithoutLocking(2, map[string]uint64{string(receiver): consts.TotalSupply}, 1, nil)
+	assert.True(t, errors.Is(err, ErrLedgerStateInvalid))
+
+	// the target has to lie between the base index and the ledger milestone index
+	_, err = manager.VerifyLedgerStateWithoutLocking(4, baseBalances, 1, nil)
+	assert.True(t, errors.Is(err, ErrLedgerStateInvalid))
+}
+
+func TestLedgerManagerRollbackIncompleteLedgerDiff(t *testing.T) {
+	genesis := hornet.HashFromAddressTrytes("UDYXTZBE9GZGPM9SSQV9LTZNDLJIZMPUVVXYXFYVBLIEUHLSEWFTKZZLXYRHHWVQV9MNNX9KZC9D9UZWZ")
+	receiver := hornet.HashFromAddressTrytes("GYISMBVRKSCEXXTUPBWTIHRCZIKIRPDYAHAYKMNTPZSCSDNADDWAEUNHKUERZCTVAYJCNFXGTNUH9OGTW")
+
+	store := mapdb.NewMapDB()
+	manager, err := NewLedgerManager(store)
+	require.NoError(t, err)
+	require.NoError(t, manager.StoreLedgerBalancesInDatabase(map[string]uint64{string(genesis): consts.TotalSupply}, 1))
+
+	// simulate a crash after the diff and the balances of milestone 2 were written, but before the ledger index was updated
+	require.NoError(t, manager.storeLedgerDiffIntent(2, map[string]uint64{string(genesis): consts.TotalSupply, string(receiver): 0}))
+	require.NoError(t, manager.ledgerDiffStore.Set(databaseKeyForLedgerDiffAndAddress(2, genesis), bytesFromDiff(-100)))
+	require.NoError(t, manager.ledgerDiffStore.Set(databaseKeyForLedgerDiffAndAddress(2, receiver), bytesFromDiff(100)))
+	require.NoError(t, manager.ledgerBalanceStore.Set(databaseKeyForAddress(genesis), bytesFromBalance(consts.TotalSupply-100)))
+	require.NoError(t, manager.ledgerBalanceStore.Set(databaseKeyForAddress(receiver), bytesFromBalance(100)))
+
+	reloaded, err := NewLedgerManager(store)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, reloaded.RolledBackMilestoneIndex())
+	assert.EqualValues(t, 1, reloaded.MilestoneIndex())
+
+	balances, _, err := reloaded.GetLedgerStateForLSMI(nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{string(genesis): consts.TotalSupply}, balances)
+
+	diff, err := reloaded.GetLedgerDiffForMilestone(2, nil)
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+
+	// a completely applied ledger diff leaves no intent behind
+	reloaded.Lock()
+	require.NoError(t, reloaded.ApplyLedgerDiffWithoutLocking(map[string]int64{string(genesis): -100, string(receiver): 100}, 2))
+	reloaded.Unlock()
+
+	reloaded, err = NewLedgerManager(store)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, reloaded.RolledBackMilestoneIndex())
+	assert.EqualValues(t, 2, reloaded.MilestoneIndex())
+}
+
+func TestLedgerManagerCorruptedEntries(t *testing.T) {
+	genesis := hornet.HashFromAddressTrytes("UDYXTZBE9GZGPM9SSQV9LTZNDLJIZMPUVVXYXFYVBLIEUHLSEWFTKZZLXYRHHWVQV9MNNX9KZC9D9UZWZ")
+
+	manager, err := NewLedgerManager(mapdb.NewMapDB())
+	require.NoError(t, err)
+	require.NoError(t, manager.StoreLedgerBalancesInDatabase(map[string]uint64{string(genesis): consts.TotalSupply}, 1))
+
+	// truncated values are reported instead of panicking
+	require.NoError(t, manager.ledgerBalanceStore.Set(databaseKeyForAddress(genesis), []byte{1, 2, 3}))
+	_, _, err = manager.GetBalanceForAddress(genesis)
+	assert.Equal(t, ErrInvalidValueLength, errors.Cause(err))
+	_, _, err = manager.readBalancesWithoutLocking(nil)
+	assert.Equal(t, ErrInvalidValueLength, errors.Cause(err))
+
+	require.NoError(t, manager.ledgerDiffStore.Set(databaseKeyForMilestoneIndex(2), bytesFromDiff(0)))
+	_, _, err = manager.readLedgerDiffWithoutLocking(2, nil)
+	assert.Equal(t, ErrInvalidKeyLength, errors.Cause(err))
+}
@@ -0,0 +1,114 @@
+package tangle
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+const (
+	ledgerDiffIntentKey = "ledgerDiffIntent"
+
+	// the size of an address and its balance in the ledger diff intent.
+	ledgerDiffIntentEntrySize = 49 + 8
+)
+
+// the intent contains the milestone index of the ledger diff which is applied,
+// followed by the balances of the changed addresses before the diff was applied.
+func bytesFromLedgerDiffIntent(index milestone.Index, previousBalances map[string]uint64) []byte {
+	bytes := make([]byte, 4, 4+len(previousBalances)*ledgerDiffIntentEntrySize)
+	binary.LittleEndian.PutUint32(bytes, uint32(index))
+
+	for address, balance := range previousBalances {
+		bytes = append(bytes, databaseKeyForAddress(hornet.Hash(address))...)
+		bytes = append(bytes, bytesFromBalance(balance)...)
+	}
+	return bytes
+}
+
+func ledgerDiffIntentFromBytes(bytes []byte) (milestone.Index, map[string]uint64, error) {
+	if len(bytes) < 4 || (len(bytes)-4)%ledgerDiffIntentEntrySize != 0 {
+		return 0, nil, errors.Wrapf(NewDatabaseError(errors.New("invalid length")), "failed to parse ledger diff intent, length %d", len(bytes))
+	}
+
+	index := milestoneIndexFromBytes(bytes[:4])
+
+	previousBalances := make(map[string]uint64)
+	for offset := 4; offset < len(bytes); offset += ledgerDiffIntentEntrySize {
+		address := bytes[offset : offset+49]
+		previousBalances[string(address)] = balanceFromBytes(bytes[offset+49 : offset+ledgerDiffIntentEntrySize])
+	}
+	return index, previousBalances, nil
+}
+
+// stores the intent to apply the ledger diff of the given milestone.
+func (m *LedgerManager) storeLedgerDiffIntent(index milestone.Index, previousBalances map[string]uint64) error {
+	if err := m.ledgerStore.Set([]byte(ledgerDiffIntentKey), bytesFromLedgerDiffIntent(index, previousBalances)); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to store ledger diff intent")
+	}
+	return nil
+}
+
+// rolls back a ledger diff which was not applied completely, e.g. because the node crashed during the confirmation of a milestone.
+// the balances of the changed addresses are restored and the stored diff of the milestone is deleted.
+func (m *LedgerManager) rollbackLedgerDiffIntent() error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	value, err := m.ledgerStore.Get([]byte(ledgerDiffIntentKey))
+	if err != nil {
+		if err != kvstore.ErrKeyNotFound {
+			return errors.Wrap(NewDatabaseError(err), "failed to load ledger diff intent")
+		}
+		return nil
+	}
+
+	index, previousBalances, err := ledgerDiffIntentFromBytes(value)
+	if err != nil {
+		return err
+	}
+
+	// the ledger index is only updated together with the removal of the intent.
+	// if it already belongs to the milestone of the intent, the diff was applied completely.
+	if index > m.ledgerMilestoneIndex {
+		balanceBatch := m.ledgerBalanceStore.Batched()
+		for address, balance := range previousBalances {
+			if balance == 0 {
+				balanceBatch.Delete(databaseKeyForAddress(hornet.Hash(address)))
+			} else {
+				balanceBatch.Set(databaseKeyForAddress(hornet.Hash(address)), bytesFromBalance(balance))
+			}
+		}
+
+		if err := balanceBatch.Commit(); err != nil {
+			return errors.Wrap(NewDatabaseError(err), "failed to roll back ledger balances")
+		}
+
+		if err := m.ledgerDiffStore.DeletePrefix(databaseKeyForMilestoneIndex(index)); err != nil {
+			return errors.Wrap(NewDatabaseError(err), "failed to roll back ledger diff")
+		}
+
+		m.rolledBackMilestoneIndex = index
+	}
+
+	if err := m.ledgerStore.Delete([]byte(ledgerDiffIntentKey)); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to delete ledger diff intent")
+	}
+
+	return nil
+}
+
+// RolledBackMilestoneIndex returns the milestone index of an incomplete ledger diff,
+// which was rolled back while loading the ledger, or 0 if the ledger was consistent.
+func (m *LedgerManager) RolledBackMilestoneIndex() milestone.Index {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.rolledBackMilestoneIndex
+}
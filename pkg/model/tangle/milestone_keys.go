@@ -0,0 +1,92 @@
+package tangle
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+var (
+	// ErrInvalidMilestoneKeyRange is returned if a key range is invalid or overlaps with an existing key range.
+	ErrInvalidMilestoneKeyRange = errors.New("invalid milestone key range")
+)
+
+// MilestoneKeyRange defines the milestone indexes a coordinator address (the root of its Merkle tree) is valid for.
+type MilestoneKeyRange struct {
+	// The coordinator address used to verify the milestone signatures.
+	Address hornet.Hash
+	// The first milestone index the address is valid for.
+	StartIndex milestone.Index
+	// The last milestone index the address is valid for (0 = no end).
+	EndIndex milestone.Index
+}
+
+// contains checks whether the milestone index is within the key range.
+func (r *MilestoneKeyRange) contains(index milestone.Index) bool {
+	return index >= r.StartIndex && (r.EndIndex == 0 || index <= r.EndIndex)
+}
+
+// overlaps checks whether both key ranges share at least one milestone index.
+func (r *MilestoneKeyRange) overlaps(other *MilestoneKeyRange) bool {
+	return (r.EndIndex == 0 || other.StartIndex <= r.EndIndex) && (other.EndIndex == 0 || r.StartIndex <= other.EndIndex)
+}
+
+// MilestoneKeyManager holds the set of coordinator addresses and the milestone index ranges they are valid for.
+type MilestoneKeyManager struct {
+	keyRanges []*MilestoneKeyRange
+}
+
+// NewMilestoneKeyManager creates a new, empty MilestoneKeyManager.
+func NewMilestoneKeyManager() *MilestoneKeyManager {
+	return &MilestoneKeyManager{}
+}
+
+// AddKeyRange adds a coordinator address which is valid for the milestones from startIndex to endIndex (0 = no end).
+// The key ranges must not overlap, so that exactly one address is valid for every milestone index.
+func (k *MilestoneKeyManager) AddKeyRange(address hornet.Hash, startIndex milestone.Index, endIndex milestone.Index) error {
+	if len(address) != hornet.HashBinarySize {
+		return errors.Wrapf(ErrInvalidMilestoneKeyRange, "invalid address length: %d", len(address))
+	}
+
+	if endIndex != 0 && endIndex < startIndex {
+		return errors.Wrapf(ErrInvalidMilestoneKeyRange, "end index %d is smaller than start index %d", endIndex, startIndex)
+	}
+
+	keyRange := &MilestoneKeyRange{Address: address, StartIndex: startIndex, EndIndex: endIndex}
+	for _, existing := range k.keyRanges {
+		if existing.overlaps(keyRange) {
+			return errors.Wrapf(ErrInvalidMilestoneKeyRange, "range %d-%d overlaps with %d-%d of %s", startIndex, endIndex, existing.StartIndex, existing.EndIndex, existing.Address.Trytes())
+		}
+	}
+
+	k.keyRanges = append(k.keyRanges, keyRange)
+	return nil
+}
+
+// KeyForMilestoneIndex returns the coordinator address which is valid for the given milestone index or nil if none is.
+func (k *MilestoneKeyManager) KeyForMilestoneIndex(index milestone.Index) hornet.Hash {
+	for _, keyRange := range k.keyRanges {
+		if keyRange.contains(index) {
+			return keyRange.Address
+		}
+	}
+	return nil
+}
+
+// IsCoordinatorAddress checks whether the given address is one of the coordinator addresses.
+func (k *MilestoneKeyManager) IsCoordinatorAddress(address hornet.Hash) bool {
+	for _, keyRange := range k.keyRanges {
+		if bytes.Equal(keyRange.Address, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyRanges returns the configured key ranges.
+func (k *MilestoneKeyManager) KeyRanges() []*MilestoneKeyRange {
+	return k.keyRanges
+}
@@ -0,0 +1,39 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+func TestMilestoneKeyManager(t *testing.T) {
+	oldKey := hornet.HashFromAddressTrytes("UDYXTZBE9GZGPM9SSQV9LTZNDLJIZMPUVVXYXFYVBLIEUHLSEWFTKZZLXYRHHWVQV9MNNX9KZC9D9UZWZ")
+	newKey := hornet.HashFromAddressTrytes("GYISMBVRKSCEXXTUPBWTIHRCZIKIRPDYAHAYKMNTPZSCSDNADDWAEUNHKUERZCTVAYJCNFXGTNUH9OGTW")
+
+	keyManager := NewMilestoneKeyManager()
+	require.NoError(t, keyManager.AddKeyRange(oldKey, 0, 999))
+	require.NoError(t, keyManager.AddKeyRange(newKey, 1000, 0))
+
+	assert.Equal(t, oldKey, keyManager.KeyForMilestoneIndex(1))
+	assert.Equal(t, oldKey, keyManager.KeyForMilestoneIndex(999))
+	assert.Equal(t, newKey, keyManager.KeyForMilestoneIndex(1000))
+	assert.Equal(t, newKey, keyManager.KeyForMilestoneIndex(5000000))
+
+	assert.True(t, keyManager.IsCoordinatorAddress(oldKey))
+	assert.False(t, keyManager.IsCoordinatorAddress(hornet.NullHashBytes))
+
+	err := keyManager.AddKeyRange(hornet.NullHashBytes, 2000, 3000)
+	assert.True(t, errors.Is(err, ErrInvalidMilestoneKeyRange))
+
+	err = keyManager.AddKeyRange(hornet.NullHashBytes, 10, 5)
+	assert.True(t, errors.Is(err, ErrInvalidMilestoneKeyRange))
+
+	limited := NewMilestoneKeyManager()
+	require.NoError(t, limited.AddKeyRange(oldKey, 100, 200))
+	assert.Nil(t, limited.KeyForMilestoneIndex(99))
+	assert.Nil(t, limited.KeyForMilestoneIndex(201))
+}
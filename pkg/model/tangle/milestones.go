@@ -20,21 +20,21 @@ import (
 )
 
 const (
-	isNodeSyncedWithinThreshold = 2
+	isNodeAlmostSyncedThreshold = 2
 )
 
 var (
-	solidMilestoneIndex   milestone.Index
-	solidMilestoneLock    syncutils.RWMutex
-	latestMilestoneIndex  milestone.Index
-	latestMilestoneLock   syncutils.RWMutex
-	isNodeSynced          bool
-	isNodeSyncedThreshold bool
+	solidMilestoneIndex  milestone.Index
+	solidMilestoneLock   syncutils.RWMutex
+	latestMilestoneIndex milestone.Index
+	latestMilestoneLock  syncutils.RWMutex
+	isNodeSynced         bool
+	isNodeAlmostSynced   bool
 
 	waitForNodeSyncedChannelsLock syncutils.Mutex
 	waitForNodeSyncedChannels     []chan struct{}
 
-	coordinatorAddress                 hornet.Hash
+	coordinatorKeyManager              *MilestoneKeyManager
 	coordinatorSecurityLevel           int
 	coordinatorMerkleTreeDepth         uint64
 	coordinatorMilestoneMerkleHashFunc crypto.Hash
@@ -43,14 +43,25 @@ var (
 	ErrInvalidMilestone = errors.New("invalid milestone")
 )
 
-func ConfigureMilestones(cooAddr hornet.Hash, cooSecLvl int, cooMerkleTreeDepth uint64, cooMilestoneMerkleHashFunc crypto.Hash) {
-	coordinatorAddress = cooAddr
+// ConfigureMilestones sets the coordinator addresses and the parameters used to verify the milestones.
+func ConfigureMilestones(cooKeyManager *MilestoneKeyManager, cooSecLvl int, cooMerkleTreeDepth uint64, cooMilestoneMerkleHashFunc crypto.Hash) {
+	coordinatorKeyManager = cooKeyManager
 	coordinatorSecurityLevel = cooSecLvl
 	coordinatorMerkleTreeDepth = cooMerkleTreeDepth
 	coordinatorMilestoneMerkleHashFunc = cooMilestoneMerkleHashFunc
 	maxMilestoneIndex = 1 << coordinatorMerkleTreeDepth
 }
 
+// GetCoordinatorAddressForMilestoneIndex returns the coordinator address which is valid for the given milestone index or nil if none is.
+func GetCoordinatorAddressForMilestoneIndex(index milestone.Index) hornet.Hash {
+	return coordinatorKeyManager.KeyForMilestoneIndex(index)
+}
+
+// IsCoordinatorAddress returns whether the given address is one of the coordinator addresses.
+func IsCoordinatorAddress(address hornet.Hash) bool {
+	return coordinatorKeyManager.IsCoordinatorAddress(address)
+}
+
 func GetMilestoneMerkleHashFunc() crypto.Hash {
 	return coordinatorMilestoneMerkleHashFunc
 }
@@ -75,7 +86,7 @@ func GetMilestoneOrNil(milestoneIndex milestone.Index) *CachedBundle {
 	}
 	defer cachedMs.Release(true) // milestone -1
 
-	return GetCachedBundleOrNil(cachedMs.GetMilestone().Hash)
+	return GetCachedBundleOrNil(cachedMs.GetMilestone().ID.Hash())
 }
 
 // IsNodeSynced returns whether the node is synced.
@@ -83,9 +94,10 @@ func IsNodeSynced() bool {
 	return isNodeSynced
 }
 
-// IsNodeSyncedWithThreshold returns whether the node is synced within a certain threshold.
-func IsNodeSyncedWithThreshold() bool {
-	return isNodeSyncedThreshold
+// IsNodeAlmostSynced returns whether the node is synced within a certain threshold.
+// this is sufficient for tip selection and for accepting new transactions.
+func IsNodeAlmostSynced() bool {
+	return isNodeAlmostSynced
 }
 
 // WaitForNodeSynced waits at most "timeout" duration for the node to become fully sync.
@@ -94,7 +106,7 @@ func IsNodeSyncedWithThreshold() bool {
 // but a new milestone came in lately.
 func WaitForNodeSynced(timeout time.Duration) bool {
 
-	if !isNodeSyncedThreshold {
+	if !isNodeAlmostSynced {
 		// node is not even synced within threshold, and therefore it is unsync
 		return false
 	}
@@ -129,11 +141,12 @@ func WaitForNodeSynced(timeout time.Duration) bool {
 }
 
 // The node is synced if LMI != 0, LMI >= "recentSeenMilestones" from snapshot and LSMI == LMI.
+// The latest milestone indexes reported by the neighbors are not taken into account, since they are not verified.
 func updateNodeSynced(latestSolidIndex, latestIndex milestone.Index) {
 	if latestIndex == 0 || latestIndex < GetLatestSeenMilestoneIndexFromSnapshot() {
 		// the node can't be sync if not all "recentSeenMilestones" from the snapshot file have been solidified.
 		isNodeSynced = false
-		isNodeSyncedThreshold = false
+		isNodeAlmostSynced = false
 		return
 	}
 
@@ -155,12 +168,12 @@ func updateNodeSynced(latestSolidIndex, latestIndex milestone.Index) {
 	}
 
 	// catch overflow
-	if latestIndex < isNodeSyncedWithinThreshold {
-		isNodeSyncedThreshold = true
+	if latestIndex < isNodeAlmostSyncedThreshold {
+		isNodeAlmostSynced = true
 		return
 	}
 
-	isNodeSyncedThreshold = latestSolidIndex >= (latestIndex - isNodeSyncedWithinThreshold)
+	isNodeAlmostSynced = latestSolidIndex >= (latestIndex - isNodeAlmostSyncedThreshold)
 }
 
 // SetSolidMilestoneIndex sets the solid milestone index.
@@ -297,6 +310,12 @@ func CheckIfMilestone(bndl *Bundle) (result bool, err error) {
 		return false, nil
 	}
 
+	cooAddress := coordinatorKeyManager.KeyForMilestoneIndex(milestoneIndex)
+	if cooAddress == nil {
+		cachedTailTx.Release() // tx -1
+		return false, errors.Wrapf(ErrInvalidMilestone, "No coordinator address for milestone index %d, Hash: %v", milestoneIndex, tailTxHash.Trytes())
+	}
+
 	cachedSignatureTxs := CachedTransactions{}
 	cachedSignatureTxs = append(cachedSignatureTxs, cachedTailTx)
 
@@ -345,7 +364,7 @@ func CheckIfMilestone(bndl *Bundle) (result bool, err error) {
 	}
 
 	// verify milestone signature
-	if valid, err := merkle.ValidateSignatureFragments(coordinatorAddress.Trytes(), uint32(milestoneIndex), path, fragments, cachedSiblingsTx.GetTransaction().Tx.Hash); !valid {
+	if valid, err := merkle.ValidateSignatureFragments(cooAddress.Trytes(), uint32(milestoneIndex), path, fragments, cachedSiblingsTx.GetTransaction().Tx.Hash); !valid {
 		if err != nil {
 			return false, errors.Wrap(ErrInvalidMilestone, err.Error())
 		}
@@ -359,14 +378,14 @@ func CheckIfMilestone(bndl *Bundle) (result bool, err error) {
 
 // Checks if the the tx could be part of a milestone.
 func IsMaybeMilestone(cachedTx *CachedTransaction) bool {
-	value := (cachedTx.GetTransaction().Tx.Value == 0) && (bytes.Equal(cachedTx.GetTransaction().GetAddress(), coordinatorAddress))
+	value := (cachedTx.GetTransaction().Tx.Value == 0) && coordinatorKeyManager.IsCoordinatorAddress(cachedTx.GetTransaction().GetAddress())
 	cachedTx.Release(true) // tx -1
 	return value
 }
 
 // Checks if the the tx could be part of a milestone.
 func IsMaybeMilestoneTx(cachedTx *CachedTransaction) bool {
-	value := (cachedTx.GetTransaction().Tx.Value == 0) && (bytes.Equal(cachedTx.GetTransaction().GetAddress(), coordinatorAddress) || bytes.Equal(cachedTx.GetTransaction().GetAddress(), hornet.NullHashBytes))
+	value := (cachedTx.GetTransaction().Tx.Value == 0) && (coordinatorKeyManager.IsCoordinatorAddress(cachedTx.GetTransaction().GetAddress()) || bytes.Equal(cachedTx.GetTransaction().GetAddress(), hornet.NullHashBytes))
 	cachedTx.Release(true) // tx -1
 	return value
 }
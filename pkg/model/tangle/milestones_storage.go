@@ -57,14 +57,18 @@ func configureMilestoneStorage(store kvstore.KVStore, opts profile.CacheOpts) {
 type Milestone struct {
 	objectstorage.StorableObjectFlags
 
+	// The index of the milestone.
 	Index milestone.Index
-	Hash  hornet.Hash
+	// The hash of the tail transaction of the milestone bundle.
+	ID hornet.MilestoneID
+	// The timestamp of the milestone (zero for milestones stored by older versions).
+	Timestamp time.Time
 }
 
 // ObjectStorage interface
 
 func (ms *Milestone) Update(_ objectstorage.StorableObject) {
-	panic(fmt.Sprintf("Milestone should never be updated: %v (%d)", ms.Hash.Trytes(), ms.Index))
+	panic(fmt.Sprintf("Milestone should never be updated: %v (%d)", ms.ID.Trytes(), ms.Index))
 }
 
 func (ms *Milestone) ObjectStorageKey() []byte {
@@ -74,14 +78,25 @@ func (ms *Milestone) ObjectStorageKey() []byte {
 func (ms *Milestone) ObjectStorageValue() (data []byte) {
 	/*
 		49 byte transaction hash
+		 8 byte timestamp
 	*/
-	return ms.Hash
+	value := make([]byte, 57)
+	copy(value, ms.ID[:])
+	binary.LittleEndian.PutUint64(value[49:], uint64(ms.Timestamp.Unix()))
+	return value
 }
 
 func (ms *Milestone) UnmarshalObjectStorageValue(data []byte) (consumedBytes int, err error) {
 
-	ms.Hash = hornet.Hash(data[:49])
-	return 49, nil
+	copy(ms.ID[:], data[:49])
+
+	// milestones stored by older versions don't contain the timestamp
+	if len(data) < 57 {
+		return 49, nil
+	}
+
+	ms.Timestamp = time.Unix(int64(binary.LittleEndian.Uint64(data[49:57])), 0)
+	return 57, nil
 }
 
 // Cached Object
@@ -103,6 +118,19 @@ func GetCachedMilestoneOrNil(milestoneIndex milestone.Index) *CachedMilestone {
 	return &CachedMilestone{CachedObject: cachedMilestone}
 }
 
+// GetMilestoneTimestamp returns the timestamp of the milestone with the given index.
+// The timestamp is zero if the milestone is unknown or was stored by an older version.
+// milestone +-0
+func GetMilestoneTimestamp(milestoneIndex milestone.Index) time.Time {
+	cachedMilestone := GetCachedMilestoneOrNil(milestoneIndex) // milestone +1
+	if cachedMilestone == nil {
+		return time.Time{}
+	}
+	defer cachedMilestone.Release(true) // milestone -1
+
+	return cachedMilestone.GetMilestone().Timestamp
+}
+
 // milestone +-0
 func ContainsMilestone(milestoneIndex milestone.Index) bool {
 	return milestoneStorage.Contains(databaseKeyForMilestoneIndex(milestoneIndex))
@@ -139,10 +167,20 @@ func StoreMilestone(bndl *Bundle) *CachedMilestone {
 
 	if bndl.IsMilestone() {
 
+		msID, err := hornet.MilestoneIDFromHash(bndl.GetMilestoneHash())
+		if err != nil {
+			panic(err)
+		}
+
+		cachedTailTx := bndl.GetTail() // tx +1
 		milestone := &Milestone{
-			Index: bndl.GetMilestoneIndex(),
-			Hash:  bndl.GetMilestoneHash(),
+			Index:     bndl.GetMilestoneIndex(),
+			ID:        msID,
+			Timestamp: time.Unix(cachedTailTx.GetTransaction().GetTimestamp(), 0),
 		}
+		cachedTailTx.Release(true) // tx -1
+
+		bndl.setMilestoneTxsMetadata()
 
 		// milestones should never exist in the database already, even with an unclean database
 		return &CachedMilestone{CachedObject: milestoneStorage.Store(milestone)}
@@ -0,0 +1,29 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeSynced(t *testing.T) {
+	ResetMilestoneIndexes()
+	defer ResetMilestoneIndexes()
+
+	SetLatestMilestoneIndex(100)
+	SetSolidMilestoneIndex(100)
+	assert.True(t, IsNodeSynced())
+	assert.True(t, IsNodeAlmostSynced())
+
+	SetLatestMilestoneIndex(102)
+	assert.False(t, IsNodeSynced())
+	assert.True(t, IsNodeAlmostSynced())
+
+	SetLatestMilestoneIndex(103)
+	assert.False(t, IsNodeSynced())
+	assert.False(t, IsNodeAlmostSynced())
+
+	SetSolidMilestoneIndex(103)
+	assert.True(t, IsNodeSynced())
+	assert.True(t, IsNodeAlmostSynced())
+}
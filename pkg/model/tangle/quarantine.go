@@ -0,0 +1,109 @@
+package tangle
+
+import (
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/kvstore"
+)
+
+var (
+	// whether corrupted entries are skipped instead of crashing the node.
+	quarantineEnabled bool
+	// whether skipped corrupted entries are deleted from the database.
+	quarantineDeleteEntries bool
+)
+
+// CorruptedEntryCaller is the caller of the CorruptedEntryQuarantined event.
+func CorruptedEntryCaller(handler interface{}, params ...interface{}) {
+	handler.(func(err *CorruptedEntryError, deleted bool))(params[0].(*CorruptedEntryError), params[1].(bool))
+}
+
+// EnableCorruptionQuarantine skips corrupted entries while loading them from the database instead of crashing the node.
+// If deleteEntries is true, the skipped entries are deleted from the database.
+// Every skipped entry triggers the CorruptedEntryQuarantined event.
+func EnableCorruptionQuarantine(deleteEntries bool) {
+	quarantineEnabled = true
+	quarantineDeleteEntries = deleteEntries
+}
+
+// IsCorruptionQuarantineEnabled returns whether corrupted entries are skipped while loading them from the database.
+func IsCorruptionQuarantineEnabled() bool {
+	return quarantineEnabled
+}
+
+// EntryValidatorFunc checks whether the given entry of a storage can be parsed.
+type EntryValidatorFunc func(key []byte, value []byte) error
+
+// quarantineStore validates the entries loaded by an object storage before they are passed to its factory.
+// Corrupted entries either panic with a CorruptedEntryError, or are skipped if the quarantine is enabled.
+// Has still reports skipped entries, so the object storage may treat them as existing but not loadable.
+type quarantineStore struct {
+	kvstore.KVStore
+	storage   string
+	validator EntryValidatorFunc
+	events    *events.Event
+}
+
+func newQuarantineStore(store kvstore.KVStore, storage string, validator EntryValidatorFunc) *quarantineStore {
+	return &quarantineStore{
+		KVStore:   store,
+		storage:   storage,
+		validator: validator,
+		events:    Events.CorruptedEntryQuarantined,
+	}
+}
+
+// checks the given entry and returns an error if it is corrupted and has to be skipped.
+func (s *quarantineStore) check(key kvstore.Key, value kvstore.Value) *CorruptedEntryError {
+	err := s.validator(key, value)
+	if err == nil {
+		return nil
+	}
+
+	corruptedErr := NewCorruptedEntryError(s.storage, key, err)
+	if !quarantineEnabled {
+		panic(corruptedErr)
+	}
+	return corruptedErr
+}
+
+// quarantines the given corrupted entry.
+func (s *quarantineStore) quarantine(corruptedErr *CorruptedEntryError) {
+	deleted := false
+	if quarantineDeleteEntries {
+		deleted = s.KVStore.Delete(corruptedErr.Key) == nil
+	}
+	s.events.Trigger(corruptedErr, deleted)
+}
+
+func (s *quarantineStore) Get(key kvstore.Key) (kvstore.Value, error) {
+	value, err := s.KVStore.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if corruptedErr := s.check(key, value); corruptedErr != nil {
+		s.quarantine(corruptedErr)
+		return nil, kvstore.ErrKeyNotFound
+	}
+
+	return value, nil
+}
+
+func (s *quarantineStore) Iterate(prefix kvstore.KeyPrefix, kvConsumerFunc kvstore.IteratorKeyValueConsumerFunc) error {
+	var corruptedErrs []*CorruptedEntryError
+
+	err := s.KVStore.Iterate(prefix, func(key kvstore.Key, value kvstore.Value) bool {
+		if corruptedErr := s.check(key, value); corruptedErr != nil {
+			// the entries are quarantined after the iteration, because the store can't be modified while iterating
+			corruptedErrs = append(corruptedErrs, corruptedErr)
+			return true
+		}
+		return kvConsumerFunc(key, value)
+	})
+
+	for _, corruptedErr := range corruptedErrs {
+		s.quarantine(corruptedErr)
+	}
+
+	return err
+}
@@ -0,0 +1,74 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/objectstorage"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+func TestQuarantineStore(t *testing.T) {
+	valid, corrupted := make(hornet.Hash, 49), make(hornet.Hash, 49)
+	valid[0], corrupted[0] = 1, 2
+
+	metadata := hornet.NewTransactionMetadata(valid)
+	metadata.SetAdditionalTxInfo(hornet.Hashes{valid, valid}, valid, true, true, false)
+
+	store := mapdb.NewMapDB()
+	require.NoError(t, store.Set(valid, metadata.ObjectStorageValue()))
+	require.NoError(t, store.Set(corrupted, []byte{1, 2, 3}))
+
+	quarantined := newQuarantineStore(store, metadataStorageName, validateMetadataEntry)
+	storage := objectstorage.New(quarantined, metadataFactory, objectstorage.PersistenceEnabled(true))
+	defer storage.Shutdown()
+
+	// corrupted entries crash the node by default with a typed error
+	func() {
+		defer func() {
+			err, ok := recover().(error)
+			require.True(t, ok)
+			assert.True(t, errors.Is(err, ErrCorruptedEntry))
+			assert.True(t, errors.Is(err, hornet.ErrInvalidMetadataLength))
+		}()
+		_, _ = quarantined.Get(corrupted)
+	}()
+
+	EnableCorruptionQuarantine(true)
+	defer func() {
+		quarantineEnabled, quarantineDeleteEntries = false, false
+	}()
+
+	var quarantinedKeys []string
+	closure := events.NewClosure(func(err *CorruptedEntryError, deleted bool) {
+		assert.True(t, deleted)
+		quarantinedKeys = append(quarantinedKeys, string(err.Key))
+	})
+	Events.CorruptedEntryQuarantined.Attach(closure)
+	defer Events.CorruptedEntryQuarantined.Detach(closure)
+
+	// corrupted entries are skipped while iterating
+	var loaded []string
+	storage.ForEach(func(key []byte, cachedObject objectstorage.CachedObject) bool {
+		loaded = append(loaded, string(key))
+		cachedObject.Release(true)
+		return true
+	})
+	assert.Equal(t, []string{string(valid)}, loaded)
+	assert.Equal(t, []string{string(corrupted)}, quarantinedKeys)
+
+	// and deleted from the database
+	_, err := store.Get(corrupted)
+	assert.Equal(t, kvstore.ErrKeyNotFound, err)
+
+	cachedMeta := storage.Load(valid)
+	assert.True(t, cachedMeta.Exists())
+	cachedMeta.Release(true)
+}
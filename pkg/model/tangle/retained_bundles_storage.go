@@ -0,0 +1,119 @@
+package tangle
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+const (
+	// the length of the confirmation timestamp of a retained bundle in the value.
+	retainedBundleTimestampLength = 8
+)
+
+var (
+	// retainedBundlesStore contains the bundles which are kept by a retention rule, keyed by their tail hash.
+	retainedBundlesStore kvstore.KVStore
+)
+
+// RetainedBundle is a bundle which is kept by a retention rule after the milestone which confirmed it was pruned.
+type RetainedBundle struct {
+	TailHash hornet.Hash
+	// ConfirmationTimestamp is the timestamp of the milestone which confirmed the bundle.
+	ConfirmationTimestamp time.Time
+	// TxHashes are the hashes of all transactions of the bundle, including the tail.
+	TxHashes hornet.Hashes
+}
+
+func configureRetainedBundlesStore(store kvstore.KVStore) {
+	retainedBundlesStore = store.WithRealm([]byte{StorePrefixRetainedBundles})
+}
+
+func (b *RetainedBundle) value() []byte {
+	value := make([]byte, retainedBundleTimestampLength, retainedBundleTimestampLength+len(b.TxHashes)*hornet.HashBinarySize)
+	binary.LittleEndian.PutUint64(value, uint64(b.ConfirmationTimestamp.Unix()))
+	for _, txHash := range b.TxHashes {
+		value = append(value, txHash...)
+	}
+	return value
+}
+
+func retainedBundleFromDatabase(key []byte, value []byte) (*RetainedBundle, error) {
+	if len(key) != hornet.HashBinarySize {
+		return nil, errors.Wrapf(ErrInvalidKeyLength, "%d bytes", len(key))
+	}
+	if len(value) < retainedBundleTimestampLength || (len(value)-retainedBundleTimestampLength)%hornet.HashBinarySize != 0 {
+		return nil, errors.Wrapf(ErrInvalidValueLength, "%d bytes", len(value))
+	}
+
+	b := &RetainedBundle{
+		TailHash:              hornet.Hash(append([]byte{}, key...)),
+		ConfirmationTimestamp: time.Unix(int64(binary.LittleEndian.Uint64(value[:retainedBundleTimestampLength])), 0),
+	}
+	for offset := retainedBundleTimestampLength; offset < len(value); offset += hornet.HashBinarySize {
+		b.TxHashes = append(b.TxHashes, hornet.Hash(append([]byte{}, value[offset:offset+hornet.HashBinarySize]...)))
+	}
+	return b, nil
+}
+
+// StoreRetainedBundle marks the bundle as retained, so it is not removed while its milestone is pruned.
+func StoreRetainedBundle(b *RetainedBundle) error {
+	if err := retainedBundlesStore.Set(b.TailHash, b.value()); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to store retained bundle")
+	}
+	return nil
+}
+
+// DeleteRetainedBundle removes the mark of the retained bundle with the given tail hash.
+func DeleteRetainedBundle(tailHash hornet.Hash) error {
+	if err := retainedBundlesStore.Delete(tailHash); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to delete retained bundle")
+	}
+	return nil
+}
+
+// RetainedBundleConsumer consumes the given retained bundle during looping through all retained bundles.
+// Returning false stops the iteration.
+type RetainedBundleConsumer func(b *RetainedBundle) bool
+
+// ForEachRetainedBundle loops over all retained bundles, invalid entries are skipped.
+func ForEachRetainedBundle(consumer RetainedBundleConsumer) error {
+	var bundles []*RetainedBundle
+	if err := retainedBundlesStore.Iterate([]byte{}, func(key kvstore.Key, value kvstore.Value) bool {
+		b, err := retainedBundleFromDatabase(key, value)
+		if err != nil {
+			return true
+		}
+		bundles = append(bundles, b)
+		return true
+	}); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to iterate retained bundles")
+	}
+
+	// the consumer is called outside of the iteration, so it may delete the entries
+	for _, b := range bundles {
+		if !consumer(b) {
+			break
+		}
+	}
+	return nil
+}
+
+// GetRetainedTransactions returns the hashes of the transactions of all retained bundles.
+func GetRetainedTransactions() (map[string]struct{}, error) {
+	txHashes := make(map[string]struct{})
+	if err := ForEachRetainedBundle(func(b *RetainedBundle) bool {
+		for _, txHash := range b.TxHashes {
+			txHashes[string(txHash)] = struct{}{}
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	return txHashes, nil
+}
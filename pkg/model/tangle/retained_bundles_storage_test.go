@@ -0,0 +1,48 @@
+package tangle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+func TestRetainedBundles(t *testing.T) {
+	configureRetainedBundlesStore(mapdb.NewMapDB())
+
+	tailHash := hornet.Hash(make([]byte, hornet.HashBinarySize))
+	txHash := hornet.Hash(make([]byte, hornet.HashBinarySize))
+	txHash[0] = 1
+
+	confirmed := time.Unix(1600000000, 0)
+	require.NoError(t, StoreRetainedBundle(&RetainedBundle{
+		TailHash:              tailHash,
+		ConfirmationTimestamp: confirmed,
+		TxHashes:              hornet.Hashes{tailHash, txHash},
+	}))
+
+	var bundles []*RetainedBundle
+	require.NoError(t, ForEachRetainedBundle(func(b *RetainedBundle) bool {
+		bundles = append(bundles, b)
+		return true
+	}))
+	require.Len(t, bundles, 1)
+	assert.Equal(t, tailHash, bundles[0].TailHash)
+	assert.True(t, confirmed.Equal(bundles[0].ConfirmationTimestamp))
+	assert.Equal(t, hornet.Hashes{tailHash, txHash}, bundles[0].TxHashes)
+
+	retained, err := GetRetainedTransactions()
+	require.NoError(t, err)
+	assert.Len(t, retained, 2)
+	assert.Contains(t, retained, string(txHash))
+
+	require.NoError(t, DeleteRetainedBundle(tailHash))
+	retained, err = GetRetainedTransactions()
+	require.NoError(t, err)
+	assert.Empty(t, retained)
+}
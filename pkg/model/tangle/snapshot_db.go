@@ -136,8 +136,13 @@ func GetAllSnapshotBalances(abortSignal <-chan struct{}) (map[string]uint64, mil
 		return nil, 0, errors.Wrap(NewDatabaseError(err), "failed to retrieve snapshot milestone index")
 	}
 
+	if len(value) != 4 {
+		return nil, 0, errors.Wrapf(NewDatabaseError(ErrInvalidValueLength), "failed to retrieve snapshot milestone index, %d bytes", len(value))
+	}
+
 	snapshotMilestoneIndex := milestoneIndexFromBytes(value)
 
+	var innerErr error
 	err = snapshotLedgerStore.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
 		select {
 		case <-abortSignal:
@@ -145,6 +150,9 @@ func GetAllSnapshotBalances(abortSignal <-chan struct{}) (map[string]uint64, mil
 		default:
 		}
 
+		if innerErr = checkLedgerEntryLength(key, value); innerErr != nil {
+			return false
+		}
 		balances[string(key[:49])] = balanceFromBytes(value)
 		return true
 	})
@@ -153,6 +161,10 @@ func GetAllSnapshotBalances(abortSignal <-chan struct{}) (map[string]uint64, mil
 		return nil, 0, err
 	}
 
+	if innerErr != nil {
+		return nil, 0, errors.Wrap(NewDatabaseError(innerErr), "failed to read snapshot balances")
+	}
+
 	var total uint64
 	for _, value := range balances {
 		total += value
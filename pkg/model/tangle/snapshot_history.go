@@ -0,0 +1,164 @@
+package tangle
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotaledger/hive.go/kvstore"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/networkid"
+)
+
+const (
+	snapshotHistoryKey    = "snapshotHistory"
+	snapshotHistoryLength = 8 + 4 + 8 + 4 + blake2b.Size256
+)
+
+var (
+	// ErrSnapshotDowngrade is returned if a snapshot is older than the ledger state or the snapshots already loaded into the database.
+	ErrSnapshotDowngrade = errors.New("snapshot is older than the local ledger")
+	// ErrForeignSnapshot is returned if a snapshot belongs to another network than the snapshots already loaded into the database.
+	ErrForeignSnapshot = errors.New("snapshot belongs to another network than the local ledger")
+
+	ErrParseSnapshotHistoryFailed = errors.New("Parsing of snapshot history failed")
+)
+
+// SnapshotHistory holds the metadata of the snapshots which were loaded into the database,
+// so that the node refuses to roll back its ledger by loading an older or foreign snapshot.
+type SnapshotHistory struct {
+	// The network ID of the loaded snapshots, networkid.Unknown if none of them carried a network ID.
+	NetworkID networkid.ID
+	// The milestone index of the latest loaded snapshot.
+	LedgerIndex milestone.Index
+	// The timestamp of the latest loaded snapshot.
+	Timestamp int64
+	// The amount of loaded snapshots.
+	Generation uint32
+	// The hash chain over the milestones of all loaded full snapshots.
+	FullSnapshotChain []byte
+}
+
+// SnapshotHistoryFromBytes parses the given bytes to a SnapshotHistory.
+func SnapshotHistoryFromBytes(bytes []byte) (*SnapshotHistory, error) {
+
+	if len(bytes) != snapshotHistoryLength {
+		return nil, errors.Wrapf(ErrParseSnapshotHistoryFailed, "Invalid length %d != %d", len(bytes), snapshotHistoryLength)
+	}
+
+	return &SnapshotHistory{
+		NetworkID:         networkid.ID(binary.LittleEndian.Uint64(bytes[:8])),
+		LedgerIndex:       milestone.Index(binary.LittleEndian.Uint32(bytes[8:12])),
+		Timestamp:         int64(binary.LittleEndian.Uint64(bytes[12:20])),
+		Generation:        binary.LittleEndian.Uint32(bytes[20:24]),
+		FullSnapshotChain: append([]byte{}, bytes[24:]...),
+	}, nil
+}
+
+// GetBytes returns the serialized SnapshotHistory.
+func (h *SnapshotHistory) GetBytes() []byte {
+	bytes := make([]byte, 24, snapshotHistoryLength)
+
+	binary.LittleEndian.PutUint64(bytes[:8], uint64(h.NetworkID))
+	binary.LittleEndian.PutUint32(bytes[8:12], uint32(h.LedgerIndex))
+	binary.LittleEndian.PutUint64(bytes[12:20], uint64(h.Timestamp))
+	binary.LittleEndian.PutUint32(bytes[20:24], h.Generation)
+
+	chain := h.FullSnapshotChain
+	if len(chain) != blake2b.Size256 {
+		chain = make([]byte, blake2b.Size256)
+	}
+	return append(bytes, chain...)
+}
+
+// Check returns an error if a snapshot with the given metadata would roll back the ledger of the database,
+// because it is older than the latest loaded snapshot or the given ledger index, or belongs to another network.
+// Snapshots without a network ID or timestamp are only checked against the milestone index.
+func (h *SnapshotHistory) Check(fileNetworkID networkid.ID, msIndex milestone.Index, timestamp int64, ledgerIndex milestone.Index) error {
+
+	if h != nil {
+		if h.NetworkID != networkid.Unknown && fileNetworkID != networkid.Unknown && h.NetworkID != fileNetworkID {
+			return errors.Wrapf(ErrForeignSnapshot, "network ID of the snapshot is %s, network ID of the loaded snapshots is %s", fileNetworkID, h.NetworkID)
+		}
+
+		if msIndex < h.LedgerIndex {
+			return errors.Wrapf(ErrSnapshotDowngrade, "snapshot milestone %d is older than the loaded snapshot milestone %d", msIndex, h.LedgerIndex)
+		}
+
+		if timestamp != 0 && timestamp < h.Timestamp {
+			return errors.Wrapf(ErrSnapshotDowngrade, "snapshot timestamp %d is older than the timestamp of the loaded snapshot %d", timestamp, h.Timestamp)
+		}
+	}
+
+	if msIndex < ledgerIndex {
+		return errors.Wrapf(ErrSnapshotDowngrade, "snapshot milestone %d is older than the ledger milestone %d", msIndex, ledgerIndex)
+	}
+
+	return nil
+}
+
+// Extend returns the history after a snapshot with the given metadata was loaded.
+// Only full snapshots are added to the hash chain, delta snapshots are linked to their full snapshot by the file itself.
+func (h *SnapshotHistory) Extend(fileNetworkID networkid.ID, msHash hornet.Hash, msIndex milestone.Index, timestamp int64, fullSnapshot bool) *SnapshotHistory {
+
+	extended := &SnapshotHistory{
+		NetworkID:         fileNetworkID,
+		LedgerIndex:       msIndex,
+		Timestamp:         timestamp,
+		FullSnapshotChain: make([]byte, blake2b.Size256),
+	}
+
+	if h != nil {
+		if extended.NetworkID == networkid.Unknown {
+			extended.NetworkID = h.NetworkID
+		}
+		extended.Generation = h.Generation
+		if len(h.FullSnapshotChain) == blake2b.Size256 {
+			copy(extended.FullSnapshotChain, h.FullSnapshotChain)
+		}
+	}
+	extended.Generation++
+
+	if fullSnapshot {
+		indexBytes := make([]byte, 4)
+		binary.LittleEndian.PutUint32(indexBytes, uint32(msIndex))
+
+		chain, _ := blake2b.New256(nil)
+		chain.Write(extended.FullSnapshotChain)
+		chain.Write(msHash)
+		chain.Write(indexBytes)
+		extended.FullSnapshotChain = chain.Sum(nil)
+	}
+
+	return extended
+}
+
+// StoreSnapshotHistory stores the given snapshot history in the database.
+func StoreSnapshotHistory(history *SnapshotHistory) error {
+
+	if err := snapshotStore.Set([]byte(snapshotHistoryKey), history.GetBytes()); err != nil {
+		return errors.Wrap(NewDatabaseError(err), "failed to store snapshot history")
+	}
+
+	return nil
+}
+
+// ReadSnapshotHistory returns the snapshot history of the database, nil if no snapshot history was stored yet.
+func ReadSnapshotHistory() (*SnapshotHistory, error) {
+	value, err := snapshotStore.Get([]byte(snapshotHistoryKey))
+	if err != nil {
+		if err != kvstore.ErrKeyNotFound {
+			return nil, errors.Wrap(NewDatabaseError(err), "failed to retrieve snapshot history")
+		}
+		return nil, nil
+	}
+
+	history, err := SnapshotHistoryFromBytes(value)
+	if err != nil {
+		return nil, errors.Wrap(NewDatabaseError(err), "failed to convert snapshot history")
+	}
+	return history, nil
+}
@@ -0,0 +1,54 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/networkid"
+)
+
+func TestSnapshotHistory(t *testing.T) {
+	mainnet := networkid.FromName("mainnet")
+	msHash := hornet.Hash("milestone")
+
+	var history *SnapshotHistory
+	require.NoError(t, history.Check(networkid.Unknown, 100, 0, 0))
+	// a newer ledger is protected even without a history
+	assert.True(t, errors.Is(history.Check(mainnet, 100, 1000, 101), ErrSnapshotDowngrade))
+
+	history = history.Extend(mainnet, msHash, 100, 1000, true)
+	assert.Equal(t, mainnet, history.NetworkID)
+	assert.EqualValues(t, 1, history.Generation)
+
+	restored, err := SnapshotHistoryFromBytes(history.GetBytes())
+	require.NoError(t, err)
+	assert.Equal(t, history, restored)
+
+	// the same snapshot may be loaded again
+	require.NoError(t, history.Check(mainnet, 100, 1000, 100))
+	// snapshots without a network ID are accepted
+	require.NoError(t, history.Check(networkid.Unknown, 110, 0, 100))
+
+	assert.True(t, errors.Is(history.Check(mainnet, 99, 1000, 0), ErrSnapshotDowngrade))
+	assert.True(t, errors.Is(history.Check(mainnet, 110, 999, 0), ErrSnapshotDowngrade))
+	assert.True(t, errors.Is(history.Check(networkid.FromName("comnet"), 110, 1100, 0), ErrForeignSnapshot))
+
+	// delta snapshots don't change the chain of the full snapshots
+	delta := history.Extend(networkid.Unknown, hornet.Hash("delta"), 110, 1100, false)
+	assert.Equal(t, mainnet, delta.NetworkID)
+	assert.EqualValues(t, 2, delta.Generation)
+	assert.Equal(t, history.FullSnapshotChain, delta.FullSnapshotChain)
+
+	full := delta.Extend(mainnet, hornet.Hash("full"), 120, 1200, true)
+	assert.NotEqual(t, delta.FullSnapshotChain, full.FullSnapshotChain)
+	// the chain depends on all previous full snapshots
+	other := (*SnapshotHistory)(nil).Extend(mainnet, hornet.Hash("full"), 120, 1200, true)
+	assert.NotEqual(t, other.FullSnapshotChain, full.FullSnapshotChain)
+
+	_, err = SnapshotHistoryFromBytes([]byte{1, 2, 3})
+	assert.True(t, errors.Is(err, ErrParseSnapshotHistoryFailed))
+}
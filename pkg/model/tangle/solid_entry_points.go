@@ -17,6 +17,12 @@ var (
 	ErrSolidEntryPointsNotInitialized     = errors.New("solidEntryPoints not initialized")
 )
 
+// SolidEntryPoint is a transaction hash with the index of the milestone which confirmed it.
+type SolidEntryPoint struct {
+	TxHash hornet.Hash
+	Index  milestone.Index
+}
+
 func ReadLockSolidEntryPoints() {
 	solidEntryPointsLock.RLock()
 }
@@ -92,3 +98,28 @@ func StoreSolidEntryPoints() {
 	}
 	storeSolidEntryPoints(solidEntryPoints)
 }
+
+// ForEachSolidEntryPoint calls the consumer for every solid entry point while holding the read lock.
+// The iteration is stopped if the consumer returns false.
+func ForEachSolidEntryPoint(consumer func(txHash hornet.Hash, milestoneIndex milestone.Index) bool) {
+	ReadLockSolidEntryPoints()
+	defer ReadUnlockSolidEntryPoints()
+
+	if solidEntryPoints == nil {
+		panic(ErrSolidEntryPointsNotInitialized)
+	}
+	solidEntryPoints.ForEach(consumer)
+}
+
+// ReplaceSolidEntryPoints atomically replaces all solid entry points with the given ones and stores them.
+// The solid entry points are added in the given order, if a hash is given more than once, the first index is kept.
+func ReplaceSolidEntryPoints(newSolidEntryPoints []*SolidEntryPoint) {
+	WriteLockSolidEntryPoints()
+	defer WriteUnlockSolidEntryPoints()
+
+	ResetSolidEntryPoints()
+	for _, solidEntryPoint := range newSolidEntryPoints {
+		SolidEntryPointsAdd(solidEntryPoint.TxHash, solidEntryPoint.Index)
+	}
+	StoreSolidEntryPoints()
+}
@@ -0,0 +1,47 @@
+package tangle_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite"
+)
+
+func TestReplaceSolidEntryPoints(t *testing.T) {
+	te := testsuite.SetupTestEnvironment(t, make(map[string]uint64), 0, false)
+	defer te.CleanupTestEnvironment(true)
+
+	hashA := hornet.Hash(bytes.Repeat([]byte{3}, 49))
+	hashB := hornet.Hash(bytes.Repeat([]byte{1}, 49))
+	hashC := hornet.Hash(bytes.Repeat([]byte{2}, 49))
+
+	tangle.ReplaceSolidEntryPoints([]*tangle.SolidEntryPoint{
+		{TxHash: hashA, Index: 5},
+		{TxHash: hashB, Index: 7},
+		{TxHash: hashC, Index: 6},
+		// the first index of a duplicate is kept
+		{TxHash: hashB, Index: 9},
+	})
+
+	var hashes hornet.Hashes
+	var indexes []milestone.Index
+	tangle.ForEachSolidEntryPoint(func(txHash hornet.Hash, milestoneIndex milestone.Index) bool {
+		hashes = append(hashes, txHash)
+		indexes = append(indexes, milestoneIndex)
+		return true
+	})
+	require.Equal(t, hornet.Hashes{hashA, hashB, hashC}, hashes)
+	require.Equal(t, []milestone.Index{5, 7, 6}, indexes)
+
+	// the previous solid entry points were removed
+	require.False(t, tangle.SolidEntryPointsContain(hornet.NullHashBytes))
+
+	index, exists := tangle.SolidEntryPointsIndex(hashB)
+	require.True(t, exists)
+	require.Equal(t, milestone.Index(7), index)
+}
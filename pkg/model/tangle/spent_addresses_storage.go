@@ -6,6 +6,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/objectstorage"
 
@@ -43,6 +45,10 @@ func (c *CachedSpentAddress) GetSpentAddress() *hornet.SpentAddress {
 }
 
 func spentAddressFactory(key []byte) (objectstorage.StorableObject, int, error) {
+	if len(key) < 49 {
+		return nil, 0, errors.Wrapf(ErrInvalidKeyLength, "%d bytes", len(key))
+	}
+
 	sa := hornet.NewSpentAddress(key[:49])
 	return sa, 49, nil
 }
@@ -96,11 +102,20 @@ func MarkAddressAsSpentWithoutLocking(address hornet.Hash) bool {
 
 // StreamSpentAddressesToWriter streams all spent addresses directly to an io.Writer.
 func StreamSpentAddressesToWriter(buf io.Writer, abortSignal <-chan struct{}) (int32, error) {
+	return StreamSpentAddressesToConsumer(func(address hornet.Hash) error {
+		return binary.Write(buf, binary.LittleEndian, address)
+	}, abortSignal)
+}
+
+// StreamSpentAddressesToConsumer passes all spent addresses to the given consumer.
+// The iteration stops at the first error of the consumer.
+func StreamSpentAddressesToConsumer(consumer func(address hornet.Hash) error, abortSignal <-chan struct{}) (int32, error) {
 
 	ReadLockSpentAddresses()
 	defer ReadUnlockSpentAddresses()
 
-	var addressesWritten int32
+	var addressesConsumed int32
+	var innerErr error
 
 	wasAborted := false
 	spentAddressesStorage.ForEachKeyOnly(func(key []byte) bool {
@@ -111,15 +126,19 @@ func StreamSpentAddressesToWriter(buf io.Writer, abortSignal <-chan struct{}) (i
 		default:
 		}
 
-		addressesWritten++
-		return binary.Write(buf, binary.LittleEndian, key) == nil
+		if innerErr = consumer(key); innerErr != nil {
+			return false
+		}
+
+		addressesConsumed++
+		return true
 	}, false)
 
 	if wasAborted {
 		return 0, ErrOperationAborted
 	}
 
-	return addressesWritten, nil
+	return addressesConsumed, innerErr
 }
 
 func ShutdownSpentAddressesStorage() {
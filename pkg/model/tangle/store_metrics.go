@@ -0,0 +1,220 @@
+package tangle
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/iotaledger/hive.go/kvstore"
+
+	"github.com/gohornet/hornet/pkg/database"
+)
+
+type storeOperation int
+
+const (
+	storeOperationGet storeOperation = iota
+	storeOperationHas
+	storeOperationSet
+	storeOperationDelete
+	storeOperationDeletePrefix
+	storeOperationIterate
+	storeOperationIterateKeys
+	storeOperationClear
+	storeOperationBatchCommit
+	storeOperationCount
+)
+
+var (
+	storeOperationNames = [storeOperationCount]string{
+		storeOperationGet:          "get",
+		storeOperationHas:          "has",
+		storeOperationSet:          "set",
+		storeOperationDelete:       "delete",
+		storeOperationDeletePrefix: "deletePrefix",
+		storeOperationIterate:      "iterate",
+		storeOperationIterateKeys:  "iterateKeys",
+		storeOperationClear:        "clear",
+		storeOperationBatchCommit:  "batchCommit",
+	}
+
+	// whether the operations on the stores are measured.
+	storeMetricsEnabled bool
+
+	storeMetricsLock   sync.Mutex
+	storeMetricsByName = make(map[string]*storeMetrics)
+)
+
+// EnableStoreMetrics measures the count and the duration of the operations on the stores of the databases.
+// It has to be called before the databases are configured.
+func EnableStoreMetrics() {
+	storeMetricsEnabled = true
+}
+
+// IsStoreMetricsEnabled returns whether the operations on the stores are measured.
+func IsStoreMetricsEnabled() bool {
+	return storeMetricsEnabled
+}
+
+// StoreOperationMetrics holds the metrics of one kind of operation on a store.
+type StoreOperationMetrics struct {
+	// The name of the store, e.g. "transactions".
+	Store string
+	// The name of the operation, e.g. "get".
+	Operation string
+	// The amount of executed operations.
+	Count uint64
+	// The total duration of the executed operations.
+	// The duration of iterations includes the time spent in the consumer.
+	Duration time.Duration
+}
+
+// GetStoreMetrics returns the metrics of all operations which were executed on the stores, sorted by store and operation.
+func GetStoreMetrics() []*StoreOperationMetrics {
+	storeMetricsLock.Lock()
+	defer storeMetricsLock.Unlock()
+
+	var result []*StoreOperationMetrics
+	for name, metrics := range storeMetricsByName {
+		for operation := storeOperation(0); operation < storeOperationCount; operation++ {
+			count := metrics.counts[operation].Load()
+			if count == 0 {
+				continue
+			}
+			result = append(result, &StoreOperationMetrics{
+				Store:     name,
+				Operation: storeOperationNames[operation],
+				Count:     count,
+				Duration:  time.Duration(metrics.durations[operation].Load()),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Store != result[j].Store {
+			return result[i].Store < result[j].Store
+		}
+		return result[i].Operation < result[j].Operation
+	})
+	return result
+}
+
+type storeMetrics struct {
+	counts    [storeOperationCount]atomic.Uint64
+	durations [storeOperationCount]atomic.Int64
+}
+
+// observe adds an operation which started at the given time.
+func (m *storeMetrics) observe(operation storeOperation, start time.Time) {
+	m.counts[operation].Inc()
+	m.durations[operation].Add(int64(time.Since(start)))
+}
+
+// returns the metrics of the store with the given name and creates them if they don't exist yet.
+func getStoreMetrics(name string) *storeMetrics {
+	storeMetricsLock.Lock()
+	defer storeMetricsLock.Unlock()
+
+	metrics, exists := storeMetricsByName[name]
+	if !exists {
+		metrics = &storeMetrics{}
+		storeMetricsByName[name] = metrics
+	}
+	return metrics
+}
+
+// returns the name of the store in the given realm of the given database, as registered in the store prefixes.
+func storeNameForRealm(database string, realm kvstore.Realm) string {
+	if len(realm) == 0 {
+		return database
+	}
+	for _, storePrefix := range storePrefixes {
+		if storePrefix.Prefix == realm[0] && storePrefix.Database == database {
+			return storePrefix.Name
+		}
+	}
+	return fmt.Sprintf("%s/%d", database, realm[0])
+}
+
+// metricsStore measures the operations on a store and accounts them to the store of its realm.
+// Stores derived with WithRealm are measured as well.
+type metricsStore struct {
+	kvstore.KVStore
+	database string
+	metrics  *storeMetrics
+}
+
+func newMetricsStore(store kvstore.KVStore, database string) *metricsStore {
+	return &metricsStore{
+		KVStore:  store,
+		database: database,
+		metrics:  getStoreMetrics(storeNameForRealm(database, store.Realm())),
+	}
+}
+
+func (s *metricsStore) WithRealm(realm kvstore.Realm) kvstore.KVStore {
+	return newMetricsStore(s.KVStore.WithRealm(realm), s.database)
+}
+
+func (s *metricsStore) Iterate(prefix kvstore.KeyPrefix, kvConsumerFunc kvstore.IteratorKeyValueConsumerFunc) error {
+	defer s.metrics.observe(storeOperationIterate, time.Now())
+	return s.KVStore.Iterate(prefix, kvConsumerFunc)
+}
+
+func (s *metricsStore) IterateKeys(prefix kvstore.KeyPrefix, consumerFunc kvstore.IteratorKeyConsumerFunc) error {
+	defer s.metrics.observe(storeOperationIterateKeys, time.Now())
+	return s.KVStore.IterateKeys(prefix, consumerFunc)
+}
+
+func (s *metricsStore) IterateKeysFrom(prefix kvstore.KeyPrefix, start kvstore.Key, consumerFunc kvstore.IteratorKeyConsumerFunc) error {
+	defer s.metrics.observe(storeOperationIterateKeys, time.Now())
+	return database.IterateKeysFrom(s.KVStore, prefix, start, consumerFunc)
+}
+
+func (s *metricsStore) Clear() error {
+	defer s.metrics.observe(storeOperationClear, time.Now())
+	return s.KVStore.Clear()
+}
+
+func (s *metricsStore) Get(key kvstore.Key) (kvstore.Value, error) {
+	defer s.metrics.observe(storeOperationGet, time.Now())
+	return s.KVStore.Get(key)
+}
+
+func (s *metricsStore) Set(key kvstore.Key, value kvstore.Value) error {
+	defer s.metrics.observe(storeOperationSet, time.Now())
+	return s.KVStore.Set(key, value)
+}
+
+func (s *metricsStore) Has(key kvstore.Key) (bool, error) {
+	defer s.metrics.observe(storeOperationHas, time.Now())
+	return s.KVStore.Has(key)
+}
+
+func (s *metricsStore) Delete(key kvstore.Key) error {
+	defer s.metrics.observe(storeOperationDelete, time.Now())
+	return s.KVStore.Delete(key)
+}
+
+func (s *metricsStore) DeletePrefix(prefix kvstore.KeyPrefix) error {
+	defer s.metrics.observe(storeOperationDeletePrefix, time.Now())
+	return s.KVStore.DeletePrefix(prefix)
+}
+
+func (s *metricsStore) Batched() kvstore.BatchedMutations {
+	return &metricsBatchedMutations{BatchedMutations: s.KVStore.Batched(), metrics: s.metrics}
+}
+
+// metricsBatchedMutations measures the commit of batched mutations, the mutations themselves are only buffered.
+type metricsBatchedMutations struct {
+	kvstore.BatchedMutations
+	metrics *storeMetrics
+}
+
+func (b *metricsBatchedMutations) Commit() error {
+	defer b.metrics.observe(storeOperationBatchCommit, time.Now())
+	return b.BatchedMutations.Commit()
+}
@@ -0,0 +1,42 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+)
+
+func TestMetricsStore(t *testing.T) {
+	// the metrics are accounted to the registered name of the realm
+	store := newMetricsStore(mapdb.NewMapDB(), SpentAddressesDbFilename)
+	spentStore := store.WithRealm([]byte{StorePrefixSpentAddresses})
+	unknownStore := store.WithRealm([]byte{StorePrefixTransactions})
+
+	require.NoError(t, spentStore.Set([]byte("a"), []byte{1}))
+	_, err := spentStore.Get([]byte("a"))
+	require.NoError(t, err)
+	_, err = spentStore.Get([]byte("b"))
+	assert.Equal(t, kvstore.ErrKeyNotFound, err)
+	require.NoError(t, spentStore.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool { return true }))
+
+	batch := unknownStore.Batched()
+	require.NoError(t, batch.Set([]byte("a"), []byte{1}))
+	require.NoError(t, batch.Delete([]byte("b")))
+	require.NoError(t, batch.Commit())
+
+	counts := make(map[string]uint64)
+	for _, metrics := range GetStoreMetrics() {
+		counts[metrics.Store+":"+metrics.Operation] = metrics.Count
+	}
+
+	assert.EqualValues(t, 1, counts["spentAddresses:set"])
+	assert.EqualValues(t, 2, counts["spentAddresses:get"])
+	assert.EqualValues(t, 1, counts["spentAddresses:iterate"])
+	// only the commit of a batch accesses the store
+	assert.EqualValues(t, 1, counts["spent.db/1:batchCommit"])
+	assert.NotContains(t, counts, "spent.db/1:set")
+}
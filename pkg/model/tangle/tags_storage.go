@@ -1,16 +1,22 @@
 package tangle
 
 import (
+	"bytes"
 	"time"
 
 	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/objectstorage"
 
+	"github.com/gohornet/hornet/pkg/database"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/profile"
 )
 
-var tagsStorage *objectstorage.ObjectStorage
+var (
+	tagsStorage *objectstorage.ObjectStorage
+	// the store of the tags, which is iterated in order for the pagination.
+	tagsStore kvstore.KVStore
+)
 
 type CachedTag struct {
 	objectstorage.CachedObject
@@ -40,8 +46,10 @@ func GetTagsStorageSize() int {
 
 func configureTagsStorage(store kvstore.KVStore, opts profile.CacheOpts) {
 
+	tagsStore = store.WithRealm([]byte{StorePrefixTags})
+
 	tagsStorage = objectstorage.New(
-		store.WithRealm([]byte{StorePrefixTags}),
+		tagsStore,
 		tagsFactory,
 		objectstorage.CacheTime(time.Duration(opts.CacheTimeMs)*time.Millisecond),
 		objectstorage.PersistenceEnabled(true),
@@ -74,6 +82,35 @@ func GetTagHashes(txTag hornet.Hash, forceRelease bool, maxFind ...int) hornet.H
 	return tagHashes
 }
 
+// GetTagHashesPage returns up to limit hashes of transactions with the given tag, which follow the given cursor.
+// the hashes are sorted, so the last returned hash can be used as the cursor for the next page.
+// the returned cursor is nil if there are no more hashes.
+// the keys of the persisted tags are iterated in order starting at the cursor, so only the requested page is read.
+// tag +-0
+func GetTagHashesPage(txTag hornet.Hash, cursor hornet.Hash, limit int) (tagHashes hornet.Hashes, nextCursor hornet.Hash) {
+
+	prefix := txTag[:17]
+	start := append(append(hornet.Hash{}, prefix...), cursor...)
+
+	_ = database.IterateKeysFrom(tagsStore, prefix, start, func(key kvstore.Key) bool {
+		txHash := hornet.Hash(key[17:66])
+		if cursor != nil && bytes.Equal(txHash, cursor) {
+			return true
+		}
+
+		if len(tagHashes) == limit {
+			// there is at least one more hash
+			nextCursor = tagHashes[limit-1]
+			return false
+		}
+
+		tagHashes = append(tagHashes, txHash)
+		return true
+	})
+
+	return tagHashes, nextCursor
+}
+
 // ContainsTag returns if the given tag exists in the cache/persistence layer.
 func ContainsTag(txTag hornet.Hash, txHash hornet.Hash) bool {
 	return tagsStorage.Contains(append(txTag, txHash...))
@@ -0,0 +1,54 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/profile"
+)
+
+func TestGetTagHashesPage(t *testing.T) {
+	configureTagsStorage(mapdb.NewMapDB(), profile.CacheOpts{})
+	defer ShutdownTagsStorage()
+
+	tag := hornet.HashFromTagTrytes("HORNET99INTEGRATED99SPAMMER")
+	otherTag := hornet.HashFromTagTrytes("ANOTHER99TAG999999999999999")
+
+	txHashes := hornet.Hashes{
+		hornet.HashFromHashTrytes("HZDFGXEBKOBTTEVBAABTDQKXBBAWFXZYBS9WZHHRGUNHBNXBHPCXAEDDWRKUISHKPTYHJQQPGFOXZ9999"),
+		hornet.HashFromHashTrytes("PWMVVQSDHAZBMXYXVLAHMQZFXOSOTRKRVFANFDKDUTPTKDLXGHFBRWSLBJEZMWTUWTGJUDBVFVZRZ9999"),
+		hornet.HashFromHashTrytes("TMVWMOEXTVQMBEOXIUOKGQSCZRXVSHFMDNTYDCXNFJCYDYWGHXTVGQESGSMRGBMYPDHHFNLUKOGVZ9999"),
+	}
+	for _, txHash := range txHashes {
+		StoreTag(tag, txHash).Release(true)
+	}
+	StoreTag(otherTag, txHashes[0]).Release(true)
+
+	// only the persisted tags are paginated
+	FlushTagsStorage()
+
+	var all hornet.Hashes
+	var cursor hornet.Hash
+	for pages := 0; ; pages++ {
+		if pages > len(txHashes) {
+			t.Fatal("pagination does not terminate")
+		}
+
+		page, nextCursor := GetTagHashesPage(tag, cursor, 2)
+		all = append(all, page...)
+		if nextCursor == nil {
+			break
+		}
+		cursor = nextCursor
+	}
+
+	assert.ElementsMatch(t, txHashes, all)
+
+	page, nextCursor := GetTagHashesPage(otherTag, nil, 2)
+	assert.Equal(t, hornet.Hashes{txHashes[0]}, page)
+	assert.Nil(t, nextCursor)
+}
@@ -1,15 +1,13 @@
 package tangle
 
 import (
-	"errors"
+	"fmt"
 	"os"
-	"path"
-
-	"go.etcd.io/bbolt"
+	"path/filepath"
 
 	"github.com/iotaledger/hive.go/kvstore"
-	"github.com/iotaledger/hive.go/kvstore/bolt"
 
+	"github.com/gohornet/hornet/pkg/database"
 	"github.com/gohornet/hornet/pkg/profile"
 )
 
@@ -20,43 +18,40 @@ const (
 )
 
 var (
-	dbDir      string
-	tangleDb   *bbolt.DB
-	snapshotDb *bbolt.DB
-	spentDb    *bbolt.DB
+	tangleDb   *database.Database
+	snapshotDb *database.Database
+	spentDb    *database.Database
 
-	ErrNothingToCleanUp = errors.New("Nothing to clean up in the databases")
+	ErrNothingToCleanUp = database.ErrNothingToCleanUp
 )
 
-func boltDB(directory string, filename string) *bbolt.DB {
-	opts := &bbolt.Options{
-		NoSync: true,
-	}
-	db, err := bolt.CreateDB(directory, filename, opts)
+func openDatabase(engine database.Engine, directory string, filename string) *database.Database {
+	db, err := database.New(engine, directory, filename)
 	if err != nil {
 		panic(err)
 	}
 	return db
 }
 
-func ConfigureDatabases(directory string) {
-
-	dbDir = directory
-	tangleDb = boltDB(directory, TangleDbFilename)
-
-	tangleStore := bolt.New(tangleDb)
+func ConfigureDatabases(directory string, engine database.Engine) {
 
-	snapshotDb = boltDB(directory, SnapshotDbFilename)
-	snapshotStore := bolt.New(snapshotDb)
+	tangleDb = openDatabase(engine, directory, TangleDbFilename)
+	snapshotDb = openDatabase(engine, directory, SnapshotDbFilename)
+	spentDb = openDatabase(engine, directory, SpentAddressesDbFilename)
 
-	spentDb = boltDB(directory, SpentAddressesDbFilename)
-	spentStore := bolt.New(spentDb)
-
-	ConfigureStorages(tangleStore, snapshotStore, spentStore, profile.LoadProfile().Caches)
+	ConfigureStorages(tangleDb.KVStore(), snapshotDb.KVStore(), spentDb.KVStore(), profile.LoadProfile().Caches)
 }
 
 func ConfigureStorages(tangleStore kvstore.KVStore, snapshotStore kvstore.KVStore, spentStore kvstore.KVStore, caches profile.Caches) {
 
+	cacheOpts = caches
+
+	if storeMetricsEnabled {
+		tangleStore = newMetricsStore(tangleStore, TangleDbFilename)
+		snapshotStore = newMetricsStore(snapshotStore, SnapshotDbFilename)
+		spentStore = newMetricsStore(spentStore, SpentAddressesDbFilename)
+	}
+
 	configureHealthStore(tangleStore)
 	configureTransactionStorage(tangleStore, caches.Transactions)
 	configureBundleTransactionsStorage(tangleStore, caches.BundleTransactions)
@@ -64,9 +59,14 @@ func ConfigureStorages(tangleStore kvstore.KVStore, snapshotStore kvstore.KVStor
 	configureApproversStorage(tangleStore, caches.Approvers)
 	configureTagsStorage(tangleStore, caches.Tags)
 	configureAddressesStorage(tangleStore, caches.Addresses)
+	configureAddressHistoryStorage(tangleStore, caches.Addresses)
 	configureMilestoneStorage(tangleStore, caches.Milestones)
 	configureUnconfirmedTxStorage(tangleStore, caches.UnconfirmedTx)
+	// the transaction types are grouped by milestone like the unconfirmed transactions
+	configureTransactionTypesStorage(tangleStore, caches.UnconfirmedTx)
 	configureLedgerStore(tangleStore)
+	configureEventLogStore(tangleStore)
+	configureRetainedBundlesStore(tangleStore)
 
 	configureSnapshotStore(snapshotStore)
 
@@ -81,7 +81,9 @@ func FlushStorages() {
 	FlushApproversStorage()
 	FlushTagsStorage()
 	FlushAddressStorage()
+	FlushAddressHistoryStorage()
 	FlushUnconfirmedTxsStorage()
+	FlushTransactionTypesStorage()
 	FlushSpentAddressesStorage()
 }
 
@@ -94,7 +96,9 @@ func ShutdownStorages() {
 	ShutdownApproversStorage()
 	ShutdownTagsStorage()
 	ShutdownAddressStorage()
+	ShutdownAddressHistoryStorage()
 	ShutdownUnconfirmedTxsStorage()
+	ShutdownTransactionTypesStorage()
 	ShutdownSpentAddressesStorage()
 }
 
@@ -103,58 +107,152 @@ func LoadInitialValuesFromDatabase() {
 	loadSolidEntryPoints()
 }
 
-func CloseDatabases() error {
-
-	if err := tangleDb.Sync(); err != nil {
-		return err
+// returns the opened databases.
+func databases() []*database.Database {
+	var dbs []*database.Database
+	for _, db := range []*database.Database{tangleDb, snapshotDb, spentDb} {
+		if db != nil {
+			dbs = append(dbs, db)
+		}
 	}
+	return dbs
+}
 
-	if err := tangleDb.Close(); err != nil {
-		return err
-	}
+// FlushDatabases writes all pending changes of the databases to disk.
+func FlushDatabases() error {
 
-	if err := snapshotDb.Sync(); err != nil {
-		return err
+	for _, db := range databases() {
+		if err := db.Flush(); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	if err := snapshotDb.Close(); err != nil {
-		return err
-	}
+func CloseDatabases() error {
 
-	if err := spentDb.Sync(); err != nil {
-		return err
+	for _, db := range databases() {
+		if err := db.Close(); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	if err := spentDb.Close(); err != nil {
-		return err
+// CheckDatabasesHealth checks whether all databases can be read from.
+func CheckDatabasesHealth() error {
+
+	for _, db := range databases() {
+		if err := db.CheckHealth(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// DatabaseSupportsCleanup returns whether the engine of the databases needs to be cleaned up (bolt reuses the freed pages itself).
 func DatabaseSupportsCleanup() bool {
-	// Bolt does not support cleaning up anything
+	for _, db := range databases() {
+		if db.SupportsCleanup() {
+			return true
+		}
+	}
 	return false
 }
 
+// CleanupDatabases frees the space of deleted data in all databases.
+// It returns ErrNothingToCleanUp if there was nothing to free in any database.
 func CleanupDatabases() error {
-	// Bolt does not support cleaning up anything
-	return ErrNothingToCleanUp
+	cleaned := false
+	for _, db := range databases() {
+		if err := db.Cleanup(); err != nil {
+			if err == database.ErrNothingToCleanUp {
+				continue
+			}
+			return fmt.Errorf("cleanup of database %s failed: %w", db.Name(), err)
+		}
+		cleaned = true
+	}
+
+	if !cleaned {
+		return ErrNothingToCleanUp
+	}
+	return nil
 }
 
 // GetDatabaseSizes returns the size of the different databases.
 func GetDatabaseSizes() (tangle int64, snapshot int64, spent int64) {
 
-	if tangleDbFile, err := os.Stat(path.Join(dbDir, TangleDbFilename)); err == nil {
-		tangle = tangleDbFile.Size()
+	if tangleDb != nil {
+		tangle, _ = tangleDb.Size()
 	}
 
-	if snapshotDbFile, err := os.Stat(path.Join(dbDir, SnapshotDbFilename)); err == nil {
-		snapshot = snapshotDbFile.Size()
+	if snapshotDb != nil {
+		snapshot, _ = snapshotDb.Size()
 	}
 
-	if spentDbFile, err := os.Stat(path.Join(dbDir, SpentAddressesDbFilename)); err == nil {
-		spent = spentDbFile.Size()
+	if spentDb != nil {
+		spent, _ = spentDb.Size()
 	}
 
 	return
 }
+
+// GetDatabaseLiveSize returns the size of the live data of all databases, without the space freed by deletions.
+func GetDatabaseLiveSize() int64 {
+	var size int64
+	for _, db := range databases() {
+		liveSize, _ := db.LiveSize()
+		size += liveSize
+	}
+	return size
+}
+
+// BackupDatabases writes a copy of every database to the given directory while the node keeps running.
+// Every database is copied consistently on its own, the copies are not consistent to each other.
+// It returns the paths of the written files.
+func BackupDatabases(directory string) ([]string, error) {
+
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, db := range databases() {
+		path := filepath.Join(directory, db.Name())
+		if err := backupDatabase(db, path); err != nil {
+			return paths, fmt.Errorf("backup of database %s failed: %w", db.Name(), err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// writes the backup to a temporary file first, so that a file at the path is always complete.
+func backupDatabase(db *database.Database, path string) error {
+	pathTmp := path + "_tmp"
+
+	f, err := os.OpenFile(pathTmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.Backup(f); err != nil {
+		f.Close()
+		os.Remove(pathTmp)
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(pathTmp)
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(pathTmp)
+		return err
+	}
+
+	return os.Rename(pathTmp, path)
+}
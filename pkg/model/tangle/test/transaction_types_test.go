@@ -0,0 +1,36 @@
+package test
+
+import (
+	"testing"
+
+	_ "golang.org/x/crypto/blake2b"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/gohornet/hornet/pkg/testsuite/utils"
+)
+
+func TestTransactionTypeOfMilestones(t *testing.T) {
+	te := testsuite.SetupTestEnvironment(t, make(map[string]uint64), 2, false)
+	defer te.CleanupTestEnvironment(true)
+
+	// the transactions of the verified milestone are stored while the previous milestone was the latest one
+	cachedMs := tangle.GetMilestoneOrNil(3) // bundle +1
+	require.NotNil(t, cachedMs)
+	defer cachedMs.Release(true) // bundle -1
+
+	milestoneTxs := tangle.GetTransactionTypeHashes(hornet.TransactionTypeMilestone, 2)
+	require.ElementsMatch(t, cachedMs.GetBundle().GetTxHashes(), milestoneTxs)
+	require.Empty(t, tangle.GetTransactionTypeHashes(hornet.TransactionTypeData, 2))
+
+	// a zero value transaction to the coordinator address is no milestone transaction
+	cooAddress := tangle.GetCoordinatorAddressForMilestoneIndex(3).Trytes()
+	lmi := tangle.GetLatestMilestoneIndex()
+	fakeMs := te.AttachAndStoreBundle(cachedMs.GetBundle().GetTailHash(), cachedMs.GetBundle().GetTailHash(), utils.ZeroValueTxToAddress(t, "FAKEMS", cooAddress))
+
+	require.Equal(t, hornet.Hashes{fakeMs.GetBundle().GetTailHash()}, tangle.GetTransactionTypeHashes(hornet.TransactionTypeData, lmi))
+	require.Empty(t, tangle.GetTransactionTypeHashes(hornet.TransactionTypeMilestone, lmi))
+}
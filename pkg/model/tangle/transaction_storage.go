@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/objectstorage"
 
@@ -12,6 +14,11 @@ import (
 	"github.com/gohornet/hornet/pkg/profile"
 )
 
+const (
+	// the name of the metadata storage in corruption errors.
+	metadataStorageName = "metadata"
+)
+
 var (
 	txStorage       *objectstorage.ObjectStorage
 	metadataStorage *objectstorage.ObjectStorage
@@ -138,19 +145,46 @@ func (c *CachedTransaction) Release(force ...bool) {
 }
 
 func transactionFactory(key []byte) (objectstorage.StorableObject, int, error) {
+	if len(key) < 49 {
+		return nil, 0, errors.Wrapf(ErrInvalidKeyLength, "%d bytes", len(key))
+	}
+
 	tx := hornet.NewTransaction(key[:49])
 	return tx, 49, nil
 }
 
 func metadataFactory(key []byte) (objectstorage.StorableObject, int, error) {
+	if err := validateMetadataKey(key); err != nil {
+		return nil, 0, NewCorruptedEntryError(metadataStorageName, key, err)
+	}
+
 	tx := hornet.NewTransactionMetadata(key[:49])
 	return tx, 49, nil
 }
 
+func validateMetadataKey(key []byte) error {
+	if len(key) != 49 {
+		return errors.Wrapf(ErrInvalidKeyLength, "%d bytes", len(key))
+	}
+	return nil
+}
+
+// validateMetadataEntry checks the stored metadata before it is passed to the factory of the metadata storage.
+func validateMetadataEntry(key []byte, value []byte) error {
+	if err := validateMetadataKey(key); err != nil {
+		return err
+	}
+	return hornet.ValidateTransactionMetadataValue(value)
+}
+
 func GetTransactionStorageSize() int {
 	return txStorage.GetSize()
 }
 
+func GetTransactionMetadataStorageSize() int {
+	return metadataStorage.GetSize()
+}
+
 func configureTransactionStorage(store kvstore.KVStore, opts profile.CacheOpts) {
 
 	txStorage = objectstorage.New(
@@ -167,7 +201,7 @@ func configureTransactionStorage(store kvstore.KVStore, opts profile.CacheOpts)
 	)
 
 	metadataStorage = objectstorage.New(
-		store.WithRealm([]byte{StorePrefixTransactionMetadata}),
+		newQuarantineStore(store.WithRealm([]byte{StorePrefixTransactionMetadata}), metadataStorageName, validateMetadataEntry),
 		metadataFactory,
 		objectstorage.CacheTime(time.Duration(opts.CacheTimeMs)*time.Millisecond),
 		objectstorage.PersistenceEnabled(true),
@@ -216,14 +250,45 @@ func GetCachedTxMetadataOrNil(txHash hornet.Hash) *CachedMetadata {
 	return &CachedMetadata{CachedObject: cachedMeta}
 }
 
+// CachedMetadataMap maps transaction hashes to their cached metadata.
+type CachedMetadataMap map[string]*CachedMetadata
+
+// meta -1
+func (cachedMetas CachedMetadataMap) Release(force ...bool) {
+	for _, cachedMeta := range cachedMetas {
+		cachedMeta.Release(force...)
+	}
+}
+
+// GetCachedTxMetadataBatch returns the cached metadata of all the given transactions, keyed by transaction hash.
+// Unknown transactions are not contained in the result. The caller has to release the result.
+// metadata +1
+func GetCachedTxMetadataBatch(txHashes hornet.Hashes) CachedMetadataMap {
+	cachedMetas := make(CachedMetadataMap, len(txHashes))
+
+	for _, txHash := range txHashes {
+		if _, exists := cachedMetas[string(txHash)]; exists {
+			continue
+		}
+
+		cachedMeta := metadataStorage.Load(txHash) // meta +1
+		if !cachedMeta.Exists() {
+			cachedMeta.Release(true) // metadata -1
+			continue
+		}
+
+		addAdditionalTxInfoToMetadata(cachedMeta.Retain())
+		cachedMetas[string(txHash)] = &CachedMetadata{CachedObject: cachedMeta}
+	}
+
+	return cachedMetas
+}
+
 func addAdditionalTxInfoToMetadata(cachedMetadata objectstorage.CachedObject) {
 	cachedMetadata.Consume(func(metadataObject objectstorage.StorableObject) {
 		metadata := metadataObject.(*hornet.TransactionMetadata)
 
-		trunkHash := metadata.GetTrunkHash()
-		branchHash := metadata.GetTrunkHash()
-
-		if len(trunkHash) == 0 || len(branchHash) == 0 {
+		if len(metadata.GetParents()) == 0 || len(metadata.GetBundleHash()) == 0 {
 			cachedTx := txStorage.Load(metadata.GetTxHash())
 			if !cachedTx.Exists() {
 				panic(fmt.Sprintf("transaction not found for metadata: %v", metadata.GetTxHash().Trytes()))
@@ -231,7 +296,7 @@ func addAdditionalTxInfoToMetadata(cachedMetadata objectstorage.CachedObject) {
 
 			cachedTx.Consume(func(transactionObject objectstorage.StorableObject) {
 				tx := transactionObject.(*hornet.Transaction)
-				metadata.SetAdditionalTxInfo(tx.GetTrunkHash(), tx.GetBranchHash(), tx.GetBundleHash(), tx.IsHead(), tx.IsTail(), tx.IsValue())
+				metadata.SetAdditionalTxInfo(tx.GetParents(), tx.GetBundleHash(), tx.IsHead(), tx.IsTail(), tx.IsValue())
 			}, true)
 		}
 	}, true)
@@ -266,7 +331,8 @@ func StoreTransactionIfAbsent(transaction *hornet.Transaction) (cachedTx *Cached
 		newlyAdded = true
 
 		metadata, _, _ := metadataFactory(transaction.GetTxHash())
-		metadata.(*hornet.TransactionMetadata).SetAdditionalTxInfo(transaction.GetTrunkHash(), transaction.GetBranchHash(), transaction.GetBundleHash(), transaction.IsHead(), transaction.IsTail(), transaction.IsValue())
+		metadata.(*hornet.TransactionMetadata).SetAdditionalTxInfo(transaction.GetParents(), transaction.GetBundleHash(), transaction.IsHead(), transaction.IsTail(), transaction.IsValue())
+		metadata.(*hornet.TransactionMetadata).SetArrivalTimestamp(int32(time.Now().Unix()))
 		cachedMeta = metadataStorage.Store(metadata) // meta +1
 
 		transaction.Persist()
@@ -312,6 +378,94 @@ func DeleteTransactionMetadata(txHash hornet.Hash) {
 	metadataStorage.Delete(txHash)
 }
 
+// MetadataPruningProgressFunc is called after every batch deleted by DeleteTransactions.
+type MetadataPruningProgressFunc func(deletedCount int, totalCount int)
+
+// DeleteTransactions deletes the given transactions (e.g. the cone of a pruned milestone) together with their metadata,
+// approver, bundle, tag and address entries. Transactions which are still part of another bundle instance are kept.
+// The address history entries of referenced transactions are deleted as well if deleteAddressHistory is set.
+// The deletions are done in batches of the given size, the progress func is called after every batch.
+// Returns the amount of deleted transactions.
+func DeleteTransactions(txHashes map[string]struct{}, deleteAddressHistory bool, batchSize int, progress MetadataPruningProgressFunc, abortSignal <-chan struct{}) (int, error) {
+
+	txsToDeleteMap := make(map[string]struct{})
+
+	for txHashToCheck := range txHashes {
+
+		cachedTxMeta := GetCachedTxMetadataOrNil(hornet.Hash(txHashToCheck)) // meta +1
+		if cachedTxMeta == nil {
+			continue
+		}
+
+		for txToRemove := range RemoveTransactionFromBundle(cachedTxMeta.GetMetadata()) {
+			txsToDeleteMap[txToRemove] = struct{}{}
+		}
+		// since it gets loaded below again it doesn't make sense to force release here
+		cachedTxMeta.Release() // meta -1
+	}
+
+	txHashesToDelete := make(hornet.Hashes, 0, len(txsToDeleteMap))
+	for txHashToDelete := range txsToDeleteMap {
+		txHashesToDelete = append(txHashesToDelete, hornet.Hash(txHashToDelete))
+	}
+
+	if batchSize <= 0 {
+		batchSize = len(txHashesToDelete)
+	}
+
+	deleted := 0
+	for start := 0; start < len(txHashesToDelete); start += batchSize {
+		select {
+		case <-abortSignal:
+			return deleted, ErrOperationAborted
+		default:
+		}
+
+		end := start + batchSize
+		if end > len(txHashesToDelete) {
+			end = len(txHashesToDelete)
+		}
+
+		for _, txHash := range txHashesToDelete[start:end] {
+			deleteTransactionEntries(txHash, deleteAddressHistory)
+		}
+		deleted = end
+
+		if progress != nil {
+			progress(deleted, len(txHashesToDelete))
+		}
+	}
+
+	return deleted, nil
+}
+
+// deleteTransactionEntries deletes the transaction, its metadata and all its index entries except the bundle entries.
+func deleteTransactionEntries(txHash hornet.Hash, deleteAddressHistory bool) {
+
+	cachedTx := GetCachedTransactionOrNil(txHash) // tx +1
+	if cachedTx == nil {
+		return
+	}
+
+	if deleteAddressHistory {
+		if referenced, referencedIndex := cachedTx.GetMetadata().GetReferenced(); referenced {
+			DeleteAddressHistoryEntry(cachedTx.GetTransaction().GetAddress(), referencedIndex, cachedTx.GetTransaction().GetTxHash())
+		}
+	}
+
+	cachedTx.ConsumeTransaction(func(tx *hornet.Transaction) { // tx -1
+		// delete the reference in the approvees
+		for _, parent := range tx.GetParents() {
+			DeleteApprover(parent, tx.GetTxHash())
+		}
+
+		DeleteTag(tx.GetTag(), tx.GetTxHash())
+		DeleteAddress(tx.GetAddress(), tx.GetTxHash())
+		DeleteApprovers(tx.GetTxHash())
+		DeleteTransaction(tx.GetTxHash())
+	})
+}
+
 func ShutdownTransactionStorage() {
 	txStorage.Shutdown()
 	metadataStorage.Shutdown()
@@ -0,0 +1,46 @@
+package tangle_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite"
+)
+
+func TestDeleteTransactions(t *testing.T) {
+	te := testsuite.SetupTestEnvironment(t, make(map[string]uint64), 2, false)
+	defer te.CleanupTestEnvironment(true)
+
+	msBundle := te.Milestones[0].GetBundle()
+	tailTxHash := msBundle.GetTailHash()
+
+	cachedTxs := msBundle.GetTransactions() // tx +1
+	txs := make([]*hornet.Transaction, len(cachedTxs))
+	txHashes := make(map[string]struct{})
+	for i, cachedTx := range cachedTxs {
+		txs[i] = cachedTx.GetTransaction()
+		txHashes[string(txs[i].GetTxHash())] = struct{}{}
+	}
+	cachedTxs.Release(true) // tx -1
+
+	var progressCalls int
+	deleted, err := tangle.DeleteTransactions(txHashes, true, 1, func(deletedCount int, totalCount int) {
+		progressCalls++
+		require.Equal(t, len(txs), totalCount)
+		require.Equal(t, progressCalls, deletedCount)
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, len(txs), deleted)
+	require.Equal(t, len(txs), progressCalls)
+
+	require.False(t, tangle.ContainsBundle(tailTxHash))
+	for _, tx := range txs {
+		require.False(t, tangle.ContainsTransaction(tx.GetTxHash()))
+		require.False(t, tangle.ContainsTag(tx.GetTag(), tx.GetTxHash()))
+		require.False(t, tangle.ContainsAddress(tx.GetAddress(), tx.GetTxHash(), false))
+		require.False(t, tangle.ContainsBundleTransaction(tx.GetBundleHash(), tx.GetTxHash(), tx.IsTail()))
+	}
+}
@@ -0,0 +1,156 @@
+package tangle
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/objectstorage"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/profile"
+)
+
+var transactionTypesStorage *objectstorage.ObjectStorage
+
+type CachedTransactionTypeEntry struct {
+	objectstorage.CachedObject
+}
+
+func (c *CachedTransactionTypeEntry) GetTransactionTypeEntry() *hornet.TransactionTypeEntry {
+	return c.Get().(*hornet.TransactionTypeEntry)
+}
+
+func transactionTypeKeyPrefix(txType hornet.TransactionType, msIndex milestone.Index) []byte {
+	key := make([]byte, 5)
+	key[0] = byte(txType)
+	binary.LittleEndian.PutUint32(key[1:5], uint32(msIndex))
+	return key
+}
+
+func transactionTypeFactory(key []byte) (objectstorage.StorableObject, int, error) {
+	entry := hornet.NewTransactionTypeEntry(hornet.TransactionType(key[0]), milestone.Index(binary.LittleEndian.Uint32(key[1:5])), key[5:54])
+	return entry, 54, nil
+}
+
+func GetTransactionTypesStorageSize() int {
+	return transactionTypesStorage.GetSize()
+}
+
+func configureTransactionTypesStorage(store kvstore.KVStore, opts profile.CacheOpts) {
+
+	transactionTypesStorage = objectstorage.New(
+		store.WithRealm([]byte{StorePrefixTransactionTypes}),
+		transactionTypeFactory,
+		objectstorage.CacheTime(time.Duration(opts.CacheTimeMs)*time.Millisecond),
+		objectstorage.PersistenceEnabled(true),
+		objectstorage.PartitionKey(1, 4, 49),
+		objectstorage.KeysOnly(true),
+		objectstorage.StoreOnCreation(true),
+		objectstorage.LeakDetectionEnabled(opts.LeakDetectionOptions.Enabled,
+			objectstorage.LeakDetectionOptions{
+				MaxConsumersPerObject: opts.LeakDetectionOptions.MaxConsumersPerObject,
+				MaxConsumerHoldTime:   time.Duration(opts.LeakDetectionOptions.MaxConsumerHoldTimeSec) * time.Second,
+			}),
+	)
+}
+
+// GetTransactionType returns the type of the given transaction.
+// Only transactions of verified milestone bundles are of type milestone.
+func GetTransactionType(tx *hornet.Transaction, metadata *hornet.TransactionMetadata) hornet.TransactionType {
+	switch {
+	case tx.IsValue():
+		return hornet.TransactionTypeValue
+	case metadata.IsMilestone():
+		return hornet.TransactionTypeMilestone
+	default:
+		return hornet.TransactionTypeData
+	}
+}
+
+// storeBundleTransactionTypes adds the possible milestone transactions of the given checked bundle to the index of their type.
+// possible milestone transactions are not added when they are stored, because they are only of type milestone
+// if the bundle is a verified milestone.
+func storeBundleTransactionTypes(txHashes hornet.Hashes, latestMilestoneIndex milestone.Index) {
+	for _, txHash := range txHashes {
+		cachedTx := GetCachedTransactionOrNil(txHash) // tx +1
+		if cachedTx == nil {
+			continue
+		}
+
+		if IsMaybeMilestoneTx(cachedTx.Retain()) { // tx pass +1
+			StoreTransactionType(GetTransactionType(cachedTx.GetTransaction(), cachedTx.GetMetadata()), latestMilestoneIndex, txHash).Release(true)
+		}
+		cachedTx.Release(true) // tx -1
+	}
+}
+
+// GetTransactionTypeHashes returns the hashes of the transactions of the given type,
+// which were first seen while the given milestone was the latest one.
+func GetTransactionTypeHashes(txType hornet.TransactionType, msIndex milestone.Index, maxFind ...int) hornet.Hashes {
+	var txHashes hornet.Hashes
+
+	i := 0
+	transactionTypesStorage.ForEachKeyOnly(func(key []byte) bool {
+		i++
+		if (len(maxFind) > 0) && (i > maxFind[0]) {
+			return false
+		}
+
+		txHashes = append(txHashes, hornet.Hash(key[5:54]))
+		return true
+	}, false, transactionTypeKeyPrefix(txType, msIndex))
+
+	return txHashes
+}
+
+// TransactionTypeConsumer consumes the given transaction type entry during looping through all entries in the persistence layer.
+type TransactionTypeConsumer func(txType hornet.TransactionType, msIndex milestone.Index, txHash hornet.Hash) bool
+
+// ForEachTransactionType loops over all transaction type entries.
+func ForEachTransactionType(consumer TransactionTypeConsumer, skipCache bool) {
+	transactionTypesStorage.ForEachKeyOnly(func(key []byte) bool {
+		return consumer(hornet.TransactionType(key[0]), milestone.Index(binary.LittleEndian.Uint32(key[1:5])), key[5:54])
+	}, skipCache)
+}
+
+// StoreTransactionType adds the transaction to the index of transactions of its type first seen while the given milestone was the latest one.
+// transactionType +1
+func StoreTransactionType(txType hornet.TransactionType, msIndex milestone.Index, txHash hornet.Hash) *CachedTransactionTypeEntry {
+	entry := hornet.NewTransactionTypeEntry(txType, msIndex, txHash[:49])
+	return &CachedTransactionTypeEntry{CachedObject: transactionTypesStorage.Store(entry)}
+}
+
+// DeleteTransactionType deletes the transaction type entry of the given transaction.
+// transactionType +-0
+func DeleteTransactionType(txType hornet.TransactionType, msIndex milestone.Index, txHash hornet.Hash) {
+	transactionTypesStorage.Delete(append(transactionTypeKeyPrefix(txType, msIndex), txHash[:49]...))
+}
+
+// DeleteTransactionTypes deletes the transaction type entries of all types for the given milestone.
+func DeleteTransactionTypes(msIndex milestone.Index) int {
+
+	var keysToDelete [][]byte
+
+	for _, txType := range hornet.TransactionTypes {
+		transactionTypesStorage.ForEachKeyOnly(func(key []byte) bool {
+			keysToDelete = append(keysToDelete, key)
+			return true
+		}, false, transactionTypeKeyPrefix(txType, msIndex))
+	}
+
+	for _, key := range keysToDelete {
+		transactionTypesStorage.Delete(key)
+	}
+
+	return len(keysToDelete)
+}
+
+func ShutdownTransactionTypesStorage() {
+	transactionTypesStorage.Shutdown()
+}
+
+func FlushTransactionTypesStorage() {
+	transactionTypesStorage.Flush()
+}
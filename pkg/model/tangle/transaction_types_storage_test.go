@@ -0,0 +1,36 @@
+package tangle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/profile"
+)
+
+func TestTransactionTypesStorage(t *testing.T) {
+	configureTransactionTypesStorage(mapdb.NewMapDB(), profile.CacheOpts{})
+	defer ShutdownTransactionTypesStorage()
+
+	valueTx := hornet.HashFromHashTrytes("HZDFGXEBKOBTTEVBAABTDQKXBBAWFXZYBS9WZHHRGUNHBNXBHPCXAEDDWRKUISHKPTYHJQQPGFOXZ9999")
+	dataTx := hornet.HashFromHashTrytes("TMVWMOEXTVQMBEOXIUOKGQSCZRXVSHFMDNTYDCXNFJCYDYWGHXTVGQESGSMRGBMYPDHHFNLUKOGVZ9999")
+	olderDataTx := hornet.HashFromHashTrytes("PWMVVQSDHAZBMXYXVLAHMQZFXOSOTRKRVFANFDKDUTPTKDLXGHFBRWSLBJEZMWTUWTGJUDBVFVZRZ9999")
+
+	StoreTransactionType(hornet.TransactionTypeValue, 10, valueTx).Release(true)
+	StoreTransactionType(hornet.TransactionTypeData, 10, dataTx).Release(true)
+	StoreTransactionType(hornet.TransactionTypeData, 9, olderDataTx).Release(true)
+
+	assert.Equal(t, hornet.Hashes{valueTx}, GetTransactionTypeHashes(hornet.TransactionTypeValue, 10))
+	assert.Equal(t, hornet.Hashes{dataTx}, GetTransactionTypeHashes(hornet.TransactionTypeData, 10))
+	assert.Equal(t, hornet.Hashes{olderDataTx}, GetTransactionTypeHashes(hornet.TransactionTypeData, 9))
+	assert.Empty(t, GetTransactionTypeHashes(hornet.TransactionTypeMilestone, 10))
+
+	// all types of the milestone are deleted, older milestones are kept
+	assert.Equal(t, 2, DeleteTransactionTypes(10))
+	assert.Empty(t, GetTransactionTypeHashes(hornet.TransactionTypeValue, 10))
+	assert.Empty(t, GetTransactionTypeHashes(hornet.TransactionTypeData, 10))
+	assert.Equal(t, hornet.Hashes{olderDataTx}, GetTransactionTypeHashes(hornet.TransactionTypeData, 9))
+}
@@ -84,6 +84,7 @@ func ForEachUnconfirmedTx(consumer UnconfirmedTxConsumer, skipCache bool) {
 	}, skipCache)
 }
 
+// StoreUnconfirmedTx adds the transaction to the index of transactions first seen while the given milestone was the latest one.
 // unconfirmedTx +1
 func StoreUnconfirmedTx(msIndex milestone.Index, txHash hornet.Hash) *CachedUnconfirmedTx {
 	unconfirmedTx := hornet.NewUnconfirmedTx(msIndex, txHash)
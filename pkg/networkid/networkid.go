@@ -0,0 +1,45 @@
+package networkid
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/gohornet/hornet/pkg/config"
+)
+
+const (
+	// Unknown is the network ID of data which doesn't carry a network ID, e.g. handshakes of older nodes.
+	// Such data is only guarded by the coordinator address and the MWM.
+	Unknown ID = 0
+)
+
+// ID identifies a network, so that a node refuses peers and snapshot files of other networks.
+type ID uint64
+
+// FromName derives the network ID from the name of a network, e.g. "mainnet".
+func FromName(name string) ID {
+	hash := blake2b.Sum256([]byte(name))
+	id := ID(binary.LittleEndian.Uint64(hash[:8]))
+	if id == Unknown {
+		// the name must not be mistaken for data without a network ID
+		id++
+	}
+	return id
+}
+
+// FromConfig returns the network ID of the network the node is configured for.
+func FromConfig() ID {
+	return FromName(config.NodeConfig.GetString(config.CfgNetNetworkID))
+}
+
+// Matches returns whether data with the given network ID may be used in the network with the own ID.
+// Data without a network ID matches every network.
+func (id ID) Matches(other ID) bool {
+	return other == Unknown || other == id
+}
+
+func (id ID) String() string {
+	return fmt.Sprintf("%d", uint64(id))
+}
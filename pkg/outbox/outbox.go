@@ -0,0 +1,309 @@
+package outbox
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+var (
+	// ErrOutboxFull is returned if a bundle can't be added because the outbox already tracks the maximum amount of bundles.
+	ErrOutboxFull = errors.New("outbox is full")
+	// ErrItemNotFound is returned if the outbox doesn't track a bundle with the given tail.
+	ErrItemNotFound = errors.New("outbox item not found")
+)
+
+// TailState is the state of the tail of a tracked bundle.
+type TailState int
+
+const (
+	// TailStatePending is the state of a tail which wasn't referenced by a milestone yet, but is still selectable by the tip selection.
+	TailStatePending TailState = iota
+	// TailStateLazy is the state of a tail which will not be referenced anymore, so the bundle has to be attached again.
+	TailStateLazy
+	// TailStateConfirmed is the state of a tail which was referenced by a milestone and included in the ledger.
+	TailStateConfirmed
+	// TailStateConflicting is the state of a tail which was referenced by a milestone, but conflicts with the ledger.
+	TailStateConflicting
+)
+
+// Result is the reason why a bundle was removed from the outbox.
+type Result string
+
+const (
+	// ResultConfirmed is the result of a bundle of which one attachment was confirmed.
+	ResultConfirmed Result = "confirmed"
+	// ResultConflicting is the result of a bundle which was referenced by a milestone, but conflicts with the ledger.
+	ResultConflicting Result = "conflicting"
+	// ResultExpired is the result of a bundle which wasn't confirmed within the maximum age.
+	ResultExpired Result = "expired"
+	// ResultCanceled is the result of a bundle which was removed from the outbox by the user.
+	ResultCanceled Result = "canceled"
+)
+
+// TailStateFunc returns the state of the given tail.
+type TailStateFunc func(tailHash hornet.Hash) (TailState, error)
+
+// RebroadcastFunc sends the bundle of the given tail to the neighbors again.
+type RebroadcastFunc func(tailHash hornet.Hash) error
+
+// ReattachFunc attaches the bundle of the given tail to new tips and returns the tail of the new attachment.
+type ReattachFunc func(tailHash hornet.Hash) (hornet.Hash, error)
+
+// RemovedFunc is called for every bundle which was removed from the outbox.
+type RemovedFunc func(item *Item, result Result)
+
+// Opts defines the thresholds of the outbox.
+type Opts struct {
+	// MaxItems is the maximum amount of tracked bundles (0 = unlimited).
+	MaxItems int
+	// RebroadcastInterval is the interval in which unconfirmed bundles are sent to the neighbors again.
+	RebroadcastInterval time.Duration
+	// ReattachAfter is the duration after which a bundle which is still unconfirmed is attached again, even if it is not lazy.
+	ReattachAfter time.Duration
+	// MaxReattachments is the maximum amount of reattachments of a bundle.
+	MaxReattachments int
+	// MaxAge is the duration after which unconfirmed bundles are removed from the outbox.
+	MaxAge time.Duration
+}
+
+// Item is a bundle tracked by the outbox.
+type Item struct {
+	// The tail of the bundle as it was submitted.
+	TailHash hornet.Hash
+	// The tails of the reattachments of the bundle.
+	Reattachments hornet.Hashes
+	// The time the bundle was submitted.
+	SubmittedAt time.Time
+	// The time the latest attachment of the bundle was created.
+	AttachedAt time.Time
+	// The time the bundle was sent to the neighbors the last time.
+	BroadcastAt time.Time
+	// The amount of times the bundle was sent to the neighbors again.
+	Rebroadcasts int
+	// The error of the latest check of the bundle, if any.
+	LastError error
+}
+
+// LatestTailHash returns the tail of the latest attachment of the bundle.
+func (i *Item) LatestTailHash() hornet.Hash {
+	if len(i.Reattachments) > 0 {
+		return i.Reattachments[len(i.Reattachments)-1]
+	}
+	return i.TailHash
+}
+
+// tailHashes returns the tails of all attachments of the bundle.
+func (i *Item) tailHashes() hornet.Hashes {
+	return append(hornet.Hashes{i.TailHash}, i.Reattachments...)
+}
+
+func (i *Item) clone() *Item {
+	c := *i
+	c.Reattachments = append(hornet.Hashes{}, i.Reattachments...)
+	return &c
+}
+
+// Outbox tracks bundles which were submitted through the node until one of their attachments is confirmed.
+// Unconfirmed bundles are sent to the neighbors again and reattached if they become lazy or remain unconfirmed for too long.
+type Outbox struct {
+	opts Opts
+
+	tailStateFunc   TailStateFunc
+	rebroadcastFunc RebroadcastFunc
+	reattachFunc    ReattachFunc
+	removedFunc     RemovedFunc
+
+	lock sync.RWMutex
+	// the tracked bundles, keyed by the tail they were submitted with
+	items map[string]*Item
+	// serializes the checks of the bundles
+	checkLock sync.Mutex
+}
+
+// New creates a new outbox. reattachFunc and removedFunc may be nil, in which case bundles are never reattached,
+// respectively the removals are not reported.
+func New(opts Opts, tailStateFunc TailStateFunc, rebroadcastFunc RebroadcastFunc, reattachFunc ReattachFunc, removedFunc RemovedFunc) *Outbox {
+	return &Outbox{
+		opts:            opts,
+		tailStateFunc:   tailStateFunc,
+		rebroadcastFunc: rebroadcastFunc,
+		reattachFunc:    reattachFunc,
+		removedFunc:     removedFunc,
+		items:           make(map[string]*Item),
+	}
+}
+
+// Add starts tracking the bundle with the given tail, which was submitted at the given time.
+// Bundles which are already tracked are ignored.
+func (o *Outbox) Add(tailHash hornet.Hash, submittedAt time.Time) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if _, exists := o.items[string(tailHash)]; exists {
+		return nil
+	}
+
+	if o.opts.MaxItems > 0 && len(o.items) >= o.opts.MaxItems {
+		return ErrOutboxFull
+	}
+
+	o.items[string(tailHash)] = &Item{
+		TailHash:    tailHash,
+		SubmittedAt: submittedAt,
+		AttachedAt:  submittedAt,
+		BroadcastAt: submittedAt,
+	}
+	return nil
+}
+
+// Cancel stops tracking the bundle with the given tail.
+func (o *Outbox) Cancel(tailHash hornet.Hash) error {
+	o.lock.Lock()
+	item, exists := o.items[string(tailHash)]
+	if !exists {
+		o.lock.Unlock()
+		return ErrItemNotFound
+	}
+	delete(o.items, string(tailHash))
+	o.lock.Unlock()
+
+	o.removed(item, ResultCanceled)
+	return nil
+}
+
+// Item returns a copy of the tracked bundle with the given tail.
+func (o *Outbox) Item(tailHash hornet.Hash) (*Item, error) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+
+	item, exists := o.items[string(tailHash)]
+	if !exists {
+		return nil, ErrItemNotFound
+	}
+	return item.clone(), nil
+}
+
+// Items returns copies of all tracked bundles.
+func (o *Outbox) Items() []*Item {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+
+	items := make([]*Item, 0, len(o.items))
+	for _, item := range o.items {
+		items = append(items, item.clone())
+	}
+	return items
+}
+
+// Size returns the amount of tracked bundles.
+func (o *Outbox) Size() int {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+
+	return len(o.items)
+}
+
+// Check checks the state of all tracked bundles at the given time.
+// Confirmed, conflicting and expired bundles are removed, the others are sent again or reattached if needed.
+func (o *Outbox) Check(now time.Time) {
+	o.checkLock.Lock()
+	defer o.checkLock.Unlock()
+
+	// the bundles are checked on copies without holding the lock,
+	// since the callbacks may take a while (e.g. the PoW of a reattachment).
+	for _, item := range o.Items() {
+		result, removed := o.checkItem(item, now)
+
+		o.lock.Lock()
+		if _, exists := o.items[string(item.TailHash)]; !exists {
+			// the bundle was canceled in the meantime
+			o.lock.Unlock()
+			continue
+		}
+		if removed {
+			delete(o.items, string(item.TailHash))
+		} else {
+			o.items[string(item.TailHash)] = item
+		}
+		o.lock.Unlock()
+
+		if removed {
+			o.removed(item, result)
+		}
+	}
+}
+
+// checkItem checks the given bundle and modifies it according to the taken actions.
+// Returns whether the bundle should be removed from the outbox and why.
+func (o *Outbox) checkItem(item *Item, now time.Time) (Result, bool) {
+	item.LastError = nil
+
+	latestTailHash := item.LatestTailHash()
+	latestTailState := TailStatePending
+	for _, tailHash := range item.tailHashes() {
+		state, err := o.tailStateFunc(tailHash)
+		if err != nil {
+			item.LastError = err
+			continue
+		}
+
+		switch state {
+		case TailStateConfirmed:
+			return ResultConfirmed, true
+		case TailStateConflicting:
+			if bytes.Equal(tailHash, latestTailHash) {
+				// older attachments may still get confirmed if the latest one conflicts
+				return ResultConflicting, true
+			}
+		}
+
+		if bytes.Equal(tailHash, latestTailHash) {
+			latestTailState = state
+		}
+	}
+
+	if o.opts.MaxAge > 0 && now.Sub(item.SubmittedAt) >= o.opts.MaxAge {
+		return ResultExpired, true
+	}
+
+	reattachDue := latestTailState == TailStateLazy || (o.opts.ReattachAfter > 0 && now.Sub(item.AttachedAt) >= o.opts.ReattachAfter)
+	if reattachDue && o.reattachFunc != nil && len(item.Reattachments) < o.opts.MaxReattachments {
+		tailHash, err := o.reattachFunc(item.LatestTailHash())
+		if err != nil {
+			item.LastError = err
+			return "", false
+		}
+
+		item.Reattachments = append(item.Reattachments, tailHash)
+		item.AttachedAt = now
+		item.BroadcastAt = now
+		return "", false
+	}
+
+	if latestTailState == TailStateLazy {
+		// sending a lazy attachment again doesn't help
+		return "", false
+	}
+
+	if o.opts.RebroadcastInterval > 0 && now.Sub(item.BroadcastAt) >= o.opts.RebroadcastInterval {
+		if err := o.rebroadcastFunc(item.LatestTailHash()); err != nil {
+			item.LastError = err
+			return "", false
+		}
+
+		item.Rebroadcasts++
+		item.BroadcastAt = now
+	}
+
+	return "", false
+}
+
+func (o *Outbox) removed(item *Item, result Result) {
+	if o.removedFunc != nil {
+		o.removedFunc(item, result)
+	}
+}
@@ -0,0 +1,144 @@
+package outbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+type testTangle struct {
+	states       map[string]TailState
+	rebroadcasts []hornet.Hash
+	reattached   []hornet.Hash
+	removed      map[string]Result
+}
+
+func newTestOutbox(opts Opts, reattach bool) (*Outbox, *testTangle) {
+	tangle := &testTangle{
+		states:  make(map[string]TailState),
+		removed: make(map[string]Result),
+	}
+
+	var reattachFunc ReattachFunc
+	if reattach {
+		reattachFunc = func(tailHash hornet.Hash) (hornet.Hash, error) {
+			tangle.reattached = append(tangle.reattached, tailHash)
+			return hornet.Hash(string(tailHash) + "'"), nil
+		}
+	}
+
+	o := New(opts,
+		func(tailHash hornet.Hash) (TailState, error) {
+			state, exists := tangle.states[string(tailHash)]
+			if !exists {
+				return 0, errors.New("not found")
+			}
+			return state, nil
+		},
+		func(tailHash hornet.Hash) error {
+			tangle.rebroadcasts = append(tangle.rebroadcasts, tailHash)
+			return nil
+		},
+		reattachFunc,
+		func(item *Item, result Result) {
+			tangle.removed[string(item.TailHash)] = result
+		},
+	)
+	return o, tangle
+}
+
+func TestOutboxRebroadcast(t *testing.T) {
+	o, tangle := newTestOutbox(Opts{RebroadcastInterval: time.Minute}, false)
+
+	start := time.Now()
+	assert.NoError(t, o.Add(hornet.Hash("A"), start))
+	tangle.states["A"] = TailStatePending
+
+	o.Check(start.Add(30 * time.Second))
+	assert.Empty(t, tangle.rebroadcasts)
+
+	o.Check(start.Add(time.Minute))
+	assert.Equal(t, []hornet.Hash{hornet.Hash("A")}, tangle.rebroadcasts)
+
+	item, err := o.Item(hornet.Hash("A"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, item.Rebroadcasts)
+
+	tangle.states["A"] = TailStateConfirmed
+	o.Check(start.Add(2 * time.Minute))
+	assert.Equal(t, 0, o.Size())
+	assert.Equal(t, ResultConfirmed, tangle.removed["A"])
+}
+
+func TestOutboxReattach(t *testing.T) {
+	o, tangle := newTestOutbox(Opts{RebroadcastInterval: time.Minute, ReattachAfter: 10 * time.Minute, MaxReattachments: 1}, true)
+
+	start := time.Now()
+	assert.NoError(t, o.Add(hornet.Hash("A"), start))
+
+	// lazy tails are reattached immediately
+	tangle.states["A"] = TailStateLazy
+	o.Check(start.Add(time.Second))
+	assert.Equal(t, []hornet.Hash{hornet.Hash("A")}, tangle.reattached)
+
+	item, err := o.Item(hornet.Hash("A"))
+	assert.NoError(t, err)
+	assert.Equal(t, hornet.Hash("A'"), item.LatestTailHash())
+
+	// the latest attachment is sent again, the maximum amount of reattachments is reached
+	tangle.states["A'"] = TailStatePending
+	o.Check(start.Add(10 * time.Minute))
+	assert.Len(t, tangle.reattached, 1)
+	assert.Equal(t, []hornet.Hash{hornet.Hash("A'")}, tangle.rebroadcasts)
+
+	// the confirmation of an older attachment confirms the bundle
+	tangle.states["A"] = TailStateConfirmed
+	o.Check(start.Add(11 * time.Minute))
+	assert.Equal(t, ResultConfirmed, tangle.removed["A"])
+}
+
+func TestOutboxReattachAfter(t *testing.T) {
+	o, tangle := newTestOutbox(Opts{ReattachAfter: 10 * time.Minute, MaxReattachments: 3}, true)
+
+	start := time.Now()
+	assert.NoError(t, o.Add(hornet.Hash("A"), start))
+	tangle.states["A"] = TailStatePending
+
+	o.Check(start.Add(5 * time.Minute))
+	assert.Empty(t, tangle.reattached)
+
+	o.Check(start.Add(10 * time.Minute))
+	assert.Equal(t, []hornet.Hash{hornet.Hash("A")}, tangle.reattached)
+}
+
+func TestOutboxRemove(t *testing.T) {
+	o, tangle := newTestOutbox(Opts{MaxItems: 2, MaxAge: time.Hour}, false)
+
+	start := time.Now()
+	assert.NoError(t, o.Add(hornet.Hash("A"), start))
+	assert.NoError(t, o.Add(hornet.Hash("B"), start))
+	assert.NoError(t, o.Add(hornet.Hash("A"), start))
+	assert.Equal(t, ErrOutboxFull, o.Add(hornet.Hash("C"), start))
+
+	assert.NoError(t, o.Cancel(hornet.Hash("B")))
+	assert.Equal(t, ErrItemNotFound, o.Cancel(hornet.Hash("B")))
+	assert.Equal(t, ResultCanceled, tangle.removed["B"])
+
+	// unknown tails are kept until they expire
+	o.Check(start.Add(time.Minute))
+	item, err := o.Item(hornet.Hash("A"))
+	assert.NoError(t, err)
+	assert.Error(t, item.LastError)
+
+	o.Check(start.Add(time.Hour))
+	assert.Equal(t, ResultExpired, tangle.removed["A"])
+
+	assert.NoError(t, o.Add(hornet.Hash("C"), start))
+	tangle.states["C"] = TailStateConflicting
+	o.Check(start.Add(time.Minute))
+	assert.Equal(t, ResultConflicting, tangle.removed["C"])
+}
@@ -0,0 +1,225 @@
+package peering
+
+import (
+	stded25519 "crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+)
+
+const (
+	// the first byte of every TLS handshake record.
+	// plaintext connections start with a gossip handshake message instead,
+	// therefore encrypted and plaintext inbound connections can be told apart by their first byte.
+	tlsRecordTypeHandshake = 0x16
+	// the time the remote has to complete the TLS handshake.
+	encryptionHandshakeTimeout = 5 * time.Second
+	// the validity of the self-signed certificate. peers are authenticated by their public key only.
+	certificateValidity = 10 * 365 * 24 * time.Hour
+)
+
+var (
+	// ErrPeerNotAuthenticated is returned when a peer did not authenticate itself with the expected public key.
+	ErrPeerNotAuthenticated = errors.New("peer is not authenticated")
+	// ErrEncryptionUnavailable is returned when a connection should be encrypted but the node has no identity.
+	ErrEncryptionUnavailable = errors.New("encryption is not available without an identity")
+	// ErrInvalidPublicKey is returned when a public key can't be parsed.
+	ErrInvalidPublicKey = errors.New("invalid public key")
+)
+
+// ParsePublicKey parses the given base58 encoded ed25519 public key.
+// An empty string results in a nil public key, which means that the connection is not encrypted.
+func ParsePublicKey(str string) (*ed25519.PublicKey, error) {
+	if str == "" {
+		return nil, nil
+	}
+
+	bytes, err := base58.Decode(str)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidPublicKey, "%s: %v", str, err)
+	}
+
+	if len(bytes) != ed25519.PublicKeySize {
+		return nil, errors.Wrapf(ErrInvalidPublicKey, "%s: length %d, need %d", str, len(bytes), ed25519.PublicKeySize)
+	}
+
+	publicKey, _, err := ed25519.PublicKeyFromBytes(bytes)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidPublicKey, "%s: %v", str, err)
+	}
+	return &publicKey, nil
+}
+
+// creates the TLS configurations of the manager out of the identity given in the options.
+func (m *Manager) configureEncryption() error {
+	if m.Opts.Identity == nil {
+		return nil
+	}
+
+	privateKey := stded25519.PrivateKey(m.Opts.Identity.Bytes())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(certificateValidity),
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		return errors.Wrap(err, "unable to create the TLS certificate")
+	}
+
+	m.certificate = &tls.Certificate{
+		Certificate: [][]byte{certDER},
+		PrivateKey:  privateKey,
+	}
+	return nil
+}
+
+// returns the TLS configuration for connections initiated by the node.
+// the remote has to authenticate itself with the given public key.
+func (m *Manager) clientTLSConfig(expectedPublicKey ed25519.PublicKey) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{*m.certificate},
+		MinVersion:   tls.VersionTLS13,
+		// there is no certificate authority, the remote is authenticated by its public key instead.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			publicKey, err := certificatePublicKey(rawCerts)
+			if err != nil {
+				return err
+			}
+			if *publicKey != expectedPublicKey {
+				return errors.Wrapf(ErrPeerNotAuthenticated, "expected public key %s, got %s", expectedPublicKey, publicKey)
+			}
+			return nil
+		},
+	}
+}
+
+// returns the TLS configuration for connections accepted by the node.
+// the public key of the remote is checked against the configured one after the gossip handshake,
+// since the peer's ID is not known before.
+func (m *Manager) serverTLSConfig() *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{*m.certificate},
+		MinVersion:   tls.VersionTLS13,
+		ClientAuth:   tls.RequireAnyClientCert,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			_, err := certificatePublicKey(rawCerts)
+			return err
+		},
+	}
+}
+
+// encrypts the given outbound connection and authenticates the remote with the given public key.
+func (m *Manager) encryptOutbound(conn net.Conn, publicKey ed25519.PublicKey) (net.Conn, error) {
+	if m.certificate == nil {
+		return nil, ErrEncryptionUnavailable
+	}
+
+	tlsConn := tls.Client(conn, m.clientTLSConfig(publicKey))
+	if err := handshakeTLS(tlsConn); err != nil {
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// checks whether the given inbound connection is encrypted and if so, completes the TLS handshake.
+// returns the connection to use for the gossip protocol and the public key the remote authenticated itself with.
+func (m *Manager) acceptInbound(conn net.Conn) (net.Conn, *ed25519.PublicKey, error) {
+	firstByte := make([]byte, 1)
+
+	if err := conn.SetReadDeadline(time.Now().Add(encryptionHandshakeTimeout)); err != nil {
+		return nil, nil, err
+	}
+	if _, err := conn.Read(firstByte); err != nil {
+		return nil, nil, err
+	}
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, nil, err
+	}
+
+	conn = &peekedConn{Conn: conn, peeked: firstByte}
+	if firstByte[0] != tlsRecordTypeHandshake {
+		// plaintext connection
+		return conn, nil, nil
+	}
+
+	if m.certificate == nil {
+		return nil, nil, ErrEncryptionUnavailable
+	}
+
+	tlsConn := tls.Server(conn, m.serverTLSConfig())
+	if err := handshakeTLS(tlsConn); err != nil {
+		return nil, nil, err
+	}
+
+	var rawCerts [][]byte
+	for _, cert := range tlsConn.ConnectionState().PeerCertificates {
+		rawCerts = append(rawCerts, cert.Raw)
+	}
+
+	publicKey, err := certificatePublicKey(rawCerts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tlsConn, publicKey, nil
+}
+
+// executes the TLS handshake on the given connection within the encryption handshake timeout.
+func handshakeTLS(tlsConn *tls.Conn) error {
+	if err := tlsConn.SetDeadline(time.Now().Add(encryptionHandshakeTimeout)); err != nil {
+		return err
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		return errors.Wrap(err, "TLS handshake failed")
+	}
+	return tlsConn.SetDeadline(time.Time{})
+}
+
+// returns the ed25519 public key of the first of the given certificates.
+func certificatePublicKey(rawCerts [][]byte) (*ed25519.PublicKey, error) {
+	if len(rawCerts) == 0 {
+		return nil, errors.Wrap(ErrPeerNotAuthenticated, "no certificate presented")
+	}
+
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return nil, errors.Wrapf(ErrPeerNotAuthenticated, "invalid certificate: %v", err)
+	}
+
+	certPublicKey, ok := cert.PublicKey.(stded25519.PublicKey)
+	if !ok {
+		return nil, errors.Wrap(ErrPeerNotAuthenticated, "certificate does not contain an ed25519 public key")
+	}
+
+	publicKey, _, err := ed25519.PublicKeyFromBytes(certPublicKey)
+	if err != nil {
+		return nil, errors.Wrapf(ErrPeerNotAuthenticated, "invalid public key: %v", err)
+	}
+	return &publicKey, nil
+}
+
+// peekedConn is a net.Conn of which the first bytes were already read.
+type peekedConn struct {
+	net.Conn
+	peeked []byte
+}
+
+func (c *peekedConn) Read(b []byte) (int, error) {
+	if len(c.peeked) > 0 {
+		n := copy(b, c.peeked)
+		c.peeked = c.peeked[n:]
+		return n, nil
+	}
+	return c.Conn.Read(b)
+}
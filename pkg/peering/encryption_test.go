@@ -0,0 +1,129 @@
+package peering
+
+import (
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+)
+
+func newTestManager(t *testing.T) (*Manager, ed25519.PublicKey) {
+	privateKey, err := ed25519.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	m := &Manager{Opts: Options{Identity: &privateKey}}
+	require.NoError(t, m.configureEncryption())
+	return m, privateKey.Public()
+}
+
+// returns both ends of a loopback TCP connection.
+func newTestConnPair(t *testing.T) (net.Conn, net.Conn) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	serverConn, err := listener.Accept()
+	require.NoError(t, err)
+	return clientConn, serverConn
+}
+
+func TestEncryptedConnection(t *testing.T) {
+	client, clientPublicKey := newTestManager(t)
+	server, serverPublicKey := newTestManager(t)
+
+	clientConn, serverConn := newTestConnPair(t)
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	type acceptResult struct {
+		conn      net.Conn
+		publicKey *ed25519.PublicKey
+		err       error
+	}
+	accepted := make(chan acceptResult, 1)
+	go func() {
+		conn, publicKey, err := server.acceptInbound(serverConn)
+		accepted <- acceptResult{conn, publicKey, err}
+	}()
+
+	encryptedClientConn, err := client.encryptOutbound(clientConn, serverPublicKey)
+	require.NoError(t, err)
+
+	result := <-accepted
+	require.NoError(t, result.err)
+	require.NotNil(t, result.publicKey)
+	assert.Equal(t, clientPublicKey, *result.publicKey)
+
+	go func() {
+		_, _ = encryptedClientConn.Write([]byte("gossip"))
+	}()
+	buf := make([]byte, 6)
+	_, err = result.conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "gossip", string(buf))
+}
+
+func TestEncryptedConnectionWrongPublicKey(t *testing.T) {
+	client, _ := newTestManager(t)
+	server, _ := newTestManager(t)
+	_, otherPublicKey := newTestManager(t)
+
+	clientConn, serverConn := newTestConnPair(t)
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	go func() {
+		_, _, _ = server.acceptInbound(serverConn)
+		serverConn.Close()
+	}()
+
+	_, err := client.encryptOutbound(clientConn, otherPublicKey)
+	assert.True(t, errors.Is(err, ErrPeerNotAuthenticated), "unexpected error: %v", err)
+}
+
+func TestPlaintextInboundConnection(t *testing.T) {
+	server, _ := newTestManager(t)
+
+	clientConn, serverConn := newTestConnPair(t)
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	go func() {
+		_, _ = clientConn.Write([]byte{1, 2, 3})
+	}()
+
+	conn, publicKey, err := server.acceptInbound(serverConn)
+	require.NoError(t, err)
+	assert.Nil(t, publicKey)
+
+	// the peeked byte must not get lost
+	buf := make([]byte, 3)
+	n := 0
+	for n < len(buf) {
+		read, err := conn.Read(buf[n:])
+		require.NoError(t, err)
+		n += read
+	}
+	assert.Equal(t, []byte{1, 2, 3}, buf)
+}
+
+func TestParsePublicKey(t *testing.T) {
+	publicKey, err := ParsePublicKey("")
+	assert.NoError(t, err)
+	assert.Nil(t, publicKey)
+
+	_, expected := newTestManager(t)
+	publicKey, err = ParsePublicKey(expected.String())
+	require.NoError(t, err)
+	assert.Equal(t, expected, *publicKey)
+
+	_, err = ParsePublicKey("invalid")
+	assert.True(t, errors.Is(err, ErrInvalidPublicKey))
+}
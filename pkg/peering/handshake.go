@@ -65,6 +65,11 @@ func (m *Manager) verifyHandshake(p *peer.Peer, handshakeMsg *handshake.Handshak
 		return ErrNonMatchingCooAddr
 	}
 
+	// check whether the peer participates in the same network, older nodes don't send a network ID
+	if !m.Opts.ValidHandshake.NetworkID.Matches(handshakeMsg.NetworkID) {
+		return errors.Wrapf(ErrNonMatchingNetworkID, "(%s instead of %s)", handshakeMsg.NetworkID, m.Opts.ValidHandshake.NetworkID)
+	}
+
 	// check feature set compatibility
 	version, err := handshakeMsg.SupportedVersion(protocol.SupportedFeatureSets)
 	if err != nil {
@@ -92,6 +97,16 @@ func (m *Manager) verifyHandshake(p *peer.Peer, handshakeMsg *handshake.Handshak
 		}
 	}
 
+	// outbound peers were already authenticated while encrypting the connection
+	if expectedPublicKey := m.expectedPublicKey(p.ID); p.IsInbound() && expectedPublicKey != nil {
+		if p.PublicKey == nil {
+			return errors.Wrapf(ErrPeerNotAuthenticated, "%s must use an encrypted connection", p.ID)
+		}
+		if *p.PublicKey != *expectedPublicKey {
+			return errors.Wrapf(ErrPeerNotAuthenticated, "%s authenticated with public key %s instead of %s", p.ID, p.PublicKey, expectedPublicKey)
+		}
+	}
+
 	// drop the connection if it's not an autopeer and in the meantime
 	// the available peering slots were filled
 	if p.Autopeering == nil && m.SlotsFilled() {
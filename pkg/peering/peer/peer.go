@@ -1,6 +1,7 @@
 package peer
 
 import (
+	"crypto/tls"
 	"net"
 	"strconv"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"go.uber.org/atomic"
 
 	"github.com/iotaledger/hive.go/autopeering/peer"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/identity"
 	"github.com/iotaledger/hive.go/iputils"
@@ -34,11 +36,24 @@ const (
 const (
 	// SendQueueSize defines the size of the send queue of every created peer.
 	SendQueueSize = 1500
+	// RequestSendQueueSize defines the size of the request send queue of every created peer.
+	RequestSendQueueSize = 500
+	// RequestSendQueueSaturation defines the fill level in percent at which the request send queue is considered saturated.
+	RequestSendQueueSaturation = 90
 	// CheckStaledAutopeerInterval is the interval autopeered neighbors
 	// are checked whether they are staled.
 	CheckStaledAutopeerInterval = 60 * time.Second
 )
 
+const (
+	// RelationStatic is a peer which was added manually and is reconnected to if the connection drops.
+	RelationStatic = "static"
+	// RelationAutopeered is a peer which was chosen by the autopeering.
+	RelationAutopeered = "autopeered"
+	// RelationUnknown is an inbound peer which is only connected because any connection is accepted.
+	RelationUnknown = "unknown"
+)
+
 func Caller(handler interface{}, params ...interface{}) {
 	handler.(func(*Peer))(params[0].(*Peer))
 }
@@ -63,6 +78,8 @@ func NewInboundPeer(remoteAddr net.Addr) *Peer {
 		Addresses:        addresses,
 		ConnectionOrigin: Inbound,
 		SendQueue:        make(chan []byte, SendQueueSize),
+		RequestSendQueue: make(chan []byte, RequestSendQueueSize),
+		RequestLatency:   NewRequestLatency(),
 		Events: Events{
 			HeartbeatUpdated: events.NewEvent(sting.HeartbeatCaller),
 		},
@@ -79,6 +96,8 @@ func NewOutboundPeer(originAddr *iputils.OriginAddress, primaryAddr net.IP, port
 		MoveBackToReconnectPool: true,
 		ConnectionOrigin:        Outbound,
 		SendQueue:               make(chan []byte, SendQueueSize),
+		RequestSendQueue:        make(chan []byte, RequestSendQueueSize),
+		RequestLatency:          NewRequestLatency(),
 		Events: Events{
 			HeartbeatUpdated: events.NewEvent(sting.HeartbeatCaller),
 		},
@@ -106,10 +125,14 @@ type Peer struct {
 	Protocol *protocol.Protocol
 	// Metrics about the peer.
 	Metrics Metrics
+	// The latency with which the peer answers transaction requests.
+	RequestLatency *RequestLatency
 	// Whether the connection for this peer was handled inbound or was created outbound.
 	ConnectionOrigin ConnectionOrigin
 	// Whether to place this peer back into the reconnect pool when the connection is closed.
 	MoveBackToReconnectPool bool
+	// The amount of failed connection attempts before the current one.
+	ReconnectAttempts int
 	// Whether the peer is a duplicate, as it is already connected.
 	Duplicate bool
 	// The peer's latest heartbeat message.
@@ -120,8 +143,13 @@ type Peer struct {
 	HeartbeatSentTime time.Time
 	// Holds the autopeering info if this peer was added via autopeering.
 	Autopeering *peer.Peer
+	// The public key the peer authenticates itself with, nil if the connection is not encrypted.
+	PublicKey *ed25519.PublicKey
 	// A channel which contains messages to be sent to the given peer.
 	SendQueue chan []byte
+	// A channel which contains requests and heartbeats to be sent to the given peer.
+	// It is drained before the SendQueue, so requests don't get stuck behind transactions.
+	RequestSendQueue chan []byte
 	// Whether this peer is marked as disconnected.
 	// Used to suppress errors stemming from connection closure.
 	Disconnected bool
@@ -176,12 +204,31 @@ func (p *Peer) CheckStaledAutopeer(maxPercentage int) (bool, float32) {
 
 // EnqueueForSending enqueues the given data to be sent to the peer.
 // If it can't because the send queue is over capacity, the message gets dropped.
-func (p *Peer) EnqueueForSending(data []byte) {
+// Returns whether the message was enqueued.
+func (p *Peer) EnqueueForSending(data []byte) bool {
+	return p.enqueue(p.SendQueue, data)
+}
+
+// EnqueueRequestForSending enqueues the given request or heartbeat to be sent to the peer.
+// If it can't because the request send queue is over capacity, the message gets dropped.
+// Returns whether the message was enqueued.
+func (p *Peer) EnqueueRequestForSending(data []byte) bool {
+	return p.enqueue(p.RequestSendQueue, data)
+}
+
+// IsRequestSendQueueSaturated tells whether the request send queue of the peer is (nearly) full.
+func (p *Peer) IsRequestSendQueueSaturated() bool {
+	return len(p.RequestSendQueue)*100 >= cap(p.RequestSendQueue)*RequestSendQueueSaturation
+}
+
+func (p *Peer) enqueue(queue chan []byte, data []byte) bool {
 	select {
-	case p.SendQueue <- data:
+	case queue <- data:
+		return true
 	default:
 		metrics.SharedServerMetrics.DroppedMessages.Inc()
 		p.Metrics.DroppedPackets.Inc()
+		return false
 	}
 }
 
@@ -208,14 +255,41 @@ func (p *Peer) Info() *Info {
 		NumberOfSentMilestoneReq:       p.Metrics.SentMilestoneRequests.Load(),
 		NumberOfSentHeartbeats:         p.Metrics.SentHeartbeats.Load(),
 		NumberOfDroppedSentPackets:     p.Metrics.DroppedPackets.Load(),
+		NumberOfInvalidTransactions:    p.Metrics.InvalidTransactions.Load(),
+		NumberOfInvalidRequests:        p.Metrics.InvalidRequests.Load(),
+		NumberOfAnsweredTransactionReq: p.Metrics.ReceivedRequestedTransactions.Load(),
+		NumberOfRateLimitedMessages:    p.Metrics.RateLimitedMessages.Load(),
 		ConnectionType:                 "tcp",
 		Connected:                      false,
 		Autopeered:                     false,
 		AutopeeringID:                  "",
 	}
-	if p.Autopeering != nil {
+	if p.PublicKey != nil {
+		info.PublicKey = p.PublicKey.String()
+	}
+	if p.Conn != nil {
+		_, info.Encrypted = p.Conn.Conn.(*tls.Conn)
+	}
+	if info.NumberOfSentTransactionsReq > 0 {
+		info.RequestAnswerRatio = float64(info.NumberOfAnsweredTransactionReq) / float64(info.NumberOfSentTransactionsReq)
+		if info.RequestAnswerRatio > 1 {
+			// requested transactions can also be received without being requested from this peer
+			info.RequestAnswerRatio = 1
+		}
+	}
+	if latency, measured := p.RequestLatency.Average(); measured {
+		info.RequestLatency = latency.Milliseconds()
+	}
+	info.NumberOfTimedOutTransactionReq = p.RequestLatency.Timeouts()
+	switch {
+	case p.Autopeering != nil:
 		info.Autopeered = true
 		info.AutopeeringID = p.Autopeering.ID().String()
+		info.Relation = RelationAutopeered
+	case p.MoveBackToReconnectPool:
+		info.Relation = RelationStatic
+	default:
+		info.Relation = RelationUnknown
 	}
 	return info
 }
@@ -279,6 +353,14 @@ type Metrics struct {
 	SentHeartbeats atomic.Uint32
 	// The number of dropped packets.
 	DroppedPackets atomic.Uint32
+	// The number of received invalid transactions.
+	InvalidTransactions atomic.Uint32
+	// The number of received invalid requests.
+	InvalidRequests atomic.Uint32
+	// The number of received transactions which were requested by the node.
+	ReceivedRequestedTransactions atomic.Uint32
+	// The number of received messages which were dropped because they exceeded a rate limit.
+	RateLimitedMessages atomic.Uint32
 }
 
 // Info acts as a static snapshot of information about a peer.
@@ -307,4 +389,21 @@ type Info struct {
 	Connected                      bool   `json:"connected"`
 	Autopeered                     bool   `json:"autopeered"`
 	AutopeeringID                  string `json:"autopeeringId,omitempty"`
+	NumberOfInvalidTransactions    uint32 `json:"numberOfInvalidTransactions"`
+	NumberOfInvalidRequests        uint32 `json:"numberOfInvalidRequests"`
+	NumberOfAnsweredTransactionReq uint32 `json:"numberOfAnsweredTransactionReq"`
+	NumberOfRateLimitedMessages    uint32 `json:"numberOfRateLimitedMessages"`
+	// RequestAnswerRatio is the ratio of sent transaction requests which this peer answered.
+	RequestAnswerRatio float64 `json:"requestAnswerRatio"`
+	// RequestLatency is the average time in milliseconds the peer needs to answer a transaction request, zero if not measured yet.
+	RequestLatency                 int64  `json:"requestLatency"`
+	NumberOfTimedOutTransactionReq uint32 `json:"numberOfTimedOutTransactionReq"`
+	MisbehaviorScore               int    `json:"misbehaviorScore"`
+	// BannedUntil is the unix timestamp until which the peer is banned.
+	BannedUntil       int64  `json:"bannedUntil,omitempty"`
+	Relation          string `json:"relation"`
+	ReconnectAttempts int    `json:"reconnectAttempts,omitempty"`
+	// PublicKey is the base58 encoded public key the peer authenticates itself with.
+	PublicKey string `json:"publicKey,omitempty"`
+	Encrypted bool   `json:"encrypted"`
 }
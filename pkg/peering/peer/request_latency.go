@@ -0,0 +1,131 @@
+package peer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+const (
+	// MaxPendingLatencyRequests defines the maximum amount of unanswered requests per peer whose latency is tracked.
+	MaxPendingLatencyRequests = 5000
+	// the weight in percent of a new sample in the average request latency.
+	requestLatencySampleWeight = 20
+)
+
+// RequestLatency tracks the time a peer needs to answer the transaction requests sent to it.
+// Requests which are not answered within the timeout are accounted with the timeout as their latency,
+// so that peers which don't answer become less preferable than slow ones.
+type RequestLatency struct {
+	sync.Mutex
+	// the send times of the unanswered requests.
+	pending map[string]time.Time
+	// the exponentially weighted moving average of the request latency.
+	average time.Duration
+	// the amount of samples in the average.
+	samples uint32
+	// the amount of requests which were not answered within the timeout.
+	timeouts uint32
+}
+
+// NewRequestLatency creates a new RequestLatency.
+func NewRequestLatency() *RequestLatency {
+	return &RequestLatency{pending: make(map[string]time.Time)}
+}
+
+// Sent marks the request for the given transaction as sent at the given time.
+func (l *RequestLatency) Sent(hash hornet.Hash, now time.Time) {
+	l.Lock()
+	defer l.Unlock()
+
+	if _, exists := l.pending[string(hash)]; exists {
+		// keep the time of the first request, a retry doesn't make the peer faster
+		return
+	}
+	if len(l.pending) >= MaxPendingLatencyRequests {
+		return
+	}
+	l.pending[string(hash)] = now
+}
+
+// Answered marks the request for the given transaction as answered at the given time
+// and adds its latency to the average. Returns false if the request was not tracked.
+func (l *RequestLatency) Answered(hash hornet.Hash, now time.Time) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	sentTime, exists := l.pending[string(hash)]
+	if !exists {
+		return false
+	}
+	delete(l.pending, string(hash))
+
+	l.addSample(now.Sub(sentTime))
+	return true
+}
+
+// Forget stops tracking the request for the given transaction without adding a sample,
+// e.g. because the transaction was received from another peer.
+func (l *RequestLatency) Forget(hash hornet.Hash) {
+	l.Lock()
+	defer l.Unlock()
+
+	delete(l.pending, string(hash))
+}
+
+// Expire accounts all requests which were sent before the given timeout with the timeout as their latency
+// and stops tracking them. Returns the amount of timed out requests.
+func (l *RequestLatency) Expire(timeout time.Duration, now time.Time) int {
+	l.Lock()
+	defer l.Unlock()
+
+	expired := 0
+	for hash, sentTime := range l.pending {
+		if now.Sub(sentTime) < timeout {
+			continue
+		}
+		delete(l.pending, hash)
+		l.addSample(timeout)
+		expired++
+	}
+	l.timeouts += uint32(expired)
+	return expired
+}
+
+// Average returns the average request latency and whether any request was measured yet.
+func (l *RequestLatency) Average() (time.Duration, bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.average, l.samples > 0
+}
+
+// Timeouts returns the amount of requests which were not answered within the timeout.
+func (l *RequestLatency) Timeouts() uint32 {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.timeouts
+}
+
+func (l *RequestLatency) addSample(latency time.Duration) {
+	if l.samples == 0 {
+		l.average = latency
+	} else {
+		l.average = (l.average*(100-requestLatencySampleWeight) + latency*requestLatencySampleWeight) / 100
+	}
+	l.samples++
+}
+
+// IsFasterThan tells whether the given peer answers requests faster than the other peer.
+// Peers without any measured request are preferred, so that their latency gets measured.
+func (p *Peer) IsFasterThan(other *Peer) bool {
+	latency, measured := p.RequestLatency.Average()
+	otherLatency, otherMeasured := other.RequestLatency.Average()
+
+	if measured != otherMeasured {
+		return !measured
+	}
+	return latency < otherLatency
+}
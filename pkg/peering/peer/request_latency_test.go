@@ -0,0 +1,58 @@
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+func TestRequestLatency(t *testing.T) {
+	l := NewRequestLatency()
+	start := time.Now()
+
+	_, measured := l.Average()
+	assert.False(t, measured)
+
+	l.Sent(hornet.Hash("a"), start)
+	l.Sent(hornet.Hash("b"), start)
+	l.Sent(hornet.Hash("c"), start)
+
+	// a retry doesn't reset the send time
+	l.Sent(hornet.Hash("a"), start.Add(50*time.Millisecond))
+	assert.True(t, l.Answered(hornet.Hash("a"), start.Add(100*time.Millisecond)))
+	assert.False(t, l.Answered(hornet.Hash("a"), start.Add(100*time.Millisecond)))
+
+	latency, measured := l.Average()
+	assert.True(t, measured)
+	assert.Equal(t, 100*time.Millisecond, latency)
+
+	// forgotten requests neither time out nor add a sample
+	l.Forget(hornet.Hash("b"))
+	assert.Equal(t, 0, l.Expire(time.Second, start.Add(500*time.Millisecond)))
+	assert.Equal(t, 1, l.Expire(time.Second, start.Add(time.Second)))
+	assert.EqualValues(t, 1, l.Timeouts())
+
+	latency, _ = l.Average()
+	assert.Equal(t, 280*time.Millisecond, latency)
+}
+
+func TestPeer_IsFasterThan(t *testing.T) {
+	newPeer := func(latency time.Duration) *Peer {
+		p := &Peer{RequestLatency: NewRequestLatency()}
+		if latency > 0 {
+			now := time.Now()
+			p.RequestLatency.Sent(hornet.Hash("a"), now)
+			p.RequestLatency.Answered(hornet.Hash("a"), now.Add(latency))
+		}
+		return p
+	}
+
+	fast, slow, unmeasured := newPeer(10*time.Millisecond), newPeer(time.Second), newPeer(0)
+	assert.True(t, fast.IsFasterThan(slow))
+	assert.False(t, slow.IsFasterThan(fast))
+	assert.True(t, unmeasured.IsFasterThan(fast))
+	assert.False(t, fast.IsFasterThan(unmeasured))
+}
@@ -1,15 +1,18 @@
 package peering
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	autopeering "github.com/iotaledger/hive.go/autopeering/peer"
+	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/iputils"
 	"github.com/iotaledger/hive.go/network"
@@ -18,6 +21,7 @@ import (
 	"go.uber.org/atomic"
 
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/pkg/peering/peer"
 	"github.com/gohornet/hornet/pkg/protocol"
@@ -38,6 +42,8 @@ var (
 	ErrNonMatchingMWM = errors.New("used MWM doesn't match")
 	// ErrNonMatchingCooAddr is returned when the Coo address doesn't match this node's Coo address.
 	ErrNonMatchingCooAddr = errors.New("used coo addr doesn't match")
+	// ErrNonMatchingNetworkID is returned when the network ID doesn't match this node's network ID.
+	ErrNonMatchingNetworkID = errors.New("used network ID doesn't match")
 	// ErrNonMatchingSrvSocketPort is returned when the server socket port doesn't match.
 	ErrNonMatchingSrvSocketPort = errors.New("advertised server socket port doesn't match")
 	// ErrUnknownPeerID is returned when an unknown peer tried to connect.
@@ -48,11 +54,13 @@ var (
 	ErrPeerAlreadyInReconnect = errors.New("peer is already in the reconnect pool")
 	// ErrManagerIsShutdown is returned when the manager is shutdown.
 	ErrManagerIsShutdown = errors.New("peering manager is shutdown")
+	// ErrPeerBanned is returned when a peer is banned because of misbehavior.
+	ErrPeerBanned = errors.New("peer is banned")
 )
 
 // NewManager creates a new manager instance with the given Options and moves the given peers
-// into the reconnect pool.
-func NewManager(opts Options, peers ...*config.PeerConfig) *Manager {
+// into the reconnect pool. It returns an error if the encryption can't be configured with the given identity.
+func NewManager(opts Options, peers ...*config.PeerConfig) (*Manager, error) {
 	m := &Manager{
 		Events: Events{
 			ConnectedAutopeeredPeer:               events.NewEvent(peer.Caller),
@@ -68,18 +76,24 @@ func NewManager(opts Options, peers ...*config.PeerConfig) *Manager {
 			AutopeeredPeerHandshaking:             events.NewEvent(peer.Caller),
 			AutopeeredPeerBecameStatic:            events.NewEvent(peer.IdentityCaller),
 			IPLookupError:                         events.NewEvent(events.ErrorCaller),
+			PeerBanned:                            events.NewEvent(peer.Caller),
 			Shutdown:                              events.NewEvent(events.CallbackCaller),
 			Error:                                 events.NewEvent(events.ErrorCaller),
 		},
-		tcpServer: tcp.NewServer(),
-		connected: map[string]*peer.Peer{},
-		reconnect: map[string]*reconnectinfo{},
-		whitelist: map[string]*autopeering.Peer{},
-		blacklist: map[string]struct{}{},
-		Opts:      opts,
+		tcpServer:   tcp.NewServer(),
+		connected:   map[string]*peer.Peer{},
+		reconnect:   map[string]*reconnectinfo{},
+		whitelist:   map[string]*autopeering.Peer{},
+		publicKeys:  map[string]*ed25519.PublicKey{},
+		blacklist:   map[string]time.Time{},
+		reputations: map[string]*reputation{},
+		Opts:        opts,
+	}
+	if err := m.configureEncryption(); err != nil {
+		return nil, err
 	}
 	m.moveInitialPeersToReconnectPool(peers)
-	return m
+	return m, nil
 }
 
 // Manager manages a set of connected peers and those to which the node
@@ -101,11 +115,18 @@ type Manager struct {
 	// holds peers to which we want to connect to.
 	reconnect map[string]*reconnectinfo
 	// defines the set of allowed peer identities.
-	whitelist   map[string]*autopeering.Peer
+	whitelist map[string]*autopeering.Peer
+	// defines the public keys the peers with the given identities have to authenticate themselves with.
+	publicKeys  map[string]*ed25519.PublicKey
 	whitelistMu sync.Mutex
-	// defines a set of blacklisted IP addresses.
-	blacklist   map[string]struct{}
+	// the certificate used to encrypt the connections, nil if the node has no identity.
+	certificate *tls.Certificate
+	// defines a set of blacklisted IP addresses and until when they are blacklisted (zero = permanently).
+	blacklist   map[string]time.Time
 	blacklistMu sync.Mutex
+	// holds the reputation of peers which misbehaved.
+	reputations   map[string]*reputation
+	reputationsMu sync.Mutex
 	// used to enforce one handshake verification at a time.
 	handshakeVerifyMu sync.Mutex
 
@@ -120,6 +141,12 @@ type reconnectinfo struct {
 	OriginAddr  *iputils.OriginAddress `json:"origin_addr"`
 	CachedIPs   *iputils.IPAddresses   `json:"cached_ips"`
 	Autopeering *autopeering.Peer      `json:"peer"`
+	// the public key the peer has to authenticate itself with, nil if the connection is not encrypted.
+	PublicKey *ed25519.PublicKey `json:"public_key"`
+	// the amount of consecutive failed connection attempts.
+	attempts int
+	// no connection attempt is made before this time.
+	nextAttempt time.Time
 }
 
 // Options defines options for the Manager.
@@ -131,6 +158,19 @@ type Options struct {
 	AcceptAnyPeer bool
 	// Inbound connection bind address.
 	BindAddress string
+	// The time to wait before reconnecting to a peer after the first failed connection attempt.
+	// The time doubles with every further failed attempt (0 = reconnect on every reconnect run).
+	ReconnectBackoff time.Duration
+	// The maximum time to wait before reconnecting to a peer.
+	MaxReconnectBackoff time.Duration
+	// The misbehavior score at which a peer gets banned (0 = peers are never banned).
+	MaxMisbehaviorScore int
+	// The time a misbehaving peer is banned.
+	BanDuration time.Duration
+	// The identity used to encrypt the connections to peers which are configured with a public key (nil = no encryption).
+	Identity *ed25519.PrivateKey
+	// Whether to encrypt the connections to autopeered peers and authenticate them with their autopeering identity.
+	EncryptAutopeering bool
 }
 
 // Events defines events fired regarding peering.
@@ -157,6 +197,8 @@ type Events struct {
 	AutopeeredPeerHandshaking *events.Event
 	// Fired when an autopeered peer was added as a static neighbor.
 	AutopeeredPeerBecameStatic *events.Event
+	// Fired when a peer is banned because its misbehavior score reached the maximum.
+	PeerBanned *events.Event
 	// Fired when a reconnect is initiated over the entire reconnect pool.
 	Reconnecting *events.Event
 	// Fired when during the reconnect phase a peer is already connected.
@@ -209,15 +251,25 @@ func (m *Manager) IsStaticallyPeered(ips []string, port uint16) bool {
 // Blacklisted tells whether the given IP address is blacklisted.
 func (m *Manager) Blacklisted(ip string) bool {
 	m.blacklistMu.Lock()
-	_, blacklisted := m.blacklist[ip]
-	m.blacklistMu.Unlock()
+	defer m.blacklistMu.Unlock()
+
+	until, blacklisted := m.blacklist[ip]
+	if blacklisted && !until.IsZero() && time.Now().After(until) {
+		delete(m.blacklist, ip)
+		return false
+	}
 	return blacklisted
 }
 
 // Blacklist blacklists the given IP from connecting.
 func (m *Manager) Blacklist(ip string) {
+	m.blacklistUntil(ip, time.Time{})
+}
+
+// blacklists the given IP from connecting until the given time (zero = permanently).
+func (m *Manager) blacklistUntil(ip string, until time.Time) {
 	m.blacklistMu.Lock()
-	m.blacklist[ip] = struct{}{}
+	m.blacklist[ip] = until
 	m.blacklistMu.Unlock()
 }
 
@@ -256,9 +308,40 @@ func (m *Manager) Whitelist(ips []string, port uint16, autopeeringPeer ...*autop
 func (m *Manager) WhitelistRemove(id string) {
 	m.whitelistMu.Lock()
 	delete(m.whitelist, id)
+	delete(m.publicKeys, id)
 	m.whitelistMu.Unlock()
 }
 
+// sets the public key all possible IDs for the given IP addresses/port combination have to authenticate themselves with.
+// a nil public key allows plaintext connections again.
+func (m *Manager) pinPublicKey(ips []string, port uint16, publicKey *ed25519.PublicKey) {
+	m.whitelistMu.Lock()
+	defer m.whitelistMu.Unlock()
+	for _, ip := range ips {
+		id := peer.NewID(ip, port)
+		if publicKey == nil {
+			delete(m.publicKeys, id)
+			continue
+		}
+		m.publicKeys[id] = publicKey
+	}
+}
+
+// returns the public key the peer with the given ID has to authenticate itself with.
+// autopeered peers authenticate themselves with their autopeering identity if encryption is enabled for them.
+func (m *Manager) expectedPublicKey(id string) *ed25519.PublicKey {
+	m.whitelistMu.Lock()
+	defer m.whitelistMu.Unlock()
+	if publicKey, pinned := m.publicKeys[id]; pinned {
+		return publicKey
+	}
+	if autopeeringPeer := m.whitelist[id]; autopeeringPeer != nil && m.Opts.EncryptAutopeering {
+		publicKey := autopeeringPeer.PublicKey()
+		return &publicKey
+	}
+	return nil
+}
+
 // PeerConsumerFunc is a function which consumes a peer.
 // If it returns false, it signals that no further calls should be made to the function.
 type PeerConsumerFunc func(p *peer.Peer) bool
@@ -295,6 +378,8 @@ func (m *Manager) ForAll(f PeerConsumerFunc) {
 			InitAddress: p.OriginAddr,
 			Addresses:   p.CachedIPs,
 			Autopeering: p.Autopeering,
+			// the reconnect peer is only used to gather info about it
+			RequestLatency: peer.NewRequestLatency(),
 		}
 		if !f(peer) {
 			return
@@ -326,25 +411,33 @@ func (m *Manager) PeerInfos() []*peer.Info {
 	for _, p := range m.connected {
 		info := p.Info()
 		info.Connected = true
+		m.fillReputation(info, reputationKey(p))
 		infos = append(infos, info)
 	}
 	for _, reconnectInfo := range m.reconnect {
 		originAddr := reconnectInfo.OriginAddr
 		addrStr := fmt.Sprintf("%s:%d", originAddr.Addr, originAddr.Port)
 		info := &peer.Info{
-			Address:        addrStr,
-			Domain:         originAddr.Addr,
-			DomainWithPort: addrStr,
-			Alias:          originAddr.Alias,
-			ConnectionType: "tcp",
-			Connected:      false,
-			Autopeered:     false,
-			PreferIPv6:     originAddr.PreferIPv6,
+			Address:           addrStr,
+			Domain:            originAddr.Addr,
+			DomainWithPort:    addrStr,
+			Alias:             originAddr.Alias,
+			ConnectionType:    "tcp",
+			Connected:         false,
+			Autopeered:        false,
+			Relation:          peer.RelationStatic,
+			PreferIPv6:        originAddr.PreferIPv6,
+			ReconnectAttempts: reconnectInfo.attempts,
+		}
+		if reconnectInfo.PublicKey != nil {
+			info.PublicKey = reconnectInfo.PublicKey.String()
 		}
 		if reconnectInfo.Autopeering != nil {
 			info.Autopeered = true
 			info.AutopeeringID = reconnectInfo.Autopeering.ID().String()
+			info.Relation = peer.RelationAutopeered
 		}
+		m.fillReputation(info, originAddr.String())
 		infos = append(infos, info)
 	}
 	return infos
@@ -364,6 +457,33 @@ func (m *Manager) ConnectedPeerCount() int {
 	return len(m.connected)
 }
 
+// NeighborsLatestMilestoneIndex returns the highest latest milestone index which at least "quorum" connected peers
+// reported in their heartbeats. it returns 0 if less than "quorum" peers sent a heartbeat or the quorum is 0.
+func (m *Manager) NeighborsLatestMilestoneIndex(quorum int) milestone.Index {
+	if quorum <= 0 {
+		return 0
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+
+	latestIndexes := make([]milestone.Index, 0, len(m.connected))
+	for _, p := range m.connected {
+		if p.LatestHeartbeat == nil {
+			continue
+		}
+		latestIndexes = append(latestIndexes, p.LatestHeartbeat.LatestMilestoneIndex)
+	}
+
+	if len(latestIndexes) < quorum {
+		return 0
+	}
+
+	// a single neighbor which reports a wrong index can't make the node look unsync
+	sort.Slice(latestIndexes, func(i, j int) bool { return latestIndexes[i] > latestIndexes[j] })
+	return latestIndexes[quorum-1]
+}
+
 // ConnectedPeerCount returns the current count of connected peers.
 // it has a cooldown time to not update too frequently.
 func (m *Manager) ConnectedAndSyncedPeerCount() (uint8, uint8) {
@@ -477,17 +597,32 @@ func (m *Manager) SetupEventHandlers(p *peer.Peer) {
 }
 
 // Add adds a new peer to the reconnect pool and immediately invokes a connection attempt.
-// The peer is not added if it is already connected or the given address is invalid.
-func (m *Manager) Add(addr string, preferIPv6 bool, alias string, autoPeer ...*autopeering.Peer) error {
+// If a base58 encoded public key is given, the connection to the peer is encrypted and the peer
+// has to authenticate itself with the given key.
+// The peer is not added if it is already connected or the given address or public key is invalid.
+func (m *Manager) Add(addr string, preferIPv6 bool, alias string, publicKey string, autoPeer ...*autopeering.Peer) error {
 
 	originAddr, err := iputils.ParseOriginAddress(addr)
 	if err != nil {
 		return fmt.Errorf("invalid peer address '%s': %w", addr, err)
 	}
 
+	pubKey, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
 	originAddr.PreferIPv6 = preferIPv6
 	originAddr.Alias = alias
 
+	isAutopeer := len(autoPeer) > 0
+
+	// autopeered peers are chosen again and again, so the ban has to be enforced here.
+	// static peers which are added manually override the ban.
+	if _, bannedUntil := m.misbehaviorScore(originAddr.String()); isAutopeer && !bannedUntil.IsZero() {
+		return fmt.Errorf("%w: '%s' is banned until %v", ErrPeerBanned, originAddr.String(), bannedUntil)
+	}
+
 	// check whether the peer is already connected by examining all IP addresses
 	possibleIPs, err := iputils.GetIPAddressesFromHost(originAddr.Addr)
 	if err != nil {
@@ -497,7 +632,6 @@ func (m *Manager) Add(addr string, preferIPv6 bool, alias string, autoPeer ...*a
 	m.Lock()
 
 	reconnect := false
-	isAutopeer := len(autoPeer) > 0
 
 	defer func() {
 		m.Unlock()
@@ -547,9 +681,13 @@ func (m *Manager) Add(addr string, preferIPv6 bool, alias string, autoPeer ...*a
 	}
 
 	// construct reconnect info
-	reconnectInfo := &reconnectinfo{OriginAddr: originAddr, CachedIPs: possibleIPs}
+	reconnectInfo := &reconnectinfo{OriginAddr: originAddr, CachedIPs: possibleIPs, PublicKey: pubKey}
 	if isAutopeer {
 		reconnectInfo.Autopeering = autoPeer[0]
+		if m.Opts.EncryptAutopeering {
+			autopeeringPublicKey := autoPeer[0].PublicKey()
+			reconnectInfo.PublicKey = &autopeeringPublicKey
+		}
 	}
 
 	m.moveToReconnectPool(reconnectInfo)
@@ -646,11 +784,21 @@ func (m *Manager) Listen() error {
 
 		m.Events.PeerHandshakingIncoming.Trigger(conn.RemoteAddr().String())
 
+		// the remote decides whether the connection is encrypted,
+		// whether it had to be is checked after the gossip handshake.
+		gossipConn, publicKey, err := m.acceptInbound(conn.Conn)
+		if err != nil {
+			m.Events.Error.Trigger(fmt.Errorf("can't accept connection from %s: %w", conn.RemoteAddr().String(), err))
+			_ = conn.Close()
+			return
+		}
+
 		// init peer
 		p := peer.NewInboundPeer(conn.Conn.RemoteAddr())
-		p.Conn = conn
+		p.Conn = network.NewManagedConnection(gossipConn)
 		p.Conn.SetWriteTimeout(connectionWriteTimeout)
-		p.Protocol = protocol.New(conn)
+		p.PublicKey = publicKey
+		p.Protocol = protocol.New(p.Conn)
 		m.SetupEventHandlers(p)
 
 		// kick off protocol
@@ -721,10 +869,45 @@ func (m *Manager) moveFromConnectedToReconnectPool(p *peer.Peer) {
 	// remove any other excess reconnect entry
 	m.removeFromReconnectPool(p)
 
-	m.reconnect[p.InitAddress.String()] = &reconnectinfo{OriginAddr: p.InitAddress, CachedIPs: p.Addresses}
+	// peers which disconnect after a successful handshake are reconnected immediately,
+	// while failed connection attempts back off exponentially.
+	var attempts int
+	if p.Protocol == nil || !p.Protocol.IsHandshaked() {
+		attempts = p.ReconnectAttempts + 1
+	}
+
+	// banned peers are reconnected after the ban
+	nextAttempt := time.Now().Add(m.reconnectBackoff(attempts))
+	if _, bannedUntil := m.misbehaviorScore(reputationKey(p)); bannedUntil.After(nextAttempt) {
+		nextAttempt = bannedUntil
+	}
+
+	m.reconnect[p.InitAddress.String()] = &reconnectinfo{
+		OriginAddr:  p.InitAddress,
+		CachedIPs:   p.Addresses,
+		PublicKey:   p.PublicKey,
+		attempts:    attempts,
+		nextAttempt: nextAttempt,
+	}
 	m.Events.PeerMovedFromConnectedToReconnectPool.Trigger(p)
 }
 
+// returns the time to wait before the next connection attempt after the given amount of failed attempts.
+func (m *Manager) reconnectBackoff(attempts int) time.Duration {
+	if attempts == 0 || m.Opts.ReconnectBackoff == 0 {
+		return 0
+	}
+
+	backoff := m.Opts.ReconnectBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if m.Opts.MaxReconnectBackoff != 0 && backoff >= m.Opts.MaxReconnectBackoff {
+			return m.Opts.MaxReconnectBackoff
+		}
+	}
+	return backoff
+}
+
 // moves the given peer into the reconnect pool
 func (m *Manager) moveToReconnectPool(reconnectInfo *reconnectinfo) {
 	if _, has := m.reconnect[reconnectInfo.OriginAddr.String()]; has {
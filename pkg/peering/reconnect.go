@@ -26,9 +26,16 @@ func (m *Manager) Reconnect() {
 
 	m.Events.Reconnecting.Trigger(int32(len(m.reconnect)))
 
+	now := time.Now()
+
 	// try to lookup each address and if we fail to do so, keep the address in the reconnect pool
 next:
 	for k, reconnectInfo := range m.reconnect {
+		if now.Before(reconnectInfo.nextAttempt) {
+			// the previous connection attempts failed, wait until the backoff is over
+			continue
+		}
+
 		originAddr := reconnectInfo.OriginAddr
 		peerAddrs, err := iputils.GetIPAddressesFromHost(originAddr.Addr)
 		if err != nil {
@@ -57,12 +64,15 @@ next:
 
 		// whitelist all possible combinations for this peer ID
 		m.Whitelist(ips, reconnectInfo.OriginAddr.Port)
+		m.pinPublicKey(ips, reconnectInfo.OriginAddr.Port, reconnectInfo.PublicKey)
 
 		// create a new outbound peer and inject autopeering metadata if available
 		p := peer.NewOutboundPeer(originAddr, prefIP, originAddr.Port, peerAddrs)
 		if reconnectInfo.Autopeering != nil {
 			p.Autopeering = reconnectInfo.Autopeering
 		}
+		p.ReconnectAttempts = reconnectInfo.attempts
+		p.PublicKey = reconnectInfo.PublicKey
 		peersToConnectTo = append(peersToConnectTo, p)
 	}
 	m.Unlock()
@@ -107,8 +117,13 @@ func (m *Manager) moveInitialPeersToReconnectPool(peers []*config.PeerConfig) {
 		originAddr.PreferIPv6 = peerConf.PreferIPv6
 		originAddr.Alias = peerConf.Alias
 
+		publicKey, err := ParsePublicKey(peerConf.PublicKey)
+		if err != nil {
+			panic(errors.Wrapf(err, "invalid public key of peer %s", peerConf.ID))
+		}
+
 		// no need to lock the manager in the configure stage
-		m.moveToReconnectPool(&reconnectinfo{OriginAddr: originAddr})
+		m.moveToReconnectPool(&reconnectinfo{OriginAddr: originAddr, PublicKey: publicKey})
 	}
 }
 
@@ -120,6 +135,15 @@ func (m *Manager) connect(p *peer.Peer) error {
 		return fmt.Errorf("can't connect to %s: %w", p.ID, err)
 	}
 
+	if p.PublicKey != nil {
+		encryptedConn, err := m.encryptOutbound(conn, *p.PublicKey)
+		if err != nil {
+			_ = conn.Close()
+			return fmt.Errorf("can't encrypt the connection to %s: %w", p.ID, err)
+		}
+		conn = encryptedConn
+	}
+
 	p.Conn = network.NewManagedConnection(conn)
 	p.Conn.SetWriteTimeout(connectionWriteTimeout)
 	p.Protocol = protocol.New(p.Conn)
@@ -0,0 +1,143 @@
+package peering
+
+import (
+	"time"
+
+	"github.com/gohornet/hornet/pkg/peering/peer"
+)
+
+const (
+	// PenaltyInvalidTransaction is the misbehavior score a peer gets for sending an invalid transaction.
+	PenaltyInvalidTransaction = 10
+	// PenaltyInvalidRequest is the misbehavior score a peer gets for sending an invalid request.
+	PenaltyInvalidRequest = 10
+	// PenaltyStaleHeartbeat is the misbehavior score a peer gets if it didn't send heartbeats anymore.
+	PenaltyStaleHeartbeat = 5
+
+	// MisbehaviorScoreLifetime is the time after which the misbehavior score of a peer
+	// is reset if the peer didn't misbehave again.
+	MisbehaviorScoreLifetime = time.Hour
+)
+
+// reputation keeps track of the misbehavior of a peer across reconnects.
+type reputation struct {
+	score           int
+	lastMisbehavior time.Time
+	bannedUntil     time.Time
+}
+
+// returns whether the reputation can be forgotten, because the peer didn't misbehave
+// for MisbehaviorScoreLifetime and isn't banned anymore.
+func (rep *reputation) expired(now time.Time) bool {
+	return now.Sub(rep.lastMisbehavior) > MisbehaviorScoreLifetime && !now.Before(rep.bannedUntil)
+}
+
+// returns the key under which the reputation of the given peer is stored.
+// it is the same key under which the peer is kept in the reconnect pool.
+func reputationKey(p *peer.Peer) string {
+	if p.InitAddress == nil {
+		return p.ID
+	}
+	return p.InitAddress.String()
+}
+
+// Misbehaved adds the given penalty to the misbehavior score of the peer.
+// If the score reaches Options.MaxMisbehaviorScore, the peer is disconnected and banned for Options.BanDuration.
+// Static peers are kept in the reconnect pool and are reconnected after the ban,
+// all other peers can't connect until the ban is over.
+func (m *Manager) Misbehaved(p *peer.Peer, penalty int) {
+	now := time.Now()
+
+	m.reputationsMu.Lock()
+	rep, exists := m.reputations[reputationKey(p)]
+	if !exists {
+		// the map only grows here, so sweeping it before every new entry keeps
+		// only the peers which misbehaved recently or are still banned.
+		m.expireReputations(now)
+
+		rep = &reputation{}
+		m.reputations[reputationKey(p)] = rep
+	}
+	if now.Sub(rep.lastMisbehavior) > MisbehaviorScoreLifetime {
+		rep.score = 0
+	}
+	rep.score += penalty
+	rep.lastMisbehavior = now
+
+	banned := m.Opts.MaxMisbehaviorScore > 0 && rep.score >= m.Opts.MaxMisbehaviorScore
+	if banned {
+		rep.score = 0
+		rep.bannedUntil = now.Add(m.Opts.BanDuration)
+	}
+	bannedUntil := rep.bannedUntil
+	m.reputationsMu.Unlock()
+
+	if !banned {
+		return
+	}
+
+	m.Events.PeerBanned.Trigger(p)
+
+	if p.Autopeering != nil || !p.MoveBackToReconnectPool {
+		if err := m.Remove(p.ID); err != nil {
+			m.Events.Error.Trigger(err)
+		}
+		// the removal blacklists the peer permanently, but it is only banned temporarily
+		m.blacklistUntil(p.PrimaryAddress.String(), bannedUntil)
+		return
+	}
+
+	// the reconnect is delayed until the ban is over
+	if err := p.Conn.Close(); err != nil {
+		m.Events.Error.Trigger(err)
+	}
+}
+
+// removes the reputations of the peers which behaved for a long time.
+// the caller must hold reputationsMu.
+func (m *Manager) expireReputations(now time.Time) {
+	for key, rep := range m.reputations {
+		if rep.expired(now) {
+			delete(m.reputations, key)
+		}
+	}
+}
+
+// returns the current misbehavior score of the peer with the given key
+// and the time until the peer is banned (zero if it isn't banned).
+func (m *Manager) misbehaviorScore(key string) (int, time.Time) {
+	m.reputationsMu.Lock()
+	defer m.reputationsMu.Unlock()
+
+	rep, exists := m.reputations[key]
+	if !exists {
+		return 0, time.Time{}
+	}
+
+	now := time.Now()
+	if rep.expired(now) {
+		// the peer behaved for a long time, forget about it
+		delete(m.reputations, key)
+		return 0, time.Time{}
+	}
+
+	var bannedUntil time.Time
+	if now.Before(rep.bannedUntil) {
+		bannedUntil = rep.bannedUntil
+	}
+
+	if now.Sub(rep.lastMisbehavior) > MisbehaviorScoreLifetime {
+		return 0, bannedUntil
+	}
+
+	return rep.score, bannedUntil
+}
+
+// fills in the reputation of the peer with the given key.
+func (m *Manager) fillReputation(info *peer.Info, key string) {
+	score, bannedUntil := m.misbehaviorScore(key)
+	info.MisbehaviorScore = score
+	if !bannedUntil.IsZero() {
+		info.BannedUntil = bannedUntil.Unix()
+	}
+}
@@ -0,0 +1,131 @@
+package peering
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/iputils"
+	"github.com/iotaledger/hive.go/network"
+
+	"github.com/gohornet/hornet/pkg/peering/peer"
+)
+
+func newTestReputationManager(t *testing.T, maxMisbehaviorScore int) *Manager {
+	m, err := NewManager(Options{MaxMisbehaviorScore: maxMisbehaviorScore, BanDuration: time.Hour})
+	require.NoError(t, err)
+	return m
+}
+
+func newTestInboundPeer(ip string) *peer.Peer {
+	p := peer.NewInboundPeer(&net.TCPAddr{IP: net.ParseIP(ip), Port: 15600})
+	p.ID = peer.NewID(ip, 15600)
+	return p
+}
+
+// moves the last misbehavior of the peer with the given key into the past.
+func ageReputation(m *Manager, key string, age time.Duration) {
+	m.reputationsMu.Lock()
+	defer m.reputationsMu.Unlock()
+	m.reputations[key].lastMisbehavior = time.Now().Add(-age)
+}
+
+func TestMisbehaviorScore(t *testing.T) {
+	m := newTestReputationManager(t, 0)
+	p := newTestInboundPeer("10.0.0.1")
+
+	score, bannedUntil := m.misbehaviorScore(reputationKey(p))
+	assert.Zero(t, score)
+	assert.True(t, bannedUntil.IsZero())
+
+	m.Misbehaved(p, PenaltyInvalidTransaction)
+	m.Misbehaved(p, PenaltyStaleHeartbeat)
+
+	// peers are never banned without a maximum score
+	score, bannedUntil = m.misbehaviorScore(reputationKey(p))
+	assert.Equal(t, PenaltyInvalidTransaction+PenaltyStaleHeartbeat, score)
+	assert.True(t, bannedUntil.IsZero())
+}
+
+func TestMisbehaviorScoreDecay(t *testing.T) {
+	m := newTestReputationManager(t, 0)
+	p := newTestInboundPeer("10.0.0.1")
+	key := reputationKey(p)
+
+	m.Misbehaved(p, PenaltyInvalidTransaction)
+
+	// the score is kept within the lifetime
+	ageReputation(m, key, MisbehaviorScoreLifetime-time.Minute)
+	m.Misbehaved(p, PenaltyInvalidRequest)
+	score, _ := m.misbehaviorScore(key)
+	assert.Equal(t, PenaltyInvalidTransaction+PenaltyInvalidRequest, score)
+
+	// a misbehavior after the lifetime starts with a new score
+	ageReputation(m, key, MisbehaviorScoreLifetime+time.Minute)
+	m.Misbehaved(p, PenaltyStaleHeartbeat)
+	score, _ = m.misbehaviorScore(key)
+	assert.Equal(t, PenaltyStaleHeartbeat, score)
+
+	// the reputation is forgotten once it is queried after the lifetime
+	ageReputation(m, key, MisbehaviorScoreLifetime+time.Minute)
+	score, _ = m.misbehaviorScore(key)
+	assert.Zero(t, score)
+	assert.Empty(t, m.reputations)
+}
+
+func TestMisbehaviorBan(t *testing.T) {
+	m := newTestReputationManager(t, PenaltyInvalidTransaction+PenaltyInvalidRequest)
+
+	clientConn, serverConn := newTestConnPair(t)
+	defer serverConn.Close()
+
+	originAddr := &iputils.OriginAddress{Addr: "10.0.0.1", Port: 15600}
+	p := peer.NewOutboundPeer(originAddr, net.ParseIP("10.0.0.1"), 15600, iputils.NewIPAddresses())
+	p.Conn = network.NewManagedConnection(clientConn)
+	key := reputationKey(p)
+
+	var bannedPeer *peer.Peer
+	m.Events.PeerBanned.Attach(events.NewClosure(func(p *peer.Peer) { bannedPeer = p }))
+
+	m.Misbehaved(p, PenaltyInvalidTransaction)
+	assert.Nil(t, bannedPeer)
+
+	m.Misbehaved(p, PenaltyInvalidRequest)
+	assert.Equal(t, p, bannedPeer)
+
+	// the score is reset by the ban
+	score, bannedUntil := m.misbehaviorScore(key)
+	assert.Zero(t, score)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), bannedUntil, time.Minute)
+
+	// the static peer is disconnected and reconnected after the ban
+	_, err := serverConn.Read(make([]byte, 1))
+	assert.Error(t, err)
+
+	// the reputation of a banned peer is kept after the lifetime
+	ageReputation(m, key, MisbehaviorScoreLifetime+time.Minute)
+	_, bannedUntil = m.misbehaviorScore(key)
+	assert.False(t, bannedUntil.IsZero())
+	assert.Contains(t, m.reputations, key)
+}
+
+func TestExpireIdleReputations(t *testing.T) {
+	m := newTestReputationManager(t, 0)
+	idle := newTestInboundPeer("10.0.0.1")
+	recent := newTestInboundPeer("10.0.0.2")
+
+	m.Misbehaved(idle, PenaltyInvalidTransaction)
+	m.Misbehaved(recent, PenaltyInvalidTransaction)
+	ageReputation(m, reputationKey(idle), MisbehaviorScoreLifetime+time.Minute)
+
+	// the idle reputation is removed as soon as another peer misbehaves, even if it is never queried again
+	m.Misbehaved(newTestInboundPeer("10.0.0.3"), PenaltyInvalidTransaction)
+
+	assert.NotContains(t, m.reputations, reputationKey(idle))
+	assert.Contains(t, m.reputations, reputationKey(recent))
+	assert.Len(t, m.reputations, 2)
+}
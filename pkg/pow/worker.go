@@ -0,0 +1,163 @@
+package pow
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/curl"
+	"github.com/iotaledger/iota.go/pow"
+	"github.com/iotaledger/iota.go/trinary"
+)
+
+const (
+	nonceOffset         = consts.HashTrinarySize - consts.NonceTrinarySize
+	nonceInitStart      = nonceOffset + 4
+	nonceIncrementStart = nonceInitStart + consts.NonceTrinarySize/3
+
+	hBits uint64 = 0xFFFFFFFFFFFFFFFF
+)
+
+var (
+	// ErrInvalidTransactionTrytes is returned if the trytes given to the worker are no transaction trytes.
+	ErrInvalidTransactionTrytes = errors.New("invalid transaction trytes")
+	// ErrInvalidMWM is returned if the given minimum weight magnitude is out of range.
+	ErrInvalidMWM = errors.New("invalid minimum weight magnitude")
+)
+
+// Score returns the amount of trailing zero trits of the given transaction hash,
+// which is the maximum minimum weight magnitude the transaction fulfills.
+func Score(txHashTrits trinary.Trits) int {
+	score := 0
+	for i := len(txHashTrits) - 1; i >= 0; i-- {
+		if txHashTrits[i] != 0 {
+			break
+		}
+		score++
+	}
+	return score
+}
+
+// Worker calculates transaction nonces with a fixed amount of goroutines.
+// Unlike the PoW functions of the Handler, the calculation can be canceled with a context.
+type Worker struct {
+	numWorkers int
+}
+
+// NewWorker creates a new PoW worker which uses numWorkers goroutines (0 = all CPU cores).
+func NewWorker(numWorkers int) *Worker {
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+	return &Worker{numWorkers: numWorkers}
+}
+
+// NumWorkers returns the amount of goroutines the worker uses.
+func (w *Worker) NumWorkers() int {
+	return w.numWorkers
+}
+
+// Mine calculates a nonce for the given transaction trytes, so that the transaction hash has at least mwm trailing zero trits.
+// It returns the error of the context if the context is done before a nonce was found.
+func (w *Worker) Mine(ctx context.Context, txTrytes trinary.Trytes, mwm int) (trinary.Trytes, error) {
+	if len(txTrytes) != consts.TransactionTrytesSize {
+		return "", ErrInvalidTransactionTrytes
+	}
+
+	if mwm < 0 || mwm > consts.HashTrinarySize {
+		return "", ErrInvalidMWM
+	}
+
+	txTrits, err := trinary.TrytesToTrits(txTrytes)
+	if err != nil {
+		return "", errors.Wrapf(ErrInvalidTransactionTrytes, "%v", err)
+	}
+
+	// absorb everything except the last hash sized chunk which contains the nonce
+	c := curl.NewCurlP81().(*curl.Curl)
+	if err := c.Absorb(txTrits[:consts.TransactionTrinarySize-consts.HashTrinarySize]); err != nil {
+		return "", err
+	}
+	copy(c.State, txTrits[consts.TransactionTrinarySize-consts.HashTrinarySize:])
+
+	// if any goroutine finds a nonce or the context is done, the cancel flag is set
+	// and thereby all other goroutines will halt.
+	var cancelled int32
+	nonceChan := make(chan trinary.Trytes, w.numWorkers)
+
+	var wg sync.WaitGroup
+	wg.Add(w.numWorkers)
+	for i := 0; i < w.numWorkers; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			lmid, hmid := pow.Para(c.State)
+			lmid[nonceOffset] = pow.PearlDiverMidStateLow0
+			hmid[nonceOffset] = pow.PearlDiverMidStateHigh0
+			lmid[nonceOffset+1] = pow.PearlDiverMidStateLow1
+			hmid[nonceOffset+1] = pow.PearlDiverMidStateHigh1
+			lmid[nonceOffset+2] = pow.PearlDiverMidStateLow2
+			hmid[nonceOffset+2] = pow.PearlDiverMidStateHigh2
+			lmid[nonceOffset+3] = pow.PearlDiverMidStateLow3
+			hmid[nonceOffset+3] = pow.PearlDiverMidStateHigh3
+
+			// every goroutine starts at a different nonce
+			incrN(i, lmid, hmid)
+
+			nonce, _, _ := pow.Loop(lmid, hmid, mwm, &cancelled, check, int(c.Rounds))
+			if len(nonce) > 0 {
+				nonceChan <- trinary.MustTritsToTrytes(nonce)
+			}
+		}(i)
+	}
+
+	var nonce trinary.Trytes
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+	case nonce = <-nonceChan:
+	}
+
+	// stop all goroutines and wait for them to finish
+	atomic.StoreInt32(&cancelled, 1)
+	wg.Wait()
+
+	return nonce, err
+}
+
+// incrN increments the nonce of the mid state n times.
+func incrN(n int, lmid *[curl.StateSize]uint64, hmid *[curl.StateSize]uint64) {
+	for j := 0; j < n; j++ {
+		var carry uint64 = 1
+
+		for i := nonceInitStart; i < nonceIncrementStart && carry != 0; i++ {
+			low := lmid[i]
+			high := hmid[i]
+			lmid[i] = high ^ low
+			hmid[i] = low
+			carry = high & (^low)
+		}
+	}
+}
+
+// check returns the index of the state which fulfills the minimum weight magnitude or -1 if none does.
+func check(l *[curl.StateSize]uint64, h *[curl.StateSize]uint64, mwm int) int {
+	nonceProbe := hBits
+	for i := consts.HashTrinarySize - mwm; i < consts.HashTrinarySize; i++ {
+		nonceProbe &= ^(l[i] ^ h[i])
+		if nonceProbe == 0 {
+			return -1
+		}
+	}
+
+	for i := uint(0); i < 64; i++ {
+		if (nonceProbe>>i)&1 == 1 {
+			return int(i)
+		}
+	}
+	return -1
+}
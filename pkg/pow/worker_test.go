@@ -0,0 +1,45 @@
+package pow
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/curl"
+	"github.com/iotaledger/iota.go/trinary"
+)
+
+func TestWorkerMine(t *testing.T) {
+	const mwm = 9
+
+	txTrytes := strings.Repeat("9", consts.TransactionTrytesSize-consts.NonceTrinarySize/3)
+
+	nonce, err := NewWorker(2).Mine(context.Background(), txTrytes+strings.Repeat("9", consts.NonceTrinarySize/3), mwm)
+	require.NoError(t, err)
+
+	txHashTrits, err := curl.HashTrits(trinary.MustTrytesToTrits(txTrytes + nonce))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, Score(txHashTrits), mwm)
+}
+
+func TestWorkerMineCanceled(t *testing.T) {
+	txTrytes := strings.Repeat("A", consts.TransactionTrytesSize)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// a hash with that many trailing zero trits is not found in time
+	_, err := NewWorker(1).Mine(ctx, txTrytes, 81)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestScore(t *testing.T) {
+	assert.Equal(t, 0, Score(trinary.Trits{0, 1}))
+	assert.Equal(t, 2, Score(trinary.Trits{1, -1, 0, 0}))
+	assert.Equal(t, 3, Score(trinary.Trits{0, 0, 0}))
+}
@@ -1,6 +1,9 @@
 package bqueue
 
 import (
+	"time"
+
+	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/peering"
 	"github.com/gohornet/hornet/pkg/peering/peer"
@@ -19,8 +22,13 @@ type Broadcast struct {
 	ExcludePeers map[string]struct{}
 }
 
-// Size defines the default size of the broadcast queue.
-const Size = 1000
+const (
+	// Size defines the default size of the broadcast queue.
+	Size = 1000
+
+	// the amount of buckets the deduplication window is split into.
+	dedupBuckets = 10
+)
 
 // Queue implements a queue which broadcasts its elements to all wanted peers.
 type Queue interface {
@@ -31,8 +39,13 @@ type Queue interface {
 }
 
 // New creates a new Queue.
-func New(manager *peering.Manager, reqQueue rqueue.Queue) Queue {
-	return &queue{c: make(chan *Broadcast, Size), manager: manager, reqQueue: reqQueue}
+// Transactions which were already broadcasted within the given deduplication window are not broadcasted again (0 = disable).
+func New(manager *peering.Manager, reqQueue rqueue.Queue, dedupWindow time.Duration) Queue {
+	q := &queue{c: make(chan *Broadcast, Size), manager: manager, reqQueue: reqQueue}
+	if dedupWindow > 0 {
+		q.dedup = newDedupCache(dedupWindow, dedupBuckets)
+	}
+	return q
 }
 
 // queue is a broadcast queue which sends the given messages to all peers.
@@ -40,6 +53,8 @@ type queue struct {
 	c        chan *Broadcast
 	manager  *peering.Manager
 	reqQueue rqueue.Queue
+	// only accessed by the Run loop, nil if the deduplication is disabled.
+	dedup *dedupCache
 }
 
 func (bc *queue) EnqueueForBroadcast(b *Broadcast) {
@@ -52,8 +67,15 @@ func (bc *queue) Run(shutdownSignal <-chan struct{}) {
 		case <-shutdownSignal:
 			return
 		case b := <-bc.c:
+			if bc.dedup != nil && len(b.RequestedTxHash) > 0 && bc.dedup.seen(string(b.RequestedTxHash), time.Now()) {
+				metrics.SharedServerMetrics.SuppressedBroadcasts.Inc()
+				continue
+			}
+
 			bc.manager.ForAllConnected(func(p *peer.Peer) bool {
 				if _, excluded := b.ExcludePeers[p.ID]; excluded {
+					// don't send the transaction back to the peers it was received from
+					metrics.SharedServerMetrics.ExcludedBroadcastPeers.Inc()
 					return true
 				}
 
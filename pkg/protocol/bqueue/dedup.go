@@ -0,0 +1,57 @@
+package bqueue
+
+import (
+	"time"
+)
+
+// dedupCache remembers the hashes of the transactions broadcasted within the last window.
+// the window is split into buckets of equal duration, which are dropped as a whole once
+// they left the window, so expired hashes don't have to be tracked one by one.
+// a hash is therefore remembered for at least the window minus the duration of one bucket.
+// it is not safe for concurrent use.
+type dedupCache struct {
+	bucketDuration time.Duration
+	buckets        []*dedupBucket
+}
+
+type dedupBucket struct {
+	epoch  int64
+	hashes map[string]struct{}
+}
+
+// newDedupCache creates a new dedupCache for the given window, which is split into the given amount of buckets.
+func newDedupCache(window time.Duration, bucketCount int) *dedupCache {
+	c := &dedupCache{
+		bucketDuration: window / time.Duration(bucketCount),
+		buckets:        make([]*dedupBucket, bucketCount),
+	}
+	for i := range c.buckets {
+		c.buckets[i] = &dedupBucket{epoch: -1}
+	}
+	return c
+}
+
+// seen returns whether the given hash was added within the window and adds it otherwise.
+func (c *dedupCache) seen(hash string, now time.Time) bool {
+	epoch := now.UnixNano() / int64(c.bucketDuration)
+	oldestEpoch := epoch - int64(len(c.buckets)) + 1
+
+	for _, bucket := range c.buckets {
+		if bucket.epoch < oldestEpoch {
+			continue
+		}
+		if _, exists := bucket.hashes[hash]; exists {
+			return true
+		}
+	}
+
+	bucket := c.buckets[epoch%int64(len(c.buckets))]
+	if bucket.epoch != epoch {
+		// the bucket belonged to an epoch which left the window
+		bucket.epoch = epoch
+		bucket.hashes = make(map[string]struct{})
+	}
+	bucket.hashes[hash] = struct{}{}
+
+	return false
+}
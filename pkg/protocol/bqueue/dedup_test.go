@@ -0,0 +1,29 @@
+package bqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupCache(t *testing.T) {
+	c := newDedupCache(10*time.Second, 10)
+	start := time.Unix(1000, 0)
+
+	assert.False(t, c.seen("A", start))
+	assert.True(t, c.seen("A", start))
+	assert.False(t, c.seen("B", start.Add(5*time.Second)))
+
+	// still within the window
+	assert.True(t, c.seen("A", start.Add(9*time.Second)))
+
+	// the bucket of A left the window, the one of B didn't
+	assert.False(t, c.seen("A", start.Add(10*time.Second)))
+	assert.True(t, c.seen("B", start.Add(10*time.Second)))
+
+	// the buckets are reused after the whole window passed
+	assert.False(t, c.seen("B", start.Add(30*time.Second)))
+	assert.False(t, c.seen("A", start.Add(30*time.Second)))
+	assert.True(t, c.seen("B", start.Add(30*time.Second)))
+}
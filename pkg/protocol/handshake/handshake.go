@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"io"
 	"time"
 
 	"github.com/willf/bitset"
 
+	"github.com/gohornet/hornet/pkg/networkid"
 	"github.com/gohornet/hornet/pkg/protocol/message"
 	"github.com/gohornet/hornet/pkg/protocol/tlv"
 )
@@ -31,6 +33,7 @@ var (
 	// - own used MWM (1 byte)
 	// - supported protocol versions. we need up to 32 bytes to represent 256 possible protocol
 	//   versions. only up to N bytes are used to communicate the highest supported version.
+	// - own network ID (8 bytes), older nodes don't send it
 	HandshakeMessageDefinition = &message.Definition{
 		ID:             MessageTypeHandshake,
 		MaxBytesLength: 92,
@@ -43,6 +46,8 @@ type HeaderState int32
 const (
 	// The amount of bytes used for the coo address sent in a handshake packet.
 	ByteEncodedCooAddressBytesLength = 49
+	// The amount of bytes used for the network ID sent in a handshake packet.
+	NetworkIDBytesLength = 8
 )
 
 var (
@@ -56,6 +61,8 @@ type Handshake struct {
 	ByteEncodedCooAddress []byte
 	MWM                   byte
 	SupportedVersions     []byte
+	// NetworkID is networkid.Unknown if the peer didn't send a network ID.
+	NetworkID networkid.ID
 }
 
 // SupportedVersion returns the highest supported protocol version.
@@ -86,7 +93,7 @@ func (hs Handshake) SupportedVersion(ownSupportedMessagesBitset *bitset.BitSet)
 }
 
 // NewHandshakeMessage creates a new handshake message.
-func NewHandshakeMessage(ownSupportedMessagesBitset *bitset.BitSet, ownSourcePort uint16, ownByteEncodedCooAddress []byte, ownUsedMWM byte) ([]byte, error) {
+func NewHandshakeMessage(ownSupportedMessagesBitset *bitset.BitSet, ownSourcePort uint16, ownByteEncodedCooAddress []byte, ownUsedMWM byte, ownNetworkID networkid.ID) ([]byte, error) {
 
 	maxLength := HandshakeMessageDefinition.MaxBytesLength
 
@@ -95,7 +102,7 @@ func NewHandshakeMessage(ownSupportedMessagesBitset *bitset.BitSet, ownSourcePor
 		return nil, err
 	}
 
-	payloadLengthBytes := maxLength - (maxLength - 60) + uint16(len(supportedMessageTypes))
+	payloadLengthBytes := maxLength - (maxLength - 60) + uint16(len(supportedMessageTypes)) + NetworkIDBytesLength
 	buf := bytes.NewBuffer(make([]byte, 0, tlv.HeaderMessageDefinition.MaxBytesLength+payloadLengthBytes))
 
 	if err := tlv.WriteHeader(buf, MessageTypeHandshake, payloadLengthBytes); err != nil {
@@ -122,6 +129,11 @@ func NewHandshakeMessage(ownSupportedMessagesBitset *bitset.BitSet, ownSourcePor
 		return nil, err
 	}
 
+	// the network ID is appended, so that older nodes, which ignore additional bytes, are still able to parse the handshake
+	if err := binary.Write(buf, binary.BigEndian, uint64(ownNetworkID)); err != nil {
+		return nil, err
+	}
+
 	return buf.Bytes(), nil
 }
 
@@ -156,5 +168,25 @@ func ParseHandshake(msg []byte) (*Handshake, error) {
 	}
 
 	hs := &Handshake{ServerSocketPort: serverSocketPort, SentTimestamp: sentTimestamp, ByteEncodedCooAddress: byteEncodedCooAddress, MWM: mwm, SupportedVersions: supportedVersions}
+
+	// the supported versions are a marshaled bitset, which starts with its length in bits (8 bytes),
+	// followed by its words (8 bytes each). the network ID follows the bitset if the peer sent it.
+	bitsetWords := (binary.BigEndian.Uint64(supportedVersions) + 63) / 64
+	if bitsetWords > uint64(r.Len())/8 {
+		return hs, nil
+	}
+
+	if _, err := r.Seek(int64(bitsetWords*8), io.SeekCurrent); err != nil {
+		return nil, err
+	}
+
+	if r.Len() >= NetworkIDBytesLength {
+		var networkID uint64
+		if err := binary.Read(r, binary.BigEndian, &networkID); err != nil {
+			return nil, err
+		}
+		hs.NetworkID = networkid.ID(networkID)
+	}
+
 	return hs, nil
 }
@@ -0,0 +1,38 @@
+package handshake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willf/bitset"
+
+	"github.com/gohornet/hornet/pkg/networkid"
+	"github.com/gohornet/hornet/pkg/protocol/tlv"
+)
+
+func TestHandshakeNetworkID(t *testing.T) {
+	cooAddress := make([]byte, ByteEncodedCooAddressBytesLength)
+	cooAddress[0] = 1
+	networkID := networkid.FromName("testnet")
+
+	msg, err := NewHandshakeMessage(bitset.From([]uint64{1}), 15600, cooAddress, 14, networkID)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(msg)-int(tlv.HeaderMessageDefinition.MaxBytesLength), int(HandshakeMessageDefinition.MaxBytesLength))
+
+	// the received message doesn't contain the TLV header
+	payload := msg[tlv.HeaderMessageDefinition.MaxBytesLength:]
+
+	hs, err := ParseHandshake(payload)
+	require.NoError(t, err)
+	assert.EqualValues(t, 15600, hs.ServerSocketPort)
+	assert.Equal(t, cooAddress, hs.ByteEncodedCooAddress)
+	assert.EqualValues(t, 14, hs.MWM)
+	assert.Equal(t, networkID, hs.NetworkID)
+
+	// older nodes don't send a network ID
+	hs, err = ParseHandshake(payload[:len(payload)-NetworkIDBytesLength])
+	require.NoError(t, err)
+	assert.EqualValues(t, 14, hs.MWM)
+	assert.Equal(t, networkid.Unknown, hs.NetworkID)
+}
@@ -23,17 +23,18 @@ func SendHeartbeat(p *peer.Peer, solidMsIndex milestone.Index, pruningMsIndex mi
 	}
 
 	heartbeatData, _ := sting.NewHeartbeatMessage(solidMsIndex, pruningMsIndex, latestMsIndex, connectedNeighbors, syncedNeighbors)
-	p.EnqueueForSending(heartbeatData)
+	p.EnqueueRequestForSending(heartbeatData)
 }
 
 // SendTransactionRequest sends a transaction request message to the given peer.
-func SendTransactionRequest(p *peer.Peer, requestedHash hornet.Hash) {
+// Returns false if the request could not be enqueued, e.g. because the request send queue of the peer is full.
+func SendTransactionRequest(p *peer.Peer, requestedHash hornet.Hash) bool {
 	if !p.Protocol.Supports(sting.FeatureSet) {
-		return
+		return false
 	}
 
 	txReqData, _ := sting.NewTransactionRequestMessage(requestedHash)
-	p.EnqueueForSending(txReqData)
+	return p.EnqueueRequestForSending(txReqData)
 }
 
 // SendMilestoneRequest sends a milestone request to the given peer.
@@ -43,7 +44,7 @@ func SendMilestoneRequest(p *peer.Peer, index milestone.Index) {
 	}
 
 	milestoneRequestData, _ := sting.NewMilestoneRequestMessage(index)
-	p.EnqueueForSending(milestoneRequestData)
+	p.EnqueueRequestForSending(milestoneRequestData)
 }
 
 // SendLatestMilestoneRequest sends a milestone request which requests the latest known milestone from the given peer.
@@ -0,0 +1,213 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/atomic"
+
+	"github.com/iotaledger/hive.go/lru_cache"
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/math"
+	"github.com/iotaledger/iota.go/transaction"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/peering/peer"
+)
+
+const (
+	// DuplicateFilterCapacity is the amount of transaction hashes the duplicate filter remembers.
+	DuplicateFilterCapacity = 100000
+)
+
+var (
+	// ErrInvalidTransaction is returned if a transaction is syntactically invalid.
+	ErrInvalidTransaction = errors.New("invalid transaction")
+	// ErrInvalidPoW is returned if the PoW of a transaction doesn't fulfill the minimum weight magnitude.
+	ErrInvalidPoW = errors.New("invalid PoW")
+	// ErrDuplicateTransaction is returned if a transaction was already seen recently.
+	ErrDuplicateTransaction = errors.New("duplicate transaction")
+)
+
+// Filter is a stage of the validation pipeline of the processor.
+// A transaction is only processed further if it passes all filters.
+//
+// Filters signal a stale transaction by returning an error wrapping ErrInvalidTimestamp,
+// an already known transaction by returning an error wrapping ErrDuplicateTransaction,
+// and a valid transaction the node is not willing to process by returning an error wrapping ErrFilteredByPolicy.
+// All other errors mark the transaction as invalid and punish the peers which sent it.
+type Filter interface {
+	// Name returns the name of the filter, which is used for its metrics.
+	Name() string
+	// Filter returns an error if the transaction must be dropped.
+	Filter(tx *hornet.Transaction) error
+}
+
+// FilterMetrics contains the metrics of a stage of the validation pipeline.
+type FilterMetrics struct {
+	Name    string `json:"name"`
+	Passed  uint32 `json:"passed"`
+	Dropped uint32 `json:"dropped"`
+}
+
+// filterStage is a filter of the pipeline together with its metrics.
+type filterStage struct {
+	filter  Filter
+	passed  atomic.Uint32
+	dropped atomic.Uint32
+}
+
+// Pipeline applies its filters in order to transactions and keeps track of the metrics of every stage.
+type Pipeline struct {
+	stages []*filterStage
+}
+
+// NewPipeline creates a new pipeline with the given filters.
+func NewPipeline(filters ...Filter) *Pipeline {
+	pipeline := &Pipeline{}
+	for _, filter := range filters {
+		pipeline.stages = append(pipeline.stages, &filterStage{filter: filter})
+	}
+	return pipeline
+}
+
+// Apply applies the filters to the given transaction, which was received from the given peer,
+// and returns the error of the first filter which dropped it.
+// The filters after the one which dropped the transaction are not applied.
+func (p *Pipeline) Apply(tx *hornet.Transaction, source *peer.Peer) error {
+	for _, stage := range p.stages {
+		if err := applyFilter(stage.filter, tx, source); err != nil {
+			stage.dropped.Inc()
+			return fmt.Errorf("%s: %w", stage.filter.Name(), err)
+		}
+		stage.passed.Inc()
+	}
+	return nil
+}
+
+// applyFilter passes the source peer to filters which take it into account.
+func applyFilter(filter Filter, tx *hornet.Transaction, source *peer.Peer) error {
+	if peerFilter, ok := filter.(PeerFilter); ok {
+		return peerFilter.FilterFromPeer(tx, source)
+	}
+	return filter.Filter(tx)
+}
+
+// Metrics returns the metrics of all stages of the pipeline in order.
+func (p *Pipeline) Metrics() []FilterMetrics {
+	result := make([]FilterMetrics, len(p.stages))
+	for i, stage := range p.stages {
+		result[i] = FilterMetrics{
+			Name:    stage.filter.Name(),
+			Passed:  stage.passed.Load(),
+			Dropped: stage.dropped.Load(),
+		}
+	}
+	return result
+}
+
+// SyntacticFilter drops value transactions with an invalid address or value.
+type SyntacticFilter struct{}
+
+// NewSyntacticFilter creates a new SyntacticFilter.
+func NewSyntacticFilter() *SyntacticFilter {
+	return &SyntacticFilter{}
+}
+
+func (f *SyntacticFilter) Name() string {
+	return "syntactic"
+}
+
+func (f *SyntacticFilter) Filter(tx *hornet.Transaction) error {
+	if tx.Tx.CurrentIndex > tx.Tx.LastIndex {
+		return fmt.Errorf("%w: current index %d above last index %d", ErrInvalidTransaction, tx.Tx.CurrentIndex, tx.Tx.LastIndex)
+	}
+
+	if tx.Tx.Value == 0 {
+		return nil
+	}
+
+	// last trit must be zero because of KERL
+	if trinary.MustTrytesToTrits(tx.Tx.Address[consts.HashTrytesSize-1 : consts.HashTrytesSize])[2] != 0 {
+		return fmt.Errorf("%w: %v", ErrInvalidTransaction, consts.ErrInvalidAddress)
+	}
+
+	if math.AbsInt64(tx.Tx.Value) > consts.TotalSupply {
+		return fmt.Errorf("%w: %v", ErrInvalidTransaction, consts.ErrInsufficientBalance)
+	}
+
+	return nil
+}
+
+// PoWFilter drops transactions which don't fulfill the minimum weight magnitude.
+type PoWFilter struct {
+	mwm uint64
+}
+
+// NewPoWFilter creates a new PoWFilter for the given minimum weight magnitude.
+func NewPoWFilter(mwm uint64) *PoWFilter {
+	return &PoWFilter{mwm: mwm}
+}
+
+func (f *PoWFilter) Name() string {
+	return "pow"
+}
+
+func (f *PoWFilter) Filter(tx *hornet.Transaction) error {
+	if !transaction.HasValidNonce(tx.Tx, f.mwm) {
+		return ErrInvalidPoW
+	}
+	return nil
+}
+
+// TimestampFilter drops transactions with a timestamp outside of the accepted range.
+type TimestampFilter struct{}
+
+// NewTimestampFilter creates a new TimestampFilter.
+func NewTimestampFilter() *TimestampFilter {
+	return &TimestampFilter{}
+}
+
+func (f *TimestampFilter) Name() string {
+	return "timestamp"
+}
+
+func (f *TimestampFilter) Filter(tx *hornet.Transaction) error {
+	if valid, _ := validateTimestamp(tx); !valid {
+		return ErrInvalidTimestamp
+	}
+	return nil
+}
+
+// DuplicateFilter drops transactions which were already seen recently.
+// It remembers the hashes of the most recently seen transactions exactly, so new transactions are never dropped by mistake.
+// Unlike the work unit cache, which only catches identical bytes within its short cache time,
+// it remembers a fixed amount of transactions regardless of their age.
+type DuplicateFilter struct {
+	seen *lru_cache.LRUCache
+}
+
+// NewDuplicateFilter creates a new DuplicateFilter which remembers the given amount of transaction hashes.
+func NewDuplicateFilter(capacity int) *DuplicateFilter {
+	return &DuplicateFilter{
+		seen: lru_cache.NewLRUCache(capacity),
+	}
+}
+
+func (f *DuplicateFilter) Name() string {
+	return "duplicate"
+}
+
+func (f *DuplicateFilter) Filter(tx *hornet.Transaction) error {
+	known := true
+	f.seen.ComputeIfAbsent(string(tx.GetTxHash()), func() interface{} {
+		known = false
+		return true
+	})
+
+	if known {
+		return ErrDuplicateTransaction
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package processor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/transaction"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+var errTestFiltered = errors.New("filtered")
+
+type testFilter struct {
+	drop bool
+}
+
+func (f *testFilter) Name() string {
+	return "test"
+}
+
+func (f *testFilter) Filter(_ *hornet.Transaction) error {
+	if f.drop {
+		return errTestFiltered
+	}
+	return nil
+}
+
+func testTransaction(hash string) *hornet.Transaction {
+	return hornet.NewTransactionFromTx(&transaction.Transaction{
+		Hash:    hash,
+		Address: consts.NullHashTrytes,
+	}, nil)
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	filter := NewDuplicateFilter(2)
+
+	txA := testTransaction("A" + consts.NullHashTrytes[1:])
+	txB := testTransaction("B" + consts.NullHashTrytes[1:])
+	txC := testTransaction("C" + consts.NullHashTrytes[1:])
+
+	assert.NoError(t, filter.Filter(txA))
+	assert.True(t, errors.Is(filter.Filter(txA), ErrDuplicateTransaction))
+	assert.NoError(t, filter.Filter(txB))
+
+	assert.True(t, errors.Is(filter.Filter(txB), ErrDuplicateTransaction))
+
+	// the least recently seen transaction is forgotten
+	assert.NoError(t, filter.Filter(txC))
+	assert.NoError(t, filter.Filter(txA))
+	assert.True(t, errors.Is(filter.Filter(txC), ErrDuplicateTransaction))
+}
+
+func TestDuplicateFilterNoFalsePositives(t *testing.T) {
+	filter := NewDuplicateFilter(10000)
+
+	for i := 0; i < 10000; i++ {
+		txHash := []byte(consts.NullHashTrytes)
+		copy(txHash, trinary.IntToTrytes(int64(i), 9))
+		assert.NoError(t, filter.Filter(testTransaction(string(txHash))))
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	dropFilter := &testFilter{}
+	pipeline := NewPipeline(NewSyntacticFilter(), dropFilter, NewDuplicateFilter(10))
+
+	tx := testTransaction(consts.NullHashTrytes)
+	assert.NoError(t, pipeline.Apply(tx, nil))
+
+	dropFilter.drop = true
+	assert.True(t, errors.Is(pipeline.Apply(tx, nil), errTestFiltered))
+
+	dropFilter.drop = false
+	assert.True(t, errors.Is(pipeline.Apply(tx, nil), ErrDuplicateTransaction))
+
+	assert.Equal(t, []FilterMetrics{
+		{Name: "syntactic", Passed: 3},
+		{Name: "test", Passed: 2, Dropped: 1},
+		{Name: "duplicate", Passed: 1, Dropped: 1},
+	}, pipeline.Metrics())
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/iotaledger/hive.go/batchhasher"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/objectstorage"
-	"github.com/iotaledger/hive.go/workerpool"
 	"github.com/iotaledger/iota.go/consts"
 	"github.com/iotaledger/iota.go/guards"
 	"github.com/iotaledger/iota.go/math"
@@ -26,6 +25,7 @@ import (
 	"github.com/gohornet/hornet/pkg/protocol/message"
 	"github.com/gohornet/hornet/pkg/protocol/rqueue"
 	"github.com/gohornet/hornet/pkg/protocol/sting"
+	"github.com/gohornet/hornet/pkg/workerpool"
 )
 
 const (
@@ -35,6 +35,8 @@ const (
 var (
 	workerCount         = batchhasher.CURLP81.GetBatchSize() * batchhasher.CURLP81.GetWorkerCount()
 	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	// ErrInvalidTransactionBytes is returned if the truncated transaction bytes have an invalid length.
+	ErrInvalidTransactionBytes = errors.New("invalid transaction bytes")
 )
 
 // New creates a new processor which parses messages.
@@ -48,6 +50,13 @@ func New(requestQueue rqueue.Queue, peerManager *peering.Manager, opts *Options)
 		},
 		opts: *opts,
 	}
+
+	// the duplicate filter comes last, so that only transactions which passed all other filters are remembered
+	filters := []Filter{NewSyntacticFilter(), NewPoWFilter(opts.ValidMWM), NewTimestampFilter()}
+	filters = append(filters, opts.Filters...)
+	filters = append(filters, NewDuplicateFilter(DuplicateFilterCapacity))
+	proc.filters = NewPipeline(filters...)
+
 	wuCacheOpts := opts.WorkUnitCacheOpts
 	proc.workUnits = objectstorage.New(
 		nil,
@@ -77,7 +86,13 @@ func New(requestQueue rqueue.Queue, peerManager *peering.Manager, opts *Options)
 		}
 
 		task.Return(nil)
-	}, workerpool.WorkerCount(workerCount), workerpool.QueueSize(WorkerQueueSize))
+	}, workerpool.Name("Processor"), workerpool.WorkerCount(workerCount), workerpool.QueueSize(WorkerQueueSize))
+
+	submissionQueueSize := opts.SubmissionQueueSize
+	if submissionQueueSize <= 0 {
+		submissionQueueSize = DefaultSubmissionQueueSize
+	}
+	proc.submissionWp = newSubmissionWorkerPool(proc, submissionQueueSize)
 
 	return proc
 }
@@ -103,8 +118,10 @@ type Processor struct {
 	Events       Events
 	pm           *peering.Manager
 	wp           *workerpool.WorkerPool
+	submissionWp *workerpool.WorkerPool
 	requestQueue rqueue.Queue
 	workUnits    *objectstorage.ObjectStorage
+	filters      *Pipeline
 	opts         Options
 }
 
@@ -112,17 +129,29 @@ type Processor struct {
 type Options struct {
 	ValidMWM          uint64
 	WorkUnitCacheOpts profile.CacheOpts
+	// Filters are additional stages of the validation pipeline, which are applied
+	// after the syntactic, PoW and timestamp filters and before the duplicate filter (e.g. the spam filters).
+	Filters []Filter
+	// SubmissionQueueSize is the amount of submissions of the API which may wait to be processed.
+	SubmissionQueueSize int
 }
 
 // Run runs the processor and blocks until the shutdown signal is triggered.
 func (proc *Processor) Run(shutdownSignal <-chan struct{}) {
 	proc.wp.Start()
+	proc.submissionWp.Start()
 	<-shutdownSignal
+	proc.submissionWp.StopAndWait()
 	proc.wp.StopAndWait()
 }
 
 // Process submits the given message to the processor for processing.
+// Milestone requests are processed with a higher priority than other messages.
 func (proc *Processor) Process(p *peer.Peer, msgType message.Type, data []byte) {
+	if msgType == sting.MessageTypeMilestoneRequest {
+		proc.wp.SubmitWithPriority(workerpool.PriorityHigh, p, msgType, data)
+		return
+	}
 	proc.wp.Submit(p, msgType, data)
 }
 
@@ -144,8 +173,7 @@ func (proc *Processor) ValidateTransactionTrytesAndEmit(txTrytes trinary.Trytes)
 		return err
 	}
 
-	hashTrits := batchhasher.CURLP81.Hash(txTrits)
-	tx.Hash = trinary.MustTritsToTrytes(hashTrits)
+	tx.Hash = compressed.TransactionHash(txTrits)
 
 	if tx.Value != 0 {
 		// last trit must be zero because of KERL
@@ -165,11 +193,41 @@ func (proc *Processor) ValidateTransactionTrytesAndEmit(txTrytes trinary.Trytes)
 	return proc.CompressAndEmit(tx, txTrits)
 }
 
+// ValidateTransactionBytesAndEmit validates the given truncated transaction bytes which were not received via gossip but
+// through some other mechanism. This function does not run within the Processor's worker pool.
+// Emits a TransactionProcessed and BroadcastTransaction event if the transaction was processed.
+// Returns the hash of the transaction.
+func (proc *Processor) ValidateTransactionBytesAndEmit(txBytesTruncated []byte) (trinary.Hash, error) {
+	if len(txBytesTruncated) < compressed.NonSigTxPartBytesLength || len(txBytesTruncated) > compressed.TransactionSize {
+		return "", ErrInvalidTransactionBytes
+	}
+
+	if len(txBytesTruncated) == compressed.TransactionSize {
+		// the transaction bytes were not truncated by the client
+		txBytesTruncated = compressed.TruncateTx(txBytesTruncated)
+	}
+
+	tx, err := compressed.TransactionFromCompressedBytes(txBytesTruncated)
+	if err != nil {
+		return "", err
+	}
+
+	if !transaction.HasValidNonce(tx, config.NodeConfig.GetUint64(config.CfgCoordinatorMWM)) {
+		return "", consts.ErrInvalidTransactionHash
+	}
+
+	return tx.Hash, proc.emit(hornet.NewTransactionFromTx(tx, txBytesTruncated))
+}
+
 // CompressAndEmit compresses the given transaction and emits TransactionProcessed and BroadcastTransaction events.
 // This function does not run within the Processor's worker pool.
 func (proc *Processor) CompressAndEmit(tx *transaction.Transaction, txTrits trinary.Trits) error {
 	txBytesTruncated := compressed.TruncateTx(trinary.MustTritsToBytes(txTrits))
-	hornetTx := hornet.NewTransactionFromTx(tx, txBytesTruncated)
+	return proc.emit(hornet.NewTransactionFromTx(tx, txBytesTruncated))
+}
+
+// emit validates the timestamp of the given transaction and emits TransactionProcessed and BroadcastTransaction events.
+func (proc *Processor) emit(hornetTx *hornet.Transaction) error {
 
 	if timeValid, _ := proc.ValidateTimestamp(hornetTx); !timeValid {
 		return ErrInvalidTimestamp
@@ -177,12 +235,25 @@ func (proc *Processor) CompressAndEmit(tx *transaction.Transaction, txTrits trin
 
 	proc.Events.TransactionProcessed.Trigger(hornetTx, (*rqueue.Request)(nil), (*peer.Peer)(nil))
 	proc.Events.BroadcastTransaction.Trigger(&bqueue.Broadcast{
-		TxData:          txBytesTruncated,
+		TxData:          hornetTx.RawBytes,
 		RequestedTxHash: hornetTx.GetTxHash(),
 	})
 	return nil
 }
 
+// Broadcast sends the given stored transaction to the neighbors again.
+func (proc *Processor) Broadcast(hornetTx *hornet.Transaction) {
+	proc.Events.BroadcastTransaction.Trigger(&bqueue.Broadcast{
+		TxData:          hornetTx.RawBytes,
+		RequestedTxHash: hornetTx.GetTxHash(),
+	})
+}
+
+// FilterMetrics returns the metrics of the stages of the validation pipeline.
+func (proc *Processor) FilterMetrics() []FilterMetrics {
+	return proc.filters.Metrics()
+}
+
 // WorkUnitSize returns the size of WorkUnits currently cached.
 func (proc *Processor) WorkUnitsSize() int {
 	return proc.workUnits.GetSize()
@@ -203,9 +274,10 @@ func (proc *Processor) processMilestoneRequest(p *peer.Peer, data []byte) {
 	msIndex, err := sting.ExtractRequestedMilestoneIndex(data)
 	if err != nil {
 		metrics.SharedServerMetrics.InvalidRequests.Inc()
+		p.Metrics.InvalidRequests.Inc()
 
-		// drop the connection to the peer
-		proc.pm.Remove(p.ID)
+		// drops the connection to the peer if it misbehaves too often
+		proc.pm.Misbehaved(p, peering.PenaltyInvalidRequest)
 		return
 	}
 
@@ -270,9 +342,10 @@ func (proc *Processor) processWorkUnit(wu *WorkUnit, p *peer.Peer) {
 		wu.processingLock.Unlock()
 
 		metrics.SharedServerMetrics.InvalidTransactions.Inc()
+		p.Metrics.InvalidTransactions.Inc()
 
-		// drop the connection to the peer
-		proc.pm.Remove(p.ID)
+		// drops the connection to the peer if it misbehaves too often
+		proc.pm.Misbehaved(p, peering.PenaltyInvalidTransaction)
 
 		return
 	case wu.Is(Hashed):
@@ -280,6 +353,8 @@ func (proc *Processor) processWorkUnit(wu *WorkUnit, p *peer.Peer) {
 
 		// emit an event to say that a transaction was fully processed
 		if request := proc.requestQueue.Received(wu.tx.GetTxHash()); request != nil {
+			p.Metrics.ReceivedRequestedTransactions.Inc()
+			proc.answeredRequest(p, request.Hash)
 			proc.Events.TransactionProcessed.Trigger(wu.tx, request, p)
 			wu.wasStale = false
 			return
@@ -307,7 +382,7 @@ func (proc *Processor) processWorkUnit(wu *WorkUnit, p *peer.Peer) {
 	tx, err := compressed.TransactionFromCompressedBytes(wu.receivedTxBytes)
 	if err != nil {
 		wu.UpdateState(Invalid)
-		wu.punish()
+		wu.punish(proc.pm)
 		return
 	}
 
@@ -316,15 +391,28 @@ func (proc *Processor) processWorkUnit(wu *WorkUnit, p *peer.Peer) {
 
 	// mark the transaction as received
 	request := proc.requestQueue.Received(hornetTx.GetTxHash())
+	if request != nil {
+		proc.answeredRequest(p, request.Hash)
+	}
 
-	// validate minimum weight magnitude requirement
-	if request == nil && !transaction.HasValidNonce(tx, proc.opts.ValidMWM) {
+	// requested transactions are needed for the solidification and bypass the validation pipeline
+	var filterErr error
+	if request == nil {
+		filterErr = proc.filters.Apply(hornetTx, p)
+	}
+
+	if filterErr != nil && !errors.Is(filterErr, ErrInvalidTimestamp) && !errors.Is(filterErr, ErrDuplicateTransaction) && !errors.Is(filterErr, ErrFilteredByPolicy) {
 		wu.UpdateState(Invalid)
-		wu.punish()
+		wu.punish(proc.pm)
 		return
 	}
 
-	timestampValid, broadcast := proc.ValidateTimestamp(hornetTx)
+	// the transaction is only dropped for the peer which exceeded its quota.
+	// the WorkUnit is not marked as hashed, so a copy of the transaction sent by another peer is processed again.
+	if errors.Is(filterErr, ErrPeerQuotaExceeded) {
+		wu.UpdateState(0)
+		return
+	}
 
 	wu.dataLock.Lock()
 	wu.receivedTxHash = hornetTx.GetTxHash()
@@ -334,12 +422,27 @@ func (proc *Processor) processWorkUnit(wu *WorkUnit, p *peer.Peer) {
 	wu.UpdateState(Hashed)
 
 	// mark the WorkUnit as containing a stale transaction but
-	if request == nil && !timestampValid {
+	if errors.Is(filterErr, ErrInvalidTimestamp) {
 		wu.wasStale = true
 		wu.stale()
 		return
 	}
 
+	// the transaction was already processed recently
+	if errors.Is(filterErr, ErrDuplicateTransaction) {
+		metrics.SharedServerMetrics.KnownTransactions.Inc()
+		p.Metrics.KnownTransactions.Inc()
+		return
+	}
+
+	// the transaction is valid, but the node is not willing to process it.
+	// it is neither stored nor broadcasted, unless it gets requested later on.
+	if errors.Is(filterErr, ErrFilteredByPolicy) {
+		return
+	}
+
+	_, broadcast := proc.ValidateTimestamp(hornetTx)
+
 	// check the existence of the transaction before broadcasting it
 	containsTx := tangle.ContainsTransaction(hornetTx.GetTxHash())
 
@@ -355,10 +458,28 @@ func (proc *Processor) processWorkUnit(wu *WorkUnit, p *peer.Peer) {
 	}
 }
 
+// measures the latency of the given peer answering the request for the given transaction.
+// the request is forgotten by all other peers, since they are not expected to answer it anymore.
+func (proc *Processor) answeredRequest(p *peer.Peer, hash hornet.Hash) {
+	now := time.Now()
+	proc.pm.ForAllConnected(func(connectedPeer *peer.Peer) bool {
+		if connectedPeer == p {
+			connectedPeer.RequestLatency.Answered(hash, now)
+			return true
+		}
+		connectedPeer.RequestLatency.Forget(hash)
+		return true
+	})
+}
+
 // checks whether the given transaction's timestamp is valid.
 // the timestamp is automatically valid if the transaction is a solid entry point.
 // the timestamp should be in the range of +/- 10 minutes to current time.
 func (proc *Processor) ValidateTimestamp(hornetTx *hornet.Transaction) (valid, broadcast bool) {
+	return validateTimestamp(hornetTx)
+}
+
+func validateTimestamp(hornetTx *hornet.Transaction) (valid, broadcast bool) {
 	snapshotTimestamp := tangle.GetSnapshotInfo().Timestamp
 	txTimestamp := hornetTx.GetTimestamp()
 
@@ -0,0 +1,59 @@
+package processor
+
+import (
+	"testing"
+
+	_ "golang.org/x/crypto/blake2b"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/compressed"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/peering/peer"
+	"github.com/gohornet/hornet/pkg/profile"
+	"github.com/gohornet/hornet/pkg/protocol/rqueue"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/gohornet/hornet/pkg/testsuite/utils"
+)
+
+// testTransactionBytes returns the truncated bytes of a new zero value transaction with the given tag.
+func testTransactionBytes(t *testing.T, tag trinary.Trytes) []byte {
+	txTrytes := utils.ZeroValueTx(t, tag)[0]
+	return compressed.TruncateTx(trinary.MustTritsToBytes(trinary.MustTrytesToTrits(txTrytes)))
+}
+
+func TestProcessTransactionPeerQuota(t *testing.T) {
+	te := testsuite.SetupTestEnvironment(t, make(map[string]uint64), 0, false)
+	defer te.CleanupTestEnvironment(true)
+
+	proc := New(rqueue.New(), nil, &Options{
+		WorkUnitCacheOpts: profile.Profile1GB.Caches.IncomingTransactionFilter,
+		Filters:           NewSpamFilters(&SpamFilterOpts{PeerTransactionsPerMinute: 1}),
+	})
+
+	processedFrom := make(map[string][]string)
+	proc.Events.TransactionProcessed.Attach(events.NewClosure(func(tx *hornet.Transaction, _ *rqueue.Request, p *peer.Peer) {
+		processedFrom[tx.Tx.Tag] = append(processedFrom[tx.Tx.Tag], p.ID)
+	}))
+
+	peerA, peerB := &peer.Peer{ID: "A"}, &peer.Peer{ID: "B"}
+	firstTx, secondTx := testTransactionBytes(t, "FIRST"), testTransactionBytes(t, "SECOND")
+
+	// peer A uses up its quota with the first transaction
+	proc.processTransaction(peerA, firstTx)
+	proc.processTransaction(peerA, secondTx)
+
+	// the second transaction is only dropped for peer A, peer B is within its quota
+	proc.processTransaction(peerB, secondTx)
+
+	// a copy which was already processed is not processed again
+	proc.processTransaction(peerB, firstTx)
+
+	require.Equal(t, map[string][]string{
+		trinary.MustPad("FIRST", 27):  {"A"},
+		trinary.MustPad("SECOND", 27): {"B"},
+	}, processedFrom)
+}
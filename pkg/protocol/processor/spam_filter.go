@@ -0,0 +1,229 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iotaledger/iota.go/transaction"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/peering/peer"
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+const (
+	// the interval in which idle quotas of disconnected peers are removed.
+	peerQuotaCleanupInterval = 5 * time.Minute
+)
+
+var (
+	// ErrFilteredByPolicy is returned if a valid transaction is dropped because of the spam filter policies of the node.
+	// Unlike invalid transactions, the peers which sent it are not punished.
+	ErrFilteredByPolicy = errors.New("filtered by policy")
+	// ErrPeerQuotaExceeded is returned if a peer exceeded its quota of new transactions.
+	// It wraps ErrFilteredByPolicy, but the transaction is only dropped for this peer and may be processed if another peer sends it.
+	ErrPeerQuotaExceeded = fmt.Errorf("%w: peer quota exceeded", ErrFilteredByPolicy)
+)
+
+// PeerFilter is a Filter which also takes the peer the transaction was received from into account.
+type PeerFilter interface {
+	Filter
+	// FilterFromPeer returns an error if the transaction received from the given peer must be dropped.
+	FilterFromPeer(tx *hornet.Transaction, p *peer.Peer) error
+}
+
+// SpamFilterOpts defines the policies of the spam filters. Zero values disable the corresponding filter.
+type SpamFilterOpts struct {
+	// MinPoWScore is the minimum amount of trailing zero trits of the transaction hashes.
+	MinPoWScore uint64
+	// MaxTransactionBytes is the maximum size of the truncated transaction bytes.
+	MaxTransactionBytes int
+	// BlockedTags are the tag prefixes of the transactions which are dropped.
+	BlockedTags []string
+	// PeerTransactionsPerMinute is the maximum amount of new transactions accepted from a single peer per minute.
+	PeerTransactionsPerMinute int
+	// Exempt returns whether the given transaction bypasses all policies (e.g. while the node is syncing).
+	// Optional, no transaction is exempt if it is nil.
+	Exempt func(tx *hornet.Transaction) bool
+	// ExemptFromMaxSize returns whether the given transaction bypasses only the size limit (e.g. transactions to the
+	// coordinator address, since the signature message fragments of milestones are full).
+	// Optional, no transaction is exempt if it is nil.
+	ExemptFromMaxSize func(tx *hornet.Transaction) bool
+}
+
+// NewSpamFilters creates the enabled spam filters for the given policies.
+func NewSpamFilters(opts *SpamFilterOpts) []Filter {
+	var filters []Filter
+
+	if opts.MinPoWScore > 0 {
+		filters = append(filters, withExemptions(NewMinPoWScoreFilter(opts.MinPoWScore), opts.Exempt))
+	}
+	if opts.MaxTransactionBytes > 0 {
+		filters = append(filters, withExemptions(NewMaxSizeFilter(opts.MaxTransactionBytes), opts.Exempt, opts.ExemptFromMaxSize))
+	}
+	if len(opts.BlockedTags) > 0 {
+		filters = append(filters, withExemptions(NewTagBlocklistFilter(opts.BlockedTags), opts.Exempt))
+	}
+	if opts.PeerTransactionsPerMinute > 0 {
+		filters = append(filters, withExemptions(NewPeerQuotaFilter(opts.PeerTransactionsPerMinute), opts.Exempt))
+	}
+
+	return filters
+}
+
+// withExemptions wraps the given filter, so that it is not applied to transactions for which one of the
+// given exemptions returns true. Nil exemptions are ignored.
+func withExemptions(filter Filter, exemptions ...func(tx *hornet.Transaction) bool) Filter {
+	var active []func(tx *hornet.Transaction) bool
+	for _, exempt := range exemptions {
+		if exempt != nil {
+			active = append(active, exempt)
+		}
+	}
+
+	if len(active) == 0 {
+		return filter
+	}
+
+	return &exemptFilter{filter: filter, exempt: func(tx *hornet.Transaction) bool {
+		for _, exempt := range active {
+			if exempt(tx) {
+				return true
+			}
+		}
+		return false
+	}}
+}
+
+// exemptFilter applies a policy filter only to transactions which are not exempt from the policy.
+type exemptFilter struct {
+	filter Filter
+	exempt func(tx *hornet.Transaction) bool
+}
+
+func (f *exemptFilter) Name() string {
+	return f.filter.Name()
+}
+
+func (f *exemptFilter) Filter(tx *hornet.Transaction) error {
+	if f.exempt(tx) {
+		return nil
+	}
+	return f.filter.Filter(tx)
+}
+
+func (f *exemptFilter) FilterFromPeer(tx *hornet.Transaction, p *peer.Peer) error {
+	if f.exempt(tx) {
+		return nil
+	}
+	return applyFilter(f.filter, tx, p)
+}
+
+// MinPoWScoreFilter drops transactions whose PoW is below the minimum score of the node,
+// which may be higher than the minimum weight magnitude of the network.
+type MinPoWScoreFilter struct {
+	minScore uint64
+}
+
+// NewMinPoWScoreFilter creates a new MinPoWScoreFilter for the given minimum score.
+func NewMinPoWScoreFilter(minScore uint64) *MinPoWScoreFilter {
+	return &MinPoWScoreFilter{minScore: minScore}
+}
+
+func (f *MinPoWScoreFilter) Name() string {
+	return "minPoWScore"
+}
+
+func (f *MinPoWScoreFilter) Filter(tx *hornet.Transaction) error {
+	if !transaction.HasValidNonce(tx.Tx, f.minScore) {
+		return fmt.Errorf("%w: PoW score below %d", ErrFilteredByPolicy, f.minScore)
+	}
+	return nil
+}
+
+// MaxSizeFilter drops transactions whose truncated bytes exceed the maximum size,
+// which limits the amount of data in the signature message fragments.
+type MaxSizeFilter struct {
+	maxBytes int
+}
+
+// NewMaxSizeFilter creates a new MaxSizeFilter for the given maximum size.
+func NewMaxSizeFilter(maxBytes int) *MaxSizeFilter {
+	return &MaxSizeFilter{maxBytes: maxBytes}
+}
+
+func (f *MaxSizeFilter) Name() string {
+	return "maxSize"
+}
+
+func (f *MaxSizeFilter) Filter(tx *hornet.Transaction) error {
+	if len(tx.RawBytes) > f.maxBytes {
+		return fmt.Errorf("%w: %d bytes exceed the maximum of %d bytes", ErrFilteredByPolicy, len(tx.RawBytes), f.maxBytes)
+	}
+	return nil
+}
+
+// TagBlocklistFilter drops transactions whose tag starts with one of the blocked prefixes.
+type TagBlocklistFilter struct {
+	blockedTags []string
+}
+
+// NewTagBlocklistFilter creates a new TagBlocklistFilter for the given tag prefixes.
+// Trailing nines of the prefixes are ignored, since tags are padded with nines.
+func NewTagBlocklistFilter(blockedTags []string) *TagBlocklistFilter {
+	filter := &TagBlocklistFilter{}
+	for _, tag := range blockedTags {
+		if tag = strings.TrimRight(tag, "9"); tag != "" {
+			filter.blockedTags = append(filter.blockedTags, tag)
+		}
+	}
+	return filter
+}
+
+func (f *TagBlocklistFilter) Name() string {
+	return "tagBlocklist"
+}
+
+func (f *TagBlocklistFilter) Filter(tx *hornet.Transaction) error {
+	for _, blockedTag := range f.blockedTags {
+		if strings.HasPrefix(tx.Tx.Tag, blockedTag) {
+			return fmt.Errorf("%w: tag %s is blocked", ErrFilteredByPolicy, tx.Tx.Tag)
+		}
+	}
+	return nil
+}
+
+// PeerQuotaFilter drops the transactions of peers which exceeded their quota of new transactions per minute.
+// It should come after the other filters, so that only transactions which would have been processed count towards the quota.
+type PeerQuotaFilter struct {
+	quotas *utils.KeyedRateLimiter
+}
+
+// NewPeerQuotaFilter creates a new PeerQuotaFilter which accepts up to transactionsPerMinute transactions per peer.
+func NewPeerQuotaFilter(transactionsPerMinute int) *PeerQuotaFilter {
+	return &PeerQuotaFilter{
+		quotas: utils.NewKeyedRateLimiter(float64(transactionsPerMinute)/60, transactionsPerMinute, peerQuotaCleanupInterval),
+	}
+}
+
+func (f *PeerQuotaFilter) Name() string {
+	return "peerQuota"
+}
+
+// Filter doesn't drop transactions without a known source peer.
+func (f *PeerQuotaFilter) Filter(_ *hornet.Transaction) error {
+	return nil
+}
+
+func (f *PeerQuotaFilter) FilterFromPeer(tx *hornet.Transaction, p *peer.Peer) error {
+	if p == nil {
+		return f.Filter(tx)
+	}
+
+	if !f.quotas.Allow(p.ID) {
+		return fmt.Errorf("%w: %s", ErrPeerQuotaExceeded, p.ID)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package processor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/transaction"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/peering/peer"
+)
+
+func testTaggedTransaction(tag string) *hornet.Transaction {
+	return hornet.NewTransactionFromTx(&transaction.Transaction{
+		Hash:    consts.NullHashTrytes,
+		Address: consts.NullHashTrytes,
+		Tag:     tag,
+	}, nil)
+}
+
+func TestNewSpamFilters(t *testing.T) {
+	assert.Empty(t, NewSpamFilters(&SpamFilterOpts{}))
+
+	filters := NewSpamFilters(&SpamFilterOpts{MaxTransactionBytes: 500, PeerTransactionsPerMinute: 60})
+	assert.Len(t, filters, 2)
+	assert.Equal(t, "maxSize", filters[0].Name())
+	assert.Equal(t, "peerQuota", filters[1].Name())
+}
+
+func TestMaxSizeFilter(t *testing.T) {
+	filter := NewMaxSizeFilter(3)
+
+	assert.NoError(t, filter.Filter(hornet.NewTransactionFromTx(&transaction.Transaction{}, []byte{1, 2, 3})))
+	assert.True(t, errors.Is(filter.Filter(hornet.NewTransactionFromTx(&transaction.Transaction{}, []byte{1, 2, 3, 4})), ErrFilteredByPolicy))
+}
+
+func TestTagBlocklistFilter(t *testing.T) {
+	filter := NewTagBlocklistFilter([]string{"SPAM99999", "999"})
+
+	assert.True(t, errors.Is(filter.Filter(testTaggedTransaction("SPAMMER999999999999999999999")), ErrFilteredByPolicy))
+	assert.NoError(t, filter.Filter(testTaggedTransaction("HORNET99999999999999999999999")))
+	assert.NoError(t, filter.Filter(testTaggedTransaction("999999999999999999999999999")))
+}
+
+func TestPeerQuotaFilter(t *testing.T) {
+	filter := NewPeerQuotaFilter(2)
+	peerA, peerB := &peer.Peer{ID: "A"}, &peer.Peer{ID: "B"}
+	tx := testTransaction(consts.NullHashTrytes)
+
+	assert.NoError(t, filter.FilterFromPeer(tx, peerA))
+	assert.NoError(t, filter.FilterFromPeer(tx, peerA))
+	assert.True(t, errors.Is(filter.FilterFromPeer(tx, peerA), ErrFilteredByPolicy))
+
+	// the quotas are tracked per peer, transactions without a source peer are not limited
+	assert.NoError(t, filter.FilterFromPeer(tx, peerB))
+	assert.NoError(t, filter.FilterFromPeer(tx, nil))
+
+	// the pipeline passes the source peer to the filter
+	pipeline := NewPipeline(filter)
+	assert.True(t, errors.Is(pipeline.Apply(tx, peerA), ErrFilteredByPolicy))
+	assert.NoError(t, pipeline.Apply(tx, nil))
+}
+
+func TestSpamFiltersExempt(t *testing.T) {
+	syncing := true
+	filters := NewSpamFilters(&SpamFilterOpts{
+		MinPoWScore:               81,
+		MaxTransactionBytes:       1,
+		BlockedTags:               []string{"SPAM"},
+		PeerTransactionsPerMinute: 1,
+		Exempt: func(_ *hornet.Transaction) bool {
+			return syncing
+		},
+	})
+	pipeline := NewPipeline(filters...)
+	p := &peer.Peer{ID: "A"}
+
+	tx := hornet.NewTransactionFromTx(&transaction.Transaction{
+		Hash:    consts.NullHashTrytes,
+		Address: consts.NullHashTrytes,
+		Tag:     "SPAM99999999999999999999999",
+	}, make([]byte, 100))
+
+	// all policies are bypassed while the node is syncing
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, pipeline.Apply(tx, p))
+	}
+
+	syncing = false
+	assert.True(t, errors.Is(pipeline.Apply(tx, p), ErrFilteredByPolicy))
+}
+
+func TestSpamFiltersExemptFromMaxSize(t *testing.T) {
+	cooAddress := "COO" + consts.NullHashTrytes[3:]
+	filters := NewSpamFilters(&SpamFilterOpts{
+		MaxTransactionBytes:       1,
+		BlockedTags:               []string{"SPAM"},
+		PeerTransactionsPerMinute: 2,
+		ExemptFromMaxSize: func(tx *hornet.Transaction) bool {
+			return tx.Tx.Address == cooAddress
+		},
+	})
+	pipeline := NewPipeline(filters...)
+	p := &peer.Peer{ID: "A"}
+
+	newTx := func(address string, tag string) *hornet.Transaction {
+		return hornet.NewTransactionFromTx(&transaction.Transaction{
+			Hash:    consts.NullHashTrytes,
+			Address: address,
+			Tag:     tag,
+		}, make([]byte, 100))
+	}
+
+	// transactions to the coordinator address only bypass the size limit
+	assert.NoError(t, pipeline.Apply(newTx(cooAddress, "MILESTONE99999999999999999"), p))
+	assert.True(t, errors.Is(pipeline.Apply(newTx(cooAddress, "SPAM99999999999999999999999"), p), ErrFilteredByPolicy))
+	assert.True(t, errors.Is(pipeline.Apply(newTx(consts.NullHashTrytes, "MILESTONE99999999999999999"), p), ErrFilteredByPolicy))
+
+	// the quota still applies
+	assert.NoError(t, pipeline.Apply(newTx(cooAddress, "MILESTONE99999999999999999"), p))
+	assert.True(t, errors.Is(pipeline.Apply(newTx(cooAddress, "MILESTONE99999999999999999"), p), ErrPeerQuotaExceeded))
+}
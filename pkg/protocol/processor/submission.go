@@ -0,0 +1,113 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/workerpool"
+)
+
+const (
+	// DefaultSubmissionQueueSize is the default amount of submissions which may wait to be processed.
+	DefaultSubmissionQueueSize = 100
+)
+
+var (
+	// ErrSubmissionQueueFull is returned if a submission is rejected because too many submissions wait to be processed.
+	// The client should retry the submission later.
+	ErrSubmissionQueueFull = errors.New("submission queue is full")
+
+	// the submissions only use half of the CPU cores, so that they can't starve the processing of gossip.
+	submissionWorkerCount = (runtime.NumCPU() + 1) / 2
+)
+
+// InvalidSubmissionError is returned if a transaction of a submission is invalid.
+// The transactions before the invalid one were already processed.
+type InvalidSubmissionError struct {
+	// Index is the index of the invalid transaction in the submission.
+	Index int
+	Err   error
+}
+
+func (e *InvalidSubmissionError) Error() string {
+	return fmt.Sprintf("invalid transaction at index %d: %v", e.Index, e.Err)
+}
+
+func (e *InvalidSubmissionError) Unwrap() error {
+	return e.Err
+}
+
+// the result of a processed submission.
+type submissionResult struct {
+	hashes []trinary.Hash
+	err    error
+}
+
+// creates the worker pool which processes the submissions of the API.
+func newSubmissionWorkerPool(proc *Processor, queueSize int) *workerpool.WorkerPool {
+	return workerpool.New(func(task workerpool.Task) {
+		switch txs := task.Param(0).(type) {
+		case []trinary.Trytes:
+			task.Return(proc.processSubmittedTrytes(txs))
+		case [][]byte:
+			task.Return(proc.processSubmittedBytes(txs))
+		}
+	}, workerpool.Name("Submission"), workerpool.WorkerCount(submissionWorkerCount), workerpool.QueueSize(queueSize), workerpool.FlushTasksAtShutdown(true))
+}
+
+// SubmitTransactionTrytes queues the given transaction trytes, which were not received via gossip,
+// and blocks until they were validated and emitted in order.
+// Returns ErrSubmissionQueueFull if the submission queue is full and an InvalidSubmissionError
+// if one of the transactions is invalid.
+func (proc *Processor) SubmitTransactionTrytes(txsTrytes []trinary.Trytes) error {
+	result, err := proc.submit(txsTrytes)
+	if err != nil {
+		return err
+	}
+	return result.err
+}
+
+// SubmitTransactionBytes queues the given truncated transaction bytes, which were not received via gossip,
+// and blocks until they were validated and emitted in order. Returns the hashes of the transactions.
+// Returns ErrSubmissionQueueFull if the submission queue is full and an InvalidSubmissionError
+// if one of the transactions is invalid.
+func (proc *Processor) SubmitTransactionBytes(txsBytesTruncated [][]byte) ([]trinary.Hash, error) {
+	result, err := proc.submit(txsBytesTruncated)
+	if err != nil {
+		return nil, err
+	}
+	return result.hashes, result.err
+}
+
+// submits the given transactions to the submission queue and waits for the result.
+func (proc *Processor) submit(txs interface{}) (*submissionResult, error) {
+	resultChan, added := proc.submissionWp.TrySubmit(txs)
+	if !added {
+		return nil, ErrSubmissionQueueFull
+	}
+	return (<-resultChan).(*submissionResult), nil
+}
+
+func (proc *Processor) processSubmittedTrytes(txsTrytes []trinary.Trytes) *submissionResult {
+	for i, txTrytes := range txsTrytes {
+		if err := proc.ValidateTransactionTrytesAndEmit(txTrytes); err != nil {
+			return &submissionResult{err: &InvalidSubmissionError{Index: i, Err: err}}
+		}
+	}
+	return &submissionResult{}
+}
+
+func (proc *Processor) processSubmittedBytes(txsBytesTruncated [][]byte) *submissionResult {
+	hashes := make([]trinary.Hash, len(txsBytesTruncated))
+	for i, txBytesTruncated := range txsBytesTruncated {
+		hash, err := proc.ValidateTransactionBytesAndEmit(txBytesTruncated)
+		if err != nil {
+			return &submissionResult{err: &InvalidSubmissionError{Index: i, Err: err}}
+		}
+		hashes[i] = hash
+	}
+	return &submissionResult{hashes: hashes}
+}
@@ -0,0 +1,40 @@
+package processor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/trinary"
+)
+
+func TestSubmissionQueue(t *testing.T) {
+	proc := &Processor{}
+	proc.submissionWp = newSubmissionWorkerPool(proc, 1)
+
+	// the pool is not started yet, so the first submission stays in the queue
+	firstResult := make(chan error, 1)
+	go func() {
+		firstResult <- proc.SubmitTransactionTrytes([]trinary.Trytes{"INVALID"})
+	}()
+	require.Eventually(t, func() bool { return proc.submissionWp.GetPendingQueueSize() == 1 }, time.Second, time.Millisecond)
+
+	err := proc.SubmitTransactionTrytes([]trinary.Trytes{"INVALID"})
+	assert.True(t, errors.Is(err, ErrSubmissionQueueFull))
+
+	proc.submissionWp.Start()
+	defer proc.submissionWp.StopAndWait()
+
+	err = <-firstResult
+	var invalidErr *InvalidSubmissionError
+	require.True(t, errors.As(err, &invalidErr))
+	assert.Equal(t, 0, invalidErr.Index)
+	assert.True(t, errors.Is(err, consts.ErrInvalidTransactionTrytes))
+
+	_, err = proc.SubmitTransactionBytes([][]byte{{1, 2, 3}})
+	assert.True(t, errors.Is(err, ErrInvalidTransactionBytes))
+}
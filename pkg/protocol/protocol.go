@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -13,6 +14,7 @@ import (
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/syncutils"
 
+	"github.com/gohornet/hornet/pkg/networkid"
 	"github.com/gohornet/hornet/pkg/protocol/handshake"
 	"github.com/gohornet/hornet/pkg/protocol/message"
 	"github.com/gohornet/hornet/pkg/protocol/sting"
@@ -37,16 +39,24 @@ var (
 	SupportedFeatureSets = bitset.From([]uint64{sting.FeatureSet})
 )
 
+var (
+	// ErrMessageBeforeHandshake is returned if a peer sends a message other than the handshake before the handshake was completed.
+	// The handshake verifies that the peer participates in the same network, so no other data of the peer is processed before.
+	ErrMessageBeforeHandshake = errors.New("message received before the handshake was completed")
+)
+
 var (
 	ownByteEncodedCooAddress []byte
 	ownMWM                   uint64
+	ownNetworkID             networkid.ID
 	ownSrvSocketPort         uint16
 )
 
 // Init initializes the protocol package with the given handshake information.
-func Init(cooAddressBytes []byte, mwm int, gossipBindAddr string) error {
+func Init(cooAddressBytes []byte, mwm int, networkID networkid.ID, gossipBindAddr string) error {
 	ownByteEncodedCooAddress = cooAddressBytes
 	ownMWM = uint64(mwm)
+	ownNetworkID = networkID
 	_, portStr, err := net.SplitHostPort(gossipBindAddr)
 	if err != nil {
 		return fmt.Errorf("gossip bind address is invalid: %w", err)
@@ -149,18 +159,16 @@ func (p *Protocol) SupportedFeatureSets() []string {
 // the connection.
 func (p *Protocol) Start() {
 	// kick off protocol by sending a handshake message
-	handshakeMsg, err := handshake.NewHandshakeMessage(SupportedFeatureSets, ownSrvSocketPort, ownByteEncodedCooAddress, byte(ownMWM))
+	handshakeMsg, err := handshake.NewHandshakeMessage(SupportedFeatureSets, ownSrvSocketPort, ownByteEncodedCooAddress, byte(ownMWM), ownNetworkID)
 	if err != nil {
-		fmt.Println("creating handshake message error: ", err)
 		_ = p.conn.Close()
-		p.Events.Error.Trigger(err)
+		p.Events.Error.Trigger(fmt.Errorf("creating handshake message failed: %w", err))
 		return
 	}
 
 	if err := p.Send(handshakeMsg); err != nil {
-		fmt.Println("sending handshake message error: ", err)
 		_ = p.conn.Close()
-		p.Events.Error.Trigger(err)
+		p.Events.Error.Trigger(fmt.Errorf("sending handshake message failed: %w", err))
 		return
 	}
 
@@ -216,6 +224,12 @@ func (p *Protocol) Receive(data []byte) {
 				return
 			}
 
+			if !p.handshaked && header.Definition.ID != handshake.MessageTypeHandshake {
+				p.Events.Error.Trigger(fmt.Errorf("%w: message type %d", ErrMessageBeforeHandshake, header.Definition.ID))
+				_ = p.conn.Close()
+				return
+			}
+
 			// advance to handle the message type the header says we are receiving
 			p.receivingMessage = header.Definition
 
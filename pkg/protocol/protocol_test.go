@@ -5,6 +5,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/gohornet/hornet/pkg/networkid"
 	"github.com/gohornet/hornet/pkg/protocol"
 	"github.com/gohornet/hornet/pkg/protocol/handshake"
 	"github.com/gohornet/hornet/pkg/protocol/sting"
@@ -79,7 +80,7 @@ func TestProtocol_Receive(t *testing.T) {
 		handshakeMessageReceived = true
 	}))
 
-	handshakeMsg, err := handshake.NewHandshakeMessage(protocol.SupportedFeatureSets, 100, make([]byte, 49), 14)
+	handshakeMsg, err := handshake.NewHandshakeMessage(protocol.SupportedFeatureSets, 100, make([]byte, 49), 14, networkid.FromName("testnet"))
 	assert.NoError(t, err)
 
 	wg := consume(t, p, conn, len(handshakeMsg))
@@ -100,7 +101,7 @@ func TestProtocol_Send(t *testing.T) {
 		handshakeMessageSent = true
 	}))
 
-	handshakeMsg, err := handshake.NewHandshakeMessage(protocol.SupportedFeatureSets, 100, make([]byte, 49), 14)
+	handshakeMsg, err := handshake.NewHandshakeMessage(protocol.SupportedFeatureSets, 100, make([]byte, 49), 14, networkid.FromName("testnet"))
 	assert.NoError(t, err)
 
 	wg := consume(t, p, conn, len(handshakeMsg))
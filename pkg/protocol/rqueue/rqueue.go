@@ -32,10 +32,20 @@ type Queue interface {
 	// Processed marks a request as fulfilled and thereby removes it from the processing set.
 	// Returns the origin request which was pending or nil if the hash was not requested.
 	Processed(hash hornet.Hash) *Request
-	// EnqueuePending enqueues all pending requests back into the queue.
+	// EnqueuePending enqueues all pending requests back into the queue which were last requested
+	// at least retryAfter ago, so that they get requested again (normally from a different neighbor).
 	// It also discards requests in the pending set of which their enqueue time is over the given delta threshold.
 	// If discardOlderThan is zero, no requests are discarded.
-	EnqueuePending(discardOlderThan time.Duration) (queued int)
+	EnqueuePending(retryAfter time.Duration, discardOlderThan time.Duration) (queued int, discarded int)
+	// DiscardBelow removes all queued and pending requests with a milestone index lower than the given index,
+	// e.g. because the data below the pruning index can't be solidified anymore.
+	// Requests which prevent being discarded are removed as well.
+	DiscardBelow(msIndex milestone.Index) (discarded int)
+	// DiscardExhausted removes all queued and pending requests which were sent at least maxRequestCount times
+	// without being answered and which are linked to a milestone at or below the given index,
+	// so they are not needed to solidify a milestone anymore. Requests which prevent being discarded are removed as well.
+	// Returns the removed requests.
+	DiscardExhausted(maxRequestCount int, msIndex milestone.Index) (discarded []*Request)
 	// Size returns the size of currently queued, requested/pending and processing requests.
 	Size() (queued int, pending int, processing int)
 	// Empty tells whether the queue has no queued and pending requests.
@@ -84,6 +94,11 @@ type Request struct {
 	// the time at which this request was first enqueued.
 	// do not modify this time
 	EnqueueTime time.Time
+	// the time at which this request was last popped from the queue to be sent.
+	LastRequestTime time.Time
+	// the amount of times this request was popped from the queue to be sent.
+	// it can be used to ask a different neighbor on every retry.
+	RequestCount int
 }
 
 // implements a priority queue where requests with the lowest milestone index are popped first.
@@ -110,7 +125,10 @@ func (pq *priorityqueue) Next() (r *Request) {
 	if len(pq.queued) == 0 {
 		return nil
 	}
-	return heap.Pop(pq).(*Request)
+	r = heap.Pop(pq).(*Request)
+	r.LastRequestTime = time.Now()
+	r.RequestCount++
+	return r
 }
 
 func (pq *priorityqueue) Enqueue(r *Request) bool {
@@ -192,31 +210,82 @@ func (pq *priorityqueue) Processed(hash hornet.Hash) *Request {
 	return req
 }
 
-func (pq *priorityqueue) EnqueuePending(discardOlderThan time.Duration) int {
+func (pq *priorityqueue) EnqueuePending(retryAfter time.Duration, discardOlderThan time.Duration) (int, int) {
 	pq.Lock()
 	defer pq.Unlock()
 	if len(pq.queued) != 0 {
-		return len(pq.queued)
+		return len(pq.queued), 0
 	}
-	enqueued := len(pq.pending)
+	var discarded int
 	s := time.Now()
 	for k, v := range pq.pending {
 		if pq.filter != nil && !pq.filter(v) {
 			delete(pq.pending, k)
-			enqueued--
 			continue
 		}
-		if discardOlderThan == 0 || v.PreventDiscard || s.Sub(v.EnqueueTime) < discardOlderThan {
-			// no need to examine the queued set
-			// as addition and removal are synced over Push and Pops
-			heap.Push(pq, v)
+		if discardOlderThan != 0 && !v.PreventDiscard && s.Sub(v.EnqueueTime) >= discardOlderThan {
+			// discard request from the queue
+			delete(pq.pending, k)
+			discarded++
+			continue
+		}
+		if s.Sub(v.LastRequestTime) < retryAfter {
+			// give the neighbor some more time to answer the request
 			continue
 		}
-		// discard request from the queue
-		delete(pq.pending, k)
-		enqueued--
+		// no need to examine the queued set
+		// as addition and removal are synced over Push and Pops
+		heap.Push(pq, v)
+	}
+	return len(pq.queued), discarded
+}
+
+func (pq *priorityqueue) DiscardBelow(msIndex milestone.Index) int {
+	pq.Lock()
+	defer pq.Unlock()
+
+	discarded := len(pq.queued) + len(pq.pending)
+	pq.removeWithoutLocking(func(r *Request) bool {
+		return r.MilestoneIndex < msIndex
+	})
+	return discarded - len(pq.queued) - len(pq.pending)
+}
+
+func (pq *priorityqueue) DiscardExhausted(maxRequestCount int, msIndex milestone.Index) []*Request {
+	pq.Lock()
+	defer pq.Unlock()
+
+	var discarded []*Request
+	pq.removeWithoutLocking(func(r *Request) bool {
+		if r.RequestCount < maxRequestCount || r.MilestoneIndex > msIndex {
+			return false
+		}
+		discarded = append(discarded, r)
+		return true
+	})
+	return discarded
+}
+
+// removeWithoutLocking removes all queued and pending requests for which the given function returns true
+// and restores the heap ordering of the remaining queued requests.
+func (pq *priorityqueue) removeWithoutLocking(remove func(r *Request) bool) {
+	filteredQueue := make([]*Request, 0, len(pq.queue))
+	for _, r := range pq.queue {
+		if remove(r) {
+			delete(pq.queued, string(r.Hash))
+			continue
+		}
+		r.index = len(filteredQueue)
+		filteredQueue = append(filteredQueue, r)
+	}
+	pq.queue = filteredQueue
+	heap.Init(pq)
+
+	for k, v := range pq.pending {
+		if remove(v) {
+			delete(pq.pending, k)
+		}
 	}
-	return enqueued
 }
 
 func (pq *priorityqueue) Size() (int, int, int) {
@@ -267,20 +336,9 @@ func (pq *priorityqueue) Filter(f FilterFunc) {
 	pq.Lock()
 	defer pq.Unlock()
 	if f != nil {
-		filteredQueue := make([]*Request, 0)
-		for _, r := range pq.queue {
-			if !f(r) {
-				delete(pq.queued, string(r.Hash))
-				continue
-			}
-			filteredQueue = append(filteredQueue, r)
-		}
-		pq.queue = filteredQueue
-		for k, v := range pq.pending {
-			if !f(v) {
-				delete(pq.pending, k)
-			}
-		}
+		pq.removeWithoutLocking(func(r *Request) bool {
+			return !f(r)
+		})
 	}
 	pq.filter = f
 }
@@ -3,6 +3,7 @@ package rqueue_test
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/protocol/rqueue"
@@ -95,7 +96,8 @@ func TestRequestQueue(t *testing.T) {
 	assert.Zero(t, processing)
 
 	// enqueue pending again
-	queuedCnt := q.EnqueuePending(0)
+	queuedCnt, discardedCnt := q.EnqueuePending(0, 0)
+	assert.Zero(t, discardedCnt)
 	queued, pending, processing = q.Size()
 	assert.Equal(t, queued, queuedCnt)
 	assert.Zero(t, pending)
@@ -125,3 +127,83 @@ func TestRequestQueue(t *testing.T) {
 	assert.Zero(t, len(pendingReqs))
 	assert.Zero(t, len(processingReq))
 }
+
+func TestRequestQueueRetriesAndDiscards(t *testing.T) {
+	q := rqueue.New()
+
+	var (
+		hashA = hornet.Hash(trinary.MustTrytesToBytes("A"))
+		hashB = hornet.Hash(trinary.MustTrytesToBytes("B"))
+		hashC = hornet.Hash(trinary.MustTrytesToBytes("C"))
+	)
+
+	assert.True(t, q.Enqueue(&rqueue.Request{Hash: hashA, MilestoneIndex: 10}))
+	assert.True(t, q.Enqueue(&rqueue.Request{Hash: hashB, MilestoneIndex: 5}))
+	assert.True(t, q.Enqueue(&rqueue.Request{Hash: hashC, MilestoneIndex: 3, PreventDiscard: true}))
+
+	for r := q.Next(); r != nil; r = q.Next() {
+		assert.Equal(t, 1, r.RequestCount)
+	}
+
+	// the requests were just sent, so they are not enqueued again
+	queuedCnt, discardedCnt := q.EnqueuePending(time.Hour, 0)
+	assert.Zero(t, queuedCnt)
+	assert.Zero(t, discardedCnt)
+
+	// requests which can be discarded are removed if they are too old
+	time.Sleep(10 * time.Millisecond)
+	queuedCnt, discardedCnt = q.EnqueuePending(0, time.Millisecond)
+	assert.Equal(t, 1, queuedCnt)
+	assert.Equal(t, 2, discardedCnt)
+
+	r := q.Next()
+	assert.Equal(t, hashC, r.Hash)
+	assert.Equal(t, 2, r.RequestCount)
+
+	// requests below the given milestone index are discarded regardless of PreventDiscard
+	assert.True(t, q.Enqueue(&rqueue.Request{Hash: hashA, MilestoneIndex: 10}))
+	assert.True(t, q.Enqueue(&rqueue.Request{Hash: hashB, MilestoneIndex: 5}))
+	assert.Equal(t, 2, q.DiscardBelow(6))
+
+	queued, pending, processing := q.Size()
+	assert.Equal(t, 1, queued)
+	assert.Zero(t, pending)
+	assert.Zero(t, processing)
+	assert.Equal(t, hashA, q.Next().Hash)
+}
+
+func TestRequestQueueDiscardExhausted(t *testing.T) {
+	q := rqueue.New()
+
+	var (
+		hashA = hornet.Hash(trinary.MustTrytesToBytes("A"))
+		hashB = hornet.Hash(trinary.MustTrytesToBytes("B"))
+		hashC = hornet.Hash(trinary.MustTrytesToBytes("C"))
+	)
+
+	assert.True(t, q.Enqueue(&rqueue.Request{Hash: hashA, MilestoneIndex: 10, PreventDiscard: true}))
+	assert.True(t, q.Enqueue(&rqueue.Request{Hash: hashB, MilestoneIndex: 5, PreventDiscard: true}))
+	assert.True(t, q.Enqueue(&rqueue.Request{Hash: hashC, MilestoneIndex: 3, PreventDiscard: true}))
+
+	// send every request twice
+	for i := 0; i < 2; i++ {
+		for q.Next() != nil {
+		}
+		q.EnqueuePending(0, 0)
+	}
+
+	// the requests were not sent often enough yet
+	assert.Empty(t, q.DiscardExhausted(3, 10))
+
+	// requests linked to milestones above the given index are still needed
+	discarded := q.DiscardExhausted(2, 5)
+	assert.Len(t, discarded, 2)
+	for _, r := range discarded {
+		assert.False(t, q.IsQueued(r.Hash))
+		assert.False(t, q.IsPending(r.Hash))
+	}
+
+	queued, pending, _ := q.Size()
+	assert.Equal(t, 1, queued+pending)
+	assert.Equal(t, hashA, q.Next().Hash)
+}
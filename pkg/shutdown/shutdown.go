@@ -17,6 +17,9 @@ const (
 	PriorityHeartbeats
 	PriorityWarpSync
 	PriorityLocalSnapshots
+	PriorityArchiver
+	PriorityBackup
+	PriorityEventLog
 	PriorityMetricsUpdater
 	PriorityDashboard
 	PriorityPoWHandler
@@ -0,0 +1,564 @@
+package snapshot
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/networkid"
+)
+
+const (
+	// LocalSnapshotFileVersion is the version of the local snapshot files written by the FileWriter.
+	LocalSnapshotFileVersion byte = 5
+	// DeltaSnapshotFileVersion is the version of the delta snapshot files written by the DeltaFileWriter.
+	DeltaSnapshotFileVersion byte = 2
+	// LocalSnapshotFileVersionWithoutNetworkID is the version of the local snapshot files before the network ID was added.
+	// These files can still be read, their network ID is networkid.Unknown.
+	LocalSnapshotFileVersionWithoutNetworkID byte = 4
+	// DeltaSnapshotFileVersionWithoutNetworkID is the version of the delta snapshot files before the network ID was added.
+	// These files can still be read, their network ID is networkid.Unknown.
+	DeltaSnapshotFileVersionWithoutNetworkID byte = 1
+
+	// the size of the binary hashes and addresses in the snapshot files.
+	hashSize = 49
+
+	// offset of the record counts in the local snapshot file header:
+	// 1 (version) + 49 (ms hash) + 4 (ms index) + 8 (ms timestamp)
+	localSnapshotCountsOffset = 62
+	// offset of the record counts in the delta snapshot file header:
+	// 1 (version) + 49 (full ms hash) + 4 (full ms index) + 49 (ms hash) + 4 (ms index) + 8 (ms timestamp)
+	deltaSnapshotCountsOffset = 115
+
+	// the buffer size used for reading and writing snapshot files.
+	bufferSize = 4096 * 2
+
+	// the maximum amount of changes of a milestone diff which are preallocated while reading,
+	// so that a corrupted record count can't exhaust the memory before the records are read.
+	maxPreallocatedChanges = 1024
+)
+
+var (
+	// ErrUnsupportedFileVersion is returned if a snapshot file has an unsupported version.
+	ErrUnsupportedFileVersion = errors.New("unsupported snapshot file version")
+	// ErrWrongRecordOrder is returned if records of a snapshot file are not written in the order of the file sections.
+	ErrWrongRecordOrder = errors.New("snapshot records written in wrong order")
+	// ErrWriterClosed is returned if records are written to an already closed writer.
+	ErrWriterClosed = errors.New("snapshot writer already closed")
+	// ErrInvalidRecordCount is returned if a snapshot file contains a negative record count.
+	ErrInvalidRecordCount = errors.New("invalid snapshot record count")
+)
+
+// FileHeader is the header of a local snapshot file.
+type FileHeader struct {
+	// The hash of the milestone tail transaction of the snapshot.
+	MilestoneHash hornet.Hash
+	// The index of the milestone of the snapshot.
+	MilestoneIndex milestone.Index
+	// The timestamp of the milestone of the snapshot.
+	Timestamp int64
+	// The ID of the network the snapshot was created in, it follows the record counts in the header.
+	NetworkID networkid.ID
+}
+
+// ReadFileHeader is the header of a local snapshot file including the amount of records in every section.
+type ReadFileHeader struct {
+	FileHeader
+	Version             byte
+	SolidEntryPoints    int32
+	SeenMilestones      int32
+	LedgerEntries       int32
+	SpentAddressesCount int32
+}
+
+// DeltaFileHeader is the header of a delta snapshot file.
+type DeltaFileHeader struct {
+	FileHeader
+	// The hash of the milestone tail transaction of the local snapshot the delta is based on.
+	FullMilestoneHash hornet.Hash
+	// The index of the milestone of the local snapshot the delta is based on.
+	FullMilestoneIndex milestone.Index
+}
+
+// ReadDeltaFileHeader is the header of a delta snapshot file including the amount of records in every section.
+type ReadDeltaFileHeader struct {
+	DeltaFileHeader
+	Version          byte
+	SolidEntryPoints int32
+	SeenMilestones   int32
+	MilestoneDiffs   int32
+}
+
+// HeaderConsumerFunc consumes the header of a local snapshot file.
+type HeaderConsumerFunc func(header *ReadFileHeader) error
+
+// DeltaHeaderConsumerFunc consumes the header of a delta snapshot file.
+type DeltaHeaderConsumerFunc func(header *ReadDeltaFileHeader) error
+
+// SolidEntryPointConsumerFunc consumes a solid entry point of a snapshot file.
+type SolidEntryPointConsumerFunc func(txHash hornet.Hash, index milestone.Index) error
+
+// SeenMilestoneConsumerFunc consumes a seen milestone of a snapshot file.
+type SeenMilestoneConsumerFunc func(msHash hornet.Hash, index milestone.Index) error
+
+// LedgerEntryConsumerFunc consumes the balance of an address of a local snapshot file.
+type LedgerEntryConsumerFunc func(address hornet.Hash, balance uint64) error
+
+// SpentAddressConsumerFunc consumes a spent address of a local snapshot file.
+type SpentAddressConsumerFunc func(address hornet.Hash) error
+
+// MilestoneDiffConsumerFunc consumes the ledger changes of a milestone of a delta snapshot file.
+type MilestoneDiffConsumerFunc func(index milestone.Index, changes map[string]int64) error
+
+// section of a snapshot file, the records have to be written in this order.
+type section byte
+
+const (
+	sectionSolidEntryPoints section = iota
+	sectionSeenMilestones
+	sectionLedger
+	sectionSpentAddresses
+	sectionClosed
+)
+
+// sectionWriter writes the records of a snapshot file in the order of its sections and counts them.
+type sectionWriter struct {
+	writeSeeker  io.WriteSeeker
+	buf          *bufio.Writer
+	section      section
+	counts       [sectionClosed]int32
+	countsOffset int64
+}
+
+func newSectionWriter(writeSeeker io.WriteSeeker, countsOffset int64) *sectionWriter {
+	return &sectionWriter{
+		writeSeeker:  writeSeeker,
+		buf:          bufio.NewWriterSize(writeSeeker, bufferSize),
+		countsOffset: countsOffset,
+	}
+}
+
+func (w *sectionWriter) write(values ...interface{}) error {
+	for _, value := range values {
+		if err := binary.Write(w.buf, binary.LittleEndian, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enter switches to the given section and counts the record that is going to be written.
+func (w *sectionWriter) enter(s section) error {
+	if w.section == sectionClosed {
+		return ErrWriterClosed
+	}
+	if s < w.section {
+		return ErrWrongRecordOrder
+	}
+	w.section = s
+	w.counts[s]++
+	return nil
+}
+
+// close flushes the buffer and writes the record counts of the given sections into the header.
+func (w *sectionWriter) close(sections ...section) error {
+	if w.section == sectionClosed {
+		return ErrWriterClosed
+	}
+	w.section = sectionClosed
+
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := w.writeSeeker.Seek(w.countsOffset, io.SeekStart); err != nil {
+		return err
+	}
+
+	for _, s := range sections {
+		if err := binary.Write(w.writeSeeker, binary.LittleEndian, w.counts[s]); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.writeSeeker.Seek(0, io.SeekEnd)
+	return err
+}
+
+// FileWriter streams the records of a local snapshot file.
+// The record counts in the header are written on Close, therefore the underlying writer has to be seekable.
+type FileWriter struct {
+	*sectionWriter
+}
+
+// NewFileWriter writes the header of a local snapshot file and returns a writer for its records.
+func NewFileWriter(writeSeeker io.WriteSeeker, header *FileHeader) (*FileWriter, error) {
+	w := &FileWriter{sectionWriter: newSectionWriter(writeSeeker, localSnapshotCountsOffset)}
+
+	// the record counts are written on Close
+	if err := w.write(LocalSnapshotFileVersion, header.MilestoneHash[:hashSize], header.MilestoneIndex, header.Timestamp,
+		int32(0), int32(0), int32(0), int32(0), uint64(header.NetworkID)); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// WriteSolidEntryPoint writes a solid entry point.
+func (w *FileWriter) WriteSolidEntryPoint(txHash hornet.Hash, index milestone.Index) error {
+	if err := w.enter(sectionSolidEntryPoints); err != nil {
+		return err
+	}
+	return w.write(txHash[:hashSize], index)
+}
+
+// WriteSeenMilestone writes a seen milestone. All solid entry points have to be written before.
+func (w *FileWriter) WriteSeenMilestone(msHash hornet.Hash, index milestone.Index) error {
+	if err := w.enter(sectionSeenMilestones); err != nil {
+		return err
+	}
+	return w.write(msHash[:hashSize], index)
+}
+
+// WriteLedgerEntry writes the balance of an address. All seen milestones have to be written before.
+func (w *FileWriter) WriteLedgerEntry(address hornet.Hash, balance uint64) error {
+	if err := w.enter(sectionLedger); err != nil {
+		return err
+	}
+	return w.write(address[:hashSize], balance)
+}
+
+// WriteSpentAddress writes a spent address. All ledger entries have to be written before.
+func (w *FileWriter) WriteSpentAddress(address hornet.Hash) error {
+	if err := w.enter(sectionSpentAddresses); err != nil {
+		return err
+	}
+	return w.write(address[:hashSize])
+}
+
+// Close flushes the written records and updates the record counts in the header.
+// The underlying writer is positioned at its end afterwards.
+func (w *FileWriter) Close() error {
+	return w.close(sectionSolidEntryPoints, sectionSeenMilestones, sectionLedger, sectionSpentAddresses)
+}
+
+// DeltaFileWriter streams the records of a delta snapshot file.
+// The record counts in the header are written on Close, therefore the underlying writer has to be seekable.
+type DeltaFileWriter struct {
+	*sectionWriter
+	lastDiffIndex milestone.Index
+}
+
+// NewDeltaFileWriter writes the header of a delta snapshot file and returns a writer for its records.
+func NewDeltaFileWriter(writeSeeker io.WriteSeeker, header *DeltaFileHeader) (*DeltaFileWriter, error) {
+	w := &DeltaFileWriter{sectionWriter: newSectionWriter(writeSeeker, deltaSnapshotCountsOffset), lastDiffIndex: header.FullMilestoneIndex}
+
+	// the record counts are written on Close
+	if err := w.write(DeltaSnapshotFileVersion, header.FullMilestoneHash[:hashSize], header.FullMilestoneIndex,
+		header.MilestoneHash[:hashSize], header.MilestoneIndex, header.Timestamp,
+		int32(0), int32(0), int32(0), uint64(header.NetworkID)); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// WriteSolidEntryPoint writes a solid entry point.
+func (w *DeltaFileWriter) WriteSolidEntryPoint(txHash hornet.Hash, index milestone.Index) error {
+	if err := w.enter(sectionSolidEntryPoints); err != nil {
+		return err
+	}
+	return w.write(txHash[:hashSize], index)
+}
+
+// WriteSeenMilestone writes a seen milestone. All solid entry points have to be written before.
+func (w *DeltaFileWriter) WriteSeenMilestone(msHash hornet.Hash, index milestone.Index) error {
+	if err := w.enter(sectionSeenMilestones); err != nil {
+		return err
+	}
+	return w.write(msHash[:hashSize], index)
+}
+
+// WriteMilestoneDiff writes the ledger changes of a milestone. All seen milestones have to be written before,
+// and the milestone diffs have to be written in ascending order.
+func (w *DeltaFileWriter) WriteMilestoneDiff(index milestone.Index, changes map[string]int64) error {
+	if index <= w.lastDiffIndex {
+		return errors.Wrapf(ErrWrongRecordOrder, "milestone diff %d after %d", index, w.lastDiffIndex)
+	}
+	if err := w.enter(sectionLedger); err != nil {
+		return err
+	}
+	w.lastDiffIndex = index
+
+	if err := w.write(index, int32(len(changes))); err != nil {
+		return err
+	}
+	for address, change := range changes {
+		if err := w.write(hornet.Hash(address)[:hashSize], change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close flushes the written records and updates the record counts in the header.
+// The underlying writer is positioned at its end afterwards.
+func (w *DeltaFileWriter) Close() error {
+	return w.close(sectionSolidEntryPoints, sectionSeenMilestones, sectionLedger)
+}
+
+func readValues(reader io.Reader, values ...interface{}) error {
+	for _, value := range values {
+		if err := binary.Read(reader, binary.LittleEndian, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRecordCounts returns ErrInvalidRecordCount if one of the given record counts is negative.
+func checkRecordCounts(counts ...int32) error {
+	for _, count := range counts {
+		if count < 0 {
+			return errors.Wrapf(ErrInvalidRecordCount, "%d", count)
+		}
+	}
+	return nil
+}
+
+func readNetworkID(reader io.Reader, header *FileHeader) error {
+	var networkID uint64
+	if err := readValues(reader, &networkID); err != nil {
+		return err
+	}
+	header.NetworkID = networkid.ID(networkID)
+	return nil
+}
+
+func readHash(reader io.Reader) (hornet.Hash, error) {
+	hash := make(hornet.Hash, hashSize)
+	if _, err := io.ReadFull(reader, hash); err != nil {
+		return nil, err
+	}
+	return hash, nil
+}
+
+// readIndexedHashes reads count records of a hash followed by a milestone index.
+func readIndexedHashes(reader io.Reader, count int32, consumer func(hash hornet.Hash, index milestone.Index) error) error {
+	for i := int32(0); i < count; i++ {
+		hash, err := readHash(reader)
+		if err != nil {
+			return err
+		}
+
+		var index milestone.Index
+		if err := readValues(reader, &index); err != nil {
+			return err
+		}
+
+		if consumer == nil {
+			continue
+		}
+		if err := consumer(hash, index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamLocalSnapshotDataFrom reads a local snapshot file and passes its records to the given consumers.
+// Consumers can be nil to skip the records of a section. Reading stops at the first error of a consumer.
+func StreamLocalSnapshotDataFrom(reader io.Reader,
+	headerConsumer HeaderConsumerFunc,
+	sepConsumer SolidEntryPointConsumerFunc,
+	seenMilestoneConsumer SeenMilestoneConsumerFunc,
+	ledgerEntryConsumer LedgerEntryConsumerFunc,
+	spentAddressConsumer SpentAddressConsumerFunc) error {
+
+	bufReader := bufio.NewReaderSize(reader, bufferSize)
+
+	header := &ReadFileHeader{}
+	if err := readValues(bufReader, &header.Version); err != nil {
+		return errors.Wrap(err, "header")
+	}
+
+	if header.Version != LocalSnapshotFileVersion && header.Version != LocalSnapshotFileVersionWithoutNetworkID {
+		return errors.Wrapf(ErrUnsupportedFileVersion, "local snapshot file version is %d, supported versions are %d and %d", header.Version, LocalSnapshotFileVersionWithoutNetworkID, LocalSnapshotFileVersion)
+	}
+
+	var err error
+	if header.MilestoneHash, err = readHash(bufReader); err != nil {
+		return errors.Wrap(err, "header")
+	}
+
+	if err := readValues(bufReader, &header.MilestoneIndex, &header.Timestamp,
+		&header.SolidEntryPoints, &header.SeenMilestones, &header.LedgerEntries, &header.SpentAddressesCount); err != nil {
+		return errors.Wrap(err, "header")
+	}
+
+	if err := checkRecordCounts(header.SolidEntryPoints, header.SeenMilestones, header.LedgerEntries, header.SpentAddressesCount); err != nil {
+		return errors.Wrap(err, "header")
+	}
+
+	if header.Version != LocalSnapshotFileVersionWithoutNetworkID {
+		if err := readNetworkID(bufReader, &header.FileHeader); err != nil {
+			return errors.Wrap(err, "header")
+		}
+	}
+
+	if headerConsumer != nil {
+		if err := headerConsumer(header); err != nil {
+			return err
+		}
+	}
+
+	if err := readIndexedHashes(bufReader, header.SolidEntryPoints, sepConsumer); err != nil {
+		return errors.Wrap(err, "solidEntryPoints")
+	}
+
+	if err := readIndexedHashes(bufReader, header.SeenMilestones, seenMilestoneConsumer); err != nil {
+		return errors.Wrap(err, "seenMilestones")
+	}
+
+	for i := int32(0); i < header.LedgerEntries; i++ {
+		address, err := readHash(bufReader)
+		if err != nil {
+			return errors.Wrap(err, "ledgerEntries")
+		}
+
+		var balance uint64
+		if err := readValues(bufReader, &balance); err != nil {
+			return errors.Wrap(err, "ledgerEntries")
+		}
+
+		if ledgerEntryConsumer == nil {
+			continue
+		}
+		if err := ledgerEntryConsumer(address, balance); err != nil {
+			return err
+		}
+	}
+
+	if spentAddressConsumer == nil {
+		return nil
+	}
+
+	for i := int32(0); i < header.SpentAddressesCount; i++ {
+		address, err := readHash(bufReader)
+		if err != nil {
+			return errors.Wrap(err, "spentAddresses")
+		}
+
+		if err := spentAddressConsumer(address); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StreamDeltaSnapshotDataFrom reads a delta snapshot file and passes its records to the given consumers.
+// Consumers can be nil to skip the records of a section. Reading stops at the first error of a consumer.
+func StreamDeltaSnapshotDataFrom(reader io.Reader,
+	headerConsumer DeltaHeaderConsumerFunc,
+	sepConsumer SolidEntryPointConsumerFunc,
+	seenMilestoneConsumer SeenMilestoneConsumerFunc,
+	milestoneDiffConsumer MilestoneDiffConsumerFunc) error {
+
+	bufReader := bufio.NewReaderSize(reader, bufferSize)
+
+	header := &ReadDeltaFileHeader{}
+	if err := readValues(bufReader, &header.Version); err != nil {
+		return errors.Wrap(err, "header")
+	}
+
+	if header.Version != DeltaSnapshotFileVersion && header.Version != DeltaSnapshotFileVersionWithoutNetworkID {
+		return errors.Wrapf(ErrUnsupportedFileVersion, "delta snapshot file version is %d, supported versions are %d and %d", header.Version, DeltaSnapshotFileVersionWithoutNetworkID, DeltaSnapshotFileVersion)
+	}
+
+	var err error
+	if header.FullMilestoneHash, err = readHash(bufReader); err != nil {
+		return errors.Wrap(err, "header")
+	}
+
+	if err := readValues(bufReader, &header.FullMilestoneIndex); err != nil {
+		return errors.Wrap(err, "header")
+	}
+
+	if header.MilestoneHash, err = readHash(bufReader); err != nil {
+		return errors.Wrap(err, "header")
+	}
+
+	if err := readValues(bufReader, &header.MilestoneIndex, &header.Timestamp,
+		&header.SolidEntryPoints, &header.SeenMilestones, &header.MilestoneDiffs); err != nil {
+		return errors.Wrap(err, "header")
+	}
+
+	if err := checkRecordCounts(header.SolidEntryPoints, header.SeenMilestones, header.MilestoneDiffs); err != nil {
+		return errors.Wrap(err, "header")
+	}
+
+	if header.Version != DeltaSnapshotFileVersionWithoutNetworkID {
+		if err := readNetworkID(bufReader, &header.FileHeader); err != nil {
+			return errors.Wrap(err, "header")
+		}
+	}
+
+	if headerConsumer != nil {
+		if err := headerConsumer(header); err != nil {
+			return err
+		}
+	}
+
+	if err := readIndexedHashes(bufReader, header.SolidEntryPoints, sepConsumer); err != nil {
+		return errors.Wrap(err, "solidEntryPoints")
+	}
+
+	if err := readIndexedHashes(bufReader, header.SeenMilestones, seenMilestoneConsumer); err != nil {
+		return errors.Wrap(err, "seenMilestones")
+	}
+
+	for i := int32(0); i < header.MilestoneDiffs; i++ {
+		var index milestone.Index
+		var changesCount int32
+		if err := readValues(bufReader, &index, &changesCount); err != nil {
+			return errors.Wrap(err, "milestoneDiffs")
+		}
+
+		if err := checkRecordCounts(changesCount); err != nil {
+			return errors.Wrap(err, "milestoneDiffs")
+		}
+
+		preallocated := changesCount
+		if preallocated > maxPreallocatedChanges {
+			preallocated = maxPreallocatedChanges
+		}
+
+		changes := make(map[string]int64, preallocated)
+		for j := int32(0); j < changesCount; j++ {
+			address, err := readHash(bufReader)
+			if err != nil {
+				return errors.Wrap(err, "milestoneDiffs")
+			}
+
+			var change int64
+			if err := readValues(bufReader, &change); err != nil {
+				return errors.Wrap(err, "milestoneDiffs")
+			}
+			changes[string(address)] = change
+		}
+
+		if milestoneDiffConsumer == nil {
+			continue
+		}
+		if err := milestoneDiffConsumer(index, changes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package snapshot
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/networkid"
+)
+
+func randomHash() hornet.Hash {
+	hash := make(hornet.Hash, hashSize)
+	rand.Read(hash)
+	return hash
+}
+
+func tempFile(t *testing.T) *os.File {
+	file, err := ioutil.TempFile("", "snapshot")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		file.Close()
+		os.Remove(file.Name())
+	})
+	return file
+}
+
+func TestLocalSnapshotFileRoundTrip(t *testing.T) {
+	file := tempFile(t)
+
+	header := &FileHeader{MilestoneHash: randomHash(), MilestoneIndex: 1000, Timestamp: 1600000000, NetworkID: networkid.FromName("testnet")}
+	sep, seen, address, spent := randomHash(), randomHash(), randomHash(), randomHash()
+
+	w, err := NewFileWriter(file, header)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteSolidEntryPoint(sep, 999))
+	require.NoError(t, w.WriteSeenMilestone(seen, 1001))
+	require.NoError(t, w.WriteLedgerEntry(address, 2779530283277761))
+	require.NoError(t, w.WriteSpentAddress(spent))
+	assert.True(t, errors.Is(w.WriteLedgerEntry(address, 0), ErrWrongRecordOrder))
+	require.NoError(t, w.Close())
+	assert.True(t, errors.Is(w.WriteSpentAddress(spent), ErrWriterClosed))
+
+	_, err = file.Seek(0, 0)
+	require.NoError(t, err)
+
+	var spentAddresses hornet.Hashes
+	err = StreamLocalSnapshotDataFrom(file,
+		func(readHeader *ReadFileHeader) error {
+			assert.Equal(t, LocalSnapshotFileVersion, readHeader.Version)
+			assert.Equal(t, *header, readHeader.FileHeader)
+			assert.EqualValues(t, 1, readHeader.SolidEntryPoints)
+			assert.EqualValues(t, 1, readHeader.SeenMilestones)
+			assert.EqualValues(t, 1, readHeader.LedgerEntries)
+			assert.EqualValues(t, 1, readHeader.SpentAddressesCount)
+			return nil
+		},
+		func(txHash hornet.Hash, index milestone.Index) error {
+			assert.Equal(t, sep, txHash)
+			assert.EqualValues(t, 999, index)
+			return nil
+		},
+		nil,
+		func(addr hornet.Hash, balance uint64) error {
+			assert.Equal(t, address, addr)
+			assert.EqualValues(t, 2779530283277761, balance)
+			return nil
+		},
+		func(addr hornet.Hash) error {
+			spentAddresses = append(spentAddresses, addr)
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, hornet.Hashes{spent}, spentAddresses)
+}
+
+func TestLocalSnapshotFileWithoutNetworkID(t *testing.T) {
+	file := tempFile(t)
+
+	header := &FileHeader{MilestoneHash: randomHash(), MilestoneIndex: 1000, Timestamp: 1600000000, NetworkID: networkid.FromName("testnet")}
+	address := randomHash()
+
+	w, err := NewFileWriter(file, header)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteLedgerEntry(address, 2779530283277761))
+	require.NoError(t, w.Close())
+
+	// files of the previous version have no network ID after the record counts
+	data, err := ioutil.ReadFile(file.Name())
+	require.NoError(t, err)
+	networkIDOffset := localSnapshotCountsOffset + 16
+	data = append(data[:networkIDOffset:networkIDOffset], data[networkIDOffset+8:]...)
+	data[0] = LocalSnapshotFileVersionWithoutNetworkID
+
+	var balances int
+	err = StreamLocalSnapshotDataFrom(bytes.NewReader(data),
+		func(readHeader *ReadFileHeader) error {
+			assert.Equal(t, LocalSnapshotFileVersionWithoutNetworkID, readHeader.Version)
+			assert.Equal(t, networkid.Unknown, readHeader.NetworkID)
+			assert.Equal(t, header.MilestoneHash, readHeader.MilestoneHash)
+			return nil
+		}, nil, nil,
+		func(addr hornet.Hash, balance uint64) error {
+			assert.Equal(t, address, addr)
+			assert.EqualValues(t, 2779530283277761, balance)
+			balances++
+			return nil
+		}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, balances)
+}
+
+func TestDeltaSnapshotFileRoundTrip(t *testing.T) {
+	file := tempFile(t)
+
+	header := &DeltaFileHeader{
+		FileHeader:         FileHeader{MilestoneHash: randomHash(), MilestoneIndex: 1010, Timestamp: 1600000100, NetworkID: networkid.FromName("testnet")},
+		FullMilestoneHash:  randomHash(),
+		FullMilestoneIndex: 1000,
+	}
+	address := randomHash()
+
+	w, err := NewDeltaFileWriter(file, header)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteMilestoneDiff(1005, map[string]int64{string(address): -10}))
+	assert.True(t, errors.Is(w.WriteMilestoneDiff(1005, nil), ErrWrongRecordOrder))
+	require.NoError(t, w.WriteMilestoneDiff(1010, map[string]int64{string(address): 10}))
+	require.NoError(t, w.Close())
+
+	_, err = file.Seek(0, 0)
+	require.NoError(t, err)
+
+	diffs := make(map[milestone.Index]map[string]int64)
+	err = StreamDeltaSnapshotDataFrom(file,
+		func(readHeader *ReadDeltaFileHeader) error {
+			assert.Equal(t, *header, readHeader.DeltaFileHeader)
+			assert.EqualValues(t, 2, readHeader.MilestoneDiffs)
+			return nil
+		}, nil, nil,
+		func(index milestone.Index, changes map[string]int64) error {
+			diffs[index] = changes
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, map[milestone.Index]map[string]int64{
+		1005: {string(address): -10},
+		1010: {string(address): 10},
+	}, diffs)
+}
+
+func TestDeltaSnapshotFileInvalidRecordCount(t *testing.T) {
+	file := tempFile(t)
+
+	header := &DeltaFileHeader{FileHeader: FileHeader{MilestoneHash: randomHash(), MilestoneIndex: 1010}, FullMilestoneHash: randomHash(), FullMilestoneIndex: 1000}
+
+	w, err := NewDeltaFileWriter(file, header)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteMilestoneDiff(1005, map[string]int64{string(randomHash()): 0}))
+	require.NoError(t, w.Close())
+
+	stream := func() error {
+		_, err := file.Seek(0, 0)
+		require.NoError(t, err)
+		return StreamDeltaSnapshotDataFrom(file, nil, nil, nil, nil)
+	}
+	require.NoError(t, stream())
+
+	// a negative changes count of the milestone diff: header + 12 (section counts) + 8 (network ID) + 4 (ms index)
+	_, err = file.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, deltaSnapshotCountsOffset+12+8+4)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(stream(), ErrInvalidRecordCount))
+
+	// a negative section count in the header
+	_, err = file.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, deltaSnapshotCountsOffset)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(stream(), ErrInvalidRecordCount))
+}
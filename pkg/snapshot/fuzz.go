@@ -0,0 +1,54 @@
+//go:build gofuzz
+// +build gofuzz
+
+package snapshot
+
+import (
+	"bytes"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+func fuzzIndexedHashConsumer(hash hornet.Hash, _ milestone.Index) error {
+	if len(hash) != hashSize {
+		panic("invalid hash length")
+	}
+	return nil
+}
+
+// FuzzLocalSnapshot is the go-fuzz target for reading local snapshot files.
+// Build with "go-fuzz-build -func FuzzLocalSnapshot".
+func FuzzLocalSnapshot(data []byte) int {
+	if err := StreamLocalSnapshotDataFrom(bytes.NewReader(data),
+		func(_ *ReadFileHeader) error { return nil },
+		fuzzIndexedHashConsumer,
+		fuzzIndexedHashConsumer,
+		func(address hornet.Hash, _ uint64) error { return fuzzIndexedHashConsumer(address, 0) },
+		func(address hornet.Hash) error { return fuzzIndexedHashConsumer(address, 0) },
+	); err != nil {
+		return 0
+	}
+	return 1
+}
+
+// FuzzDeltaSnapshot is the go-fuzz target for reading delta snapshot files.
+// Build with "go-fuzz-build -func FuzzDeltaSnapshot".
+func FuzzDeltaSnapshot(data []byte) int {
+	if err := StreamDeltaSnapshotDataFrom(bytes.NewReader(data),
+		func(_ *ReadDeltaFileHeader) error { return nil },
+		fuzzIndexedHashConsumer,
+		fuzzIndexedHashConsumer,
+		func(_ milestone.Index, changes map[string]int64) error {
+			for address := range changes {
+				if err := fuzzIndexedHashConsumer(hornet.Hash(address), 0); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	); err != nil {
+		return 0
+	}
+	return 1
+}
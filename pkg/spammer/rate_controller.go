@@ -0,0 +1,76 @@
+package spammer
+
+import (
+	"sync"
+)
+
+const (
+	// the factor the rate is multiplied with if the node is overloaded.
+	rateDecreaseFactor = 0.5
+	// the fraction of the target rate the rate is increased by if the node is not overloaded.
+	rateIncreaseFraction = 0.1
+	// the fraction of the target rate the rate never falls below.
+	minRateFraction = 0.1
+)
+
+// RateController adapts the spam rate to the load of the node.
+// The rate is halved every time the node is overloaded and increases linearly
+// up to the target rate again while the node keeps up.
+type RateController struct {
+	sync.RWMutex
+	target  float64
+	current float64
+}
+
+// NewRateController creates a new RateController which starts at the given target rate.
+func NewRateController(target float64) *RateController {
+	return &RateController{
+		target:  target,
+		current: target,
+	}
+}
+
+// Target returns the rate the controller heads towards.
+func (r *RateController) Target() float64 {
+	r.RLock()
+	defer r.RUnlock()
+	return r.target
+}
+
+// Current returns the current rate.
+func (r *RateController) Current() float64 {
+	r.RLock()
+	defer r.RUnlock()
+	return r.current
+}
+
+// SetTarget sets the rate the controller heads towards.
+// The current rate is capped at the new target.
+func (r *RateController) SetTarget(target float64) {
+	r.Lock()
+	defer r.Unlock()
+	r.target = target
+	if r.current > target {
+		r.current = target
+	}
+}
+
+// Adjust adapts the current rate depending on whether the node is overloaded and returns it.
+func (r *RateController) Adjust(overloaded bool) float64 {
+	r.Lock()
+	defer r.Unlock()
+
+	if overloaded {
+		r.current *= rateDecreaseFactor
+		if minRate := r.target * minRateFraction; r.current < minRate {
+			r.current = minRate
+		}
+		return r.current
+	}
+
+	r.current += r.target * rateIncreaseFraction
+	if r.current > r.target {
+		r.current = r.target
+	}
+	return r.current
+}
@@ -0,0 +1,30 @@
+package spammer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateController(t *testing.T) {
+	r := NewRateController(100)
+	assert.Equal(t, 100.0, r.Current())
+
+	// the rate is halved on overload, but never falls below the minimum
+	assert.Equal(t, 50.0, r.Adjust(true))
+	assert.Equal(t, 25.0, r.Adjust(true))
+	assert.Equal(t, 12.5, r.Adjust(true))
+	assert.Equal(t, 10.0, r.Adjust(true))
+
+	// the rate increases linearly up to the target again
+	assert.Equal(t, 20.0, r.Adjust(false))
+	for i := 0; i < 20; i++ {
+		r.Adjust(false)
+	}
+	assert.Equal(t, 100.0, r.Current())
+
+	// the current rate is capped at a lower target
+	r.SetTarget(40)
+	assert.Equal(t, 40.0, r.Current())
+	assert.Equal(t, 40.0, r.Target())
+}
@@ -73,7 +73,9 @@ func New(txAddress string, message string, tag string, tagSemiLazy string, tipse
 	}
 }
 
-func (s *Spammer) DoSpam(bundleSize int, valueSpam bool, shutdownSignal <-chan struct{}) (time.Duration, time.Duration, error) {
+// DoSpam selects tips, creates a bundle of the given size, does the PoW with the given parallelism and sends it to the network.
+// It returns the duration of the tip selection and of the PoW.
+func (s *Spammer) DoSpam(bundleSize int, valueSpam bool, powParallelism int, shutdownSignal <-chan struct{}) (time.Duration, time.Duration, error) {
 
 	tag := s.tagSubstring
 
@@ -102,7 +104,7 @@ func (s *Spammer) DoSpam(bundleSize int, valueSpam bool, shutdownSignal <-chan s
 	}
 
 	timeStart = time.Now()
-	err = s.doPow(b, tips[0].Trytes(), tips[1].Trytes(), s.mwm, shutdownSignal)
+	err = s.doPow(b, tips[0].Trytes(), tips[1].Trytes(), s.mwm, powParallelism, shutdownSignal)
 	if err != nil {
 		return time.Duration(0), time.Duration(0), err
 	}
@@ -115,7 +117,7 @@ func (s *Spammer) DoSpam(bundleSize int, valueSpam bool, shutdownSignal <-chan s
 	return durationGTTA, durationPOW, nil
 }
 
-func (s *Spammer) doPow(b bundle.Bundle, trunk trinary.Hash, branch trinary.Hash, mwm int, shutdownSignal <-chan struct{}) error {
+func (s *Spammer) doPow(b bundle.Bundle, trunk trinary.Hash, branch trinary.Hash, mwm int, parallelism int, shutdownSignal <-chan struct{}) error {
 	var prev trinary.Hash
 
 	for i := len(b) - 1; i >= 0; i-- {
@@ -144,7 +146,7 @@ func (s *Spammer) doPow(b bundle.Bundle, trunk trinary.Hash, branch trinary.Hash
 		default:
 		}
 
-		nonce, err := s.powHandler.DoPoW(trytes, mwm, 1)
+		nonce, err := s.powHandler.DoPoW(trytes, mwm, parallelism)
 		if err != nil {
 			return err
 		}
@@ -61,7 +61,11 @@ func (te *TestEnvironment) configureCoordinator() {
 	tangle.SetSnapshotMilestone(hornet.HashFromAddressTrytes(cooAddress), hornet.NullHashBytes, 0, 0, 0, time.Now().Unix(), false)
 
 	// configure Milestones
-	tangle.ConfigureMilestones(hornet.HashFromAddressTrytes(cooAddress), int(cooSecLevel), merkleTreeDepth, merkleHashFunc)
+	keyManager := tangle.NewMilestoneKeyManager()
+	err = keyManager.AddKeyRange(hornet.HashFromAddressTrytes(cooAddress), 0, 0)
+	require.NoError(te.testState, err)
+
+	tangle.ConfigureMilestones(keyManager, int(cooSecLevel), merkleTreeDepth, merkleHashFunc)
 
 	milestoneHash, err := te.coo.Bootstrap()
 	require.NoError(te.testState, err)
@@ -118,14 +118,14 @@ func (te *TestEnvironment) VerifyLMI(index milestone.Index) {
 // AssertAddressBalance generates an address for the given seed and index and checks correct balance.
 func (te *TestEnvironment) AssertAddressBalance(seed trinary.Trytes, index uint64, balance uint64) {
 	address := utils.GenerateAddress(te.testState, seed, index)
-	addrBalance, _, err := tangle.GetBalanceForAddress(address)
+	addrBalance, _, err := tangle.Ledger().GetBalanceForAddress(address)
 	require.NoError(te.testState, err)
 	require.Equal(te.testState, balance, addrBalance)
 }
 
 // AssertTotalSupplyStillValid checks if the total supply in the database is still correct.
 func (te *TestEnvironment) AssertTotalSupplyStillValid() {
-	_, _, err := tangle.GetLedgerStateForLSMI(nil)
+	_, _, err := tangle.Ledger().GetLedgerStateForLSMI(nil)
 	require.NoError(te.testState, err)
 }
 
@@ -16,6 +16,7 @@ import (
 	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/iota.go/consts"
 
+	"github.com/gohornet/hornet/pkg/dag"
 	"github.com/gohornet/hornet/pkg/model/coordinator"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
@@ -32,7 +33,7 @@ var (
 // TestEnvironment holds the state of the test environment.
 type TestEnvironment struct {
 	// testState is the state of the current test case.
-	testState *testing.T
+	testState testing.TB
 
 	// Milestones are the created milestones by the coordinator during the test.
 	Milestones tangle.CachedBundles
@@ -40,6 +41,9 @@ type TestEnvironment struct {
 	// cachedBundles is used to cleanup all bundles at the end of a test.
 	cachedBundles tangle.CachedBundles
 
+	// namedBundles are the bundles of the built topology by their name.
+	namedBundles map[string]*tangle.CachedBundle
+
 	// showConfirmationGraphs is set if pictures of the confirmation graph should be externally opened during the test.
 	showConfirmationGraphs bool
 
@@ -76,12 +80,13 @@ func searchProjectRootFolder() string {
 
 // SetupTestEnvironment initializes a clean database with initial balances,
 // configures a coordinator with a clean state, bootstraps the network and issues the first "numberOfMilestones" milestones.
-func SetupTestEnvironment(testState *testing.T, initialBalances map[string]uint64, numberOfMilestones int, showConfirmationGraphs bool) *TestEnvironment {
+func SetupTestEnvironment(testState testing.TB, initialBalances map[string]uint64, numberOfMilestones int, showConfirmationGraphs bool) *TestEnvironment {
 
 	te := &TestEnvironment{
 		testState:              testState,
 		Milestones:             make(tangle.CachedBundles, 0),
 		cachedBundles:          make(tangle.CachedBundles, 0),
+		namedBundles:           make(map[string]*tangle.CachedBundle),
 		showConfirmationGraphs: showConfirmationGraphs,
 		powHandler:             pow.New(nil, "", 30*time.Second),
 		lastMilestoneHash:      hornet.NullHashBytes,
@@ -105,11 +110,12 @@ func SetupTestEnvironment(testState *testing.T, initialBalances map[string]uint6
 
 	tangle.ResetSolidEntryPoints()
 	tangle.ResetMilestoneIndexes()
+	dag.ResetRootSnapshotIndexesCache()
 
 	snapshotIndex := milestone.Index(0)
 
 	tangle.StoreSnapshotBalancesInDatabase(balances, snapshotIndex)
-	tangle.StoreLedgerBalancesInDatabase(balances, snapshotIndex)
+	tangle.Ledger().StoreLedgerBalancesInDatabase(balances, snapshotIndex)
 
 	te.AssertTotalSupplyStillValid()
 
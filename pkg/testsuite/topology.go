@@ -0,0 +1,154 @@
+package testsuite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/guards"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite/utils"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+)
+
+const (
+	// milestoneNamePrefix is the prefix of the names which reference milestones in a topology, e.g. "ms2".
+	milestoneNamePrefix = "ms"
+)
+
+// BundleSpec describes a bundle of a tangle topology.
+type BundleSpec struct {
+	// Name is the name of the bundle, which is used to reference it as a parent and in the assertions.
+	Name string
+	// Trunk and Branch are the names of the bundles or milestones ("ms<index>") the bundle is attached to.
+	Trunk  string
+	Branch string
+	// Trytes are the trytes of the bundle. A zero value bundle tagged with the name is created if empty.
+	Trytes []trinary.Trytes
+}
+
+// Topology is a list of bundles, which is built in order, so that parents have to be listed before their approvers.
+type Topology []BundleSpec
+
+// BuildTopology attaches and stores all bundles of the given topology.
+func (te *TestEnvironment) BuildTopology(topology Topology) {
+	for _, spec := range topology {
+		te.AttachNamedBundle(spec.Name, spec.Trunk, spec.Branch, spec.Trytes)
+	}
+}
+
+// AttachNamedBundle attaches the given bundle to the bundles or milestones with the given names and stores it under its name.
+// A zero value bundle tagged with the name is created if no trytes are given.
+func (te *TestEnvironment) AttachNamedBundle(name string, trunk string, branch string, trytes []trinary.Trytes) *tangle.CachedBundle {
+	require.NotContains(te.testState, te.namedBundles, name, "bundle %s already exists", name)
+	require.False(te.testState, strings.HasPrefix(name, milestoneNamePrefix), "bundle names must not start with %q", milestoneNamePrefix)
+
+	if len(trytes) == 0 {
+		tag := strings.ToUpper(name)
+		require.True(te.testState, guards.IsTrytesOfMaxLength(tag, consts.TagTrinarySize/3), "bundle name %s is not a valid tag", name)
+		trytes = utils.ZeroValueTx(te.testState, tag)
+	}
+
+	cachedBndl := te.AttachAndStoreBundle(te.TailOf(trunk), te.TailOf(branch), trytes)
+	te.namedBundles[name] = cachedBndl
+
+	return cachedBndl
+}
+
+// TailOf returns the tail transaction hash of the bundle or milestone ("ms<index>") with the given name.
+func (te *TestEnvironment) TailOf(name string) hornet.Hash {
+	if strings.HasPrefix(name, milestoneNamePrefix) {
+		msIndex, err := strconv.ParseUint(strings.TrimPrefix(name, milestoneNamePrefix), 10, 32)
+		require.NoError(te.testState, err, "invalid milestone name %s", name)
+
+		cachedMs := tangle.GetMilestoneOrNil(milestone.Index(msIndex)) // bundle +1
+		require.NotNil(te.testState, cachedMs, "milestone %d not found", msIndex)
+		defer cachedMs.Release(true) // bundle -1
+
+		return cachedMs.GetBundle().GetTailHash()
+	}
+
+	cachedBndl, exists := te.namedBundles[name]
+	require.True(te.testState, exists, "bundle %s not found", name)
+
+	return cachedBndl.GetBundle().GetTailHash()
+}
+
+// ConfirmMilestoneOn issues a milestone on top of the bundle or milestone with the given name and confirms it.
+func (te *TestEnvironment) ConfirmMilestoneOn(name string) *whiteflag.ConfirmedMilestoneStats {
+	return te.IssueAndConfirmMilestoneOnTip(te.TailOf(name), false)
+}
+
+// metadataOf returns the metadata of the tail transaction of the bundle with the given name.
+func (te *TestEnvironment) metadataOf(name string) *tangle.CachedMetadata {
+	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(te.TailOf(name)) // meta +1
+	require.NotNil(te.testState, cachedTxMeta, "metadata of %s not found", name)
+	return cachedTxMeta
+}
+
+// AssertConfirmed checks if the bundles with the given names were confirmed by the given milestone.
+func (te *TestEnvironment) AssertConfirmed(msIndex milestone.Index, names ...string) {
+	for _, name := range names {
+		cachedTxMeta := te.metadataOf(name) // meta +1
+		confirmed, at := cachedTxMeta.GetMetadata().GetConfirmed()
+		cachedTxMeta.Release(true) // meta -1
+
+		require.True(te.testState, confirmed, "%s is not confirmed", name)
+		require.Equal(te.testState, msIndex, at, "%s was confirmed by another milestone", name)
+	}
+}
+
+// AssertNotConfirmed checks if the bundles with the given names were not confirmed yet.
+func (te *TestEnvironment) AssertNotConfirmed(names ...string) {
+	for _, name := range names {
+		cachedTxMeta := te.metadataOf(name) // meta +1
+		confirmed := cachedTxMeta.GetMetadata().IsConfirmed()
+		cachedTxMeta.Release(true) // meta -1
+
+		require.False(te.testState, confirmed, "%s is confirmed", name)
+	}
+}
+
+// AssertLedgerInclusionState checks the ledger inclusion state and the conflict reason of the bundle with the given name.
+func (te *TestEnvironment) AssertLedgerInclusionState(name string, state hornet.LedgerInclusionState, reason hornet.ConflictReason) {
+	cachedTxMeta := te.metadataOf(name) // meta +1
+	defer cachedTxMeta.Release(true)    // meta -1
+
+	require.Equal(te.testState, state, cachedTxMeta.GetMetadata().GetLedgerInclusionState(), "ledger inclusion state of %s", name)
+	require.Equal(te.testState, reason, cachedTxMeta.GetMetadata().GetConflictReason(), "conflict reason of %s", name)
+}
+
+// AssertRootSnapshotIndexes checks the youngest and oldest root snapshot indexes of the bundle with the given name,
+// which are used by the tip selection to score the tips.
+func (te *TestEnvironment) AssertRootSnapshotIndexes(name string, youngest milestone.Index, oldest milestone.Index) {
+	yrtsi, ortsi := dag.GetTransactionRootSnapshotIndexes(te.metadataOf(name), tangle.GetSolidMilestoneIndex()) // meta pass +1
+	require.Equal(te.testState, youngest, yrtsi, "youngest root snapshot index of %s", name)
+	require.Equal(te.testState, oldest, ortsi, "oldest root snapshot index of %s", name)
+}
+
+// AssertLedgerDiff checks the balance changes of the given milestone, the keys are addresses in the form of hornet.Hash.
+func (te *TestEnvironment) AssertLedgerDiff(msIndex milestone.Index, expected map[string]int64) {
+	diff, err := tangle.GetLedgerDiffForMilestone(msIndex, nil)
+	require.NoError(te.testState, err)
+
+	formatDiff := func(diff map[string]int64) string {
+		var entries []string
+		for address, change := range diff {
+			entries = append(entries, fmt.Sprintf("%s: %d", hornet.Hash(address).Trytes(), change))
+		}
+		return strings.Join(entries, ", ")
+	}
+
+	require.Equal(te.testState, len(expected), len(diff), "ledger diff of milestone %d: %s", msIndex, formatDiff(diff))
+	for address, change := range expected {
+		require.Equal(te.testState, change, diff[address], "balance change of %s in milestone %d", hornet.Hash(address).Trytes(), msIndex)
+	}
+}
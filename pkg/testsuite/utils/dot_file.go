@@ -39,7 +39,7 @@ func ShortenedTag(bundle *tangle.CachedBundle) string {
 }
 
 // ShowDotFile creates a png file with dot and shows it in an external application.
-func ShowDotFile(t *testing.T, dotCommand string, outFilePath string) {
+func ShowDotFile(t testing.TB, dotCommand string, outFilePath string) {
 
 	cmd := exec.Command("dot", "-Tpng", "-o"+outFilePath)
 
@@ -20,7 +20,7 @@ import (
 )
 
 // GenerateAddress generates an address for the given seed and index with medium security.
-func GenerateAddress(t *testing.T, seed trinary.Trytes, index uint64) hornet.Hash {
+func GenerateAddress(t testing.TB, seed trinary.Trytes, index uint64) hornet.Hash {
 	seedAddress, err := address.GenerateAddress(seed, index, consts.SecurityLevelMedium, false)
 	require.NoError(t, err)
 
@@ -28,11 +28,16 @@ func GenerateAddress(t *testing.T, seed trinary.Trytes, index uint64) hornet.Has
 }
 
 // ZeroValueTx creates a zero value transaction to a random address with the given tag.
-func ZeroValueTx(t *testing.T, tag trinary.Trytes) []trinary.Trytes {
+func ZeroValueTx(t testing.TB, tag trinary.Trytes) []trinary.Trytes {
+	return ZeroValueTxToAddress(t, tag, trinary.MustPad(utils.RandomTrytesInsecure(consts.AddressTrinarySize/3), consts.AddressTrinarySize/3))
+}
+
+// ZeroValueTxToAddress creates a zero value transaction to the given address with the given tag.
+func ZeroValueTxToAddress(t testing.TB, tag trinary.Trytes, addr trinary.Hash) []trinary.Trytes {
 
 	var b bundle.Bundle
 	entry := bundle.BundleEntry{
-		Address:                   trinary.MustPad(utils.RandomTrytesInsecure(consts.AddressTrinarySize/3), consts.AddressTrinarySize/3),
+		Address:                   addr,
 		Value:                     0,
 		Tag:                       tag,
 		Timestamp:                 uint64(time.Now().UnixNano() / int64(time.Second)),
@@ -46,7 +51,7 @@ func ZeroValueTx(t *testing.T, tag trinary.Trytes) []trinary.Trytes {
 }
 
 // ValueTx creates a value transaction with the given tag from an input seed index to an address created by a given output seed and index.
-func ValueTx(t *testing.T, tag trinary.Trytes, fromSeed trinary.Trytes, fromIndex uint64, balance uint64, toSeed trinary.Trytes, toIndex uint64, value uint64) []trinary.Trytes {
+func ValueTx(t testing.TB, tag trinary.Trytes, fromSeed trinary.Trytes, fromIndex uint64, balance uint64, toSeed trinary.Trytes, toIndex uint64, value uint64) []trinary.Trytes {
 
 	_, powFunc := pow.GetFastestProofOfWorkImpl()
 	iotaAPI, err := api.ComposeAPI(api.HTTPClientSettings{
@@ -0,0 +1,61 @@
+package tipselect
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+)
+
+// PromotionAction is the action a wallet should take to get a pending transfer confirmed.
+type PromotionAction string
+
+const (
+	// PromotionActionNone is returned if the transfer was already referenced by a milestone, or its tail is still non-lazy.
+	PromotionActionNone PromotionAction = "none"
+	// PromotionActionPromote is returned if the tail is semi-lazy, so that a transaction approving it and a non-lazy tip
+	// makes it selectable again.
+	PromotionActionPromote PromotionAction = "promote"
+	// PromotionActionReattach is returned if the tail is lazy (below max depth) or not solid,
+	// which means that it will not be referenced anymore and the bundle has to be attached again.
+	PromotionActionReattach PromotionAction = "reattach"
+)
+
+var (
+	// ErrNotATailTransaction is returned if the promotion state of a transaction which is not a tail was requested.
+	ErrNotATailTransaction = errors.New("transaction is not a tail")
+)
+
+// GetPromotionAction determines whether the bundle with the given tail needs a promotion or a reattachment
+// to get confirmed, based on its confirmation state and its root snapshot indexes.
+func (ts *TipSelector) GetPromotionAction(tailTxHash hornet.Hash) (PromotionAction, error) {
+	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(tailTxHash) // meta +1
+	if cachedTxMeta == nil {
+		return "", fmt.Errorf("%w: %s", tangle.ErrTransactionNotFound, tailTxHash.Trytes())
+	}
+	defer cachedTxMeta.Release(true) // meta -1
+
+	metadata := cachedTxMeta.GetMetadata()
+	if !metadata.IsTail() {
+		return "", fmt.Errorf("%w: %s", ErrNotATailTransaction, tailTxHash.Trytes())
+	}
+
+	if metadata.IsConfirmed() {
+		// conflicting transfers can't be fixed by promoting or reattaching either
+		return PromotionActionNone, nil
+	}
+
+	if !metadata.IsSolid() {
+		return PromotionActionReattach, nil
+	}
+
+	switch score, _ := ts.calculateScoreOfMetadata(cachedTxMeta, tangle.GetSolidMilestoneIndex()); score {
+	case ScoreLazy:
+		return PromotionActionReattach, nil
+	case ScoreSemiLazy:
+		return PromotionActionPromote, nil
+	default:
+		return PromotionActionNone, nil
+	}
+}
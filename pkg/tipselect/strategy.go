@@ -0,0 +1,144 @@
+package tipselect
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+const (
+	// StrategyUniformRandom picks every tip of the pool with the same probability.
+	StrategyUniformRandom = "uniform"
+	// StrategyAgeWeighted prefers tips which are in the pool for a longer time,
+	// so that they get referenced before they become lazy.
+	StrategyAgeWeighted = "ageWeighted"
+	// StrategyBelowMaxDepth prefers tips whose OTRSI is far away from the below max depth.
+	StrategyBelowMaxDepth = "belowMaxDepth"
+)
+
+// Strategy picks a single tip out of a tip pool.
+type Strategy interface {
+	// Name returns the name of the strategy.
+	Name() string
+	// PickTip picks a tip out of the given tips map.
+	// the tips map is locked by the caller and must not be modified.
+	PickTip(tipsMap map[string]*Tip) (*Tip, error)
+}
+
+// NewStrategy creates the tip selection strategy with the given name.
+func NewStrategy(name string, belowMaxDepth int) (Strategy, error) {
+	switch name {
+	case StrategyUniformRandom:
+		return &uniformRandomStrategy{}, nil
+	case StrategyAgeWeighted:
+		return &ageWeightedStrategy{}, nil
+	case StrategyBelowMaxDepth:
+		return &belowMaxDepthStrategy{belowMaxDepth: milestone.Index(belowMaxDepth)}, nil
+	default:
+		return nil, fmt.Errorf("unknown tip selection strategy: %s", name)
+	}
+}
+
+// pickWeightedTip picks a random tip out of the tips map according to the weight of the tips.
+// tips with a weight of zero or less are never picked.
+func pickWeightedTip(tipsMap map[string]*Tip, weight func(tip *Tip) int64) (*Tip, error) {
+
+	tips := make([]*Tip, 0, len(tipsMap))
+	weights := make([]int64, 0, len(tipsMap))
+
+	var totalWeight int64
+	for _, tip := range tipsMap {
+		tipWeight := weight(tip)
+		if tipWeight <= 0 {
+			continue
+		}
+		tips = append(tips, tip)
+		weights = append(weights, tipWeight)
+		totalWeight += tipWeight
+	}
+
+	if totalWeight == 0 {
+		return nil, ErrNoTipsAvailable
+	}
+
+	// get a random number between 0 and the total weight-1
+	// and subtract the weight of each tip until it is below zero
+	randWeight := utils.RandomInsecureInt64(0, totalWeight-1)
+	for i, tip := range tips {
+		randWeight -= weights[i]
+		if randWeight < 0 {
+			return tip, nil
+		}
+	}
+
+	return nil, ErrNoTipsAvailable
+}
+
+// uniformRandomStrategy picks every tip of the pool with the same probability.
+type uniformRandomStrategy struct{}
+
+func (s *uniformRandomStrategy) Name() string {
+	return StrategyUniformRandom
+}
+
+func (s *uniformRandomStrategy) PickTip(tipsMap map[string]*Tip) (*Tip, error) {
+
+	if len(tipsMap) == 0 {
+		// no semi-/non-lazy tips available
+		return nil, ErrNoTipsAvailable
+	}
+
+	// get a random number between 0 and the amount of tips-1
+	randTip := utils.RandomInsecure(0, len(tipsMap)-1)
+
+	// iterate over the tipsMap and subtract each tip from randTip
+	for _, tip := range tipsMap {
+		// subtract the tip from randTip
+		randTip--
+
+		// if randTip is below zero, we return the given tip
+		if randTip < 0 {
+			return tip, nil
+		}
+	}
+
+	// no tips
+	return nil, ErrNoTipsAvailable
+}
+
+// ageWeightedStrategy picks tips with a probability proportional to the time they are in the pool.
+type ageWeightedStrategy struct{}
+
+func (s *ageWeightedStrategy) Name() string {
+	return StrategyAgeWeighted
+}
+
+func (s *ageWeightedStrategy) PickTip(tipsMap map[string]*Tip) (*Tip, error) {
+	now := time.Now()
+
+	return pickWeightedTip(tipsMap, func(tip *Tip) int64 {
+		// every tip gets at least a weight of 1, so that new tips are also picked
+		return int64(now.Sub(tip.TimeAdded)/time.Millisecond) + 1
+	})
+}
+
+// belowMaxDepthStrategy picks tips with a probability proportional to the
+// amount of milestones left until their OTRSI would be below max depth.
+type belowMaxDepthStrategy struct {
+	belowMaxDepth milestone.Index
+}
+
+func (s *belowMaxDepthStrategy) Name() string {
+	return StrategyBelowMaxDepth
+}
+
+func (s *belowMaxDepthStrategy) PickTip(tipsMap map[string]*Tip) (*Tip, error) {
+	lsmi := tangle.GetSolidMilestoneIndex()
+
+	return pickWeightedTip(tipsMap, func(tip *Tip) int64 {
+		return int64(s.belowMaxDepth) - (int64(lsmi) - int64(tip.OldestRootSnapshotIndex)) + 1
+	})
+}
@@ -0,0 +1,100 @@
+package tipselect
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+)
+
+const strategyTestPicks = 3000
+
+// newTestTips creates a tips map with a tip for every given name.
+func newTestTips(names ...string) map[string]*Tip {
+	tips := make(map[string]*Tip, len(names))
+	for _, name := range names {
+		tips[name] = &Tip{Hash: hornet.Hash(name), TimeAdded: time.Now()}
+	}
+	return tips
+}
+
+// pickTips picks a tip with the given strategy strategyTestPicks times and returns how often each tip was picked.
+func pickTips(t *testing.T, strategy Strategy, tips map[string]*Tip) map[string]int {
+	picked := make(map[string]int)
+	for i := 0; i < strategyTestPicks; i++ {
+		tip, err := strategy.PickTip(tips)
+		require.NoError(t, err)
+		picked[string(tip.Hash)]++
+	}
+	return picked
+}
+
+func TestNewStrategy(t *testing.T) {
+	for _, name := range []string{StrategyUniformRandom, StrategyAgeWeighted, StrategyBelowMaxDepth} {
+		strategy, err := NewStrategy(name, 15)
+		require.NoError(t, err)
+		assert.Equal(t, name, strategy.Name())
+	}
+
+	_, err := NewStrategy("heaviest", 15)
+	assert.Error(t, err)
+}
+
+func TestUniformRandomStrategy(t *testing.T) {
+	strategy, err := NewStrategy(StrategyUniformRandom, 15)
+	require.NoError(t, err)
+
+	_, err = strategy.PickTip(newTestTips())
+	assert.True(t, errors.Is(err, ErrNoTipsAvailable))
+
+	// every tip is picked about a third of the time
+	picked := pickTips(t, strategy, newTestTips("A", "B", "C"))
+	for _, name := range []string{"A", "B", "C"} {
+		assert.Greater(t, picked[name], strategyTestPicks/4, "tip %s", name)
+	}
+}
+
+func TestAgeWeightedStrategy(t *testing.T) {
+	strategy, err := NewStrategy(StrategyAgeWeighted, 15)
+	require.NoError(t, err)
+
+	_, err = strategy.PickTip(newTestTips())
+	assert.True(t, errors.Is(err, ErrNoTipsAvailable))
+
+	// the old tip has a weight of about 10000, the new tip is still picked sometimes
+	tips := newTestTips("old", "new")
+	tips["old"].TimeAdded = time.Now().Add(-10 * time.Second)
+
+	picked := pickTips(t, strategy, tips)
+	assert.Greater(t, picked["old"], strategyTestPicks*95/100)
+	assert.Equal(t, strategyTestPicks, picked["old"]+picked["new"])
+}
+
+func TestBelowMaxDepthStrategy(t *testing.T) {
+	tangle.ResetMilestoneIndexes()
+	defer tangle.ResetMilestoneIndexes()
+	tangle.SetSolidMilestoneIndex(20, false)
+
+	strategy, err := NewStrategy(StrategyBelowMaxDepth, 15)
+	require.NoError(t, err)
+
+	// "fresh" has a weight of 16, "edge" is one milestone away from below max depth with a weight of 1,
+	// "below" is below max depth and is never picked
+	tips := newTestTips("fresh", "edge", "below")
+	tips["fresh"].OldestRootSnapshotIndex = 20
+	tips["edge"].OldestRootSnapshotIndex = 5
+	tips["below"].OldestRootSnapshotIndex = 4
+
+	picked := pickTips(t, strategy, tips)
+	assert.Greater(t, picked["fresh"], strategyTestPicks*85/100)
+	assert.NotZero(t, picked["edge"])
+	assert.Zero(t, picked["below"])
+
+	_, err = strategy.PickTip(map[string]*Tip{"below": tips["below"]})
+	assert.True(t, errors.Is(err, ErrNoTipsAvailable))
+}
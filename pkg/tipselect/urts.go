@@ -15,7 +15,6 @@ import (
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
-	"github.com/gohornet/hornet/pkg/utils"
 )
 
 // Score defines the score of a tip.
@@ -54,6 +53,8 @@ var (
 	ErrNoTipsAvailable = errors.New("no tips available")
 	// ErrTipLazy is returned when the choosen tip was lazy already.
 	ErrTipLazy = errors.New("tip already lazy")
+	// ErrInvalidTipCount is returned when less than one tip is requested.
+	ErrInvalidTipCount = errors.New("invalid tip count")
 )
 
 // Tip defines a tip.
@@ -66,6 +67,10 @@ type Tip struct {
 	TimeFirstApprover time.Time
 	// ApproversCount is the amount the tip was referenced by other transactions.
 	ApproversCount *atomic.Uint32
+	// TimeAdded is the timestamp the tip was added to the tip pool.
+	TimeAdded time.Time
+	// OldestRootSnapshotIndex is the OTRSI of the tip at the time its score was calculated.
+	OldestRootSnapshotIndex milestone.Index
 }
 
 // Events represents events happening on the tip-selector.
@@ -119,6 +124,8 @@ type TipSelector struct {
 	// spammerTipsThresholdSemiLazy is the maximum amount of tips in a tip-pool before the spammer tries to reduce these (0 = disable)
 	// this is used to support the network if someone attacks the tangle by spamming a lot of tips. (semi-lazy pool)
 	spammerTipsThresholdSemiLazy int
+	// strategy is used to pick the tips out of the tip pools.
+	strategy Strategy
 	// nonLazyTipsMap contains only non-lazy tips.
 	nonLazyTipsMap map[string]*Tip
 	// semiLazyTipsMap contains only semi-lazy tips.
@@ -140,7 +147,8 @@ func New(maxDeltaTxYoungestRootSnapshotIndexToLSMI int,
 	retentionRulesTipsLimitSemiLazy int,
 	maxReferencedTipAgeSecondsSemiLazy time.Duration,
 	maxApproversSemiLazy uint32,
-	spammerTipsThresholdSemiLazy int) *TipSelector {
+	spammerTipsThresholdSemiLazy int,
+	strategy Strategy) *TipSelector {
 
 	return &TipSelector{
 		maxDeltaTxYoungestRootSnapshotIndexToLSMI: milestone.Index(maxDeltaTxYoungestRootSnapshotIndexToLSMI),
@@ -154,6 +162,7 @@ func New(maxDeltaTxYoungestRootSnapshotIndexToLSMI int,
 		maxReferencedTipAgeSecondsSemiLazy:        maxReferencedTipAgeSecondsSemiLazy,
 		maxApproversSemiLazy:                      maxApproversSemiLazy,
 		spammerTipsThresholdSemiLazy:              spammerTipsThresholdSemiLazy,
+		strategy:                                  strategy,
 		nonLazyTipsMap:                            make(map[string]*Tip),
 		semiLazyTipsMap:                           make(map[string]*Tip),
 		Events: Events{
@@ -183,7 +192,7 @@ func (ts *TipSelector) AddTip(bndl *tangle.Bundle) {
 
 	lsmi := tangle.GetSolidMilestoneIndex()
 
-	score := ts.calculateScore(tailTxHash, lsmi)
+	score, otrsi := ts.calculateScore(tailTxHash, lsmi)
 	if score == ScoreLazy {
 		// do not add lazy tips.
 		// lazy tips should also not remove other tips from the pool, otherwise the tip pool will run empty.
@@ -191,10 +200,12 @@ func (ts *TipSelector) AddTip(bndl *tangle.Bundle) {
 	}
 
 	tip := &Tip{
-		Score:             score,
-		Hash:              tailTxHash,
-		TimeFirstApprover: time.Time{},
-		ApproversCount:    atomic.NewUint32(0),
+		Score:                   score,
+		Hash:                    tailTxHash,
+		TimeFirstApprover:       time.Time{},
+		ApproversCount:          atomic.NewUint32(0),
+		TimeAdded:               time.Now(),
+		OldestRootSnapshotIndex: otrsi,
 	}
 
 	switch tip.Score {
@@ -269,91 +280,101 @@ func (ts *TipSelector) removeTipWithoutLocking(tipsMap map[string]*Tip, tailTxHa
 	return false
 }
 
-// randomTipWithoutLocking picks a random tip from the pool and checks it's "own" score again without acquiring the lock.
-func (ts *TipSelector) randomTipWithoutLocking(tipsMap map[string]*Tip) (hornet.Hash, error) {
-
-	if len(tipsMap) == 0 {
-		// no semi-/non-lazy tips available
-		return nil, ErrNoTipsAvailable
-	}
-
-	// get a random number between 0 and the amount of tips-1
-	randTip := utils.RandomInsecure(0, len(tipsMap)-1)
-
-	// iterate over the tipsMap and subtract each tip from randTip
-	for _, tip := range tipsMap {
-		// subtract the tip from randTip
-		randTip--
-
-		// if randTip is below zero, we return the given tip
-		if randTip < 0 {
-			return tip.Hash, nil
-		}
+// pickTipWithoutLocking picks a tip from the pool by using the configured strategy without acquiring the lock.
+func (ts *TipSelector) pickTipWithoutLocking(tipsMap map[string]*Tip) (hornet.Hash, error) {
+	tip, err := ts.strategy.PickTip(tipsMap)
+	if err != nil {
+		return nil, err
 	}
-
-	// no tips
-	return nil, ErrNoTipsAvailable
+	return tip.Hash, nil
 }
 
 // selectTipWithoutLocking selects a tip.
 func (ts *TipSelector) selectTipWithoutLocking(tipsMap map[string]*Tip) (hornet.Hash, error) {
 
-	if !tangle.IsNodeSyncedWithThreshold() {
+	if !tangle.IsNodeAlmostSynced() {
 		return nil, tangle.ErrNodeNotSynced
 	}
 
 	// record stats
 	start := time.Now()
 
-	tipHash, err := ts.randomTipWithoutLocking(tipsMap)
+	tipHash, err := ts.pickTipWithoutLocking(tipsMap)
 	ts.Events.TipSelPerformed.Trigger(&TipSelStats{Duration: time.Since(start)})
 
 	return tipHash, err
 }
 
-// SelectTips selects two tips.
-func (ts *TipSelector) selectTips(tipsMap map[string]*Tip) (hornet.Hashes, error) {
+// selectTips selects count tips out of the given tips map.
+// if there are not enough distinct tips available, the first tip is used again.
+func (ts *TipSelector) selectTips(tipsMap map[string]*Tip, count int) (hornet.Hashes, error) {
+	if count < 1 {
+		return nil, ErrInvalidTipCount
+	}
+
 	tips := hornet.Hashes{}
 
 	ts.tipsLock.Lock()
 	defer ts.tipsLock.Unlock()
 
-	trunk, err := ts.selectTipWithoutLocking(tipsMap)
+	first, err := ts.selectTipWithoutLocking(tipsMap)
 	if err != nil {
 		return nil, err
 	}
-	tips = append(tips, trunk)
+	tips = append(tips, first)
 
-	// retry the tipselection several times if trunk and branch are equal
-	for i := 0; i < 10; i++ {
-		branch, err := ts.selectTipWithoutLocking(tipsMap)
-		if err != nil {
-			if err == ErrNoTipsAvailable {
-				// do not search other tips if there are none
+	containsTip := func(tipHash hornet.Hash) bool {
+		for _, tip := range tips {
+			if bytes.Equal(tip, tipHash) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for len(tips) < count {
+		found := false
+
+		// retry the tipselection several times if the tip was already selected
+		for i := 0; i < 10; i++ {
+			tipHash, err := ts.selectTipWithoutLocking(tipsMap)
+			if err != nil {
+				if err == ErrNoTipsAvailable {
+					// do not search other tips if there are none
+					break
+				}
+				return nil, err
+			}
+
+			if !containsTip(tipHash) {
+				tips = append(tips, tipHash)
+				found = true
 				break
 			}
-			return nil, err
 		}
 
-		if !bytes.Equal(trunk, branch) {
-			tips = append(tips, branch)
-			return tips, nil
+		if !found {
+			// no other tip found, use the first one again
+			tips = append(tips, first)
 		}
 	}
 
-	// no second tip found, use the same again
-	tips = append(tips, trunk)
 	return tips, nil
 }
 
+// SelectTips selects count non-lazy tips.
+func (ts *TipSelector) SelectTips(count int) (hornet.Hashes, error) {
+	return ts.selectTips(ts.nonLazyTipsMap, count)
+}
+
 // SelectSemiLazyTips selects two semi-lazy tips.
 func (ts *TipSelector) SelectSemiLazyTips() (hornet.Hashes, error) {
-	return ts.selectTips(ts.semiLazyTipsMap)
+	return ts.selectTips(ts.semiLazyTipsMap, 2)
 }
 
 // SelectNonLazyTips selects two non-lazy tips.
 func (ts *TipSelector) SelectNonLazyTips() (hornet.Hashes, error) {
-	return ts.selectTips(ts.nonLazyTipsMap)
+	return ts.selectTips(ts.nonLazyTipsMap, 2)
 }
 
 func (ts *TipSelector) SelectSpammerTips() (isSemiLazy bool, tips hornet.Hashes, err error) {
@@ -420,6 +441,66 @@ func (ts *TipSelector) CleanUpReferencedTips() int {
 	return count
 }
 
+// tipHashes returns the hashes of all tips in the non-lazy and semi-lazy tip pools.
+func (ts *TipSelector) tipHashes() hornet.Hashes {
+	ts.tipsLock.Lock()
+	defer ts.tipsLock.Unlock()
+
+	tipHashes := make(hornet.Hashes, 0, len(ts.nonLazyTipsMap)+len(ts.semiLazyTipsMap))
+	for _, tip := range ts.nonLazyTipsMap {
+		tipHashes = append(tipHashes, tip.Hash)
+	}
+	for _, tip := range ts.semiLazyTipsMap {
+		tipHashes = append(tipHashes, tip.Hash)
+	}
+	return tipHashes
+}
+
+// UpdateRootSnapshotIndexes recalculates the stale root snapshot indexes of all known tips for the current LSMI.
+// The tips are processed in batches of the given size, and the given delay is applied between the batches
+// to limit the load on the node. The calculation stops early if a newer milestone got solid in the meantime,
+// since the calculated indexes would be outdated anyway. Returns the amount of processed tips.
+func (ts *TipSelector) UpdateRootSnapshotIndexes(batchSize int, batchDelay time.Duration, abortSignal <-chan struct{}) (int, error) {
+
+	lsmi := tangle.GetSolidMilestoneIndex()
+	tipHashes := ts.tipHashes()
+
+	if batchSize <= 0 {
+		batchSize = len(tipHashes)
+	}
+
+	processed := 0
+	for start := 0; start < len(tipHashes); start += batchSize {
+		if start > 0 {
+			select {
+			case <-abortSignal:
+				return processed, tangle.ErrOperationAborted
+			case <-time.After(batchDelay):
+			}
+
+			if tangle.GetSolidMilestoneIndex() != lsmi {
+				// a newer milestone got solid, the indexes have to be calculated again anyway
+				return processed, nil
+			}
+		}
+
+		end := start + batchSize
+		if end > len(tipHashes) {
+			end = len(tipHashes)
+		}
+
+		cachedTxMetas := tangle.GetCachedTxMetadataBatch(tipHashes[start:end]) // meta +1
+		for _, cachedTxMeta := range cachedTxMetas {
+			// calculates and stores the indexes if the calculation index doesn't match the LSMI
+			dag.GetTransactionRootSnapshotIndexes(cachedTxMeta.Retain(), lsmi) // meta +1
+			processed++
+		}
+		cachedTxMetas.Release(true) // meta -1
+	}
+
+	return processed, nil
+}
+
 // UpdateScores updates the scores of the tips and removes lazy ones.
 func (ts *TipSelector) UpdateScores() int {
 
@@ -428,10 +509,22 @@ func (ts *TipSelector) UpdateScores() int {
 
 	lsmi := tangle.GetSolidMilestoneIndex()
 
+	tipHashes := make(hornet.Hashes, 0, len(ts.nonLazyTipsMap)+len(ts.semiLazyTipsMap))
+	for _, tip := range ts.nonLazyTipsMap {
+		tipHashes = append(tipHashes, tip.Hash)
+	}
+	for _, tip := range ts.semiLazyTipsMap {
+		tipHashes = append(tipHashes, tip.Hash)
+	}
+
+	// load the metadata of all tips at once
+	cachedTxMetas := tangle.GetCachedTxMetadataBatch(tipHashes) // meta +1
+	defer cachedTxMetas.Release(true)                           // meta -1
+
 	count := 0
 	for _, tip := range ts.nonLazyTipsMap {
 		// check the score of the tip again to avoid old tips
-		tip.Score = ts.calculateScore(tip.Hash, lsmi)
+		tip.Score, tip.OldestRootSnapshotIndex = ts.calculateScoreOfMetadata(cachedTxMetas[string(tip.Hash)], lsmi)
 		if tip.Score == ScoreLazy {
 			// remove the tip from the pool because it is outdated
 			if ts.removeTipWithoutLocking(ts.nonLazyTipsMap, tip.Hash) {
@@ -457,7 +550,7 @@ func (ts *TipSelector) UpdateScores() int {
 
 	for _, tip := range ts.semiLazyTipsMap {
 		// check the score of the tip again to avoid old tips
-		tip.Score = ts.calculateScore(tip.Hash, lsmi)
+		tip.Score, tip.OldestRootSnapshotIndex = ts.calculateScoreOfMetadata(cachedTxMetas[string(tip.Hash)], lsmi)
 		if tip.Score == ScoreLazy {
 			// remove the tip from the pool because it is outdated
 			if ts.removeTipWithoutLocking(ts.semiLazyTipsMap, tip.Hash) {
@@ -484,18 +577,33 @@ func (ts *TipSelector) UpdateScores() int {
 	return count
 }
 
-// calculateScore calculates the tip selection score of this transaction
-func (ts *TipSelector) calculateScore(txHash hornet.Hash, lsmi milestone.Index) Score {
-	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(txHash) // meta +1
+// calculateScore calculates the tip selection score of this transaction and returns its OTRSI.
+// The root snapshot indexes are taken from the cache if possible, which avoids loading the metadata.
+func (ts *TipSelector) calculateScore(txHash hornet.Hash, lsmi milestone.Index) (Score, milestone.Index) {
+	ytrsi, ortsi, exists := dag.GetTransactionRootSnapshotIndexesByHash(txHash, lsmi)
+	if !exists {
+		return ScoreLazy, 0
+	}
+
+	return ts.scoreOfRootSnapshotIndexes(ytrsi, ortsi, lsmi), ortsi
+}
+
+// calculateScoreOfMetadata calculates the tip selection score of the transaction with the given metadata and returns its OTRSI.
+// The metadata is not released by this function.
+func (ts *TipSelector) calculateScoreOfMetadata(cachedTxMeta *tangle.CachedMetadata, lsmi milestone.Index) (Score, milestone.Index) {
 	if cachedTxMeta == nil {
 		// we need to return lazy instead of panic here, because the transaction could have been pruned already
 		// if the node was not sync for a longer time and after the pruning "UpdateScores" is called.
-		return ScoreLazy
+		return ScoreLazy, 0
 	}
-	defer cachedTxMeta.Release(true)
 
 	ytrsi, ortsi := dag.GetTransactionRootSnapshotIndexes(cachedTxMeta.Retain(), lsmi) // meta +1
 
+	return ts.scoreOfRootSnapshotIndexes(ytrsi, ortsi, lsmi), ortsi
+}
+
+// scoreOfRootSnapshotIndexes calculates the tip selection score of a transaction with the given root snapshot indexes.
+func (ts *TipSelector) scoreOfRootSnapshotIndexes(ytrsi milestone.Index, ortsi milestone.Index, lsmi milestone.Index) Score {
 	// if the LSMI to YTRSI delta is over MaxDeltaTxYoungestRootSnapshotIndexToLSMI, then the tip is lazy
 	if (lsmi - ytrsi) > ts.maxDeltaTxYoungestRootSnapshotIndexToLSMI {
 		return ScoreLazy
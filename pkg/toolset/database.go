@@ -0,0 +1,87 @@
+package toolset
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+)
+
+func databaseMigration(args []string) error {
+
+	if len(args) != 2 {
+		return errors.New("usage: 'db-migration [target engine] [target path]'")
+	}
+
+	sourcePath := config.NodeConfig.GetString(config.CfgDatabasePath)
+	sourceEngine, err := database.EngineFromString(config.NodeConfig.GetString(config.CfgDatabaseEngine))
+	if err != nil {
+		return err
+	}
+
+	targetEngine, err := database.EngineFromString(args[0])
+	if err != nil {
+		return err
+	}
+	targetPath := args[1]
+
+	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+		return fmt.Errorf("source database does not exist. %v", sourcePath)
+	}
+
+	if _, err := os.Stat(targetPath); !os.IsNotExist(err) {
+		return fmt.Errorf("target database already exists. %v", targetPath)
+	}
+
+	for _, name := range []string{tangle.TangleDbFilename, tangle.SnapshotDbFilename, tangle.SpentAddressesDbFilename} {
+		if err := copyDatabase(sourceEngine, sourcePath, targetEngine, targetPath, name); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("migrated the database from %s (%s) to %s (%s). set '%s' and '%s' to use it.\n", sourcePath, sourceEngine, targetPath, targetEngine, config.CfgDatabasePath, config.CfgDatabaseEngine)
+
+	return nil
+}
+
+func copyDatabase(sourceEngine database.Engine, sourcePath string, targetEngine database.Engine, targetPath string, name string) error {
+
+	source, err := database.New(sourceEngine, sourcePath, name)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := database.New(targetEngine, targetPath, name)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	fmt.Printf("copying %s...\n", name)
+
+	ts := time.Now()
+	lastStatusTime := time.Now()
+
+	copied, err := database.Copy(source.KVStore(), target.KVStore(), func(copied int) {
+		if time.Since(lastStatusTime) >= printStatusInterval {
+			lastStatusTime = time.Now()
+			fmt.Printf("copied %d entries of %s...\n", copied, name)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := target.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("copied %d entries of %s. took %v\n", copied, name, time.Since(ts).Truncate(time.Millisecond))
+
+	return nil
+}
@@ -0,0 +1,367 @@
+package toolset
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/model/coordinator"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/networkid"
+	snapshotFile "github.com/gohornet/hornet/pkg/snapshot"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+)
+
+// txMetadataInfo is the metadata of a transaction printed by the 'db-tx' tool.
+type txMetadataInfo struct {
+	Hash                 trinary.Hash                `json:"hash"`
+	BundleHash           trinary.Hash                `json:"bundleHash"`
+	Parents              []trinary.Hash              `json:"parents"`
+	IsTail               bool                        `json:"isTail"`
+	IsHead               bool                        `json:"isHead"`
+	IsValue              bool                        `json:"isValue"`
+	Solid                bool                        `json:"solid"`
+	Confirmed            bool                        `json:"confirmed"`
+	ConfirmationIndex    milestone.Index             `json:"confirmationIndex"`
+	LedgerInclusionState hornet.LedgerInclusionState `json:"ledgerInclusionState"`
+	ConflictReason       hornet.ConflictReason       `json:"conflictReason"`
+	IsMilestone          bool                        `json:"isMilestone"`
+	MilestoneIndex       milestone.Index             `json:"milestoneIndex"`
+	YoungestRootSnapshot milestone.Index             `json:"youngestRootSnapshotIndex"`
+	OldestRootSnapshot   milestone.Index             `json:"oldestRootSnapshotIndex"`
+	RootSnapshotCalc     milestone.Index             `json:"rootSnapshotCalculationIndex"`
+}
+
+// addressInfo is the state of an address printed by the 'db-address' tool.
+type addressInfo struct {
+	Address           trinary.Hash    `json:"address"`
+	Balance           uint64          `json:"balance"`
+	LedgerIndex       milestone.Index `json:"ledgerIndex"`
+	Spent             bool            `json:"spent"`
+	ValueTransactions []trinary.Hash  `json:"valueTransactions"`
+}
+
+// withDatabases opens the databases of the node, loads the snapshot info and the solid entry points,
+// and closes the databases after the given function returned.
+// The node must not be running, panics of the storage layer are returned as errors.
+func withDatabases(f func() error) (err error) {
+
+	path := config.NodeConfig.GetString(config.CfgDatabasePath)
+	engine, err := database.EngineFromString(config.NodeConfig.GetString(config.CfgDatabaseEngine))
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return fmt.Errorf("database does not exist. %v", path)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if recoveredErr, ok := r.(error); ok {
+				err = recoveredErr
+				return
+			}
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	tangle.ConfigureDatabases(path, engine)
+	defer func() {
+		tangle.ShutdownStorages()
+		if closeErr := tangle.CloseDatabases(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	tangle.LoadInitialValuesFromDatabase()
+
+	return f()
+}
+
+func printJSON(value interface{}) error {
+	output, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(output))
+	return nil
+}
+
+func databaseTransaction(args []string) error {
+
+	if len(args) != 1 {
+		return errors.New("usage: 'db-tx [transaction hash]'")
+	}
+
+	txHash, err := hornet.HashFromTrytes(args[0])
+	if err != nil {
+		return err
+	}
+
+	return withDatabases(func() error {
+		cachedTxMeta := tangle.GetCachedTxMetadataOrNil(txHash) // meta +1
+		if cachedTxMeta == nil {
+			return fmt.Errorf("transaction not found. %v", args[0])
+		}
+		defer cachedTxMeta.Release(true) // meta -1
+
+		metadata := cachedTxMeta.GetMetadata()
+		confirmed, confirmationIndex := metadata.GetConfirmed()
+		isMilestone, milestoneIndex := metadata.GetMilestone()
+		yrtsi, ortsi, rtsci := metadata.GetRootSnapshotIndexes()
+
+		return printJSON(&txMetadataInfo{
+			Hash:                 metadata.GetTxHash().Trytes(),
+			BundleHash:           metadata.GetBundleHash().Trytes(),
+			Parents:              metadata.GetParents().Trytes(),
+			IsTail:               metadata.IsTail(),
+			IsHead:               metadata.IsHead(),
+			IsValue:              metadata.IsValue(),
+			Solid:                metadata.IsSolid(),
+			Confirmed:            confirmed,
+			ConfirmationIndex:    confirmationIndex,
+			LedgerInclusionState: metadata.GetLedgerInclusionState(),
+			ConflictReason:       metadata.GetConflictReason(),
+			IsMilestone:          isMilestone,
+			MilestoneIndex:       milestoneIndex,
+			YoungestRootSnapshot: yrtsi,
+			OldestRootSnapshot:   ortsi,
+			RootSnapshotCalc:     rtsci,
+		})
+	})
+}
+
+func databaseAddress(args []string) error {
+
+	if len(args) != 1 {
+		return errors.New("usage: 'db-address [address]'")
+	}
+
+	address, err := hornet.AddressFromTrytes(args[0])
+	if err != nil {
+		return err
+	}
+
+	return withDatabases(func() error {
+		balance, ledgerIndex, err := tangle.Ledger().GetBalanceForAddress(address)
+		if err != nil {
+			return err
+		}
+
+		return printJSON(&addressInfo{
+			Address:           address.Trytes(),
+			Balance:           balance,
+			LedgerIndex:       ledgerIndex,
+			Spent:             tangle.WasAddressSpentFrom(address),
+			ValueTransactions: tangle.GetTransactionHashesForAddress(address, true, true).Trytes(),
+		})
+	})
+}
+
+func databaseVerifyLedger(args []string) error {
+
+	if len(args) > 0 {
+		return errors.New("too many arguments for 'db-verify-ledger'")
+	}
+
+	return withDatabases(func() error {
+		snapshotBalances, snapshotIndex, err := tangle.GetAllSnapshotBalances(nil)
+		if err != nil {
+			return err
+		}
+
+		ledger := tangle.Ledger()
+		ledgerIndex := ledger.MilestoneIndex()
+
+		ledger.RLock()
+		defer ledger.RUnlock()
+
+		// the balances of the ledger milestone index are rolled back to the snapshot index
+		balances, err := ledger.VerifyLedgerStateWithoutLocking(ledgerIndex, snapshotBalances, snapshotIndex, nil)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("the ledger state of milestone %d is valid (%d addresses), the ledger diffs match the snapshot balances of milestone %d.\n", ledgerIndex, len(balances), snapshotIndex)
+		return nil
+	})
+}
+
+func databaseVerifyTangle(args []string) error {
+
+	if len(args) > 0 {
+		return errors.New("too many arguments for 'db-verify-tangle'")
+	}
+
+	// the merkle tree hashes of the milestones are recomputed, the signatures of the stored milestones were already checked
+	milestoneMerkleHashFunc, err := coordinator.MilestoneMerkleTreeHashFuncWithName(config.NodeConfig.GetString(config.CfgCoordinatorMilestoneMerkleTreeHashFunc))
+	if err != nil {
+		return err
+	}
+
+	tangle.ConfigureMilestones(
+		tangle.NewMilestoneKeyManager(),
+		config.NodeConfig.GetInt(config.CfgCoordinatorSecurityLevel),
+		uint64(config.NodeConfig.GetInt(config.CfgCoordinatorMerkleTreeDepth)),
+		milestoneMerkleHashFunc,
+	)
+
+	return withDatabases(func() error {
+		balances, snapshotIndex, err := tangle.GetAllSnapshotBalances(nil)
+		if err != nil {
+			return err
+		}
+
+		ledger := tangle.Ledger()
+		ledgerIndex := ledger.MilestoneIndex()
+
+		ledger.RLock()
+		defer ledger.RUnlock()
+
+		// the confirmations are recomputed starting at the snapshot balances, so every milestone
+		// is verified against the ledger state resulting from the verified milestones before.
+		var tailsReferenced int
+		for msIndex := snapshotIndex + 1; msIndex <= ledgerIndex; msIndex++ {
+			mutations, err := whiteflag.VerifyConfirmedMilestone(msIndex, balances)
+			if err != nil {
+				return fmt.Errorf("first divergent milestone is %d: %w", msIndex, err)
+			}
+			tailsReferenced += len(mutations.TailsReferenced)
+
+			if (msIndex-snapshotIndex)%1000 == 0 {
+				fmt.Printf("verified milestone %d/%d\n", msIndex, ledgerIndex)
+			}
+		}
+
+		ledgerBalances, _, err := ledger.GetLedgerStateForLSMIWithoutLocking(nil)
+		if err != nil {
+			return err
+		}
+
+		for address, balance := range ledgerBalances {
+			if balances[address] != balance {
+				return fmt.Errorf("the ledger state of milestone %d diverges: computed balance of address %s is %d, the ledger contains %d", ledgerIndex, hornet.Hash(address).Trytes(), balances[address], balance)
+			}
+		}
+		if len(balances) != len(ledgerBalances) {
+			return fmt.Errorf("the ledger state of milestone %d diverges: computed %d addresses, the ledger contains %d", ledgerIndex, len(balances), len(ledgerBalances))
+		}
+
+		fmt.Printf("the confirmations of the milestones %d to %d are consistent (%d bundles), the ledger state matches the snapshot balances of milestone %d.\n", snapshotIndex+1, ledgerIndex, tailsReferenced, snapshotIndex)
+		return nil
+	})
+}
+
+func databaseExportSnapshot(args []string) error {
+
+	if len(args) != 1 {
+		return errors.New("usage: 'db-snapshot-export [file path]'")
+	}
+
+	filePath := args[0]
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		return fmt.Errorf("snapshot file already exists. %v", filePath)
+	}
+
+	return withDatabases(func() error {
+		snapshotInfo := tangle.GetSnapshotInfo()
+		if snapshotInfo == nil {
+			return errors.New("snapshot info not found in the database")
+		}
+
+		balances, balancesIndex, err := tangle.GetAllSnapshotBalances(nil)
+		if err != nil {
+			return err
+		}
+
+		if balancesIndex != snapshotInfo.SnapshotIndex {
+			return fmt.Errorf("snapshot balances belong to milestone %d, the snapshot index is %d", balancesIndex, snapshotInfo.SnapshotIndex)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+			return err
+		}
+
+		exportFile, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0660)
+		if err != nil {
+			return err
+		}
+		defer exportFile.Close()
+
+		hash, err := writeSnapshotFile(exportFile, snapshotInfo, balances)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("exported the local snapshot of milestone %d to %s (sha256: %x).\n", snapshotInfo.SnapshotIndex, filePath, hash)
+		return nil
+	})
+}
+
+// writes the solid entry points, the balances and the spent addresses of the snapshot stored in the database
+// into a local snapshot file, followed by the sha256 hash of the file.
+func writeSnapshotFile(exportFile *os.File, snapshotInfo *tangle.SnapshotInfo, balances map[string]uint64) ([]byte, error) {
+
+	lsWriter, err := snapshotFile.NewFileWriter(exportFile, &snapshotFile.FileHeader{
+		MilestoneHash:  snapshotInfo.Hash,
+		MilestoneIndex: snapshotInfo.SnapshotIndex,
+		Timestamp:      snapshotInfo.Timestamp,
+		NetworkID:      networkid.FromConfig(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tangle.ForEachSolidEntryPoint(func(txHash hornet.Hash, index milestone.Index) bool {
+		err = lsWriter.WriteSolidEntryPoint(txHash, index)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for address, balance := range balances {
+		if err := lsWriter.WriteLedgerEntry(hornet.Hash(address), balance); err != nil {
+			return nil, err
+		}
+	}
+
+	if snapshotInfo.IsSpentAddressesEnabled() {
+		if _, err := tangle.StreamSpentAddressesToConsumer(lsWriter.WriteSpentAddress, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// flush the records and write the record counts into the header
+	if err := lsWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	if _, err := exportFile.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	lsHash := sha256.New()
+	if _, err := io.Copy(lsHash, exportFile); err != nil {
+		return nil, err
+	}
+
+	sha256Hash := lsHash.Sum(nil)
+	if err := binary.Write(exportFile, binary.LittleEndian, sha256Hash); err != nil {
+		return nil, err
+	}
+
+	return sha256Hash, nil
+}
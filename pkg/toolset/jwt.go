@@ -0,0 +1,29 @@
+package toolset
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gohornet/hornet/pkg/jwt"
+)
+
+func issueAPIJWT(args []string) error {
+
+	if len(args) > 0 {
+		return errors.New("too many arguments for 'jwt-api'")
+	}
+
+	jwtAuth, err := jwt.NewAPIAuthFromConfig()
+	if err != nil {
+		return err
+	}
+
+	token, err := jwtAuth.IssueJWT()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Your API JWT: ", token)
+
+	return nil
+}
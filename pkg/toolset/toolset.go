@@ -8,10 +8,17 @@ import (
 
 var (
 	tools = map[string]func([]string) error{
-		"pwdhash": hashPasswordAndSalt,
-		"seedgen": seedGen,
-		"list":    listTools,
-		"merkle":  merkleTreeCreate,
+		"pwdhash":            hashPasswordAndSalt,
+		"seedgen":            seedGen,
+		"list":               listTools,
+		"merkle":             merkleTreeCreate,
+		"jwt-api":            issueAPIJWT,
+		"db-migration":       databaseMigration,
+		"db-tx":              databaseTransaction,
+		"db-address":         databaseAddress,
+		"db-verify-ledger":   databaseVerifyLedger,
+		"db-verify-tangle":   databaseVerifyTangle,
+		"db-snapshot-export": databaseExportSnapshot,
 	}
 )
 
@@ -58,6 +65,13 @@ func listTools(args []string) error {
 	fmt.Println("pwdhash: generates a sha265 sum from your password and salt")
 	fmt.Println("seedgen: generates an autopeering seed")
 	fmt.Println("merkle: generates a Merkle tree for coordinator plugin")
+	fmt.Println("jwt-api: issues a JWT for the HTTP API and the dashboard")
+	fmt.Println("db-migration: copies the database to another database engine (or compacts it, if the engine is the same)")
+	fmt.Println("db-tx: prints the metadata of a transaction stored in the database")
+	fmt.Println("db-address: prints the balance, the spent state and the value transactions of an address stored in the database")
+	fmt.Println("db-verify-ledger: verifies the ledger state of the database against the ledger diffs and the snapshot balances")
+	fmt.Println("db-verify-tangle: recomputes the white-flag confirmations of all milestones and reports the first milestone which diverges from the stored ledger and metadata")
+	fmt.Println("db-snapshot-export: exports the local snapshot stored in the database to a local snapshot file")
 
 	return nil
 }
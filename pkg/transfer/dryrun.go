@@ -0,0 +1,104 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+var (
+	// ErrConflictingBundle is returned if an input of a bundle is also spent by another bundle which is not confirmed yet.
+	// Only one of the bundles can be confirmed.
+	ErrConflictingBundle = errors.New("conflicting bundle")
+)
+
+// PendingLedger is a Ledger which also knows the bundles which are not confirmed yet.
+type PendingLedger interface {
+	Ledger
+	// PendingSpends returns the hashes of the bundles, which are not confirmed yet, that spend from the address.
+	PendingSpends(address hornet.Hash) ([]trinary.Hash, error)
+}
+
+// AddressChange is the simulated change of the balance of an address by a bundle.
+type AddressChange struct {
+	Address trinary.Hash
+	// Balance is the confirmed balance of the address before the bundle is applied.
+	Balance uint64
+	Change  int64
+	// Spent is whether funds were already moved from the address.
+	Spent bool
+	// ConflictingBundles are the hashes of the other unconfirmed bundles which spend from the address.
+	ConflictingBundles []trinary.Hash
+}
+
+// DryRunResult is the result of simulating the application of a bundle to the ledger.
+type DryRunResult struct {
+	BundleHash trinary.Hash
+	// Changes are the balance changes of the addresses, in the order they appear in the bundle.
+	Changes []*AddressChange
+	// Err is the reason why the bundle can't be confirmed, nil if it can be applied to the ledger.
+	Err error
+}
+
+// DryRun simulates the application of the given signed bundle to the ledger without changing it.
+// The validation errors of the bundle are returned in the result, the error is only set if the bundle couldn't be read.
+func DryRun(ledger PendingLedger, bundleTrytes []trinary.Trytes) (*DryRunResult, error) {
+	b, err := parseBundle(bundleTrytes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DryRunResult{BundleHash: b[0].Bundle}
+
+	changes := make(map[trinary.Hash]*AddressChange)
+	for i := range b {
+		change, exists := changes[b[i].Address]
+		if !exists {
+			change = &AddressChange{Address: b[i].Address}
+			changes[b[i].Address] = change
+			result.Changes = append(result.Changes, change)
+		}
+		change.Change += b[i].Value
+	}
+
+	for _, change := range result.Changes {
+		addrHash := hornet.HashFromAddressTrytes(change.Address)
+
+		if change.Balance, err = ledger.Balance(addrHash); err != nil {
+			return nil, err
+		}
+		change.Spent = ledger.WasSpent(addrHash)
+
+		if change.Change >= 0 {
+			continue
+		}
+
+		bundleHashes, err := ledger.PendingSpends(addrHash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, bundleHash := range bundleHashes {
+			// reattachments of the same bundle don't conflict with each other
+			if bundleHash != result.BundleHash {
+				change.ConflictingBundles = append(change.ConflictingBundles, bundleHash)
+			}
+		}
+	}
+
+	if result.Err = Validate(ledger, b); result.Err != nil {
+		return result, nil
+	}
+
+	for _, change := range result.Changes {
+		if len(change.ConflictingBundles) > 0 {
+			result.Err = fmt.Errorf("%w: input %s is also spent by bundle %s", ErrConflictingBundle, change.Address, change.ConflictingBundles[0])
+			break
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,455 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/iotaledger/iota.go/address"
+	"github.com/iotaledger/iota.go/bundle"
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/guards"
+	"github.com/iotaledger/iota.go/signing"
+	"github.com/iotaledger/iota.go/transaction"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+var (
+	// ErrInvalidAddress is returned if an address is not valid or its checksum doesn't match.
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrInvalidSecurityLevel is returned if the security level of an input is not 1, 2 or 3.
+	ErrInvalidSecurityLevel = errors.New("invalid security level")
+	// ErrInvalidTag is returned if the tag of an output is not valid.
+	ErrInvalidTag = errors.New("invalid tag")
+	// ErrInvalidMessage is returned if the message of an output is not valid.
+	ErrInvalidMessage = errors.New("invalid message")
+	// ErrNoOutputs is returned if a transfer has no outputs.
+	ErrNoOutputs = errors.New("no outputs given")
+	// ErrNoInputs is returned if a transfer which moves funds has no inputs.
+	ErrNoInputs = errors.New("no inputs given")
+	// ErrDuplicateInput is returned if an address is used more than once as an input.
+	ErrDuplicateInput = errors.New("duplicate input address")
+	// ErrInputIsOutput is returned if an input address is also used as an output address.
+	ErrInputIsOutput = errors.New("input address is used as output address")
+	// ErrInputAlreadySpent is returned if funds are moved from an address which was already spent from.
+	// Signing again with the same key would reveal more of the private key.
+	ErrInputAlreadySpent = errors.New("input address was already spent from")
+	// ErrInsufficientBalance is returned if the inputs don't cover the outputs, or an input moves more than its balance.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrRemainderAddressMissing is returned if the inputs exceed the outputs, but no remainder address was given.
+	ErrRemainderAddressMissing = errors.New("remainder address missing")
+	// ErrInvalidBundle is returned if the transactions of a bundle are not a valid bundle.
+	ErrInvalidBundle = errors.New("invalid bundle")
+	// ErrMissingSignature is returned if no signature was given for an input of a bundle.
+	ErrMissingSignature = errors.New("missing signature")
+	// ErrInvalidSignature is returned if the signature of an input doesn't match the input address and the bundle hash.
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Ledger provides the confirmed state of the addresses a transfer is validated against.
+type Ledger interface {
+	// Balance returns the confirmed balance of the address.
+	Balance(address hornet.Hash) (uint64, error)
+	// WasSpent returns whether funds were already moved from the address.
+	WasSpent(address hornet.Hash) bool
+}
+
+// Input is an address whose whole balance is moved by a transfer.
+type Input struct {
+	Address trinary.Hash
+	// SecurityLevel is the security level of the address, which defines the number of signature fragments.
+	SecurityLevel consts.SecurityLevel
+}
+
+// Output is an address which receives funds or a message by a transfer.
+type Output struct {
+	Address trinary.Hash
+	Value   uint64
+	Tag     trinary.Trytes
+	Message trinary.Trytes
+}
+
+// Transfer describes the inputs and outputs of a value bundle.
+type Transfer struct {
+	Inputs  []*Input
+	Outputs []*Output
+	// RemainderAddress receives the funds of the inputs which are not needed for the outputs.
+	RemainderAddress trinary.Hash
+}
+
+// SignatureRequest describes the signature an input of a prepared bundle requires.
+type SignatureRequest struct {
+	Address       trinary.Hash
+	SecurityLevel consts.SecurityLevel
+	// Index is the index of the first transaction of the input in the bundle.
+	Index   uint64
+	Balance uint64
+}
+
+// PreparedBundle is a bundle without the signatures of its inputs.
+type PreparedBundle struct {
+	BundleHash trinary.Hash
+	// Trytes are the transactions of the bundle, ordered from the head to the tail transaction.
+	Trytes []trinary.Trytes
+	// Inputs are the signatures the client has to create for the bundle hash.
+	Inputs []*SignatureRequest
+}
+
+// Signature contains the signature fragments of an input of a bundle, one fragment per security level.
+type Signature struct {
+	Address   trinary.Hash
+	Fragments []trinary.Trytes
+}
+
+// normalizes the given address to 81 trytes, a checksum is validated and removed.
+func normalizeAddress(addr trinary.Hash) (trinary.Hash, error) {
+	if err := address.ValidAddress(addr); err != nil {
+		return "", fmt.Errorf("%w: %s: %v", ErrInvalidAddress, addr, err)
+	}
+	return addr[:consts.HashTrytesSize], nil
+}
+
+// SelectInputs selects inputs from the given addresses in the given order, until their balances cover the given value.
+// Addresses without balance and addresses which were already spent from are skipped.
+func SelectInputs(ledger Ledger, addresses []trinary.Hash, securityLevel consts.SecurityLevel, value uint64) ([]*Input, error) {
+	if securityLevel < consts.SecurityLevelLow || securityLevel > consts.SecurityLevelHigh {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidSecurityLevel, securityLevel)
+	}
+
+	var inputs []*Input
+	var total uint64
+	for _, addr := range addresses {
+		if total >= value {
+			break
+		}
+
+		addr, err := normalizeAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		addrHash := hornet.HashFromAddressTrytes(addr)
+		if ledger.WasSpent(addrHash) {
+			continue
+		}
+
+		balance, err := ledger.Balance(addrHash)
+		if err != nil {
+			return nil, err
+		}
+		if balance == 0 {
+			continue
+		}
+
+		inputs = append(inputs, &Input{Address: addr, SecurityLevel: securityLevel})
+		total += balance
+	}
+
+	if total < value {
+		return nil, fmt.Errorf("%w: the addresses hold %d, the outputs need %d", ErrInsufficientBalance, total, value)
+	}
+
+	return inputs, nil
+}
+
+// Prepare validates the given transfer against the ledger and creates the bundle without the signatures of its inputs.
+// The whole balance of every input is moved, the funds which are not needed for the outputs are moved to the remainder address.
+func Prepare(ledger Ledger, transfer *Transfer, timestamp time.Time) (*PreparedBundle, error) {
+	if len(transfer.Outputs) == 0 {
+		return nil, ErrNoOutputs
+	}
+
+	var b bundle.Bundle
+	outputAddresses := make(map[trinary.Hash]struct{})
+	var outputsTotal uint64
+
+	addOutput := func(output *Output) error {
+		addr, err := normalizeAddress(output.Address)
+		if err != nil {
+			return err
+		}
+
+		tag := output.Tag
+		if tag != "" && !guards.IsTrytesOfMaxLength(tag, consts.TagTrinarySize/3) {
+			return fmt.Errorf("%w: %s", ErrInvalidTag, tag)
+		}
+
+		if output.Message != "" && !guards.IsTrytes(output.Message) {
+			return fmt.Errorf("%w: not trytes", ErrInvalidMessage)
+		}
+
+		// messages which exceed one signature message fragment are split into several transactions
+		fragmentCount := int(math.Ceil(float64(len(output.Message)) / consts.SignatureMessageFragmentSizeInTrytes))
+		if fragmentCount == 0 {
+			fragmentCount = 1
+		}
+		message := trinary.MustPad(output.Message, fragmentCount*consts.SignatureMessageFragmentSizeInTrytes)
+
+		fragments := make([]trinary.Trytes, fragmentCount)
+		for i := range fragments {
+			fragments[i] = message[i*consts.SignatureMessageFragmentSizeInTrytes : (i+1)*consts.SignatureMessageFragmentSizeInTrytes]
+		}
+
+		if outputsTotal+output.Value < outputsTotal || outputsTotal+output.Value > consts.TotalSupply {
+			return fmt.Errorf("%w: the outputs exceed the total supply", ErrInsufficientBalance)
+		}
+		outputsTotal += output.Value
+		outputAddresses[addr] = struct{}{}
+
+		b = bundle.AddEntry(b, bundle.BundleEntry{
+			Address:                   addr,
+			Value:                     int64(output.Value),
+			Tag:                       tag,
+			Timestamp:                 uint64(timestamp.Unix()),
+			Length:                    uint64(fragmentCount),
+			SignatureMessageFragments: fragments,
+		})
+		return nil
+	}
+
+	for _, output := range transfer.Outputs {
+		if err := addOutput(output); err != nil {
+			return nil, err
+		}
+	}
+
+	if outputsTotal > 0 && len(transfer.Inputs) == 0 {
+		return nil, ErrNoInputs
+	}
+
+	inputAddresses := make(map[trinary.Hash]struct{})
+	var inputsTotal uint64
+	var signatureRequests []*SignatureRequest
+
+	for _, input := range transfer.Inputs {
+		addr, err := normalizeAddress(input.Address)
+		if err != nil {
+			return nil, err
+		}
+
+		if input.SecurityLevel < consts.SecurityLevelLow || input.SecurityLevel > consts.SecurityLevelHigh {
+			return nil, fmt.Errorf("%w: %d (input %s)", ErrInvalidSecurityLevel, input.SecurityLevel, addr)
+		}
+		if _, exists := inputAddresses[addr]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateInput, addr)
+		}
+		if _, exists := outputAddresses[addr]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrInputIsOutput, addr)
+		}
+		inputAddresses[addr] = struct{}{}
+
+		addrHash := hornet.HashFromAddressTrytes(addr)
+		if ledger.WasSpent(addrHash) {
+			return nil, fmt.Errorf("%w: %s", ErrInputAlreadySpent, addr)
+		}
+
+		balance, err := ledger.Balance(addrHash)
+		if err != nil {
+			return nil, err
+		}
+		if balance == 0 {
+			return nil, fmt.Errorf("%w: input %s has no balance", ErrInsufficientBalance, addr)
+		}
+		inputsTotal += balance
+
+		signatureRequests = append(signatureRequests, &SignatureRequest{
+			Address:       addr,
+			SecurityLevel: input.SecurityLevel,
+			Index:         uint64(len(b)),
+			Balance:       balance,
+		})
+
+		b = bundle.AddEntry(b, bundle.BundleEntry{
+			Address:   addr,
+			Value:     -int64(balance),
+			Timestamp: uint64(timestamp.Unix()),
+			Length:    uint64(input.SecurityLevel),
+		})
+	}
+
+	if inputsTotal < outputsTotal {
+		return nil, fmt.Errorf("%w: the inputs hold %d, the outputs need %d", ErrInsufficientBalance, inputsTotal, outputsTotal)
+	}
+
+	if remainder := inputsTotal - outputsTotal; remainder > 0 {
+		if transfer.RemainderAddress == "" {
+			return nil, fmt.Errorf("%w: the remainder of %d would be lost", ErrRemainderAddressMissing, remainder)
+		}
+
+		remainderAddress, err := normalizeAddress(transfer.RemainderAddress)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := inputAddresses[remainderAddress]; exists {
+			return nil, fmt.Errorf("%w: %s is the remainder address", ErrInputIsOutput, remainderAddress)
+		}
+
+		b = bundle.AddEntry(b, bundle.BundleEntry{
+			Address:   remainderAddress,
+			Value:     int64(remainder),
+			Timestamp: uint64(timestamp.Unix()),
+			Length:    1,
+		})
+	}
+
+	b, err := bundle.Finalize(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+
+	return &PreparedBundle{
+		BundleHash: b[0].Bundle,
+		Trytes:     transaction.MustFinalTransactionTrytes(b),
+		Inputs:     signatureRequests,
+	}, nil
+}
+
+// Finalize adds the given signatures to the inputs of the given bundle and validates the signed bundle against the ledger.
+// The trytes of the signed bundle are returned ordered from the head to the tail transaction.
+func Finalize(ledger Ledger, bundleTrytes []trinary.Trytes, signatures []*Signature) ([]trinary.Trytes, error) {
+	b, err := parseBundle(bundleTrytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signaturesByAddress := make(map[trinary.Hash][]trinary.Trytes, len(signatures))
+	for _, signature := range signatures {
+		addr, err := normalizeAddress(signature.Address)
+		if err != nil {
+			return nil, err
+		}
+		signaturesByAddress[addr] = signature.Fragments
+	}
+
+	for i := range b {
+		if b[i].Value >= 0 {
+			continue
+		}
+
+		fragments, exists := signaturesByAddress[b[i].Address]
+		if !exists {
+			return nil, fmt.Errorf("%w: input %s at index %d", ErrMissingSignature, b[i].Address, i)
+		}
+
+		if securityLevel := inputSecurityLevel(b, i); len(fragments) != securityLevel {
+			return nil, fmt.Errorf("%w: input %s at index %d needs %d fragments, %d given", ErrInvalidSignature, b[i].Address, i, securityLevel, len(fragments))
+		}
+
+		for j, fragment := range fragments {
+			if !guards.IsTrytesOfExactLength(fragment, consts.SignatureMessageFragmentSizeInTrytes) {
+				return nil, fmt.Errorf("%w: fragment %d of input %s has an invalid length", ErrInvalidSignature, j, b[i].Address)
+			}
+		}
+		b = bundle.AddTrytes(b, fragments, i)
+	}
+
+	if err := Validate(ledger, b); err != nil {
+		return nil, err
+	}
+
+	return transaction.MustFinalTransactionTrytes(b), nil
+}
+
+// returns the security level of the input at the given index of the bundle,
+// which is the number of transactions the input spans to hold its signature fragments.
+func inputSecurityLevel(b bundle.Bundle, index int) int {
+	securityLevel := 1
+	for i := index + 1; i < len(b) && b[i].Address == b[index].Address && b[i].Value == 0; i++ {
+		securityLevel++
+	}
+	return securityLevel
+}
+
+// parses the given transaction trytes into a bundle ordered by the transaction index.
+func parseBundle(bundleTrytes []trinary.Trytes) (bundle.Bundle, error) {
+	if len(bundleTrytes) == 0 {
+		return nil, fmt.Errorf("%w: no transactions given", ErrInvalidBundle)
+	}
+
+	for i, trytes := range bundleTrytes {
+		if !guards.IsTransactionTrytes(trytes) {
+			return nil, fmt.Errorf("%w: invalid transaction trytes at index %d", ErrInvalidBundle, i)
+		}
+	}
+
+	txs, err := transaction.AsTransactionObjects(bundleTrytes, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+
+	b := make(bundle.Bundle, len(txs))
+	for _, tx := range txs {
+		if tx.LastIndex != uint64(len(txs)-1) || tx.CurrentIndex > tx.LastIndex {
+			return nil, fmt.Errorf("%w: transaction index %d/%d doesn't match the %d transactions", ErrInvalidBundle, tx.CurrentIndex, tx.LastIndex, len(txs))
+		}
+		if b[tx.CurrentIndex].Address != "" {
+			return nil, fmt.Errorf("%w: duplicate transaction index %d", ErrInvalidBundle, tx.CurrentIndex)
+		}
+		b[tx.CurrentIndex] = tx
+	}
+
+	return b, nil
+}
+
+// Validate checks the signatures of the inputs, the structure of the given bundle and whether the inputs are covered by the ledger.
+func Validate(ledger Ledger, b bundle.Bundle) error {
+	if len(b) == 0 {
+		return fmt.Errorf("%w: no transactions given", ErrInvalidBundle)
+	}
+
+	// the balance changes of the addresses
+	changes := make(map[trinary.Hash]int64)
+
+	for i := range b {
+		tx := &b[i]
+		changes[tx.Address] += tx.Value
+
+		if tx.Value >= 0 {
+			continue
+		}
+
+		// the signature fragments of an input are in the transactions of the input and the following zero value
+		// transactions of the same address
+		fragments := []trinary.Trytes{tx.SignatureMessageFragment}
+		for j := i + 1; j < len(b) && b[j].Value == 0 && b[j].Address == tx.Address; j++ {
+			fragments = append(fragments, b[j].SignatureMessageFragment)
+		}
+
+		valid, err := signing.ValidateSignatures(tx.Address, fragments, tx.Bundle)
+		if err != nil {
+			return fmt.Errorf("%w: input %s at index %d: %v", ErrInvalidSignature, tx.Address, i, err)
+		}
+		if !valid {
+			return fmt.Errorf("%w: input %s at index %d", ErrInvalidSignature, tx.Address, i)
+		}
+	}
+
+	if err := bundle.ValidBundle(b); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+
+	for addr, change := range changes {
+		if change >= 0 {
+			continue
+		}
+
+		addrHash := hornet.HashFromAddressTrytes(addr)
+		if ledger.WasSpent(addrHash) {
+			return fmt.Errorf("%w: %s", ErrInputAlreadySpent, addr)
+		}
+
+		balance, err := ledger.Balance(addrHash)
+		if err != nil {
+			return err
+		}
+
+		if uint64(-change) > balance {
+			return fmt.Errorf("%w: input %s moves %d, the balance is %d", ErrInsufficientBalance, addr, -change, balance)
+		}
+	}
+
+	return nil
+}
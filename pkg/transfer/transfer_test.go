@@ -0,0 +1,215 @@
+package transfer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/iota.go/address"
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/kerl"
+	"github.com/iotaledger/iota.go/signing"
+	"github.com/iotaledger/iota.go/signing/key"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+)
+
+const seed = "TESTSEED9TESTSEED9TESTSEED9TESTSEED9TESTSEED9TESTSEED9TESTSEED9TESTSEED9TESTSEED9"
+
+type fakeLedger struct {
+	balances map[string]uint64
+	spent    map[string]struct{}
+	pending  map[string][]trinary.Hash
+}
+
+func newFakeLedger() *fakeLedger {
+	return &fakeLedger{
+		balances: make(map[string]uint64),
+		spent:    make(map[string]struct{}),
+		pending:  make(map[string][]trinary.Hash),
+	}
+}
+
+func (l *fakeLedger) Balance(address hornet.Hash) (uint64, error) {
+	return l.balances[string(address)], nil
+}
+
+func (l *fakeLedger) WasSpent(address hornet.Hash) bool {
+	_, spent := l.spent[string(address)]
+	return spent
+}
+
+func (l *fakeLedger) PendingSpends(address hornet.Hash) ([]trinary.Hash, error) {
+	return l.pending[string(address)], nil
+}
+
+func (l *fakeLedger) setBalance(addr trinary.Hash, balance uint64) {
+	l.balances[string(hornet.HashFromAddressTrytes(addr))] = balance
+}
+
+func (l *fakeLedger) markSpent(addr trinary.Hash) {
+	l.spent[string(hornet.HashFromAddressTrytes(addr))] = struct{}{}
+}
+
+func generateAddress(t *testing.T, index uint64, securityLevel consts.SecurityLevel) trinary.Hash {
+	addr, err := address.GenerateAddress(seed, index, securityLevel, false)
+	require.NoError(t, err)
+	return addr
+}
+
+// signs the given bundle hash with the key of the given seed index, like a wallet does.
+func sign(t *testing.T, bundleHash trinary.Hash, index uint64, securityLevel consts.SecurityLevel) []trinary.Trytes {
+	normalizedBundleHash := signing.NormalizedBundleHash(bundleHash)
+
+	subseed, err := signing.Subseed(seed, index)
+	require.NoError(t, err)
+
+	prvKey, err := key.Sponge(subseed, securityLevel, kerl.NewKerl())
+	require.NoError(t, err)
+
+	fragments := make([]trinary.Trytes, securityLevel)
+	for i := range fragments {
+		fragment, err := signing.SignatureFragment(
+			normalizedBundleHash[i*consts.HashTrytesSize/3:(i+1)*consts.HashTrytesSize/3],
+			prvKey[i*consts.KeyFragmentLength:(i+1)*consts.KeyFragmentLength],
+		)
+		require.NoError(t, err)
+		fragments[i] = trinary.MustTritsToTrytes(fragment)
+	}
+	return fragments
+}
+
+func TestTransfer(t *testing.T) {
+	ledger := newFakeLedger()
+
+	input1 := generateAddress(t, 0, consts.SecurityLevelMedium)
+	input2 := generateAddress(t, 1, consts.SecurityLevelMedium)
+	remainder := generateAddress(t, 2, consts.SecurityLevelMedium)
+	spentAddress := generateAddress(t, 3, consts.SecurityLevelMedium)
+	receiver := generateAddress(t, 4, consts.SecurityLevelLow)
+
+	ledger.setBalance(input1, 60)
+	ledger.setBalance(input2, 50)
+	ledger.setBalance(spentAddress, 1000)
+	ledger.markSpent(spentAddress)
+
+	// spent addresses and addresses without balance are not selected
+	inputs, err := SelectInputs(ledger, []trinary.Hash{spentAddress, remainder, input1, input2}, consts.SecurityLevelMedium, 100)
+	require.NoError(t, err)
+	require.Len(t, inputs, 2)
+	assert.Equal(t, input1, inputs[0].Address)
+	assert.Equal(t, input2, inputs[1].Address)
+
+	_, err = SelectInputs(ledger, []trinary.Hash{input1, input2}, consts.SecurityLevelMedium, 111)
+	assert.True(t, errors.Is(err, ErrInsufficientBalance))
+
+	outputs := []*Output{{Address: receiver, Value: 100, Tag: "HORNET"}}
+
+	_, err = Prepare(ledger, &Transfer{Inputs: inputs, Outputs: outputs}, time.Now())
+	assert.True(t, errors.Is(err, ErrRemainderAddressMissing))
+
+	_, err = Prepare(ledger, &Transfer{Inputs: []*Input{{Address: spentAddress, SecurityLevel: consts.SecurityLevelMedium}}, Outputs: outputs}, time.Now())
+	assert.True(t, errors.Is(err, ErrInputAlreadySpent))
+
+	_, err = Prepare(ledger, &Transfer{Inputs: inputs[:1], Outputs: outputs}, time.Now())
+	assert.True(t, errors.Is(err, ErrInsufficientBalance))
+
+	_, err = Prepare(ledger, &Transfer{Inputs: []*Input{inputs[0], inputs[0]}, Outputs: outputs, RemainderAddress: remainder}, time.Now())
+	assert.True(t, errors.Is(err, ErrDuplicateInput))
+
+	prepared, err := Prepare(ledger, &Transfer{Inputs: inputs, Outputs: outputs, RemainderAddress: remainder}, time.Now())
+	require.NoError(t, err)
+
+	// one output, two inputs with two fragments each and the remainder
+	require.Len(t, prepared.Trytes, 6)
+	require.Len(t, prepared.Inputs, 2)
+	assert.Equal(t, uint64(1), prepared.Inputs[0].Index)
+	assert.Equal(t, uint64(3), prepared.Inputs[1].Index)
+	assert.Equal(t, uint64(50), prepared.Inputs[1].Balance)
+
+	signature1 := &Signature{Address: input1, Fragments: sign(t, prepared.BundleHash, 0, consts.SecurityLevelMedium)}
+	signature2 := &Signature{Address: input2, Fragments: sign(t, prepared.BundleHash, 1, consts.SecurityLevelMedium)}
+
+	_, err = Finalize(ledger, prepared.Trytes, []*Signature{signature1})
+	assert.True(t, errors.Is(err, ErrMissingSignature))
+
+	// the signature of the wrong key
+	_, err = Finalize(ledger, prepared.Trytes, []*Signature{signature1, {Address: input2, Fragments: signature1.Fragments}})
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+
+	// the fragments have to match the security level of the input
+	_, err = Finalize(ledger, prepared.Trytes, []*Signature{signature1, {Address: input2, Fragments: signature2.Fragments[:1]}})
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+
+	_, err = Finalize(ledger, prepared.Trytes, []*Signature{signature1, {Address: input2, Fragments: append(signature2.Fragments, signature2.Fragments[0])}})
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+
+	signed, err := Finalize(ledger, prepared.Trytes, []*Signature{signature1, signature2})
+	require.NoError(t, err)
+	require.Len(t, signed, 6)
+
+	// the ledger changed in the meantime
+	ledger.setBalance(input2, 40)
+	_, err = Finalize(ledger, prepared.Trytes, []*Signature{signature1, signature2})
+	assert.True(t, errors.Is(err, ErrInsufficientBalance))
+}
+
+func TestDryRun(t *testing.T) {
+	ledger := newFakeLedger()
+
+	input := generateAddress(t, 0, consts.SecurityLevelMedium)
+	receiver := generateAddress(t, 1, consts.SecurityLevelMedium)
+	ledger.setBalance(input, 100)
+
+	prepared, err := Prepare(ledger, &Transfer{
+		Inputs:  []*Input{{Address: input, SecurityLevel: consts.SecurityLevelMedium}},
+		Outputs: []*Output{{Address: receiver, Value: 100}},
+	}, time.Now())
+	require.NoError(t, err)
+
+	signature := &Signature{Address: input, Fragments: sign(t, prepared.BundleHash, 0, consts.SecurityLevelMedium)}
+	signed, err := Finalize(ledger, prepared.Trytes, []*Signature{signature})
+	require.NoError(t, err)
+
+	result, err := DryRun(ledger, signed)
+	require.NoError(t, err)
+	assert.NoError(t, result.Err)
+	assert.Equal(t, prepared.BundleHash, result.BundleHash)
+	require.Len(t, result.Changes, 2)
+	assert.Equal(t, receiver, result.Changes[0].Address)
+	assert.Equal(t, int64(100), result.Changes[0].Change)
+	assert.Equal(t, input, result.Changes[1].Address)
+	assert.Equal(t, int64(-100), result.Changes[1].Change)
+	assert.Equal(t, uint64(100), result.Changes[1].Balance)
+
+	// the unsigned bundle is not valid
+	result, err = DryRun(ledger, prepared.Trytes)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(result.Err, ErrInvalidSignature))
+
+	// a reattachment of the same bundle is no conflict, another unconfirmed bundle spending from the input is
+	ledger.pending[string(hornet.HashFromAddressTrytes(input))] = []trinary.Hash{prepared.BundleHash}
+	result, err = DryRun(ledger, signed)
+	require.NoError(t, err)
+	assert.NoError(t, result.Err)
+
+	otherBundle := trinary.MustPad("OTHER", 81)
+	ledger.pending[string(hornet.HashFromAddressTrytes(input))] = []trinary.Hash{prepared.BundleHash, otherBundle}
+	result, err = DryRun(ledger, signed)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(result.Err, ErrConflictingBundle))
+	assert.Equal(t, []trinary.Hash{otherBundle}, result.Changes[1].ConflictingBundles)
+
+	ledger.markSpent(input)
+	result, err = DryRun(ledger, signed)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(result.Err, ErrInputAlreadySpent))
+	assert.True(t, result.Changes[1].Spent)
+
+	_, err = DryRun(ledger, signed[:1])
+	assert.True(t, errors.Is(err, ErrInvalidBundle))
+}
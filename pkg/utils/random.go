@@ -24,6 +24,16 @@ func RandomInsecure(min int, max int) int {
 	return seededRand.Intn(max+1-min) + min
 }
 
+// RandomInsecureInt64 returns a random int64 in the range of min to max.
+// the result is not cryptographically secure.
+// RandomInsecureInt64 is inclusive max value.
+func RandomInsecureInt64(min int64, max int64) int64 {
+	// Rand needs to be locked: https://github.com/golang/go/issues/3611
+	randLock.Lock()
+	defer randLock.Unlock()
+	return seededRand.Int63n(max+1-min) + min
+}
+
 // RandomTrytesInsecure returns random Trytes with the given length.
 // the result is not cryptographically secure.
 // DO NOT USE this function to generate a seed.
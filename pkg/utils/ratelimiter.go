@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket which allows events up to a given rate with bursts of a given size.
+type RateLimiter struct {
+	lock       sync.Mutex
+	rate       float64
+	burst      float64
+	tokens     float64
+	lastUpdate time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter which allows ratePerSecond events per second on average
+// and up to burst events at once. The bucket starts full.
+func NewRateLimiter(ratePerSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:       ratePerSecond,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastUpdate: time.Now(),
+	}
+}
+
+// Allow checks whether an event may happen now and consumes a token if so.
+func (r *RateLimiter) Allow() bool {
+	return r.AllowN(1)
+}
+
+// AllowN checks whether n events may happen now and consumes n tokens if so.
+func (r *RateLimiter) AllowN(n int) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.refillWithoutLocking(time.Now())
+
+	if r.tokens < float64(n) {
+		return false
+	}
+	r.tokens -= float64(n)
+	return true
+}
+
+// CancelN returns n tokens which were consumed by AllowN, e.g. because the events were rejected by another limiter.
+// The bucket never holds more than burst tokens.
+func (r *RateLimiter) CancelN(n int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.refillWithoutLocking(time.Now())
+
+	r.tokens += float64(n)
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+}
+
+// refillWithoutLocking adds the tokens which accumulated since the last update.
+func (r *RateLimiter) refillWithoutLocking(now time.Time) {
+	r.tokens += now.Sub(r.lastUpdate).Seconds() * r.rate
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.lastUpdate = now
+}
+
+// isFullWithoutLocking returns whether the bucket refilled completely, i.e. the limiter was idle.
+func (r *RateLimiter) isFullWithoutLocking(now time.Time) bool {
+	r.refillWithoutLocking(now)
+	return r.tokens >= r.burst
+}
+
+// KeyedRateLimiter manages a RateLimiter for every key, e.g. per remote address.
+// Idle limiters are removed periodically to keep the memory usage bounded.
+type KeyedRateLimiter struct {
+	lock            sync.Mutex
+	ratePerSecond   float64
+	burst           int
+	cleanupInterval time.Duration
+	lastCleanup     time.Time
+	limiters        map[string]*RateLimiter
+}
+
+// NewKeyedRateLimiter creates a new KeyedRateLimiter whose limiters allow ratePerSecond events per second
+// and bursts of up to burst events. Idle limiters are removed every cleanupInterval.
+func NewKeyedRateLimiter(ratePerSecond float64, burst int, cleanupInterval time.Duration) *KeyedRateLimiter {
+	return &KeyedRateLimiter{
+		ratePerSecond:   ratePerSecond,
+		burst:           burst,
+		cleanupInterval: cleanupInterval,
+		lastCleanup:     time.Now(),
+		limiters:        make(map[string]*RateLimiter),
+	}
+}
+
+// Allow checks whether an event for the given key may happen now.
+func (k *KeyedRateLimiter) Allow(key string) bool {
+	k.lock.Lock()
+
+	now := time.Now()
+	if now.Sub(k.lastCleanup) > k.cleanupInterval {
+		k.cleanupWithoutLocking(now)
+	}
+
+	limiter, exists := k.limiters[key]
+	if !exists {
+		limiter = NewRateLimiter(k.ratePerSecond, k.burst)
+		k.limiters[key] = limiter
+	}
+	k.lock.Unlock()
+
+	return limiter.Allow()
+}
+
+// cleanupWithoutLocking removes all limiters whose buckets refilled completely.
+func (k *KeyedRateLimiter) cleanupWithoutLocking(now time.Time) {
+	for key, limiter := range k.limiters {
+		limiter.lock.Lock()
+		idle := limiter.isFullWithoutLocking(now)
+		limiter.lock.Unlock()
+
+		if idle {
+			delete(k.limiters, key)
+		}
+	}
+	k.lastCleanup = now
+}
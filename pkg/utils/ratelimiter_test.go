@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(1000, 2)
+
+	// the bucket starts full
+	assert.True(t, limiter.Allow())
+	assert.True(t, limiter.Allow())
+	assert.False(t, limiter.Allow())
+
+	// one token is added every millisecond
+	time.Sleep(5 * time.Millisecond)
+	assert.True(t, limiter.AllowN(2))
+	assert.False(t, limiter.AllowN(3))
+}
+
+func TestKeyedRateLimiter(t *testing.T) {
+	limiter := NewKeyedRateLimiter(1, 1, time.Millisecond)
+
+	assert.True(t, limiter.Allow("a"))
+	assert.False(t, limiter.Allow("a"))
+
+	// every key has its own limiter
+	assert.True(t, limiter.Allow("b"))
+
+	// exhausted limiters are not removed by the cleanup
+	time.Sleep(2 * time.Millisecond)
+	assert.False(t, limiter.Allow("a"))
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	limiter := NewRateLimiter(0.001, 3)
+
+	assert.True(t, limiter.AllowN(3))
+	assert.False(t, limiter.Allow())
+
+	// the returned tokens may be consumed again
+	limiter.CancelN(2)
+	assert.True(t, limiter.AllowN(2))
+	assert.False(t, limiter.Allow())
+
+	// the bucket never holds more than the burst size
+	limiter.CancelN(10)
+	assert.True(t, limiter.AllowN(3))
+	assert.False(t, limiter.Allow())
+}
@@ -0,0 +1,230 @@
+package watch
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+)
+
+var (
+	// ErrSubscriberNotFound is returned if the subscriber is not registered.
+	ErrSubscriberNotFound = errors.New("subscriber not found")
+	// ErrTooManyAddresses is returned if a subscriber would watch more addresses than allowed.
+	ErrTooManyAddresses = errors.New("too many watched addresses")
+)
+
+// AddressChange is the change of the balance of a watched address caused by a confirmed milestone.
+type AddressChange struct {
+	// The address that was mutated.
+	Address hornet.Hash
+	// The balance of the address after the milestone was applied.
+	Balance uint64
+	// The change of the balance caused by the milestone.
+	Delta int64
+	// The index of the milestone that mutated the address.
+	MilestoneIndex milestone.Index
+}
+
+// NotifyFunc is called with the changes of the watched addresses of a subscriber.
+type NotifyFunc func(changes []*AddressChange)
+
+type subscriber struct {
+	notify    NotifyFunc
+	addresses map[string]struct{}
+}
+
+// Registry keeps track of the address sets of its subscribers
+// and notifies them about the changes of their addresses.
+type Registry struct {
+	lock sync.RWMutex
+	// the maximum amount of addresses a subscriber may watch (0 = unlimited)
+	maxAddresses int
+	// the registered subscribers, keyed by their id
+	subscribers map[string]*subscriber
+	// the ids of the subscribers watching an address, keyed by address
+	watchers map[string]map[string]struct{}
+}
+
+// NewRegistry creates a new registry which allows each subscriber to watch up to maxAddresses addresses.
+func NewRegistry(maxAddresses int) *Registry {
+	return &Registry{
+		maxAddresses: maxAddresses,
+		subscribers:  make(map[string]*subscriber),
+		watchers:     make(map[string]map[string]struct{}),
+	}
+}
+
+// Register adds a subscriber with the given id. An already registered subscriber keeps its addresses.
+func (r *Registry) Register(id string, notify NotifyFunc) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if s, exists := r.subscribers[id]; exists {
+		s.notify = notify
+		return
+	}
+
+	r.subscribers[id] = &subscriber{
+		notify:    notify,
+		addresses: make(map[string]struct{}),
+	}
+}
+
+// Unregister removes the subscriber with the given id and all its watched addresses.
+func (r *Registry) Unregister(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s, exists := r.subscribers[id]
+	if !exists {
+		return
+	}
+
+	for addr := range s.addresses {
+		r.removeWatcherWithoutLocking(addr, id)
+	}
+	delete(r.subscribers, id)
+}
+
+// IsRegistered returns whether a subscriber with the given id is registered.
+func (r *Registry) IsRegistered(id string) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	_, exists := r.subscribers[id]
+	return exists
+}
+
+// Watch adds the given addresses to the address set of the subscriber.
+// Either all or none of the addresses are added.
+func (r *Registry) Watch(id string, addresses ...hornet.Hash) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s, exists := r.subscribers[id]
+	if !exists {
+		return ErrSubscriberNotFound
+	}
+
+	if r.maxAddresses > 0 {
+		newAddresses := make(map[string]struct{})
+		for _, addr := range addresses {
+			if _, watched := s.addresses[string(addr)]; !watched {
+				newAddresses[string(addr)] = struct{}{}
+			}
+		}
+		if len(s.addresses)+len(newAddresses) > r.maxAddresses {
+			return ErrTooManyAddresses
+		}
+	}
+
+	for _, addr := range addresses {
+		s.addresses[string(addr)] = struct{}{}
+
+		ids, exists := r.watchers[string(addr)]
+		if !exists {
+			ids = make(map[string]struct{})
+			r.watchers[string(addr)] = ids
+		}
+		ids[id] = struct{}{}
+	}
+
+	return nil
+}
+
+// Unwatch removes the given addresses from the address set of the subscriber.
+func (r *Registry) Unwatch(id string, addresses ...hornet.Hash) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s, exists := r.subscribers[id]
+	if !exists {
+		return
+	}
+
+	for _, addr := range addresses {
+		if _, watched := s.addresses[string(addr)]; !watched {
+			continue
+		}
+		delete(s.addresses, string(addr))
+		r.removeWatcherWithoutLocking(string(addr), id)
+	}
+}
+
+// Addresses returns the addresses watched by the subscriber.
+func (r *Registry) Addresses(id string) hornet.Hashes {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	s, exists := r.subscribers[id]
+	if !exists {
+		return nil
+	}
+
+	addresses := make(hornet.Hashes, 0, len(s.addresses))
+	for addr := range s.addresses {
+		addresses = append(addresses, hornet.Hash(addr))
+	}
+	return addresses
+}
+
+// SubscriberCount returns the amount of registered subscribers.
+func (r *Registry) SubscriberCount() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return len(r.subscribers)
+}
+
+// OnMilestoneConfirmed notifies every subscriber about the changes of its watched addresses
+// caused by the confirmed milestone. Subscribers without changes are not notified.
+func (r *Registry) OnMilestoneConfirmed(confirmation *whiteflag.Confirmation) {
+	if confirmation.Mutations == nil {
+		return
+	}
+
+	changes := make(map[string][]*AddressChange)
+	notifyFuncs := make(map[string]NotifyFunc)
+
+	r.lock.RLock()
+	for addr, delta := range confirmation.Mutations.AddressMutations {
+		ids, watched := r.watchers[addr]
+		if !watched {
+			continue
+		}
+
+		change := &AddressChange{
+			Address:        hornet.Hash(addr),
+			Balance:        uint64(confirmation.Mutations.NewAddressState[addr]),
+			Delta:          delta,
+			MilestoneIndex: confirmation.MilestoneIndex,
+		}
+
+		for id := range ids {
+			changes[id] = append(changes[id], change)
+			notifyFuncs[id] = r.subscribers[id].notify
+		}
+	}
+	r.lock.RUnlock()
+
+	// the subscribers are notified without holding the lock,
+	// so they are allowed to modify their address sets in the callback.
+	for id, subscriberChanges := range changes {
+		notifyFuncs[id](subscriberChanges)
+	}
+}
+
+func (r *Registry) removeWatcherWithoutLocking(addr string, id string) {
+	ids, exists := r.watchers[addr]
+	if !exists {
+		return
+	}
+
+	delete(ids, id)
+	if len(ids) == 0 {
+		delete(r.watchers, addr)
+	}
+}
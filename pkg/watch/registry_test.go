@@ -0,0 +1,99 @@
+package watch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+)
+
+var (
+	addrA = hornet.Hash("A")
+	addrB = hornet.Hash("B")
+	addrC = hornet.Hash("C")
+)
+
+func confirmation(newAddressState map[string]int64, addressMutations map[string]int64) *whiteflag.Confirmation {
+	return &whiteflag.Confirmation{
+		MilestoneIndex: 5,
+		Mutations: &whiteflag.WhiteFlagMutations{
+			NewAddressState:  newAddressState,
+			AddressMutations: addressMutations,
+		},
+	}
+}
+
+func TestRegistryNotify(t *testing.T) {
+	registry := NewRegistry(0)
+
+	received := make(map[string][]*AddressChange)
+	notify := func(id string) NotifyFunc {
+		return func(changes []*AddressChange) {
+			received[id] = append(received[id], changes...)
+		}
+	}
+
+	registry.Register("1", notify("1"))
+	registry.Register("2", notify("2"))
+	registry.Register("3", notify("3"))
+	assert.NoError(t, registry.Watch("1", addrA, addrB))
+	assert.NoError(t, registry.Watch("2", addrB))
+	assert.NoError(t, registry.Watch("3", addrC))
+	assert.Equal(t, ErrSubscriberNotFound, registry.Watch("4", addrA))
+
+	registry.OnMilestoneConfirmed(confirmation(
+		map[string]int64{string(addrA): 10, string(addrB): 0},
+		map[string]int64{string(addrA): 10, string(addrB): -10},
+	))
+
+	assert.Len(t, received["1"], 2)
+	assert.Len(t, received["2"], 1)
+	assert.Equal(t, addrB, received["2"][0].Address)
+	assert.Equal(t, uint64(0), received["2"][0].Balance)
+	assert.Equal(t, int64(-10), received["2"][0].Delta)
+	assert.EqualValues(t, 5, received["2"][0].MilestoneIndex)
+
+	// subscribers without changes are not notified
+	_, notified := received["3"]
+	assert.False(t, notified)
+}
+
+func TestRegistryUnwatch(t *testing.T) {
+	registry := NewRegistry(0)
+
+	var received []*AddressChange
+	registry.Register("1", func(changes []*AddressChange) {
+		received = append(received, changes...)
+	})
+	assert.NoError(t, registry.Watch("1", addrA, addrB))
+
+	registry.Unwatch("1", addrA)
+	assert.Equal(t, hornet.Hashes{addrB}, registry.Addresses("1"))
+
+	registry.OnMilestoneConfirmed(confirmation(
+		map[string]int64{string(addrA): 10},
+		map[string]int64{string(addrA): 10},
+	))
+	assert.Empty(t, received)
+
+	registry.Unregister("1")
+	assert.False(t, registry.IsRegistered("1"))
+	assert.Equal(t, 0, registry.SubscriberCount())
+	assert.Empty(t, registry.watchers)
+}
+
+func TestRegistryMaxAddresses(t *testing.T) {
+	registry := NewRegistry(2)
+	registry.Register("1", func(_ []*AddressChange) {})
+
+	assert.Equal(t, ErrTooManyAddresses, registry.Watch("1", addrA, addrB, addrC))
+	assert.Empty(t, registry.Addresses("1"))
+
+	assert.NoError(t, registry.Watch("1", addrA, addrB))
+
+	// already watched addresses don't count twice
+	assert.NoError(t, registry.Watch("1", addrA))
+	assert.Equal(t, ErrTooManyAddresses, registry.Watch("1", addrC))
+}
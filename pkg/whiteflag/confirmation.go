@@ -47,8 +47,8 @@ func ConfirmMilestone(cachedTxMetas map[string]*tangle.CachedMetadata, cachedMsB
 		cachedBundles[string(cachedMsBundle.GetBundle().GetTailHash())] = cachedMsBundle.Retain()
 	}
 
-	tangle.WriteLockLedger()
-	defer tangle.WriteUnlockLedger()
+	tangle.Ledger().Lock()
+	defer tangle.Ledger().Unlock()
 
 	milestoneIndex := msBundle.GetMilestoneIndex()
 
@@ -74,14 +74,22 @@ func ConfirmMilestone(cachedTxMetas map[string]*tangle.CachedMetadata, cachedMsB
 
 	tc := time.Now()
 
-	err = tangle.ApplyLedgerDiffWithoutLocking(mutations.AddressMutations, milestoneIndex)
+	err = tangle.Ledger().ApplyLedgerDiffWithoutLocking(mutations.AddressMutations, milestoneIndex)
 	if err != nil {
 		return nil, fmt.Errorf("confirmMilestone: ApplyLedgerDiff failed with Error: %v", err)
 	}
 
+	if err := tangle.SyncLedgerAfterConfirmation(); err != nil {
+		return nil, fmt.Errorf("confirmMilestone: SyncLedgerAfterConfirmation failed with Error: %v", err)
+	}
+
 	cachedMsTailTx := msBundle.GetTail()
 	defer cachedMsTailTx.Release(true)
 
+	trackLatency := tangle.IsNodeSynced()
+	confirmationTime := cachedMsTailTx.GetTransaction().GetTimestamp()
+	confirmedAt := time.Now().Unix()
+
 	loadTxMeta := func(txHash hornet.Hash) (*tangle.CachedMetadata, error) {
 		cachedTxMeta, exists := cachedTxMetas[string(txHash)]
 		if !exists {
@@ -106,12 +114,31 @@ func ConfirmMilestone(cachedTxMetas map[string]*tangle.CachedMetadata, cachedMsB
 		return cachedBundle, nil
 	}
 
+	// only track the latency if the node is synced, otherwise old transactions would distort the statistics.
+	// the latency is measured from the arrival at the node, since the attachment timestamps are set by the clients.
+	observeConfirmationLatency := func(cachedBundle *tangle.CachedBundle) error {
+		if !trackLatency {
+			return nil
+		}
+		cachedTailTxMeta, err := loadTxMeta(cachedBundle.GetBundle().GetTailHash())
+		if err != nil {
+			return err
+		}
+		if arrivalTimestamp := int64(cachedTailTxMeta.GetMetadata().GetArrivalTimestamp()); arrivalTimestamp != 0 {
+			metrics.SharedTangleStatistics.TimeToConfirmation.Observe(time.Duration(confirmedAt-arrivalTimestamp) * time.Second)
+		}
+		return nil
+	}
+
 	// load the bundle for the given tail tx and iterate over each tx in the bundle
 	forEachBundleTxMetaWithTailTxHash := func(txHash hornet.Hash, do func(tx *tangle.CachedMetadata)) error {
 		bundle, err := loadBundle(txHash)
 		if err != nil {
 			return err
 		}
+		if err := observeConfirmationLatency(bundle); err != nil {
+			return err
+		}
 		bundleTxHashes := bundle.GetBundle().GetTxHashes()
 		for _, bundleTxHash := range bundleTxHashes {
 			cachedBundleTx, err := loadTxMeta(bundleTxHash)
@@ -123,16 +150,28 @@ func ConfirmMilestone(cachedTxMetas map[string]*tangle.CachedMetadata, cachedMsB
 		return nil
 	}
 
+	if tangle.IsAddressHistoryEnabled() {
+		// record the confirmed transactions in the history of their addresses
+		onConfirmedTx := forEachConfirmedTx
+		forEachConfirmedTx = func(txMeta *tangle.CachedMetadata, index milestone.Index, confTime int64) {
+			txHash := txMeta.GetMetadata().GetTxHash()
+			if cachedTx := tangle.GetCachedTransactionOrNil(txHash); cachedTx != nil { // tx +1
+				tangle.StoreAddressHistoryEntry(cachedTx.GetTransaction().GetAddress(), index, txHash)
+				cachedTx.Release(true) // tx -1
+			}
+			onConfirmedTx(txMeta, index, confTime)
+		}
+	}
+
 	conf := &ConfirmedMilestoneStats{
 		Index: milestoneIndex,
 	}
 
-	confirmationTime := cachedMsTailTx.GetTransaction().GetTimestamp()
-
 	// confirm all txs of the included tails
 	for _, txHash := range mutations.TailsIncluded {
 		if err := forEachBundleTxMetaWithTailTxHash(txHash, func(txMeta *tangle.CachedMetadata) {
 			if !txMeta.GetMetadata().IsConfirmed() {
+				txMeta.GetMetadata().SetIncluded(true)
 				txMeta.GetMetadata().SetConfirmed(true, milestoneIndex)
 				txMeta.GetMetadata().SetRootSnapshotIndexes(milestoneIndex, milestoneIndex, milestoneIndex)
 				conf.TxsConfirmed++
@@ -150,6 +189,7 @@ func ConfirmMilestone(cachedTxMetas map[string]*tangle.CachedMetadata, cachedMsB
 	for _, txHash := range mutations.TailsExcludedZeroValue {
 		if err := forEachBundleTxMetaWithTailTxHash(txHash, func(txMeta *tangle.CachedMetadata) {
 			if !txMeta.GetMetadata().IsConfirmed() {
+				txMeta.GetMetadata().SetNoTransaction(true)
 				txMeta.GetMetadata().SetConfirmed(true, milestoneIndex)
 				txMeta.GetMetadata().SetRootSnapshotIndexes(milestoneIndex, milestoneIndex, milestoneIndex)
 				conf.TxsConfirmed++
@@ -165,8 +205,9 @@ func ConfirmMilestone(cachedTxMetas map[string]*tangle.CachedMetadata, cachedMsB
 
 	// confirm all conflicting txs of the conflicting tails
 	for _, txHash := range mutations.TailsExcludedConflicting {
+		conflictReason := mutations.ConflictReasons[string(txHash)]
 		if err := forEachBundleTxMetaWithTailTxHash(txHash, func(txMeta *tangle.CachedMetadata) {
-			txMeta.GetMetadata().SetConflicting(true)
+			txMeta.GetMetadata().SetConflicting(true, conflictReason)
 			if !txMeta.GetMetadata().IsConfirmed() {
 				txMeta.GetMetadata().SetConfirmed(true, milestoneIndex)
 				txMeta.GetMetadata().SetRootSnapshotIndexes(milestoneIndex, milestoneIndex, milestoneIndex)
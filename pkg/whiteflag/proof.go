@@ -0,0 +1,144 @@
+package whiteflag
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+)
+
+var (
+	// ErrTransactionNotTail is returned if an inclusion proof is requested for a transaction which is not a tail.
+	// Only the tails of the bundles are part of the white-flag Merkle tree.
+	ErrTransactionNotTail = errors.New("transaction is not a tail")
+	// ErrTransactionNotIncluded is returned if an inclusion proof is requested for a tail whose bundle didn't mutate the ledger.
+	ErrTransactionNotIncluded = errors.New("transaction is not included in the ledger")
+	// ErrMerkleTreeHashMismatch is returned if the reconstructed Merkle tree doesn't match the Merkle tree hash of the milestone.
+	ErrMerkleTreeHashMismatch = errors.New("reconstructed merkle tree hash doesn't match the milestone")
+)
+
+// InclusionProof proves that the bundle of a tail transaction was included in the ledger by a milestone.
+// It can be verified with Hasher.VerifyAuditPath against the Merkle tree hash contained in the milestone.
+type InclusionProof struct {
+	// The index of the milestone which included the bundle.
+	MilestoneIndex milestone.Index
+	// The tail transaction hash of the milestone which included the bundle.
+	MilestoneHash hornet.Hash
+	// The white-flag Merkle tree hash of the milestone.
+	MerkleTreeHash []byte
+	// The tail transaction hash of the included bundle.
+	TailHash hornet.Hash
+	// The position of the tail in the Merkle tree.
+	LeafIndex int
+	// The amount of leaves of the Merkle tree.
+	TreeSize int
+	// The sibling hashes from the leaf up to the root.
+	AuditPath [][]byte
+}
+
+// ComputeInclusionProof computes the white-flag Merkle audit path of the given tail transaction.
+// The included tails of the milestone are reconstructed from the metadata of its past cone,
+// so the cone must not be pruned yet.
+func ComputeInclusionProof(tailHash hornet.Hash) (*InclusionProof, error) {
+	tangle.Ledger().RLock()
+	defer tangle.Ledger().RUnlock()
+
+	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(tailHash) // meta +1
+	if cachedTxMeta == nil {
+		return nil, fmt.Errorf("%w: %s", tangle.ErrTransactionNotFound, tailHash.Trytes())
+	}
+	metadata := cachedTxMeta.GetMetadata()
+	isTail, isIncluded := metadata.IsTail(), metadata.IsIncluded()
+	_, msIndex := metadata.GetReferenced()
+	cachedTxMeta.Release(true) // meta -1
+
+	if !isTail {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotTail, tailHash.Trytes())
+	}
+	if !isIncluded {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotIncluded, tailHash.Trytes())
+	}
+
+	cachedMsBundle := tangle.GetMilestoneOrNil(msIndex) // bundle +1
+	if cachedMsBundle == nil {
+		return nil, fmt.Errorf("%w: milestone %d", tangle.ErrMilestoneNotFound, msIndex)
+	}
+	defer cachedMsBundle.Release(true) // bundle -1
+	msBundle := cachedMsBundle.GetBundle()
+
+	tailsIncluded, err := includedTails(msBundle.GetTailHash(), msIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher := NewHasher(tangle.GetMilestoneMerkleHashFunc())
+	merkleTreeHash := msBundle.GetMilestoneMerkleTreeHash()
+	if !bytes.Equal(hasher.TreeHash(tailsIncluded), merkleTreeHash) {
+		return nil, fmt.Errorf("%w: milestone %d", ErrMerkleTreeHashMismatch, msIndex)
+	}
+
+	leafIndex := -1
+	for i, includedTailHash := range tailsIncluded {
+		if bytes.Equal(includedTailHash, tailHash) {
+			leafIndex = i
+			break
+		}
+	}
+	if leafIndex == -1 {
+		return nil, fmt.Errorf("%w: %s was not found in the cone of milestone %d", ErrTransactionNotIncluded, tailHash.Trytes(), msIndex)
+	}
+
+	return &InclusionProof{
+		MilestoneIndex: msIndex,
+		MilestoneHash:  msBundle.GetTailHash(),
+		MerkleTreeHash: merkleTreeHash,
+		TailHash:       tailHash,
+		LeafIndex:      leafIndex,
+		TreeSize:       len(tailsIncluded),
+		AuditPath:      hasher.AuditPath(tailsIncluded, leafIndex),
+	}, nil
+}
+
+// includedTails returns the tails which mutated the ledger in the confirmation of the given milestone
+// in the order in which they were applied.
+// The past cone is traversed in the same order as in ComputeWhiteFlagMutations, but only the transactions
+// referenced by the milestone are walked, since the transactions referenced by older milestones were
+// already confirmed at the time of the confirmation.
+func includedTails(msTailHash hornet.Hash, msIndex milestone.Index) (hornet.Hashes, error) {
+	var tailsIncluded hornet.Hashes
+
+	condition := func(cachedTxMeta *tangle.CachedMetadata) (bool, error) { // meta +1
+		defer cachedTxMeta.Release(true) // meta -1
+
+		referenced, referencedIndex := cachedTxMeta.GetMetadata().GetReferenced()
+		return referenced && referencedIndex == msIndex, nil
+	}
+
+	consumer := func(cachedTxMeta *tangle.CachedMetadata) error { // meta +1
+		defer cachedTxMeta.Release(true) // meta -1
+
+		if cachedTxMeta.GetMetadata().IsIncluded() {
+			tailsIncluded = append(tailsIncluded, cachedTxMeta.GetMetadata().GetTxHash())
+		}
+		return nil
+	}
+
+	if err := dag.TraverseApproveesParents(hornet.Hashes{msTailHash},
+		condition,
+		consumer,
+		// called on missing approvees
+		// return error on missing approvees
+		nil,
+		// called on solid entry points
+		// Ignore solid entry points (snapshot milestone included)
+		nil,
+		false, true, nil); err != nil {
+		return nil, err
+	}
+
+	return tailsIncluded, nil
+}
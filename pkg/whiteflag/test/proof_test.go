@@ -0,0 +1,78 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	_ "golang.org/x/crypto/blake2b"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/gohornet/hornet/pkg/testsuite/utils"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+)
+
+func TestComputeInclusionProof(t *testing.T) {
+
+	// Fill up the balances
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(t, seed1, 0))] = 1000
+
+	te := testsuite.SetupTestEnvironment(t, balances, 3, showConfirmationGraphs)
+	defer te.CleanupTestEnvironment(!showConfirmationGraphs)
+
+	te.BuildTopology(testsuite.Topology{
+		// Valid transfer 100 from seed1[0] to seed2[0]
+		{Name: "A", Trunk: "ms2", Branch: "ms3", Trytes: utils.ValueTx(t, "A", seed1, 0, 1000, seed2, 0, 100)},
+		// Valid transfer 200 from seed1[1] to seed3[0]
+		{Name: "B", Trunk: "A", Branch: "ms3", Trytes: utils.ValueTx(t, "B", seed1, 1, 900, seed3, 0, 200)},
+		// Invalid transfer 10 from seed4[0] to seed2[0] (insufficient funds)
+		{Name: "C", Trunk: "B", Branch: "ms2", Trytes: utils.ValueTx(t, "C", seed4, 0, 99999, seed2, 0, 10)},
+		// Zero value bundle
+		{Name: "D", Trunk: "C", Branch: "A"},
+		// Valid transfer 50 from seed2[0] to seed4[1]
+		{Name: "E", Trunk: "D", Branch: "B", Trytes: utils.ValueTx(t, "E", seed2, 0, 100, seed4, 1, 50)},
+	})
+	conf := te.ConfirmMilestoneOn("E")
+
+	cachedMs := tangle.GetMilestoneOrNil(conf.Index) // bundle +1
+	require.NotNil(t, cachedMs)
+	defer cachedMs.Release(true) // bundle -1
+
+	msBundle := cachedMs.GetBundle()
+	hasher := whiteflag.NewHasher(tangle.GetMilestoneMerkleHashFunc())
+
+	// every included bundle is a leaf of the Merkle tree of the milestone
+	leafIndexes := make(map[int]struct{})
+	for _, name := range []string{"A", "B", "E"} {
+		proof, err := whiteflag.ComputeInclusionProof(te.TailOf(name))
+		require.NoError(t, err, "bundle %s", name)
+
+		require.Equal(t, conf.Index, proof.MilestoneIndex)
+		require.Equal(t, msBundle.GetTailHash(), proof.MilestoneHash)
+		require.Equal(t, msBundle.GetMilestoneMerkleTreeHash(), proof.MerkleTreeHash)
+		require.Equal(t, te.TailOf(name), proof.TailHash)
+		require.Equal(t, 3, proof.TreeSize)
+		require.True(t, hasher.VerifyAuditPath(proof.TailHash, proof.LeafIndex, proof.TreeSize, proof.AuditPath, msBundle.GetMilestoneMerkleTreeHash()), "bundle %s", name)
+
+		leafIndexes[proof.LeafIndex] = struct{}{}
+	}
+	require.Len(t, leafIndexes, 3)
+
+	// zero value and conflicting bundles didn't mutate the ledger
+	for _, name := range []string{"C", "D"} {
+		_, err := whiteflag.ComputeInclusionProof(te.TailOf(name))
+		require.True(t, errors.Is(err, whiteflag.ErrTransactionNotIncluded), "bundle %s, error: %v", name, err)
+	}
+
+	// only the tails are part of the Merkle tree
+	cachedBundle := tangle.GetCachedBundleOrNil(te.TailOf("A")) // bundle +1
+	require.NotNil(t, cachedBundle)
+	defer cachedBundle.Release(true) // bundle -1
+
+	txHashes := cachedBundle.GetBundle().GetTxHashes()
+	_, err := whiteflag.ComputeInclusionProof(txHashes[len(txHashes)-1])
+	require.True(t, errors.Is(err, whiteflag.ErrTransactionNotTail), "error: %v", err)
+}
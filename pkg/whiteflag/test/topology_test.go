@@ -0,0 +1,52 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/gohornet/hornet/pkg/testsuite/utils"
+)
+
+func TestWhiteFlagTopology(t *testing.T) {
+
+	// Fill up the balances
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(t, seed1, 0))] = 1000
+
+	te := testsuite.SetupTestEnvironment(t, balances, 3, showConfirmationGraphs)
+	defer te.CleanupTestEnvironment(!showConfirmationGraphs)
+
+	te.BuildTopology(testsuite.Topology{
+		{Name: "A", Trunk: "ms2", Branch: "ms3"},
+		// Valid transfer 100 from seed1[0] to seed2[0]
+		{Name: "B", Trunk: "A", Branch: "ms4", Trytes: utils.ValueTx(t, "B", seed1, 0, 1000, seed2, 0, 100)},
+		// Invalid transfer 10 from seed3[0] to seed2[0] (insufficient funds)
+		{Name: "C", Trunk: "B", Branch: "ms2", Trytes: utils.ValueTx(t, "C", seed3, 0, 99999, seed2, 0, 10)},
+		{Name: "D", Trunk: "ms3", Branch: "ms4"},
+	})
+
+	// The root snapshot indexes are used by the tip selection
+	te.AssertRootSnapshotIndexes("A", 3, 2)
+	te.AssertRootSnapshotIndexes("D", 4, 3)
+
+	// Confirming milestone at bundle C (bundle D is not included)
+	conf := te.ConfirmMilestoneOn("C")
+	te.AssertConfirmed(conf.Index, "A", "B", "C")
+	te.AssertNotConfirmed("D")
+
+	te.AssertLedgerInclusionState("A", hornet.LedgerInclusionStateNoTransaction, hornet.ConflictReasonNone)
+	te.AssertLedgerInclusionState("B", hornet.LedgerInclusionStateIncluded, hornet.ConflictReasonNone)
+	te.AssertLedgerInclusionState("C", hornet.LedgerInclusionStateConflicting, hornet.ConflictReasonInsufficientBalance)
+
+	te.AssertLedgerDiff(conf.Index, map[string]int64{
+		string(utils.GenerateAddress(t, seed1, 0)): -1000,
+		string(utils.GenerateAddress(t, seed1, 1)): 900,
+		string(utils.GenerateAddress(t, seed2, 0)): 100,
+	})
+
+	// Confirming milestone at bundle D
+	conf = te.ConfirmMilestoneOn("D")
+	te.AssertConfirmed(conf.Index, "D")
+	te.AssertLedgerDiff(conf.Index, map[string]int64{})
+}
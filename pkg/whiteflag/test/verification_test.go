@@ -0,0 +1,64 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/gohornet/hornet/pkg/testsuite/utils"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+)
+
+func TestVerifyConfirmedMilestone(t *testing.T) {
+
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(t, seed1, 0))] = 1000
+
+	te := testsuite.SetupTestEnvironment(t, balances, 3, showConfirmationGraphs)
+	defer te.CleanupTestEnvironment(!showConfirmationGraphs)
+
+	// Valid transfer 100 from seed1[0] to seed2[0]
+	bundleA := te.AttachAndStoreBundle(te.Milestones[0].GetBundle().GetTailHash(), te.Milestones[1].GetBundle().GetTailHash(), utils.ValueTx(t, "A", seed1, 0, 1000, seed2, 0, 100))
+	// Invalid transfer 10 from seed3[0] to seed2[0] (insufficient funds)
+	bundleB := te.AttachAndStoreBundle(te.Milestones[2].GetBundle().GetTailHash(), bundleA.GetBundle().GetTailHash(), utils.ValueTx(t, "B", seed3, 0, 99999, seed2, 0, 10))
+	te.IssueAndConfirmMilestoneOnTip(bundleB.GetBundle().GetTailHash(), false)
+
+	// Valid transfer 100 from seed2[0] to seed4[0]
+	bundleC := te.AttachAndStoreBundle(bundleB.GetBundle().GetTailHash(), te.Milestones[3].GetBundle().GetTailHash(), utils.ValueTx(t, "C", seed2, 0, 100, seed4, 0, 100))
+	te.IssueAndConfirmMilestoneOnTip(bundleC.GetBundle().GetTailHash(), false)
+
+	verifyAll := func() (milestone.Index, error) {
+		ledgerState, snapshotIndex, err := tangle.GetAllSnapshotBalances(nil)
+		require.NoError(t, err)
+
+		for msIndex := snapshotIndex + 1; msIndex <= tangle.GetSolidMilestoneIndex(); msIndex++ {
+			if _, err := whiteflag.VerifyConfirmedMilestone(msIndex, ledgerState); err != nil {
+				return msIndex, err
+			}
+		}
+
+		currentLedgerState, _, err := tangle.Ledger().GetLedgerStateForLSMI(nil)
+		require.NoError(t, err)
+		require.Equal(t, currentLedgerState, ledgerState)
+		return 0, nil
+	}
+
+	_, err := verifyAll()
+	require.NoError(t, err)
+
+	// the metadata of the conflicting bundle doesn't match the white-flag confirmation anymore
+	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(bundleB.GetBundle().GetTailHash()) // meta +1
+	require.NotNil(t, cachedTxMeta)
+	cachedTxMeta.GetMetadata().SetConflicting(false, hornet.ConflictReasonNone)
+	cachedTxMeta.Release(true) // meta -1
+
+	confirmingIndex := tangle.GetSolidMilestoneIndex() - 1
+	divergentIndex, err := verifyAll()
+	require.True(t, errors.Is(err, whiteflag.ErrConfirmationMismatch))
+	require.Equal(t, confirmingIndex, divergentIndex)
+}
@@ -36,3 +36,26 @@ func TestWhiteFlagMerkleTreeHash(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, bytes.Equal(hash, expectedHash))
 }
+
+func TestWhiteFlagMerkleAuditPath(t *testing.T) {
+	hasher := whiteflag.NewHasher(crypto.BLAKE2b_512)
+
+	for size := 1; size <= 17; size++ {
+		tailHashes := make([]hornet.Hash, size)
+		for i := range tailHashes {
+			tailHashes[i] = hornet.Hash{byte(i), byte(size)}
+		}
+		root := hasher.TreeHash(tailHashes)
+
+		for index := range tailHashes {
+			auditPath := hasher.AuditPath(tailHashes, index)
+			require.True(t, hasher.VerifyAuditPath(tailHashes[index], index, size, auditPath, root), "size %d, index %d", size, index)
+
+			// the path must not prove another hash or position
+			require.False(t, hasher.VerifyAuditPath(hornet.Hash{0xff}, index, size, auditPath, root))
+			if size > 1 {
+				require.False(t, hasher.VerifyAuditPath(tailHashes[index], (index+1)%size, size, auditPath, root))
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/gohornet/hornet/pkg/testsuite/utils"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+)
+
+// attachCone attaches the given amount of zero value bundles, which approve random former bundles,
+// on top of a few value and conflicting bundles and returns the tail of the youngest bundle.
+func attachCone(tb testing.TB, te *testsuite.TestEnvironment, zeroValueBundles int) hornet.Hash {
+
+	tails := hornet.Hashes{te.Milestones[0].GetBundle().GetTailHash(), te.Milestones[1].GetBundle().GetTailHash(), te.Milestones[2].GetBundle().GetTailHash()}
+
+	// Valid transfer 100 from seed1[0] to seed2[0]
+	bundleA := te.AttachAndStoreBundle(tails[0], tails[1], utils.ValueTx(tb, "A", seed1, 0, 1000, seed2, 0, 100))
+	// Invalid transfer 10 from seed3[0] to seed2[0] (insufficient funds)
+	bundleB := te.AttachAndStoreBundle(tails[2], bundleA.GetBundle().GetTailHash(), utils.ValueTx(tb, "B", seed3, 0, 99999, seed2, 0, 10))
+	// Valid transfer 50 from seed2[0] to seed4[0]
+	bundleC := te.AttachAndStoreBundle(bundleB.GetBundle().GetTailHash(), tails[1], utils.ValueTx(tb, "C", seed2, 0, 100, seed4, 0, 50))
+	tails = append(tails, bundleA.GetBundle().GetTailHash(), bundleB.GetBundle().GetTailHash(), bundleC.GetBundle().GetTailHash())
+
+	// the topology is random, but the same for every run
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < zeroValueBundles; i++ {
+		trunk := tails[len(tails)-1-rnd.Intn(min(len(tails), 10))]
+		branch := tails[rnd.Intn(len(tails))]
+		cachedBundle := te.AttachAndStoreBundle(trunk, branch, utils.ZeroValueTx(tb, "Z"))
+		tails = append(tails, cachedBundle.GetBundle().GetTailHash())
+	}
+
+	// the youngest bundle references the value bundles, so they are part of every cone
+	tip := te.AttachAndStoreBundle(tails[len(tails)-1], bundleC.GetBundle().GetTailHash(), utils.ZeroValueTx(tb, "TIP"))
+	return tip.GetBundle().GetTailHash()
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// computeMutations computes the white-flag mutations of the cone of the given tip with the given amount of traversal workers.
+func computeMutations(tb testing.TB, workers int, tip hornet.Hash) *whiteflag.WhiteFlagMutations {
+	formerWorkers := whiteflag.GetTraversalWorkers()
+	whiteflag.SetTraversalWorkers(workers)
+	defer whiteflag.SetTraversalWorkers(formerWorkers)
+
+	cachedTxMetas := make(map[string]*tangle.CachedMetadata)
+	cachedBundles := make(map[string]*tangle.CachedBundle)
+
+	defer func() {
+		// All releases are forced since the cone is referenced and not needed anymore
+		for _, cachedBundle := range cachedBundles {
+			cachedBundle.Release(true) // bundle -1
+		}
+		for _, cachedTxMeta := range cachedTxMetas {
+			cachedTxMeta.Release(true) // meta -1
+		}
+	}()
+
+	mutations, err := whiteflag.ComputeWhiteFlagMutations(cachedTxMetas, cachedBundles, tangle.GetMilestoneMerkleHashFunc(), tip)
+	require.NoError(tb, err)
+	return mutations
+}
+
+func TestWhiteFlagParallelTraversalMatchesSerial(t *testing.T) {
+
+	// Fill up the balances
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(t, seed1, 0))] = 1000
+
+	te := testsuite.SetupTestEnvironment(t, balances, 3, showConfirmationGraphs)
+	defer te.CleanupTestEnvironment(!showConfirmationGraphs)
+
+	tip := attachCone(t, te, 200)
+
+	serial := computeMutations(t, 1, tip)
+	require.Len(t, serial.TailsIncluded, 2)
+	require.Len(t, serial.TailsExcludedConflicting, 1)
+	require.NotEmpty(t, serial.TailsExcludedZeroValue)
+
+	for _, workers := range []int{2, 4, 16} {
+		require.Equal(t, serial, computeMutations(t, workers, tip), "workers: %d", workers)
+	}
+
+	// the ledger changes of the cone are the same as the ones of the serial traversal
+	te.IssueAndConfirmMilestoneOnTip(tip, false)
+	te.AssertAddressBalance(seed2, 0, 0)
+	te.AssertAddressBalance(seed4, 0, 50)
+	te.AssertTotalSupplyStillValid()
+}
+
+func BenchmarkComputeWhiteFlagMutations(b *testing.B) {
+
+	// Fill up the balances
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(b, seed1, 0))] = 1000
+
+	te := testsuite.SetupTestEnvironment(b, balances, 3, false)
+	defer te.CleanupTestEnvironment(true)
+
+	tip := attachCone(b, te, 1000)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				computeMutations(b, workers, tip)
+			}
+		})
+	}
+}
@@ -1,12 +1,17 @@
 package test
 
 import (
+	"bytes"
 	"testing"
 
 	_ "golang.org/x/crypto/blake2b"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/iotaledger/iota.go/consts"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/pkg/testsuite"
 	"github.com/gohornet/hornet/pkg/testsuite/utils"
 )
@@ -49,6 +54,12 @@ func TestWhiteFlagWithMultipleConflicting(t *testing.T) {
 	require.Equal(t, 4, conf.TxsConflicting)
 	require.Equal(t, 3, conf.TxsZeroValue) // The milestone
 
+	// Verify the conflict reason of bundle C
+	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(bundleC.GetBundle().GetTailHash()) // meta +1
+	require.NotNil(t, cachedTxMeta)
+	require.Equal(t, hornet.ConflictReasonInsufficientBalance, cachedTxMeta.GetMetadata().GetConflictReason())
+	cachedTxMeta.Release(true) // meta -1
+
 	// Verify balances (seed, index, balance)
 	te.AssertAddressBalance(seed1, 0, 0)
 	te.AssertAddressBalance(seed1, 1, 0)
@@ -111,3 +122,26 @@ func TestWhiteFlagWithOnlyZeroTx(t *testing.T) {
 	require.Equal(t, 0, conf.TxsValue)
 	require.Equal(t, 0, conf.TxsConflicting)
 }
+
+func TestWhiteFlagConflictReasonIsDeterministic(t *testing.T) {
+
+	// the whole supply is on seed2[0], so every further credit exceeds the total supply
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(t, seed2, 0))] = consts.TotalSupply
+
+	te := testsuite.SetupTestEnvironment(t, balances, 3, showConfirmationGraphs)
+	defer te.CleanupTestEnvironment(!showConfirmationGraphs)
+
+	// Invalid transfer 100 from seed1[0] to seed2[0], the input has insufficient funds and the output exceeds the total supply
+	te.BuildTopology(testsuite.Topology{
+		{Name: "A", Trunk: "ms2", Branch: "ms3", Trytes: utils.ValueTx(t, "A", seed1, 0, 100, seed2, 0, 100)},
+	})
+	te.ConfirmMilestoneOn("A")
+
+	// the conflict of the lower address is reported
+	expectedReason := hornet.ConflictReasonInsufficientBalance
+	if bytes.Compare(utils.GenerateAddress(t, seed2, 0), utils.GenerateAddress(t, seed1, 0)) < 0 {
+		expectedReason = hornet.ConflictReasonBalanceExceedsTotalSupply
+	}
+	te.AssertLedgerInclusionState("A", hornet.LedgerInclusionStateConflicting, expectedReason)
+}
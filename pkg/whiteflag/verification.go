@@ -0,0 +1,133 @@
+package whiteflag
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+)
+
+var (
+	// ErrConfirmationMismatch is returned if the stored confirmation of a milestone doesn't match the recomputed white-flag confirmation.
+	ErrConfirmationMismatch = errors.New("the stored confirmation doesn't match the white-flag confirmation")
+)
+
+// VerifyConfirmedMilestone recomputes the white-flag confirmation of the confirmed milestone with the given index
+// against the given ledger state of the previous milestone and compares it with the stored merkle tree hash of the milestone,
+// the stored ledger diff and the stored ledger inclusion states of the referenced tails.
+// If the confirmation matches, the given balances are updated to the ledger state of the milestone.
+// The ledger state must be read locked while this function is getting called in order to ensure consistency.
+func VerifyConfirmedMilestone(msIndex milestone.Index, balances map[string]uint64) (*WhiteFlagMutations, error) {
+
+	cachedMsBundle := tangle.GetMilestoneOrNil(msIndex) // bundle +1
+	if cachedMsBundle == nil {
+		return nil, fmt.Errorf("%w: milestone %d not found", ErrConfirmationMismatch, msIndex)
+	}
+	defer cachedMsBundle.Release(true) // bundle -1
+	msBundle := cachedMsBundle.GetBundle()
+
+	cachedTxMetas := make(map[string]*tangle.CachedMetadata)
+	cachedBundles := make(map[string]*tangle.CachedBundle)
+
+	defer func() {
+		// all releases are forced since the cone is not needed anymore
+		for _, cachedTxMeta := range cachedTxMetas {
+			cachedTxMeta.Release(true) // meta -1
+		}
+		for _, cachedBundle := range cachedBundles {
+			cachedBundle.Release(true) // bundle -1
+		}
+	}()
+
+	mutations, err := RecomputeWhiteFlagMutations(cachedTxMetas, cachedBundles, tangle.GetMilestoneMerkleHashFunc(), msIndex, balances, msBundle.GetTailHash())
+	if err != nil {
+		return nil, fmt.Errorf("%w: milestone %d: %v", ErrConfirmationMismatch, msIndex, err)
+	}
+
+	if merkleTreeHash := msBundle.GetMilestoneMerkleTreeHash(); !bytes.Equal(mutations.MerkleTreeHash, merkleTreeHash) {
+		return nil, fmt.Errorf("%w: milestone %d: computed merkle tree hash %s does not match the value in the milestone %s", ErrConfirmationMismatch, msIndex, hex.EncodeToString(mutations.MerkleTreeHash), hex.EncodeToString(merkleTreeHash))
+	}
+
+	diff, err := tangle.Ledger().GetLedgerDiffForMilestoneWithoutLocking(msIndex, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := compareLedgerDiff(mutations.AddressMutations, diff); err != nil {
+		return nil, fmt.Errorf("%w: milestone %d: %v", ErrConfirmationMismatch, msIndex, err)
+	}
+
+	verifyTails := func(tailHashes hornet.Hashes, state hornet.LedgerInclusionState) error {
+		for _, tailHash := range tailHashes {
+			if err := verifyTailMetadata(cachedTxMetas[string(tailHash)], msIndex, state, mutations.ConflictReasons[string(tailHash)]); err != nil {
+				return fmt.Errorf("%w: milestone %d: tail %s: %v", ErrConfirmationMismatch, msIndex, tailHash.Trytes(), err)
+			}
+		}
+		return nil
+	}
+
+	if err := verifyTails(mutations.TailsIncluded, hornet.LedgerInclusionStateIncluded); err != nil {
+		return nil, err
+	}
+	if err := verifyTails(mutations.TailsExcludedZeroValue, hornet.LedgerInclusionStateNoTransaction); err != nil {
+		return nil, err
+	}
+	if err := verifyTails(mutations.TailsExcludedConflicting, hornet.LedgerInclusionStateConflicting); err != nil {
+		return nil, err
+	}
+
+	for address, change := range mutations.AddressMutations {
+		newBalance := int64(balances[address]) + change
+		if newBalance == 0 {
+			delete(balances, address)
+			continue
+		}
+		balances[address] = uint64(newBalance)
+	}
+
+	return mutations, nil
+}
+
+// compareLedgerDiff compares the computed mutations with the stored ledger diff, entries without a change are ignored.
+func compareLedgerDiff(mutations map[string]int64, diff map[string]int64) error {
+	for address, change := range mutations {
+		if diff[address] != change {
+			return fmt.Errorf("computed balance change of address %s is %d, the ledger diff contains %d", hornet.Hash(address).Trytes(), change, diff[address])
+		}
+	}
+	for address, change := range diff {
+		if _, exists := mutations[address]; !exists && change != 0 {
+			return fmt.Errorf("the ledger diff contains a balance change of %d for address %s which was not computed", change, hornet.Hash(address).Trytes())
+		}
+	}
+	return nil
+}
+
+// verifyTailMetadata checks that the tail was referenced by the milestone with the given index with the expected ledger inclusion state.
+func verifyTailMetadata(cachedTxMeta *tangle.CachedMetadata, msIndex milestone.Index, state hornet.LedgerInclusionState, reason hornet.ConflictReason) error {
+	if cachedTxMeta == nil {
+		return errors.New("metadata not found")
+	}
+	metadata := cachedTxMeta.GetMetadata()
+
+	if referenced, referencedIndex := metadata.GetReferenced(); !referenced || referencedIndex != msIndex {
+		return fmt.Errorf("referenced: %v, referenced by milestone %d", referenced, referencedIndex)
+	}
+
+	storedState := metadata.GetLedgerInclusionState()
+	if state == hornet.LedgerInclusionStateNoTransaction && storedState == hornet.LedgerInclusionStateIncluded && !metadata.IsIncluded() {
+		// referenced before the inclusion flags were introduced
+		storedState = state
+	}
+
+	if storedState != state {
+		return fmt.Errorf("ledger inclusion state is %s, expected %s", storedState, state)
+	}
+	if storedReason := metadata.GetConflictReason(); storedReason != reason {
+		return fmt.Errorf("conflict reason is %d, expected %d", storedReason, reason)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"crypto"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/iotaledger/iota.go/consts"
 	"github.com/iotaledger/iota.go/math"
@@ -38,6 +39,8 @@ type WhiteFlagMutations struct {
 	TailsIncluded hornet.Hashes
 	// The tails of bundles which were excluded as they were conflicting with the mutations.
 	TailsExcludedConflicting hornet.Hashes
+	// The reasons why the tails in TailsExcludedConflicting were conflicting, keyed by tail hash.
+	ConflictReasons map[string]hornet.ConflictReason
 	// The tails which were excluded because they were part of a zero or spam value transfer.
 	TailsExcludedZeroValue hornet.Hashes
 	// The tails which were referenced by the milestone (should be the sum of TailsIncluded + TailsExcludedConflicting + TailsExcludedZeroValue).
@@ -60,14 +63,93 @@ type WhiteFlagMutations struct {
 // The ledger state must be write locked while this function is getting called in order to ensure consistency.
 // all cachedTxMetas and cachedBundles have to be released outside.
 func ComputeWhiteFlagMutations(cachedTxMetas map[string]*tangle.CachedMetadata, cachedBundles map[string]*tangle.CachedBundle, merkleTreeHashFunc crypto.Hash, trunkHash hornet.Hash, branchHash ...hornet.Hash) (*WhiteFlagMutations, error) {
-	wfConf := &WhiteFlagMutations{
+
+	// only traverse and process the transactions which were not confirmed yet
+	isUnconfirmed := func(txMeta *hornet.TransactionMetadata) bool {
+		return !txMeta.IsConfirmed()
+	}
+
+	balanceFromPreviousMilestone := func(address hornet.Hash) (uint64, error) {
+		balance, _, err := tangle.Ledger().GetBalanceForAddressWithoutLocking(address)
+		return balance, err
+	}
+
+	return computeWhiteFlagMutations(cachedTxMetas, cachedBundles, merkleTreeHashFunc, isUnconfirmed, balanceFromPreviousMilestone, trunkHash, branchHash...)
+}
+
+// RecomputeWhiteFlagMutations computes the ledger changes of the already confirmed milestone with the given index
+// in accordance to the white-flag rules, e.g. to verify the stored state of its confirmation.
+// The transactions referenced by older milestones are not traversed and the balances of the addresses are
+// taken from the given ledger state of the previous milestone, so the current ledger state is not accessed.
+// all cachedTxMetas and cachedBundles have to be released outside.
+func RecomputeWhiteFlagMutations(cachedTxMetas map[string]*tangle.CachedMetadata, cachedBundles map[string]*tangle.CachedBundle, merkleTreeHashFunc crypto.Hash, msIndex milestone.Index, previousBalances map[string]uint64, trunkHash hornet.Hash, branchHash ...hornet.Hash) (*WhiteFlagMutations, error) {
+
+	// transactions which are not referenced at all or by a younger milestone are traversed as well,
+	// they can't be part of the cone of the milestone in a consistent database and show up as a mismatch.
+	isNotReferencedBefore := func(txMeta *hornet.TransactionMetadata) bool {
+		referenced, referencedIndex := txMeta.GetReferenced()
+		return !referenced || referencedIndex >= msIndex
+	}
+
+	balanceFromPreviousMilestone := func(address hornet.Hash) (uint64, error) {
+		return previousBalances[string(address)], nil
+	}
+
+	return computeWhiteFlagMutations(cachedTxMetas, cachedBundles, merkleTreeHashFunc, isNotReferencedBefore, balanceFromPreviousMilestone, trunkHash, branchHash...)
+}
+
+// computeWhiteFlagMutations traverses the cone referenced by trunk and branch and applies the bundles of the transactions
+// passing the traverse function against the balances returned by balanceFromPreviousMilestone.
+// The cone is loaded by parallel workers if more than one traversal worker is configured.
+func computeWhiteFlagMutations(cachedTxMetas map[string]*tangle.CachedMetadata, cachedBundles map[string]*tangle.CachedBundle, merkleTreeHashFunc crypto.Hash, traverse func(txMeta *hornet.TransactionMetadata) bool, balanceFromPreviousMilestone func(address hornet.Hash) (uint64, error), trunkHash hornet.Hash, branchHash ...hornet.Hash) (*WhiteFlagMutations, error) {
+	if workers := GetTraversalWorkers(); workers > 1 {
+		return computeWhiteFlagMutationsParallel(cachedTxMetas, cachedBundles, merkleTreeHashFunc, traverse, balanceFromPreviousMilestone, workers, trunkHash, branchHash...)
+	}
+	return computeWhiteFlagMutationsSerial(cachedTxMetas, cachedBundles, merkleTreeHashFunc, traverse, balanceFromPreviousMilestone, trunkHash, branchHash...)
+}
+
+func newWhiteFlagMutations() *WhiteFlagMutations {
+	return &WhiteFlagMutations{
 		TailsIncluded:            make(hornet.Hashes, 0),
 		TailsExcludedConflicting: make(hornet.Hashes, 0),
+		ConflictReasons:          make(map[string]hornet.ConflictReason),
 		TailsExcludedZeroValue:   make(hornet.Hashes, 0),
 		TailsReferenced:          make(hornet.Hashes, 0),
 		NewAddressState:          make(map[string]int64),
 		AddressMutations:         make(map[string]int64),
 	}
+}
+
+// loadConeBundle loads the bundle of the given tail of the cone and checks it against the white-flag rules.
+// The loaded metadata and bundle are added to the given maps, if they don't contain them already.
+// Returns whether the tail passes the traverse function.
+func loadConeBundle(cachedTxMeta *tangle.CachedMetadata, cachedBundles map[string]*tangle.CachedBundle, traverse func(txMeta *hornet.TransactionMetadata) bool) (*tangle.CachedBundle, bool, error) {
+	if !cachedTxMeta.GetMetadata().IsTail() {
+		return nil, false, fmt.Errorf("%w: candidate tx %s is not a tail of a bundle", ErrMilestoneApprovedInvalidBundle, cachedTxMeta.GetMetadata().GetTxHash().Trytes())
+	}
+
+	// load up bundle
+	cachedBundle, exists := cachedBundles[string(cachedTxMeta.GetMetadata().GetTxHash())]
+	if !exists {
+		cachedBundle = tangle.GetCachedBundleOrNil(cachedTxMeta.GetMetadata().GetTxHash()) // bundle +1
+		if cachedBundle == nil {
+			return nil, false, fmt.Errorf("%w: bundle %s of candidate tx %s doesn't exist", tangle.ErrBundleNotFound, cachedTxMeta.GetMetadata().GetBundleHash().Trytes(), cachedTxMeta.GetMetadata().GetTxHash().Trytes())
+		}
+		// release the bundles at the end to speed up calculation
+		cachedBundles[string(cachedTxMeta.GetMetadata().GetTxHash())] = cachedBundle
+	}
+
+	// check validty and correct strict semantics
+	if !cachedBundle.GetBundle().IsValid() || !cachedBundle.GetBundle().ValidStrictSemantics() {
+		return nil, false, fmt.Errorf("%w: bundle %s is invalid", ErrMilestoneApprovedInvalidBundle, cachedBundle.GetBundle().GetBundleHash().Trytes())
+	}
+
+	return cachedBundle, traverse(cachedTxMeta.GetMetadata()), nil
+}
+
+// computeWhiteFlagMutationsSerial computes the white-flag mutations during a single post-order depth-first search.
+func computeWhiteFlagMutationsSerial(cachedTxMetas map[string]*tangle.CachedMetadata, cachedBundles map[string]*tangle.CachedBundle, merkleTreeHashFunc crypto.Hash, traverse func(txMeta *hornet.TransactionMetadata) bool, balanceFromPreviousMilestone func(address hornet.Hash) (uint64, error), trunkHash hornet.Hash, branchHash ...hornet.Hash) (*WhiteFlagMutations, error) {
+	wfConf := newWhiteFlagMutations()
 
 	// traversal stops if no more transactions pass the given condition
 	// Caution: condition func is not in DFS order
@@ -79,28 +161,8 @@ func ComputeWhiteFlagMutations(cachedTxMetas map[string]*tangle.CachedMetadata,
 			cachedTxMetas[string(cachedTxMeta.GetMetadata().GetTxHash())] = cachedTxMeta.Retain()
 		}
 
-		if !cachedTxMeta.GetMetadata().IsTail() {
-			return false, fmt.Errorf("%w: candidate tx %s is not a tail of a bundle", ErrMilestoneApprovedInvalidBundle, cachedTxMeta.GetMetadata().GetTxHash().Trytes())
-		}
-
-		// load up bundle
-		cachedBundle, exists := cachedBundles[string(cachedTxMeta.GetMetadata().GetTxHash())]
-		if !exists {
-			cachedBundle = tangle.GetCachedBundleOrNil(cachedTxMeta.GetMetadata().GetTxHash()) // bundle +1
-			if cachedBundle == nil {
-				return false, fmt.Errorf("%w: bundle %s of candidate tx %s doesn't exist", tangle.ErrBundleNotFound, cachedTxMeta.GetMetadata().GetBundleHash().Trytes(), cachedTxMeta.GetMetadata().GetTxHash().Trytes())
-			}
-			// release the bundles at the end to speed up calculation
-			cachedBundles[string(cachedTxMeta.GetMetadata().GetTxHash())] = cachedBundle
-		}
-
-		// check validty and correct strict semantics
-		if !cachedBundle.GetBundle().IsValid() || !cachedBundle.GetBundle().ValidStrictSemantics() {
-			return false, fmt.Errorf("%w: bundle %s is invalid", ErrMilestoneApprovedInvalidBundle, cachedBundle.GetBundle().GetBundleHash().Trytes())
-		}
-
-		// only traverse and process the transaction if it was not confirmed yet
-		return !cachedTxMeta.GetMetadata().IsConfirmed(), nil
+		_, traverseTx, err := loadConeBundle(cachedTxMeta, cachedBundles, traverse)
+		return traverseTx, err
 	}
 
 	// consumer
@@ -114,109 +176,118 @@ func ComputeWhiteFlagMutations(cachedTxMetas map[string]*tangle.CachedMetadata,
 		}
 		defer cachedBundle.Release(true)
 
-		// exclude zero or spam value bundles
-		bundle := cachedBundle.GetBundle()
-		mutations := bundle.GetLedgerChanges()
-		if bundle.IsValueSpam() || len(mutations) == 0 {
-			wfConf.TailsReferenced = append(wfConf.TailsReferenced, cachedTxMeta.GetMetadata().GetTxHash())
-			wfConf.TailsExcludedZeroValue = append(wfConf.TailsExcludedZeroValue, cachedTxMeta.GetMetadata().GetTxHash())
-			return nil
-		}
+		return wfConf.applyBundle(cachedTxMeta.GetMetadata().GetTxHash(), cachedBundle.GetBundle(), balanceFromPreviousMilestone)
+	}
 
-		var conflicting bool
-
-		// contains the updated mutations from this bundle against the
-		// current mutations of the milestone's confirming cone (or previous ledger state).
-		// we only apply it to the milestone's confirming cone mutations if
-		// the bundle doesn't create any conflict.
-		patchedState := make(map[string]int64)
-		validMutations := make(map[string]int64)
-
-		for addr, change := range mutations {
-
-			// load state from milestone cone mutation or previous milestone
-			balance, has := wfConf.NewAddressState[addr]
-			if !has {
-				balanceStateFromPreviousMilestone, _, err := tangle.GetBalanceForAddressWithoutLocking(hornet.Hash(addr))
-				if err != nil {
-					return fmt.Errorf("%w: unable to retrieve balance of address %s", err, addr)
-				}
-				balance = int64(balanceStateFromPreviousMilestone)
-			}
+	// This function does the DFS and computes the mutations a white-flag confirmation would create.
+	// If trunk and branch of a bundle head transaction are both SEPs, are already processed or already confirmed,
+	// then the mutations from the transaction retrieved from the stack are accumulated to the given Confirmation struct's mutations.
+	// If the popped transaction was used to mutate the Confirmation struct, it will also be appended to Confirmation.TailsIncluded.
+	// if a branch hash is given, first walk trunk then branch
+	if err := dag.TraverseApproveesParents(append(hornet.Hashes{trunkHash}, branchHash...),
+		condition,
+		consumer,
+		// called on missing approvees
+		// return error on missing approvees
+		nil,
+		// called on solid entry points
+		// Ignore solid entry points (snapshot milestone included)
+		nil,
+		false, true, nil); err != nil {
+		return nil, err
+	}
 
-			// note that there's no overflow of int64 values here
-			// as a valid bundle's transaction can not spend more than the total supply,
-			// meaning that newBalance could be max 2*total_supply or min -total_supply.
-			newBalance := balance + change
+	return wfConf.finalize(merkleTreeHashFunc)
+}
 
-			// on below zero or above total supply the mutation is invalid
-			if newBalance < 0 || math.AbsInt64(newBalance) > consts.TotalSupply {
-				conflicting = true
-				break
-			}
+// applyBundle applies the ledger changes of the given bundle to the mutations of the cone, if it doesn't create a conflict.
+func (wfConf *WhiteFlagMutations) applyBundle(tailHash hornet.Hash, bundle *tangle.Bundle, balanceFromPreviousMilestone func(address hornet.Hash) (uint64, error)) error {
 
-			patchedState[addr] = newBalance
-			validMutations[addr] = validMutations[addr] + change
-		}
+	// exclude zero or spam value bundles
+	mutations := bundle.GetLedgerChanges()
+	if bundle.IsValueSpam() || len(mutations) == 0 {
+		wfConf.TailsReferenced = append(wfConf.TailsReferenced, tailHash)
+		wfConf.TailsExcludedZeroValue = append(wfConf.TailsExcludedZeroValue, tailHash)
+		return nil
+	}
 
-		wfConf.TailsReferenced = append(wfConf.TailsReferenced, cachedTxMeta.GetMetadata().GetTxHash())
+	conflictReason := hornet.ConflictReasonNone
 
-		if conflicting {
-			wfConf.TailsExcludedConflicting = append(wfConf.TailsExcludedConflicting, cachedTxMeta.GetMetadata().GetTxHash())
-			return nil
-		}
+	// contains the updated mutations from this bundle against the
+	// current mutations of the milestone's confirming cone (or previous ledger state).
+	// we only apply it to the milestone's confirming cone mutations if
+	// the bundle doesn't create any conflict.
+	patchedState := make(map[string]int64)
+	validMutations := make(map[string]int64)
+
+	// the addresses are checked in sorted order, so the conflict reason of a bundle
+	// with several conflicting addresses is the same on every node.
+	addresses := make([]string, 0, len(mutations))
+	for addr := range mutations {
+		addresses = append(addresses, addr)
+	}
+	sort.Strings(addresses)
 
-		// mark the given tail to be part of milestone ledger changing tail inclusion set
-		wfConf.TailsIncluded = append(wfConf.TailsIncluded, cachedTxMeta.GetMetadata().GetTxHash())
+	for _, addr := range addresses {
+		change := mutations[addr]
 
-		// incorporate the mutations in accordance with the previous mutations
-		// in the milestone's confirming cone/previous ledger state.
-		for addr, balance := range patchedState {
-			wfConf.NewAddressState[addr] = balance
+		// load state from milestone cone mutation or previous milestone
+		balance, has := wfConf.NewAddressState[addr]
+		if !has {
+			balanceStateFromPreviousMilestone, err := balanceFromPreviousMilestone(hornet.Hash(addr))
+			if err != nil {
+				return fmt.Errorf("%w: unable to retrieve balance of address %s", err, addr)
+			}
+			balance = int64(balanceStateFromPreviousMilestone)
 		}
 
-		// incorporate the mutations in accordance with the previous mutations
-		for addr, mutation := range validMutations {
-			wfConf.AddressMutations[addr] = wfConf.AddressMutations[addr] + mutation
+		// note that there's no overflow of int64 values here
+		// as a valid bundle's transaction can not spend more than the total supply,
+		// meaning that newBalance could be max 2*total_supply or min -total_supply.
+		newBalance := balance + change
+
+		// on below zero or above total supply the mutation is invalid
+		if newBalance < 0 {
+			conflictReason = hornet.ConflictReasonInsufficientBalance
+			break
+		}
+		if math.AbsInt64(newBalance) > consts.TotalSupply {
+			conflictReason = hornet.ConflictReasonBalanceExceedsTotalSupply
+			break
 		}
 
+		patchedState[addr] = newBalance
+		validMutations[addr] = validMutations[addr] + change
+	}
+
+	wfConf.TailsReferenced = append(wfConf.TailsReferenced, tailHash)
+
+	if conflictReason != hornet.ConflictReasonNone {
+		wfConf.TailsExcludedConflicting = append(wfConf.TailsExcludedConflicting, tailHash)
+		wfConf.ConflictReasons[string(tailHash)] = conflictReason
 		return nil
 	}
 
-	// This function does the DFS and computes the mutations a white-flag confirmation would create.
-	// If trunk and branch of a bundle head transaction are both SEPs, are already processed or already confirmed,
-	// then the mutations from the transaction retrieved from the stack are accumulated to the given Confirmation struct's mutations.
-	// If the popped transaction was used to mutate the Confirmation struct, it will also be appended to Confirmation.TailsIncluded.
-	if len(branchHash) == 0 {
-		// no branch hash given, only walk trunk
-		if err := dag.TraverseApprovees(trunkHash,
-			condition,
-			consumer,
-			// called on missing approvees
-			// return error on missing approvees
-			nil,
-			// called on solid entry points
-			// Ignore solid entry points (snapshot milestone included)
-			nil,
-			false, true, nil); err != nil {
-			return nil, err
-		}
-	} else {
-		// branch hash given, first walk trunk then branch
-		if err := dag.TraverseApproveesTrunkBranch(trunkHash, branchHash[0],
-			condition,
-			consumer,
-			// called on missing approvees
-			// return error on missing approvees
-			nil,
-			// called on solid entry points
-			// Ignore solid entry points (snapshot milestone included)
-			nil,
-			false, true, nil); err != nil {
-			return nil, err
-		}
+	// mark the given tail to be part of milestone ledger changing tail inclusion set
+	wfConf.TailsIncluded = append(wfConf.TailsIncluded, tailHash)
+
+	// incorporate the mutations in accordance with the previous mutations
+	// in the milestone's confirming cone/previous ledger state.
+	for addr, balance := range patchedState {
+		wfConf.NewAddressState[addr] = balance
 	}
 
+	// incorporate the mutations in accordance with the previous mutations
+	for addr, mutation := range validMutations {
+		wfConf.AddressMutations[addr] = wfConf.AddressMutations[addr] + mutation
+	}
+
+	return nil
+}
+
+// finalize computes the merkle tree root hash of the included tails and checks the sums of the tails.
+func (wfConf *WhiteFlagMutations) finalize(merkleTreeHashFunc crypto.Hash) (*WhiteFlagMutations, error) {
+
 	// compute merkle tree root hash
 	wfConf.MerkleTreeHash = NewHasher(merkleTreeHashFunc).TreeHash(wfConf.TailsIncluded)
 
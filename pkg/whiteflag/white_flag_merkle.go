@@ -1,6 +1,7 @@
 package whiteflag
 
 import (
+	"bytes"
 	"crypto"
 	"math/bits"
 
@@ -58,6 +59,57 @@ func (t *Hasher) HashNode(l, r []byte) []byte {
 	return h.Sum(nil)
 }
 
+// AuditPath computes the RFC6962 audit path of the hash at the given index of the provided hashes.
+// The path starts with the sibling of the leaf and ends with the sibling just below the root.
+func (t *Hasher) AuditPath(tailHashes []hornet.Hash, index int) [][]byte {
+	if index < 0 || index >= len(tailHashes) {
+		panic("index out of range")
+	}
+	if len(tailHashes) == 1 {
+		return [][]byte{}
+	}
+
+	k := largestPowerOfTwo(len(tailHashes))
+	if index < k {
+		return append(t.AuditPath(tailHashes[:k], index), t.TreeHash(tailHashes[k:]))
+	}
+	return append(t.AuditPath(tailHashes[k:], index-k), t.TreeHash(tailHashes[:k]))
+}
+
+// VerifyAuditPath verifies that the given hash is the one at the given index of a tree of the given size
+// with the given Merkle tree hash, using the audit path of the hash.
+func (t *Hasher) VerifyAuditPath(hash hornet.Hash, index int, size int, auditPath [][]byte, root []byte) bool {
+	if index < 0 || index >= size {
+		return false
+	}
+
+	fn, sn := index, size-1
+	r := t.HashLeaf(hash)
+
+	for _, sibling := range auditPath {
+		if sn == 0 {
+			// the path is longer than the tree is deep
+			return false
+		}
+
+		if fn&1 == 1 || fn == sn {
+			r = t.HashNode(sibling, r)
+			// skip the levels on which the node has no right sibling
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = t.HashNode(r, sibling)
+		}
+
+		fn >>= 1
+		sn >>= 1
+	}
+
+	return sn == 0 && bytes.Equal(r, root)
+}
+
 // largestPowerOfTwo returns the largest power of two less than n.
 func largestPowerOfTwo(x int) int {
 	if x < 2 {
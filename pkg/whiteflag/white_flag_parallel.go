@@ -0,0 +1,255 @@
+package whiteflag
+
+import (
+	"crypto"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"go.uber.org/atomic"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+)
+
+var (
+	// the amount of workers which load the cone of a milestone in parallel.
+	traversalWorkers = atomic.NewInt32(int32(runtime.NumCPU()))
+)
+
+// SetTraversalWorkers sets the amount of workers which load the cone of a milestone in parallel.
+// With less than two workers the cone is traversed serially.
+func SetTraversalWorkers(workers int) {
+	traversalWorkers.Store(int32(workers))
+}
+
+// GetTraversalWorkers returns the amount of workers which load the cone of a milestone in parallel.
+func GetTraversalWorkers() int {
+	return int(traversalWorkers.Load())
+}
+
+// coneEntry holds the result of loading a transaction of the cone.
+type coneEntry struct {
+	txHash hornet.Hash
+	// whether the transaction is a solid entry point, its approvees are not part of the cone.
+	solidEntryPoint bool
+	// whether the transaction passed the traverse function.
+	traverse bool
+	// the error the serial traversal would stop with when reaching the transaction.
+	err error
+	// the bundle of the tail, nil if the transaction is not traversed.
+	bundle *tangle.Bundle
+	// the trunk and branch of the head of the bundle.
+	approvees hornet.Hashes
+}
+
+// coneLoader loads the transactions and bundles of a cone with parallel workers.
+// The workers don't apply any ledger changes, so the order in which branches are loaded doesn't matter.
+type coneLoader struct {
+	sync.Mutex
+	cond *sync.Cond
+
+	traverse func(txMeta *hornet.TransactionMetadata) bool
+
+	// the loaded and scheduled transactions of the cone.
+	cone map[string]*coneEntry
+	// the scheduled transactions which are not loaded yet.
+	pending []*coneEntry
+	// the amount of transactions which are currently loaded by a worker.
+	active int
+}
+
+func newConeLoader(traverse func(txMeta *hornet.TransactionMetadata) bool) *coneLoader {
+	l := &coneLoader{
+		traverse: traverse,
+		cone:     make(map[string]*coneEntry),
+	}
+	l.cond = sync.NewCond(l)
+	return l
+}
+
+// scheduleWithoutLocking schedules the given transaction to be loaded, if it was not scheduled before.
+func (l *coneLoader) scheduleWithoutLocking(txHash hornet.Hash) {
+	if _, exists := l.cone[string(txHash)]; exists {
+		return
+	}
+
+	entry := &coneEntry{txHash: txHash}
+	l.cone[string(txHash)] = entry
+	l.pending = append(l.pending, entry)
+}
+
+// load loads the cone of the given parents with the given amount of workers.
+// The loaded metadata and bundles are added to the given maps.
+func (l *coneLoader) load(cachedTxMetas map[string]*tangle.CachedMetadata, cachedBundles map[string]*tangle.CachedBundle, workers int, parents hornet.Hashes) {
+
+	l.Lock()
+	for _, parent := range parents {
+		l.scheduleWithoutLocking(parent)
+	}
+	l.Unlock()
+
+	workerTxMetas := make([]map[string]*tangle.CachedMetadata, workers)
+	workerBundles := make([]map[string]*tangle.CachedBundle, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		// every worker keeps its own maps, so they are not locked while loading
+		workerTxMetas[i] = make(map[string]*tangle.CachedMetadata)
+		workerBundles[i] = make(map[string]*tangle.CachedBundle)
+
+		go func(txMetas map[string]*tangle.CachedMetadata, bundles map[string]*tangle.CachedBundle) {
+			defer wg.Done()
+			l.work(txMetas, bundles)
+		}(workerTxMetas[i], workerBundles[i])
+	}
+	wg.Wait()
+
+	// every transaction was only loaded by a single worker,
+	// but the given maps may already contain some of them.
+	for i := 0; i < workers; i++ {
+		for txHash, cachedTxMeta := range workerTxMetas[i] {
+			if _, exists := cachedTxMetas[txHash]; exists {
+				cachedTxMeta.Release(true) // meta -1
+				continue
+			}
+			cachedTxMetas[txHash] = cachedTxMeta
+		}
+		for txHash, cachedBundle := range workerBundles[i] {
+			if _, exists := cachedBundles[txHash]; exists {
+				cachedBundle.Release(true) // bundle -1
+				continue
+			}
+			cachedBundles[txHash] = cachedBundle
+		}
+	}
+}
+
+// work loads the scheduled transactions until the whole cone is loaded.
+func (l *coneLoader) work(cachedTxMetas map[string]*tangle.CachedMetadata, cachedBundles map[string]*tangle.CachedBundle) {
+	for {
+		l.Lock()
+		for len(l.pending) == 0 && l.active > 0 {
+			// other workers may still schedule the approvees of their transactions
+			l.cond.Wait()
+		}
+
+		if len(l.pending) == 0 {
+			// the whole cone is loaded
+			l.Unlock()
+			return
+		}
+
+		// take the youngest scheduled transaction, so that the workers descend into the branches
+		entry := l.pending[len(l.pending)-1]
+		l.pending = l.pending[:len(l.pending)-1]
+		l.active++
+		l.Unlock()
+
+		l.loadEntry(entry, cachedTxMetas, cachedBundles)
+
+		l.Lock()
+		if entry.traverse && entry.err == nil {
+			for _, approveeHash := range entry.approvees {
+				l.scheduleWithoutLocking(approveeHash)
+			}
+		}
+		l.active--
+		l.cond.Broadcast()
+		l.Unlock()
+	}
+}
+
+// loadEntry loads the transaction of the given entry and checks it like the condition of the serial traversal.
+func (l *coneLoader) loadEntry(entry *coneEntry, cachedTxMetas map[string]*tangle.CachedMetadata, cachedBundles map[string]*tangle.CachedBundle) {
+
+	if tangle.SolidEntryPointsContain(entry.txHash) {
+		entry.solidEntryPoint = true
+		return
+	}
+
+	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(entry.txHash) // meta +1
+	if cachedTxMeta == nil {
+		entry.err = fmt.Errorf("%w: transaction %s", tangle.ErrTransactionNotFound, entry.txHash.Trytes())
+		return
+	}
+	// release the tx metadata at the end to speed up calculation
+	cachedTxMetas[string(entry.txHash)] = cachedTxMeta
+
+	cachedBundle, traverse, err := loadConeBundle(cachedTxMeta, cachedBundles, l.traverse)
+	if err != nil {
+		entry.err = err
+		return
+	}
+
+	entry.traverse = traverse
+	if !traverse {
+		return
+	}
+
+	entry.bundle = cachedBundle.GetBundle()
+	entry.approvees = hornet.Hashes{entry.bundle.GetTrunkHash(true), entry.bundle.GetBranchHash(true)}
+}
+
+// computeWhiteFlagMutationsParallel loads the cone with the given amount of parallel workers and afterwards
+// applies the bundles in the same post-order depth-first order as computeWhiteFlagMutationsSerial.
+// The result, including the error of an invalid cone, is the same as the one of the serial traversal.
+func computeWhiteFlagMutationsParallel(cachedTxMetas map[string]*tangle.CachedMetadata, cachedBundles map[string]*tangle.CachedBundle, merkleTreeHashFunc crypto.Hash, traverse func(txMeta *hornet.TransactionMetadata) bool, balanceFromPreviousMilestone func(address hornet.Hash) (uint64, error), workers int, trunkHash hornet.Hash, branchHash ...hornet.Hash) (*WhiteFlagMutations, error) {
+
+	parents := append(hornet.Hashes{trunkHash}, branchHash...)
+
+	loader := newConeLoader(traverse)
+	loader.load(cachedTxMetas, cachedBundles, workers, parents)
+
+	wfConf := newWhiteFlagMutations()
+
+	// the merge step walks the loaded cone in the order of the serial traversal,
+	// so the ledger changes are applied deterministically.
+	processed := make(map[string]struct{})
+	for _, parent := range parents {
+		stack := hornet.Hashes{parent}
+
+		for len(stack) > 0 {
+			txHash := stack[len(stack)-1]
+
+			if _, wasProcessed := processed[string(txHash)]; wasProcessed {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			entry := loader.cone[string(txHash)]
+			if entry.err != nil {
+				return nil, entry.err
+			}
+
+			if entry.solidEntryPoint || !entry.traverse {
+				processed[string(txHash)] = struct{}{}
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			descended := false
+			for _, approveeHash := range entry.approvees {
+				if _, approveeProcessed := processed[string(approveeHash)]; !approveeProcessed {
+					// first walk trunk then branch
+					stack = append(stack, approveeHash)
+					descended = true
+					break
+				}
+			}
+			if descended {
+				continue
+			}
+
+			processed[string(txHash)] = struct{}{}
+			stack = stack[:len(stack)-1]
+
+			if err := wfConf.applyBundle(txHash, entry.bundle, balanceFromPreviousMilestone); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return wfConf.finalize(merkleTreeHashFunc)
+}
@@ -0,0 +1,75 @@
+package workerpool
+
+import (
+	"runtime"
+)
+
+// the default options applied to a WorkerPool.
+var defaultOptions = &Options{
+	Name:                 "",
+	WorkerCount:          2 * runtime.NumCPU(),
+	QueueSize:            4 * runtime.NumCPU(),
+	SaturationThreshold:  0.75,
+	FlushTasksAtShutdown: false,
+}
+
+// Options define options for a WorkerPool.
+type Options struct {
+	// Name is the name of the pool; named pools are registered for their metrics.
+	Name string
+	// WorkerCount is the amount of workers processing the tasks.
+	WorkerCount int
+	// QueueSize is the size of the queue of every priority lane.
+	QueueSize int
+	// SaturationThreshold is the fraction of the queue size at which a lane is considered saturated.
+	SaturationThreshold float64
+	// FlushTasksAtShutdown defines whether the queued tasks are processed after the pool was stopped.
+	FlushTasksAtShutdown bool
+}
+
+// applies the given Option.
+func (o Options) apply(opts ...Option) *Options {
+	result := &o
+	for _, opt := range opts {
+		opt(result)
+	}
+	return result
+}
+
+// Option is a function setting a WorkerPool option.
+type Option func(opts *Options)
+
+// Name sets the name of the pool.
+func Name(name string) Option {
+	return func(opts *Options) {
+		opts.Name = name
+	}
+}
+
+// WorkerCount sets the amount of workers.
+func WorkerCount(workerCount int) Option {
+	return func(opts *Options) {
+		opts.WorkerCount = workerCount
+	}
+}
+
+// QueueSize sets the size of the queue of every priority lane.
+func QueueSize(queueSize int) Option {
+	return func(opts *Options) {
+		opts.QueueSize = queueSize
+	}
+}
+
+// SaturationThreshold sets the fraction of the queue size at which a lane is considered saturated.
+func SaturationThreshold(threshold float64) Option {
+	return func(opts *Options) {
+		opts.SaturationThreshold = threshold
+	}
+}
+
+// FlushTasksAtShutdown defines whether the queued tasks are processed after the pool was stopped.
+func FlushTasksAtShutdown(flush bool) Option {
+	return func(opts *Options) {
+		opts.FlushTasksAtShutdown = flush
+	}
+}
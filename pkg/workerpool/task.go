@@ -0,0 +1,18 @@
+package workerpool
+
+// Task is a unit of work submitted to a WorkerPool.
+type Task struct {
+	params     []interface{}
+	resultChan chan interface{}
+}
+
+// Return sends the result of the task to the submitter.
+func (task *Task) Return(result interface{}) {
+	task.resultChan <- result
+	close(task.resultChan)
+}
+
+// Param returns the parameter of the task at the given index.
+func (task *Task) Param(index int) interface{} {
+	return task.params[index]
+}
@@ -0,0 +1,355 @@
+package workerpool
+
+import (
+	"sync"
+
+	"go.uber.org/atomic"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/syncutils"
+)
+
+// Priority is the priority of a task.
+// Tasks of a higher priority are always processed before queued tasks of a lower priority.
+type Priority int
+
+const (
+	// PriorityNormal is the priority of regular tasks.
+	PriorityNormal Priority = iota
+	// PriorityHigh is the priority of milestone related tasks.
+	PriorityHigh
+
+	// the amount of priority lanes of a pool.
+	priorityCount = 2
+)
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	switch p {
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	// the named pools, which are exposed via Metrics.
+	pools     []*WorkerPool
+	poolsLock syncutils.RWMutex
+)
+
+// QueueCaller is the caller of the queue length events.
+func QueueCaller(handler interface{}, params ...interface{}) {
+	handler.(func(priority Priority, queueLength int))(params[0].(Priority), params[1].(int))
+}
+
+// Events are the events fired by a WorkerPool.
+type Events struct {
+	// Fired when the queue of a lane reaches the saturation threshold.
+	// Handlers are called by the submitter and must not block.
+	QueueSaturated *events.Event
+	// Fired when the queue of a saturated lane falls below half of the saturation threshold again.
+	QueueRelieved *events.Event
+}
+
+// LaneMetrics contains the metrics of a priority lane of a WorkerPool.
+type LaneMetrics struct {
+	Priority    Priority `json:"priority"`
+	QueueLength int      `json:"queueLength"`
+	QueueSize   int      `json:"queueSize"`
+	Saturated   bool     `json:"saturated"`
+	Submitted   uint64   `json:"submitted"`
+	Processed   uint64   `json:"processed"`
+	Dropped     uint64   `json:"dropped"`
+}
+
+// PoolMetrics contains the metrics of a WorkerPool.
+type PoolMetrics struct {
+	Name        string        `json:"name"`
+	WorkerCount int           `json:"workerCount"`
+	Lanes       []LaneMetrics `json:"lanes"`
+}
+
+// a queue of tasks of the same priority.
+type lane struct {
+	calls     chan Task
+	saturated atomic.Bool
+	submitted atomic.Uint64
+	processed atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+// WorkerPool processes submitted tasks with a fixed amount of workers.
+// The tasks are queued in bounded lanes per priority, which gives backpressure to the submitters
+// instead of spawning an unbounded amount of goroutines under heavy load.
+type WorkerPool struct {
+	Events Events
+
+	workerFnc func(Task)
+	opts      *Options
+
+	lanes               [priorityCount]*lane
+	saturationThreshold int
+	terminate           chan struct{}
+
+	running  bool
+	shutdown bool
+
+	lock syncutils.RWMutex
+	wait sync.WaitGroup
+}
+
+// New creates a new WorkerPool which calls the given function for every submitted task.
+// Pools with a name are registered for their metrics, a pool replaces a registered pool with the same name.
+func New(workerFnc func(Task), opts ...Option) *WorkerPool {
+	options := defaultOptions.apply(opts...)
+
+	wp := &WorkerPool{
+		Events: Events{
+			QueueSaturated: events.NewEvent(QueueCaller),
+			QueueRelieved:  events.NewEvent(QueueCaller),
+		},
+		workerFnc:           workerFnc,
+		opts:                options,
+		saturationThreshold: int(float64(options.QueueSize) * options.SaturationThreshold),
+		terminate:           make(chan struct{}),
+	}
+
+	for i := range wp.lanes {
+		wp.lanes[i] = &lane{calls: make(chan Task, options.QueueSize)}
+	}
+
+	if options.Name != "" {
+		register(wp)
+	}
+
+	return wp
+}
+
+// registers the given named pool.
+func register(wp *WorkerPool) {
+	poolsLock.Lock()
+	defer poolsLock.Unlock()
+
+	for i, pool := range pools {
+		if pool.opts.Name == wp.opts.Name {
+			pools[i] = wp
+			return
+		}
+	}
+	pools = append(pools, wp)
+}
+
+// Metrics returns the metrics of all named pools.
+func Metrics() []*PoolMetrics {
+	poolsLock.RLock()
+	defer poolsLock.RUnlock()
+
+	result := make([]*PoolMetrics, len(pools))
+	for i, pool := range pools {
+		result[i] = pool.Metrics()
+	}
+	return result
+}
+
+// Name returns the name of the pool.
+func (wp *WorkerPool) Name() string {
+	return wp.opts.Name
+}
+
+// Submit submits a task of normal priority and blocks if the queue is full.
+func (wp *WorkerPool) Submit(params ...interface{}) (result chan interface{}, added bool) {
+	return wp.SubmitWithPriority(PriorityNormal, params...)
+}
+
+// SubmitWithPriority submits a task of the given priority and blocks if the queue of the lane is full.
+func (wp *WorkerPool) SubmitWithPriority(priority Priority, params ...interface{}) (result chan interface{}, added bool) {
+	wp.lock.RLock()
+	defer wp.lock.RUnlock()
+
+	if wp.shutdown {
+		return nil, false
+	}
+
+	l := wp.lanes[priority]
+	result = make(chan interface{}, 1)
+	l.calls <- Task{params: params, resultChan: result}
+	wp.submitted(priority, l)
+
+	return result, true
+}
+
+// TrySubmit submits a task of normal priority and drops it if the queue is full.
+func (wp *WorkerPool) TrySubmit(params ...interface{}) (result chan interface{}, added bool) {
+	return wp.TrySubmitWithPriority(PriorityNormal, params...)
+}
+
+// TrySubmitWithPriority submits a task of the given priority and drops it if the queue of the lane is full.
+func (wp *WorkerPool) TrySubmitWithPriority(priority Priority, params ...interface{}) (result chan interface{}, added bool) {
+	wp.lock.RLock()
+	defer wp.lock.RUnlock()
+
+	if wp.shutdown {
+		return nil, false
+	}
+
+	l := wp.lanes[priority]
+	result = make(chan interface{}, 1)
+	select {
+	case l.calls <- Task{params: params, resultChan: result}:
+		wp.submitted(priority, l)
+		return result, true
+	default:
+		// queue full => drop the task
+		l.dropped.Inc()
+		close(result)
+		return nil, false
+	}
+}
+
+// updates the metrics of the lane after a task was submitted and fires the saturation event.
+func (wp *WorkerPool) submitted(priority Priority, l *lane) {
+	l.submitted.Inc()
+
+	if queueLength := len(l.calls); queueLength >= wp.saturationThreshold && l.saturated.CAS(false, true) {
+		wp.Events.QueueSaturated.Trigger(priority, queueLength)
+	}
+}
+
+// processes the given task and fires the relief event if the lane is not saturated anymore.
+func (wp *WorkerPool) process(priority Priority, task Task) {
+	l := wp.lanes[priority]
+
+	if queueLength := len(l.calls); queueLength <= wp.saturationThreshold/2 && l.saturated.CAS(true, false) {
+		wp.Events.QueueRelieved.Trigger(priority, queueLength)
+	}
+
+	wp.workerFnc(task)
+	l.processed.Inc()
+}
+
+// Start starts the workers of the pool.
+func (wp *WorkerPool) Start() {
+	wp.lock.Lock()
+	defer wp.lock.Unlock()
+
+	if wp.running {
+		return
+	}
+
+	if wp.shutdown {
+		panic("worker pool was already used before")
+	}
+	wp.running = true
+
+	for i := 0; i < wp.opts.WorkerCount; i++ {
+		wp.wait.Add(1)
+		go wp.worker()
+	}
+}
+
+// Stop stops the workers of the pool.
+func (wp *WorkerPool) Stop() {
+	wp.lock.Lock()
+	defer wp.lock.Unlock()
+
+	if wp.running {
+		wp.shutdown = true
+		wp.running = false
+		close(wp.terminate)
+	}
+}
+
+// StopAndWait stops the workers of the pool and waits until they finished.
+func (wp *WorkerPool) StopAndWait() {
+	wp.Stop()
+	wp.wait.Wait()
+}
+
+// GetWorkerCount returns the amount of workers of the pool.
+func (wp *WorkerPool) GetWorkerCount() int {
+	return wp.opts.WorkerCount
+}
+
+// GetPendingQueueSize returns the amount of queued tasks of all lanes.
+func (wp *WorkerPool) GetPendingQueueSize() int {
+	var size int
+	for _, l := range wp.lanes {
+		size += len(l.calls)
+	}
+	return size
+}
+
+// Metrics returns the metrics of the pool.
+func (wp *WorkerPool) Metrics() *PoolMetrics {
+	result := &PoolMetrics{
+		Name:        wp.opts.Name,
+		WorkerCount: wp.opts.WorkerCount,
+		Lanes:       make([]LaneMetrics, len(wp.lanes)),
+	}
+
+	for i, l := range wp.lanes {
+		result.Lanes[i] = LaneMetrics{
+			Priority:    Priority(i),
+			QueueLength: len(l.calls),
+			QueueSize:   wp.opts.QueueSize,
+			Saturated:   l.saturated.Load(),
+			Submitted:   l.submitted.Load(),
+			Processed:   l.processed.Load(),
+			Dropped:     l.dropped.Load(),
+		}
+	}
+
+	return result
+}
+
+func (wp *WorkerPool) worker() {
+	defer wp.wait.Done()
+
+	high, normal := wp.lanes[PriorityHigh].calls, wp.lanes[PriorityNormal].calls
+
+	for {
+		select {
+		case <-wp.terminate:
+			if wp.opts.FlushTasksAtShutdown {
+				wp.flush()
+			}
+			return
+		default:
+		}
+
+		// queued tasks of high priority are always processed first
+		select {
+		case task := <-high:
+			wp.process(PriorityHigh, task)
+			continue
+		default:
+		}
+
+		select {
+		case <-wp.terminate:
+		case task := <-high:
+			wp.process(PriorityHigh, task)
+		case task := <-normal:
+			wp.process(PriorityNormal, task)
+		}
+	}
+}
+
+// processes all queued tasks after the shutdown signal, ordered by priority.
+func (wp *WorkerPool) flush() {
+	for priority := Priority(priorityCount - 1); priority >= PriorityNormal; priority-- {
+		for {
+			select {
+			case task := <-wp.lanes[priority].calls:
+				wp.process(priority, task)
+				continue
+			default:
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package workerpool
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iotaledger/hive.go/events"
+)
+
+func TestWorkerPoolPriorities(t *testing.T) {
+	var processedWg sync.WaitGroup
+	var processed []int
+
+	wp := New(func(task Task) {
+		processed = append(processed, task.Param(0).(int))
+		task.Return(nil)
+		processedWg.Done()
+	}, WorkerCount(1), QueueSize(4), SaturationThreshold(0.5))
+
+	var saturated []Priority
+	wp.Events.QueueSaturated.Attach(events.NewClosure(func(priority Priority, _ int) {
+		saturated = append(saturated, priority)
+	}))
+
+	// queue the tasks before the workers are started
+	for i := 0; i < 4; i++ {
+		_, added := wp.TrySubmit(i)
+		assert.True(t, added)
+	}
+	_, added := wp.TrySubmit(4)
+	assert.False(t, added)
+
+	_, added = wp.TrySubmitWithPriority(PriorityHigh, 10)
+	assert.True(t, added)
+
+	assert.Equal(t, []Priority{PriorityNormal}, saturated)
+	assert.Equal(t, 5, wp.GetPendingQueueSize())
+
+	processedWg.Add(5)
+	wp.Start()
+	processedWg.Wait()
+	wp.StopAndWait()
+
+	// the high priority task is processed first
+	assert.Equal(t, []int{10, 0, 1, 2, 3}, processed)
+
+	metrics := wp.Metrics()
+	assert.Equal(t, uint64(4), metrics.Lanes[PriorityNormal].Submitted)
+	assert.Equal(t, uint64(1), metrics.Lanes[PriorityNormal].Dropped)
+	assert.Equal(t, uint64(1), metrics.Lanes[PriorityHigh].Processed)
+}
+
+func TestWorkerPoolFlush(t *testing.T) {
+	var processed []int
+
+	wp := New(func(task Task) {
+		processed = append(processed, task.Param(0).(int))
+		task.Return(nil)
+	}, WorkerCount(1), QueueSize(4), FlushTasksAtShutdown(true))
+
+	wp.Submit(1)
+	wp.SubmitWithPriority(PriorityHigh, 2)
+
+	// start and stop directly, so that the tasks are flushed ordered by priority
+	wp.Start()
+	wp.StopAndWait()
+
+	assert.Len(t, processed, 2)
+	assert.Equal(t, 2, processed[0])
+
+	_, added := wp.Submit(3)
+	assert.False(t, added)
+}
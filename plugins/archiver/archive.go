@@ -0,0 +1,365 @@
+package archiver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/compressed"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+)
+
+const (
+	// ArchiveFileVersion is the version of the archive files written by this node.
+	ArchiveFileVersion byte = 2
+)
+
+// the ledger inclusion states of archived transactions.
+const (
+	archivedStateIncluded      byte = 0
+	archivedStateNoTransaction byte = 1
+	archivedStateConflicting   byte = 2
+)
+
+var (
+	// ErrUnsupportedArchiveFileVersion is returned if an archive file has an unsupported version.
+	ErrUnsupportedArchiveFileVersion = errors.New("unsupported archive file version")
+	// ErrArchivedTransactionHashMismatch is returned if the hash of an archived transaction doesn't match its bytes.
+	ErrArchivedTransactionHashMismatch = errors.New("archived transaction hash mismatch")
+	// ErrArchiveImportWasAborted is returned if the import of an archive file was aborted.
+	ErrArchiveImportWasAborted = errors.New("archive import was aborted")
+	// ErrArchivedMilestoneNotVerified is returned if the milestone bundle of an archived milestone is missing or invalid.
+	ErrArchivedMilestoneNotVerified = errors.New("archived milestone could not be verified")
+	// ErrInvalidArchivedLedgerInclusionState is returned if an archived transaction has an unknown ledger inclusion state.
+	ErrInvalidArchivedLedgerInclusionState = errors.New("invalid archived ledger inclusion state")
+)
+
+// ArchivedTransaction is a transaction of an archive file with the white-flag state of its bundle.
+type ArchivedTransaction struct {
+	Transaction *hornet.Transaction
+	// the ledger inclusion state of the bundle, one of included, noTransaction or conflicting.
+	LedgerInclusionState hornet.LedgerInclusionState
+	// the reason why the bundle was conflicting.
+	ConflictReason hornet.ConflictReason
+}
+
+// archiveFilePath returns the path of the archive file which contains the given milestone.
+//
+// Archive file format:
+//
+//	version					1 byte
+//	milestones:
+//		index				4 bytes
+//		milestone hash		49 bytes
+//		transactions count	4 bytes
+//		transactions:
+//			tx hash			49 bytes
+//			inclusion state	1 byte (0 = included, 1 = no transaction, 2 = conflicting)
+//			conflict reason	1 byte
+//			tx bytes length	2 bytes
+//			tx bytes		truncated transaction bytes
+func archiveFilePath(msIndex milestone.Index) string {
+	firstIndex := ((msIndex-1)/milestonesPerFile)*milestonesPerFile + 1
+	lastIndex := firstIndex + milestonesPerFile - 1
+	return filepath.Join(archivePath, fmt.Sprintf("%d-%d.archive", firstIndex, lastIndex))
+}
+
+// collectConfirmedTransactions returns all transactions referenced by the given milestone confirmation
+// together with the white-flag state of their bundles.
+// the cachedTxs have to be released outside.
+func collectConfirmedTransactions(confirmation *whiteflag.Confirmation) (tangle.CachedTransactions, []*ArchivedTransaction, error) {
+	var cachedTxs tangle.CachedTransactions
+	var archivedTxs []*ArchivedTransaction
+
+	inclusionStates := make(map[string]hornet.LedgerInclusionState)
+	for _, tailTxHash := range confirmation.Mutations.TailsIncluded {
+		inclusionStates[string(tailTxHash)] = hornet.LedgerInclusionStateIncluded
+	}
+	for _, tailTxHash := range confirmation.Mutations.TailsExcludedZeroValue {
+		inclusionStates[string(tailTxHash)] = hornet.LedgerInclusionStateNoTransaction
+	}
+	for _, tailTxHash := range confirmation.Mutations.TailsExcludedConflicting {
+		inclusionStates[string(tailTxHash)] = hornet.LedgerInclusionStateConflicting
+	}
+
+	for _, tailTxHash := range confirmation.Mutations.TailsReferenced {
+		cachedBundle := tangle.GetCachedBundleOrNil(tailTxHash) // bundle +1
+		if cachedBundle == nil {
+			cachedTxs.Release(true) // tx -1
+			return nil, nil, errors.Wrapf(tangle.ErrBundleNotFound, "tail %s", tailTxHash.Trytes())
+		}
+
+		bundleTxs := cachedBundle.GetBundle().GetTransactions() // tx +1
+		cachedBundle.Release(true)                              // bundle -1
+
+		for _, cachedTx := range bundleTxs {
+			archivedTxs = append(archivedTxs, &ArchivedTransaction{
+				Transaction:          cachedTx.GetTransaction(),
+				LedgerInclusionState: inclusionStates[string(tailTxHash)],
+				ConflictReason:       confirmation.Mutations.ConflictReasons[string(tailTxHash)],
+			})
+		}
+		cachedTxs = append(cachedTxs, bundleTxs...)
+	}
+
+	return cachedTxs, archivedTxs, nil
+}
+
+// archiveMilestone appends all transactions referenced by the given milestone confirmation to the archive file of the milestone.
+func archiveMilestone(confirmation *whiteflag.Confirmation) error {
+
+	cachedTxs, archivedTxs, err := collectConfirmedTransactions(confirmation) // tx +1
+	if err != nil {
+		return err
+	}
+	defer cachedTxs.Release(true) // tx -1
+
+	// the whole milestone is appended with a single write
+	var buf bytes.Buffer
+	if err := writeArchivedMilestone(&buf, confirmation.MilestoneIndex, confirmation.MilestoneHash, archivedTxs); err != nil {
+		return err
+	}
+
+	return appendToArchiveFile(archiveFilePath(confirmation.MilestoneIndex), buf.Bytes())
+}
+
+// writeArchivedMilestone writes the given milestone and the transactions it confirmed in the format of the archive files.
+func writeArchivedMilestone(w io.Writer, msIndex milestone.Index, msHash hornet.Hash, txs []*ArchivedTransaction) error {
+	for _, value := range []interface{}{msIndex, msHash[:hornet.HashBinarySize], uint32(len(txs))} {
+		if err := binary.Write(w, binary.LittleEndian, value); err != nil {
+			return err
+		}
+	}
+
+	for _, archivedTx := range txs {
+		var inclusionState byte
+		switch archivedTx.LedgerInclusionState {
+		case hornet.LedgerInclusionStateIncluded:
+			inclusionState = archivedStateIncluded
+		case hornet.LedgerInclusionStateNoTransaction:
+			inclusionState = archivedStateNoTransaction
+		case hornet.LedgerInclusionStateConflicting:
+			inclusionState = archivedStateConflicting
+		default:
+			return errors.Wrapf(ErrInvalidArchivedLedgerInclusionState, "tx %s: %s", archivedTx.Transaction.GetTxHash().Trytes(), archivedTx.LedgerInclusionState)
+		}
+
+		tx := archivedTx.Transaction
+		for _, value := range []interface{}{tx.GetTxHash()[:hornet.HashBinarySize], inclusionState, byte(archivedTx.ConflictReason), uint16(len(tx.RawBytes)), tx.RawBytes} {
+			if err := binary.Write(w, binary.LittleEndian, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// appendToArchiveFile appends the given archived milestones to the archive file at the given path.
+// The file is created with the version header if it doesn't exist yet.
+func appendToArchiveFile(filePath string, archivedMilestones []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	archiveFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0660)
+	if err != nil {
+		return err
+	}
+	defer archiveFile.Close()
+
+	fileInfo, err := archiveFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	if fileInfo.Size() == 0 {
+		if err := binary.Write(archiveFile, binary.LittleEndian, ArchiveFileVersion); err != nil {
+			return err
+		}
+	}
+
+	_, err = archiveFile.Write(archivedMilestones)
+	return err
+}
+
+// ArchivedMilestoneConsumerFunc consumes a milestone of an archive file and the transactions it confirmed.
+type ArchivedMilestoneConsumerFunc func(msIndex milestone.Index, msHash hornet.Hash, txs []*ArchivedTransaction) error
+
+// StreamArchiveFile reads the given archive file and passes every archived milestone to the consumer.
+// It returns an error wrapping ErrArchivedTransactionHashMismatch if the hash of a transaction doesn't match its bytes.
+func StreamArchiveFile(filePath string, consumer ArchivedMilestoneConsumerFunc) error {
+
+	archiveFile, err := os.OpenFile(filePath, os.O_RDONLY, 0666)
+	if err != nil {
+		return err
+	}
+	defer archiveFile.Close()
+
+	reader := bufio.NewReader(archiveFile)
+
+	var fileVersion byte
+	if err := binary.Read(reader, binary.LittleEndian, &fileVersion); err != nil {
+		return err
+	}
+
+	if fileVersion != ArchiveFileVersion {
+		return errors.Wrapf(ErrUnsupportedArchiveFileVersion, "archive file version is %d but this HORNET version only supports %d", fileVersion, ArchiveFileVersion)
+	}
+
+	for {
+		var msIndex milestone.Index
+		if err := binary.Read(reader, binary.LittleEndian, &msIndex); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrapf(err, "milestone index")
+		}
+
+		msHash := make(hornet.Hash, hornet.HashBinarySize)
+		if _, err := io.ReadFull(reader, msHash); err != nil {
+			return errors.Wrapf(err, "milestone %d", msIndex)
+		}
+
+		var txCount uint32
+		if err := binary.Read(reader, binary.LittleEndian, &txCount); err != nil {
+			return errors.Wrapf(err, "milestone %d", msIndex)
+		}
+
+		txs := make([]*ArchivedTransaction, 0, txCount)
+		for i := uint32(0); i < txCount; i++ {
+			archivedTx, err := readArchivedTransaction(reader)
+			if err != nil {
+				return errors.Wrapf(err, "milestone %d", msIndex)
+			}
+			txs = append(txs, archivedTx)
+		}
+
+		if err := consumer(msIndex, msHash, txs); err != nil {
+			return err
+		}
+	}
+}
+
+// readArchivedTransaction reads a single transaction in the format of the archive files.
+func readArchivedTransaction(reader io.Reader) (*ArchivedTransaction, error) {
+	txHash := make(hornet.Hash, hornet.HashBinarySize)
+	if _, err := io.ReadFull(reader, txHash); err != nil {
+		return nil, err
+	}
+
+	var inclusionState, conflictReason byte
+	for _, value := range []interface{}{&inclusionState, &conflictReason} {
+		if err := binary.Read(reader, binary.LittleEndian, value); err != nil {
+			return nil, errors.Wrapf(err, "tx %s", txHash.Trytes())
+		}
+	}
+
+	archivedTx := &ArchivedTransaction{ConflictReason: hornet.ConflictReason(conflictReason)}
+	switch inclusionState {
+	case archivedStateIncluded:
+		archivedTx.LedgerInclusionState = hornet.LedgerInclusionStateIncluded
+	case archivedStateNoTransaction:
+		archivedTx.LedgerInclusionState = hornet.LedgerInclusionStateNoTransaction
+	case archivedStateConflicting:
+		archivedTx.LedgerInclusionState = hornet.LedgerInclusionStateConflicting
+	default:
+		return nil, errors.Wrapf(ErrInvalidArchivedLedgerInclusionState, "tx %s: %d", txHash.Trytes(), inclusionState)
+	}
+
+	var txBytesLength uint16
+	if err := binary.Read(reader, binary.LittleEndian, &txBytesLength); err != nil {
+		return nil, errors.Wrapf(err, "tx %s", txHash.Trytes())
+	}
+
+	txBytes := make([]byte, txBytesLength)
+	if _, err := io.ReadFull(reader, txBytes); err != nil {
+		return nil, errors.Wrapf(err, "tx %s", txHash.Trytes())
+	}
+
+	// the hash is calculated again, so a modified archive file can't inject transactions
+	tx, err := compressed.TransactionFromCompressedBytes(txBytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "tx %s", txHash.Trytes())
+	}
+
+	if tx.Hash != txHash.Trytes() {
+		return nil, errors.Wrapf(ErrArchivedTransactionHashMismatch, "tx %s, calculated hash %s", txHash.Trytes(), tx.Hash)
+	}
+
+	archivedTx.Transaction = hornet.NewTransactionFromTx(tx, txBytes)
+	return archivedTx, nil
+}
+
+// ImportArchiveFile stores all transactions of the given archive file in the database and marks them as confirmed
+// by the milestone they were archived with, including the white-flag state of their bundles.
+// The milestone bundle of every archived milestone has to be part of the archive and pass the milestone verification,
+// otherwise the import is stopped with an error wrapping ErrArchivedMilestoneNotVerified.
+func ImportArchiveFile(filePath string, abortSignal <-chan struct{}) (int, error) {
+
+	imported := 0
+	err := StreamArchiveFile(filePath, func(msIndex milestone.Index, msHash hornet.Hash, txs []*ArchivedTransaction) error {
+		select {
+		case <-abortSignal:
+			return ErrArchiveImportWasAborted
+		default:
+		}
+
+		// the transactions are stored as unconfirmed first, so they are pruned if the milestone turns out to be invalid.
+		// storing the transactions of the milestone bundle triggers its verification.
+		var cachedTxs tangle.CachedTransactions
+		defer func() { cachedTxs.Release(true) }() // tx -1
+
+		var newTxs []*ArchivedTransaction
+		var newCachedTxs tangle.CachedTransactions
+		for _, archivedTx := range txs {
+			cachedTx, alreadyAdded := tangle.AddTransactionToStorage(archivedTx.Transaction, tangle.GetLatestMilestoneIndex(), false, true, false) // tx +1
+			cachedTxs = append(cachedTxs, cachedTx)
+
+			if !alreadyAdded {
+				newTxs = append(newTxs, archivedTx)
+				newCachedTxs = append(newCachedTxs, cachedTx)
+			}
+		}
+
+		cachedMs := tangle.GetMilestoneOrNil(msIndex) // bundle +1
+		if cachedMs == nil {
+			return errors.Wrapf(ErrArchivedMilestoneNotVerified, "milestone %d, hash %s", msIndex, msHash.Trytes())
+		}
+		verifiedHash := cachedMs.GetBundle().GetMilestoneHash()
+		cachedMs.Release(true) // bundle -1
+
+		if !bytes.Equal(verifiedHash, msHash) {
+			return errors.Wrapf(ErrArchivedMilestoneNotVerified, "milestone %d, hash %s, verified hash %s", msIndex, msHash.Trytes(), verifiedHash.Trytes())
+		}
+
+		for i, archivedTx := range newTxs {
+			metadata := newCachedTxs[i].GetMetadata()
+
+			switch archivedTx.LedgerInclusionState {
+			case hornet.LedgerInclusionStateIncluded:
+				metadata.SetIncluded(true)
+			case hornet.LedgerInclusionStateNoTransaction:
+				metadata.SetNoTransaction(true)
+			case hornet.LedgerInclusionStateConflicting:
+				metadata.SetConflicting(true, archivedTx.ConflictReason)
+			}
+			metadata.SetSolid(true)
+			metadata.SetConfirmed(true, msIndex)
+		}
+		imported += len(newTxs)
+
+		return nil
+	})
+
+	return imported, err
+}
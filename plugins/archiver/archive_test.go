@@ -0,0 +1,189 @@
+package archiver
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	_ "golang.org/x/crypto/blake2b"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/gohornet/hornet/pkg/testsuite/utils"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+)
+
+const (
+	seed1 = "JBN9ZRCOH9YRUGSWIQNZWAIFEZUBDUGTFPVRKXWPAUCEQQFS9NHPQLXCKZKRHVCCUZNF9CZZWKXRZVCWQ"
+	seed2 = "JBNAZRCOH9YRUGSWIQNZWAIFEZUBDUGTFPVRKXWPAUCEQQFS9NHPQLXCKZKRHVCCUZNF9CZZWKXRZVCWQ"
+	seed3 = "JBNBZRCOH9YRUGSWIQNZWAIFEZUBDUGTFPVRKXWPAUCEQQFS9NHPQLXCKZKRHVCCUZNF9CZZWKXRZVCWQ"
+)
+
+// archivedBundle is a bundle of the archived test milestone with its expected white-flag state.
+type archivedBundle struct {
+	hashes hornet.Hashes
+	state  hornet.LedgerInclusionState
+	reason hornet.ConflictReason
+}
+
+// confirmTestMilestone confirms a milestone which references an included, a zero value and a conflicting bundle
+// and returns its confirmation together with the bundles, the milestone bundle last.
+func confirmTestMilestone(t *testing.T, te *testsuite.TestEnvironment) (*whiteflag.Confirmation, []archivedBundle) {
+	te.BuildTopology(testsuite.Topology{
+		{Name: "A", Trunk: "ms2", Branch: "ms3"},
+		// valid transfer 100 from seed1[0] to seed2[0]
+		{Name: "B", Trunk: "A", Branch: "ms3", Trytes: utils.ValueTx(t, "B", seed1, 0, 1000, seed2, 0, 100)},
+		// invalid transfer 10 from seed3[0] to seed2[0] (insufficient funds)
+		{Name: "C", Trunk: "B", Branch: "ms2", Trytes: utils.ValueTx(t, "C", seed3, 0, 99999, seed2, 0, 10)},
+	})
+	conf := te.ConfirmMilestoneOn("C")
+
+	cachedMs := tangle.GetMilestoneOrNil(conf.Index) // bundle +1
+	require.NotNil(t, cachedMs)
+	defer cachedMs.Release(true) // bundle -1
+
+	msTail := cachedMs.GetBundle().GetTailHash()
+
+	confirmation := &whiteflag.Confirmation{
+		MilestoneIndex: conf.Index,
+		MilestoneHash:  cachedMs.GetBundle().GetMilestoneHash(),
+		Mutations: &whiteflag.WhiteFlagMutations{
+			TailsIncluded:            hornet.Hashes{te.TailOf("B")},
+			TailsExcludedConflicting: hornet.Hashes{te.TailOf("C")},
+			ConflictReasons:          map[string]hornet.ConflictReason{string(te.TailOf("C")): hornet.ConflictReasonInsufficientBalance},
+			TailsExcludedZeroValue:   hornet.Hashes{te.TailOf("A"), msTail},
+			TailsReferenced:          hornet.Hashes{te.TailOf("A"), te.TailOf("B"), te.TailOf("C"), msTail},
+		},
+	}
+
+	bundleHashes := func(tail hornet.Hash) hornet.Hashes {
+		cachedBundle := tangle.GetCachedBundleOrNil(tail) // bundle +1
+		require.NotNil(t, cachedBundle)
+		defer cachedBundle.Release(true) // bundle -1
+		return cachedBundle.GetBundle().GetTxHashes()
+	}
+
+	return confirmation, []archivedBundle{
+		{hashes: bundleHashes(te.TailOf("A")), state: hornet.LedgerInclusionStateNoTransaction},
+		{hashes: bundleHashes(te.TailOf("B")), state: hornet.LedgerInclusionStateIncluded},
+		{hashes: bundleHashes(te.TailOf("C")), state: hornet.LedgerInclusionStateConflicting, reason: hornet.ConflictReasonInsufficientBalance},
+		{hashes: bundleHashes(msTail), state: hornet.LedgerInclusionStateNoTransaction},
+	}
+}
+
+// exportMilestone archives the given confirmation to a new archive file and returns its path.
+func exportMilestone(t *testing.T, confirmation *whiteflag.Confirmation) string {
+	archivePath = t.TempDir()
+	milestonesPerFile = 100
+
+	require.NoError(t, archiveMilestone(confirmation))
+	return archiveFilePath(confirmation.MilestoneIndex)
+}
+
+func TestArchiveExportImport(t *testing.T) {
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(t, seed1, 0))] = 1000
+
+	te := testsuite.SetupTestEnvironment(t, balances, 3, false)
+	confirmation, bundles := confirmTestMilestone(t, te)
+	filePath := exportMilestone(t, confirmation)
+	te.CleanupTestEnvironment(true)
+
+	// import the archive into a new database
+	te = testsuite.SetupTestEnvironment(t, make(map[string]uint64), 0, false)
+	defer te.CleanupTestEnvironment(true)
+
+	imported, err := ImportArchiveFile(filePath, nil)
+	require.NoError(t, err)
+
+	expectedCount := 0
+	for _, bndl := range bundles {
+		expectedCount += len(bndl.hashes)
+
+		for _, hash := range bndl.hashes {
+			cachedTxMeta := tangle.GetCachedTxMetadataOrNil(hash) // meta +1
+			require.NotNil(t, cachedTxMeta)
+
+			require.True(t, cachedTxMeta.GetMetadata().IsSolid())
+			confirmed, confirmationIndex := cachedTxMeta.GetMetadata().GetConfirmed()
+			require.True(t, confirmed)
+			require.Equal(t, confirmation.MilestoneIndex, confirmationIndex)
+			require.Equal(t, bndl.state, cachedTxMeta.GetMetadata().GetLedgerInclusionState())
+			require.Equal(t, bndl.reason, cachedTxMeta.GetMetadata().GetConflictReason())
+			require.Equal(t, bndl.state == hornet.LedgerInclusionStateIncluded, cachedTxMeta.GetMetadata().IsIncluded())
+
+			cachedTxMeta.Release(true) // meta -1
+		}
+	}
+	require.Equal(t, expectedCount, imported)
+}
+
+func TestArchiveImportWithoutMilestoneBundle(t *testing.T) {
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(t, seed1, 0))] = 1000
+
+	te := testsuite.SetupTestEnvironment(t, balances, 3, false)
+	confirmation, bundles := confirmTestMilestone(t, te)
+
+	// the milestone bundle is missing in the archive
+	confirmation.Mutations.TailsReferenced = confirmation.Mutations.TailsReferenced[:len(confirmation.Mutations.TailsReferenced)-1]
+	filePath := exportMilestone(t, confirmation)
+	te.CleanupTestEnvironment(true)
+
+	te = testsuite.SetupTestEnvironment(t, make(map[string]uint64), 0, false)
+	defer te.CleanupTestEnvironment(true)
+
+	_, err := ImportArchiveFile(filePath, nil)
+	require.True(t, errors.Is(err, ErrArchivedMilestoneNotVerified), "error: %v", err)
+
+	// the transactions are stored, but not confirmed
+	for _, hash := range bundles[0].hashes {
+		cachedTxMeta := tangle.GetCachedTxMetadataOrNil(hash) // meta +1
+		require.NotNil(t, cachedTxMeta)
+		require.False(t, cachedTxMeta.GetMetadata().IsConfirmed())
+		cachedTxMeta.Release(true) // meta -1
+	}
+}
+
+func TestArchiveImportWrongMilestoneIndex(t *testing.T) {
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(t, seed1, 0))] = 1000
+
+	te := testsuite.SetupTestEnvironment(t, balances, 3, false)
+	confirmation, _ := confirmTestMilestone(t, te)
+
+	// the archived milestone index doesn't match the verified milestone bundle
+	confirmation.MilestoneIndex += 10
+	filePath := exportMilestone(t, confirmation)
+	te.CleanupTestEnvironment(true)
+
+	te = testsuite.SetupTestEnvironment(t, make(map[string]uint64), 0, false)
+	defer te.CleanupTestEnvironment(true)
+
+	_, err := ImportArchiveFile(filePath, nil)
+	require.True(t, errors.Is(err, ErrArchivedMilestoneNotVerified), "error: %v", err)
+}
+
+func TestArchiveImportTampered(t *testing.T) {
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(t, seed1, 0))] = 1000
+
+	te := testsuite.SetupTestEnvironment(t, balances, 3, false)
+	defer te.CleanupTestEnvironment(true)
+
+	confirmation, _ := confirmTestMilestone(t, te)
+	filePath := exportMilestone(t, confirmation)
+
+	archive, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+
+	// modify the last byte of the last transaction, which is part of its nonce
+	archive[len(archive)-1] ^= 0x01
+	require.NoError(t, ioutil.WriteFile(filePath, archive, 0600))
+
+	_, err = ImportArchiveFile(filePath, nil)
+	require.True(t, errors.Is(err, ErrArchivedTransactionHashMismatch), "error: %v", err)
+}
@@ -0,0 +1,83 @@
+package archiver
+
+import (
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/hive.go/workerpool"
+
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+	"github.com/gohornet/hornet/plugins/tangle"
+)
+
+var (
+	PLUGIN = node.NewPlugin("Archiver", node.Disabled, configure, run)
+	log    *logger.Logger
+
+	archiveWorkerCount     = 1
+	archiveWorkerQueueSize = 1000
+	archiveWorkerPool      *workerpool.WorkerPool
+
+	archivePath       string
+	milestonesPerFile milestone.Index
+)
+
+func configure(plugin *node.Plugin) {
+	log = logging.NewLogger(plugin.Name)
+
+	archivePath = config.NodeConfig.GetString(config.CfgArchiverPath)
+
+	milestonesPerFile = milestone.Index(config.NodeConfig.GetInt(config.CfgArchiverMilestonesPerFile))
+	if milestonesPerFile == 0 {
+		log.Fatalf("%s must be greater than 0", config.CfgArchiverMilestonesPerFile)
+	}
+
+	archiveWorkerPool = workerpool.New(func(task workerpool.Task) {
+		confirmation := task.Param(0).(*whiteflag.Confirmation)
+		if err := archiveMilestone(confirmation); err != nil {
+			log.Errorf("archiving milestone %d failed: %s", confirmation.MilestoneIndex, err)
+		}
+		task.Return(nil)
+	}, workerpool.WorkerCount(archiveWorkerCount), workerpool.QueueSize(archiveWorkerQueueSize), workerpool.FlushTasksAtShutdown(true))
+}
+
+func run(_ *node.Plugin) {
+
+	onMilestoneConfirmed := events.NewClosure(func(confirmation *whiteflag.Confirmation) {
+		// the worker has to keep up, otherwise the archive would have gaps
+		archiveWorkerPool.Submit(confirmation)
+	})
+
+	daemon.BackgroundWorker("Archiver", func(shutdownSignal <-chan struct{}) {
+		log.Infof("Starting Archiver ... done, archiving confirmed milestones to %s", archivePath)
+		tangle.Events.MilestoneConfirmed.Attach(onMilestoneConfirmed)
+		archiveWorkerPool.Start()
+		<-shutdownSignal
+		log.Info("Stopping Archiver ...")
+		tangle.Events.MilestoneConfirmed.Detach(onMilestoneConfirmed)
+		archiveWorkerPool.StopAndWait()
+		log.Info("Stopping Archiver ... done")
+	}, shutdown.PriorityArchiver)
+
+	daemon.BackgroundWorker("Archiver[Import]", func(shutdownSignal <-chan struct{}) {
+		for _, filePath := range config.NodeConfig.GetStringSlice(config.CfgArchiverImportFiles) {
+			log.Infof("Importing archive file %s ...", filePath)
+
+			imported, err := ImportArchiveFile(filePath, shutdownSignal)
+			if err != nil {
+				if err == ErrArchiveImportWasAborted {
+					return
+				}
+				log.Errorf("Importing archive file %s failed: %s", filePath, err)
+				continue
+			}
+
+			log.Infof("Importing archive file %s ... done, imported %d transactions", filePath, imported)
+		}
+	}, shutdown.PriorityArchiver)
+}
@@ -48,17 +48,14 @@ func configureAutopeering(local *Local) {
 		log.Warn(err)
 	}
 
-	gossipServiceKeyHash := fnv.New32a()
-	gossipServiceKeyHash.Write([]byte(services.GossipServiceKey()))
-	networkID := gossipServiceKeyHash.Sum32()
-
-	discoveryProtocol = discover.New(local.PeerLocal, protocolVersion, networkID, discover.Logger(log.Named("disc")), discover.MasterPeers(entryNodes))
+	discoveryProtocol = discover.New(local.PeerLocal, protocolVersion, networkID(), discover.Logger(log.Named("disc")), discover.MasterPeers(entryNodes))
 
 	// only enable peer selection when the peering plugin is enabled
 	if !node.IsSkipped(peering.PLUGIN) {
 
 		isValidPeer := func(p *peer.Peer) bool {
-			// gossip must be supported
+			// gossip must be supported.
+			// nodes of a different network use a different gossip service key and are therefore never selected.
 			gossipService := p.Services().Get(services.GossipServiceKey())
 			if gossipService == nil {
 				return false
@@ -75,6 +72,14 @@ func configureAutopeering(local *Local) {
 	}
 }
 
+// networkID derives the ID of the network the node is part of from the gossip service key,
+// so that the discovery only talks to nodes using the same coordinator and MWM.
+func networkID() uint32 {
+	gossipServiceKeyHash := fnv.New32a()
+	gossipServiceKeyHash.Write([]byte(services.GossipServiceKey()))
+	return gossipServiceKeyHash.Sum32()
+}
+
 func start(local *Local, shutdownSignal <-chan struct{}) {
 	log.Info("\n\nWARNING: The autopeering plugin will disclose your public IP address to possibly all nodes and entry points. Please disable this plugin if you do not want this to happen!\n")
 
@@ -109,7 +114,7 @@ func start(local *Local, shutdownSignal <-chan struct{}) {
 	}
 
 	ID = lPeer.ID().String()
-	log.Infof("started: ID=%s Address=%s/%s PublicKey=%s", lPeer.ID(), localAddr.String(), localAddr.Network(), lPeer.PublicKey().String())
+	log.Infof("started: ID=%s Address=%s/%s PublicKey=%s NetworkID=%d", lPeer.ID(), localAddr.String(), localAddr.Network(), lPeer.PublicKey().String(), networkID())
 
 	<-shutdownSignal
 	log.Info("Stopping Autopeering ...")
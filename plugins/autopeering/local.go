@@ -11,10 +11,10 @@ import (
 	"github.com/iotaledger/hive.go/autopeering/peer/service"
 	"github.com/iotaledger/hive.go/crypto/ed25519"
 	"github.com/iotaledger/hive.go/kvstore/bolt"
-	"github.com/iotaledger/hive.go/logger"
 
 	"github.com/gohornet/hornet/pkg/autopeering/services"
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/model/tangle"
 )
 
@@ -25,7 +25,7 @@ type Local struct {
 }
 
 func newLocal() *Local {
-	log := logger.NewLogger("Local")
+	log := logging.NewLogger("Local")
 
 	var peeringIP net.IP
 
@@ -77,7 +77,7 @@ func newLocal() *Local {
 		seed = append(seed, bytes)
 	}
 
-	boltDb, err := bolt.CreateDB(config.NodeConfig.GetString(config.CfgDatabasePath), "peer.db")
+	boltDb, err := bolt.CreateDB(config.NodeConfig.GetString(config.CfgDatabasePath), tangle.AutopeeringDbFilename)
 	if err != nil {
 		log.Fatalf("Unable to create autopeering database: %s", err)
 	}
@@ -14,11 +14,13 @@ import (
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
 
+	"github.com/gohornet/hornet/pkg/app"
 	"github.com/gohornet/hornet/pkg/autopeering/services"
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
+	peeringpackage "github.com/gohornet/hornet/pkg/peering"
 	"github.com/gohornet/hornet/pkg/peering/peer"
 	"github.com/gohornet/hornet/pkg/shutdown"
-	"github.com/gohornet/hornet/plugins/peering"
 )
 
 var (
@@ -38,6 +40,21 @@ var (
 	onSelectionDropped                  *events.Closure
 )
 
+// dependencies of the plugin which are injected before it is configured.
+type dependencies struct {
+	app.In
+	// the peering manager is not available if the node runs as an autopeering entry node.
+	PeeringManager *peeringpackage.Manager `optional:"true"`
+}
+
+var deps dependencies
+
+func init() {
+	app.Inject(PLUGIN, func(d dependencies) {
+		deps = d
+	})
+}
+
 func configure(p *node.Plugin) {
 	selection.SetParameters(selection.Parameters{
 		InboundNeighborSize:        config.NodeConfig.GetInt(config.CfgNetAutopeeringInboundPeers),
@@ -47,7 +64,7 @@ func configure(p *node.Plugin) {
 		FullOutboundUpdateInterval: 30 * time.Second,
 	})
 	services.GossipServiceKey()
-	log = logger.NewLogger(p.Name)
+	log = logging.NewLogger(p.Name)
 	local = newLocal()
 	configureAutopeering(local)
 	configureEvents()
@@ -100,14 +117,14 @@ func configureEvents() {
 		originAddr, _ := iputils.ParseOriginAddress(gossipAddr)
 
 		// check if the peer is already statically peered
-		if peering.Manager().IsStaticallyPeered([]string{originAddr.Addr}, originAddr.Port) {
+		if deps.PeeringManager.IsStaticallyPeered([]string{originAddr.Addr}, originAddr.Port) {
 			log.Infof("peer is statically peered already %s", originAddr.String())
 			log.Infof("removing: %s / %s", gossipAddr, ev.Peer.ID())
 			selectionProtocol.RemoveNeighbor(ev.Peer.ID())
 			return
 		}
 
-		if err := peering.Manager().Add(gossipAddr, false, "", ev.Peer); err != nil {
+		if err := deps.PeeringManager.Add(gossipAddr, false, "", "", ev.Peer); err != nil {
 			log.Warnf("couldn't add autopeering peer %s", err)
 		}
 	})
@@ -124,20 +141,20 @@ func configureEvents() {
 		originAddr, _ := iputils.ParseOriginAddress(gossipAddr)
 
 		// check if the peer is already statically peered
-		if peering.Manager().IsStaticallyPeered([]string{originAddr.Addr}, originAddr.Port) {
+		if deps.PeeringManager.IsStaticallyPeered([]string{originAddr.Addr}, originAddr.Port) {
 			log.Infof("peer is statically peered already %s", originAddr.String())
 			log.Infof("removing: %s / %s", gossipAddr, ev.Peer.ID())
 			selectionProtocol.RemoveNeighbor(ev.Peer.ID())
 			return
 		}
-		peering.Manager().Whitelist([]string{originAddr.Addr}, originAddr.Port, ev.Peer)
+		deps.PeeringManager.Whitelist([]string{originAddr.Addr}, originAddr.Port, ev.Peer)
 	})
 
 	onSelectionDropped = events.NewClosure(func(ev *selection.DroppedEvent) {
 		log.Infof("[dropped event] trying to remove connection to %s", ev.DroppedID)
 
 		var found *peer.Peer
-		peering.Manager().ForAll(func(p *peer.Peer) bool {
+		deps.PeeringManager.ForAll(func(p *peer.Peer) bool {
 			if p.Autopeering == nil || p.Autopeering.ID() != ev.DroppedID {
 				return true
 			}
@@ -154,7 +171,7 @@ func configureEvents() {
 		}
 
 		log.Infof("removing autopeered peer %s", found.InitAddress.String())
-		if err := peering.Manager().Remove(found.ID); err != nil {
+		if err := deps.PeeringManager.Remove(found.ID); err != nil {
 			log.Errorf("couldn't remove autopeered peer %s: %s", found.InitAddress.String(), err)
 			return
 		}
@@ -168,13 +185,13 @@ func attachEvents() {
 	discoveryProtocol.Events().PeerDeleted.Attach(onDiscoveryPeerDeleted)
 
 	// only handle outgoing/incoming peering requests when the peering plugin is enabled
-	if node.IsSkipped(peering.PLUGIN) {
+	if deps.PeeringManager == nil {
 		return
 	}
 
 	// notify the selection when a connection is closed or failed.
-	peering.Manager().Events.PeerDisconnected.Attach(onManagerPeerDisconnected)
-	peering.Manager().Events.AutopeeredPeerBecameStatic.Attach(onManagerAutopeeredPeerBecameStatic)
+	deps.PeeringManager.Events.PeerDisconnected.Attach(onManagerPeerDisconnected)
+	deps.PeeringManager.Events.AutopeeredPeerBecameStatic.Attach(onManagerAutopeeredPeerBecameStatic)
 	selectionProtocol.Events().SaltUpdated.Attach(onSelectionSaltUpdated)
 	selectionProtocol.Events().OutgoingPeering.Attach(onSelectionOutgoingPeering)
 	selectionProtocol.Events().IncomingPeering.Attach(onSelectionIncomingPeering)
@@ -186,12 +203,12 @@ func detachEvents() {
 	discoveryProtocol.Events().PeerDeleted.Detach(onDiscoveryPeerDeleted)
 
 	// outgoing/incoming peering requests are only handle when the peering plugin is enabled
-	if node.IsSkipped(peering.PLUGIN) {
+	if deps.PeeringManager == nil {
 		return
 	}
 
-	peering.Manager().Events.PeerDisconnected.Detach(onManagerPeerDisconnected)
-	peering.Manager().Events.AutopeeredPeerBecameStatic.Detach(onManagerAutopeeredPeerBecameStatic)
+	deps.PeeringManager.Events.PeerDisconnected.Detach(onManagerPeerDisconnected)
+	deps.PeeringManager.Events.AutopeeredPeerBecameStatic.Detach(onManagerAutopeeredPeerBecameStatic)
 	selectionProtocol.Events().SaltUpdated.Detach(onSelectionSaltUpdated)
 	selectionProtocol.Events().OutgoingPeering.Detach(onSelectionOutgoingPeering)
 	selectionProtocol.Events().IncomingPeering.Detach(onSelectionIncomingPeering)
@@ -0,0 +1,181 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/hive.go/timeutil"
+	"github.com/iotaledger/hive.go/workerpool"
+
+	"github.com/gohornet/hornet/pkg/backup"
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/plugins/snapshot"
+)
+
+var (
+	PLUGIN = node.NewPlugin("Backup", node.Disabled, configure, run)
+	log    *logger.Logger
+
+	// the uploads run one after another, so they don't compete for the bandwidth
+	backupWorkerCount     = 1
+	backupWorkerQueueSize = 10
+	backupWorkerPool      *workerpool.WorkerPool
+
+	target backup.Target
+	prefix string
+
+	// canceled at shutdown to abort the running upload
+	backupCtx    context.Context
+	backupCancel context.CancelFunc
+)
+
+// a snapshot file which was created and has to be uploaded.
+type snapshotFile struct {
+	targetIndex milestone.Index
+	filePath    string
+	isDelta     bool
+}
+
+// the task to back up the databases.
+type databaseBackup struct{}
+
+func configure(plugin *node.Plugin) {
+	log = logging.NewLogger(plugin.Name)
+
+	var err error
+	target, err = backup.NewS3Target(backup.S3Options{
+		Endpoint:        config.NodeConfig.GetString(config.CfgBackupS3Endpoint),
+		Region:          config.NodeConfig.GetString(config.CfgBackupS3Region),
+		Bucket:          config.NodeConfig.GetString(config.CfgBackupS3Bucket),
+		AccessKeyID:     config.NodeConfig.GetString(config.CfgBackupS3AccessKeyID),
+		SecretAccessKey: config.NodeConfig.GetString(config.CfgBackupS3SecretAccessKey),
+		PathStyle:       config.NodeConfig.GetBool(config.CfgBackupS3PathStyle),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	prefix = config.NodeConfig.GetString(config.CfgBackupPrefix)
+
+	if config.NodeConfig.GetBool(config.CfgBackupDatabaseEnabled) && config.NodeConfig.GetInt(config.CfgBackupDatabaseIntervalHours) <= 0 {
+		log.Fatalf("%s must be greater than 0", config.CfgBackupDatabaseIntervalHours)
+	}
+
+	backupCtx, backupCancel = context.WithCancel(context.Background())
+
+	backupWorkerPool = workerpool.New(func(task workerpool.Task) {
+		switch t := task.Param(0).(type) {
+		case *snapshotFile:
+			uploadSnapshot(t)
+		case *databaseBackup:
+			backupDatabases()
+		}
+		task.Return(nil)
+	}, workerpool.WorkerCount(backupWorkerCount), workerpool.QueueSize(backupWorkerQueueSize))
+}
+
+func run(_ *node.Plugin) {
+
+	onSnapshotCreated := events.NewClosure(func(targetIndex milestone.Index, filePath string, isDelta bool) {
+		if _, added := backupWorkerPool.TrySubmit(&snapshotFile{targetIndex: targetIndex, filePath: filePath, isDelta: isDelta}); !added {
+			log.Warnf("skipped upload of snapshot file %s, the previous uploads are still running", filePath)
+		}
+	})
+
+	daemon.BackgroundWorker("Backup", func(shutdownSignal <-chan struct{}) {
+		log.Info("Starting Backup ... done")
+		if config.NodeConfig.GetBool(config.CfgBackupSnapshotsEnabled) {
+			snapshot.Events.SnapshotCreated.Attach(onSnapshotCreated)
+		}
+		backupWorkerPool.Start()
+		<-shutdownSignal
+		log.Info("Stopping Backup ...")
+		snapshot.Events.SnapshotCreated.Detach(onSnapshotCreated)
+		backupCancel()
+		backupWorkerPool.StopAndWait()
+		log.Info("Stopping Backup ... done")
+	}, shutdown.PriorityBackup)
+
+	if !config.NodeConfig.GetBool(config.CfgBackupDatabaseEnabled) {
+		return
+	}
+
+	interval := time.Duration(config.NodeConfig.GetInt(config.CfgBackupDatabaseIntervalHours)) * time.Hour
+	daemon.BackgroundWorker("Backup[Database]", func(shutdownSignal <-chan struct{}) {
+		timeutil.Ticker(func() {
+			if _, added := backupWorkerPool.TrySubmit(&databaseBackup{}); !added {
+				log.Warn("skipped database backup, the previous uploads are still running")
+			}
+		}, interval, shutdownSignal)
+	}, shutdown.PriorityBackup)
+}
+
+func uploadSnapshot(file *snapshotFile) {
+	ts := time.Now()
+
+	key := backup.SnapshotKey(prefix, file.isDelta, file.targetIndex, file.filePath)
+	if err := backup.UploadFile(backupCtx, target, key, file.filePath); err != nil {
+		log.Errorf("uploading snapshot file failed: %s", err)
+		return
+	}
+	log.Infof("uploaded snapshot file %s to %s, took %v", file.filePath, key, time.Since(ts).Truncate(time.Millisecond))
+
+	deleted, err := backup.ApplyRetention(backupCtx, target, backup.SnapshotSetPrefix(prefix, file.isDelta), config.NodeConfig.GetInt(config.CfgBackupSnapshotsRetention))
+	if err != nil {
+		log.Errorf("deleting old snapshot files failed: %s", err)
+		return
+	}
+	if len(deleted) > 0 {
+		log.Infof("deleted %d old snapshot files", len(deleted))
+	}
+}
+
+func backupDatabases() {
+	ts := time.Now()
+
+	filePaths, err := tangle.BackupDatabases(config.NodeConfig.GetString(config.CfgBackupDatabasePath))
+	defer func() {
+		for _, filePath := range filePaths {
+			os.Remove(filePath)
+		}
+	}()
+	if err != nil {
+		log.Errorf("backing up the databases failed: %s", err)
+		return
+	}
+
+	var uploaded []string
+	for _, filePath := range filePaths {
+		key := backup.DatabaseKey(prefix, ts, filePath)
+		if err := backup.UploadFile(backupCtx, target, key, filePath); err != nil {
+			log.Errorf("uploading database backup failed: %s", err)
+
+			// an incomplete backup would count as the newest one for the retention
+			for _, key := range uploaded {
+				if err := target.Delete(context.Background(), key); err != nil {
+					log.Warnf("deleting incomplete database backup %s failed: %s", key, err)
+				}
+			}
+			return
+		}
+		uploaded = append(uploaded, key)
+	}
+	log.Infof("uploaded backup of %d databases, took %v", len(uploaded), time.Since(ts).Truncate(time.Millisecond))
+
+	deleted, err := backup.ApplyRetention(backupCtx, target, backup.DatabaseSetPrefix(prefix), config.NodeConfig.GetInt(config.CfgBackupDatabaseRetention))
+	if err != nil {
+		log.Errorf("deleting old database backups failed: %s", err)
+		return
+	}
+	if len(deleted) > 0 {
+		log.Infof("deleted %d files of old database backups", len(deleted))
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/iotaledger/hive.go/node"
 
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
 )
 
 var (
@@ -43,13 +44,25 @@ func ParseConfig() {
 	}
 	parseParameters()
 
+	logging.ConfigureRotation(config.NodeConfig.GetInt(config.CfgLoggerRotationMaxSize), config.NodeConfig.GetInt(config.CfgLoggerRotationMaxBackups))
 	if err := logger.InitGlobalLogger(config.NodeConfig); err != nil {
 		panic(err)
 	}
+
+	level, err := logging.ParseLevel(config.NodeConfig.GetString(logger.ViperKeyLevel))
+	if err != nil {
+		panic(err)
+	}
+	componentLevels, err := logging.ParseComponentLevels(config.NodeConfig.GetStringMapString(config.CfgLoggerComponentLevels))
+	if err != nil {
+		panic(err)
+	}
+	logging.SetLevel(level)
+	logging.SetComponentLevels(componentLevels)
 }
 
 func PrintConfig() {
-	config.PrintConfig([]string{config.CfgWebAPIBasicAuthPasswordHash, config.CfgWebAPIBasicAuthPasswordSalt, config.CfgDashboardBasicAuthPasswordHash, config.CfgDashboardBasicAuthPasswordSalt})
+	config.PrintConfig([]string{config.CfgWebAPIBasicAuthPasswordHash, config.CfgWebAPIBasicAuthPasswordSalt, config.CfgWebAPIJWTAuthSecret, config.CfgDashboardBasicAuthPasswordHash, config.CfgDashboardBasicAuthPasswordSalt, config.CfgProfilingBasicAuthPasswordHash, config.CfgProfilingBasicAuthPasswordSalt, config.CfgBackupS3SecretAccessKey})
 }
 
 // HideConfigFlags hides all non essential flags from the help/usage text.
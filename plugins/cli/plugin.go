@@ -15,6 +15,7 @@ import (
 	"github.com/iotaledger/hive.go/timeutil"
 
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/profile"
 	"github.com/gohornet/hornet/pkg/shutdown"
 )
@@ -60,7 +61,7 @@ func parseParameters() {
 
 func configure(plugin *node.Plugin) {
 
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
 	githubTag = &latest.GithubTag{
 		Owner:             "gohornet",
@@ -87,6 +88,29 @@ func configure(plugin *node.Plugin) {
 		log.Infof("Using profile '%s'", profile.LoadProfile().Name)
 	}
 
+	config.Events.ParameterChanged.Attach(events.NewClosure(func(key string) {
+		switch key {
+		case logger.ViperKeyLevel:
+			level, err := logging.ParseLevel(config.NodeConfig.GetString(logger.ViperKeyLevel))
+			if err != nil {
+				log.Warnf("invalid '%s' in config: %s", logger.ViperKeyLevel, err)
+				return
+			}
+			logging.SetLevel(level)
+			log.Infof("set '%s' to <%s> due to config change", logger.ViperKeyLevel, level)
+
+		case config.CfgLoggerComponentLevels:
+			componentLevels, err := logging.ParseComponentLevels(config.NodeConfig.GetStringMapString(config.CfgLoggerComponentLevels))
+			if err != nil {
+				log.Warnf("invalid '%s' in config: %s", config.CfgLoggerComponentLevels, err)
+				return
+			}
+			logging.SetComponentLevels(componentLevels)
+			log.Infof("set '%s' to <%v> due to config change", config.CfgLoggerComponentLevels, componentLevels)
+		}
+	}))
+	config.WatchNodeConfig()
+
 	log.Info("Loading plugins ...")
 }
 
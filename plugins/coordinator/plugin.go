@@ -18,6 +18,7 @@ import (
 
 	"github.com/gohornet/hornet/pkg/config"
 	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/model/coordinator"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
@@ -67,11 +68,17 @@ var (
 )
 
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
 	// set the node as synced at startup, so the coo plugin can select tips
 	tangleplugin.SetUpdateSyncedAtStartup(true)
 
+	// the coordinator is the source of the confirmations, it must not lose one it already issued
+	if tangle.GetDurabilityMode() != tangle.DurabilityModeConfirmation {
+		log.Warnf("Durability mode '%s' is not safe for a coordinator, switching to '%s'", tangle.GetDurabilityMode(), tangle.DurabilityModeConfirmation)
+		tangle.SetDurabilityMode(tangle.DurabilityModeConfirmation)
+	}
+
 	var err error
 	coo, err = initCoordinator(*bootstrap, *startIndex, pow.Handler())
 	if err != nil {
@@ -98,6 +105,7 @@ func initCoordinator(bootstrap bool, startIndex uint32, powHandler *powpackage.H
 		config.NodeConfig.GetInt(config.CfgCoordinatorTipselectMaxHeaviestBranchTipsPerCheckpoint),
 		config.NodeConfig.GetInt(config.CfgCoordinatorTipselectRandomTipsPerCheckpoint),
 		time.Duration(config.NodeConfig.GetInt(config.CfgCoordinatorTipselectHeaviestBranchSelectionDeadlineMilliseconds))*time.Millisecond,
+		config.NodeConfig.GetInt(config.CfgCoordinatorTipselectHeaviestBranchSelectionMaxTipEvaluations),
 	)
 
 	nextCheckpointSignal = make(chan struct{})
@@ -110,6 +118,11 @@ func initCoordinator(bootstrap bool, startIndex uint32, powHandler *powpackage.H
 
 	belowMaxDepth = milestone.Index(config.NodeConfig.GetInt(config.CfgTipSelBelowMaxDepth))
 
+	milestoneMerkleHashFunc, err := coordinator.MilestoneMerkleTreeHashFuncWithName(config.NodeConfig.GetString(config.CfgCoordinatorMilestoneMerkleTreeHashFunc))
+	if err != nil {
+		return nil, err
+	}
+
 	coo := coordinator.New(
 		seed,
 		consts.SecurityLevel(config.NodeConfig.GetInt(config.CfgCoordinatorSecurityLevel)),
@@ -119,7 +132,7 @@ func initCoordinator(bootstrap bool, startIndex uint32, powHandler *powpackage.H
 		config.NodeConfig.GetInt(config.CfgCoordinatorIntervalSeconds),
 		powHandler,
 		sendBundle,
-		coordinator.MilestoneMerkleTreeHashFuncWithName(config.NodeConfig.GetString(config.CfgCoordinatorMilestoneMerkleTreeHashFunc)),
+		milestoneMerkleHashFunc,
 	)
 
 	if err := coo.InitMerkleTree(config.NodeConfig.GetString(config.CfgCoordinatorMerkleTreeFilePath), config.NodeConfig.GetString(config.CfgCoordinatorAddress)); err != nil {
@@ -361,7 +374,7 @@ func configureEvents() {
 		ts := time.Now()
 
 		// do not propagate during syncing, because it is not needed at all
-		if !tangle.IsNodeSyncedWithThreshold() {
+		if !tangle.IsNodeAlmostSynced() {
 			return
 		}
 
@@ -47,14 +47,16 @@ type ExplorerTx struct {
 		Conflicting bool            `json:"conflicting"`
 		Milestone   milestone.Index `json:"milestone_index"`
 	} `json:"confirmed"`
-	Approvers      []string        `json:"approvers"`
-	Solid          bool            `json:"solid"`
-	MWM            int             `json:"mwm"`
-	Previous       trinary.Hash    `json:"previous"`
-	Next           trinary.Hash    `json:"next"`
-	BundleComplete bool            `json:"bundle_complete"`
-	IsMilestone    bool            `json:"is_milestone"`
-	MilestoneIndex milestone.Index `json:"milestone_index"`
+	LedgerInclusionState hornet.LedgerInclusionState `json:"ledger_inclusion_state"`
+	ConflictReason       hornet.ConflictReason       `json:"conflict_reason"`
+	Approvers            []string                    `json:"approvers"`
+	Solid                bool                        `json:"solid"`
+	MWM                  int                         `json:"mwm"`
+	Previous             trinary.Hash                `json:"previous"`
+	Next                 trinary.Hash                `json:"next"`
+	BundleComplete       bool                        `json:"bundle_complete"`
+	IsMilestone          bool                        `json:"is_milestone"`
+	MilestoneIndex       milestone.Index             `json:"milestone_index"`
 }
 
 func createExplorerTx(cachedTx *tangle.CachedTransaction) (*ExplorerTx, error) {
@@ -86,11 +88,20 @@ func createExplorerTx(cachedTx *tangle.CachedTransaction) (*ExplorerTx, error) {
 			Conflicting bool            `json:"conflicting"`
 			Milestone   milestone.Index `json:"milestone_index"`
 		}{confirmed, conflicting, by},
-		Solid: cachedTx.GetMetadata().IsSolid(),
+		LedgerInclusionState: cachedTx.GetMetadata().GetLedgerInclusionState(),
+		ConflictReason:       cachedTx.GetMetadata().GetConflictReason(),
+		Solid:                cachedTx.GetMetadata().IsSolid(),
 	}
 
+	// milestone transactions are flagged in the metadata
+	t.IsMilestone, t.MilestoneIndex = cachedTx.GetMetadata().GetMilestone()
+
 	// Approvers
-	t.Approvers = tangle.GetApproverHashes(cachedTx.GetTransaction().GetTxHash(), MaxApproversResults).Trytes()
+	approvers, err := tangle.GetApproverHashes(cachedTx.GetTransaction().GetTxHash(), MaxApproversResults).ToTrytes()
+	if err != nil {
+		return nil, err
+	}
+	t.Approvers = approvers
 
 	// compute mwm
 	trits, err := trinary.BytesToTrits(cachedTx.GetTransaction().GetTxHash())
@@ -135,8 +146,8 @@ func createExplorerTx(cachedTx *tangle.CachedTransaction) (*ExplorerTx, error) {
 		}
 		cachedTxs.Release(true) // tx -1
 
-		// check whether milestone
-		if cachedBndl.GetBundle().IsMilestone() {
+		// check whether milestone (transactions stored before the metadata flag was introduced)
+		if !t.IsMilestone && cachedBndl.GetBundle().IsMilestone() {
 			t.IsMilestone = true
 			t.MilestoneIndex = cachedBndl.GetBundle().GetMilestoneIndex()
 		}
@@ -261,30 +272,39 @@ func setupExplorerRoutes(routeGroup *echo.Group) {
 		search = search[:81]
 
 		wg := sync.WaitGroup{}
-		wg.Add(3)
-		go func() {
-			defer wg.Done()
+
+		// the lookups run in parallel as long as the goroutine budget of the plugin allows it
+		lookup := func(f func()) {
+			wg.Add(1)
+			if !pluginBudget.Go(func() {
+				defer wg.Done()
+				f()
+			}) {
+				f()
+				wg.Done()
+			}
+		}
+
+		lookup(func() {
 			tx, err := findTransaction(search)
 			if err == nil {
 				result.Tx = tx
 			}
-		}()
+		})
 
-		go func() {
-			defer wg.Done()
+		lookup(func() {
 			addr, err := findAddress(search, false)
 			if err == nil && (len(addr.Txs) > 0 || addr.Balance > 0) {
 				result.Address = addr
 			}
-		}()
+		})
 
-		go func() {
-			defer wg.Done()
+		lookup(func() {
 			bundles, err := findBundles(search)
 			if err == nil {
 				result.Bundles = bundles
 			}
-		}()
+		})
 		wg.Wait()
 
 		return c.JSON(http.StatusOK, result)
@@ -411,7 +431,7 @@ func findAddress(hash trinary.Hash, valueOnly bool) (*ExplorerAddress, error) {
 		}
 	}
 
-	balance, _, err := tangle.GetBalanceForAddress(addr)
+	balance, _, err := tangle.Ledger().GetBalanceForAddress(addr)
 	if err != nil {
 		return nil, err
 	}
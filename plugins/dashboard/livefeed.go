@@ -20,7 +20,7 @@ func runLiveFeed() {
 
 	onReceivedNewTransaction := events.NewClosure(func(cachedTx *tanglemodel.CachedTransaction, latestMilestoneIndex milestone.Index, latestSolidMilestoneIndex milestone.Index) {
 		cachedTx.ConsumeTransaction(func(tx *hornet.Transaction) {
-			if !tanglemodel.IsNodeSyncedWithThreshold() {
+			if !tanglemodel.IsNodeAlmostSynced() {
 				return
 			}
 
@@ -15,12 +15,17 @@ import (
 	"github.com/iotaledger/hive.go/node"
 	"github.com/iotaledger/hive.go/websockethub"
 
+	"github.com/gohornet/hornet/pkg/app"
 	"github.com/gohornet/hornet/pkg/basicauth"
+	"github.com/gohornet/hornet/pkg/budget"
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/jwt"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
+	peeringpackage "github.com/gohornet/hornet/pkg/peering"
 	"github.com/gohornet/hornet/pkg/peering/peer"
 	"github.com/gohornet/hornet/pkg/protocol/sting"
 	"github.com/gohornet/hornet/pkg/shutdown"
@@ -28,7 +33,6 @@ import (
 	"github.com/gohornet/hornet/plugins/cli"
 	"github.com/gohornet/hornet/plugins/gossip"
 	metricsplugin "github.com/gohornet/hornet/plugins/metrics"
-	"github.com/gohornet/hornet/plugins/peering"
 	tangleplugin "github.com/gohornet/hornet/plugins/tangle"
 )
 
@@ -87,11 +91,28 @@ var (
 	hub      *websockethub.Hub
 	upgrader *websocket.Upgrader
 
+	// limits the resources of the websocket clients and explorer searches.
+	pluginBudget *budget.Budget
+
 	cachedMilestoneMetrics []*tangleplugin.ConfirmedMilestoneMetric
 )
 
+// dependencies of the plugin which are injected before it is configured.
+type dependencies struct {
+	app.In
+	PeeringManager *peeringpackage.Manager
+}
+
+var deps dependencies
+
+func init() {
+	app.Inject(PLUGIN, func(d dependencies) {
+		deps = d
+	})
+}
+
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
 	upgrader = &websocket.Upgrader{
 		HandshakeTimeout:  webSocketWriteTimeout,
@@ -99,7 +120,8 @@ func configure(plugin *node.Plugin) {
 		EnableCompression: true,
 	}
 
-	hub = websockethub.NewHub(log, upgrader, broadcastQueueSize, clientSendChannelSize)
+	pluginBudget = budget.ForPlugin(plugin.Name)
+	hub = websockethub.NewHub(log, upgrader, pluginBudget.QueueSize(broadcastQueueSize), pluginBudget.QueueSize(clientSendChannelSize))
 }
 
 func run(_ *node.Plugin) {
@@ -122,12 +144,28 @@ func run(_ *node.Plugin) {
 			log.Fatalf("'%s' must be 64 (sha256 hash) in length if dashboard basic auth is enabled", config.CfgDashboardBasicAuthPasswordHash)
 		}
 
-		e.Use(middleware.BasicAuth(func(username, password string, c echo.Context) (bool, error) {
-			if username == expectedUsername &&
-				basicauth.VerifyPassword(password, passwordSalt, expectedPasswordHash) {
-				return true, nil
+		// a valid JWT of the HTTP API replaces the basic auth credentials if enabled
+		skipper := middleware.DefaultSkipper
+		if config.NodeConfig.GetBool(config.CfgDashboardJWTAuthEnabled) {
+			jwtAuth, err := jwt.NewAPIAuthFromConfig()
+			if err != nil {
+				log.Fatalf("dashboard JWT authentication is enabled, but unable to create the JWTs: %s", err)
+			}
+
+			skipper = func(c echo.Context) bool {
+				return jwtAuth.VerifyAuthorizationHeader(c.Request().Header.Get(echo.HeaderAuthorization)) == nil
 			}
-			return false, nil
+		}
+
+		e.Use(middleware.BasicAuthWithConfig(middleware.BasicAuthConfig{
+			Skipper: skipper,
+			Validator: func(username, password string, c echo.Context) (bool, error) {
+				if username == expectedUsername &&
+					basicauth.VerifyPassword(password, passwordSalt, expectedPasswordHash) {
+					return true, nil
+				}
+				return false, nil
+			},
 		}))
 	}
 
@@ -239,6 +277,7 @@ type NodeStatus struct {
 	ServerMetrics          *ServerMetrics  `json:"server_metrics"`
 	Mem                    *MemMetrics     `json:"mem"`
 	Caches                 *CachesMetric   `json:"caches"`
+	Latencies              *LatencyMetrics `json:"latencies"`
 }
 
 // ServerMetrics are global metrics of the server.
@@ -262,6 +301,39 @@ type ServerMetrics struct {
 	NumberOfSeenSpentAddr          uint32 `json:"spent_addr"`
 }
 
+// LatencyMetrics represents the latency statistics of the tangle in milliseconds.
+type LatencyMetrics struct {
+	TimeToSolid        *LatencyHistogram `json:"time_to_solid"`
+	TimeToConfirmation *LatencyHistogram `json:"time_to_confirmation"`
+}
+
+// LatencyHistogram represents a rolling latency histogram.
+// The last bucket has no upper bound and contains all latencies above the other buckets.
+type LatencyHistogram struct {
+	Count        uint64   `json:"count"`
+	Mean         int64    `json:"mean"`
+	Median       int64    `json:"median"`
+	P95          int64    `json:"p95"`
+	BucketBounds []int64  `json:"bucket_bounds"`
+	BucketCounts []uint64 `json:"bucket_counts"`
+}
+
+func newLatencyHistogram(snapshot *metrics.LatencyHistogramSnapshot) *LatencyHistogram {
+	h := &LatencyHistogram{
+		Count:  snapshot.Count,
+		Mean:   snapshot.Mean().Milliseconds(),
+		Median: snapshot.Quantile(0.5).Milliseconds(),
+		P95:    snapshot.Quantile(0.95).Milliseconds(),
+	}
+	for _, bucket := range snapshot.Buckets {
+		if bucket.UpperBound != 0 {
+			h.BucketBounds = append(h.BucketBounds, bucket.UpperBound.Milliseconds())
+		}
+		h.BucketCounts = append(h.BucketCounts, bucket.Count)
+	}
+	return h
+}
+
 // MemMetrics represents memory metrics.
 type MemMetrics struct {
 	Sys          uint64 `json:"sys"`
@@ -308,7 +380,7 @@ type Cache struct {
 }
 
 func peerMetrics() []*PeerMetric {
-	infos := peering.Manager().PeerInfos()
+	infos := deps.PeeringManager.PeerInfos()
 	var stats []*PeerMetric
 	for _, info := range infos {
 		m := &PeerMetric{
@@ -357,7 +429,7 @@ func currentNodeStatus() *NodeStatus {
 	status.IsHealthy = tangleplugin.IsNodeHealthy()
 	status.NodeAlias = config.NodeConfig.GetString(config.CfgNodeAlias)
 
-	status.ConnectedPeersCount = peering.Manager().ConnectedPeerCount()
+	status.ConnectedPeersCount = deps.PeeringManager.ConnectedPeerCount()
 
 	snapshotInfo := tangle.GetSnapshotInfo()
 	if snapshotInfo != nil {
@@ -413,6 +485,12 @@ func currentNodeStatus() *NodeStatus {
 		NumberOfSeenSpentAddr:          metrics.SharedServerMetrics.SeenSpentAddresses.Load(),
 	}
 
+	// latency metrics
+	status.Latencies = &LatencyMetrics{
+		TimeToSolid:        newLatencyHistogram(metrics.SharedTangleStatistics.TimeToSolid.Snapshot()),
+		TimeToConfirmation: newLatencyHistogram(metrics.SharedTangleStatistics.TimeToConfirmation.Snapshot()),
+	}
+
 	// memory metrics
 	status.Mem = &MemMetrics{
 		Sys:          m.Sys,
@@ -165,6 +165,12 @@ func websocketRoute(ctx echo.Context) error {
 		case MsgTypeDatabaseCleanupEvent:
 			client.Send(&Msg{Type: MsgTypeDatabaseCleanupEvent, Data: lastDbCleanup})
 
+		case MsgTypeVertex:
+			// fill the visualizer of the client with the recent vertices
+			for _, v := range currentRecentVertices() {
+				client.Send(&Msg{Type: MsgTypeVertex, Data: v}, true)
+			}
+
 		case MsgTypeMs:
 			start := tangle.GetLatestMilestoneIndex()
 			for i := start - 10; i <= start; i++ {
@@ -195,16 +201,16 @@ func websocketRoute(ctx echo.Context) error {
 				topicsLock.RUnlock()
 				return registered
 			}
-			client.ReceiveChan = make(chan *websockethub.WebsocketMsg, 100)
+			receiveChan := make(chan *websockethub.WebsocketMsg, 100)
 
-			go func() {
+			if !pluginBudget.Go(func() {
 				for {
 					select {
 					case <-client.ExitSignal:
 						// client was disconnected
 						return
 
-					case msg, ok := <-client.ReceiveChan:
+					case msg, ok := <-receiveChan:
 						if !ok {
 							// client was disconnected
 							return
@@ -235,7 +241,12 @@ func websocketRoute(ctx echo.Context) error {
 						}
 					}
 				}
-			}()
+			}) {
+				// without a receiver the client can't register any topic, so it doesn't get any messages
+				log.Warn("WebSocket client can't register topics, the goroutine budget is exhausted")
+				return
+			}
+			client.ReceiveChan = receiveChan
 		},
 
 		// onConnect gets called when the client was registered
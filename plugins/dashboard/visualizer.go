@@ -4,6 +4,7 @@ import (
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/hive.go/syncutils"
 
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
@@ -19,6 +20,13 @@ import (
 
 const (
 	VisualizerIdLength = 7
+	// the amount of recent vertices which are sent to clients that start to visualize the tangle.
+	visualizerRecentVerticesCount = 1000
+)
+
+var (
+	recentVertices     []*vertex
+	recentVerticesLock syncutils.Mutex
 )
 
 // vertex defines a vertex in a DAG.
@@ -33,6 +41,42 @@ type vertex struct {
 	IsTip       bool   `json:"is_tip"`
 }
 
+func addRecentVertex(v *vertex) {
+	recentVerticesLock.Lock()
+	defer recentVerticesLock.Unlock()
+
+	recentVertices = append(recentVertices, v)
+	if len(recentVertices) > visualizerRecentVerticesCount {
+		recentVertices = recentVertices[len(recentVertices)-visualizerRecentVerticesCount:]
+	}
+}
+
+// returns the recently received vertices with their current solid, confirmed and milestone state.
+func currentRecentVertices() []*vertex {
+	recentVerticesLock.Lock()
+	vertices := make([]*vertex, len(recentVertices))
+	copy(vertices, recentVertices)
+	recentVerticesLock.Unlock()
+
+	result := make([]*vertex, 0, len(vertices))
+	for _, v := range vertices {
+		cachedTxMeta := tanglemodel.GetCachedTxMetadataOrNil(hornet.HashFromHashTrytes(v.ID)) // meta +1
+		if cachedTxMeta == nil {
+			// transaction was pruned in the meantime
+			continue
+		}
+
+		current := *v
+		current.IsSolid = cachedTxMeta.GetMetadata().IsSolid()
+		current.IsConfirmed = cachedTxMeta.GetMetadata().IsConfirmed()
+		current.IsMilestone = cachedTxMeta.GetMetadata().IsMilestone()
+		cachedTxMeta.Release(true) // meta -1
+
+		result = append(result, &current)
+	}
+	return result
+}
+
 // metainfo signals that metadata of a given transaction changed.
 type metainfo struct {
 	ID string `json:"id"`
@@ -54,30 +98,28 @@ func runVisualizer() {
 
 	onReceivedNewTransaction := events.NewClosure(func(cachedTx *tanglemodel.CachedTransaction, latestMilestoneIndex milestone.Index, latestSolidMilestoneIndex milestone.Index) {
 		cachedTx.ConsumeTransactionAndMetadata(func(tx *hornet.Transaction, metadata *hornet.TransactionMetadata) { // tx -1
-			if !tanglemodel.IsNodeSyncedWithThreshold() {
+			if !tanglemodel.IsNodeAlmostSynced() {
 				return
 			}
 
-			hub.BroadcastMsg(
-				&Msg{
-					Type: MsgTypeVertex,
-					Data: &vertex{
-						ID:          tx.Tx.Hash,
-						Tag:         tx.Tx.Tag,
-						TrunkID:     tx.Tx.TrunkTransaction[:VisualizerIdLength],
-						BranchID:    tx.Tx.BranchTransaction[:VisualizerIdLength],
-						IsSolid:     metadata.IsSolid(),
-						IsConfirmed: metadata.IsConfirmed(),
-						IsMilestone: false,
-						IsTip:       false,
-					},
-				},
-			)
+			v := &vertex{
+				ID:          tx.Tx.Hash,
+				Tag:         tx.Tx.Tag,
+				TrunkID:     tx.Tx.TrunkTransaction[:VisualizerIdLength],
+				BranchID:    tx.Tx.BranchTransaction[:VisualizerIdLength],
+				IsSolid:     metadata.IsSolid(),
+				IsConfirmed: metadata.IsConfirmed(),
+				IsMilestone: false,
+				IsTip:       false,
+			}
+			addRecentVertex(v)
+
+			hub.BroadcastMsg(&Msg{Type: MsgTypeVertex, Data: v})
 		})
 	})
 
 	onTransactionSolid := events.NewClosure(func(txHash hornet.Hash) {
-		if !tanglemodel.IsNodeSyncedWithThreshold() {
+		if !tanglemodel.IsNodeAlmostSynced() {
 			return
 		}
 
@@ -93,7 +135,7 @@ func runVisualizer() {
 
 	onReceivedNewMilestone := events.NewClosure(func(cachedBndl *tanglePackage.CachedBundle) {
 		cachedBndl.ConsumeBundle(func(bndl *tanglePackage.Bundle) { // bundle -1
-			if !tanglemodel.IsNodeSyncedWithThreshold() {
+			if !tanglemodel.IsNodeAlmostSynced() {
 				return
 			}
 
@@ -112,7 +154,7 @@ func runVisualizer() {
 
 	// show checkpoints as milestones in the coordinator node
 	onIssuedCheckpointTransaction := events.NewClosure(func(checkpointIndex int, tipIndex int, tipsTotal int, txHash hornet.Hash) {
-		if !tanglemodel.IsNodeSyncedWithThreshold() {
+		if !tanglemodel.IsNodeAlmostSynced() {
 			return
 		}
 
@@ -127,7 +169,7 @@ func runVisualizer() {
 	})
 
 	onMilestoneConfirmed := events.NewClosure(func(confirmation *whiteflag.Confirmation) {
-		if !tanglemodel.IsNodeSyncedWithThreshold() {
+		if !tanglemodel.IsNodeAlmostSynced() {
 			return
 		}
 
@@ -148,7 +190,7 @@ func runVisualizer() {
 	})
 
 	onTipAdded := events.NewClosure(func(tip *tipselect.Tip) {
-		if !tanglemodel.IsNodeSyncedWithThreshold() {
+		if !tanglemodel.IsNodeAlmostSynced() {
 			return
 		}
 
@@ -164,7 +206,7 @@ func runVisualizer() {
 	})
 
 	onTipRemoved := events.NewClosure(func(tip *tipselect.Tip) {
-		if !tanglemodel.IsNodeSyncedWithThreshold() {
+		if !tanglemodel.IsNodeAlmostSynced() {
 			return
 		}
 
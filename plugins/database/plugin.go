@@ -7,11 +7,14 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
 	"github.com/iotaledger/hive.go/syncutils"
 
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/database"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/pkg/shutdown"
 )
@@ -24,7 +27,7 @@ var (
 )
 
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
 	viper.BindEnv("GOMAXPROCS")
 	goMaxProcsEnv := viper.GetInt("GOMAXPROCS")
@@ -35,7 +38,54 @@ func configure(plugin *node.Plugin) {
 		runtime.GOMAXPROCS(128)
 	}
 
-	tangle.ConfigureDatabases(config.NodeConfig.GetString(config.CfgDatabasePath))
+	engine, err := database.EngineFromString(config.NodeConfig.GetString(config.CfgDatabaseEngine))
+	if err != nil {
+		log.Panic(err)
+	}
+
+	if config.NodeConfig.GetBool(config.CfgDatabaseQuarantineEnabled) {
+		deleteEntries := config.NodeConfig.GetBool(config.CfgDatabaseQuarantineDeleteEntries)
+		tangle.EnableCorruptionQuarantine(deleteEntries)
+		tangle.Events.CorruptedEntryQuarantined.Attach(events.NewClosure(func(err *tangle.CorruptedEntryError, deleted bool) {
+			if deleted {
+				log.Warnf("Deleted %s", err)
+				return
+			}
+			log.Warnf("Skipped %s", err)
+		}))
+		log.Infof("Corruption quarantine enabled (delete entries: %v)", deleteEntries)
+	}
+
+	durabilityMode, err := tangle.DurabilityModeFromString(config.NodeConfig.GetString(config.CfgDatabaseDurabilityMode))
+	if err != nil {
+		log.Panic(err)
+	}
+	tangle.SetDurabilityMode(durabilityMode)
+
+	if config.NodeConfig.GetBool(config.CfgDatabaseMetricsEnabled) {
+		tangle.EnableStoreMetrics()
+		log.Info("Database metrics enabled")
+	}
+
+	if config.NodeConfig.GetBool(config.CfgDatabaseLedgerAnalyticsEnabled) {
+		tangle.EnableLedgerAnalytics()
+		log.Info("Ledger analytics enabled")
+	}
+
+	tangle.ConfigureDatabases(config.NodeConfig.GetString(config.CfgDatabasePath), engine)
+
+	unknownPrefixes, err := tangle.CheckStorePrefixes()
+	if err != nil {
+		log.Panic(err)
+	}
+	for database, prefixes := range unknownPrefixes {
+		log.Warnf("The database %s contains entries with unknown store prefixes %v. They were written by another version of HORNET and are ignored.", database, prefixes)
+	}
+
+	if config.NodeConfig.GetBool(config.CfgDatabaseAddressHistoryEnabled) {
+		tangle.EnableAddressHistory()
+		log.Info("Address history enabled")
+	}
 
 	if !tangle.IsCorrectDatabaseVersion() {
 		if !tangle.UpdateDatabaseVersion() {
@@ -45,9 +95,25 @@ func configure(plugin *node.Plugin) {
 
 	daemon.BackgroundWorker("Close database", func(shutdownSignal <-chan struct{}) {
 		<-shutdownSignal
-		tangle.MarkDatabaseHealthy()
+
+		// wait until running ledger changes are applied.
+		// the lock is never released, no further changes are written to the ledger.
+		tangle.Ledger().Lock()
+
 		log.Info("Syncing databases to disk...")
-		tangle.CloseDatabases()
+		if err := tangle.FlushDatabases(); err != nil {
+			// the database stays marked as corrupted and is revalidated at the next startup
+			log.Errorf("Syncing databases to disk failed: %s", err)
+			return
+		}
+
+		// only mark the database as healthy after all changes were persisted
+		tangle.MarkDatabaseHealthy()
+
+		if err := tangle.CloseDatabases(); err != nil {
+			log.Errorf("Closing databases failed: %s", err)
+			return
+		}
 		log.Info("Syncing databases to disk... done")
 	}, shutdown.PriorityCloseDatabase)
 }
@@ -87,5 +153,24 @@ func RunGarbageCollection() {
 }
 
 func run(_ *node.Plugin) {
-	// do nothing
+	syncInterval := time.Duration(config.NodeConfig.GetInt(config.CfgDatabaseDurabilitySyncIntervalSeconds)) * time.Second
+	if tangle.GetDurabilityMode() != tangle.DurabilityModePeriodic || syncInterval <= 0 {
+		return
+	}
+
+	daemon.BackgroundWorker("Database syncer", func(shutdownSignal <-chan struct{}) {
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-shutdownSignal:
+				return
+			case <-ticker.C:
+				if err := tangle.FlushDatabases(); err != nil {
+					log.Errorf("Syncing databases to disk failed: %s", err)
+				}
+			}
+		}
+	}, shutdown.PriorityFlushToDatabase)
 }
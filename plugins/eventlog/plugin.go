@@ -0,0 +1,208 @@
+package eventlog
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/hive.go/syncutils"
+
+	"github.com/gohornet/hornet/pkg/app"
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	peeringpackage "github.com/gohornet/hornet/pkg/peering"
+	"github.com/gohornet/hornet/pkg/peering/peer"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/plugins/snapshot"
+	tanglePlugin "github.com/gohornet/hornet/plugins/tangle"
+)
+
+const (
+	// EventTypePeerDisconnected is the type of the entries of disconnected peers.
+	EventTypePeerDisconnected = "peerDisconnected"
+	// EventTypePeerBanned is the type of the entries of peers which were banned because of misbehavior.
+	EventTypePeerBanned = "peerBanned"
+	// EventTypeMilestoneGap is the type of the entries of the solid milestone falling behind the latest milestone and catching up again.
+	EventTypeMilestoneGap = "milestoneGap"
+	// EventTypeInvalidMilestone is the type of the entries of received invalid milestones.
+	EventTypeInvalidMilestone = "invalidMilestone"
+	// EventTypeDatabaseCorruption is the type of the entries of detected database corruptions.
+	EventTypeDatabaseCorruption = "databaseCorruption"
+	// EventTypePruning is the type of the entries of pruning runs.
+	EventTypePruning = "pruning"
+	// EventTypeSnapshot is the type of the entries of created snapshot files.
+	EventTypeSnapshot = "snapshot"
+)
+
+var (
+	PLUGIN = node.NewPlugin("EventLog", node.Enabled, configure, run)
+	log    *logger.Logger
+
+	maxMilestoneDelta milestone.Index
+
+	// whether the solid milestone is more than maxMilestoneDelta behind the latest milestone.
+	milestoneGapOpen bool
+	milestoneGapLock syncutils.Mutex
+
+	pruningStartTime time.Time
+
+	onPeerDisconnected         *events.Closure
+	onPeerBanned               *events.Closure
+	onMilestoneIndexChanged    *events.Closure
+	onReceivedInvalidMilestone *events.Closure
+	onCorruptedEntry           *events.Closure
+	onPruningStarted           *events.Closure
+	onPruningFinished          *events.Closure
+	onSnapshotCreated          *events.Closure
+)
+
+// dependencies of the plugin which are injected before it is configured.
+type dependencies struct {
+	app.In
+	// the peering manager is not available if the node runs as an autopeering entry node.
+	PeeringManager *peeringpackage.Manager `optional:"true"`
+}
+
+var deps dependencies
+
+func init() {
+	app.Inject(PLUGIN, func(d dependencies) {
+		deps = d
+	})
+}
+
+func configure(plugin *node.Plugin) {
+	log = logging.NewLogger(plugin.Name)
+
+	maxMilestoneDelta = milestone.Index(config.NodeConfig.GetInt(config.CfgHealthMaxMilestoneDelta))
+
+	if err := tangle.SetEventLogCapacity(config.NodeConfig.GetInt(config.CfgEventLogCapacity)); err != nil {
+		log.Panic(err)
+	}
+
+	// the database is marked as corrupted while the node is running, so this has to be checked before it is started
+	if tangle.IsDatabaseCorrupted() {
+		add(EventTypeDatabaseCorruption, "the node was not shut down correctly, the database is revalidated")
+	}
+	if tangle.IsDatabaseTainted() {
+		add(EventTypeDatabaseCorruption, "the database is tainted")
+	}
+
+	configureEvents()
+
+	// corrupted entries are already skipped while the other plugins load their initial values from the database
+	tangle.Events.CorruptedEntryQuarantined.Attach(onCorruptedEntry)
+}
+
+func run(_ *node.Plugin) {
+	daemon.BackgroundWorker("EventLog", func(shutdownSignal <-chan struct{}) {
+		log.Info("Starting EventLog ... done")
+		attachEvents()
+		<-shutdownSignal
+		log.Info("Stopping EventLog ...")
+		detachEvents()
+		log.Info("Stopping EventLog ... done")
+	}, shutdown.PriorityEventLog)
+}
+
+// add adds an entry to the event log, errors are only logged so that the incidents don't affect the node.
+func add(eventType string, format string, args ...interface{}) {
+	if _, err := tangle.AddEventLogEntry(eventType, fmt.Sprintf(format, args...)); err != nil {
+		log.Warnf("adding %s entry to the event log failed: %s", eventType, err)
+	}
+}
+
+// checks whether the solid milestone fell behind the latest milestone or caught up again.
+func checkMilestoneGap() {
+	solidMilestoneIndex := tangle.GetSolidMilestoneIndex()
+	latestMilestoneIndex := tangle.GetLatestMilestoneIndex()
+
+	milestoneGapLock.Lock()
+	defer milestoneGapLock.Unlock()
+
+	gapOpen := latestMilestoneIndex > solidMilestoneIndex+maxMilestoneDelta
+	if gapOpen == milestoneGapOpen {
+		return
+	}
+	milestoneGapOpen = gapOpen
+
+	if gapOpen {
+		add(EventTypeMilestoneGap, "solid milestone %d fell behind latest milestone %d", solidMilestoneIndex, latestMilestoneIndex)
+		return
+	}
+	add(EventTypeMilestoneGap, "solid milestone %d caught up with latest milestone %d", solidMilestoneIndex, latestMilestoneIndex)
+}
+
+func configureEvents() {
+	onPeerDisconnected = events.NewClosure(func(p *peer.Peer) {
+		add(EventTypePeerDisconnected, "disconnected %s", p.ID)
+	})
+
+	onPeerBanned = events.NewClosure(func(p *peer.Peer) {
+		add(EventTypePeerBanned, "banned %s for %v because of misbehavior", p.ID, deps.PeeringManager.Opts.BanDuration)
+	})
+
+	onMilestoneIndexChanged = events.NewClosure(func(_ milestone.Index) {
+		checkMilestoneGap()
+	})
+
+	onReceivedInvalidMilestone = events.NewClosure(func(err error) {
+		add(EventTypeInvalidMilestone, "%s", err)
+	})
+
+	onCorruptedEntry = events.NewClosure(func(err *tangle.CorruptedEntryError, deleted bool) {
+		if deleted {
+			add(EventTypeDatabaseCorruption, "deleted %s", err)
+			return
+		}
+		add(EventTypeDatabaseCorruption, "skipped %s", err)
+	})
+
+	onPruningStarted = events.NewClosure(func(targetIndex milestone.Index) {
+		pruningStartTime = time.Now()
+	})
+
+	onPruningFinished = events.NewClosure(func(pruningIndex milestone.Index) {
+		add(EventTypePruning, "pruned the database up to milestone %d, took %v", pruningIndex, time.Since(pruningStartTime).Truncate(time.Millisecond))
+	})
+
+	onSnapshotCreated = events.NewClosure(func(targetIndex milestone.Index, filePath string, isDelta bool) {
+		if isDelta {
+			add(EventTypeSnapshot, "created delta snapshot for target index %d: %s", targetIndex, filePath)
+			return
+		}
+		add(EventTypeSnapshot, "created local snapshot for target index %d: %s", targetIndex, filePath)
+	})
+}
+
+func attachEvents() {
+	if deps.PeeringManager != nil {
+		deps.PeeringManager.Events.PeerDisconnected.Attach(onPeerDisconnected)
+		deps.PeeringManager.Events.PeerBanned.Attach(onPeerBanned)
+	}
+	tanglePlugin.Events.SolidMilestoneIndexChanged.Attach(onMilestoneIndexChanged)
+	tanglePlugin.Events.LatestMilestoneIndexChanged.Attach(onMilestoneIndexChanged)
+	tangle.Events.ReceivedInvalidMilestone.Attach(onReceivedInvalidMilestone)
+	snapshot.Events.PruningStarted.Attach(onPruningStarted)
+	snapshot.Events.PruningFinished.Attach(onPruningFinished)
+	snapshot.Events.SnapshotCreated.Attach(onSnapshotCreated)
+}
+
+func detachEvents() {
+	if deps.PeeringManager != nil {
+		deps.PeeringManager.Events.PeerDisconnected.Detach(onPeerDisconnected)
+		deps.PeeringManager.Events.PeerBanned.Detach(onPeerBanned)
+	}
+	tanglePlugin.Events.SolidMilestoneIndexChanged.Detach(onMilestoneIndexChanged)
+	tanglePlugin.Events.LatestMilestoneIndexChanged.Detach(onMilestoneIndexChanged)
+	tangle.Events.ReceivedInvalidMilestone.Detach(onReceivedInvalidMilestone)
+	tangle.Events.CorruptedEntryQuarantined.Detach(onCorruptedEntry)
+	snapshot.Events.PruningStarted.Detach(onPruningStarted)
+	snapshot.Events.PruningFinished.Detach(onPruningFinished)
+	snapshot.Events.SnapshotCreated.Detach(onSnapshotCreated)
+}
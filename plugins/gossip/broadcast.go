@@ -27,7 +27,7 @@ func BroadcastHeartbeat(filter func(p *peer.Peer) bool) {
 			return true
 		}
 
-		p.EnqueueForSending(heartbeatMsg)
+		p.EnqueueRequestForSending(heartbeatMsg)
 		return true
 	})
 }
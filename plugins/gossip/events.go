@@ -0,0 +1,42 @@
+package gossip
+
+import (
+	"github.com/iotaledger/hive.go/events"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/peering/peer"
+)
+
+// RequestsDiscardedCaller is the caller of the RequestsDiscarded event.
+func RequestsDiscardedCaller(handler interface{}, params ...interface{}) {
+	handler.(func(count int))(params[0].(int))
+}
+
+// RequestsExhaustedCaller is the caller of the RequestsExhausted event.
+func RequestsExhaustedCaller(handler interface{}, params ...interface{}) {
+	handler.(func(txHashes hornet.Hashes))(params[0].(hornet.Hashes))
+}
+
+// RateLimitExceededCaller is the caller of the RateLimitExceeded event.
+func RateLimitExceededCaller(handler interface{}, params ...interface{}) {
+	handler.(func(p *peer.Peer, limit string, global bool))(params[0].(*peer.Peer), params[1].(string), params[2].(bool))
+}
+
+var Events = pluginEvents{
+	RequestsDiscarded: events.NewEvent(RequestsDiscardedCaller),
+	RequestsExhausted: events.NewEvent(RequestsExhaustedCaller),
+	RateLimitExceeded: events.NewEvent(RateLimitExceededCaller),
+}
+
+type pluginEvents struct {
+	// RequestsDiscarded is fired with the amount of requests which were removed from the request queue
+	// without being answered, either because they expired or because they are below the pruning index.
+	RequestsDiscarded *events.Event
+	// RequestsExhausted is fired with the hashes of the transactions whose requests were dropped, because no neighbor
+	// answered them within the maximum number of attempts and they are not needed to solidify a milestone.
+	// The transactions referencing them can't become solid.
+	RequestsExhausted *events.Event
+	// RateLimitExceeded is fired with the peer, the name of the exceeded limit and whether it is a global limit
+	// for every message which is dropped because of a rate limit.
+	RateLimitExceeded *events.Event
+}
@@ -3,7 +3,10 @@ package gossip
 import (
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/gohornet/hornet/pkg/logging"
+	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/pkg/protocol/helpers"
 	"github.com/iotaledger/hive.go/daemon"
@@ -11,6 +14,7 @@ import (
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
 
+	"github.com/gohornet/hornet/pkg/app"
 	"github.com/gohornet/hornet/pkg/config"
 	"github.com/gohornet/hornet/pkg/peering"
 	"github.com/gohornet/hornet/pkg/peering/peer"
@@ -20,7 +24,11 @@ import (
 	"github.com/gohornet/hornet/pkg/protocol/rqueue"
 	"github.com/gohornet/hornet/pkg/protocol/sting"
 	"github.com/gohornet/hornet/pkg/shutdown"
-	peeringplugin "github.com/gohornet/hornet/plugins/peering"
+)
+
+const (
+	// the maximum number of milestones which are requested at once because of the heartbeats of the neighbors.
+	maxNeighborsMilestoneRequests = 3
 )
 
 var (
@@ -34,8 +42,28 @@ var (
 	broadcastQueue         bqueue.Queue
 	broadcastQueueOnce     sync.Once
 	onBroadcastTransaction *events.Closure
+
+	// the number of neighbors which must report a newer latest milestone before it is requested from them.
+	syncQuorum int
+
+	// the number of times a transaction is requested before the request is dropped (0 = unlimited).
+	maxRequestAttempts int
 )
 
+// dependencies of the plugin which are injected before it is configured.
+type dependencies struct {
+	app.In
+	PeeringManager *peering.Manager
+}
+
+var deps dependencies
+
+func init() {
+	app.Inject(PLUGIN, func(d dependencies) {
+		deps = d
+	})
+}
+
 // RequestQueue returns the request queue instance of the gossip plugin.
 func RequestQueue() rqueue.Queue {
 	requestQueueOnce.Do(func() {
@@ -47,7 +75,7 @@ func RequestQueue() rqueue.Queue {
 // BroadcastQueue returns the broadcast queue instance of the gossip plugin.
 func BroadcastQueue() bqueue.Queue {
 	broadcastQueueOnce.Do(func() {
-		broadcastQueue = bqueue.New(peeringplugin.Manager(), RequestQueue())
+		broadcastQueue = bqueue.New(deps.PeeringManager, RequestQueue(), time.Duration(config.NodeConfig.GetInt(config.CfgNetGossipBroadcastDedupWindowSeconds))*time.Second)
 	})
 	return broadcastQueue
 }
@@ -55,18 +83,29 @@ func BroadcastQueue() bqueue.Queue {
 // Processor returns the message processor instance of the gossip plugin.
 func Processor() *processor.Processor {
 	msgProcessorOnce.Do(func() {
-		msgProcessor = processor.New(requestQueue, peeringplugin.Manager(), &processor.Options{
-			ValidMWM:          config.NodeConfig.GetUint64(config.CfgCoordinatorMWM),
-			WorkUnitCacheOpts: profile.LoadProfile().Caches.IncomingTransactionFilter,
+		msgProcessor = processor.New(requestQueue, deps.PeeringManager, &processor.Options{
+			ValidMWM:            config.NodeConfig.GetUint64(config.CfgCoordinatorMWM),
+			WorkUnitCacheOpts:   profile.LoadProfile().Caches.IncomingTransactionFilter,
+			Filters:             processor.NewSpamFilters(spamFilterOpts()),
+			SubmissionQueueSize: config.NodeConfig.GetInt(config.CfgWebAPILimitsSubmissionQueueSize),
 		})
 	})
 	return msgProcessor
 }
 
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
-	manager = peeringplugin.Manager()
+	manager = deps.PeeringManager
+
+	configureRateLimiters()
+
+	syncQuorum = config.NodeConfig.GetInt(config.CfgNetGossipSyncQuorum)
+	if _, coordinatorEnabled := node.EnabledPlugins[node.GetPluginIdentifier("Coordinator")]; coordinatorEnabled {
+		// the coordinator issues the milestones itself, the heartbeats of its neighbors are irrelevant
+		syncQuorum = 0
+	}
+	maxRequestAttempts = config.NodeConfig.GetInt(config.CfgNetGossipMaxRequestAttempts)
 
 	// create networking queues
 	RequestQueue()
@@ -78,6 +117,9 @@ func configure(plugin *node.Plugin) {
 	// handle broadcasts emitted by the message processor
 	onBroadcastTransaction = events.NewClosure(broadcastQueue.EnqueueForBroadcast)
 
+	// don't enqueue pending requests if no peer can take them
+	AddRequestBackpressureSignal(requestSendQueuesSaturated)
+
 	// register event handlers for messages
 	manager.Events.PeerConnected.Attach(events.NewClosure(func(p *peer.Peer) {
 
@@ -98,6 +140,12 @@ func configure(plugin *node.Plugin) {
 			close(disconnectSignal)
 		}))
 
+		send := func(data []byte) {
+			if err := p.Protocol.Send(data); err != nil {
+				p.Protocol.Events.Error.Trigger(err)
+			}
+		}
+
 		// fire up send queue consumer
 		daemon.BackgroundWorker(fmt.Sprintf("send queue %s", p.ID), func(shutdownSignal <-chan struct{}) {
 			for {
@@ -106,16 +154,47 @@ func configure(plugin *node.Plugin) {
 					return
 				case <-shutdownSignal:
 					return
+				case data := <-p.RequestSendQueue:
+					send(data)
 				case data := <-p.SendQueue:
-					if err := p.Protocol.Send(data); err != nil {
-						p.Protocol.Events.Error.Trigger(err)
+					// requests and heartbeats are sent first
+					for drained := false; !drained; {
+						select {
+						case request := <-p.RequestSendQueue:
+							send(request)
+						default:
+							drained = true
+						}
 					}
+					send(data)
 				}
 			}
 		}, shutdown.PriorityPeerSendQueue)
 	}))
 }
 
+// requestNeighborsLatestMilestones requests the milestones above the latest milestone of the node,
+// which a quorum of the neighbors reported in their heartbeats.
+// the reported indexes are only used as a hint, since they are not verified. the sync status of the node
+// only changes once the requested milestones were received and verified.
+func requestNeighborsLatestMilestones() {
+	if syncQuorum <= 0 {
+		return
+	}
+
+	latestIndex := tangle.GetLatestMilestoneIndex()
+	neighborsIndex := manager.NeighborsLatestMilestoneIndex(syncQuorum)
+	if neighborsIndex <= latestIndex {
+		return
+	}
+
+	rangeToRequest := int(neighborsIndex - latestIndex)
+	if rangeToRequest > maxNeighborsMilestoneRequests {
+		rangeToRequest = maxNeighborsMilestoneRequests
+	}
+	BroadcastMilestoneRequests(rangeToRequest, nil, latestIndex)
+}
+
 func run(_ *node.Plugin) {
 
 	daemon.BackgroundWorker("BroadcastQueue", func(shutdownSignal <-chan struct{}) {
@@ -134,3 +213,30 @@ func run(_ *node.Plugin) {
 
 	runRequestWorkers()
 }
+
+// spamFilterOpts loads the spam filter policies from the config.
+func spamFilterOpts() *processor.SpamFilterOpts {
+	opts := &processor.SpamFilterOpts{
+		MinPoWScore:               config.NodeConfig.GetUint64(config.CfgNetGossipSpamFilterMinPoWScore),
+		MaxTransactionBytes:       config.NodeConfig.GetInt(config.CfgNetGossipSpamFilterMaxTransactionBytes),
+		BlockedTags:               config.NodeConfig.GetStringSlice(config.CfgNetGossipSpamFilterBlockedTags),
+		PeerTransactionsPerMinute: config.NodeConfig.GetInt(config.CfgNetGossipSpamFilterPeerTransactionsPerMinute),
+		// the policies must never keep the node from catching up with the network
+		Exempt: func(tx *hornet.Transaction) bool {
+			return !tangle.IsNodeAlmostSynced()
+		},
+		// the signature message fragments of milestones are full. anyone can send transactions to the coordinator
+		// address, so they are only exempt from the size limit. milestones which are dropped by the other policies
+		// bypass them once they are requested.
+		ExemptFromMaxSize: func(tx *hornet.Transaction) bool {
+			return tangle.IsCoordinatorAddress(tx.GetAddress())
+		},
+	}
+
+	// a score below the minimum weight magnitude of the network is already enforced by the PoW filter
+	if opts.MinPoWScore <= config.NodeConfig.GetUint64(config.CfgCoordinatorMWM) {
+		opts.MinPoWScore = 0
+	}
+
+	return opts
+}
@@ -0,0 +1,171 @@
+package gossip
+
+import (
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/syncutils"
+
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/pkg/peering/peer"
+	"github.com/gohornet/hornet/pkg/protocol/tlv"
+	"github.com/gohornet/hornet/pkg/utils"
+)
+
+const (
+	// RateLimitTransactions is the name of the limit of received transactions.
+	RateLimitTransactions = "transactions"
+	// RateLimitRequests is the name of the limit of received transaction and milestone requests.
+	RateLimitRequests = "requests"
+	// RateLimitBytes is the name of the limit of received bytes.
+	RateLimitBytes = "bytes"
+)
+
+var (
+	// the limits which apply to the messages of all peers together.
+	globalRateLimiters     *rateLimiters
+	globalRateLimitersLock syncutils.RWMutex
+)
+
+// rateLimiters limits the received messages and bytes.
+// limiters which are nil are disabled.
+type rateLimiters struct {
+	transactions *utils.RateLimiter
+	requests     *utils.RateLimiter
+	bytes        *utils.RateLimiter
+}
+
+// creates new rate limiters for the given rates per second (0 = unlimited).
+// the burst size of each limiter is the amount of one second.
+func newRateLimiters(transactionsPerSecond int, requestsPerSecond int, bytesPerSecond int) *rateLimiters {
+	newLimiter := func(rate int) *utils.RateLimiter {
+		if rate <= 0 {
+			return nil
+		}
+		return utils.NewRateLimiter(float64(rate), rate)
+	}
+
+	return &rateLimiters{
+		transactions: newLimiter(transactionsPerSecond),
+		requests:     newLimiter(requestsPerSecond),
+		bytes:        newLimiter(bytesPerSecond),
+	}
+}
+
+// creates the rate limiters for a single peer.
+// changed peer limits in the config only apply to peers which connect afterwards.
+func newPeerRateLimiters() *rateLimiters {
+	return newRateLimiters(
+		config.NodeConfig.GetInt(config.CfgNetGossipRateLimitPeerTransactions),
+		config.NodeConfig.GetInt(config.CfgNetGossipRateLimitPeerRequests),
+		config.NodeConfig.GetInt(config.CfgNetGossipRateLimitPeerBytes),
+	)
+}
+
+// creates the rate limiters which apply to all peers together.
+func loadGlobalRateLimiters() {
+	globalRateLimitersLock.Lock()
+	defer globalRateLimitersLock.Unlock()
+
+	globalRateLimiters = newRateLimiters(
+		config.NodeConfig.GetInt(config.CfgNetGossipRateLimitGlobalTransactions),
+		config.NodeConfig.GetInt(config.CfgNetGossipRateLimitGlobalRequests),
+		config.NodeConfig.GetInt(config.CfgNetGossipRateLimitGlobalBytes),
+	)
+}
+
+func configureRateLimiters() {
+	loadGlobalRateLimiters()
+
+	config.Events.ParameterChanged.Attach(events.NewClosure(func(key string) {
+		switch key {
+		case config.CfgNetGossipRateLimitGlobalTransactions, config.CfgNetGossipRateLimitGlobalRequests, config.CfgNetGossipRateLimitGlobalBytes:
+			loadGlobalRateLimiters()
+			log.Infof("set '%s' to <%d> due to config change", key, config.NodeConfig.GetInt(key))
+		case config.CfgNetGossipRateLimitPeerTransactions, config.CfgNetGossipRateLimitPeerRequests, config.CfgNetGossipRateLimitPeerBytes:
+			log.Infof("set '%s' to <%d> due to config change, the limit applies to newly connected peers", key, config.NodeConfig.GetInt(key))
+		}
+	}))
+}
+
+// rateLimitReservations holds the tokens a message consumed from the rate limiters,
+// so they can be returned if a later limiter rejects the message.
+type rateLimitReservations struct {
+	limiters [4]*utils.RateLimiter
+	tokens   [4]int
+	count    int
+}
+
+func (res *rateLimitReservations) add(limiter *utils.RateLimiter, n int) {
+	res.limiters[res.count] = limiter
+	res.tokens[res.count] = n
+	res.count++
+}
+
+// cancel returns all reserved tokens to their limiters.
+func (res *rateLimitReservations) cancel() {
+	for i := 0; i < res.count; i++ {
+		res.limiters[i].CancelN(res.tokens[i])
+	}
+	res.count = 0
+}
+
+// reserve consumes the tokens of a message of the given limit and size and records them in the given reservations.
+// it returns the name of the exceeded limit if the message is not allowed. tokens consumed before are not returned.
+func (r *rateLimiters) reserve(limit string, size int, res *rateLimitReservations) string {
+	limiter := r.requests
+	if limit == RateLimitTransactions {
+		limiter = r.transactions
+	}
+
+	if limiter != nil {
+		if !limiter.Allow() {
+			return limit
+		}
+		res.add(limiter, 1)
+	}
+
+	if r.bytes != nil {
+		if !r.bytes.AllowN(size) {
+			return RateLimitBytes
+		}
+		res.add(r.bytes, size)
+	}
+
+	return ""
+}
+
+// allowMessage checks whether a message of the given limit and size fulfills the given limits of the peer
+// and the global limits. The tokens are only consumed if all limits are fulfilled.
+// it returns the name of the exceeded limit and whether it is a global limit if not.
+func allowMessage(peerRateLimiters *rateLimiters, limit string, size int) (allowed bool, exceededLimit string, global bool) {
+	var res rateLimitReservations
+
+	exceededLimit = peerRateLimiters.reserve(limit, size, &res)
+	if exceededLimit == "" {
+		globalRateLimitersLock.RLock()
+		exceededLimit = globalRateLimiters.reserve(limit, size, &res)
+		globalRateLimitersLock.RUnlock()
+		global = exceededLimit != ""
+	}
+
+	if exceededLimit == "" {
+		return true, "", false
+	}
+
+	res.cancel()
+	return false, exceededLimit, global
+}
+
+// allowReceivedMessage checks whether the received message of the given peer fulfills the
+// limits of the peer and the global limits. Messages which exceed a limit are dropped.
+func allowReceivedMessage(p *peer.Peer, peerRateLimiters *rateLimiters, limit string, data []byte) bool {
+	allowed, exceededLimit, global := allowMessage(peerRateLimiters, limit, len(data)+tlv.HeaderBytesLength)
+	if allowed {
+		return true
+	}
+
+	p.Metrics.RateLimitedMessages.Inc()
+	metrics.SharedServerMetrics.RateLimitedMessages.Inc()
+	Events.RateLimitExceeded.Trigger(p, exceededLimit, global)
+	return false
+}
@@ -0,0 +1,71 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setGlobalRateLimiters replaces the global limits for the duration of the test.
+func setGlobalRateLimiters(t *testing.T, limiters *rateLimiters) {
+	globalRateLimitersLock.Lock()
+	previous := globalRateLimiters
+	globalRateLimiters = limiters
+	globalRateLimitersLock.Unlock()
+
+	t.Cleanup(func() {
+		globalRateLimitersLock.Lock()
+		globalRateLimiters = previous
+		globalRateLimitersLock.Unlock()
+	})
+}
+
+func TestAllowMessage(t *testing.T) {
+	setGlobalRateLimiters(t, newRateLimiters(0, 0, 0))
+	peerRateLimiters := newRateLimiters(2, 1, 1000)
+
+	allowed, _, _ := allowMessage(peerRateLimiters, RateLimitTransactions, 100)
+	require.True(t, allowed)
+	allowed, _, _ = allowMessage(peerRateLimiters, RateLimitRequests, 100)
+	require.True(t, allowed)
+
+	allowed, exceededLimit, global := allowMessage(peerRateLimiters, RateLimitRequests, 100)
+	require.False(t, allowed)
+	require.Equal(t, RateLimitRequests, exceededLimit)
+	require.False(t, global)
+}
+
+func TestAllowMessageExceededBytesKeepsMessageTokens(t *testing.T) {
+	setGlobalRateLimiters(t, newRateLimiters(0, 0, 0))
+	peerRateLimiters := newRateLimiters(1, 0, 100)
+
+	// the message is too big, so the transaction token must not be consumed
+	allowed, exceededLimit, global := allowMessage(peerRateLimiters, RateLimitTransactions, 200)
+	require.False(t, allowed)
+	require.Equal(t, RateLimitBytes, exceededLimit)
+	require.False(t, global)
+
+	allowed, _, _ = allowMessage(peerRateLimiters, RateLimitTransactions, 100)
+	require.True(t, allowed)
+}
+
+func TestAllowMessageExceededGlobalKeepsPeerTokens(t *testing.T) {
+	globalLimiters := newRateLimiters(1, 0, 0)
+	setGlobalRateLimiters(t, globalLimiters)
+
+	peerRateLimiters := newRateLimiters(1, 0, 1000)
+	otherPeerRateLimiters := newRateLimiters(1, 0, 1000)
+
+	// the other peer exhausts the global limit
+	allowed, _, _ := allowMessage(otherPeerRateLimiters, RateLimitTransactions, 100)
+	require.True(t, allowed)
+
+	allowed, exceededLimit, global := allowMessage(peerRateLimiters, RateLimitTransactions, 100)
+	require.False(t, allowed)
+	require.Equal(t, RateLimitTransactions, exceededLimit)
+	require.True(t, global)
+
+	// the tokens of the peer were returned, so only the global limit is exceeded again
+	require.True(t, peerRateLimiters.transactions.Allow())
+	require.True(t, peerRateLimiters.bytes.AllowN(1000))
+}
@@ -2,11 +2,13 @@ package gossip
 
 import (
 	"bytes"
+	"sort"
 	"time"
 
 	"github.com/iotaledger/hive.go/daemon"
 
 	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
@@ -20,6 +22,7 @@ import (
 var (
 	requestQueueEnqueueSignal      = make(chan struct{}, 2)
 	enqueuePendingRequestsInterval = 1500 * time.Millisecond
+	retryRequestsAfter             = 1500 * time.Millisecond
 	discardRequestsOlderThan       = 10 * time.Second
 	requestBackpressureSignals     [](func() bool)
 )
@@ -28,6 +31,20 @@ func AddRequestBackpressureSignal(reqFunc func() bool) {
 	requestBackpressureSignals = append(requestBackpressureSignals, reqFunc)
 }
 
+// requestSendQueuesSaturated tells whether the request send queues of all connected STING peers are saturated,
+// in which case enqueueing more pending requests would only lead to dropped requests.
+func requestSendQueuesSaturated() bool {
+	saturated := false
+	manager.ForAllConnected(func(p *peer.Peer) bool {
+		if !p.Protocol.Supports(sting.FeatureSet) {
+			return true
+		}
+		saturated = p.IsRequestSendQueueSaturated()
+		return saturated
+	})
+	return saturated
+}
+
 func runRequestWorkers() {
 	daemon.BackgroundWorker("PendingRequestsEnqueuer", func(shutdownSignal <-chan struct{}) {
 		enqueueTicker := time.NewTicker(enqueuePendingRequestsInterval)
@@ -45,8 +62,21 @@ func runRequestWorkers() {
 					}
 				}
 
+				// requests which were not answered in time count as slow answers of the requested peers
+				now := time.Now()
+				manager.ForAllConnected(func(p *peer.Peer) bool {
+					p.RequestLatency.Expire(retryRequestsAfter, now)
+					return true
+				})
+
+				discardExhaustedRequests()
+
 				// always fire the signal if something is in the queue, otherwise the sting request is not kicking in
-				queued := requestQueue.EnqueuePending(discardRequestsOlderThan)
+				queued, discarded := requestQueue.EnqueuePending(retryRequestsAfter, discardRequestsOlderThan)
+				if discarded > 0 {
+					metrics.SharedServerMetrics.DiscardedTransactionRequests.Add(uint32(discarded))
+					Events.RequestsDiscarded.Trigger(discarded)
+				}
 				if queued > 0 {
 					select {
 					case requestQueueEnqueueSignal <- struct{}{}:
@@ -71,22 +101,37 @@ func runRequestWorkers() {
 
 				// drain request queue
 				for r := RequestQueue().Next(); r != nil; r = RequestQueue().Next() {
-					requested := false
+					if r.RequestCount > 1 {
+						metrics.SharedServerMetrics.RetriedTransactionRequests.Inc()
+					}
+
+					// we only send a request message to a peer which actually has the data
+					// (r.MilestoneIndex > PrunedMilestoneIndex && r.MilestoneIndex <= SolidMilestoneIndex)
+					var candidates []*peer.Peer
 					manager.ForAllConnected(func(p *peer.Peer) bool {
-						if !p.Protocol.Supports(sting.FeatureSet) {
-							return true
-						}
-						// we only send a request message if the peer actually has the data
-						// (r.MilestoneIndex > PrunedMilestoneIndex && r.MilestoneIndex <= SolidMilestoneIndex)
-						if !p.HasDataFor(r.MilestoneIndex) {
-							return true
+						if p.Protocol.Supports(sting.FeatureSet) && p.HasDataFor(r.MilestoneIndex) {
+							candidates = append(candidates, p)
 						}
+						return true
+					})
 
-						helpers.SendTransactionRequest(p, r.Hash)
-						requested = true
-						return false
+					// the request is sent to the fastest candidate first
+					sort.SliceStable(candidates, func(i, j int) bool {
+						return candidates[i].IsFasterThan(candidates[j])
 					})
 
+					// a retried request is sent to the next candidate, so that a neighbor
+					// which doesn't answer doesn't block the request forever
+					requested := false
+					for i := 0; i < len(candidates); i++ {
+						p := candidates[(r.RequestCount-1+i)%len(candidates)]
+						if sendTransactionRequest(p, r.Hash) {
+							requested = true
+							break
+						}
+						// the request send queue of the peer is full, try the next one
+					}
+
 					if !requested {
 						// We have no neighbor that has the data for sure,
 						// so we ask all neighbors that could have the data
@@ -101,7 +146,7 @@ func runRequestWorkers() {
 								return true
 							}
 
-							helpers.SendTransactionRequest(p, r.Hash)
+							sendTransactionRequest(p, r.Hash)
 							return true
 						})
 					}
@@ -111,6 +156,51 @@ func runRequestWorkers() {
 	}, shutdown.PriorityRequestsProcessor)
 }
 
+// sends the request for the given transaction to the given peer and starts measuring its latency.
+func sendTransactionRequest(p *peer.Peer, hash hornet.Hash) bool {
+	if !helpers.SendTransactionRequest(p, hash) {
+		return false
+	}
+	p.RequestLatency.Sent(hash, time.Now())
+	return true
+}
+
+// DiscardPrunedRequests removes all requests from the request queue which are linked to
+// a milestone at or below the given pruning index, since they can't be solidified anymore.
+func DiscardPrunedRequests(pruningIndex milestone.Index) {
+	discarded := RequestQueue().DiscardBelow(pruningIndex + 1)
+	if discarded == 0 {
+		return
+	}
+
+	metrics.SharedServerMetrics.DiscardedTransactionRequests.Add(uint32(discarded))
+	Events.RequestsDiscarded.Trigger(discarded)
+}
+
+// drops the requests which were not answered within the maximum number of attempts.
+// requests linked to a milestone above the solid milestone are kept, they are needed to solidify the milestone.
+// the requests for the transactions of a solid milestone cone were all answered, so the remaining ones stem from
+// transactions outside of any milestone cone, whose past cone references data no neighbor has anymore.
+func discardExhaustedRequests() {
+	if maxRequestAttempts == 0 {
+		return
+	}
+
+	exhausted := RequestQueue().DiscardExhausted(maxRequestAttempts, tangle.GetSolidMilestoneIndex())
+	if len(exhausted) == 0 {
+		return
+	}
+
+	txHashes := make(hornet.Hashes, len(exhausted))
+	for i, r := range exhausted {
+		txHashes[i] = r.Hash
+	}
+
+	metrics.SharedServerMetrics.DiscardedTransactionRequests.Add(uint32(len(exhausted)))
+	Events.RequestsDiscarded.Trigger(len(exhausted))
+	Events.RequestsExhausted.Trigger(txHashes)
+}
+
 // adds the request to the request queue and signals the request to drain it.
 func enqueueAndSignal(r *rqueue.Request) bool {
 	if !RequestQueue().Enqueue(r) {
@@ -161,6 +251,7 @@ func RequestMultiple(hashes hornet.Hashes, msIndex milestone.Index, preventDisca
 
 // RequestApprovees enqueues requests for the approvees of the given transaction to the request queue, if the
 // given transaction is not a solid entry point and neither its approvees are and also not in the database.
+// The approvees of transactions which were marked as unsolidifiable are not requested again.
 func RequestApprovees(cachedTx *tangle.CachedTransaction, msIndex milestone.Index, preventDiscard ...bool) {
 	cachedTx.ConsumeMetadata(func(metadata *hornet.TransactionMetadata) {
 		txHash := metadata.GetTxHash()
@@ -169,6 +260,11 @@ func RequestApprovees(cachedTx *tangle.CachedTransaction, msIndex milestone.Inde
 			return
 		}
 
+		if metadata.IsUnsolidifiable() {
+			// the past cone of the transaction references transactions no neighbor answered requests for
+			return
+		}
+
 		Request(metadata.GetTrunkHash(), msIndex, preventDiscard...)
 		if !bytes.Equal(metadata.GetTrunkHash(), metadata.GetBranchHash()) {
 			Request(metadata.GetBranchHash(), msIndex, preventDiscard...)
@@ -211,7 +307,7 @@ func MemoizedRequestMissingMilestoneApprovees(preventDiscard ...bool) func(ms mi
 			log.Panicf("milestone %d wasn't found", ms)
 		}
 
-		msHash := cachedMs.GetMilestone().Hash
+		msHash := cachedMs.GetMilestone().ID.Hash()
 		cachedMs.Release(true) // bundle -1
 
 		dag.TraverseApprovees(msHash,
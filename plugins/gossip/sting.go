@@ -9,13 +9,18 @@ import (
 	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/pkg/peering/peer"
 	"github.com/gohornet/hornet/pkg/protocol/sting"
-	"github.com/gohornet/hornet/plugins/peering"
 )
 
 // sets up the event handlers which propagate STING messages.
 func addSTINGMessageEventHandlers(p *peer.Peer) {
 
+	// messages which exceed the rate limits are dropped before they are processed
+	peerRateLimiters := newPeerRateLimiters()
+
 	p.Protocol.Events.Received[sting.MessageTypeTransaction].Attach(events.NewClosure(func(data []byte) {
+		if !allowReceivedMessage(p, peerRateLimiters, RateLimitTransactions, data) {
+			return
+		}
 		p.Metrics.ReceivedTransactions.Inc()
 		metrics.SharedServerMetrics.Transactions.Inc()
 		msgProcessor.Process(p, sting.MessageTypeTransaction, data)
@@ -28,6 +33,9 @@ func addSTINGMessageEventHandlers(p *peer.Peer) {
 	}))
 
 	p.Protocol.Events.Received[sting.MessageTypeTransactionRequest].Attach(events.NewClosure(func(data []byte) {
+		if !allowReceivedMessage(p, peerRateLimiters, RateLimitRequests, data) {
+			return
+		}
 		p.Metrics.ReceivedTransactionRequests.Inc()
 		metrics.SharedServerMetrics.ReceivedTransactionRequests.Inc()
 		msgProcessor.Process(p, sting.MessageTypeTransactionRequest, data)
@@ -40,6 +48,9 @@ func addSTINGMessageEventHandlers(p *peer.Peer) {
 	}))
 
 	p.Protocol.Events.Received[sting.MessageTypeMilestoneRequest].Attach(events.NewClosure(func(data []byte) {
+		if !allowReceivedMessage(p, peerRateLimiters, RateLimitRequests, data) {
+			return
+		}
 		p.Metrics.ReceivedMilestoneRequests.Inc()
 		metrics.SharedServerMetrics.ReceivedMilestoneRequests.Inc()
 		msgProcessor.Process(p, sting.MessageTypeMilestoneRequest, data)
@@ -52,6 +63,7 @@ func addSTINGMessageEventHandlers(p *peer.Peer) {
 	}))
 
 	p.Protocol.Events.Received[sting.MessageTypeHeartbeat].Attach(events.NewClosure(func(data []byte) {
+		// heartbeats are not rate limited, otherwise the peer would be considered dead
 		p.Metrics.ReceivedHeartbeats.Inc()
 		metrics.SharedServerMetrics.ReceivedHeartbeats.Inc()
 
@@ -62,11 +74,12 @@ func addSTINGMessageEventHandlers(p *peer.Peer) {
 			// peer is connected via autopeering and its latest solid milestone index is below our pruning index.
 			// we can't help this neighbor to become sync, so it's better to drop the connection and free the slots for other peers.
 			log.Infof("dropping autopeered neighbor %s / %s because LSMI (%d) is below our pruning index (%d)", p.Autopeering.Address(), p.Autopeering.ID(), p.LatestHeartbeat.SolidMilestoneIndex, tangle.GetSnapshotInfo().PruningIndex)
-			peering.Manager().Remove(p.ID)
+			deps.PeeringManager.Remove(p.ID)
 			return
 		}
 
 		p.Events.HeartbeatUpdated.Trigger(p.LatestHeartbeat)
+		requestNeighborsLatestMilestones()
 	}))
 
 	p.Protocol.Events.Sent[sting.MessageTypeHeartbeat].Attach(events.NewClosure(func() {
@@ -10,6 +10,8 @@ import (
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
+
+	"github.com/gohornet/hornet/pkg/logging"
 )
 
 // the maximum amount of time to wait for background processes to terminate. After that the process is killed.
@@ -21,7 +23,7 @@ var (
 )
 
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
 	gracefulStop := make(chan os.Signal)
 
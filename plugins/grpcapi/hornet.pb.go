@@ -0,0 +1,949 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        (unknown)
+// source: hornet.proto
+
+package grpcapi
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type SubmitTransactionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// the trytes of the transactions (2673 trytes each)
+	Trytes []string `protobuf:"bytes,1,rep,name=trytes,proto3" json:"trytes,omitempty"`
+}
+
+func (x *SubmitTransactionsRequest) Reset() {
+	*x = SubmitTransactionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hornet_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitTransactionsRequest) ProtoMessage() {}
+
+func (x *SubmitTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hornet_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*SubmitTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_hornet_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubmitTransactionsRequest) GetTrytes() []string {
+	if x != nil {
+		return x.Trytes
+	}
+	return nil
+}
+
+type SubmitTransactionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// the hashes of the submitted transactions in the order of the request
+	Hashes []string `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+}
+
+func (x *SubmitTransactionsResponse) Reset() {
+	*x = SubmitTransactionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hornet_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitTransactionsResponse) ProtoMessage() {}
+
+func (x *SubmitTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hornet_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*SubmitTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_hornet_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitTransactionsResponse) GetHashes() []string {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+type GetTransactionMetadataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (x *GetTransactionMetadataRequest) Reset() {
+	*x = GetTransactionMetadataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hornet_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTransactionMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransactionMetadataRequest) ProtoMessage() {}
+
+func (x *GetTransactionMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hornet_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransactionMetadataRequest.ProtoReflect.Descriptor instead.
+func (*GetTransactionMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_hornet_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetTransactionMetadataRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type TransactionMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash                       string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Solid                      bool   `protobuf:"varint,2,opt,name=solid,proto3" json:"solid,omitempty"`
+	Referenced                 bool   `protobuf:"varint,3,opt,name=referenced,proto3" json:"referenced,omitempty"`
+	ReferencedByMilestoneIndex uint32 `protobuf:"varint,4,opt,name=referenced_by_milestone_index,json=referencedByMilestoneIndex,proto3" json:"referenced_by_milestone_index,omitempty"`
+	// one of "notReferenced", "noTransaction", "included" or "conflicting"
+	LedgerInclusionState string `protobuf:"bytes,5,opt,name=ledger_inclusion_state,json=ledgerInclusionState,proto3" json:"ledger_inclusion_state,omitempty"`
+	ConflictReason       uint32 `protobuf:"varint,6,opt,name=conflict_reason,json=conflictReason,proto3" json:"conflict_reason,omitempty"`
+	IsMilestone          bool   `protobuf:"varint,7,opt,name=is_milestone,json=isMilestone,proto3" json:"is_milestone,omitempty"`
+	MilestoneIndex       uint32 `protobuf:"varint,8,opt,name=milestone_index,json=milestoneIndex,proto3" json:"milestone_index,omitempty"`
+}
+
+func (x *TransactionMetadata) Reset() {
+	*x = TransactionMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hornet_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactionMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionMetadata) ProtoMessage() {}
+
+func (x *TransactionMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_hornet_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionMetadata.ProtoReflect.Descriptor instead.
+func (*TransactionMetadata) Descriptor() ([]byte, []int) {
+	return file_hornet_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TransactionMetadata) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *TransactionMetadata) GetSolid() bool {
+	if x != nil {
+		return x.Solid
+	}
+	return false
+}
+
+func (x *TransactionMetadata) GetReferenced() bool {
+	if x != nil {
+		return x.Referenced
+	}
+	return false
+}
+
+func (x *TransactionMetadata) GetReferencedByMilestoneIndex() uint32 {
+	if x != nil {
+		return x.ReferencedByMilestoneIndex
+	}
+	return 0
+}
+
+func (x *TransactionMetadata) GetLedgerInclusionState() string {
+	if x != nil {
+		return x.LedgerInclusionState
+	}
+	return ""
+}
+
+func (x *TransactionMetadata) GetConflictReason() uint32 {
+	if x != nil {
+		return x.ConflictReason
+	}
+	return 0
+}
+
+func (x *TransactionMetadata) GetIsMilestone() bool {
+	if x != nil {
+		return x.IsMilestone
+	}
+	return false
+}
+
+func (x *TransactionMetadata) GetMilestoneIndex() uint32 {
+	if x != nil {
+		return x.MilestoneIndex
+	}
+	return 0
+}
+
+type GetBalanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (x *GetBalanceRequest) Reset() {
+	*x = GetBalanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hornet_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceRequest) ProtoMessage() {}
+
+func (x *GetBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hornet_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_hornet_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetBalanceRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type Balance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Balance uint64 `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	// the index of the milestone the balance was confirmed with
+	LedgerIndex uint32 `protobuf:"varint,3,opt,name=ledger_index,json=ledgerIndex,proto3" json:"ledger_index,omitempty"`
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hornet_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_hornet_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_hornet_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Balance) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Balance) GetBalance() uint64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *Balance) GetLedgerIndex() uint32 {
+	if x != nil {
+		return x.LedgerIndex
+	}
+	return 0
+}
+
+type StreamConfirmationsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StreamConfirmationsRequest) Reset() {
+	*x = StreamConfirmationsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hornet_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamConfirmationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamConfirmationsRequest) ProtoMessage() {}
+
+func (x *StreamConfirmationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hornet_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamConfirmationsRequest.ProtoReflect.Descriptor instead.
+func (*StreamConfirmationsRequest) Descriptor() ([]byte, []int) {
+	return file_hornet_proto_rawDescGZIP(), []int{6}
+}
+
+type MilestoneConfirmation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MilestoneIndex uint32 `protobuf:"varint,1,opt,name=milestone_index,json=milestoneIndex,proto3" json:"milestone_index,omitempty"`
+	MilestoneHash  string `protobuf:"bytes,2,opt,name=milestone_hash,json=milestoneHash,proto3" json:"milestone_hash,omitempty"`
+	// the tails of the bundles which mutated the ledger
+	TailsIncluded []string `protobuf:"bytes,3,rep,name=tails_included,json=tailsIncluded,proto3" json:"tails_included,omitempty"`
+	// the tails of the bundles which were ignored because they conflicted with the ledger
+	TailsExcludedConflicting []string `protobuf:"bytes,4,rep,name=tails_excluded_conflicting,json=tailsExcludedConflicting,proto3" json:"tails_excluded_conflicting,omitempty"`
+	// the tails of the zero value bundles
+	TailsExcludedZeroValue []string `protobuf:"bytes,5,rep,name=tails_excluded_zero_value,json=tailsExcludedZeroValue,proto3" json:"tails_excluded_zero_value,omitempty"`
+}
+
+func (x *MilestoneConfirmation) Reset() {
+	*x = MilestoneConfirmation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hornet_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MilestoneConfirmation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MilestoneConfirmation) ProtoMessage() {}
+
+func (x *MilestoneConfirmation) ProtoReflect() protoreflect.Message {
+	mi := &file_hornet_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MilestoneConfirmation.ProtoReflect.Descriptor instead.
+func (*MilestoneConfirmation) Descriptor() ([]byte, []int) {
+	return file_hornet_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MilestoneConfirmation) GetMilestoneIndex() uint32 {
+	if x != nil {
+		return x.MilestoneIndex
+	}
+	return 0
+}
+
+func (x *MilestoneConfirmation) GetMilestoneHash() string {
+	if x != nil {
+		return x.MilestoneHash
+	}
+	return ""
+}
+
+func (x *MilestoneConfirmation) GetTailsIncluded() []string {
+	if x != nil {
+		return x.TailsIncluded
+	}
+	return nil
+}
+
+func (x *MilestoneConfirmation) GetTailsExcludedConflicting() []string {
+	if x != nil {
+		return x.TailsExcludedConflicting
+	}
+	return nil
+}
+
+func (x *MilestoneConfirmation) GetTailsExcludedZeroValue() []string {
+	if x != nil {
+		return x.TailsExcludedZeroValue
+	}
+	return nil
+}
+
+type StreamLedgerChangesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// the index of the milestone to start with, 0 to only stream the milestones confirmed from now on.
+	// it must be newer than the pruning index of the node.
+	MilestoneIndex uint32 `protobuf:"varint,1,opt,name=milestone_index,json=milestoneIndex,proto3" json:"milestone_index,omitempty"`
+	// the amount of changes of the first milestone which were already consumed
+	Offset uint32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+}
+
+func (x *StreamLedgerChangesRequest) Reset() {
+	*x = StreamLedgerChangesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hornet_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamLedgerChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamLedgerChangesRequest) ProtoMessage() {}
+
+func (x *StreamLedgerChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hornet_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamLedgerChangesRequest.ProtoReflect.Descriptor instead.
+func (*StreamLedgerChangesRequest) Descriptor() ([]byte, []int) {
+	return file_hornet_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StreamLedgerChangesRequest) GetMilestoneIndex() uint32 {
+	if x != nil {
+		return x.MilestoneIndex
+	}
+	return 0
+}
+
+func (x *StreamLedgerChangesRequest) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type LedgerChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MilestoneIndex uint32 `protobuf:"varint,1,opt,name=milestone_index,json=milestoneIndex,proto3" json:"milestone_index,omitempty"`
+	// the position of the change within the changes of the milestone, which are sorted by address.
+	// a stream is resumed with the cursor (milestone_index, offset + 1).
+	Offset uint32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// the amount of changes of the milestone
+	MilestoneChangeCount uint32 `protobuf:"varint,3,opt,name=milestone_change_count,json=milestoneChangeCount,proto3" json:"milestone_change_count,omitempty"`
+	Address              string `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	Change               int64  `protobuf:"varint,5,opt,name=change,proto3" json:"change,omitempty"`
+}
+
+func (x *LedgerChange) Reset() {
+	*x = LedgerChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hornet_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LedgerChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LedgerChange) ProtoMessage() {}
+
+func (x *LedgerChange) ProtoReflect() protoreflect.Message {
+	mi := &file_hornet_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LedgerChange.ProtoReflect.Descriptor instead.
+func (*LedgerChange) Descriptor() ([]byte, []int) {
+	return file_hornet_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LedgerChange) GetMilestoneIndex() uint32 {
+	if x != nil {
+		return x.MilestoneIndex
+	}
+	return 0
+}
+
+func (x *LedgerChange) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *LedgerChange) GetMilestoneChangeCount() uint32 {
+	if x != nil {
+		return x.MilestoneChangeCount
+	}
+	return 0
+}
+
+func (x *LedgerChange) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *LedgerChange) GetChange() int64 {
+	if x != nil {
+		return x.Change
+	}
+	return 0
+}
+
+var File_hornet_proto protoreflect.FileDescriptor
+
+var file_hornet_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x68, 0x6f, 0x72, 0x6e, 0x65, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09,
+	0x68, 0x6f, 0x72, 0x6e, 0x65, 0x74, 0x2e, 0x76, 0x31, 0x22, 0x33, 0x0a, 0x19, 0x53, 0x75, 0x62,
+	0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x72, 0x79, 0x74, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x74, 0x72, 0x79, 0x74, 0x65, 0x73, 0x22, 0x34,
+	0x0a, 0x1a, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x68, 0x61,
+	0x73, 0x68, 0x65, 0x73, 0x22, 0x33, 0x0a, 0x1d, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x22, 0xcd, 0x02, 0x0a, 0x13, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x6f, 0x6c, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x73, 0x6f, 0x6c, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x64, 0x12, 0x41, 0x0a, 0x1d, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x5f, 0x6d, 0x69, 0x6c,
+	0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x1a, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x64, 0x42, 0x79,
+	0x4d, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x34,
+	0x0a, 0x16, 0x6c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69,
+	0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14,
+	0x6c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74,
+	0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x63,
+	0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x21, 0x0a,
+	0x0c, 0x69, 0x73, 0x5f, 0x6d, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0b, 0x69, 0x73, 0x4d, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65,
+	0x12, 0x27, 0x0a, 0x0f, 0x6d, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6d, 0x69, 0x6c, 0x65, 0x73,
+	0x74, 0x6f, 0x6e, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x2d, 0x0a, 0x11, 0x47, 0x65, 0x74,
+	0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x60, 0x0a, 0x07, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x65, 0x64, 0x67, 0x65,
+	0x72, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6c,
+	0x65, 0x64, 0x67, 0x65, 0x72, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x1c, 0x0a, 0x1a, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x87, 0x02, 0x0a, 0x15, 0x4d, 0x69, 0x6c,
+	0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x6d, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x5f,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6d, 0x69, 0x6c,
+	0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x25, 0x0a, 0x0e, 0x6d,
+	0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x6d, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x5f, 0x69, 0x6e, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x12, 0x3c, 0x0a, 0x1a, 0x74, 0x61, 0x69,
+	0x6c, 0x73, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x66,
+	0x6c, 0x69, 0x63, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x18, 0x74,
+	0x61, 0x69, 0x6c, 0x73, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x66,
+	0x6c, 0x69, 0x63, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x39, 0x0a, 0x19, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x5f, 0x7a, 0x65, 0x72, 0x6f, 0x5f, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x16, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x45, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x5a, 0x65, 0x72, 0x6f, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x22, 0x5d, 0x0a, 0x1a, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4c, 0x65, 0x64, 0x67,
+	0x65, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x27, 0x0a, 0x0f, 0x6d, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6d, 0x69, 0x6c, 0x65, 0x73,
+	0x74, 0x6f, 0x6e, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x22, 0xb7, 0x01, 0x0a, 0x0c, 0x4c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x6d, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x5f,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6d, 0x69, 0x6c,
+	0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x6f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x12, 0x34, 0x0a, 0x16, 0x6d, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65,
+	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x14, 0x6d, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x32, 0xc8, 0x03, 0x0a, 0x04,
+	0x4e, 0x6f, 0x64, 0x65, 0x12, 0x61, 0x0a, 0x12, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x24, 0x2e, 0x68, 0x6f, 0x72,
+	0x6e, 0x65, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x25, 0x2e, 0x68, 0x6f, 0x72, 0x6e, 0x65, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62,
+	0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x28, 0x2e, 0x68, 0x6f, 0x72, 0x6e, 0x65, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x68, 0x6f,
+	0x72, 0x6e, 0x65, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x3e, 0x0a, 0x0a, 0x47,
+	0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1c, 0x2e, 0x68, 0x6f, 0x72, 0x6e,
+	0x65, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x68, 0x6f, 0x72, 0x6e, 0x65, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x60, 0x0a, 0x13, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x25, 0x2e, 0x68, 0x6f, 0x72, 0x6e, 0x65, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x68, 0x6f, 0x72, 0x6e,
+	0x65, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x69, 0x6c, 0x65, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x30, 0x01, 0x12, 0x57, 0x0a,
+	0x13, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x73, 0x12, 0x25, 0x2e, 0x68, 0x6f, 0x72, 0x6e, 0x65, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x68, 0x6f,
+	0x72, 0x6e, 0x65, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x65, 0x64, 0x67, 0x65, 0x72, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x30, 0x01, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x68, 0x6f, 0x72, 0x6e, 0x65, 0x74, 0x2f, 0x68, 0x6f,
+	0x72, 0x6e, 0x65, 0x74, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x2f, 0x67, 0x72, 0x70,
+	0x63, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_hornet_proto_rawDescOnce sync.Once
+	file_hornet_proto_rawDescData = file_hornet_proto_rawDesc
+)
+
+func file_hornet_proto_rawDescGZIP() []byte {
+	file_hornet_proto_rawDescOnce.Do(func() {
+		file_hornet_proto_rawDescData = protoimpl.X.CompressGZIP(file_hornet_proto_rawDescData)
+	})
+	return file_hornet_proto_rawDescData
+}
+
+var file_hornet_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_hornet_proto_goTypes = []interface{}{
+	(*SubmitTransactionsRequest)(nil),     // 0: hornet.v1.SubmitTransactionsRequest
+	(*SubmitTransactionsResponse)(nil),    // 1: hornet.v1.SubmitTransactionsResponse
+	(*GetTransactionMetadataRequest)(nil), // 2: hornet.v1.GetTransactionMetadataRequest
+	(*TransactionMetadata)(nil),           // 3: hornet.v1.TransactionMetadata
+	(*GetBalanceRequest)(nil),             // 4: hornet.v1.GetBalanceRequest
+	(*Balance)(nil),                       // 5: hornet.v1.Balance
+	(*StreamConfirmationsRequest)(nil),    // 6: hornet.v1.StreamConfirmationsRequest
+	(*MilestoneConfirmation)(nil),         // 7: hornet.v1.MilestoneConfirmation
+	(*StreamLedgerChangesRequest)(nil),    // 8: hornet.v1.StreamLedgerChangesRequest
+	(*LedgerChange)(nil),                  // 9: hornet.v1.LedgerChange
+}
+var file_hornet_proto_depIdxs = []int32{
+	0, // 0: hornet.v1.Node.SubmitTransactions:input_type -> hornet.v1.SubmitTransactionsRequest
+	2, // 1: hornet.v1.Node.GetTransactionMetadata:input_type -> hornet.v1.GetTransactionMetadataRequest
+	4, // 2: hornet.v1.Node.GetBalance:input_type -> hornet.v1.GetBalanceRequest
+	6, // 3: hornet.v1.Node.StreamConfirmations:input_type -> hornet.v1.StreamConfirmationsRequest
+	8, // 4: hornet.v1.Node.StreamLedgerChanges:input_type -> hornet.v1.StreamLedgerChangesRequest
+	1, // 5: hornet.v1.Node.SubmitTransactions:output_type -> hornet.v1.SubmitTransactionsResponse
+	3, // 6: hornet.v1.Node.GetTransactionMetadata:output_type -> hornet.v1.TransactionMetadata
+	5, // 7: hornet.v1.Node.GetBalance:output_type -> hornet.v1.Balance
+	7, // 8: hornet.v1.Node.StreamConfirmations:output_type -> hornet.v1.MilestoneConfirmation
+	9, // 9: hornet.v1.Node.StreamLedgerChanges:output_type -> hornet.v1.LedgerChange
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_hornet_proto_init() }
+func file_hornet_proto_init() {
+	if File_hornet_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_hornet_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitTransactionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hornet_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitTransactionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hornet_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTransactionMetadataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hornet_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactionMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hornet_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBalanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hornet_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Balance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hornet_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamConfirmationsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hornet_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MilestoneConfirmation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hornet_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamLedgerChangesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hornet_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LedgerChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_hornet_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_hornet_proto_goTypes,
+		DependencyIndexes: file_hornet_proto_depIdxs,
+		MessageInfos:      file_hornet_proto_msgTypes,
+	}.Build()
+	File_hornet_proto = out.File
+	file_hornet_proto_rawDesc = nil
+	file_hornet_proto_goTypes = nil
+	file_hornet_proto_depIdxs = nil
+}
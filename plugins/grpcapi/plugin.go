@@ -0,0 +1,151 @@
+package grpcapi
+
+import (
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+	"github.com/gohornet/hornet/plugins/tangle"
+)
+
+const (
+	// the amount of confirmations buffered for every stream before confirmations are dropped
+	confirmationsStreamBufferSize = 100
+)
+
+var (
+	// gRPC API is disabled by default
+	PLUGIN = node.NewPlugin("gRPC API", node.Disabled, configure, run)
+	log    *logger.Logger
+
+	server               *grpc.Server
+	serverShutdownSignal <-chan struct{}
+
+	confirmationSubscribersLock sync.RWMutex
+	confirmationSubscribers     = make(map[chan *MilestoneConfirmation]struct{})
+
+	ledgerSubscribersLock sync.RWMutex
+	ledgerSubscribers     = make(map[chan struct{}]struct{})
+)
+
+func configure(plugin *node.Plugin) {
+	log = logging.NewLogger(plugin.Name)
+
+	server = grpc.NewServer()
+	server.RegisterService(&serviceDesc, struct{}{})
+}
+
+func run(_ *node.Plugin) {
+
+	onMilestoneConfirmed := events.NewClosure(func(confirmation *whiteflag.Confirmation) {
+		publishConfirmation(&MilestoneConfirmation{
+			MilestoneIndex:           uint32(confirmation.MilestoneIndex),
+			MilestoneHash:            confirmation.MilestoneHash.Trytes(),
+			TailsIncluded:            confirmation.Mutations.TailsIncluded.Trytes(),
+			TailsExcludedConflicting: confirmation.Mutations.TailsExcludedConflicting.Trytes(),
+			TailsExcludedZeroValue:   confirmation.Mutations.TailsExcludedZeroValue.Trytes(),
+		})
+		notifyLedgerChanged()
+	})
+
+	daemon.BackgroundWorker("gRPC API server", func(shutdownSignal <-chan struct{}) {
+		serverShutdownSignal = shutdownSignal
+
+		bindAddr := config.NodeConfig.GetString(config.CfgGRPCBindAddress)
+		listener, err := net.Listen("tcp", bindAddr)
+		if err != nil {
+			log.Errorf("Starting gRPC API server failed: %v", err)
+			return
+		}
+
+		tangle.Events.MilestoneConfirmed.Attach(onMilestoneConfirmed)
+		defer tangle.Events.MilestoneConfirmed.Detach(onMilestoneConfirmed)
+
+		go func() {
+			log.Infof("You can now access the gRPC API using: %s", bindAddr)
+			if err := server.Serve(listener); err != nil {
+				log.Warnf("Stopping gRPC API server due to an error: %v", err)
+			}
+		}()
+
+		<-shutdownSignal
+		log.Info("Stopping gRPC API server ...")
+		server.GracefulStop()
+		log.Info("Stopping gRPC API server ... done")
+	}, shutdown.PriorityAPI)
+}
+
+// subscribeConfirmations returns a channel which receives the confirmed milestones.
+func subscribeConfirmations() chan *MilestoneConfirmation {
+	confirmations := make(chan *MilestoneConfirmation, confirmationsStreamBufferSize)
+
+	confirmationSubscribersLock.Lock()
+	defer confirmationSubscribersLock.Unlock()
+
+	confirmationSubscribers[confirmations] = struct{}{}
+	return confirmations
+}
+
+func unsubscribeConfirmations(confirmations chan *MilestoneConfirmation) {
+	confirmationSubscribersLock.Lock()
+	defer confirmationSubscribersLock.Unlock()
+
+	delete(confirmationSubscribers, confirmations)
+}
+
+// publishConfirmation sends the confirmation to all streams.
+// Confirmations are dropped for streams whose buffer is full.
+func publishConfirmation(confirmation *MilestoneConfirmation) {
+	confirmationSubscribersLock.RLock()
+	defer confirmationSubscribersLock.RUnlock()
+
+	for confirmations := range confirmationSubscribers {
+		select {
+		case confirmations <- confirmation:
+		default:
+			log.Debugf("dropped confirmation of milestone %d, stream is too slow", confirmation.MilestoneIndex)
+		}
+	}
+}
+
+// subscribeLedgerChanged returns a channel which is notified after the ledger was changed by a milestone.
+// Notifications are coalesced, the changes itself are read from the stored ledger diffs.
+func subscribeLedgerChanged() chan struct{} {
+	notifications := make(chan struct{}, 1)
+
+	ledgerSubscribersLock.Lock()
+	defer ledgerSubscribersLock.Unlock()
+
+	ledgerSubscribers[notifications] = struct{}{}
+	return notifications
+}
+
+func unsubscribeLedgerChanged(notifications chan struct{}) {
+	ledgerSubscribersLock.Lock()
+	defer ledgerSubscribersLock.Unlock()
+
+	delete(ledgerSubscribers, notifications)
+}
+
+func notifyLedgerChanged() {
+	ledgerSubscribersLock.RLock()
+	defer ledgerSubscribersLock.RUnlock()
+
+	for notifications := range ledgerSubscribers {
+		select {
+		case notifications <- struct{}{}:
+		default:
+			// the stream wasn't notified about the last change yet
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/iota.go/guards"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/compressed"
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/protocol/processor"
+	"github.com/gohornet/hornet/plugins/gossip"
+)
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative hornet.proto
+
+const (
+	// the name of the service in hornet.proto
+	serviceName = "hornet.v1.Node"
+
+	waitForNodeSyncedTimeout = 2000 * time.Millisecond
+)
+
+// serviceDesc describes the Node service of hornet.proto.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("SubmitTransactions", func() interface{} { return &SubmitTransactionsRequest{} }, submitTransactions),
+		unaryMethod("GetTransactionMetadata", func() interface{} { return &GetTransactionMetadataRequest{} }, getTransactionMetadata),
+		unaryMethod("GetBalance", func() interface{} { return &GetBalanceRequest{} }, getBalance),
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamConfirmations",
+			Handler:       streamConfirmations,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamLedgerChanges",
+			Handler:       streamLedgerChanges,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "hornet.proto",
+}
+
+// unaryHandlerFunc handles a unary call and returns the response message.
+type unaryHandlerFunc func(ctx context.Context, request interface{}) (interface{}, error)
+
+// unaryMethod describes a unary method of the service.
+// The messages are the ones generated from hornet.proto, which are serialized by the default codec of gRPC.
+func unaryMethod(name string, newRequest func() interface{}, handler unaryHandlerFunc) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			request := newRequest()
+			if err := dec(request); err != nil {
+				return nil, err
+			}
+
+			if interceptor == nil {
+				return handler(ctx, request)
+			}
+
+			info := &grpc.UnaryServerInfo{
+				Server:     nil,
+				FullMethod: "/" + serviceName + "/" + name,
+			}
+			return interceptor(ctx, request, info, grpc.UnaryHandler(handler))
+		},
+	}
+}
+
+func submitTransactions(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*SubmitTransactionsRequest)
+
+	if len(req.Trytes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no trytes provided")
+	}
+
+	maxRequestsList := config.NodeConfig.GetInt(config.CfgWebAPILimitsMaxRequestsList)
+	if len(req.Trytes) > maxRequestsList {
+		return nil, status.Errorf(codes.InvalidArgument, "too many transactions, max. %d allowed", maxRequestsList)
+	}
+
+	hashes := make([]string, len(req.Trytes))
+	for i, trytes := range req.Trytes {
+		if !guards.IsTransactionTrytes(trytes) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid transaction trytes at index %d", i)
+		}
+
+		txTrits, err := trinary.TrytesToTrits(trytes)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid trytes at index %d: %v", i, err)
+		}
+		hashes[i] = compressed.TransactionHash(txTrits)
+	}
+
+	if err := gossip.Processor().SubmitTransactionTrytes(req.Trytes); err != nil {
+		if errors.Is(err, processor.ErrSubmissionQueueFull) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &SubmitTransactionsResponse{Hashes: hashes}, nil
+}
+
+func getTransactionMetadata(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*GetTransactionMetadataRequest)
+
+	txHash, err := hornet.HashFromTrytes(req.Hash)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid transaction hash: %v", err)
+	}
+
+	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(txHash) // meta +1
+	if cachedTxMeta == nil {
+		return nil, status.Errorf(codes.NotFound, "transaction %s not found", req.Hash)
+	}
+	defer cachedTxMeta.Release(true) // meta -1
+
+	metadata := cachedTxMeta.GetMetadata()
+	referenced, referencedByIndex := metadata.GetReferenced()
+	isMilestone, milestoneIndex := metadata.GetMilestone()
+
+	return &TransactionMetadata{
+		Hash:                       txHash.Trytes(),
+		Solid:                      metadata.IsSolid(),
+		Referenced:                 referenced,
+		ReferencedByMilestoneIndex: uint32(referencedByIndex),
+		LedgerInclusionState:       string(metadata.GetLedgerInclusionState()),
+		ConflictReason:             uint32(metadata.GetConflictReason()),
+		IsMilestone:                isMilestone,
+		MilestoneIndex:             uint32(milestoneIndex),
+	}, nil
+}
+
+func getBalance(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*GetBalanceRequest)
+
+	addr, err := hornet.AddressFromTrytes(req.Address)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid address: %v", err)
+	}
+
+	if !tangle.WaitForNodeSynced(waitForNodeSyncedTimeout) {
+		return nil, status.Error(codes.Unavailable, tangle.ErrNodeNotSynced.Error())
+	}
+
+	balance, ledgerIndex, err := tangle.Ledger().GetBalanceForAddress(addr)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ledger state invalid: %v", err)
+	}
+
+	return &Balance{
+		Address:     addr.Trytes(),
+		Balance:     balance,
+		LedgerIndex: uint32(ledgerIndex),
+	}, nil
+}
+
+func streamConfirmations(_ interface{}, stream grpc.ServerStream) error {
+	if err := stream.RecvMsg(&StreamConfirmationsRequest{}); err != nil {
+		return err
+	}
+
+	confirmations := subscribeConfirmations()
+	defer unsubscribeConfirmations(confirmations)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+
+		case <-serverShutdownSignal:
+			return status.Error(codes.Unavailable, "node is shutting down")
+
+		case confirmation := <-confirmations:
+			if err := stream.SendMsg(confirmation); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func streamLedgerChanges(_ interface{}, stream grpc.ServerStream) error {
+	req := &StreamLedgerChangesRequest{}
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+
+	// subscribe before the replay, so no milestone is missed in between
+	notifications := subscribeLedgerChanged()
+	defer unsubscribeLedgerChanged(notifications)
+
+	index := milestone.Index(req.MilestoneIndex)
+	offset := int(req.Offset)
+	if index == 0 {
+		index = tangle.Ledger().MilestoneIndex() + 1
+		offset = 0
+	}
+
+	for {
+		// replay the stored ledger diffs up to the current ledger state
+		for ; index <= tangle.Ledger().MilestoneIndex(); index++ {
+			if err := sendLedgerChanges(stream, index, offset); err != nil {
+				return err
+			}
+			offset = 0
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+
+		case <-serverShutdownSignal:
+			return status.Error(codes.Unavailable, "node is shutting down")
+
+		case <-notifications:
+		}
+	}
+}
+
+// sendLedgerChanges sends the changes of the ledger diff of the given milestone, starting at the given offset.
+// The changes are sorted by address, so that the offset of a change is the same for every stream.
+func sendLedgerChanges(stream grpc.ServerStream, index milestone.Index, offset int) error {
+	diff, err := tangle.Ledger().GetLedgerDiffForMilestone(index, serverShutdownSignal)
+	if err != nil {
+		return status.Errorf(codes.Internal, "loading ledger diff of milestone %d failed: %v", index, err)
+	}
+
+	// the diff is empty if it was pruned in the meantime
+	var pruningIndex milestone.Index
+	if snapshotInfo := tangle.GetSnapshotInfo(); snapshotInfo != nil {
+		pruningIndex = snapshotInfo.PruningIndex
+	}
+	if index <= pruningIndex {
+		return status.Errorf(codes.OutOfRange, "milestone %d is already pruned, minimum: %d", index, pruningIndex+1)
+	}
+
+	addresses := make([]string, 0, len(diff))
+	for address := range diff {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	if offset > len(addresses) {
+		return status.Errorf(codes.InvalidArgument, "offset %d exceeds the %d changes of milestone %d", offset, len(addresses), index)
+	}
+
+	for i := offset; i < len(addresses); i++ {
+		if err := stream.SendMsg(&LedgerChange{
+			MilestoneIndex:       uint32(index),
+			Offset:               uint32(i),
+			MilestoneChangeCount: uint32(len(addresses)),
+			Address:              hornet.Hash(addresses[i]).Trytes(),
+			Change:               diff[addresses[i]],
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	_ "golang.org/x/crypto/blake2b"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/gohornet/hornet/pkg/testsuite/utils"
+)
+
+const (
+	seed1 = "JBN9ZRCOH9YRUGSWIQNZWAIFEZUBDUGTFPVRKXWPAUCEQQFS9NHPQLXCKZKRHVCCUZNF9CZZWKXRZVCWQ"
+	seed2 = "JBNAZRCOH9YRUGSWIQNZWAIFEZUBDUGTFPVRKXWPAUCEQQFS9NHPQLXCKZKRHVCCUZNF9CZZWKXRZVCWQ"
+)
+
+// newTestClient serves the API on an in-memory listener and returns a client connected to it.
+func newTestClient(t *testing.T) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+
+	testServer := grpc.NewServer()
+	testServer.RegisterService(&serviceDesc, struct{}{})
+	go func() {
+		_ = testServer.Serve(listener)
+	}()
+	t.Cleanup(testServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	codec := encoding.GetCodec("proto")
+
+	messages := []proto.Message{
+		&SubmitTransactionsRequest{Trytes: []string{"ABC", "", "DEF"}},
+		&TransactionMetadata{Hash: "ABC", Solid: true, ReferencedByMilestoneIndex: 5, LedgerInclusionState: "included", MilestoneIndex: 5},
+		&MilestoneConfirmation{MilestoneIndex: 7, MilestoneHash: "ABC", TailsIncluded: []string{"A", "B"}, TailsExcludedZeroValue: []string{"C"}},
+		&StreamLedgerChangesRequest{MilestoneIndex: 3, Offset: 2},
+		&LedgerChange{MilestoneIndex: 3, Offset: 1, MilestoneChangeCount: 2, Address: "ABC", Change: -100},
+	}
+
+	for _, msg := range messages {
+		data, err := codec.Marshal(msg)
+		require.NoError(t, err)
+
+		decoded := msg.ProtoReflect().New().Interface()
+		require.NoError(t, codec.Unmarshal(data, decoded))
+		require.True(t, proto.Equal(msg, decoded), "%v != %v", msg, decoded)
+	}
+}
+
+func TestCodecWireFormat(t *testing.T) {
+	// the negative change is encoded as a 64 bit two's complement varint like every proto3 int64
+	data, err := proto.Marshal(&LedgerChange{MilestoneIndex: 1, Change: -1})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x08, 0x01, 0x28, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, data)
+}
+
+func TestGetTransactionMetadata(t *testing.T) {
+	te := testsuite.SetupTestEnvironment(t, make(map[string]uint64), 2, false)
+	defer te.CleanupTestEnvironment(true)
+
+	conn := newTestClient(t)
+
+	msBundle := te.Milestones[0].GetBundle()
+
+	response := &TransactionMetadata{}
+	require.NoError(t, conn.Invoke(context.Background(), "/hornet.v1.Node/GetTransactionMetadata",
+		&GetTransactionMetadataRequest{Hash: msBundle.GetTailHash().Trytes()}, response))
+
+	require.Equal(t, msBundle.GetTailHash().Trytes(), response.GetHash())
+	require.True(t, response.GetReferenced())
+	require.Equal(t, uint32(msBundle.GetMilestoneIndex()), response.GetReferencedByMilestoneIndex())
+	require.Equal(t, "noTransaction", response.GetLedgerInclusionState())
+	require.True(t, response.GetIsMilestone())
+	require.Equal(t, uint32(msBundle.GetMilestoneIndex()), response.GetMilestoneIndex())
+
+	err := conn.Invoke(context.Background(), "/hornet.v1.Node/GetTransactionMetadata",
+		&GetTransactionMetadataRequest{Hash: "INVALID"}, response)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	err = conn.Invoke(context.Background(), "/hornet.v1.Node/GetTransactionMetadata",
+		&GetTransactionMetadataRequest{Hash: "999999999999999999999999999999999999999999999999999999999999999999999999999999999"}, response)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// openLedgerChangesStream opens a StreamLedgerChanges stream starting at the given cursor.
+func openLedgerChangesStream(t *testing.T, ctx context.Context, conn *grpc.ClientConn, request *StreamLedgerChangesRequest) grpc.ClientStream {
+	stream, err := conn.NewStream(ctx, &serviceDesc.Streams[1], "/hornet.v1.Node/StreamLedgerChanges")
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(request))
+	require.NoError(t, stream.CloseSend())
+	return stream
+}
+
+func TestStreamLedgerChanges(t *testing.T) {
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(t, seed1, 0))] = 1000
+
+	te := testsuite.SetupTestEnvironment(t, balances, 2, false)
+	defer te.CleanupTestEnvironment(true)
+
+	conn := newTestClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	msIndex := tangle.Ledger().MilestoneIndex() + 1
+	stream := openLedgerChangesStream(t, ctx, conn, &StreamLedgerChangesRequest{MilestoneIndex: uint32(msIndex)})
+
+	// transfer 100 from seed1[0] to seed2[0], the remainder goes to seed1[1]
+	bundle := te.AttachAndStoreBundle(te.Milestones[0].GetBundle().GetTailHash(), te.Milestones[1].GetBundle().GetTailHash(), utils.ValueTx(t, "A", seed1, 0, 1000, seed2, 0, 100))
+	te.IssueAndConfirmMilestoneOnTip(bundle.GetBundle().GetTailHash(), false)
+	notifyLedgerChanged()
+
+	expected := map[string]int64{
+		hornet.Hash(utils.GenerateAddress(t, seed1, 0)).Trytes(): -1000,
+		hornet.Hash(utils.GenerateAddress(t, seed1, 1)).Trytes(): 900,
+		hornet.Hash(utils.GenerateAddress(t, seed2, 0)).Trytes(): 100,
+	}
+
+	received := make(map[string]int64)
+	for i := 0; i < len(expected); i++ {
+		change := &LedgerChange{}
+		require.NoError(t, stream.RecvMsg(change))
+		require.Equal(t, uint32(msIndex), change.GetMilestoneIndex())
+		require.Equal(t, uint32(i), change.GetOffset())
+		require.Equal(t, uint32(len(expected)), change.GetMilestoneChangeCount())
+		received[change.GetAddress()] = change.GetChange()
+	}
+	require.Equal(t, expected, received)
+
+	// a resumed stream replays the remaining changes of the stored ledger diff
+	resumeCtx, resumeCancel := context.WithCancel(context.Background())
+	defer resumeCancel()
+
+	resumed := openLedgerChangesStream(t, resumeCtx, conn, &StreamLedgerChangesRequest{MilestoneIndex: uint32(msIndex), Offset: 2})
+	change := &LedgerChange{}
+	require.NoError(t, resumed.RecvMsg(change))
+	require.Equal(t, uint32(msIndex), change.GetMilestoneIndex())
+	require.Equal(t, uint32(2), change.GetOffset())
+	resumeCancel()
+
+	// the cancellation by the client ends the stream and removes its subscription
+	cancel()
+	err := stream.RecvMsg(&LedgerChange{})
+	require.Equal(t, codes.Canceled, status.Code(err))
+
+	require.Eventually(t, func() bool {
+		ledgerSubscribersLock.RLock()
+		defer ledgerSubscribersLock.RUnlock()
+		return len(ledgerSubscribers) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
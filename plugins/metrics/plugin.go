@@ -4,16 +4,23 @@ import (
 	"time"
 
 	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
 	"github.com/iotaledger/hive.go/timeutil"
 
+	"github.com/gohornet/hornet/pkg/budget"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/shutdown"
 )
 
-var PLUGIN = node.NewPlugin("Metrics", node.Enabled, configure, run)
+var (
+	PLUGIN = node.NewPlugin("Metrics", node.Enabled, configure, run)
+	log    *logger.Logger
+)
 
-func configure(_ *node.Plugin) {
-	// nothing
+func configure(plugin *node.Plugin) {
+	log = logging.NewLogger(plugin.Name)
 }
 
 func run(_ *node.Plugin) {
@@ -21,4 +28,15 @@ func run(_ *node.Plugin) {
 	daemon.BackgroundWorker("Metrics TPS Updater", func(shutdownSignal <-chan struct{}) {
 		timeutil.Ticker(measureTPS, 1*time.Second, shutdownSignal)
 	}, shutdown.PriorityMetricsUpdater)
+
+	onBudgetExceeded := events.NewClosure(func(b *budget.Budget, resource string, usage float64, limit float64) {
+		log.Warnf("Plugin %s exceeded its %s budget (usage: %0.2f, limit: %0.2f)", b.Name(), resource, usage, limit)
+	})
+
+	// create a background worker that checks the plugin budgets every second
+	daemon.BackgroundWorker("Metrics Budget Checker", func(shutdownSignal <-chan struct{}) {
+		budget.Events.BudgetExceeded.Attach(onBudgetExceeded)
+		defer budget.Events.BudgetExceeded.Detach(onBudgetExceeded)
+		timeutil.Ticker(budget.CheckAll, 1*time.Second, shutdownSignal)
+	}, shutdown.PriorityMetricsUpdater)
 }
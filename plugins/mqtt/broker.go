@@ -1,35 +1,61 @@
 package mqtt
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/eclipse/paho.mqtt.golang/packets"
 	"github.com/fhmq/hmq/broker"
+
+	"github.com/gohornet/hornet/pkg/budget"
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/watch"
+)
+
+var (
+	// ErrMemoryBudgetExceeded is returned when a message was dropped because the memory budget of the plugin is exhausted.
+	ErrMemoryBudgetExceeded = errors.New("message dropped, memory budget exceeded")
 )
 
 // Simple mqtt publisher abstraction
 type Broker struct {
-	broker *broker.Broker
-	config *broker.Config
+	broker        *broker.Broker
+	config        *broker.Config
+	budget        *budget.Budget
+	subscriptions *subscriptionManager
+	watches       *watchManager
 }
 
 // Create a new publisher.
-func NewBroker() (*Broker, error) {
+func NewBroker(pluginBudget *budget.Budget) (*Broker, error) {
 	mqttConfigFile := config.NodeConfig.GetString(config.CfgMQTTConfig)
 	c, err := broker.ConfigureConfig([]string{fmt.Sprintf("--config=%s", mqttConfigFile)})
 	if err != nil {
 		log.Fatal("configure broker config error: ", err)
 	}
 
+	// hook into the broker to keep track of the subscribed topics
+	subscriptions := newSubscriptionManager(c.Plugin.Bridge)
+	c.Plugin.Bridge = subscriptions
+
+	// hook into the broker to receive the watch requests of the clients
+	watches := newWatchManager(c.Plugin.Bridge, config.NodeConfig.GetInt(config.CfgMQTTWatchAddresses))
+	c.Plugin.Bridge = watches
+
+	// only allow the clients to subscribe to their own watch topic
+	c.Plugin.Auth = newWatchAuth(c.Plugin.Auth)
+
 	b, err := broker.NewBroker(c)
 	if err != nil {
 		log.Fatal("New Broker error: ", err)
 	}
 
 	return &Broker{
-		broker: b,
-		config: c,
+		broker:        b,
+		config:        c,
+		budget:        pluginBudget,
+		subscriptions: subscriptions,
+		watches:       watches,
 	}, nil
 }
 
@@ -45,9 +71,26 @@ func (b *Broker) Shutdown() error {
 	return nil
 }
 
+// HasSubscribers returns whether at least one client is subscribed to the given topic.
+func (b *Broker) HasSubscribers(topic string) bool {
+	return b.subscriptions.HasSubscribers(topic)
+}
+
+// WatchRegistry returns the registry of the addresses watched by the clients.
+func (b *Broker) WatchRegistry() *watch.Registry {
+	return b.watches.registry
+}
+
 // Publish a new list of messages.
 func (b *Broker) Send(topic string, message string) error {
 
+	// account the payload to the memory budget while it is published
+	size := int64(len(topic) + len(message))
+	if !b.budget.Alloc(size) {
+		return ErrMemoryBudgetExceeded
+	}
+	defer b.budget.Free(size)
+
 	packet := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
 	packet.TopicName = topic
 	packet.Qos = 0
@@ -1,6 +1,7 @@
 package mqtt
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/whiteflag"
 )
 
 var (
@@ -32,6 +34,11 @@ func onNewTx(cachedTx *tangle.CachedTransaction) {
 		if err != nil {
 			log.Warn(err.Error())
 		}
+
+		// transactions/tag/{tag} topic
+		if err := publishTxForTag(tx.Tx); err != nil {
+			log.Warn(err.Error())
+		}
 	})
 }
 
@@ -72,6 +79,10 @@ func onNewLatestMilestone(cachedBndl *tangle.CachedBundle) {
 	if err != nil {
 		log.Warn(err.Error())
 	}
+	err = publishMilestone(topicMilestonesLatest, cachedBndl.GetBundle().GetMilestoneIndex())
+	if err != nil {
+		log.Warn(err.Error())
+	}
 	cachedBndl.Release(true) // bundle -1
 }
 
@@ -87,6 +98,47 @@ func onNewSolidMilestone(cachedBndl *tangle.CachedBundle) {
 	cachedBndl.Release(true) // bundle -1
 }
 
+func onMilestoneConfirmed(confirmation *whiteflag.Confirmation) {
+	if err := publishMilestone(topicMilestonesConfirmed, confirmation.MilestoneIndex); err != nil {
+		log.Warn(err.Error())
+	}
+
+	for addr, balance := range confirmation.Mutations.NewAddressState {
+		if err := publishAddressBalance(hornet.Hash(addr).Trytes(), uint64(balance), confirmation.MilestoneIndex); err != nil {
+			log.Warn(err.Error())
+		}
+	}
+
+	// watch/{clientID} topics
+	mqttBroker.WatchRegistry().OnMilestoneConfirmed(confirmation)
+}
+
+// onTransactionMetadataChanged is called synchronously by the metadata events, so it only builds the payloads
+// for topics with subscribers and hands them over to the worker pool.
+func onTransactionMetadataChanged(metadata *hornet.TransactionMetadata, inclusionStateChanged bool) {
+	txHash := metadata.GetTxHash().Trytes()
+
+	if topic := topicForTransactionMetadata(txHash); mqttBroker.HasSubscribers(topic) {
+		submitPayload(topic, marshalTransactionMetadata(txHash, metadata))
+	}
+
+	if !inclusionStateChanged {
+		return
+	}
+
+	if topic := topicForTransactionInclusionState(txHash); mqttBroker.HasSubscribers(topic) {
+		submitPayload(topic, marshalTransactionInclusionState(txHash, metadata))
+	}
+}
+
+// submitPayload hands a payload over to the worker pool which publishes it.
+func submitPayload(topic string, payload []byte) {
+	if payload == nil {
+		return
+	}
+	metadataWorkerPool.TrySubmit(topic, payload)
+}
+
 func onSpentAddress(addr trinary.Hash) {
 	if err := publishSpentAddress(addr); err != nil {
 		log.Warn(err.Error())
@@ -190,8 +242,20 @@ func publishTxTrytes(iotaTx *transaction.Transaction) error {
 
 // Publish a transaction that has recently been added to the ledger
 func publishTx(iotaTx *transaction.Transaction) error {
+	return mqttBroker.Send(topicTX, txPayload(iotaTx))
+}
 
-	return mqttBroker.Send(topicTX, fmt.Sprintf(`{"txHash":"%v","address":"%v","value":%d,"obsoleteTag":"%v","txTimestamp":%d,"currentIndex":%d,"lastIndex":%d,"bundle":"%v","trunk":"%v","branch":"%v","recTimestamp":%d,"tag":"%v","timestamp":"%s"}`,
+// Publish a transaction that has recently been added to the ledger to the topic of its tag
+func publishTxForTag(iotaTx *transaction.Transaction) error {
+	topic := topicForTag(iotaTx.Tag)
+	if !mqttBroker.HasSubscribers(topic) {
+		return nil
+	}
+	return mqttBroker.Send(topic, txPayload(iotaTx))
+}
+
+func txPayload(iotaTx *transaction.Transaction) string {
+	return fmt.Sprintf(`{"txHash":"%v","address":"%v","value":%d,"obsoleteTag":"%v","txTimestamp":%d,"currentIndex":%d,"lastIndex":%d,"bundle":"%v","trunk":"%v","branch":"%v","recTimestamp":%d,"tag":"%v","timestamp":"%s"}`,
 		iotaTx.Hash,              // Transaction hash
 		iotaTx.Address,           // Address
 		iotaTx.Value,             // Value
@@ -204,9 +268,122 @@ func publishTx(iotaTx *transaction.Transaction) error {
 		iotaTx.BranchTransaction, // Branch transaction hash
 		time.Now().Unix(),        // Unix timestamp for when the transaction was received
 		iotaTx.Tag,               // Tag
-		time.Now().UTC().Format(time.RFC3339)))
+		time.Now().UTC().Format(time.RFC3339))
 }
 
 func publishSpentAddress(addr trinary.Hash) error {
 	return mqttBroker.Send(topicSpentAddress, addr)
 }
+
+// milestonePayload is the payload of the milestone topics.
+type milestonePayload struct {
+	Index     milestone.Index `json:"index"`
+	Hash      trinary.Hash    `json:"hash"`
+	Timestamp int64           `json:"timestamp,omitempty"`
+}
+
+// Publish a milestone to the given milestone topic
+func publishMilestone(topic string, msIndex milestone.Index) error {
+	cachedMs := tangle.GetCachedMilestoneOrNil(msIndex) // milestone +1
+	if cachedMs == nil {
+		return fmt.Errorf("milestone %d not found", msIndex)
+	}
+	defer cachedMs.Release(true) // milestone -1
+
+	ms := cachedMs.GetMilestone()
+
+	payload := &milestonePayload{
+		Index: ms.Index,
+		Hash:  ms.ID.Trytes(),
+	}
+	if !ms.Timestamp.IsZero() {
+		payload.Timestamp = ms.Timestamp.Unix()
+	}
+
+	return publishJSON(topic, payload)
+}
+
+// addressPayload is the payload of the address topics.
+type addressPayload struct {
+	Address     trinary.Hash    `json:"address"`
+	Balance     uint64          `json:"balance"`
+	LedgerIndex milestone.Index `json:"ledgerIndex"`
+}
+
+// Publish the new balance of an address that was mutated by a milestone, if a client is subscribed to it
+func publishAddressBalance(addr trinary.Hash, balance uint64, msIndex milestone.Index) error {
+	topic := topicForAddress(addr)
+	if !mqttBroker.HasSubscribers(topic) {
+		return nil
+	}
+
+	return publishJSON(topic, &addressPayload{
+		Address:     addr,
+		Balance:     balance,
+		LedgerIndex: msIndex,
+	})
+}
+
+// transactionMetadataPayload is the payload of the transaction metadata topics.
+type transactionMetadataPayload struct {
+	TxHash                     trinary.Hash                `json:"txHash"`
+	Solid                      bool                        `json:"solid"`
+	Referenced                 bool                        `json:"referenced"`
+	ReferencedByMilestoneIndex milestone.Index             `json:"referencedByMilestoneIndex,omitempty"`
+	LedgerInclusionState       hornet.LedgerInclusionState `json:"ledgerInclusionState"`
+	ConflictReason             hornet.ConflictReason       `json:"conflictReason,omitempty"`
+	IsMilestone                bool                        `json:"isMilestone"`
+	MilestoneIndex             milestone.Index             `json:"milestoneIndex,omitempty"`
+}
+
+func marshalTransactionMetadata(txHash trinary.Hash, metadata *hornet.TransactionMetadata) []byte {
+	referenced, referencedByIndex := metadata.GetReferenced()
+	isMilestone, milestoneIndex := metadata.GetMilestone()
+
+	return marshalPayload(&transactionMetadataPayload{
+		TxHash:                     txHash,
+		Solid:                      metadata.IsSolid(),
+		Referenced:                 referenced,
+		ReferencedByMilestoneIndex: referencedByIndex,
+		LedgerInclusionState:       metadata.GetLedgerInclusionState(),
+		ConflictReason:             metadata.GetConflictReason(),
+		IsMilestone:                isMilestone,
+		MilestoneIndex:             milestoneIndex,
+	})
+}
+
+// transactionInclusionStatePayload is the payload of the transaction inclusion state topics.
+type transactionInclusionStatePayload struct {
+	TxHash                     trinary.Hash                `json:"txHash"`
+	ReferencedByMilestoneIndex milestone.Index             `json:"referencedByMilestoneIndex"`
+	LedgerInclusionState       hornet.LedgerInclusionState `json:"ledgerInclusionState"`
+	ConflictReason             hornet.ConflictReason       `json:"conflictReason,omitempty"`
+}
+
+func marshalTransactionInclusionState(txHash trinary.Hash, metadata *hornet.TransactionMetadata) []byte {
+	_, referencedByIndex := metadata.GetReferenced()
+
+	return marshalPayload(&transactionInclusionStatePayload{
+		TxHash:                     txHash,
+		ReferencedByMilestoneIndex: referencedByIndex,
+		LedgerInclusionState:       metadata.GetLedgerInclusionState(),
+		ConflictReason:             metadata.GetConflictReason(),
+	})
+}
+
+func marshalPayload(payload interface{}) []byte {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil
+	}
+	return data
+}
+
+func publishJSON(topic string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return mqttBroker.Send(topic, string(data))
+}
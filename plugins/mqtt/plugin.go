@@ -9,9 +9,13 @@ import (
 	"github.com/iotaledger/hive.go/node"
 	"github.com/iotaledger/hive.go/workerpool"
 
+	"github.com/gohornet/hornet/pkg/budget"
+	"github.com/gohornet/hornet/pkg/logging"
+	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	tanglePackage "github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/whiteflag"
 	"github.com/gohornet/hornet/plugins/tangle"
 )
 
@@ -36,46 +40,83 @@ var (
 	newSolidMilestoneWorkerQueueSize = 100
 	newSolidMilestoneWorkerPool      *workerpool.WorkerPool
 
+	milestoneConfirmedWorkerCount     = 1
+	milestoneConfirmedWorkerQueueSize = 100
+	milestoneConfirmedWorkerPool      *workerpool.WorkerPool
+
+	metadataWorkerCount     = 1
+	metadataWorkerQueueSize = 10000
+	metadataWorkerPool      *workerpool.WorkerPool
+
 	spentAddressWorkerCount     = 1
 	spentAddressWorkerQueueSize = 1000
 	spentAddressWorkerPool      *workerpool.WorkerPool
 
 	wasSyncBefore = false
 
+	pluginBudget *budget.Budget
+
 	mqttBroker *Broker
 )
 
 // Configure the MQTT plugin
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
+	pluginBudget = budget.ForPlugin(plugin.Name)
 
 	newTxWorkerPool = workerpool.New(func(task workerpool.Task) {
-		onNewTx(task.Param(0).(*tanglePackage.CachedTransaction)) // tx pass +1
+		pluginBudget.Track(func() {
+			onNewTx(task.Param(0).(*tanglePackage.CachedTransaction)) // tx pass +1
+		})
 		task.Return(nil)
-	}, workerpool.WorkerCount(newTxWorkerCount), workerpool.QueueSize(newTxWorkerQueueSize), workerpool.FlushTasksAtShutdown(true))
+	}, workerpool.WorkerCount(pluginBudget.WorkerCount(newTxWorkerCount)), workerpool.QueueSize(pluginBudget.QueueSize(newTxWorkerQueueSize)), workerpool.FlushTasksAtShutdown(true))
 
 	confirmedTxWorkerPool = workerpool.New(func(task workerpool.Task) {
-		onConfirmedTx(task.Param(0).(*tanglePackage.CachedMetadata), task.Param(1).(milestone.Index), task.Param(2).(int64)) // meta pass +1
+		pluginBudget.Track(func() {
+			onConfirmedTx(task.Param(0).(*tanglePackage.CachedMetadata), task.Param(1).(milestone.Index), task.Param(2).(int64)) // meta pass +1
+		})
 		task.Return(nil)
-	}, workerpool.WorkerCount(confirmedTxWorkerCount), workerpool.QueueSize(confirmedTxWorkerQueueSize), workerpool.FlushTasksAtShutdown(true))
+	}, workerpool.WorkerCount(pluginBudget.WorkerCount(confirmedTxWorkerCount)), workerpool.QueueSize(pluginBudget.QueueSize(confirmedTxWorkerQueueSize)), workerpool.FlushTasksAtShutdown(true))
 
 	newLatestMilestoneWorkerPool = workerpool.New(func(task workerpool.Task) {
-		onNewLatestMilestone(task.Param(0).(*tanglePackage.CachedBundle)) // bundle pass +1
+		pluginBudget.Track(func() {
+			onNewLatestMilestone(task.Param(0).(*tanglePackage.CachedBundle)) // bundle pass +1
+		})
 		task.Return(nil)
-	}, workerpool.WorkerCount(newLatestMilestoneWorkerCount), workerpool.QueueSize(newLatestMilestoneWorkerQueueSize), workerpool.FlushTasksAtShutdown(true))
+	}, workerpool.WorkerCount(pluginBudget.WorkerCount(newLatestMilestoneWorkerCount)), workerpool.QueueSize(pluginBudget.QueueSize(newLatestMilestoneWorkerQueueSize)), workerpool.FlushTasksAtShutdown(true))
 
 	newSolidMilestoneWorkerPool = workerpool.New(func(task workerpool.Task) {
-		onNewSolidMilestone(task.Param(0).(*tanglePackage.CachedBundle)) // bundle pass +1
+		pluginBudget.Track(func() {
+			onNewSolidMilestone(task.Param(0).(*tanglePackage.CachedBundle)) // bundle pass +1
+		})
+		task.Return(nil)
+	}, workerpool.WorkerCount(pluginBudget.WorkerCount(newSolidMilestoneWorkerCount)), workerpool.QueueSize(pluginBudget.QueueSize(newSolidMilestoneWorkerQueueSize)), workerpool.FlushTasksAtShutdown(true))
+
+	milestoneConfirmedWorkerPool = workerpool.New(func(task workerpool.Task) {
+		pluginBudget.Track(func() {
+			onMilestoneConfirmed(task.Param(0).(*whiteflag.Confirmation))
+		})
+		task.Return(nil)
+	}, workerpool.WorkerCount(pluginBudget.WorkerCount(milestoneConfirmedWorkerCount)), workerpool.QueueSize(pluginBudget.QueueSize(milestoneConfirmedWorkerQueueSize)), workerpool.FlushTasksAtShutdown(true))
+
+	metadataWorkerPool = workerpool.New(func(task workerpool.Task) {
+		pluginBudget.Track(func() {
+			if err := mqttBroker.Send(task.Param(0).(string), string(task.Param(1).([]byte))); err != nil {
+				log.Warn(err.Error())
+			}
+		})
 		task.Return(nil)
-	}, workerpool.WorkerCount(newSolidMilestoneWorkerCount), workerpool.QueueSize(newSolidMilestoneWorkerQueueSize), workerpool.FlushTasksAtShutdown(true))
+	}, workerpool.WorkerCount(pluginBudget.WorkerCount(metadataWorkerCount)), workerpool.QueueSize(pluginBudget.QueueSize(metadataWorkerQueueSize)))
 
 	spentAddressWorkerPool = workerpool.New(func(task workerpool.Task) {
-		onSpentAddress(task.Param(0).(trinary.Hash))
+		pluginBudget.Track(func() {
+			onSpentAddress(task.Param(0).(trinary.Hash))
+		})
 		task.Return(nil)
-	}, workerpool.WorkerCount(spentAddressWorkerCount), workerpool.QueueSize(spentAddressWorkerQueueSize))
+	}, workerpool.WorkerCount(pluginBudget.WorkerCount(spentAddressWorkerCount)), workerpool.QueueSize(pluginBudget.QueueSize(spentAddressWorkerQueueSize)))
 
 	var err error
-	mqttBroker, err = NewBroker()
+	mqttBroker, err = NewBroker(pluginBudget)
 	if err != nil {
 		log.Fatalf("MQTT broker init failed! %v", err)
 	}
@@ -88,7 +129,7 @@ func run(plugin *node.Plugin) {
 
 	onReceivedNewTransaction := events.NewClosure(func(cachedTx *tanglePackage.CachedTransaction, latestMilestoneIndex milestone.Index, latestSolidMilestoneIndex milestone.Index) {
 		if !wasSyncBefore {
-			if !tanglePackage.IsNodeSyncedWithThreshold() {
+			if !tanglePackage.IsNodeAlmostSynced() {
 				cachedTx.Release(true) // tx -1
 				return
 			}
@@ -142,6 +183,28 @@ func run(plugin *node.Plugin) {
 		cachedBndl.Release(true) // bundle -1
 	})
 
+	onMilestoneConfirmedEvent := events.NewClosure(func(confirmation *whiteflag.Confirmation) {
+		if !wasSyncBefore {
+			// Not sync
+			return
+		}
+		milestoneConfirmedWorkerPool.TrySubmit(confirmation)
+	})
+
+	onTransactionMetadataSolid := events.NewClosure(func(metadata *hornet.TransactionMetadata) {
+		if !wasSyncBefore {
+			return
+		}
+		onTransactionMetadataChanged(metadata, false)
+	})
+
+	onTransactionMetadataConfirmed := events.NewClosure(func(metadata *hornet.TransactionMetadata) {
+		if !wasSyncBefore {
+			return
+		}
+		onTransactionMetadataChanged(metadata, true)
+	})
+
 	onAddressSpent := events.NewClosure(func(addr trinary.Hash) {
 		spentAddressWorkerPool.TrySubmit(addr)
 	})
@@ -174,12 +237,6 @@ func run(plugin *node.Plugin) {
 		}
 	}, shutdown.PriorityMetricsPublishers)
 
-	/*
-		daemon.BackgroundWorker("MQTT address topic updater", func(shutdownSignal <-chan struct{}) {
-			timeutil.Ticker(updateAddressTopics, 5*time.Second, shutdownSignal)
-		}, shutdown.PriorityMetricsPublishers)
-	*/
-
 	daemon.BackgroundWorker("MQTT[NewTxWorker]", func(shutdownSignal <-chan struct{}) {
 		log.Info("Starting MQTT[NewTxWorker] ... done")
 		tangle.Events.ReceivedNewTransaction.Attach(onReceivedNewTransaction)
@@ -220,6 +277,28 @@ func run(plugin *node.Plugin) {
 		log.Info("Stopping MQTT[NewSolidMilestoneWorker] ... done")
 	}, shutdown.PriorityMetricsPublishers)
 
+	daemon.BackgroundWorker("MQTT[MilestoneConfirmedWorker]", func(shutdownSignal <-chan struct{}) {
+		log.Info("Starting MQTT[MilestoneConfirmedWorker] ... done")
+		tangle.Events.MilestoneConfirmed.Attach(onMilestoneConfirmedEvent)
+		milestoneConfirmedWorkerPool.Start()
+		<-shutdownSignal
+		tangle.Events.MilestoneConfirmed.Detach(onMilestoneConfirmedEvent)
+		milestoneConfirmedWorkerPool.StopAndWait()
+		log.Info("Stopping MQTT[MilestoneConfirmedWorker] ... done")
+	}, shutdown.PriorityMetricsPublishers)
+
+	daemon.BackgroundWorker("MQTT[TransactionMetadataWorker]", func(shutdownSignal <-chan struct{}) {
+		log.Info("Starting MQTT[TransactionMetadataWorker] ... done")
+		tanglePackage.Events.TransactionMetadataSolid.Attach(onTransactionMetadataSolid)
+		tanglePackage.Events.TransactionMetadataConfirmed.Attach(onTransactionMetadataConfirmed)
+		metadataWorkerPool.Start()
+		<-shutdownSignal
+		tanglePackage.Events.TransactionMetadataSolid.Detach(onTransactionMetadataSolid)
+		tanglePackage.Events.TransactionMetadataConfirmed.Detach(onTransactionMetadataConfirmed)
+		metadataWorkerPool.StopAndWait()
+		log.Info("Stopping MQTT[TransactionMetadataWorker] ... done")
+	}, shutdown.PriorityMetricsPublishers)
+
 	daemon.BackgroundWorker("MQTT[SpentAddress]", func(shutdownSignal <-chan struct{}) {
 		log.Info("Starting MQTT[SpentAddress] ... done")
 		tanglePackage.Events.AddressSpent.Attach(onAddressSpent)
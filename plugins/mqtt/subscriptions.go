@@ -0,0 +1,181 @@
+package mqtt
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/fhmq/hmq/plugins/bridge"
+)
+
+const (
+	// prefix of shared subscriptions, followed by the group name and the topic
+	sharedSubscriptionPrefix = "$share/"
+)
+
+// subscriptionManager keeps track of the topics the clients of the broker are subscribed to,
+// so that topics without subscribers don't need to be published at all.
+// It is hooked into the broker as a bridge plugin, which is notified about the (un)subscriptions of the clients.
+type subscriptionManager struct {
+	// the bridge configured in the broker config, events are forwarded to it
+	bridge bridge.BridgeMQ
+
+	lock sync.RWMutex
+	// the topics each client is subscribed to
+	clientTopics map[string]map[string]struct{}
+	// the amount of clients subscribed to each topic
+	topicSubscribers map[string]int
+	// the amount of clients subscribed to each topic which contains wildcards
+	wildcardSubscribers map[string]int
+}
+
+func newSubscriptionManager(forward bridge.BridgeMQ) *subscriptionManager {
+	return &subscriptionManager{
+		bridge:              forward,
+		clientTopics:        make(map[string]map[string]struct{}),
+		topicSubscribers:    make(map[string]int),
+		wildcardSubscribers: make(map[string]int),
+	}
+}
+
+// Publish is called by the broker for every client event.
+func (s *subscriptionManager) Publish(e *bridge.Elements) error {
+	switch e.Action {
+	case bridge.Subscribe:
+		s.subscribe(e.ClientID, e.Topic)
+	case bridge.Unsubscribe:
+		s.unsubscribe(e.ClientID, e.Topic)
+	case bridge.Disconnect:
+		s.unsubscribeAll(e.ClientID)
+	}
+
+	if s.bridge == nil {
+		return nil
+	}
+	return s.bridge.Publish(e)
+}
+
+// HasSubscribers returns whether at least one client is subscribed to the given topic.
+func (s *subscriptionManager) HasSubscribers(topic string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.topicSubscribers[topic] > 0 {
+		return true
+	}
+
+	for filter := range s.wildcardSubscribers {
+		if topicMatchesFilter(topic, filter) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *subscriptionManager) subscribe(clientID string, topic string) {
+	topic = stripSharedSubscriptionPrefix(topic)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	topics, exists := s.clientTopics[clientID]
+	if !exists {
+		topics = make(map[string]struct{})
+		s.clientTopics[clientID] = topics
+	}
+
+	if _, subscribed := topics[topic]; subscribed {
+		return
+	}
+	topics[topic] = struct{}{}
+
+	if isWildcardTopic(topic) {
+		s.wildcardSubscribers[topic]++
+		return
+	}
+	s.topicSubscribers[topic]++
+}
+
+func (s *subscriptionManager) unsubscribe(clientID string, topic string) {
+	topic = stripSharedSubscriptionPrefix(topic)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	topics, exists := s.clientTopics[clientID]
+	if !exists {
+		return
+	}
+
+	if _, subscribed := topics[topic]; !subscribed {
+		return
+	}
+	delete(topics, topic)
+	if len(topics) == 0 {
+		delete(s.clientTopics, clientID)
+	}
+
+	s.removeSubscriberWithoutLocking(topic)
+}
+
+func (s *subscriptionManager) unsubscribeAll(clientID string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for topic := range s.clientTopics[clientID] {
+		s.removeSubscriberWithoutLocking(topic)
+	}
+	delete(s.clientTopics, clientID)
+}
+
+func (s *subscriptionManager) removeSubscriberWithoutLocking(topic string) {
+	subscribers := s.topicSubscribers
+	if isWildcardTopic(topic) {
+		subscribers = s.wildcardSubscribers
+	}
+
+	subscribers[topic]--
+	if subscribers[topic] <= 0 {
+		delete(subscribers, topic)
+	}
+}
+
+// stripSharedSubscriptionPrefix removes the "$share/<group>/" prefix of shared subscriptions.
+func stripSharedSubscriptionPrefix(topic string) string {
+	if !strings.HasPrefix(topic, sharedSubscriptionPrefix) {
+		return topic
+	}
+
+	parts := strings.SplitN(topic, "/", 3)
+	if len(parts) != 3 {
+		return topic
+	}
+	return parts[2]
+}
+
+func isWildcardTopic(topic string) bool {
+	return strings.ContainsAny(topic, "+#")
+}
+
+// topicMatchesFilter checks whether the topic matches the given topic filter,
+// which may contain single level (+) and multi level (#) wildcards.
+func topicMatchesFilter(topic string, filter string) bool {
+	topicLevels := strings.Split(topic, "/")
+	filterLevels := strings.Split(filter, "/")
+
+	for i, filterLevel := range filterLevels {
+		if filterLevel == "#" {
+			return true
+		}
+
+		if i >= len(topicLevels) {
+			return false
+		}
+
+		if filterLevel != "+" && filterLevel != topicLevels[i] {
+			return false
+		}
+	}
+
+	return len(topicLevels) == len(filterLevels)
+}
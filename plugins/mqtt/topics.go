@@ -1,5 +1,9 @@
 package mqtt
 
+import (
+	"strings"
+)
+
 // Topic names
 const (
 	topicLMI          = "lmi"
@@ -12,59 +16,38 @@ const (
 	topicTxTrytes     = "trytes"
 	topicTX           = "tx"
 	topicSpentAddress = "spent_address"
-	//topicPrefixAddress = "addr/"
-)
 
-/*
-var (
-	addressTopics AddressTopics
+	topicMilestonesLatest    = "milestones/latest"
+	topicMilestonesConfirmed = "milestones/confirmed"
+
+	// the following topics are only published if a client is subscribed to them
+	topicTransactionsTag           = "transactions/tag/{tag}"
+	topicTransactionMetadata       = "transactions/{txHash}/metadata"
+	topicTransactionInclusionState = "transactions/{txHash}/inclusionState"
+	topicAddress                   = "addresses/{address}"
+	topicWatch                     = "watch/{clientID}"
+
+	// clients publish to the following topics to (un)watch sets of addresses
+	topicWatchRegister   = "watch/register"
+	topicWatchUnregister = "watch/unregister"
 )
 
-// AddressTopics stuct
-type AddressTopics struct {
-	mu         sync.Mutex
-	Addressses []string
+func topicForTag(tag string) string {
+	return strings.Replace(topicTransactionsTag, "{tag}", tag, 1)
 }
 
-// GetAddressTopics returns subscribed addresses
-func GetAddressTopics() []string {
-	addressTopics.mu.Lock()
-	defer addressTopics.mu.Unlock()
-	return addressTopics.Addressses
+func topicForTransactionMetadata(txHash string) string {
+	return strings.Replace(topicTransactionMetadata, "{txHash}", txHash, 1)
 }
 
-// updateAddressTopics filters Subscribers for address topics
-func updateAddressTopics() {
-
-	addrTopic := []string{}
-	if mqttBroker != nil {
-
-		var subs []interface{}
-		var qoss []byte
-
-		err := mqttBroker.Subscribers([]byte(topicPrefixAddress), packet.Qos, &subs, &qoss)
-		if err != nil {
-			log.Errorf("search sub client error, %v", err)
-		}
-
-		for _, sub := range subs {
-			if s, ok := sub.(*subscription); ok {
-				err := address.ValidAddress(s)
-				if err == nil {
-					if len(s) == 90 {
-						addrTopic = append(addrTopic, s[:81])
-					} else if len(s) == 81 {
-						addrTopic = append(addrTopic, s)
-					}
-				}
-			}
-		}
-	}
+func topicForTransactionInclusionState(txHash string) string {
+	return strings.Replace(topicTransactionInclusionState, "{txHash}", txHash, 1)
+}
 
-	sort.Strings(addrTopic)
+func topicForWatch(clientID string) string {
+	return strings.Replace(topicWatch, "{clientID}", clientID, 1)
+}
 
-	addressTopics.mu.Lock()
-	addressTopics.Addressses = addrTopic
-	addressTopics.mu.Unlock()
+func topicForAddress(address string) string {
+	return strings.Replace(topicAddress, "{address}", address, 1)
 }
-*/
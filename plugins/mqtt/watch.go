@@ -0,0 +1,202 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fhmq/hmq/broker"
+	"github.com/fhmq/hmq/plugins/auth"
+	"github.com/fhmq/hmq/plugins/bridge"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/watch"
+)
+
+// watchRequest is the payload clients publish to the watch topics.
+type watchRequest struct {
+	Addresses []trinary.Hash `json:"addresses"`
+}
+
+// watchedAddressPayload is an element of the payload of the watch topic of a client.
+type watchedAddressPayload struct {
+	Address     trinary.Hash    `json:"address"`
+	Balance     uint64          `json:"balance"`
+	Delta       int64           `json:"delta"`
+	LedgerIndex milestone.Index `json:"ledgerIndex"`
+}
+
+// watchManager lets the clients of the broker register sets of addresses by publishing to the watch topics.
+// The changes of the watched addresses are published to the watch topic of the client.
+// It is hooked into the broker as a bridge plugin, which is notified about the publications of the clients.
+type watchManager struct {
+	// the bridge events are forwarded to
+	bridge bridge.BridgeMQ
+
+	registry *watch.Registry
+}
+
+func newWatchManager(forward bridge.BridgeMQ, maxAddresses int) *watchManager {
+	return &watchManager{
+		bridge:   forward,
+		registry: watch.NewRegistry(maxAddresses),
+	}
+}
+
+// Publish is called by the broker for every client event.
+func (w *watchManager) Publish(e *bridge.Elements) error {
+	switch e.Action {
+	case bridge.Publish:
+		switch e.Topic {
+		case topicWatchRegister:
+			w.watch(e.ClientID, e.Payload)
+		case topicWatchUnregister:
+			w.unwatch(e.ClientID, e.Payload)
+		}
+	case bridge.Disconnect:
+		w.registry.Unregister(e.ClientID)
+	}
+
+	if w.bridge == nil {
+		return nil
+	}
+	return w.bridge.Publish(e)
+}
+
+func (w *watchManager) watch(clientID string, payload string) {
+	if !isValidWatchClientID(clientID) {
+		log.Debugf("MQTT client %s can't watch addresses: the client ID can't be used in its watch topic", clientID)
+		return
+	}
+
+	addresses, err := parseWatchRequest(payload)
+	if err != nil {
+		log.Debugf("MQTT client %s sent an invalid watch request: %s", clientID, err)
+		return
+	}
+
+	if !w.registry.IsRegistered(clientID) {
+		topic := topicForWatch(clientID)
+		w.registry.Register(clientID, func(changes []*watch.AddressChange) {
+			if err := publishWatchedAddresses(topic, changes); err != nil {
+				log.Warn(err.Error())
+			}
+		})
+	}
+
+	if err := w.registry.Watch(clientID, addresses...); err != nil {
+		log.Debugf("MQTT client %s can't watch addresses: %s", clientID, err)
+	}
+}
+
+// unwatch removes the given addresses from the watched addresses of the client, or all of them if none are given.
+func (w *watchManager) unwatch(clientID string, payload string) {
+	addresses, err := parseWatchRequest(payload)
+	if err != nil {
+		log.Debugf("MQTT client %s sent an invalid watch request: %s", clientID, err)
+		return
+	}
+
+	if len(addresses) == 0 {
+		w.registry.Unregister(clientID)
+		return
+	}
+	w.registry.Unwatch(clientID, addresses...)
+}
+
+// isValidWatchClientID checks whether the client ID can be used as the last level of a watch topic.
+// The IDs of the register topics are reserved, and IDs with topic separators or wildcards
+// would lead to watch topics which overlap with the ones of other clients.
+func isValidWatchClientID(clientID string) bool {
+	if clientID == "" || strings.ContainsAny(clientID, "/+#") {
+		return false
+	}
+	return topicForWatch(clientID) != topicWatchRegister && topicForWatch(clientID) != topicWatchUnregister
+}
+
+// isWatchSubscriptionAllowed checks whether the client is allowed to subscribe to the given topic filter.
+// A client may only receive the changes of its own watched addresses, so filters which match
+// the watch topic of another client or the register topics all clients publish to are rejected.
+func isWatchSubscriptionAllowed(clientID string, filter string) bool {
+	if topicMatchesFilter(topicWatchRegister, filter) || topicMatchesFilter(topicWatchUnregister, filter) {
+		return false
+	}
+
+	// a wildcard at the level of the client ID matches the watch topics of all clients
+	otherClientID := "+"
+	if levels := strings.Split(filter, "/"); len(levels) > 1 && !isWildcardTopic(levels[1]) {
+		otherClientID = levels[1]
+	}
+
+	return otherClientID == clientID || !topicMatchesFilter(topicForWatch(otherClientID), filter)
+}
+
+// watchAuth wraps the authentication of the broker, so that clients can't subscribe to the watch topics of other clients.
+type watchAuth struct {
+	// the authentication configured in the broker config, nil if there is none
+	auth auth.Auth
+}
+
+func newWatchAuth(forward auth.Auth) *watchAuth {
+	return &watchAuth{auth: forward}
+}
+
+// CheckACL is called by the broker for every publication and subscription of a client.
+func (a *watchAuth) CheckACL(action, clientID, username, ip, topic string) bool {
+	if action == broker.SUB && !isWatchSubscriptionAllowed(clientID, topic) {
+		log.Debugf("MQTT client %s is not allowed to subscribe to %s", clientID, topic)
+		return false
+	}
+
+	if a.auth == nil {
+		return true
+	}
+	return a.auth.CheckACL(action, clientID, username, ip, topic)
+}
+
+// CheckConnect is called by the broker for every connecting client.
+func (a *watchAuth) CheckConnect(clientID, username, password string) bool {
+	if a.auth == nil {
+		return true
+	}
+	return a.auth.CheckConnect(clientID, username, password)
+}
+
+func parseWatchRequest(payload string) (hornet.Hashes, error) {
+	request := &watchRequest{}
+	if payload != "" {
+		if err := json.Unmarshal([]byte(payload), request); err != nil {
+			return nil, err
+		}
+	}
+
+	addresses := make(hornet.Hashes, len(request.Addresses))
+	for i, addr := range request.Addresses {
+		addrHash, err := hornet.AddressFromTrytes(addr)
+		if err != nil {
+			return nil, err
+		}
+		addresses[i] = addrHash
+	}
+	return addresses, nil
+}
+
+// Publish the changes of the watched addresses of a client, if it is subscribed to its watch topic
+func publishWatchedAddresses(topic string, changes []*watch.AddressChange) error {
+	if !mqttBroker.HasSubscribers(topic) {
+		return nil
+	}
+
+	payload := make([]*watchedAddressPayload, len(changes))
+	for i, change := range changes {
+		payload[i] = &watchedAddressPayload{
+			Address:     change.Address.Trytes(),
+			Balance:     change.Balance,
+			Delta:       change.Delta,
+			LedgerIndex: change.MilestoneIndex,
+		}
+	}
+
+	return publishJSON(topic, payload)
+}
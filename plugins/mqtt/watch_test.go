@@ -0,0 +1,49 @@
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidWatchClientID(t *testing.T) {
+	assert.True(t, isValidWatchClientID("client1"))
+
+	for _, clientID := range []string{"", "register", "unregister", "a/b", "+", "client#"} {
+		assert.False(t, isValidWatchClientID(clientID), "client ID %q", clientID)
+	}
+}
+
+func TestIsWatchSubscriptionAllowed(t *testing.T) {
+	allowed := []string{
+		"watch/client1",
+		"watch",
+		"lmi",
+		"transactions/tag/+",
+		"addresses/+",
+		"milestones/#",
+	}
+	for _, filter := range allowed {
+		assert.True(t, isWatchSubscriptionAllowed("client1", filter), "filter %s", filter)
+	}
+
+	rejected := []string{
+		// the watch topics of other clients
+		"watch/client2",
+		"+/client2",
+		"watch/+",
+		"watch/#",
+		"+/+",
+		"#",
+		"watch/client2/#",
+		// the register topics all clients publish their addresses to
+		"watch/register",
+		"watch/unregister",
+	}
+	for _, filter := range rejected {
+		assert.False(t, isWatchSubscriptionAllowed("client1", filter), "filter %s", filter)
+	}
+
+	// a client with a reserved ID can't subscribe to the register topics as its own watch topic
+	assert.False(t, isWatchSubscriptionAllowed("register", "watch/register"))
+}
@@ -0,0 +1,43 @@
+package peering
+
+import (
+	"fmt"
+
+	"github.com/mr-tron/base58/base58"
+
+	"github.com/iotaledger/hive.go/crypto/ed25519"
+
+	"github.com/gohornet/hornet/pkg/config"
+)
+
+// loads the identity which is used to encrypt the connections to peers with a configured public key.
+// if no encryption seed is configured, the autopeering seed is used, so that autopeered neighbors
+// can authenticate the node with its autopeering identity. without any seed, an ephemeral identity
+// is created, whose public key changes with every restart of the node.
+func loadIdentity() (identity *ed25519.PrivateKey, ephemeral bool, err error) {
+	seedKey := config.CfgNetGossipEncryptionSeed
+	seedStr := config.NodeConfig.GetString(config.CfgNetGossipEncryptionSeed)
+	if seedStr == "" {
+		seedKey = config.CfgNetAutopeeringSeed
+		seedStr = config.NodeConfig.GetString(config.CfgNetAutopeeringSeed)
+	}
+
+	if seedStr == "" {
+		privateKey, err := ed25519.GeneratePrivateKey()
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to create an identity: %w", err)
+		}
+		return &privateKey, true, nil
+	}
+
+	seed, err := base58.Decode(seedStr)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid %s: %w", seedKey, err)
+	}
+	if l := len(seed); l != ed25519.SeedSize {
+		return nil, false, fmt.Errorf("invalid %s length: %d, need %d", seedKey, l, ed25519.SeedSize)
+	}
+
+	privateKey := ed25519.PrivateKeyFromSeed(seed)
+	return &privateKey, false, nil
+}
@@ -0,0 +1,96 @@
+package peering
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/peering"
+	"github.com/gohornet/hornet/pkg/peering/peer"
+)
+
+var (
+	// ErrPeerNotFound is returned if a peer is neither connected nor in the reconnect pool.
+	ErrPeerNotFound = errors.New("peer not found")
+
+	// used to serialize the modifications of the persisted peers.
+	peersConfigLock sync.Mutex
+)
+
+// PeerInfo returns the info of the connected or in the reconnect pool residing peer with the given ID.
+// The ID is either the IP/port combination or the address the peer was added with.
+func PeerInfo(id string) (*peer.Info, error) {
+	for _, info := range Manager().PeerInfos() {
+		if strings.EqualFold(info.Address, id) || strings.EqualFold(info.DomainWithPort, id) {
+			return info, nil
+		}
+	}
+	return nil, errors.Wrapf(ErrPeerNotFound, "peer %s", id)
+}
+
+// AddPeer adds a static peer to the manager and persists it in the peering config,
+// so that the peer is also known after a restart of the node.
+// If a public key is given, the connection to the peer is encrypted.
+func AddPeer(addr string, alias string, preferIPv6 bool, publicKey string) error {
+	if err := Manager().Add(addr, preferIPv6, alias, publicKey); err != nil {
+		if !errors.Is(err, peering.ErrPeerAlreadyConnected) && !errors.Is(err, peering.ErrPeerAlreadyInReconnect) {
+			return err
+		}
+	}
+
+	return modifyPersistedPeers(func(peers []config.PeerConfig) ([]config.PeerConfig, bool) {
+		for _, p := range peers {
+			if strings.EqualFold(p.ID, addr) {
+				return peers, false
+			}
+		}
+		return append(peers, config.PeerConfig{
+			ID:         addr,
+			Alias:      alias,
+			PreferIPv6: preferIPv6,
+			PublicKey:  publicKey,
+		}), true
+	})
+}
+
+// RemovePeer disconnects the peer with the given ID and removes it from the peering config.
+func RemovePeer(id string) error {
+	if err := Manager().Remove(id); err != nil {
+		return err
+	}
+
+	return modifyPersistedPeers(func(peers []config.PeerConfig) ([]config.PeerConfig, bool) {
+		for i, p := range peers {
+			if strings.EqualFold(p.ID, id) {
+				return append(peers[:i], peers[i+1:]...), true
+			}
+		}
+		return peers, false
+	})
+}
+
+// modifyPersistedPeers applies the given function to the peers in the peering config
+// and writes the config if the function modified them.
+func modifyPersistedPeers(modify func(peers []config.PeerConfig) ([]config.PeerConfig, bool)) error {
+	peersConfigLock.Lock()
+	defer peersConfigLock.Unlock()
+
+	var peers []config.PeerConfig
+	if err := config.PeeringConfig.UnmarshalKey(config.CfgPeers, &peers); err != nil {
+		return err
+	}
+
+	peers, modified := modify(peers)
+	if !modified {
+		return nil
+	}
+
+	// the write must not trigger the config watcher, since the manager is already up to date
+	config.DenyPeeringConfigHotReload()
+	defer config.AllowPeeringConfigHotReload()
+
+	config.PeeringConfig.Set(config.CfgPeers, peers)
+	return config.PeeringConfig.WriteConfig()
+}
@@ -3,7 +3,6 @@ package peering
 import (
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/iotaledger/hive.go/daemon"
@@ -13,8 +12,11 @@ import (
 	"github.com/iotaledger/hive.go/node"
 	"github.com/iotaledger/hive.go/timeutil"
 
+	"github.com/gohornet/hornet/pkg/app"
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/networkid"
 	"github.com/gohornet/hornet/pkg/peering"
 	"github.com/gohornet/hornet/pkg/peering/peer"
 	"github.com/gohornet/hornet/pkg/protocol"
@@ -27,61 +29,88 @@ const (
 )
 
 var (
-	PLUGIN      = node.NewPlugin("Peering", node.Enabled, configure, run)
-	log         *logger.Logger
-	manager     *peering.Manager
-	managerOnce sync.Once
+	PLUGIN  = node.NewPlugin("Peering", node.Enabled, configure, run)
+	log     *logger.Logger
+	manager *peering.Manager
+	// whether the identity of the node changes with every restart.
+	ephemeralIdentity bool
 )
 
+func init() {
+	app.Provide(PLUGIN, newManager)
+	app.Inject(PLUGIN, func(m *peering.Manager) {
+		manager = m
+	})
+}
+
 // Manager gets the peering Manager instance the peering plugin uses.
+// Plugins should declare the *peering.Manager as a dependency instead.
 func Manager() *peering.Manager {
-	managerOnce.Do(func() {
-		// init protocol package with handshake data
-		cooAddrBytes := hornet.HashFromAddressTrytes(config.NodeConfig.GetString(config.CfgCoordinatorAddress))
-		mwm := config.NodeConfig.GetInt(config.CfgCoordinatorMWM)
-		bindAddr := config.NodeConfig.GetString(config.CfgNetGossipBindAddress)
-		if err := protocol.Init(cooAddrBytes, mwm, bindAddr); err != nil {
-			log.Fatalf("couldn't initialize protocol: %s", err)
-		}
+	return manager
+}
 
-		// load initial config peers
-		var peers []*config.PeerConfig
-		if err := config.PeeringConfig.UnmarshalKey(config.CfgPeers, &peers); err != nil {
-			panic(err)
-		}
+// creates the peering manager out of the node config.
+func newManager() (*peering.Manager, error) {
+	// init protocol package with handshake data
+	cooAddrBytes := hornet.HashFromAddressTrytes(config.NodeConfig.GetString(config.CfgCoordinatorAddress))
+	mwm := config.NodeConfig.GetInt(config.CfgCoordinatorMWM)
+	networkID := networkid.FromConfig()
+	bindAddr := config.NodeConfig.GetString(config.CfgNetGossipBindAddress)
+	if err := protocol.Init(cooAddrBytes, mwm, networkID, bindAddr); err != nil {
+		return nil, fmt.Errorf("couldn't initialize protocol: %w", err)
+	}
 
-		for i, p := range peers {
-			if p.ID == ExamplePeerURI {
-				peers[i] = peers[len(peers)-1]
-				peers[len(peers)-1] = nil
-				peers = peers[:len(peers)-1]
-				break
-			}
-		}
+	// load initial config peers
+	var peers []*config.PeerConfig
+	if err := config.PeeringConfig.UnmarshalKey(config.CfgPeers, &peers); err != nil {
+		return nil, err
+	}
 
-		for _, p := range config.NodeConfig.GetStringSlice(config.CfgPeersList) {
-			peers = append(peers, &config.PeerConfig{ID: p})
+	for i, p := range peers {
+		if p.ID == ExamplePeerURI {
+			peers[i] = peers[len(peers)-1]
+			peers[len(peers)-1] = nil
+			peers = peers[:len(peers)-1]
+			break
 		}
+	}
 
-		// init peer manager
-		manager = peering.NewManager(peering.Options{
-			BindAddress: config.NodeConfig.GetString(config.CfgNetGossipBindAddress),
-			ValidHandshake: handshake.Handshake{
-				ByteEncodedCooAddress: cooAddrBytes,
-				MWM:                   byte(mwm),
-			},
-			MaxConnected:  config.PeeringConfig.GetInt(config.CfgPeeringMaxPeers),
-			AcceptAnyPeer: config.PeeringConfig.GetBool(config.CfgPeeringAcceptAnyConnection),
-		}, peers...)
-	})
-	return manager
+	for _, p := range config.NodeConfig.GetStringSlice(config.CfgPeersList) {
+		peers = append(peers, &config.PeerConfig{ID: p})
+	}
+
+	identity, ephemeral, err := loadIdentity()
+	if err != nil {
+		return nil, err
+	}
+	ephemeralIdentity = ephemeral
+
+	// init peer manager
+	return peering.NewManager(peering.Options{
+		BindAddress: config.NodeConfig.GetString(config.CfgNetGossipBindAddress),
+		ValidHandshake: handshake.Handshake{
+			ByteEncodedCooAddress: cooAddrBytes,
+			MWM:                   byte(mwm),
+			NetworkID:             networkID,
+		},
+		MaxConnected:        config.PeeringConfig.GetInt(config.CfgPeeringMaxPeers),
+		AcceptAnyPeer:       config.PeeringConfig.GetBool(config.CfgPeeringAcceptAnyConnection),
+		ReconnectBackoff:    time.Duration(config.NodeConfig.GetInt(config.CfgNetGossipReconnectAttemptIntervalSeconds)) * time.Second,
+		MaxReconnectBackoff: time.Duration(config.NodeConfig.GetInt(config.CfgNetGossipMaxReconnectBackoffSeconds)) * time.Second,
+		MaxMisbehaviorScore: config.NodeConfig.GetInt(config.CfgNetGossipMaxMisbehaviorScore),
+		BanDuration:         time.Duration(config.NodeConfig.GetInt(config.CfgNetGossipBanDurationSeconds)) * time.Second,
+		Identity:            identity,
+		EncryptAutopeering:  config.NodeConfig.GetBool(config.CfgNetGossipEncryptionAutopeering),
+	}, peers...)
 }
 
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
-	// init peering manager
-	Manager()
+	if ephemeralIdentity {
+		log.Warnf("no %s configured, peers can't authenticate the node across restarts", config.CfgNetGossipEncryptionSeed)
+	}
+	log.Infof("public key for encrypted connections: %s", manager.Opts.Identity.Public())
 
 	// register log event handlers
 	configureManagerEventHandlers()
@@ -109,7 +138,11 @@ func configureManagerEventHandlers() {
 		if len(p.InitAddress.Alias) > 0 {
 			aliasMeta = fmt.Sprintf(" [alias: %s]", p.InitAddress.Alias)
 		}
-		log.Infof("connected with %s%s%s%s", p.ID, aliasMeta, featureSetMeta, autopeeringMeta)
+		var encryptionMeta string
+		if p.PublicKey != nil {
+			encryptionMeta = fmt.Sprintf(" [encrypted: %s]", p.PublicKey)
+		}
+		log.Infof("connected with %s%s%s%s%s", p.ID, aliasMeta, featureSetMeta, autopeeringMeta, encryptionMeta)
 	}))
 
 	manager.Events.PeerMovedIntoReconnectPool.Attach(events.NewClosure(func(addr *iputils.OriginAddress) {
@@ -128,6 +161,10 @@ func configureManagerEventHandlers() {
 		log.Infof("handshaking with autopeered peer %s / %s", p.ID, p.Autopeering.ID())
 	}))
 
+	manager.Events.PeerBanned.Attach(events.NewClosure(func(p *peer.Peer) {
+		log.Warnf("banned %s for %v because of misbehavior", p.ID, manager.Opts.BanDuration)
+	}))
+
 	manager.Events.Reconnecting.Attach(events.NewClosure(func(count int32) {
 		log.Infof("trying to connect to %d peers", count)
 	}))
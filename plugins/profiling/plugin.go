@@ -2,19 +2,41 @@ package profiling
 
 import (
 	"net/http"
-	_ "net/http/pprof"
+	"net/http/pprof"
 	"runtime"
 
-	"github.com/gohornet/hornet/pkg/config"
+	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
+
+	"github.com/gohornet/hornet/pkg/basicauth"
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/jwt"
+	"github.com/gohornet/hornet/pkg/logging"
 )
 
 var (
 	PLUGIN = node.NewPlugin("Profiling", node.Enabled, configure, run)
+	log    *logger.Logger
+
+	// the handler of the profiler, guarded by the configured authentication.
+	handler http.Handler
 )
 
-func configure(_ *node.Plugin) {
-	// nothing
+func configure(plugin *node.Plugin) {
+	log = logging.NewLogger(plugin.Name)
+
+	configureRuntime()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/runtime", handleRuntime)
+	mux.HandleFunc("/debug/runtime/gc", handleGC)
+
+	handler = authHandler(mux)
 }
 
 func run(_ *node.Plugin) {
@@ -22,5 +44,53 @@ func run(_ *node.Plugin) {
 	runtime.SetBlockProfileRate(5)
 
 	bindAddr := config.NodeConfig.GetString(config.CfgProfilingBindAddress)
-	go http.ListenAndServe(bindAddr, nil) // pprof Server for Debbuging Mutexes
+	go func() {
+		if err := http.ListenAndServe(bindAddr, handler); err != nil {
+			log.Warnf("profiler stopped: %s", err)
+		}
+	}()
+}
+
+// authHandler wraps the given handler with the HTTP basic auth of the profiler if it is enabled.
+// a valid JWT of the HTTP API replaces the basic auth credentials if enabled.
+func authHandler(next http.Handler) http.Handler {
+	if !config.NodeConfig.GetBool(config.CfgProfilingBasicAuthEnabled) {
+		return next
+	}
+
+	// grab auth info
+	expectedUsername := config.NodeConfig.GetString(config.CfgProfilingBasicAuthUsername)
+	expectedPasswordHash := config.NodeConfig.GetString(config.CfgProfilingBasicAuthPasswordHash)
+	passwordSalt := config.NodeConfig.GetString(config.CfgProfilingBasicAuthPasswordSalt)
+
+	if len(expectedUsername) == 0 {
+		log.Fatalf("'%s' must not be empty if profiling basic auth is enabled", config.CfgProfilingBasicAuthUsername)
+	}
+
+	if len(expectedPasswordHash) != 64 {
+		log.Fatalf("'%s' must be 64 (sha256 hash) in length if profiling basic auth is enabled", config.CfgProfilingBasicAuthPasswordHash)
+	}
+
+	var jwtAuth *jwt.JWTAuth
+	if config.NodeConfig.GetBool(config.CfgProfilingJWTAuthEnabled) {
+		var err error
+		if jwtAuth, err = jwt.NewAPIAuthFromConfig(); err != nil {
+			log.Fatalf("profiling JWT authentication is enabled, but unable to create the JWTs: %s", err)
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if jwtAuth != nil && jwtAuth.VerifyAuthorizationHeader(r.Header.Get("Authorization")) == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		username, password, ok := r.BasicAuth()
+		if !ok || username != expectedUsername || !basicauth.VerifyPassword(password, passwordSalt, expectedPasswordHash) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
 }
@@ -0,0 +1,143 @@
+package profiling
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+var (
+	// the current garbage collection target percentage.
+	// the runtime only reveals it while setting a new one, therefore it is tracked here.
+	gcPercent     int
+	gcPercentLock sync.Mutex
+)
+
+// runtimeSettings are the runtime knobs which can be tuned via the profiler.
+type runtimeSettings struct {
+	// the garbage collection target percentage (a negative value disables the garbage collection).
+	GCPercent *int `json:"gcPercent,omitempty"`
+	// the maximum number of CPUs executing simultaneously.
+	MaxProcs *int `json:"maxProcs,omitempty"`
+}
+
+// runtimeStatus is the response of the runtime endpoint.
+type runtimeStatus struct {
+	GCPercent    int `json:"gcPercent"`
+	MaxProcs     int `json:"maxProcs"`
+	NumCPU       int `json:"numCPU"`
+	NumGoroutine int `json:"numGoroutine"`
+}
+
+func configureRuntime() {
+	gcPercentLock.Lock()
+	defer gcPercentLock.Unlock()
+
+	gcPercent = debug.SetGCPercent(-1)
+	debug.SetGCPercent(gcPercent)
+}
+
+func currentRuntimeStatus() *runtimeStatus {
+	gcPercentLock.Lock()
+	defer gcPercentLock.Unlock()
+
+	return &runtimeStatus{
+		GCPercent:    gcPercent,
+		MaxProcs:     runtime.GOMAXPROCS(0),
+		NumCPU:       runtime.NumCPU(),
+		NumGoroutine: runtime.NumGoroutine(),
+	}
+}
+
+func applyRuntimeSettings(settings *runtimeSettings) error {
+	if settings.MaxProcs != nil && *settings.MaxProcs < 1 {
+		return fmt.Errorf("invalid maxProcs: %d, must be at least 1", *settings.MaxProcs)
+	}
+
+	if settings.GCPercent != nil {
+		gcPercentLock.Lock()
+		debug.SetGCPercent(*settings.GCPercent)
+		gcPercent = *settings.GCPercent
+		gcPercentLock.Unlock()
+		log.Infof("garbage collection target percentage set to %d", *settings.GCPercent)
+	}
+
+	if settings.MaxProcs != nil {
+		runtime.GOMAXPROCS(*settings.MaxProcs)
+		log.Infof("maximum number of CPUs set to %d", *settings.MaxProcs)
+	}
+
+	return nil
+}
+
+// verifyChangeRequest rejects requests which change the runtime and could have been sent by a browser on behalf of a foreign website.
+// requests with a JSON body can't be sent cross-origin without a CORS preflight, which the profiler never allows,
+// and the origin of requests which are sent by a browser must match the profiler itself.
+func verifyChangeRequest(r *http.Request) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return errors.New("content type must be application/json")
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" {
+		originURL, err := url.Parse(origin)
+		if err != nil || originURL.Host != r.Host {
+			return fmt.Errorf("foreign origin: %s", origin)
+		}
+	}
+
+	return nil
+}
+
+// handleRuntime shows the runtime knobs on GET and changes them on POST/PUT.
+func handleRuntime(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodPut:
+		if err := verifyChangeRequest(r); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		settings := &runtimeSettings{}
+		if err := json.NewDecoder(r.Body).Decode(settings); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+			return
+		}
+		if err := applyRuntimeSettings(settings); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(currentRuntimeStatus())
+}
+
+// handleGC forces a garbage collection and returns as much memory to the operating system as possible,
+// e.g. before taking a heap profile.
+func handleGC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := verifyChangeRequest(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	debug.FreeOSMemory()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(currentRuntimeStatus())
+}
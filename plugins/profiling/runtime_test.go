@@ -0,0 +1,35 @@
+package profiling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyChangeRequest(t *testing.T) {
+	newRequest := func(contentType string, origin string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost:6060/debug/runtime", strings.NewReader(`{}`))
+		if contentType != "" {
+			r.Header.Set("Content-Type", contentType)
+		}
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		return r
+	}
+
+	assert.NoError(t, verifyChangeRequest(newRequest("application/json", "")))
+	assert.NoError(t, verifyChangeRequest(newRequest("application/json; charset=utf-8", "http://localhost:6060")))
+
+	// simple requests which browsers send cross-origin without a preflight
+	assert.Error(t, verifyChangeRequest(newRequest("", "")))
+	assert.Error(t, verifyChangeRequest(newRequest("text/plain", "")))
+	assert.Error(t, verifyChangeRequest(newRequest("application/x-www-form-urlencoded", "")))
+
+	// foreign origins
+	assert.Error(t, verifyChangeRequest(newRequest("application/json", "http://evil.example")))
+	assert.Error(t, verifyChangeRequest(newRequest("application/json", "null")))
+}
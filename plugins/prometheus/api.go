@@ -0,0 +1,74 @@
+package prometheus
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/gohornet/hornet/pkg/metrics"
+)
+
+var (
+	apiRequests         *prometheus.GaugeVec
+	apiRequestDurations *prometheus.GaugeVec
+	apiRequestBuckets   *prometheus.GaugeVec
+)
+
+func init() {
+	apiRequests = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_api_requests",
+			Help: "Number of requests of the API routes per response status code.",
+		},
+		[]string{"route", "status"},
+	)
+	apiRequestDurations = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_api_request_duration_seconds",
+			Help: "Durations of the requests of the API routes over the last minutes.",
+		},
+		[]string{"route", "stat"},
+	)
+	apiRequestBuckets = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_api_request_duration_buckets",
+			Help: "Number of requests of the API routes over the last minutes per duration bucket (upper bound in seconds, +Inf for the overflow bucket).",
+		},
+		[]string{"route", "le"},
+	)
+
+	registry.MustRegister(apiRequests)
+	registry.MustRegister(apiRequestDurations)
+	registry.MustRegister(apiRequestBuckets)
+
+	AddCollect(collectAPIRequests)
+}
+
+func collectAPIRequests() {
+	apiRequestDurations.Reset()
+	apiRequestBuckets.Reset()
+
+	for _, routeMetrics := range metrics.SharedAPIMetrics.Snapshot() {
+		for statusCode, count := range routeMetrics.StatusCodes {
+			apiRequests.WithLabelValues(routeMetrics.Route, strconv.Itoa(statusCode)).Set(float64(count))
+		}
+
+		if routeMetrics.Durations.Count == 0 {
+			continue
+		}
+
+		apiRequestDurations.WithLabelValues(routeMetrics.Route, "mean").Set(routeMetrics.Durations.Mean().Seconds())
+		apiRequestDurations.WithLabelValues(routeMetrics.Route, "median").Set(routeMetrics.Durations.Quantile(0.5).Seconds())
+		apiRequestDurations.WithLabelValues(routeMetrics.Route, "p95").Set(routeMetrics.Durations.Quantile(0.95).Seconds())
+		apiRequestDurations.WithLabelValues(routeMetrics.Route, "p99").Set(routeMetrics.Durations.Quantile(0.99).Seconds())
+		apiRequestDurations.WithLabelValues(routeMetrics.Route, "count").Set(float64(routeMetrics.Durations.Count))
+
+		for _, bucket := range routeMetrics.Durations.Buckets {
+			upperBound := "+Inf"
+			if bucket.UpperBound != 0 {
+				upperBound = strconv.FormatFloat(bucket.UpperBound.Seconds(), 'f', -1, 64)
+			}
+			apiRequestBuckets.WithLabelValues(routeMetrics.Route, upperBound).Set(float64(bucket.Count))
+		}
+	}
+}
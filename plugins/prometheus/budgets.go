@@ -0,0 +1,64 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/gohornet/hornet/pkg/budget"
+)
+
+var (
+	budgetUsage    *prometheus.GaugeVec
+	budgetLimit    *prometheus.GaugeVec
+	budgetExceeded *prometheus.GaugeVec
+)
+
+func init() {
+	budgetUsage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_budget_usage",
+			Help: "Current resource usage of a plugin.",
+		},
+		[]string{"plugin", "resource"},
+	)
+	budgetLimit = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_budget_limit",
+			Help: "Resource limit of a plugin (0 = unlimited).",
+		},
+		[]string{"plugin", "resource"},
+	)
+	budgetExceeded = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_budget_exceeded",
+			Help: "Number of times a plugin exceeded its budget.",
+		},
+		[]string{"plugin"},
+	)
+
+	registry.MustRegister(budgetUsage)
+	registry.MustRegister(budgetLimit)
+	registry.MustRegister(budgetExceeded)
+
+	AddCollect(collectBudgets)
+}
+
+func collectBudgets() {
+	budgetUsage.Reset()
+	budgetLimit.Reset()
+	budgetExceeded.Reset()
+
+	budget.ForEach(func(b *budget.Budget) {
+		opts := b.Opts()
+
+		budgetUsage.WithLabelValues(b.Name(), budget.ResourceGoroutines).Set(float64(b.Goroutines()))
+		budgetUsage.WithLabelValues(b.Name(), budget.ResourceMemory).Set(float64(b.Memory()))
+		budgetUsage.WithLabelValues(b.Name(), budget.ResourceCPU).Set(b.CPUUsage())
+
+		budgetLimit.WithLabelValues(b.Name(), budget.ResourceWorkers).Set(float64(opts.MaxWorkers))
+		budgetLimit.WithLabelValues(b.Name(), budget.ResourceGoroutines).Set(float64(opts.MaxGoroutines))
+		budgetLimit.WithLabelValues(b.Name(), budget.ResourceMemory).Set(float64(opts.MaxMemoryBytes))
+		budgetLimit.WithLabelValues(b.Name(), budget.ResourceCPU).Set(opts.MaxCPUUsage)
+
+		budgetExceeded.WithLabelValues(b.Name()).Set(float64(b.ExceededCount()))
+	})
+}
@@ -0,0 +1,49 @@
+package prometheus
+
+import (
+	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/plugins/gossip"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	cacheSizes                     *prometheus.GaugeVec
+	rootSnapshotIndexesCacheHits   prometheus.Gauge
+	rootSnapshotIndexesCacheMisses prometheus.Gauge
+)
+
+func init() {
+	cacheSizes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_cache_sizes",
+			Help: "Number of objects in the caches of the object storages.",
+		},
+		[]string{"name"},
+	)
+	rootSnapshotIndexesCacheHits = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_cache_root_snapshot_indexes_hits",
+		Help: "Number of root snapshot index lookups which were answered by the cache.",
+	})
+	rootSnapshotIndexesCacheMisses = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_cache_root_snapshot_indexes_misses",
+		Help: "Number of root snapshot index lookups which were not answered by the cache.",
+	})
+
+	registry.MustRegister(cacheSizes)
+	registry.MustRegister(rootSnapshotIndexesCacheHits)
+	registry.MustRegister(rootSnapshotIndexesCacheMisses)
+
+	AddCollect(collectCaches)
+}
+
+func collectCaches() {
+	for _, cache := range tangle.GetCacheStatistics() {
+		cacheSizes.WithLabelValues(cache.Name).Set(float64(cache.Size))
+	}
+	cacheSizes.WithLabelValues("incoming_transaction_work_units").Set(float64(gossip.Processor().WorkUnitsSize()))
+	cacheSizes.WithLabelValues("root_snapshot_indexes").Set(float64(dag.GetRootSnapshotIndexesCacheSize()))
+	rootSnapshotIndexesCacheHits.Set(float64(metrics.SharedServerMetrics.RootSnapshotIndexesCacheHits.Load()))
+	rootSnapshotIndexesCacheMisses.Set(float64(metrics.SharedServerMetrics.RootSnapshotIndexesCacheMisses.Load()))
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -23,7 +24,7 @@ func init() {
 
 	registry.MustRegister(dataSizes)
 
-	addCollect(collectData)
+	AddCollect(collectData)
 }
 
 func collectData() {
@@ -32,6 +33,11 @@ func collectData() {
 	if err == nil {
 		dataSizes.WithLabelValues("database").Set(float64(dbSize))
 	}
+
+	tangleSize, snapshotSize, spentSize := tangle.GetDatabaseSizes()
+	dataSizes.WithLabelValues("tangle").Set(float64(tangleSize))
+	dataSizes.WithLabelValues("snapshot").Set(float64(snapshotSize))
+	dataSizes.WithLabelValues("spent_addresses").Set(float64(spentSize))
 }
 
 func directorySize(path string) (int64, error) {
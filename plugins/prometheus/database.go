@@ -0,0 +1,45 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/gohornet/hornet/pkg/model/tangle"
+)
+
+var (
+	databaseOperations        *prometheus.GaugeVec
+	databaseOperationsSeconds *prometheus.GaugeVec
+)
+
+func init() {
+	databaseOperations = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_database_operations",
+			Help: "Number of operations on the stores of the databases.",
+		},
+		[]string{"store", "operation"},
+	)
+	databaseOperationsSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_database_operations_seconds",
+			Help: "Total duration of the operations on the stores of the databases.",
+		},
+		[]string{"store", "operation"},
+	)
+
+	registry.MustRegister(databaseOperations)
+	registry.MustRegister(databaseOperationsSeconds)
+
+	AddCollect(collectDatabaseOperations)
+}
+
+func collectDatabaseOperations() {
+	if !tangle.IsStoreMetricsEnabled() {
+		return
+	}
+
+	for _, metrics := range tangle.GetStoreMetrics() {
+		databaseOperations.WithLabelValues(metrics.Store, metrics.Operation).Set(float64(metrics.Count))
+		databaseOperationsSeconds.WithLabelValues(metrics.Store, metrics.Operation).Set(metrics.Duration.Seconds())
+	}
+}
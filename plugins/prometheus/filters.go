@@ -0,0 +1,40 @@
+package prometheus
+
+import (
+	"github.com/gohornet/hornet/plugins/gossip"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	filterPassed  *prometheus.GaugeVec
+	filterDropped *prometheus.GaugeVec
+)
+
+func init() {
+	filterPassed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_filter_passed_transactions",
+			Help: "Number of received transactions which passed a stage of the validation pipeline.",
+		},
+		[]string{"filter"},
+	)
+	filterDropped = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_filter_dropped_transactions",
+			Help: "Number of received transactions which were dropped by a stage of the validation pipeline.",
+		},
+		[]string{"filter"},
+	)
+
+	registry.MustRegister(filterPassed)
+	registry.MustRegister(filterDropped)
+
+	AddCollect(collectFilters)
+}
+
+func collectFilters() {
+	for _, filter := range gossip.Processor().FilterMetrics() {
+		filterPassed.WithLabelValues(filter.Name).Set(float64(filter.Passed))
+		filterDropped.WithLabelValues(filter.Name).Set(float64(filter.Dropped))
+	}
+}
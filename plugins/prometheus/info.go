@@ -3,6 +3,7 @@ package prometheus
 import (
 	"strconv"
 
+	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/plugins/cli"
 	"github.com/gohornet/hornet/plugins/gossip"
@@ -19,6 +20,8 @@ var (
 	infoSnapshotIndex         prometheus.Gauge
 	infoPruningIndex          prometheus.Gauge
 	infoTips                  prometheus.Gauge
+	infoTipsNonLazy           prometheus.Gauge
+	infoTipsSemiLazy          prometheus.Gauge
 	infoTransactionsToRequest prometheus.Gauge
 )
 
@@ -64,6 +67,14 @@ func init() {
 		Name: "iota_info_tips",
 		Help: "Number of tips.",
 	})
+	infoTipsNonLazy = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_info_tips_non_lazy",
+		Help: "Number of non-lazy tips in the tip pool.",
+	})
+	infoTipsSemiLazy = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_info_tips_semi_lazy",
+		Help: "Number of semi-lazy tips in the tip pool.",
+	})
 	infoTransactionsToRequest = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name: "iota_info_transactions_to_request",
 		Help: "Number of transactions to request.",
@@ -79,9 +90,11 @@ func init() {
 	registry.MustRegister(infoSnapshotIndex)
 	registry.MustRegister(infoPruningIndex)
 	registry.MustRegister(infoTips)
+	registry.MustRegister(infoTipsNonLazy)
+	registry.MustRegister(infoTipsSemiLazy)
 	registry.MustRegister(infoTransactionsToRequest)
 
-	addCollect(collectInfo)
+	AddCollect(collectInfo)
 }
 
 func collectInfo() {
@@ -125,7 +138,11 @@ func collectInfo() {
 	}
 
 	// Tips
-	infoTips.Set(0)
+	nonLazyTips := metrics.SharedServerMetrics.TipsNonLazy.Load()
+	semiLazyTips := metrics.SharedServerMetrics.TipsSemiLazy.Load()
+	infoTips.Set(float64(nonLazyTips + semiLazyTips))
+	infoTipsNonLazy.Set(float64(nonLazyTips))
+	infoTipsSemiLazy.Set(float64(semiLazyTips))
 
 	// Transactions to request
 	queued, pending, _ := gossip.RequestQueue().Size()
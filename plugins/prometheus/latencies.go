@@ -0,0 +1,39 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/gohornet/hornet/pkg/metrics"
+)
+
+var (
+	latencies *prometheus.GaugeVec
+)
+
+func init() {
+	latencies = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_latencies_seconds",
+			Help: "Latencies of the transactions in the tangle over the last minutes.",
+		},
+		[]string{"type", "stat"},
+	)
+
+	registry.MustRegister(latencies)
+
+	AddCollect(collectLatencies)
+}
+
+func collectLatencies() {
+	latencies.Reset()
+
+	for name, histogram := range map[string]*metrics.LatencyHistogram{
+		"time_to_solid":        metrics.SharedTangleStatistics.TimeToSolid,
+		"time_to_confirmation": metrics.SharedTangleStatistics.TimeToConfirmation,
+	} {
+		snapshot := histogram.Snapshot()
+		latencies.WithLabelValues(name, "mean").Set(snapshot.Mean().Seconds())
+		latencies.WithLabelValues(name, "median").Set(snapshot.Quantile(0.5).Seconds())
+		latencies.WithLabelValues(name, "p95").Set(snapshot.Quantile(0.95).Seconds())
+	}
+}
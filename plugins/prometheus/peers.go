@@ -3,7 +3,6 @@ package prometheus
 import (
 	"net"
 
-	"github.com/gohornet/hornet/plugins/peering"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -130,7 +129,7 @@ func init() {
 	registry.MustRegister(peersDroppedSentPackets)
 	registry.MustRegister(peersConnected)
 
-	addCollect(collectPeers)
+	AddCollect(collectPeers)
 }
 
 func collectPeers() {
@@ -148,7 +147,7 @@ func collectPeers() {
 	peersDroppedSentPackets.Reset()
 	peersConnected.Reset()
 
-	for _, peer := range peering.Manager().PeerInfos() {
+	for _, peer := range deps.PeeringManager.PeerInfos() {
 		address, port, _ := net.SplitHostPort(peer.Address)
 		labels := prometheus.Labels{
 			"address":        address,
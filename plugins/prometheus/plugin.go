@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,7 +16,10 @@ import (
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
 
+	"github.com/gohornet/hornet/pkg/app"
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
+	peeringpackage "github.com/gohornet/hornet/pkg/peering"
 	"github.com/gohornet/hornet/pkg/shutdown"
 )
 
@@ -24,13 +28,28 @@ var (
 	PLUGIN = node.NewPlugin("Prometheus", node.Disabled, configure, run)
 	log    *logger.Logger
 
-	server   *http.Server
-	registry = prometheus.NewRegistry()
-	collects []func()
+	server     *http.Server
+	registry   = prometheus.NewRegistry()
+	collects   []func()
+	collectsMu sync.RWMutex
 )
 
+// dependencies of the plugin which are injected before it is configured.
+type dependencies struct {
+	app.In
+	PeeringManager *peeringpackage.Manager
+}
+
+var deps dependencies
+
+func init() {
+	app.Inject(PLUGIN, func(d dependencies) {
+		deps = d
+	})
+}
+
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
 	if config.NodeConfig.GetBool(config.CfgPrometheusGoMetrics) {
 		registry.MustRegister(prometheus.NewGoCollector())
@@ -38,12 +57,32 @@ func configure(plugin *node.Plugin) {
 	if config.NodeConfig.GetBool(config.CfgPrometheusProcessMetrics) {
 		registry.MustRegister(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
 	}
+
+	configureTPS()
 }
 
-func addCollect(collect func()) {
+// Register registers the given collectors at the registry of the exporter.
+// Other plugins can use it to expose their own metrics, it panics if a collector can't be registered.
+func Register(collectors ...prometheus.Collector) {
+	registry.MustRegister(collectors...)
+}
+
+// AddCollect adds a function which is called before the metrics are gathered,
+// so that the values of registered collectors can be updated on every scrape.
+func AddCollect(collect func()) {
+	collectsMu.Lock()
+	defer collectsMu.Unlock()
 	collects = append(collects, collect)
 }
 
+func collectAll() {
+	collectsMu.RLock()
+	defer collectsMu.RUnlock()
+	for _, collect := range collects {
+		collect()
+	}
+}
+
 type fileservicediscovery struct {
 	Targets []string          `json:"targets"`
 	Labels  map[string]string `json:"labels"`
@@ -82,9 +121,7 @@ func run(plugin *node.Plugin) {
 		engine := gin.New()
 		engine.Use(gin.Recovery())
 		engine.GET("/metrics", func(c *gin.Context) {
-			for _, collect := range collects {
-				collect()
-			}
+			collectAll()
 			handler := promhttp.HandlerFor(
 				registry,
 				promhttp.HandlerOpts{
@@ -0,0 +1,62 @@
+package prometheus
+
+import (
+	"github.com/gohornet/hornet/pkg/metrics"
+	"github.com/gohornet/hornet/plugins/gossip"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	requestsQueued     prometheus.Gauge
+	requestsPending    prometheus.Gauge
+	requestsProcessing prometheus.Gauge
+	requestsAvgLatency prometheus.Gauge
+	requestsRetried    prometheus.Gauge
+	requestsDiscarded  prometheus.Gauge
+)
+
+func init() {
+	requestsQueued = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_requests_queued",
+		Help: "Number of queued transaction requests.",
+	})
+	requestsPending = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_requests_pending",
+		Help: "Number of sent transaction requests which are not answered yet.",
+	})
+	requestsProcessing = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_requests_processing",
+		Help: "Number of received requested transactions which are processing.",
+	})
+	requestsAvgLatency = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_requests_avg_latency",
+		Help: "Average latency of transaction requests in milliseconds.",
+	})
+	requestsRetried = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_requests_retried",
+		Help: "Number of transaction requests which were sent again.",
+	})
+	requestsDiscarded = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_requests_discarded",
+		Help: "Number of transaction requests which were discarded.",
+	})
+
+	registry.MustRegister(requestsQueued)
+	registry.MustRegister(requestsPending)
+	registry.MustRegister(requestsProcessing)
+	registry.MustRegister(requestsAvgLatency)
+	registry.MustRegister(requestsRetried)
+	registry.MustRegister(requestsDiscarded)
+
+	AddCollect(collectRequests)
+}
+
+func collectRequests() {
+	queued, pending, processing := gossip.RequestQueue().Size()
+	requestsQueued.Set(float64(queued))
+	requestsPending.Set(float64(pending))
+	requestsProcessing.Set(float64(processing))
+	requestsAvgLatency.Set(float64(gossip.RequestQueue().AvgLatency()))
+	requestsRetried.Set(float64(metrics.SharedServerMetrics.RetriedTransactionRequests.Load()))
+	requestsDiscarded.Set(float64(metrics.SharedServerMetrics.DiscardedTransactionRequests.Load()))
+}
@@ -21,6 +21,10 @@ var (
 	serverSentMilestoneRequests       prometheus.Gauge
 	serverSentHeartbeats              prometheus.Gauge
 	serverDroppedSentPackets          prometheus.Gauge
+	serverRateLimitedMessages         prometheus.Gauge
+	serverSuppressedBroadcasts        prometheus.Gauge
+	serverExcludedBroadcastPeers      prometheus.Gauge
+	serverDroppedSolidifications      prometheus.Gauge
 	serverSentSpamTransactions        prometheus.Gauge
 	serverValidatedBundles            prometheus.Gauge
 	serverSeenSpentAddresses          prometheus.Gauge
@@ -87,6 +91,22 @@ func init() {
 		Name: "iota_server_dropped_sent_packets",
 		Help: "Number of dropped sent packets.",
 	})
+	serverRateLimitedMessages = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_server_rate_limited_messages",
+		Help: "Number of received messages which were dropped because they exceeded a rate limit.",
+	})
+	serverSuppressedBroadcasts = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_server_suppressed_broadcasts",
+		Help: "Number of transaction broadcasts which were suppressed, because the transaction was broadcasted recently.",
+	})
+	serverExcludedBroadcastPeers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_server_excluded_broadcast_peers",
+		Help: "Number of peers a broadcasted transaction was not sent to, because it was received from them.",
+	})
+	serverDroppedSolidifications = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_server_dropped_future_cone_solidifications",
+		Help: "Number of future cone solidifications which were dropped because the queue was full.",
+	})
 	serverSentSpamTransactions = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name: "iota_server_sent_spam_transactions",
 		Help: "Number of sent spam transactions.",
@@ -115,11 +135,15 @@ func init() {
 	registry.MustRegister(serverSentMilestoneRequests)
 	registry.MustRegister(serverSentHeartbeats)
 	registry.MustRegister(serverDroppedSentPackets)
+	registry.MustRegister(serverRateLimitedMessages)
+	registry.MustRegister(serverSuppressedBroadcasts)
+	registry.MustRegister(serverExcludedBroadcastPeers)
+	registry.MustRegister(serverDroppedSolidifications)
 	registry.MustRegister(serverSentSpamTransactions)
 	registry.MustRegister(serverValidatedBundles)
 	registry.MustRegister(serverSeenSpentAddresses)
 
-	addCollect(collectServer)
+	AddCollect(collectServer)
 }
 
 func collectServer() {
@@ -138,6 +162,10 @@ func collectServer() {
 	serverSentMilestoneRequests.Set(float64(metrics.SharedServerMetrics.SentMilestoneRequests.Load()))
 	serverSentHeartbeats.Set(float64(metrics.SharedServerMetrics.SentHeartbeats.Load()))
 	serverDroppedSentPackets.Set(float64(metrics.SharedServerMetrics.DroppedMessages.Load()))
+	serverRateLimitedMessages.Set(float64(metrics.SharedServerMetrics.RateLimitedMessages.Load()))
+	serverSuppressedBroadcasts.Set(float64(metrics.SharedServerMetrics.SuppressedBroadcasts.Load()))
+	serverExcludedBroadcastPeers.Set(float64(metrics.SharedServerMetrics.ExcludedBroadcastPeers.Load()))
+	serverDroppedSolidifications.Set(float64(metrics.SharedServerMetrics.DroppedFutureConeSolidifications.Load()))
 	serverSentSpamTransactions.Set(float64(metrics.SharedServerMetrics.SentSpamTransactions.Load()))
 	serverValidatedBundles.Set(float64(metrics.SharedServerMetrics.ValidatedBundles.Load()))
 	serverSeenSpentAddresses.Set(float64(metrics.SharedServerMetrics.SeenSpentAddresses.Load()))
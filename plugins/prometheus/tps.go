@@ -0,0 +1,74 @@
+package prometheus
+
+import (
+	"github.com/iotaledger/hive.go/events"
+	"github.com/prometheus/client_golang/prometheus"
+
+	metricsplugin "github.com/gohornet/hornet/plugins/metrics"
+	"github.com/gohornet/hornet/plugins/tangle"
+)
+
+var (
+	tpsIncoming                     prometheus.Gauge
+	tpsNew                          prometheus.Gauge
+	tpsOutgoing                     prometheus.Gauge
+	milestoneTPS                    prometheus.Gauge
+	milestoneCTPS                   prometheus.Gauge
+	milestoneConfirmationRate       prometheus.Gauge
+	milestoneTimeSinceLastMilestone prometheus.Gauge
+)
+
+func init() {
+	tpsIncoming = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_tps_incoming",
+		Help: "Number of incoming transactions per second.",
+	})
+	tpsNew = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_tps_new",
+		Help: "Number of new transactions per second.",
+	})
+	tpsOutgoing = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_tps_outgoing",
+		Help: "Number of outgoing transactions per second.",
+	})
+	milestoneTPS = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_milestone_tps",
+		Help: "Number of transactions per second between the last two confirmed milestones.",
+	})
+	milestoneCTPS = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_milestone_ctps",
+		Help: "Number of confirmed transactions per second between the last two confirmed milestones.",
+	})
+	milestoneConfirmationRate = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_milestone_confirmation_rate",
+		Help: "Percentage of the transactions which got confirmed by the last confirmed milestone.",
+	})
+	milestoneTimeSinceLastMilestone = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iota_milestone_time_since_last_milestone_seconds",
+		Help: "Time between the last two confirmed milestones in seconds.",
+	})
+
+	registry.MustRegister(tpsIncoming)
+	registry.MustRegister(tpsNew)
+	registry.MustRegister(tpsOutgoing)
+	registry.MustRegister(milestoneTPS)
+	registry.MustRegister(milestoneCTPS)
+	registry.MustRegister(milestoneConfirmationRate)
+	registry.MustRegister(milestoneTimeSinceLastMilestone)
+}
+
+// the rates are only known when they are calculated, so they are updated by events instead of on every scrape.
+func configureTPS() {
+	metricsplugin.Events.TPSMetricsUpdated.Attach(events.NewClosure(func(tpsMetrics *metricsplugin.TPSMetrics) {
+		tpsIncoming.Set(float64(tpsMetrics.Incoming))
+		tpsNew.Set(float64(tpsMetrics.New))
+		tpsOutgoing.Set(float64(tpsMetrics.Outgoing))
+	}))
+
+	tangle.Events.NewConfirmedMilestoneMetric.Attach(events.NewClosure(func(metric *tangle.ConfirmedMilestoneMetric) {
+		milestoneTPS.Set(metric.TPS)
+		milestoneCTPS.Set(metric.CTPS)
+		milestoneConfirmationRate.Set(metric.ConfirmationRate)
+		milestoneTimeSinceLastMilestone.Set(metric.TimeSinceLastMilestone)
+	}))
+}
@@ -0,0 +1,79 @@
+package prometheus
+
+import (
+	"github.com/gohornet/hornet/pkg/workerpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	workerPoolQueueLength *prometheus.GaugeVec
+	workerPoolSaturated   *prometheus.GaugeVec
+	workerPoolSubmitted   *prometheus.GaugeVec
+	workerPoolProcessed   *prometheus.GaugeVec
+	workerPoolDropped     *prometheus.GaugeVec
+)
+
+func init() {
+	workerPoolQueueLength = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_workerpool_queue_length",
+			Help: "Number of queued tasks of a worker pool by priority.",
+		},
+		[]string{"name", "priority"},
+	)
+	workerPoolSaturated = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_workerpool_saturated",
+			Help: "Whether the queue of a worker pool reached its saturation threshold, by priority.",
+		},
+		[]string{"name", "priority"},
+	)
+	workerPoolSubmitted = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_workerpool_submitted_tasks",
+			Help: "Number of submitted tasks of a worker pool by priority.",
+		},
+		[]string{"name", "priority"},
+	)
+	workerPoolProcessed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_workerpool_processed_tasks",
+			Help: "Number of processed tasks of a worker pool by priority.",
+		},
+		[]string{"name", "priority"},
+	)
+	workerPoolDropped = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iota_workerpool_dropped_tasks",
+			Help: "Number of tasks of a worker pool which were dropped because the queue was full, by priority.",
+		},
+		[]string{"name", "priority"},
+	)
+
+	registry.MustRegister(workerPoolQueueLength)
+	registry.MustRegister(workerPoolSaturated)
+	registry.MustRegister(workerPoolSubmitted)
+	registry.MustRegister(workerPoolProcessed)
+	registry.MustRegister(workerPoolDropped)
+
+	AddCollect(collectWorkerPools)
+}
+
+func collectWorkerPools() {
+	for _, pool := range workerpool.Metrics() {
+		for _, lane := range pool.Lanes {
+			priority := lane.Priority.String()
+
+			saturated := 0.0
+			if lane.Saturated {
+				saturated = 1.0
+			}
+
+			workerPoolQueueLength.WithLabelValues(pool.Name, priority).Set(float64(lane.QueueLength))
+			workerPoolSaturated.WithLabelValues(pool.Name, priority).Set(saturated)
+			workerPoolSubmitted.WithLabelValues(pool.Name, priority).Set(float64(lane.Submitted))
+			workerPoolProcessed.WithLabelValues(pool.Name, priority).Set(float64(lane.Processed))
+			workerPoolDropped.WithLabelValues(pool.Name, priority).Set(float64(lane.Dropped))
+		}
+	}
+}
@@ -0,0 +1,465 @@
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/iota.go/consts"
+
+	"github.com/iotaledger/hive.go/daemon"
+
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/networkid"
+	snapshotFile "github.com/gohornet/hornet/pkg/snapshot"
+	"github.com/gohornet/hornet/plugins/gossip"
+	tanglePlugin "github.com/gohornet/hornet/plugins/tangle"
+)
+
+var (
+	SupportedDeltaSnapshotFileVersions = []byte{snapshotFile.DeltaSnapshotFileVersionWithoutNetworkID, snapshotFile.DeltaSnapshotFileVersion}
+
+	ErrUnsupportedDeltaFileVersion = errors.New("unsupported delta snapshot file version")
+	ErrDeltaSnapshotMismatch       = errors.New("delta snapshot does not belong to the loaded local snapshot")
+)
+
+// readSnapshotFileMilestone reads the milestone hash and index from the header of a local snapshot file.
+func readSnapshotFileMilestone(filePath string) (hornet.Hash, milestone.Index, error) {
+	file, err := os.OpenFile(filePath, os.O_RDONLY, 0666)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	var fileVersion byte
+	if err := binary.Read(file, binary.LittleEndian, &fileVersion); err != nil {
+		return nil, 0, err
+	}
+
+	if !bytes.Contains(SupportedLocalSnapshotFileVersions, []byte{fileVersion}) {
+		return nil, 0, errors.Wrapf(ErrUnsupportedLSFileVersion, "local snapshot file version is %d but this HORNET version only supports %v", fileVersion, SupportedLocalSnapshotFileVersions)
+	}
+
+	msHash := make(hornet.Hash, 49)
+	if err := binary.Read(file, binary.LittleEndian, msHash); err != nil {
+		return nil, 0, err
+	}
+
+	var msIndex milestone.Index
+	if err := binary.Read(file, binary.LittleEndian, &msIndex); err != nil {
+		return nil, 0, err
+	}
+
+	return msHash, msIndex, nil
+}
+
+// shouldTakeDeltaSnapshot checks whether a delta snapshot based on the local snapshot file
+// can be created for the target index, instead of a new local snapshot file.
+func shouldTakeDeltaSnapshot(targetIndex milestone.Index, filePath string) bool {
+	if fullSnapshotInterval == 0 || deltaSnapshotPath == "" {
+		return false
+	}
+
+	_, fullSnapshotIndex, err := readSnapshotFileMilestone(filePath)
+	if err != nil {
+		return false
+	}
+
+	if targetIndex <= fullSnapshotIndex || targetIndex-fullSnapshotIndex >= fullSnapshotInterval {
+		return false
+	}
+
+	// the ledger diffs since the local snapshot file must still be available
+	return fullSnapshotIndex >= tangle.GetSnapshotInfo().PruningIndex
+}
+
+type deltaSnapshotHeader struct {
+	fullMsHash       hornet.Hash
+	fullMsIndex      milestone.Index
+	msHash           hornet.Hash
+	msIndex          milestone.Index
+	msTimestamp      int64
+	solidEntryPoints []*tangle.SolidEntryPoint
+	seenMilestones   map[string]milestone.Index
+	ledgerDiffs      map[milestone.Index]map[string]int64
+}
+
+// writeRecords writes the solid entry points, seen milestones and ledger diffs of the delta snapshot.
+func (ds *deltaSnapshotHeader) writeRecords(dsWriter *snapshotFile.DeltaFileWriter, abortSignal <-chan struct{}) error {
+
+	for _, solidEntryPoint := range ds.solidEntryPoints {
+		select {
+		case <-abortSignal:
+			return ErrSnapshotCreationWasAborted
+		default:
+		}
+
+		if err := dsWriter.WriteSolidEntryPoint(solidEntryPoint.TxHash, solidEntryPoint.Index); err != nil {
+			return err
+		}
+	}
+
+	for hash, val := range ds.seenMilestones {
+		select {
+		case <-abortSignal:
+			return ErrSnapshotCreationWasAborted
+		default:
+		}
+
+		if err := dsWriter.WriteSeenMilestone(hornet.Hash(hash), val); err != nil {
+			return err
+		}
+	}
+
+	// the ledger diffs are written in ascending milestone order
+	for msIndex := ds.fullMsIndex + 1; msIndex <= ds.msIndex; msIndex++ {
+		select {
+		case <-abortSignal:
+			return ErrSnapshotCreationWasAborted
+		default:
+		}
+
+		diff, exists := ds.ledgerDiffs[msIndex]
+		if !exists {
+			continue
+		}
+
+		if err := dsWriter.WriteMilestoneDiff(msIndex, diff); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createDeltaSnapshotFile streams the delta snapshot into the given file, followed by the sha256 hash of its content.
+func createDeltaSnapshotFile(filePath string, dsh *deltaSnapshotHeader, abortSignal <-chan struct{}) ([]byte, error) {
+
+	if _, fileErr := os.Stat(filePath); os.IsNotExist(fileErr) {
+		// create dir if it not exists
+		if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+			return nil, err
+		}
+	}
+	exportFile, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0660)
+	if err != nil {
+		return nil, err
+	}
+	defer exportFile.Close()
+
+	dsWriter, err := snapshotFile.NewDeltaFileWriter(exportFile, &snapshotFile.DeltaFileHeader{
+		FileHeader: snapshotFile.FileHeader{
+			MilestoneHash:  dsh.msHash,
+			MilestoneIndex: dsh.msIndex,
+			Timestamp:      dsh.msTimestamp,
+			NetworkID:      networkid.FromConfig(),
+		},
+		FullMilestoneHash:  dsh.fullMsHash,
+		FullMilestoneIndex: dsh.fullMsIndex,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dsh.writeRecords(dsWriter, abortSignal); err != nil {
+		return nil, err
+	}
+
+	// flush the records and write the record counts into the header
+	if err := dsWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	// seek back to the beginning of the file
+	if _, err := exportFile.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	// compute sha256 of file
+	dsHash := sha256.New()
+	if _, err := io.Copy(dsHash, exportFile); err != nil {
+		return nil, err
+	}
+
+	// write sha256 hash into the file
+	sha256Hash := dsHash.Sum(nil)
+	if err := binary.Write(exportFile, binary.LittleEndian, sha256Hash); err != nil {
+		return nil, err
+	}
+
+	return sha256Hash, nil
+}
+
+// createDeltaSnapshot writes a delta snapshot file for the target index, which contains the ledger diffs
+// since the milestone of the given local snapshot file.
+func createDeltaSnapshot(targetIndex milestone.Index, fullSnapshotFilePath string, deltaFilePath string, lsh *localSnapshotHeader, abortSignal <-chan struct{}) ([]byte, error) {
+
+	fullMsHash, fullMsIndex, err := readSnapshotFileMilestone(fullSnapshotFilePath)
+	if err != nil {
+		return nil, errors.Wrapf(ErrSnapshotCreationFailed, "local snapshot file: %v", err)
+	}
+
+	ledgerDiffs := make(map[milestone.Index]map[string]int64)
+	for msIndex := fullMsIndex + 1; msIndex <= targetIndex; msIndex++ {
+		diff, err := tangle.Ledger().GetLedgerDiffForMilestone(msIndex, abortSignal)
+		if err != nil {
+			if err == tangle.ErrOperationAborted {
+				return nil, err
+			}
+			return nil, errors.Wrap(ErrCritical, err.Error())
+		}
+
+		if len(diff) != 0 {
+			ledgerDiffs[msIndex] = diff
+		}
+	}
+
+	dsh := &deltaSnapshotHeader{
+		fullMsHash:       fullMsHash,
+		fullMsIndex:      fullMsIndex,
+		msHash:           lsh.msHash,
+		msIndex:          lsh.msIndex,
+		msTimestamp:      lsh.msTimestamp,
+		solidEntryPoints: lsh.solidEntryPoints,
+		seenMilestones:   lsh.seenMilestones,
+		ledgerDiffs:      ledgerDiffs,
+	}
+
+	filePathTmp := deltaFilePath + "_tmp"
+
+	// Remove old temp file
+	os.Remove(filePathTmp)
+
+	hash, err := createDeltaSnapshotFile(filePathTmp, dsh, abortSignal)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(filePathTmp, deltaFilePath); err != nil {
+		return nil, err
+	}
+
+	return hash, nil
+}
+
+// streamDeltaSnapshotFile streams the records of the delta snapshot file to the consumers
+// and verifies the sha256 hash at the end of the file against the streamed content afterwards.
+// The consumers must therefore not apply any changes before the function returned successfully.
+func streamDeltaSnapshotFile(file *os.File,
+	headerConsumer snapshotFile.DeltaHeaderConsumerFunc,
+	sepConsumer snapshotFile.SolidEntryPointConsumerFunc,
+	seenMilestoneConsumer snapshotFile.SeenMilestoneConsumerFunc,
+	milestoneDiffConsumer snapshotFile.MilestoneDiffConsumerFunc) error {
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if fileInfo.Size() < sha256.Size {
+		return errors.Wrapf(ErrInvalidSnapshotFile, "file too small (%d bytes)", fileInfo.Size())
+	}
+
+	dsHash := sha256.New()
+	content := io.TeeReader(io.LimitReader(file, fileInfo.Size()-sha256.Size), dsHash)
+
+	if err := snapshotFile.StreamDeltaSnapshotDataFrom(content, headerConsumer, sepConsumer, seenMilestoneConsumer, milestoneDiffConsumer); err != nil {
+		return err
+	}
+
+	// hash the content which was not read by the stream
+	if _, err := io.Copy(ioutil.Discard, content); err != nil {
+		return err
+	}
+
+	expectedHash := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(file, expectedHash); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(dsHash.Sum(nil), expectedHash) {
+		return errors.Wrapf(ErrInvalidSnapshotFile, "sha256 mismatch: %x != %x", dsHash.Sum(nil), expectedHash)
+	}
+
+	return nil
+}
+
+// LoadDeltaSnapshotFromFile applies the delta snapshot on top of the previously loaded local snapshot.
+// Nothing is applied if the sha256 hash at the end of the file doesn't match its content.
+func LoadDeltaSnapshotFromFile(filePath string) error {
+	log.Info("Loading delta snapshot file...")
+
+	file, err := os.OpenFile(filePath, os.O_RDONLY, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	snapshotInfo := tangle.GetSnapshotInfo()
+	if snapshotInfo == nil {
+		return errors.Wrap(ErrSnapshotImportFailed, "no snapshot info found")
+	}
+
+	var header *snapshotFile.ReadDeltaFileHeader
+	var newSolidEntryPoints []*tangle.SolidEntryPoint
+	var ledgerState map[string]uint64
+	seenMilestones := make(map[string]milestone.Index)
+	spentAddresses := make(map[string]struct{})
+
+	headerConsumer := func(readHeader *snapshotFile.ReadDeltaFileHeader) error {
+		header = readHeader
+
+		if err := checkNetworkID(header.NetworkID); err != nil {
+			return err
+		}
+
+		if snapshotInfo.SnapshotIndex != header.FullMilestoneIndex || !bytes.Equal(snapshotInfo.Hash, header.FullMilestoneHash) {
+			return errors.Wrapf(ErrDeltaSnapshotMismatch, "delta snapshot is based on milestone %d, loaded snapshot is %d", header.FullMilestoneIndex, snapshotInfo.SnapshotIndex)
+		}
+
+		if header.MilestoneIndex <= header.FullMilestoneIndex {
+			return errors.Wrapf(ErrSnapshotImportFailed, "delta snapshot milestone %d is not above the local snapshot milestone %d", header.MilestoneIndex, header.FullMilestoneIndex)
+		}
+
+		if err := checkSnapshotHistory(header.NetworkID, header.MilestoneIndex, header.Timestamp); err != nil {
+			return err
+		}
+
+		ledgerState, _, err = tangle.Ledger().GetLedgerStateForLSMI(nil)
+		if err != nil {
+			return errors.Wrapf(ErrSnapshotImportFailed, "ledgerState: %v", err)
+		}
+
+		newSolidEntryPoints = []*tangle.SolidEntryPoint{{TxHash: header.MilestoneHash, Index: header.MilestoneIndex}}
+
+		log.Info("importing solid entry points")
+		return nil
+	}
+
+	sepConsumer := func(txHash hornet.Hash, index milestone.Index) error {
+		if daemon.IsStopped() {
+			return ErrSnapshotImportWasAborted
+		}
+
+		// duplicates are ignored by ReplaceSolidEntryPoints
+		newSolidEntryPoints = append(newSolidEntryPoints, &tangle.SolidEntryPoint{TxHash: txHash, Index: index})
+		return nil
+	}
+
+	seenMilestoneConsumer := func(msHash hornet.Hash, index milestone.Index) error {
+		if daemon.IsStopped() {
+			return ErrSnapshotImportWasAborted
+		}
+
+		seenMilestones[string(msHash)] = index
+		return nil
+	}
+
+	milestoneDiffConsumer := func(diffIndex milestone.Index, changes map[string]int64) error {
+		if daemon.IsStopped() {
+			return ErrSnapshotImportWasAborted
+		}
+
+		if diffIndex <= header.FullMilestoneIndex || diffIndex > header.MilestoneIndex {
+			return errors.Wrapf(ErrSnapshotImportFailed, "ledgerDiffs: milestone %d out of range", diffIndex)
+		}
+
+		for addr, change := range changes {
+			balance := int64(ledgerState[addr]) + change
+			if balance < 0 {
+				return errors.Wrapf(ErrInvalidBalance, "milestone %d creates negative balance for address %s", diffIndex, hornet.Hash(addr).Trytes())
+			}
+
+			if balance == 0 {
+				delete(ledgerState, addr)
+			} else {
+				ledgerState[addr] = uint64(balance)
+			}
+
+			if change < 0 {
+				// only spent addresses have negative balance changes
+				spentAddresses[addr] = struct{}{}
+			}
+		}
+		return nil
+	}
+
+	if err := streamDeltaSnapshotFile(file, headerConsumer, sepConsumer, seenMilestoneConsumer, milestoneDiffConsumer); err != nil {
+		switch {
+		case err == ErrSnapshotImportWasAborted,
+			errors.Is(err, ErrInvalidSnapshotFile),
+			errors.Is(err, ErrDeltaSnapshotMismatch),
+			errors.Is(err, ErrWrongNetworkID),
+			errors.Is(err, tangle.ErrSnapshotDowngrade),
+			errors.Is(err, tangle.ErrForeignSnapshot),
+			errors.Is(err, ErrSnapshotImportFailed),
+			errors.Is(err, ErrInvalidBalance):
+			return err
+		case errors.Is(err, snapshotFile.ErrUnsupportedFileVersion):
+			return errors.Wrap(ErrUnsupportedDeltaFileVersion, err.Error())
+		default:
+			return errors.Wrapf(ErrSnapshotImportFailed, "%v", err)
+		}
+	}
+
+	msIndex := header.MilestoneIndex
+
+	var total uint64
+	for _, value := range ledgerState {
+		total += value
+	}
+
+	if total != consts.TotalSupply {
+		return errors.Wrapf(ErrInvalidBalance, "%d != %d", total, consts.TotalSupply)
+	}
+
+	err = tangle.StoreSnapshotBalancesInDatabase(ledgerState, msIndex)
+	if err != nil {
+		return errors.Wrapf(ErrSnapshotImportFailed, "snapshot ledgerEntries: %s", err)
+	}
+
+	err = tangle.Ledger().StoreLedgerBalancesInDatabase(ledgerState, msIndex)
+	if err != nil {
+		return errors.Wrapf(ErrSnapshotImportFailed, "ledgerEntries: %v", err)
+	}
+
+	spentAddressesEnabled := snapshotInfo.IsSpentAddressesEnabled()
+	if spentAddressesEnabled && config.NodeConfig.GetBool(config.CfgSpentAddressesEnabled) {
+		log.Infof("importing %d spent addresses", len(spentAddresses))
+		for addr := range spentAddresses {
+			tangle.MarkAddressAsSpentWithoutLocking(hornet.Hash(addr))
+		}
+	}
+
+	tangle.SetSnapshotMilestone(snapshotInfo.CoordinatorAddress, header.MilestoneHash, msIndex, msIndex, msIndex, header.Timestamp, spentAddressesEnabled)
+	tangle.ReplaceSolidEntryPoints(newSolidEntryPoints)
+	tangle.SetLatestSeenMilestoneIndexFromSnapshot(msIndex)
+
+	for txHash, val := range seenMilestones {
+		tangle.SetLatestSeenMilestoneIndexFromSnapshot(val)
+		// request the milestone and prevent the request from being discarded from the request queue
+		gossip.Request(hornet.Hash(txHash), val, true)
+	}
+
+	if err := storeSnapshotHistory(header.NetworkID, header.MilestoneHash, msIndex, header.Timestamp, false); err != nil {
+		return err
+	}
+
+	// set the solid milestone index based on the delta snapshot milestone
+	tangle.SetSolidMilestoneIndex(msIndex, false)
+
+	log.Info("finished loading delta snapshot")
+
+	tanglePlugin.Events.SnapshotMilestoneIndexChanged.Trigger(msIndex)
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package snapshot
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	snapshotFile "github.com/gohornet/hornet/pkg/snapshot"
+)
+
+// writeDeltaSnapshotFile writes a delta snapshot file with a single ledger diff and returns its content.
+func writeDeltaSnapshotFile(t *testing.T, filePath string) []byte {
+	address := string(bytes.Repeat([]byte{3}, 49))
+
+	_, err := createDeltaSnapshotFile(filePath, &deltaSnapshotHeader{
+		fullMsHash:       bytes.Repeat([]byte{1}, 49),
+		fullMsIndex:      10,
+		msHash:           bytes.Repeat([]byte{2}, 49),
+		msIndex:          12,
+		msTimestamp:      1600000000,
+		solidEntryPoints: []*tangle.SolidEntryPoint{{TxHash: bytes.Repeat([]byte{2}, 49), Index: 12}},
+		seenMilestones:   map[string]milestone.Index{},
+		ledgerDiffs:      map[milestone.Index]map[string]int64{11: {address: 100}},
+	}, nil)
+	require.NoError(t, err)
+
+	content, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	return content
+}
+
+// streamDeltaSnapshotFilePath streams the delta snapshot file at the given path and returns the streamed ledger diffs.
+func streamDeltaSnapshotFilePath(filePath string) (map[milestone.Index]map[string]int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	diffs := make(map[milestone.Index]map[string]int64)
+	err = streamDeltaSnapshotFile(file,
+		func(_ *snapshotFile.ReadDeltaFileHeader) error { return nil },
+		func(_ hornet.Hash, _ milestone.Index) error { return nil },
+		func(_ hornet.Hash, _ milestone.Index) error { return nil },
+		func(index milestone.Index, changes map[string]int64) error {
+			diffs[index] = changes
+			return nil
+		})
+	return diffs, err
+}
+
+func TestStreamDeltaSnapshotFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "delta_snapshot.bin")
+	writeDeltaSnapshotFile(t, filePath)
+
+	diffs, err := streamDeltaSnapshotFilePath(filePath)
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+	require.Equal(t, int64(100), diffs[11][string(bytes.Repeat([]byte{3}, 49))])
+}
+
+func TestStreamDeltaSnapshotFileCorrupted(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "delta_snapshot.bin")
+	content := writeDeltaSnapshotFile(t, filePath)
+
+	// flip a bit of the balance change, which is the last value before the sha256 hash
+	corrupted := append([]byte{}, content...)
+	corrupted[len(corrupted)-33] ^= 0x01
+	require.NoError(t, ioutil.WriteFile(filePath, corrupted, 0600))
+
+	_, err := streamDeltaSnapshotFilePath(filePath)
+	require.True(t, errors.Is(err, ErrInvalidSnapshotFile), "unexpected error: %v", err)
+
+	// the hash is replaced by the last bytes of the content if the file is truncated
+	require.NoError(t, ioutil.WriteFile(filePath, content[:len(content)-1], 0600))
+
+	_, err = streamDeltaSnapshotFilePath(filePath)
+	require.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filePath, content[:10], 0600))
+
+	_, err = streamDeltaSnapshotFilePath(filePath)
+	require.True(t, errors.Is(err, ErrInvalidSnapshotFile), "unexpected error: %v", err)
+}
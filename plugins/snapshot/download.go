@@ -1,6 +1,8 @@
 package snapshot
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,7 +11,20 @@ import (
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+
 	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/iota.go/consts"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	snapshotFile "github.com/gohornet/hornet/pkg/snapshot"
+)
+
+const (
+	// the minimum size of the header of a local snapshot file (without the network ID of newer versions):
+	// 1 (version) + 49 (ms hash) + 4 (ms index) + 8 (ms timestamp) +
+	// 4 (SEPs count) + 4 (seen ms count) + 4 (ledger entries) + 4 (spent addresses count)
+	localSnapshotHeaderSize = 78
 )
 
 // WriteCounter counts the number of bytes written to it. It implements to the io.Writer interface
@@ -93,6 +108,12 @@ func downloadSnapshotFile(filepath string, urls []string) error {
 		// The progress use the same line so print a new line once it's finished downloading
 		fmt.Print("\n")
 
+		if err := verifyLocalSnapshotFile(filepath + ".tmp"); err != nil {
+			log.Warnf("Downloaded snapshot from %s is invalid: %v", url, err)
+			out.Close()
+			continue
+		}
+
 		downloadOK = true
 
 		// Close the file without defer so it can happen before Rename()
@@ -110,3 +131,66 @@ func downloadSnapshotFile(filepath string, urls []string) error {
 	}
 	return nil
 }
+
+// verifyLocalSnapshotFile checks the sha256 hash at the end of the local snapshot file, whether it belongs to the network
+// of the node and whether the sum of the ledger entries matches the total supply, without importing the file.
+func verifyLocalSnapshotFile(filePath string) error {
+
+	file, err := os.OpenFile(filePath, os.O_RDONLY, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if fileInfo.Size() < localSnapshotHeaderSize+sha256.Size {
+		return errors.Wrapf(ErrInvalidSnapshotFile, "file too small (%d bytes)", fileInfo.Size())
+	}
+
+	lsHash := sha256.New()
+	if _, err := io.Copy(lsHash, io.LimitReader(file, fileInfo.Size()-sha256.Size)); err != nil {
+		return err
+	}
+
+	expectedHash := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(file, expectedHash); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(lsHash.Sum(nil), expectedHash) {
+		return errors.Wrapf(ErrInvalidSnapshotFile, "sha256 mismatch: %x != %x", lsHash.Sum(nil), expectedHash)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	var total uint64
+	if err := snapshotFile.StreamLocalSnapshotDataFrom(io.LimitReader(file, fileInfo.Size()-sha256.Size),
+		func(header *snapshotFile.ReadFileHeader) error {
+			return checkNetworkID(header.NetworkID)
+		}, nil, nil,
+		func(_ hornet.Hash, balance uint64) error {
+			total += balance
+			return nil
+		}, nil); err != nil {
+		switch {
+		case errors.Is(err, ErrWrongNetworkID):
+			return err
+		case errors.Is(err, snapshotFile.ErrUnsupportedFileVersion):
+			return errors.Wrap(ErrUnsupportedLSFileVersion, err.Error())
+		default:
+			return errors.Wrapf(ErrInvalidSnapshotFile, "%v", err)
+		}
+	}
+
+	if total != consts.TotalSupply {
+		return errors.Wrapf(ErrInvalidBalance, "%d != %d", total, consts.TotalSupply)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package snapshot
+
+import (
+	"github.com/iotaledger/hive.go/events"
+
+	"github.com/gohornet/hornet/pkg/model/milestone"
+)
+
+func PruningProgressCaller(handler interface{}, params ...interface{}) {
+	handler.(func(currentIndex milestone.Index, targetIndex milestone.Index))(params[0].(milestone.Index), params[1].(milestone.Index))
+}
+
+func SnapshotCreatedCaller(handler interface{}, params ...interface{}) {
+	handler.(func(targetIndex milestone.Index, filePath string, isDelta bool))(params[0].(milestone.Index), params[1].(string), params[2].(bool))
+}
+
+var Events = pluginEvents{
+	PruningStarted:  events.NewEvent(milestone.IndexCaller),
+	PruningProgress: events.NewEvent(PruningProgressCaller),
+	PruningFinished: events.NewEvent(milestone.IndexCaller),
+	SnapshotCreated: events.NewEvent(SnapshotCreatedCaller),
+}
+
+type pluginEvents struct {
+	// PruningStarted is triggered with the target index before the database is pruned.
+	PruningStarted *events.Event
+	// PruningProgress is triggered with the current and the target index after every pruned milestone.
+	PruningProgress *events.Event
+	// PruningFinished is triggered with the reached pruning index after the database was pruned.
+	PruningFinished *events.Event
+	// SnapshotCreated is triggered with the target index, the file path and whether it is a delta snapshot after a snapshot file was created.
+	SnapshotCreated *events.Event
+}
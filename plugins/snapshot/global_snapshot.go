@@ -15,6 +15,7 @@ import (
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/networkid"
 	tanglePlugin "github.com/gohornet/hornet/plugins/tangle"
 )
 
@@ -57,14 +58,13 @@ func loadSnapshotFromTextfiles(filePathLedger string, filePathsSpent []string, s
 		return errors.Wrapf(ErrSnapshotImportFailed, "Milestone in database (%d) newer than snapshot milestone (%d)", latestMilestoneFromDatabase, snapshotIndex)
 	}
 
-	tangle.WriteLockSolidEntryPoints()
-	tangle.ResetSolidEntryPoints()
+	if err := checkSnapshotHistory(networkid.Unknown, snapshotIndex, 0); err != nil {
+		return err
+	}
 
 	// Genesis transaction must be marked as SEP with snapshot index during loading a global snapshot,
 	// because coordinator bootstraps the network by referencing the genesis tx
-	tangle.SolidEntryPointsAdd(hornet.NullHashBytes, snapshotIndex)
-	tangle.StoreSolidEntryPoints()
-	tangle.WriteUnlockSolidEntryPoints()
+	tangle.ReplaceSolidEntryPoints([]*tangle.SolidEntryPoint{{TxHash: hornet.NullHashBytes, Index: snapshotIndex}})
 
 	log.Infof("Importing initial ledger from %v", filePathLedger)
 
@@ -114,7 +114,7 @@ func loadSnapshotFromTextfiles(filePathLedger string, filePathsSpent []string, s
 		return errors.Wrapf(ErrSnapshotImportFailed, "snapshot ledgerEntries: %s", err)
 	}
 
-	err = tangle.StoreLedgerBalancesInDatabase(ledgerState, snapshotIndex)
+	err = tangle.Ledger().StoreLedgerBalancesInDatabase(ledgerState, snapshotIndex)
 	if err != nil {
 		return errors.Wrapf(ErrSnapshotImportFailed, "ledgerEntries: %s", err)
 	}
@@ -135,6 +135,10 @@ func loadSnapshotFromTextfiles(filePathLedger string, filePathsSpent []string, s
 	tangle.SetSnapshotMilestone(coordinatorAddress, hornet.NullHashBytes, snapshotIndex, snapshotIndex, snapshotIndex, 0, spentAddrEnabled)
 	tangle.SetLatestSeenMilestoneIndexFromSnapshot(snapshotIndex)
 
+	if err := storeSnapshotHistory(networkid.Unknown, hornet.NullHashBytes, snapshotIndex, 0, true); err != nil {
+		return err
+	}
+
 	// set the solid milestone index based on the snapshot milestone
 	tangle.SetSolidMilestoneIndex(snapshotIndex, false)
 
@@ -1,13 +1,12 @@
 package snapshot
 
 import (
-	"bufio"
 	"crypto/sha256"
 	"encoding/binary"
 	"io"
-	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
@@ -21,6 +20,8 @@ import (
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/networkid"
+	snapshotFile "github.com/gohornet/hornet/pkg/snapshot"
 	"github.com/gohornet/hornet/plugins/gossip"
 	tanglePlugin "github.com/gohornet/hornet/plugins/tangle"
 )
@@ -32,7 +33,7 @@ const (
 )
 
 var (
-	SupportedLocalSnapshotFileVersions = []byte{4}
+	SupportedLocalSnapshotFileVersions = []byte{snapshotFile.LocalSnapshotFileVersionWithoutNetworkID, snapshotFile.LocalSnapshotFileVersion}
 
 	ErrCritical                 = errors.New("critical error")
 	ErrUnsupportedLSFileVersion = errors.New("unsupported local snapshot file version")
@@ -173,9 +174,12 @@ func shouldTakeSnapshot(solidMilestoneIndex milestone.Index) bool {
 	return solidMilestoneIndex-(snapshotDepth+snapshotInterval) >= snapshotInfo.SnapshotIndex
 }
 
-func getSolidEntryPoints(targetIndex milestone.Index, abortSignal <-chan struct{}) (map[string]milestone.Index, error) {
+// getSolidEntryPoints returns the solid entry points for the given target index,
+// ordered by the milestones which reference them, starting with the oldest one.
+func getSolidEntryPoints(targetIndex milestone.Index, abortSignal <-chan struct{}) ([]*tangle.SolidEntryPoint, error) {
 
-	solidEntryPoints := make(map[string]milestone.Index)
+	var solidEntryPoints []*tangle.SolidEntryPoint
+	seenSolidEntryPoints := make(map[string]struct{})
 
 	// HINT: Check if "old solid entry points are still valid" is skipped in HORNET,
 	//		 since they should all be found by iterating the milestones to a certain depth under targetIndex, because the tipselection for COO was changed.
@@ -217,7 +221,18 @@ func getSolidEntryPoints(targetIndex milestone.Index, abortSignal <-chan struct{
 					return nil, errors.Wrap(ErrCritical, err.Error())
 				}
 
+				// the tails of an approvee are sorted to get the same order on every node
+				tailHashes := make([]string, 0, len(tails))
 				for tailHash := range tails {
+					tailHashes = append(tailHashes, tailHash)
+				}
+				sort.Strings(tailHashes)
+
+				for _, tailHash := range tailHashes {
+					if _, seen := seenSolidEntryPoints[tailHash]; seen {
+						continue
+					}
+
 					cachedTxMeta := tangle.GetCachedTxMetadataOrNil(hornet.Hash(tailHash))
 					if cachedTxMeta == nil {
 						return nil, errors.Wrapf(ErrCritical, "metadata (%v) not found!", hornet.Hash(tailHash).Trytes())
@@ -230,7 +245,8 @@ func getSolidEntryPoints(targetIndex milestone.Index, abortSignal <-chan struct{
 					}
 					cachedTxMeta.Release(true)
 
-					solidEntryPoints[tailHash] = at
+					seenSolidEntryPoints[tailHash] = struct{}{}
+					solidEntryPoints = append(solidEntryPoints, &tangle.SolidEntryPoint{TxHash: hornet.Hash(tailHash), Index: at})
 				}
 			}
 		}
@@ -303,23 +319,24 @@ func createSnapshotFile(filePath string, lsh *localSnapshotHeader, abortSignal <
 			return nil, err
 		}
 	}
-	exportFile, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0660)
+	exportFile, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0660)
 	if err != nil {
 		return nil, err
 	}
 	defer exportFile.Close()
 
-	// write into the file with an 8MB buffer
-	fileBufWriter := bufio.NewWriterSize(exportFile, 4096*2)
-
-	// write header, SEPs, seen milestones and ledger
-	// with a WRONG spent addresses count
-	if err := lsh.WriteToBuffer(fileBufWriter, abortSignal); err != nil {
+	lsWriter, err := snapshotFile.NewFileWriter(exportFile, &snapshotFile.FileHeader{
+		MilestoneHash:  lsh.msHash,
+		MilestoneIndex: lsh.msIndex,
+		Timestamp:      lsh.msTimestamp,
+		NetworkID:      networkid.FromConfig(),
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// flush remains of header and content without spent addresses to file
-	if err := fileBufWriter.Flush(); err != nil {
+	// write SEPs, seen milestones and ledger
+	if err := lsh.writeRecords(lsWriter, abortSignal); err != nil {
 		return nil, err
 	}
 
@@ -327,32 +344,21 @@ func createSnapshotFile(filePath string, lsh *localSnapshotHeader, abortSignal <
 		config.NodeConfig.GetBool(config.CfgSpentAddressesEnabled) {
 
 		// stream spent addresses into the file
-		spentAddressesCount, err := tangle.StreamSpentAddressesToWriter(fileBufWriter, abortSignal)
-		if err != nil {
-			return nil, err
-		}
-
-		if err := fileBufWriter.Flush(); err != nil {
+		if _, err := tangle.StreamSpentAddressesToConsumer(lsWriter.WriteSpentAddress, abortSignal); err != nil {
+			if err == tangle.ErrOperationAborted {
+				return nil, ErrSnapshotCreationWasAborted
+			}
 			return nil, err
 		}
+	}
 
-		if spentAddressesCount > 0 {
-			// seek to spent addresses count in the header:
-			// 1 (version) + 49 (ms hash) + 4 (ms index) + 8 (ms timestamp) +
-			// 4 (SEPs count) + 4 (seen ms count) + 4 (ledger entries) = 74
-			if _, err := exportFile.Seek(74, 0); err != nil {
-				return nil, err
-			}
-
-			// override 0 spent addresses count with actual count
-			if err := binary.Write(exportFile, binary.LittleEndian, spentAddressesCount); err != nil {
-				return nil, err
-			}
-		}
+	// flush the records and write the record counts into the header
+	if err := lsWriter.Close(); err != nil {
+		return nil, err
 	}
 
 	// seek back to the beginning of the file
-	if _, err := exportFile.Seek(0, 0); err != nil {
+	if _, err := exportFile.Seek(0, io.SeekStart); err != nil {
 		return nil, err
 	}
 
@@ -377,7 +383,10 @@ func setIsSnapshotting(value bool) {
 	statusLock.Unlock()
 }
 
-func createLocalSnapshotWithoutLocking(targetIndex milestone.Index, filePath string, writeToDatabase bool, abortSignal <-chan struct{}) error {
+// createLocalSnapshotWithoutLocking creates a snapshot for the target index.
+// If a deltaFilePath is given, a delta snapshot based on the local snapshot file is written instead of a new
+// local snapshot file, as long as the full snapshot interval was not reached.
+func createLocalSnapshotWithoutLocking(targetIndex milestone.Index, filePath string, deltaFilePath string, writeToDatabase bool, abortSignal <-chan struct{}) error {
 
 	log.Infof("creating local snapshot for targetIndex %d", targetIndex)
 
@@ -435,18 +444,30 @@ func createLocalSnapshotWithoutLocking(targetIndex milestone.Index, filePath str
 		balances:         newBalances,
 	}
 
-	filePathTmp := filePath + "_tmp"
+	var hash []byte
+	isDelta := deltaFilePath != "" && shouldTakeDeltaSnapshot(targetIndex, filePath)
+	if isDelta {
+		if hash, err = createDeltaSnapshot(targetIndex, filePath, deltaFilePath, lsh, abortSignal); err != nil {
+			return err
+		}
+	} else {
+		filePathTmp := filePath + "_tmp"
 
-	// Remove old temp file
-	os.Remove(filePathTmp)
+		// Remove old temp file
+		os.Remove(filePathTmp)
 
-	hash, err := createSnapshotFile(filePathTmp, lsh, abortSignal)
-	if err != nil {
-		return err
-	}
+		if hash, err = createSnapshotFile(filePathTmp, lsh, abortSignal); err != nil {
+			return err
+		}
 
-	if err := os.Rename(filePathTmp, filePath); err != nil {
-		return err
+		if err := os.Rename(filePathTmp, filePath); err != nil {
+			return err
+		}
+
+		if deltaFilePath != "" {
+			// the delta snapshot file is based on the replaced local snapshot file
+			os.Remove(deltaFilePath)
+		}
 	}
 
 	if writeToDatabase {
@@ -466,7 +487,13 @@ func createLocalSnapshotWithoutLocking(targetIndex milestone.Index, filePath str
 		tanglePlugin.Events.SnapshotMilestoneIndexChanged.Trigger(targetIndex)
 	}
 
-	log.Infof("created local snapshot for target index %d (sha256: %x), took %v", targetIndex, hash, time.Since(ts))
+	if isDelta {
+		log.Infof("created delta snapshot for target index %d (sha256: %x), took %v", targetIndex, hash, time.Since(ts))
+		Events.SnapshotCreated.Trigger(targetIndex, deltaFilePath, true)
+	} else {
+		log.Infof("created local snapshot for target index %d (sha256: %x), took %v", targetIndex, hash, time.Since(ts))
+		Events.SnapshotCreated.Trigger(targetIndex, filePath, false)
+	}
 
 	return nil
 }
@@ -474,66 +501,29 @@ func createLocalSnapshotWithoutLocking(targetIndex milestone.Index, filePath str
 func CreateLocalSnapshot(targetIndex milestone.Index, filePath string, writeToDatabase bool, abortSignal <-chan struct{}) error {
 	localSnapshotLock.Lock()
 	defer localSnapshotLock.Unlock()
-	return createLocalSnapshotWithoutLocking(targetIndex, filePath, writeToDatabase, abortSignal)
+	return createLocalSnapshotWithoutLocking(targetIndex, filePath, "", writeToDatabase, abortSignal)
 }
 
 type localSnapshotHeader struct {
-	msHash              hornet.Hash
-	msIndex             milestone.Index
-	msTimestamp         int64
-	solidEntryPoints    map[string]milestone.Index
-	seenMilestones      map[string]milestone.Index
-	balances            map[string]uint64
-	spentAddressesCount int32
+	msHash           hornet.Hash
+	msIndex          milestone.Index
+	msTimestamp      int64
+	solidEntryPoints []*tangle.SolidEntryPoint
+	seenMilestones   map[string]milestone.Index
+	balances         map[string]uint64
 }
 
-func (ls *localSnapshotHeader) WriteToBuffer(buf io.Writer, abortSignal <-chan struct{}) error {
-	var err error
-
-	if err = binary.Write(buf, binary.LittleEndian, SupportedLocalSnapshotFileVersions[0]); err != nil {
-		return err
-	}
-
-	if err = binary.Write(buf, binary.LittleEndian, ls.msHash[:49]); err != nil {
-		return err
-	}
-
-	if err = binary.Write(buf, binary.LittleEndian, ls.msIndex); err != nil {
-		return err
-	}
-
-	if err = binary.Write(buf, binary.LittleEndian, ls.msTimestamp); err != nil {
-		return err
-	}
-
-	if err = binary.Write(buf, binary.LittleEndian, int32(len(ls.solidEntryPoints))); err != nil {
-		return err
-	}
-
-	if err = binary.Write(buf, binary.LittleEndian, int32(len(ls.seenMilestones))); err != nil {
-		return err
-	}
-
-	if err = binary.Write(buf, binary.LittleEndian, int32(len(ls.balances))); err != nil {
-		return err
-	}
-
-	if err = binary.Write(buf, binary.LittleEndian, ls.spentAddressesCount); err != nil {
-		return err
-	}
+// writeRecords writes the solid entry points, seen milestones and balances of the snapshot.
+func (ls *localSnapshotHeader) writeRecords(lsWriter *snapshotFile.FileWriter, abortSignal <-chan struct{}) error {
 
-	for hash, val := range ls.solidEntryPoints {
+	for _, solidEntryPoint := range ls.solidEntryPoints {
 		select {
 		case <-abortSignal:
 			return ErrSnapshotCreationWasAborted
 		default:
 		}
 
-		if err = binary.Write(buf, binary.LittleEndian, hornet.Hash(hash)[:49]); err != nil {
-			return err
-		}
-
-		if err = binary.Write(buf, binary.LittleEndian, val); err != nil {
+		if err := lsWriter.WriteSolidEntryPoint(solidEntryPoint.TxHash, solidEntryPoint.Index); err != nil {
 			return err
 		}
 	}
@@ -545,11 +535,7 @@ func (ls *localSnapshotHeader) WriteToBuffer(buf io.Writer, abortSignal <-chan s
 		default:
 		}
 
-		if err = binary.Write(buf, binary.LittleEndian, hornet.Hash(hash)[:49]); err != nil {
-			return err
-		}
-
-		if err = binary.Write(buf, binary.LittleEndian, val); err != nil {
+		if err := lsWriter.WriteSeenMilestone(hornet.Hash(hash), val); err != nil {
 			return err
 		}
 	}
@@ -561,11 +547,7 @@ func (ls *localSnapshotHeader) WriteToBuffer(buf io.Writer, abortSignal <-chan s
 		default:
 		}
 
-		if err = binary.Write(buf, binary.LittleEndian, hornet.Hash(addr)[:49]); err != nil {
-			return err
-		}
-
-		if err = binary.Write(buf, binary.LittleEndian, val); err != nil {
+		if err := lsWriter.WriteLedgerEntry(hornet.Hash(addr), val); err != nil {
 			return err
 		}
 	}
@@ -582,133 +564,106 @@ func LoadSnapshotFromFile(filePath string) error {
 	}
 	defer file.Close()
 
-	// check file version
-	var fileVersion byte
-	if err := binary.Read(file, binary.LittleEndian, &fileVersion); err != nil {
-		return err
-	}
-
-	var supported bool
-	for _, v := range SupportedLocalSnapshotFileVersions {
-		if v == fileVersion {
-			supported = true
-			break
-		}
-	}
-	if !supported {
-		return errors.Wrapf(ErrUnsupportedLSFileVersion, "local snapshot file version is %d but this HORNET version only supports %v", fileVersion, SupportedLocalSnapshotFileVersions)
-	}
-
-	msHash := make(hornet.Hash, 49)
-	if _, err := file.Read(msHash); err != nil {
-		return err
-	}
-
-	tangle.WriteLockSolidEntryPoints()
-	tangle.ResetSolidEntryPoints()
-
-	var msIndex int32
-	var msTimestamp int64
-	var solidEntryPointsCount, seenMilestonesCount, ledgerEntriesCount, spentAddrsCount int32
-
-	if err := binary.Read(file, binary.LittleEndian, &msIndex); err != nil {
-		return err
-	}
+	var header *snapshotFile.ReadFileHeader
+	var newSolidEntryPoints []*tangle.SolidEntryPoint
+	seenMilestones := make(map[string]milestone.Index)
+	ledgerState := make(map[string]uint64)
+	var spentAddrsImported int32
 
-	if err := binary.Read(file, binary.LittleEndian, &msTimestamp); err != nil {
-		return err
-	}
+	headerConsumer := func(readHeader *snapshotFile.ReadFileHeader) error {
+		header = readHeader
 
-	if err := binary.Read(file, binary.LittleEndian, &solidEntryPointsCount); err != nil {
-		return err
-	}
+		if err := checkNetworkID(header.NetworkID); err != nil {
+			return err
+		}
 
-	if err := binary.Read(file, binary.LittleEndian, &seenMilestonesCount); err != nil {
-		return err
-	}
+		if err := checkSnapshotHistory(header.NetworkID, header.MilestoneIndex, header.Timestamp); err != nil {
+			return err
+		}
 
-	if err := binary.Read(file, binary.LittleEndian, &ledgerEntriesCount); err != nil {
-		return err
-	}
+		coordinatorAddress := hornet.HashFromAddressTrytes(config.NodeConfig.GetString(config.CfgCoordinatorAddress))
+		tangle.SetSnapshotMilestone(coordinatorAddress, header.MilestoneHash, header.MilestoneIndex, header.MilestoneIndex, header.MilestoneIndex, header.Timestamp, header.SpentAddressesCount != 0 && config.NodeConfig.GetBool(config.CfgSpentAddressesEnabled))
+		newSolidEntryPoints = []*tangle.SolidEntryPoint{{TxHash: header.MilestoneHash, Index: header.MilestoneIndex}}
+		tangle.SetLatestSeenMilestoneIndexFromSnapshot(header.MilestoneIndex)
 
-	if err := binary.Read(file, binary.LittleEndian, &spentAddrsCount); err != nil {
-		return err
+		log.Info("importing solid entry points")
+		return nil
 	}
 
-	coordinatorAddress := hornet.HashFromAddressTrytes(config.NodeConfig.GetString(config.CfgCoordinatorAddress))
-	tangle.SetSnapshotMilestone(coordinatorAddress, msHash, milestone.Index(msIndex), milestone.Index(msIndex), milestone.Index(msIndex), msTimestamp, spentAddrsCount != 0 && config.NodeConfig.GetBool("spentAddresses.enabled"))
-	tangle.SolidEntryPointsAdd(msHash, milestone.Index(msIndex))
-	tangle.SetLatestSeenMilestoneIndexFromSnapshot(milestone.Index(msIndex))
-
-	log.Info("importing solid entry points")
-
-	for i := 0; i < int(solidEntryPointsCount); i++ {
+	sepConsumer := func(txHash hornet.Hash, index milestone.Index) error {
 		if daemon.IsStopped() {
 			return ErrSnapshotImportWasAborted
 		}
 
-		var val int32
-		txHashBuf := make(hornet.Hash, 49)
-
-		if err := binary.Read(file, binary.LittleEndian, txHashBuf); err != nil {
-			return errors.Wrapf(ErrSnapshotImportFailed, "solidEntryPoints: %v", err)
-		}
+		// duplicates are ignored by ReplaceSolidEntryPoints
+		newSolidEntryPoints = append(newSolidEntryPoints, &tangle.SolidEntryPoint{TxHash: txHash, Index: index})
+		return nil
+	}
 
-		if err := binary.Read(file, binary.LittleEndian, &val); err != nil {
-			return errors.Wrapf(ErrSnapshotImportFailed, "solidEntryPoints: %v", err)
+	seenMilestoneConsumer := func(msHash hornet.Hash, index milestone.Index) error {
+		if daemon.IsStopped() {
+			return ErrSnapshotImportWasAborted
 		}
 
-		tangle.SolidEntryPointsAdd(txHashBuf, milestone.Index(val))
+		tangle.SetLatestSeenMilestoneIndexFromSnapshot(index)
+		seenMilestones[string(msHash)] = index
+		return nil
 	}
 
-	tangle.StoreSolidEntryPoints()
-	tangle.WriteUnlockSolidEntryPoints()
-
-	log.Info("importing seen milestones")
-
-	for i := 0; i < int(seenMilestonesCount); i++ {
+	ledgerEntryConsumer := func(address hornet.Hash, balance uint64) error {
 		if daemon.IsStopped() {
 			return ErrSnapshotImportWasAborted
 		}
 
-		var val int32
-		txHashBuf := make(hornet.Hash, 49)
+		ledgerState[string(address)] = balance
+		return nil
+	}
 
-		if err := binary.Read(file, binary.LittleEndian, txHashBuf); err != nil {
-			return errors.Wrapf(ErrSnapshotImportFailed, "seenMilestones: %v", err)
-		}
+	var spentAddressConsumer snapshotFile.SpentAddressConsumerFunc
+	if config.NodeConfig.GetBool(config.CfgSpentAddressesEnabled) {
+		spentAddressConsumer = func(address hornet.Hash) error {
+			if spentAddrsImported == 0 {
+				log.Infof("importing %d spent addresses. this can take a while...", header.SpentAddressesCount)
+			}
 
-		if err := binary.Read(file, binary.LittleEndian, &val); err != nil {
-			return errors.Wrapf(ErrSnapshotImportFailed, "seenMilestones: %v", err)
-		}
+			tangle.MarkAddressAsSpentWithoutLocking(address)
+			spentAddrsImported++
 
-		tangle.SetLatestSeenMilestoneIndexFromSnapshot(milestone.Index(val))
-		// request the milestone and prevent the request from being discarded from the request queue
-		gossip.Request(txHashBuf, milestone.Index(val), true)
+			if spentAddrsImported%SpentAddressesImportBatchSize == 0 || spentAddrsImported == header.SpentAddressesCount {
+				if daemon.IsStopped() {
+					return ErrSnapshotImportWasAborted
+				}
+				log.Infof("processed %d/%d spent addresses", spentAddrsImported, header.SpentAddressesCount)
+			}
+			return nil
+		}
 	}
 
-	log.Info("importing ledger state")
-
-	ledgerState := make(map[string]uint64)
-	for i := 0; i < int(ledgerEntriesCount); i++ {
-		if daemon.IsStopped() {
-			return ErrSnapshotImportWasAborted
+	if err := snapshotFile.StreamLocalSnapshotDataFrom(file, headerConsumer, sepConsumer, seenMilestoneConsumer, ledgerEntryConsumer, spentAddressConsumer); err != nil {
+		switch {
+		case err == ErrSnapshotImportWasAborted,
+			errors.Is(err, ErrWrongNetworkID),
+			errors.Is(err, tangle.ErrSnapshotDowngrade),
+			errors.Is(err, tangle.ErrForeignSnapshot):
+			return err
+		case errors.Is(err, snapshotFile.ErrUnsupportedFileVersion):
+			return errors.Wrap(ErrUnsupportedLSFileVersion, err.Error())
+		default:
+			return errors.Wrapf(ErrSnapshotImportFailed, "%v", err)
 		}
+	}
 
-		var val uint64
-		addrBuf := make(hornet.Hash, 49)
-
-		if err := binary.Read(file, binary.LittleEndian, addrBuf); err != nil {
-			return errors.Wrapf(ErrSnapshotImportFailed, "ledgerEntries: %v", err)
-		}
+	tangle.ReplaceSolidEntryPoints(newSolidEntryPoints)
 
-		if err := binary.Read(file, binary.LittleEndian, &val); err != nil {
-			return errors.Wrapf(ErrSnapshotImportFailed, "ledgerEntries: %v", err)
-		}
+	log.Info("importing seen milestones")
 
-		ledgerState[string(addrBuf)] = val
+	for msHash, index := range seenMilestones {
+		// request the milestone and prevent the request from being discarded from the request queue
+		gossip.Request(hornet.Hash(msHash), index, true)
 	}
 
+	log.Info("importing ledger state")
+
 	var total uint64
 	for _, value := range ledgerState {
 		total += value
@@ -718,53 +673,26 @@ func LoadSnapshotFromFile(filePath string) error {
 		return errors.Wrapf(ErrInvalidBalance, "%d != %d", total, consts.TotalSupply)
 	}
 
-	err = tangle.StoreSnapshotBalancesInDatabase(ledgerState, milestone.Index(msIndex))
+	err = tangle.StoreSnapshotBalancesInDatabase(ledgerState, header.MilestoneIndex)
 	if err != nil {
 		return errors.Wrapf(ErrSnapshotImportFailed, "snapshot ledgerEntries: %s", err)
 	}
 
-	err = tangle.StoreLedgerBalancesInDatabase(ledgerState, milestone.Index(msIndex))
+	err = tangle.Ledger().StoreLedgerBalancesInDatabase(ledgerState, header.MilestoneIndex)
 	if err != nil {
 		return errors.Wrapf(ErrSnapshotImportFailed, "ledgerEntries: %v", err)
 	}
 
-	if config.NodeConfig.GetBool(config.CfgSpentAddressesEnabled) {
-		log.Infof("importing %d spent addresses. this can take a while...", spentAddrsCount)
-
-		batchAmount := int(math.Ceil(float64(spentAddrsCount) / float64(SpentAddressesImportBatchSize)))
-		for i := 0; i < batchAmount; i++ {
-			if daemon.IsStopped() {
-				return ErrSnapshotImportWasAborted
-			}
-
-			batchStart := int32(i * SpentAddressesImportBatchSize)
-			batchEnd := batchStart + SpentAddressesImportBatchSize
-
-			if batchEnd > spentAddrsCount {
-				batchEnd = spentAddrsCount
-			}
-
-			for j := batchStart; j < batchEnd; j++ {
-
-				spentAddrBuf := make(hornet.Hash, 49)
-				err = binary.Read(file, binary.BigEndian, spentAddrBuf)
-				if err != nil {
-					return errors.Wrapf(ErrSnapshotImportFailed, "spentAddrs: %v", err)
-				}
-
-				tangle.MarkAddressAsSpentWithoutLocking(spentAddrBuf)
-			}
-
-			log.Infof("processed %d/%d spent addresses", batchEnd, spentAddrsCount)
-		}
+	if err := storeSnapshotHistory(header.NetworkID, header.MilestoneHash, header.MilestoneIndex, header.Timestamp, true); err != nil {
+		return err
 	}
 
 	// set the solid milestone index based on the snapshot milestone
-	tangle.SetSolidMilestoneIndex(milestone.Index(msIndex), false)
+	tangle.SetSolidMilestoneIndex(header.MilestoneIndex, false)
 
 	log.Info("finished loading snapshot")
 
-	tanglePlugin.Events.SnapshotMilestoneIndexChanged.Trigger(milestone.Index(msIndex))
+	tanglePlugin.Events.SnapshotMilestoneIndexChanged.Trigger(header.MilestoneIndex)
 
 	return nil
 }
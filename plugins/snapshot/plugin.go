@@ -2,11 +2,13 @@ package snapshot
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/dustin/go-humanize"
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 
@@ -17,9 +19,11 @@ import (
 	"github.com/iotaledger/hive.go/syncutils"
 
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/networkid"
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/plugins/gossip"
 	tanglePlugin "github.com/gohornet/hornet/plugins/tangle"
@@ -37,6 +41,7 @@ var (
 	ErrSnapshotDownloadWasAborted      = errors.New("snapshot download was aborted")
 	ErrSnapshotDownloadNoValidSource   = errors.New("no valid source found, snapshot download not possible")
 	ErrSnapshotImportWasAborted        = errors.New("snapshot import was aborted")
+	ErrInvalidSnapshotFile             = errors.New("invalid snapshot file")
 	ErrSnapshotImportFailed            = errors.New("snapshot import failed")
 	ErrSnapshotCreationWasAborted      = errors.New("operation was aborted")
 	ErrSnapshotCreationFailed          = errors.New("creating snapshot failed")
@@ -48,6 +53,7 @@ var (
 	ErrUnconfirmedTxInSubtangle        = errors.New("unconfirmed tx in subtangle")
 	ErrInvalidBalance                  = errors.New("invalid balance! total does not match supply:")
 	ErrWrongCoordinatorAddressDatabase = errors.New("configured coordinator address does not match database information")
+	ErrWrongNetworkID                  = errors.New("snapshot file belongs to another network")
 
 	localSnapshotLock       = syncutils.Mutex{}
 	newSolidMilestoneSignal = make(chan milestone.Index)
@@ -55,9 +61,13 @@ var (
 	snapshotDepth            milestone.Index
 	snapshotIntervalSynced   milestone.Index
 	snapshotIntervalUnsynced milestone.Index
+	fullSnapshotInterval     milestone.Index
+	deltaSnapshotPath        string
 
-	pruningEnabled bool
-	pruningDelay   milestone.Index
+	pruningEnabled            bool
+	pruningDelay              milestone.Index
+	pruningTargetDatabaseSize int64
+	pruneAddressHistory       bool
 
 	statusLock     syncutils.RWMutex
 	isSnapshotting bool
@@ -65,7 +75,7 @@ var (
 )
 
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
 	snapshotDepth = milestone.Index(config.NodeConfig.GetInt(config.CfgLocalSnapshotsDepth))
 	if snapshotDepth < SolidEntryPointCheckThresholdFuture {
@@ -74,6 +84,8 @@ func configure(plugin *node.Plugin) {
 	}
 	snapshotIntervalSynced = milestone.Index(config.NodeConfig.GetInt(config.CfgLocalSnapshotsIntervalSynced))
 	snapshotIntervalUnsynced = milestone.Index(config.NodeConfig.GetInt(config.CfgLocalSnapshotsIntervalUnsynced))
+	fullSnapshotInterval = milestone.Index(config.NodeConfig.GetInt(config.CfgLocalSnapshotsFullInterval))
+	deltaSnapshotPath = config.NodeConfig.GetString(config.CfgLocalSnapshotsDeltaPath)
 
 	pruningEnabled = config.NodeConfig.GetBool(config.CfgPruningEnabled)
 	pruningDelay = milestone.Index(config.NodeConfig.GetInt(config.CfgPruningDelay))
@@ -83,6 +95,17 @@ func configure(plugin *node.Plugin) {
 		pruningDelay = pruningDelayMin
 	}
 
+	if targetDatabaseSize := config.NodeConfig.GetString(config.CfgPruningTargetDatabaseSize); targetDatabaseSize != "" {
+		size, err := humanize.ParseBytes(targetDatabaseSize)
+		if err != nil {
+			log.Fatalf("Parameter '%s' is invalid: %v", config.CfgPruningTargetDatabaseSize, err)
+		}
+		pruningTargetDatabaseSize = int64(size)
+	}
+
+	pruneAddressHistory = tangle.IsAddressHistoryEnabled() && !config.NodeConfig.GetBool(config.CfgDatabaseAddressHistoryKeepPruned)
+	loadRetentionRules()
+
 	gossip.AddRequestBackpressureSignal(isSnapshottingOrPruning)
 
 	snapshotInfo := tangle.GetSnapshotInfo()
@@ -103,7 +126,7 @@ func configure(plugin *node.Plugin) {
 		if !*forceGlobalSnapshot {
 			// If we don't enforce loading of a global snapshot,
 			// we can check the ledger state of current database and start the node.
-			tangle.GetLedgerStateForLSMI(nil)
+			tangle.Ledger().GetLedgerStateForLSMI(nil)
 			return
 		}
 	}
@@ -145,13 +168,32 @@ func configure(plugin *node.Plugin) {
 			}
 
 			err = LoadSnapshotFromFile(path)
+			if err != nil {
+				break
+			}
+
+			if deltaSnapshotPath != "" {
+				if _, fileErr := os.Stat(deltaSnapshotPath); fileErr == nil {
+					if deltaErr := LoadDeltaSnapshotFromFile(deltaSnapshotPath); deltaErr != nil {
+						if !errors.Is(deltaErr, ErrDeltaSnapshotMismatch) && !errors.Is(deltaErr, tangle.ErrSnapshotDowngrade) {
+							err = deltaErr
+							break
+						}
+						// the node can still start from the local snapshot file and sync the missing milestones
+						log.Warn(deltaErr)
+					}
+				}
+			}
 		}
 	default:
 		log.Fatalf("invalid snapshot type under config option '%s': %s", config.CfgSnapshotLoadType, config.NodeConfig.GetString(config.CfgSnapshotLoadType))
 	}
 
 	if err != nil {
-		tangle.MarkDatabaseCorrupted()
+		// refused snapshots were rejected before anything was written to the database
+		if !errors.Is(err, tangle.ErrSnapshotDowngrade) && !errors.Is(err, tangle.ErrForeignSnapshot) {
+			tangle.MarkDatabaseCorrupted()
+		}
 		log.Panic(err.Error())
 	}
 }
@@ -189,7 +231,7 @@ func run(_ *node.Plugin) {
 
 				if shouldTakeSnapshot(solidMilestoneIndex) {
 					localSnapshotPath := config.NodeConfig.GetString(config.CfgLocalSnapshotsPath)
-					if err := createLocalSnapshotWithoutLocking(solidMilestoneIndex-snapshotDepth, localSnapshotPath, true, shutdownSignal); err != nil {
+					if err := createLocalSnapshotWithoutLocking(solidMilestoneIndex-snapshotDepth, localSnapshotPath, deltaSnapshotPath, true, shutdownSignal); err != nil {
 						if errors.Is(err, ErrCritical) {
 							log.Panic(errors.Wrap(ErrSnapshotCreationFailed, err.Error()))
 						}
@@ -198,14 +240,16 @@ func run(_ *node.Plugin) {
 				}
 
 				if pruningEnabled {
-					if solidMilestoneIndex <= pruningDelay {
-						// Not enough history
-						localSnapshotLock.Unlock()
-						continue
+					if solidMilestoneIndex > pruningDelay {
+						if err := pruneDatabase(solidMilestoneIndex-pruningDelay, shutdownSignal); err != nil {
+							log.Debugf("pruning aborted: %v", err.Error())
+						}
 					}
 
-					if err := pruneDatabase(solidMilestoneIndex-pruningDelay, shutdownSignal); err != nil {
-						log.Debugf("pruning aborted: %v", err.Error())
+					if pruningTargetDatabaseSize != 0 {
+						if err := pruneDatabaseBySize(pruningTargetDatabaseSize, shutdownSignal); err != nil {
+							log.Debugf("pruning by database size aborted: %v", err.Error())
+						}
 					}
 				}
 
@@ -213,6 +257,8 @@ func run(_ *node.Plugin) {
 			}
 		}
 	}, shutdown.PriorityLocalSnapshots)
+
+	runUnsolidifiableCleanup()
 }
 
 func PruneDatabaseByDepth(depth milestone.Index) error {
@@ -235,3 +281,37 @@ func PruneDatabaseByTargetIndex(targetIndex milestone.Index) error {
 
 	return pruneDatabase(targetIndex, nil)
 }
+
+// checkNetworkID checks whether a snapshot file with the given network ID belongs to the network of the node.
+// Files of older versions don't contain a network ID and are accepted.
+func checkNetworkID(fileNetworkID networkid.ID) error {
+	if ownNetworkID := networkid.FromConfig(); !ownNetworkID.Matches(fileNetworkID) {
+		return errors.Wrapf(ErrWrongNetworkID, "network ID of the file is %s, network ID of the node is %s", fileNetworkID, ownNetworkID)
+	}
+	return nil
+}
+
+// checkSnapshotHistory checks whether loading a snapshot with the given metadata would roll back the ledger of the database.
+func checkSnapshotHistory(fileNetworkID networkid.ID, msIndex milestone.Index, timestamp int64) error {
+	history, err := tangle.ReadSnapshotHistory()
+	if err != nil {
+		return err
+	}
+	return history.Check(fileNetworkID, msIndex, timestamp, tangle.Ledger().MilestoneIndex())
+}
+
+// storeSnapshotHistory adds the loaded snapshot to the snapshot history of the database.
+func storeSnapshotHistory(fileNetworkID networkid.ID, msHash hornet.Hash, msIndex milestone.Index, timestamp int64, fullSnapshot bool) error {
+	history, err := tangle.ReadSnapshotHistory()
+	if err != nil {
+		return err
+	}
+
+	history = history.Extend(fileNetworkID, msHash, msIndex, timestamp, fullSnapshot)
+	if err := tangle.StoreSnapshotHistory(history); err != nil {
+		return errors.Wrapf(ErrSnapshotImportFailed, "snapshot history: %v", err)
+	}
+
+	log.Infof("snapshot generation %d, full snapshot chain %s", history.Generation, hex.EncodeToString(history.FullSnapshotChain))
+	return nil
+}
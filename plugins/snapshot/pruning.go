@@ -6,7 +6,6 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/gohornet/hornet/pkg/dag"
-	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/plugins/database"
@@ -17,6 +16,13 @@ const (
 	// AdditionalPruningThreshold is needed, because the transactions in the getMilestoneApprovees call in getSolidEntryPoints
 	// can reference older transactions as well
 	AdditionalPruningThreshold = 50
+
+	// MetadataPruningBatchSize is the amount of transactions of a pruned milestone's cone deleted in one batch
+	MetadataPruningBatchSize = 1000
+
+	// DatabaseSizePruningStep is the amount of milestones pruned at once if the database exceeds the target size.
+	// It has to be bigger than AdditionalPruningThreshold, because the solid entry points are recalculated in these steps.
+	DatabaseSizePruningStep = 2 * AdditionalPruningThreshold
 )
 
 // pruneUnconfirmedTransactions prunes all unconfirmed tx from the database for the given milestone
@@ -65,6 +71,7 @@ loopOverUnconfirmed:
 
 	txCountDeleted = pruneTransactions(txsToCheckMap)
 	tangle.DeleteUnconfirmedTxs(targetIndex)
+	tangle.DeleteTransactionTypes(targetIndex)
 
 	return txCountDeleted, len(txsToCheckMap)
 }
@@ -73,53 +80,17 @@ loopOverUnconfirmed:
 func pruneMilestone(milestoneIndex milestone.Index) {
 
 	// state diffs
-	if err := tangle.DeleteLedgerDiffForMilestone(milestoneIndex); err != nil {
+	if err := tangle.Ledger().DeleteLedgerDiffForMilestone(milestoneIndex); err != nil {
 		log.Warn(err)
 	}
 
 	tangle.DeleteMilestone(milestoneIndex)
 }
 
-// pruneTransactions prunes the approvers, bundles, bundle txs, addresses, tags and transaction metadata from the database
+// pruneTransactions prunes the approvers, bundles, bundle txs, addresses, address history, tags and transaction metadata from the database
 func pruneTransactions(txsToCheckMap map[string]struct{}) int {
-
-	txsToDeleteMap := make(map[string]struct{})
-
-	for txHashToCheck := range txsToCheckMap {
-
-		cachedTxMeta := tangle.GetCachedTxMetadataOrNil(hornet.Hash(txHashToCheck)) // tx +1
-		if cachedTxMeta == nil {
-			log.Warnf("pruneTransactions: Transaction not found: %s", txHashToCheck)
-			continue
-		}
-
-		for txToRemove := range tangle.RemoveTransactionFromBundle(cachedTxMeta.GetMetadata()) {
-			txsToDeleteMap[txToRemove] = struct{}{}
-		}
-		// since it gets loaded below again it doesn't make sense to force release here
-		cachedTxMeta.Release() // tx -1
-	}
-
-	for txHashToDelete := range txsToDeleteMap {
-
-		cachedTx := tangle.GetCachedTransactionOrNil(hornet.Hash(txHashToDelete)) // tx +1
-		if cachedTx == nil {
-			continue
-		}
-
-		cachedTx.ConsumeTransaction(func(tx *hornet.Transaction) { // tx -1
-			// Delete the reference in the approvees
-			tangle.DeleteApprover(tx.GetTrunkHash(), tx.GetTxHash())
-			tangle.DeleteApprover(tx.GetBranchHash(), tx.GetTxHash())
-
-			tangle.DeleteTag(tx.GetTag(), tx.GetTxHash())
-			tangle.DeleteAddress(tx.GetAddress(), tx.GetTxHash())
-			tangle.DeleteApprovers(tx.GetTxHash())
-			tangle.DeleteTransaction(tx.GetTxHash())
-		})
-	}
-
-	return len(txsToDeleteMap)
+	txCountDeleted, _ := tangle.DeleteTransactions(txsToCheckMap, pruneAddressHistory, 0, nil, nil)
+	return txCountDeleted
 }
 
 func setIsPruning(value bool) {
@@ -158,95 +129,97 @@ func pruneDatabase(targetIndex milestone.Index, abortSignal <-chan struct{}) err
 	setIsPruning(true)
 	defer setIsPruning(false)
 
+	Events.PruningStarted.Trigger(targetIndex)
+	defer func() { Events.PruningFinished.Trigger(snapshotInfo.PruningIndex) }()
+
 	// calculate solid entry points for the new end of the tangle history
 	newSolidEntryPoints, err := getSolidEntryPoints(targetIndex, abortSignal)
 	if err != nil {
 		return err
 	}
 
-	tangle.WriteLockSolidEntryPoints()
-	tangle.ResetSolidEntryPoints()
-	for solidEntryPoint, index := range newSolidEntryPoints {
-		tangle.SolidEntryPointsAdd(hornet.Hash(solidEntryPoint), index)
-	}
-	tangle.StoreSolidEntryPoints()
-	tangle.WriteUnlockSolidEntryPoints()
+	tangle.ReplaceSolidEntryPoints(newSolidEntryPoints)
 
 	// we have to set the new solid entry point index.
 	// this way we can cleanly prune even if the pruning was aborted last time
 	snapshotInfo.EntryPointIndex = targetIndex
 	tangle.SetSnapshotInfo(snapshotInfo)
 
+	retainedTxs, err := tangle.GetRetainedTransactions()
+	if err != nil {
+		return err
+	}
+
 	// unconfirmed txs have to be pruned for PruningIndex as well, since this could be LSI at startup of the node
 	pruneUnconfirmedTransactions(snapshotInfo.PruningIndex)
 
-	// Iterate through all milestones that have to be pruned
-	for milestoneIndex := snapshotInfo.PruningIndex + 1; milestoneIndex <= targetIndex; milestoneIndex++ {
-		select {
-		case <-abortSignal:
-			// Stop pruning the next milestone
-			return ErrPruningAborted
-		default:
-		}
+	// the transactions of the pruned milestones' cones are found by walking the cones
+	ts := time.Now()
+	var pruningMilestoneIndex milestone.Index
+	err = dag.DeleteTransactionMetadataBelowIndex(snapshotInfo.PruningIndex+1, targetIndex+1, pruneAddressHistory, MetadataPruningBatchSize,
+		// keep the bundles which are retained because of the retention rules
+		func(msIndex milestone.Index, txHashes map[string]struct{}) {
+			pruningMilestoneIndex = msIndex
+			log.Infof("Pruning milestone (%d)...", msIndex)
+
+			if retainedCount := retainBundles(txHashes, retainedTxs, tangle.GetMilestoneTimestamp(msIndex)); retainedCount > 0 {
+				log.Infof("Pruning milestone (%d): retained %d bundles because of the retention rules", msIndex, retainedCount)
+			}
+		},
+		func(deletedCount int, totalCount int) {
+			log.Debugf("Pruning milestone (%d): %d/%d transactions", pruningMilestoneIndex, deletedCount, totalCount)
+		},
+		func(msIndex milestone.Index, txCountDeleted int, txCountChecked int, err error) {
+			defer func() { ts = time.Now() }()
+
+			unconfirmedCountDeleted, unconfirmedCountChecked := pruneUnconfirmedTransactions(msIndex)
+
+			if err != nil {
+				log.Warnf("Pruning milestone (%d) failed! Error: %v", msIndex, err)
+				return
+			}
 
-		log.Infof("Pruning milestone (%d)...", milestoneIndex)
+			pruneMilestone(msIndex)
 
-		ts := time.Now()
-		txCountDeleted, txCountChecked := pruneUnconfirmedTransactions(milestoneIndex)
+			snapshotInfo.PruningIndex = msIndex
+			tangle.SetSnapshotInfo(snapshotInfo)
 
-		cachedMs := tangle.GetCachedMilestoneOrNil(milestoneIndex) // milestone +1
-		if cachedMs == nil {
-			// Milestone not found, pruning impossible
-			log.Warnf("Pruning milestone (%d) failed! Milestone not found!", milestoneIndex)
-			continue
-		}
+			log.Infof("Pruning milestone (%d) took %v. Pruned %d/%d transactions. ", msIndex, time.Since(ts), txCountDeleted+unconfirmedCountDeleted, txCountChecked+unconfirmedCountChecked)
 
-		txsToCheckMap := make(map[string]struct{})
-
-		err := dag.TraverseApprovees(cachedMs.GetMilestone().Hash,
-			// traversal stops if no more transactions pass the given condition
-			// Caution: condition func is not in DFS order
-			func(cachedTxMeta *tangle.CachedMetadata) (bool, error) { // tx +1
-				defer cachedTxMeta.Release(true) // tx -1
-				// everything that was referenced by that milestone can be pruned (even transactions of older milestones)
-				return true, nil
-			},
-			// consumer
-			func(cachedTxMeta *tangle.CachedMetadata) error { // tx +1
-				defer cachedTxMeta.Release(true) // tx -1
-				txsToCheckMap[string(cachedTxMeta.GetMetadata().GetTxHash())] = struct{}{}
-				return nil
-			},
-			// called on missing approvees
-			func(approveeHash hornet.Hash) error { return nil },
-			// called on solid entry points
-			// Ignore solid entry points (snapshot milestone included)
-			nil,
-			// the pruning target index is also a solid entry point => traverse it anyways
-			true,
-			false,
-			nil)
-
-		cachedMs.Release(true) // milestone -1
-		if err != nil {
-			log.Warnf("Pruning milestone (%d) failed! Error: %v", milestoneIndex, err)
-			continue
-		}
+			tanglePlugin.Events.PruningMilestoneIndexChanged.Trigger(msIndex)
+			Events.PruningProgress.Trigger(msIndex, targetIndex)
+		}, abortSignal)
+	if err != nil {
+		// the milestone is pruned again next time, since the pruning index was not updated
+		return ErrPruningAborted
+	}
 
-		txCountChecked += len(txsToCheckMap)
-		txCountDeleted += pruneTransactions(txsToCheckMap)
+	if txCountDeleted, bundleCount := pruneExpiredRetainedBundles(); bundleCount > 0 {
+		log.Infof("Pruned %d retained bundles whose retention expired. Pruned %d transactions.", bundleCount, txCountDeleted)
+	}
 
-		pruneMilestone(milestoneIndex)
+	database.RunGarbageCollection()
 
-		snapshotInfo.PruningIndex = milestoneIndex
-		tangle.SetSnapshotInfo(snapshotInfo)
+	return nil
+}
 
-		log.Infof("Pruning milestone (%d) took %v. Pruned %d/%d transactions. ", milestoneIndex, time.Since(ts), txCountDeleted, txCountChecked)
+// pruneDatabaseBySize prunes the next DatabaseSizePruningStep milestones if the live data of the database is bigger than the target size.
+// The pruning is still limited by the snapshot index, so the solid entry points of the local snapshot stay available.
+// The live data doesn't include the space freed by pruning, since the bolt files never shrink.
+// The files have to be compacted via the db-migration tool to release that space.
+func pruneDatabaseBySize(targetDatabaseSize int64, abortSignal <-chan struct{}) error {
 
-		tanglePlugin.Events.PruningMilestoneIndexChanged.Trigger(milestoneIndex)
+	databaseSize := tangle.GetDatabaseLiveSize()
+	if databaseSize <= targetDatabaseSize {
+		return errors.Wrapf(ErrNoPruningNeeded, "database size: %d, target size: %d", databaseSize, targetDatabaseSize)
 	}
 
-	database.RunGarbageCollection()
+	snapshotInfo := tangle.GetSnapshotInfo()
+	if snapshotInfo == nil {
+		log.Panic("No snapshotInfo found!")
+	}
 
-	return nil
+	log.Infof("Database live size (%d bytes) exceeds the target size (%d bytes)", databaseSize, targetDatabaseSize)
+
+	return pruneDatabase(snapshotInfo.PruningIndex+DatabaseSizePruningStep, abortSignal)
 }
@@ -0,0 +1,167 @@
+package snapshot
+
+import (
+	"strings"
+	"time"
+
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/guards"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+)
+
+// retentionRule keeps the bundles with a matching tag for the given amount of days after their confirmation,
+// even if the milestone which confirmed them is pruned.
+type retentionRule struct {
+	// Tag is matched against the beginning of the tags of the transactions of a bundle.
+	Tag  string `mapstructure:"tag"`
+	Days int    `mapstructure:"days"`
+}
+
+var (
+	retentionRules []*retentionRule
+)
+
+// loadRetentionRules reads the retention rules from the node config.
+func loadRetentionRules() {
+	if !config.NodeConfig.IsSet(config.CfgPruningRetentionRules) {
+		return
+	}
+
+	if err := config.NodeConfig.UnmarshalKey(config.CfgPruningRetentionRules, &retentionRules); err != nil {
+		log.Fatalf("Parameter '%s' is invalid: %v", config.CfgPruningRetentionRules, err)
+	}
+
+	for _, rule := range retentionRules {
+		if rule.Tag == "" || !guards.IsTrytesOfMaxLength(rule.Tag, consts.TagTrinarySize/3) {
+			log.Fatalf("Parameter '%s' is invalid: tag '%s' is no valid tag", config.CfgPruningRetentionRules, rule.Tag)
+		}
+		if rule.Days <= 0 {
+			log.Fatalf("Parameter '%s' is invalid: days of tag '%s' must be greater than 0", config.CfgPruningRetentionRules, rule.Tag)
+		}
+	}
+}
+
+// retentionDuration returns the longest retention of the rules matching one of the tags, 0 if no rule matches.
+func retentionDuration(tags []trinary.Trytes) time.Duration {
+	var duration time.Duration
+	for _, rule := range retentionRules {
+		for _, tag := range tags {
+			if !strings.HasPrefix(tag, rule.Tag) {
+				continue
+			}
+			if ruleDuration := time.Duration(rule.Days) * 24 * time.Hour; ruleDuration > duration {
+				duration = ruleDuration
+			}
+			break
+		}
+	}
+	return duration
+}
+
+// bundleRetention returns the hashes of the transactions of the bundle with the given tail and how long the bundle is retained.
+func bundleRetention(tailHash hornet.Hash) (hornet.Hashes, time.Duration) {
+	cachedBndl := tangle.GetCachedBundleOrNil(tailHash) // bundle +1
+	if cachedBndl == nil {
+		return nil, 0
+	}
+	defer cachedBndl.Release(true) // bundle -1
+
+	cachedTxs := cachedBndl.GetBundle().GetTransactions() // tx +1
+	defer cachedTxs.Release(true)                         // tx -1
+
+	txHashes := make(hornet.Hashes, 0, len(cachedTxs))
+	tags := make([]trinary.Trytes, 0, len(cachedTxs))
+	for _, cachedTx := range cachedTxs {
+		txHashes = append(txHashes, cachedTx.GetTransaction().GetTxHash())
+		tags = append(tags, cachedTx.GetTransaction().Tx.Tag)
+	}
+
+	return txHashes, retentionDuration(tags)
+}
+
+// retainBundles removes the bundles which are kept by a retention rule from the transactions to prune
+// and marks them as retained. The already retained transactions are removed as well, they are reached
+// again if younger transactions approve them. It returns the amount of newly retained bundles.
+func retainBundles(txsToCheckMap map[string]struct{}, retainedTxs map[string]struct{}, confirmationTimestamp time.Time) int {
+	if len(retentionRules) == 0 {
+		return 0
+	}
+
+	retained := 0
+	for txHash := range txsToCheckMap {
+		if _, isRetained := retainedTxs[txHash]; isRetained {
+			delete(txsToCheckMap, txHash)
+			continue
+		}
+
+		cachedTxMeta := tangle.GetCachedTxMetadataOrNil(hornet.Hash(txHash)) // meta +1
+		if cachedTxMeta == nil {
+			continue
+		}
+		isTail := cachedTxMeta.GetMetadata().IsTail()
+		cachedTxMeta.Release(true) // meta -1
+
+		if !isTail {
+			// the other transactions of a retained bundle are kept as long as their tail references them
+			continue
+		}
+
+		txHashes, duration := bundleRetention(hornet.Hash(txHash))
+		if duration == 0 || time.Since(confirmationTimestamp) >= duration {
+			continue
+		}
+
+		if err := tangle.StoreRetainedBundle(&tangle.RetainedBundle{
+			TailHash:              hornet.Hash(txHash),
+			ConfirmationTimestamp: confirmationTimestamp,
+			TxHashes:              txHashes,
+		}); err != nil {
+			log.Warn(err)
+			continue
+		}
+
+		for _, bundleTxHash := range txHashes {
+			delete(txsToCheckMap, string(bundleTxHash))
+			retainedTxs[string(bundleTxHash)] = struct{}{}
+		}
+		retained++
+	}
+
+	return retained
+}
+
+// pruneExpiredRetainedBundles prunes the retained bundles whose retention expired.
+// The rules are evaluated again, so that changed rules also apply to the already retained bundles.
+func pruneExpiredRetainedBundles() (txCountDeleted int, bundleCount int) {
+
+	txsToCheckMap := make(map[string]struct{})
+
+	if err := tangle.ForEachRetainedBundle(func(b *tangle.RetainedBundle) bool {
+		if _, duration := bundleRetention(b.TailHash); duration > 0 && time.Since(b.ConfirmationTimestamp) < duration {
+			return true
+		}
+
+		if err := tangle.DeleteRetainedBundle(b.TailHash); err != nil {
+			log.Warn(err)
+			return true
+		}
+
+		for _, txHash := range b.TxHashes {
+			txsToCheckMap[string(txHash)] = struct{}{}
+		}
+		bundleCount++
+		return true
+	}); err != nil {
+		log.Warn(err)
+	}
+
+	if len(txsToCheckMap) == 0 {
+		return 0, 0
+	}
+
+	return pruneTransactions(txsToCheckMap), bundleCount
+}
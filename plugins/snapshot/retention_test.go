@@ -0,0 +1,129 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	_ "golang.org/x/crypto/blake2b"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/testsuite"
+	"github.com/gohornet/hornet/pkg/testsuite/utils"
+)
+
+const (
+	retentionSeed1 = "JBN9ZRCOH9YRUGSWIQNZWAIFEZUBDUGTFPVRKXWPAUCEQQFS9NHPQLXCKZKRHVCCUZNF9CZZWKXRZVCWQ"
+	retentionSeed2 = "JBNAZRCOH9YRUGSWIQNZWAIFEZUBDUGTFPVRKXWPAUCEQQFS9NHPQLXCKZKRHVCCUZNF9CZZWKXRZVCWQ"
+)
+
+// setRetentionRules sets the given retention rules until the end of the test.
+func setRetentionRules(t *testing.T, rules ...*retentionRule) {
+	retentionRules = rules
+	t.Cleanup(func() { retentionRules = nil })
+}
+
+// bundleTxHashes returns the transaction hashes of the bundle with the given tail.
+func bundleTxHashes(t *testing.T, tailHash hornet.Hash) hornet.Hashes {
+	cachedBndl := tangle.GetCachedBundleOrNil(tailHash) // bundle +1
+	require.NotNil(t, cachedBndl)
+	defer cachedBndl.Release(true) // bundle -1
+	return cachedBndl.GetBundle().GetTxHashes()
+}
+
+func TestRetentionDuration(t *testing.T) {
+	setRetentionRules(t,
+		&retentionRule{Tag: "HORNET", Days: 1},
+		&retentionRule{Tag: "HORNETLONG", Days: 7},
+		&retentionRule{Tag: "IOTA", Days: 3},
+	)
+
+	day := 24 * time.Hour
+	assert.Equal(t, day, retentionDuration([]trinary.Trytes{"HORNET999"}))
+	// the longest retention of all matching rules wins
+	assert.Equal(t, 7*day, retentionDuration([]trinary.Trytes{"HORNETLONG9"}))
+	assert.Equal(t, 7*day, retentionDuration([]trinary.Trytes{"IOTA99", "HORNETLONG9"}))
+	// a rule matches any tag of the bundle
+	assert.Equal(t, 3*day, retentionDuration([]trinary.Trytes{"OTHER", "IOTA99"}))
+	// the tags are only matched by prefix
+	assert.Zero(t, retentionDuration([]trinary.Trytes{"MYHORNET9"}))
+	assert.Zero(t, retentionDuration(nil))
+}
+
+func TestRetainBundles(t *testing.T) {
+	setRetentionRules(t, &retentionRule{Tag: "RETAIN", Days: 1})
+
+	balances := make(map[string]uint64)
+	balances[string(utils.GenerateAddress(t, retentionSeed1, 0))] = 1000
+
+	te := testsuite.SetupTestEnvironment(t, balances, 3, false)
+	defer te.CleanupTestEnvironment(true)
+
+	te.BuildTopology(testsuite.Topology{
+		{Name: "A", Trunk: "ms2", Branch: "ms3", Trytes: utils.ValueTx(t, "RETAIN", retentionSeed1, 0, 1000, retentionSeed2, 0, 100)},
+		{Name: "B", Trunk: "A", Branch: "ms3"},
+	})
+	retainedTxHashes := bundleTxHashes(t, te.TailOf("A"))
+	require.Greater(t, len(retainedTxHashes), 1)
+
+	txsToCheck := func() map[string]struct{} {
+		txsToCheckMap := map[string]struct{}{string(te.TailOf("B")): {}}
+		for _, txHash := range retainedTxHashes {
+			txsToCheckMap[string(txHash)] = struct{}{}
+		}
+		return txsToCheckMap
+	}
+
+	// a bundle whose retention already ended at the time of pruning is not retained
+	txsToCheckMap := txsToCheck()
+	require.Zero(t, retainBundles(txsToCheckMap, make(map[string]struct{}), time.Now().Add(-25*time.Hour)))
+	require.Len(t, txsToCheckMap, len(retainedTxHashes)+1)
+
+	// the whole bundle with the matching tag is removed from the transactions to prune
+	txsToCheckMap = txsToCheck()
+	retainedTxs := make(map[string]struct{})
+	require.Equal(t, 1, retainBundles(txsToCheckMap, retainedTxs, time.Now()))
+	require.Equal(t, map[string]struct{}{string(te.TailOf("B")): {}}, txsToCheckMap)
+	require.Len(t, retainedTxs, len(retainedTxHashes))
+
+	// already retained transactions are removed without retaining the bundle again
+	txsToCheckMap = txsToCheck()
+	require.Zero(t, retainBundles(txsToCheckMap, retainedTxs, time.Now()))
+	require.Len(t, txsToCheckMap, 1)
+
+	var retained []*tangle.RetainedBundle
+	require.NoError(t, tangle.ForEachRetainedBundle(func(b *tangle.RetainedBundle) bool {
+		retained = append(retained, b)
+		return true
+	}))
+	require.Len(t, retained, 1)
+	require.Equal(t, te.TailOf("A"), retained[0].TailHash)
+	require.ElementsMatch(t, retainedTxHashes, retained[0].TxHashes)
+
+	// the bundle is kept while it is retained
+	txCountDeleted, bundleCount := pruneExpiredRetainedBundles()
+	require.Zero(t, txCountDeleted)
+	require.Zero(t, bundleCount)
+	require.True(t, tangle.ContainsTransaction(te.TailOf("A")))
+
+	// the bundle is pruned after its retention ended
+	retained[0].ConfirmationTimestamp = time.Now().Add(-25 * time.Hour)
+	require.NoError(t, tangle.StoreRetainedBundle(retained[0]))
+
+	txCountDeleted, bundleCount = pruneExpiredRetainedBundles()
+	require.Equal(t, len(retainedTxHashes), txCountDeleted)
+	require.Equal(t, 1, bundleCount)
+	for _, txHash := range retainedTxHashes {
+		require.False(t, tangle.ContainsTransaction(txHash))
+	}
+
+	require.NoError(t, tangle.ForEachRetainedBundle(func(b *tangle.RetainedBundle) bool {
+		require.Fail(t, "no retained bundle is left", "tail: %s", b.TailHash.Trytes())
+		return true
+	}))
+}
@@ -0,0 +1,90 @@
+package snapshot
+
+import (
+	"time"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/syncutils"
+	"github.com/iotaledger/hive.go/timeutil"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	tanglePlugin "github.com/gohornet/hornet/plugins/tangle"
+)
+
+const (
+	// the interval in which the transactions which were marked as unsolidifiable are removed from the database.
+	unsolidifiableCleanupInterval = 1 * time.Minute
+)
+
+var (
+	// the transactions which were marked as unsolidifiable since the last cleanup.
+	unsolidifiableTxs     = make(map[string]struct{})
+	unsolidifiableTxsLock syncutils.Mutex
+)
+
+func runUnsolidifiableCleanup() {
+
+	onTransactionsUnsolidifiable := events.NewClosure(func(txHashes hornet.Hashes) {
+		unsolidifiableTxsLock.Lock()
+		defer unsolidifiableTxsLock.Unlock()
+
+		for _, txHash := range txHashes {
+			unsolidifiableTxs[string(txHash)] = struct{}{}
+		}
+	})
+
+	daemon.BackgroundWorker("LocalSnapshots[UnsolidifiableCleanup]", func(shutdownSignal <-chan struct{}) {
+		tanglePlugin.Events.TransactionsUnsolidifiable.Attach(onTransactionsUnsolidifiable)
+		defer tanglePlugin.Events.TransactionsUnsolidifiable.Detach(onTransactionsUnsolidifiable)
+
+		timeutil.Ticker(pruneUnsolidifiableTransactions, unsolidifiableCleanupInterval, shutdownSignal)
+	}, shutdown.PriorityLocalSnapshots)
+}
+
+// pruneUnsolidifiableTransactions removes the transactions which were marked as unsolidifiable from the database.
+// Transactions which became solid in the meantime are kept, they are pruned like every other transaction.
+func pruneUnsolidifiableTransactions() {
+
+	unsolidifiableTxsLock.Lock()
+	txsToCheck := unsolidifiableTxs
+	unsolidifiableTxs = make(map[string]struct{})
+	unsolidifiableTxsLock.Unlock()
+
+	if len(txsToCheck) == 0 {
+		return
+	}
+
+	localSnapshotLock.Lock()
+	defer localSnapshotLock.Unlock()
+
+	txsToPrune := make(map[string]struct{})
+	for txHash := range txsToCheck {
+		cachedTxMeta := tangle.GetCachedTxMetadataOrNil(hornet.Hash(txHash)) // meta +1
+		if cachedTxMeta == nil {
+			// transaction was already pruned
+			continue
+		}
+
+		// the approvers of an unsolid transaction are unsolid as well and were marked together with it,
+		// so no solid transaction references a pruned one.
+		if !cachedTxMeta.GetMetadata().IsSolid() && cachedTxMeta.GetMetadata().IsUnsolidifiable() {
+			txsToPrune[txHash] = struct{}{}
+		}
+
+		// do not force release, since it is loaded again
+		cachedTxMeta.Release() // meta -1
+	}
+
+	if len(txsToPrune) == 0 {
+		return
+	}
+
+	setIsPruning(true)
+	defer setIsPruning(false)
+
+	txCountDeleted := pruneTransactions(txsToPrune)
+	log.Infof("pruned %d unsolidifiable transactions", txCountDeleted)
+}
@@ -17,15 +17,17 @@ import (
 	"github.com/iotaledger/iota.go/transaction"
 	"go.uber.org/atomic"
 
+	"github.com/gohornet/hornet/pkg/app"
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/tangle"
+	peeringpackage "github.com/gohornet/hornet/pkg/peering"
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/spammer"
 	"github.com/gohornet/hornet/pkg/utils"
 	"github.com/gohornet/hornet/plugins/coordinator"
 	"github.com/gohornet/hornet/plugins/gossip"
-	"github.com/gohornet/hornet/plugins/peering"
 	"github.com/gohornet/hornet/plugins/pow"
 	"github.com/gohornet/hornet/plugins/urts"
 )
@@ -37,6 +39,13 @@ var (
 	spammerInstance *spammer.Spammer
 	spammerLock     syncutils.RWMutex
 
+	// the settings of the running spammer, nil if it is stopped.
+	runningSettings *Settings
+	// adapts the rate of the running spammer, nil if the rate is not limited.
+	rateController *spammer.RateController
+	// closed when the running spammer is stopped.
+	stopSignal chan struct{}
+
 	spammerStartTime   time.Time
 	spammerAvgHeap     *utils.TimeHeap
 	lastSentSpamTxsCnt uint32
@@ -56,10 +65,53 @@ var (
 
 	// ErrSpammerDisabled is returned if the spammer plugin is disabled.
 	ErrSpammerDisabled = errors.New("Spammer plugin disabled")
+	// ErrSpammerNotRunning is returned if the settings of a stopped spammer are changed.
+	ErrSpammerNotRunning = errors.New("Spammer not running")
+)
+
+// dependencies of the plugin which are injected before it is configured.
+type dependencies struct {
+	app.In
+	PeeringManager *peeringpackage.Manager
+}
+
+var deps dependencies
+
+func init() {
+	app.Inject(PLUGIN, func(d dependencies) {
+		deps = d
+	})
+}
+
+const (
+	// the interval in which the rate of the spammer is adapted to the load of the node.
+	rateAdjustmentInterval = time.Second
 )
 
+// Settings are the settings the spammer runs with.
+type Settings struct {
+	// the target rate of spam transactions/bundles per second (0 = no limit).
+	TPSRateLimit float64 `json:"tpsRateLimit"`
+	CPUMaxUsage  float64 `json:"cpuMaxUsage"`
+	BundleSize   int     `json:"bundleSize"`
+	ValueSpam    bool    `json:"valueSpam"`
+	Workers      int     `json:"workers"`
+	// the amount of goroutines every spammer uses for the PoW.
+	PoWParallelism int  `json:"powParallelism"`
+	AdaptiveRate   bool `json:"adaptiveRate"`
+}
+
+// Status is the status of the spammer.
+type Status struct {
+	Running bool `json:"running"`
+	// the settings of the running spammer.
+	Settings *Settings `json:"settings,omitempty"`
+	// the rate the spammer currently runs at, which is lower than the target rate if the node is overloaded.
+	CurrentTPSRateLimit float64 `json:"currentTpsRateLimit,omitempty"`
+}
+
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
 	// do not enable the spammer if URTS is disabled
 	if node.IsSkipped(urts.PLUGIN) {
@@ -85,6 +137,21 @@ func configure(plugin *node.Plugin) {
 		return nil
 	}
 
+	config.Events.ParameterChanged.Attach(events.NewClosure(func(key string) {
+		if key != config.CfgSpammerTPSRateLimit {
+			return
+		}
+
+		tpsRateLimit := config.NodeConfig.GetFloat64(config.CfgSpammerTPSRateLimit)
+		if err := SetTPSRateLimit(tpsRateLimit); err != nil {
+			if err != ErrSpammerNotRunning {
+				log.Warnf("changing the rate limit due to config change failed: %s", err)
+			}
+			return
+		}
+		log.Infof("set '%s' to <%v> due to config change", config.CfgSpammerTPSRateLimit, tpsRateLimit)
+	}))
+
 	spammerInstance = spammer.New(
 		config.NodeConfig.GetString(config.CfgSpammerAddress),
 		config.NodeConfig.GetString(config.CfgSpammerMessage),
@@ -131,6 +198,8 @@ func Start(tpsRateLimit *float64, cpuMaxUsage *float64, bundleSize *int, valueSp
 	bundleSizeCfg := config.NodeConfig.GetInt(config.CfgSpammerBundleSize)
 	valueSpamCfg := config.NodeConfig.GetBool(config.CfgSpammerValueSpam)
 	spammerWorkerCount := config.NodeConfig.GetInt(config.CfgSpammerWorkers)
+	powWorkerCount := config.NodeConfig.GetInt(config.CfgSpammerPoWWorkers)
+	adaptiveRate := config.NodeConfig.GetBool(config.CfgSpammerAdaptiveRate)
 	checkPeersConnected := node.IsSkipped(coordinator.PLUGIN)
 
 	if tpsRateLimit != nil {
@@ -175,54 +244,142 @@ func Start(tpsRateLimit *float64, cpuMaxUsage *float64, bundleSize *int, valueSp
 		spammerWorkerCount = 1
 	}
 
-	startSpammerWorkers(tpsRateLimitCfg, cpuMaxUsageCfg, bundleSizeCfg, valueSpamCfg, spammerWorkerCount, checkPeersConnected)
+	// the spammers share the PoW worker budget
+	powParallelism := 1
+	if powWorkerCount > 0 {
+		if spammerWorkerCount > powWorkerCount {
+			spammerWorkerCount = powWorkerCount
+		}
+		powParallelism = powWorkerCount / spammerWorkerCount
+	}
+
+	startWithoutLocking(&Settings{
+		TPSRateLimit:   tpsRateLimitCfg,
+		CPUMaxUsage:    cpuMaxUsageCfg,
+		BundleSize:     bundleSizeCfg,
+		ValueSpam:      valueSpamCfg,
+		Workers:        spammerWorkerCount,
+		PoWParallelism: powParallelism,
+		AdaptiveRate:   adaptiveRate,
+	}, checkPeersConnected)
 
 	return tpsRateLimitCfg, cpuMaxUsageCfg, bundleSizeCfg, valueSpamCfg, nil
 }
 
-func startSpammerWorkers(tpsRateLimit float64, cpuMaxUsage float64, bundleSize int, valueSpam bool, spammerWorkerCount int, checkPeersConnected bool) {
+// SetTPSRateLimit changes the rate limit of the running spammer (0 = no limit).
+func SetTPSRateLimit(tpsRateLimit float64) error {
+	if spammerInstance == nil {
+		return ErrSpammerDisabled
+	}
+
+	spammerLock.Lock()
+	defer spammerLock.Unlock()
+
+	if runningSettings == nil {
+		return ErrSpammerNotRunning
+	}
+
+	if rateController != nil && tpsRateLimit != 0.0 {
+		// the rate limit worker picks up the new rate with its next tick
+		rateController.SetTarget(tpsRateLimit)
+		runningSettings.TPSRateLimit = tpsRateLimit
+		return nil
+	}
+
+	// switching between a limited and an unlimited rate needs a restart of the workers
+	settings := *runningSettings
+	settings.TPSRateLimit = tpsRateLimit
+	stopWithoutLocking()
+	startWithoutLocking(&settings, node.IsSkipped(coordinator.PLUGIN))
+
+	return nil
+}
+
+// GetStatus returns the status of the spammer.
+func GetStatus() (*Status, error) {
+	if spammerInstance == nil {
+		return nil, ErrSpammerDisabled
+	}
+
+	spammerLock.RLock()
+	defer spammerLock.RUnlock()
+
+	status := &Status{Running: runningSettings != nil}
+	if runningSettings != nil {
+		settings := *runningSettings
+		status.Settings = &settings
+		status.CurrentTPSRateLimit = runningSettings.TPSRateLimit
+		if rateController != nil {
+			status.CurrentTPSRateLimit = rateController.Current()
+		}
+	}
+	return status, nil
+}
+
+func startWithoutLocking(settings *Settings, checkPeersConnected bool) {
+	runningSettings = settings
+	stopSignal = make(chan struct{})
+
+	rateController = nil
+	if settings.TPSRateLimit != 0.0 {
+		rateController = spammer.NewRateController(settings.TPSRateLimit)
+	}
+
+	startSpammerWorkers(settings, rateController, stopSignal, checkPeersConnected)
+}
+
+func startSpammerWorkers(settings *Settings, rateController *spammer.RateController, stopSignal <-chan struct{}, checkPeersConnected bool) {
 
 	var rateLimitChannel chan struct{} = nil
 	var rateLimitAbortSignal chan struct{} = nil
 
-	if tpsRateLimit != 0.0 {
-		rateLimitChannelSize := int64(tpsRateLimit) * 2
+	if rateController != nil {
+		rateLimitChannelSize := int64(settings.TPSRateLimit) * 2
 		if rateLimitChannelSize < 2 {
 			rateLimitChannelSize = 2
 		}
 		rateLimitChannel = make(chan struct{}, rateLimitChannelSize)
 		rateLimitAbortSignal = make(chan struct{})
 
-		// create a background worker that fills rateLimitChannel every second
+		// create a background worker that fills rateLimitChannel with the current rate
 		daemon.BackgroundWorker("Spammer rate limit channel", func(shutdownSignal <-chan struct{}) {
 			spammerWaitGroup.Add(1)
-			done := make(chan struct{})
-			currentProcessID := processID.Load()
+			defer spammerWaitGroup.Done()
+
+			lastRateAdjustment := time.Now()
 
-			timeutil.Ticker(func() {
+			rateLimitTimer := time.NewTimer(rateLimitInterval(rateController.Current()))
+			defer rateLimitTimer.Stop()
 
-				if currentProcessID != processID.Load() {
+			for {
+				select {
+				case <-shutdownSignal:
 					close(rateLimitAbortSignal)
-					close(done)
 					return
+				case <-stopSignal:
+					close(rateLimitAbortSignal)
+					return
+				case <-rateLimitTimer.C:
 				}
 
 				select {
-				case <-shutdownSignal:
-					close(rateLimitAbortSignal)
-					close(done)
 				case rateLimitChannel <- struct{}{}:
 				default:
 					// Channel full
 				}
-			}, time.Duration(int64(float64(time.Second)/tpsRateLimit)), done)
 
-			spammerWaitGroup.Done()
+				if settings.AdaptiveRate && time.Since(lastRateAdjustment) >= rateAdjustmentInterval {
+					rateController.Adjust(isNodeOverloaded(settings.CPUMaxUsage))
+					lastRateAdjustment = time.Now()
+				}
+
+				rateLimitTimer.Reset(rateLimitInterval(rateController.Current()))
+			}
 		}, shutdown.PrioritySpammer)
 	}
 
 	spammerCnt := atomic.NewInt32(0)
-	for i := 0; i < spammerWorkerCount; i++ {
+	for i := 0; i < settings.Workers; i++ {
 		daemon.BackgroundWorker(fmt.Sprintf("Spammer_%d", i), func(shutdownSignal <-chan struct{}) {
 			spammerWaitGroup.Add(1)
 			spammerIndex := spammerCnt.Inc()
@@ -240,7 +397,7 @@ func startSpammerWorkers(tpsRateLimit float64, cpuMaxUsage float64, bundleSize i
 						break spammerLoop
 					}
 
-					if tpsRateLimit != 0 {
+					if rateController != nil {
 						// if rateLimit is activated, wait until this spammer thread gets a signal
 						select {
 						case <-rateLimitAbortSignal:
@@ -251,17 +408,17 @@ func startSpammerWorkers(tpsRateLimit float64, cpuMaxUsage float64, bundleSize i
 						}
 					}
 
-					if !tangle.IsNodeSyncedWithThreshold() {
+					if !tangle.IsNodeAlmostSynced() {
 						time.Sleep(time.Second)
 						continue
 					}
 
-					if checkPeersConnected && peering.Manager().ConnectedPeerCount() == 0 {
+					if checkPeersConnected && deps.PeeringManager.ConnectedPeerCount() == 0 {
 						time.Sleep(time.Second)
 						continue
 					}
 
-					if err := waitForLowerCPUUsage(cpuMaxUsage, shutdownSignal); err != nil {
+					if err := waitForLowerCPUUsage(settings.CPUMaxUsage, shutdownSignal); err != nil {
 						if err != tangle.ErrOperationAborted {
 							log.Warn(err.Error())
 						}
@@ -273,7 +430,7 @@ func startSpammerWorkers(tpsRateLimit float64, cpuMaxUsage float64, bundleSize i
 						spammerStartTime = time.Now()
 					}
 
-					durationGTTA, durationPOW, err := spammerInstance.DoSpam(bundleSize, valueSpam, shutdownSignal)
+					durationGTTA, durationPOW, err := spammerInstance.DoSpam(settings.BundleSize, settings.ValueSpam, settings.PoWParallelism, shutdownSignal)
 					if err != nil {
 						continue
 					}
@@ -289,6 +446,29 @@ func startSpammerWorkers(tpsRateLimit float64, cpuMaxUsage float64, bundleSize i
 	}
 }
 
+// rateLimitInterval returns the interval between two spam transactions/bundles at the given rate.
+func rateLimitInterval(tpsRateLimit float64) time.Duration {
+	return time.Duration(float64(time.Second) / tpsRateLimit)
+}
+
+// isNodeOverloaded tells whether the spammer should lower its rate,
+// because the node fell behind the latest milestone or the cpu usage is over the limit.
+func isNodeOverloaded(cpuMaxUsage float64) bool {
+	if !tangle.IsNodeSynced() {
+		return true
+	}
+
+	if cpuMaxUsage == 0.0 {
+		return false
+	}
+
+	cpuUsage, err := cpuUsage()
+	if err != nil {
+		return false
+	}
+	return cpuUsage >= cpuMaxUsage
+}
+
 // Stop stops the spammer.
 func Stop() error {
 	if spammerInstance == nil {
@@ -306,10 +486,17 @@ func Stop() error {
 func stopWithoutLocking() {
 	// increase the process ID to stop all running workers
 	processID.Inc()
+	if stopSignal != nil {
+		close(stopSignal)
+		stopSignal = nil
+	}
 
 	// wait until all spammers are stopped
 	spammerWaitGroup.Wait()
 
+	runningSettings = nil
+	rateController = nil
+
 	// reset the start time to stop the metrics
 	spammerStartTime = time.Time{}
 
@@ -3,6 +3,7 @@ package tangle
 import (
 	"github.com/iotaledger/hive.go/events"
 
+	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/pkg/whiteflag"
@@ -16,6 +17,16 @@ func ConfirmedMilestoneCaller(handler interface{}, params ...interface{}) {
 	handler.(func(confirmation *whiteflag.Confirmation))(params[0].(*whiteflag.Confirmation))
 }
 
+// MissingTransactionsRequestedCaller is the caller of the MissingTransactionsRequested event.
+func MissingTransactionsRequestedCaller(handler interface{}, params ...interface{}) {
+	handler.(func(msIndex milestone.Index, requested int, missing int))(params[0].(milestone.Index), params[1].(int), params[2].(int))
+}
+
+// TransactionHashesCaller is the caller of events with the hashes of several transactions.
+func TransactionHashesCaller(handler interface{}, params ...interface{}) {
+	handler.(func(txHashes hornet.Hashes))(params[0].(hornet.Hashes))
+}
+
 var Events = pluginEvents{
 	ReceivedNewTransaction:        events.NewEvent(tangle.NewTransactionCaller),
 	ReceivedKnownTransaction:      events.NewEvent(tangle.TransactionCaller),
@@ -34,6 +45,8 @@ var Events = pluginEvents{
 	PruningMilestoneIndexChanged:  events.NewEvent(milestone.IndexCaller),
 	NewConfirmedMilestoneMetric:   events.NewEvent(NewConfirmedMilestoneMetricCaller),
 	MilestoneSolidificationFailed: events.NewEvent(milestone.IndexCaller),
+	MissingTransactionsRequested:  events.NewEvent(MissingTransactionsRequestedCaller),
+	TransactionsUnsolidifiable:    events.NewEvent(TransactionHashesCaller),
 }
 
 type pluginEvents struct {
@@ -54,4 +67,9 @@ type pluginEvents struct {
 	PruningMilestoneIndexChanged  *events.Event
 	NewConfirmedMilestoneMetric   *events.Event
 	MilestoneSolidificationFailed *events.Event
+	// MissingTransactionsRequested is triggered when the solidifier requested the missing transactions in the cone of a milestone.
+	MissingTransactionsRequested *events.Event
+	// TransactionsUnsolidifiable is triggered with the hashes of the transactions which were marked as unsolidifiable,
+	// because their past cone references transactions no neighbor answered requests for.
+	TransactionsUnsolidifiable *events.Event
 }
@@ -3,40 +3,70 @@ package tangle
 import (
 	"time"
 
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
-	"github.com/gohornet/hornet/plugins/peering"
 )
 
-const (
-	maxAllowedMilestoneAge = time.Minute * 5
-)
+// HealthStatus holds the criteria the health of the node is determined by.
+type HealthStatus struct {
+	// Healthy is true if all criteria are met.
+	Healthy bool `json:"healthy"`
+	// Synced is true if the latest solid milestone is at most the configured amount of milestones behind the latest milestone.
+	Synced bool `json:"synced"`
+	// MilestoneDelta is the amount of milestones the latest solid milestone is behind the latest milestone.
+	MilestoneDelta milestone.Index `json:"milestoneDelta"`
+	// EnoughPeers is true if at least the configured amount of peers is connected.
+	EnoughPeers bool `json:"enoughPeers"`
+	// ConnectedPeers is the amount of connected peers.
+	ConnectedPeers int `json:"connectedPeers"`
+	// MilestoneRecent is true if the latest milestone is younger than the configured maximum age.
+	MilestoneRecent bool `json:"milestoneRecent"`
+	// MilestoneAgeSeconds is the age of the latest milestone in seconds (-1 if the latest milestone is unknown).
+	MilestoneAgeSeconds int64 `json:"milestoneAgeSeconds"`
+	// DatabaseHealthy is true if all databases can be read from.
+	DatabaseHealthy bool `json:"databaseHealthy"`
+}
+
+// GetHealthStatus checks the configured health criteria of the node.
+func GetHealthStatus() *HealthStatus {
+	status := &HealthStatus{MilestoneAgeSeconds: -1}
 
-// IsNodeHealthy returns whether the node is synced, has active neighbors and its latest milestone is not too old.
-func IsNodeHealthy() bool {
 	// Synced
-	if !tangle.IsNodeSyncedWithThreshold() {
-		return false
+	lmi := tangle.GetLatestMilestoneIndex()
+	lsmi := tangle.GetSolidMilestoneIndex()
+	if lmi > lsmi {
+		status.MilestoneDelta = lmi - lsmi
 	}
+	maxMilestoneDelta := milestone.Index(config.NodeConfig.GetInt(config.CfgHealthMaxMilestoneDelta))
+	status.Synced = lmi != 0 && lmi >= tangle.GetLatestSeenMilestoneIndexFromSnapshot() && status.MilestoneDelta <= maxMilestoneDelta
 
 	// Has connected neighbors
-	if peering.Manager().ConnectedPeerCount() == 0 {
-		return false
-	}
+	status.ConnectedPeers = deps.PeeringManager.ConnectedPeerCount()
+	status.EnoughPeers = status.ConnectedPeers >= config.NodeConfig.GetInt(config.CfgHealthMinConnectedPeers)
 
 	// Latest milestone timestamp
-	var milestoneTimestamp int64
-	lmi := tangle.GetLatestMilestoneIndex()
-	cachedLatestMs := tangle.GetMilestoneOrNil(lmi) // bundle +1
-	if cachedLatestMs == nil {
-		return false
+	if cachedLatestMs := tangle.GetMilestoneOrNil(lmi); cachedLatestMs != nil { // bundle +1
+		cachedMsTailTx := cachedLatestMs.GetBundle().GetTail() // tx +1
+		milestoneTimestamp := cachedMsTailTx.GetTransaction().GetTimestamp()
+		cachedMsTailTx.Release(true) // tx -1
+		cachedLatestMs.Release(true) // bundle -1
+
+		milestoneAge := time.Since(time.Unix(milestoneTimestamp, 0))
+		maxMilestoneAge := time.Duration(config.NodeConfig.GetInt(config.CfgHealthMaxMilestoneAgeSeconds)) * time.Second
+		status.MilestoneAgeSeconds = int64(milestoneAge.Seconds())
+		status.MilestoneRecent = milestoneAge < maxMilestoneAge
 	}
 
-	cachedMsTailTx := cachedLatestMs.GetBundle().GetTail() // tx +1
-	milestoneTimestamp = cachedMsTailTx.GetTransaction().GetTimestamp()
-	cachedMsTailTx.Release(true) // tx -1
-	cachedLatestMs.Release(true) // bundle -1
+	// Database
+	status.DatabaseHealthy = tangle.CheckDatabasesHealth() == nil
 
-	// Check whether the milestone is older than 5 minutes
-	timeMs := time.Unix(milestoneTimestamp, 0)
-	return time.Since(timeMs) < maxAllowedMilestoneAge
+	status.Healthy = status.Synced && status.EnoughPeers && status.MilestoneRecent && status.DatabaseHealthy
+	return status
+}
+
+// IsNodeHealthy returns whether the node is synced, has enough connected peers, its latest milestone is not too old
+// and its databases are readable.
+func IsNodeHealthy() bool {
+	return GetHealthStatus().Healthy
 }
@@ -1,9 +1,8 @@
 package tangle
 
 import (
-	"github.com/iotaledger/hive.go/workerpool"
-
 	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/workerpool"
 	"github.com/gohornet/hornet/plugins/gossip"
 )
 
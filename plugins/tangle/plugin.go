@@ -1,6 +1,7 @@
 package tangle
 
 import (
+	"bytes"
 	"os"
 	"time"
 
@@ -15,17 +16,19 @@ import (
 
 	"github.com/iotaledger/iota.go/address"
 
+	"github.com/gohornet/hornet/pkg/app"
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/model/coordinator"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
+	peeringpackage "github.com/gohornet/hornet/pkg/peering"
 	"github.com/gohornet/hornet/pkg/peering/peer"
 	"github.com/gohornet/hornet/pkg/protocol/sting"
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/plugins/database"
 	"github.com/gohornet/hornet/plugins/gossip"
-	"github.com/gohornet/hornet/plugins/peering"
 )
 
 const (
@@ -48,15 +51,31 @@ var (
 	onReceivedNewTx                *events.Closure
 )
 
+// dependencies of the plugin which are injected before it is configured.
+type dependencies struct {
+	app.In
+	PeeringManager *peeringpackage.Manager
+}
+
+var deps dependencies
+
 func init() {
 	flag.CommandLine.MarkHidden("syncedAtStartup")
+
+	app.Inject(PLUGIN, func(d dependencies) {
+		deps = d
+	})
 }
 
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
 	tangle.LoadInitialValuesFromDatabase()
 
+	if msIndex := tangle.Ledger().RolledBackMilestoneIndex(); msIndex != 0 {
+		log.Warnf("the ledger changes of milestone %d were not applied completely and were rolled back", msIndex)
+	}
+
 	updateSyncedAtStartup = *syncedAtStartup
 
 	// Create a background worker that marks the database as corrupted at clean startup.
@@ -71,19 +90,71 @@ func configure(plugin *node.Plugin) {
 		log.Fatal(err.Error())
 	}
 
+	milestoneMerkleHashFunc, err := coordinator.MilestoneMerkleTreeHashFuncWithName(config.NodeConfig.GetString(config.CfgCoordinatorMilestoneMerkleTreeHashFunc))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
 	tangle.ConfigureMilestones(
-		hornet.HashFromAddressTrytes(config.NodeConfig.GetString(config.CfgCoordinatorAddress)),
+		loadMilestoneKeyManager(),
 		config.NodeConfig.GetInt(config.CfgCoordinatorSecurityLevel),
 		uint64(config.NodeConfig.GetInt(config.CfgCoordinatorMerkleTreeDepth)),
-		coordinator.MilestoneMerkleTreeHashFuncWithName(config.NodeConfig.GetString(config.CfgCoordinatorMilestoneMerkleTreeHashFunc)),
+		milestoneMerkleHashFunc,
 	)
 
 	configureEvents()
+	configureUnsolidifiableEvents()
 	configureTangleProcessor(plugin)
 
 	gossip.AddRequestBackpressureSignal(IsReceiveTxWorkerPoolBusy)
 }
 
+// loadMilestoneKeyManager creates the set of coordinator addresses from the configured key ranges.
+// If no key ranges are configured, the coordinator address is valid for all milestones.
+// Otherwise the coordinator address has to be the address of the latest key range.
+func loadMilestoneKeyManager() *tangle.MilestoneKeyManager {
+	keyManager := tangle.NewMilestoneKeyManager()
+
+	var keyRanges []*config.MilestoneKeyRangeConfig
+	if err := config.NodeConfig.UnmarshalKey(config.CfgCoordinatorKeyRanges, &keyRanges); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	if len(keyRanges) == 0 {
+		if err := keyManager.AddKeyRange(hornet.HashFromAddressTrytes(config.NodeConfig.GetString(config.CfgCoordinatorAddress)), 0, 0); err != nil {
+			log.Fatal(err.Error())
+		}
+		return keyManager
+	}
+
+	var latestKeyRange *config.MilestoneKeyRangeConfig
+	for _, keyRange := range keyRanges {
+		if err := address.ValidAddress(keyRange.Address); err != nil {
+			log.Fatalf("invalid coordinator key range address %s: %s", keyRange.Address, err)
+		}
+
+		if err := keyManager.AddKeyRange(hornet.HashFromAddressTrytes(keyRange.Address), milestone.Index(keyRange.StartIndex), milestone.Index(keyRange.EndIndex)); err != nil {
+			log.Fatal(err.Error())
+		}
+
+		if latestKeyRange == nil || keyRange.StartIndex > latestKeyRange.StartIndex {
+			latestKeyRange = keyRange
+		}
+	}
+
+	// the coordinator address is still used for the handshakes, the snapshots and by the coordinator plugin.
+	// the addresses are compared without their checksums.
+	cooAddress := config.NodeConfig.GetString(config.CfgCoordinatorAddress)
+	if err := address.ValidAddress(cooAddress); err != nil {
+		log.Fatalf("invalid coordinator address %s: %s", cooAddress, err)
+	}
+	if !bytes.Equal(hornet.HashFromAddressTrytes(cooAddress), hornet.HashFromAddressTrytes(latestKeyRange.Address)) {
+		log.Fatalf("'%s' (%s) must be the address of the latest coordinator key range (%s)", config.CfgCoordinatorAddress, cooAddress, latestKeyRange.Address)
+	}
+
+	return keyManager
+}
+
 func run(plugin *node.Plugin) {
 
 	if tangle.IsDatabaseCorrupted() && !config.NodeConfig.GetBool(config.CfgDatabaseDebug) {
@@ -113,9 +184,10 @@ func run(plugin *node.Plugin) {
 
 			peerIDsToRemove := make(map[string]struct{})
 			peersToReconnect := make(map[string]*peer.Peer)
+			var stalePeers []*peer.Peer
 
 			// check if peers are alive by checking whether we received heartbeats lately
-			peering.Manager().ForAllConnected(func(p *peer.Peer) bool {
+			deps.PeeringManager.ForAllConnected(func(p *peer.Peer) bool {
 				if !p.Protocol.Supports(sting.FeatureSet) {
 					return true
 				}
@@ -125,6 +197,8 @@ func run(plugin *node.Plugin) {
 				}
 
 				// peer is connected but doesn't seem to be alive
+				stalePeers = append(stalePeers, p)
+
 				if p.Autopeering != nil {
 					// it's better to drop the connection to autopeered peers and free the slots for other peers
 					peerIDsToRemove[p.ID] = struct{}{}
@@ -138,8 +212,13 @@ func run(plugin *node.Plugin) {
 				return true
 			})
 
+			for _, p := range stalePeers {
+				// the peer might get banned, in which case it is already disconnected below
+				deps.PeeringManager.Misbehaved(p, peeringpackage.PenaltyStaleHeartbeat)
+			}
+
 			for peerIDToRemove := range peerIDsToRemove {
-				peering.Manager().Remove(peerIDToRemove)
+				deps.PeeringManager.Remove(peerIDToRemove)
 			}
 
 			for _, p := range peersToReconnect {
@@ -153,9 +232,13 @@ func run(plugin *node.Plugin) {
 	}, shutdown.PriorityHeartbeats)
 
 	daemon.BackgroundWorker("Tangle[SolidifierGossipEvents]", func(shutdownSignal <-chan struct{}) {
+		futureConeSolidifierWorkerPool.Start()
 		attachSolidifierGossipEvents()
+		attachUnsolidifiableEvents()
 		<-shutdownSignal
+		detachUnsolidifiableEvents()
 		detachSolidifierGossipEvents()
+		futureConeSolidifierWorkerPool.StopAndWait()
 	}, shutdown.PrioritySolidifierGossip)
 
 	daemon.BackgroundWorker("Cleanup at shutdown", func(shutdownSignal <-chan struct{}) {
@@ -190,6 +273,9 @@ func configureEvents() {
 	})
 
 	onPruningMilestoneIndexChanged = events.NewClosure(func(msIndex milestone.Index) {
+		// the pruned transactions can't be solidified anymore
+		gossip.DiscardPrunedRequests(msIndex)
+
 		// notify peers about our new pruning milestone index
 		gossip.BroadcastHeartbeat(nil)
 	})
@@ -203,9 +289,18 @@ func configureEvents() {
 		// Force release possible here, since processIncomingTx still holds a reference
 		defer cachedTx.Release(true) // tx -1
 
-		if tangle.IsNodeSyncedWithThreshold() {
-			solidifyFutureConeOfTx(cachedTx.GetCachedMetadata()) // meta pass +1
+		if !tangle.IsNodeAlmostSynced() {
+			return
+		}
+
+		// the future cone is walked in a separate worker pool, to not block the processing of incoming transactions.
+		// if the queue is full, the solidity is propagated by the milestone solidifier later on.
+		cachedTxMeta := cachedTx.GetCachedMetadata()                                   // meta +1
+		if _, added := futureConeSolidifierWorkerPool.TrySubmit(cachedTxMeta); added { // meta pass +1
+			return // Avoid meta -1 (done inside workerpool task)
 		}
+		cachedTxMeta.Release(true) // meta -1
+		onFutureConeSolidificationDropped()
 	})
 }
 
@@ -29,8 +29,14 @@ var (
 // If the node crashes, it is not guaranteed that all data in the cache was already persisted to the disk.
 // Thats why we flag the database as corrupted.
 //
-// This function tries to restore a clean database state by deleting all existing transactions
-// since last local snapshot, deleting all ledger states and changes, loading valid snapshot ledger state.
+// This function tries to restore a clean database state by rolling back to the last verified solid milestone.
+// A milestone is verified if all transactions of its cone are stored and confirmed by it (or older milestones),
+// and if the ledger state of the milestone can be derived from the stored ledger diffs, which is checked
+// by rolling back the ledger to the snapshot ledger state. If no milestone newer than the last local snapshot
+// can be verified, the database is rolled back to the last local snapshot.
+//
+// All transactions, ledger changes and milestones newer than the target milestone are deleted,
+// and the ledger state of the target milestone is applied.
 //
 // This way HORNET should be able to re-solidify the existing tangle in the database.
 //
@@ -45,15 +51,17 @@ var (
 // 		Stored without caching:
 //			- Tag								=> will be removed and added again if missing by receiving the tx
 //			- Address							=> will be removed and added again if missing by receiving the tx
+//			- AddressHistory					=> will be removed above the target milestone and added again by confirmation
 //			- UnconfirmedTx 					=> will be removed at pruning anyway
+//			- TransactionType					=> will be removed and added again if missing by receiving the tx
 //			- Milestone							=> will be removed and added again by receiving the tx
 //			- SpentAddresses					=> will be removed and added again if missing by receiving the tx
 //
 // Database:
 // 		- LedgerState
-//			- Balances of latest solid milestone		=> will be removed and replaced with snapshot milestone
+//			- Balances of latest solid milestone		=> will be removed and replaced with the target milestone
 //			- Balances of snapshot milestone			=> should be consistent (total iotas are checked)
-//			- Balance diffs of every solid milestone	=> will be removed and added again by confirmation
+//			- Balance diffs of every solid milestone	=> will be removed above the target milestone and added again by confirmation
 //
 func revalidateDatabase() error {
 
@@ -74,20 +82,30 @@ func revalidateDatabase() error {
 		return ErrLatestMilestoneOlderThanSnapshotIndex
 	}
 
-	log.Infof("reverting database state back from %d to local snapshot %d (this might take a while)... ", latestMilestoneIndex, snapshotInfo.SnapshotIndex)
+	// search the newest milestone the database can be rolled back to
+	targetIndex, targetBalances, err := findLastVerifiedMilestone(snapshotInfo)
+	if err != nil {
+		return err
+	}
+
+	if targetIndex == snapshotInfo.SnapshotIndex {
+		log.Infof("reverting database state back from %d to local snapshot %d (this might take a while)... ", latestMilestoneIndex, targetIndex)
+	} else {
+		log.Infof("reverting database state back from %d to verified milestone %d (this might take a while)... ", latestMilestoneIndex, targetIndex)
+	}
 
-	// delete milestone data newer than the local snapshot
-	if err := cleanupMilestones(snapshotInfo); err != nil {
+	// delete milestone data newer than the target milestone
+	if err := cleanupMilestones(targetIndex); err != nil {
 		return err
 	}
 
-	// deletes all ledger diffs which have a confirmation milestone newer than the last local snapshot's milestone.
-	if err := cleanupLedgerDiffs(snapshotInfo); err != nil {
+	// deletes all ledger diffs which have a confirmation milestone newer than the target milestone.
+	if err := cleanupLedgerDiffs(targetIndex); err != nil {
 		return err
 	}
 
-	// clean up transactions which are above the local snapshot
-	if err := cleanupTransactions(snapshotInfo); err != nil {
+	// clean up transactions which are above the target milestone
+	if err := cleanupTransactions(targetIndex); err != nil {
 		return err
 	}
 
@@ -121,6 +139,16 @@ func revalidateDatabase() error {
 		return err
 	}
 
+	// deletes all address history entries which were confirmed by milestones newer than the target milestone.
+	if err := cleanupAddressHistory(targetIndex); err != nil {
+		return err
+	}
+
+	// deletes all transaction types where the tx doesn't exist in the database anymore.
+	if err := cleanupTransactionTypes(); err != nil {
+		return err
+	}
+
 	// deletes all unconfirmed txs that are left in the database (we do not need them since we deleted all unconfirmed txs).
 	if err := cleanupUnconfirmedTxs(); err != nil {
 		return err
@@ -130,18 +158,25 @@ func revalidateDatabase() error {
 	tangle.FlushStorages()
 	log.Info("flushing storages... done!")
 
-	// apply the ledger from the last snapshot to the database
-	if err := applySnapshotLedger(snapshotInfo); err != nil {
-		return err
+	if targetBalances == nil {
+		// apply the ledger from the last snapshot to the database
+		if err := applySnapshotLedger(snapshotInfo); err != nil {
+			return err
+		}
+	} else {
+		// apply the verified ledger of the target milestone to the database
+		if err := applyVerifiedLedger(targetBalances, targetIndex); err != nil {
+			return err
+		}
 	}
 
-	log.Infof("reverted state back to local snapshot %d, took %v", snapshotInfo.SnapshotIndex, time.Since(start).Truncate(time.Millisecond))
+	log.Infof("reverted state back to milestone %d, took %v", targetIndex, time.Since(start).Truncate(time.Millisecond))
 
 	return nil
 }
 
-// deletes milestones above the given snapshot's milestone index.
-func cleanupMilestones(info *tangle.SnapshotInfo) error {
+// deletes milestones above the given target milestone index.
+func cleanupMilestones(targetIndex milestone.Index) error {
 
 	start := time.Now()
 
@@ -163,7 +198,7 @@ func cleanupMilestones(info *tangle.SnapshotInfo) error {
 		}
 
 		// do not delete older milestones
-		if msIndex <= info.SnapshotIndex {
+		if msIndex <= targetIndex {
 			return true
 		}
 
@@ -193,7 +228,8 @@ func cleanupMilestones(info *tangle.SnapshotInfo) error {
 		}
 
 		tangle.DeleteUnconfirmedTxs(msIndex)
-		if err := tangle.DeleteLedgerDiffForMilestone(msIndex); err != nil {
+		tangle.DeleteTransactionTypes(msIndex)
+		if err := tangle.Ledger().DeleteLedgerDiffForMilestone(msIndex); err != nil {
 			panic(err)
 		}
 
@@ -201,6 +237,7 @@ func cleanupMilestones(info *tangle.SnapshotInfo) error {
 	}
 
 	tangle.FlushUnconfirmedTxsStorage()
+	tangle.FlushTransactionTypesStorage()
 	tangle.FlushMilestoneStorage()
 
 	log.Infof("deleting milestones...%d/%d (100.00%%) done. took %v", total, total, time.Since(start).Truncate(time.Millisecond))
@@ -208,8 +245,8 @@ func cleanupMilestones(info *tangle.SnapshotInfo) error {
 	return nil
 }
 
-// deletes all ledger diffs which have a confirmation milestone newer than the last local snapshot's milestone.
-func cleanupLedgerDiffs(info *tangle.SnapshotInfo) error {
+// deletes all ledger diffs which have a confirmation milestone newer than the target milestone.
+func cleanupLedgerDiffs(targetIndex milestone.Index) error {
 
 	start := time.Now()
 
@@ -217,7 +254,7 @@ func cleanupLedgerDiffs(info *tangle.SnapshotInfo) error {
 
 	lastStatusTime := time.Now()
 	var ledgerDiffsCounter int64
-	tangle.ForEachLedgerDiffHash(func(msIndex milestone.Index, address hornet.Hash) bool {
+	tangle.Ledger().ForEachLedgerDiffHash(func(msIndex milestone.Index, address hornet.Hash) bool {
 		ledgerDiffsCounter++
 
 		if time.Since(lastStatusTime) >= printStatusInterval {
@@ -231,7 +268,7 @@ func cleanupLedgerDiffs(info *tangle.SnapshotInfo) error {
 		}
 
 		// do not delete older milestones
-		if msIndex <= info.SnapshotIndex {
+		if msIndex <= targetIndex {
 			return true
 		}
 
@@ -260,7 +297,7 @@ func cleanupLedgerDiffs(info *tangle.SnapshotInfo) error {
 			log.Infof("deleting ledger diffs...%d/%d (%0.2f%%). %v left...", deletionCounter, total, percentage, remaining.Truncate(time.Second))
 		}
 
-		tangle.DeleteLedgerDiffForMilestone(msIndex)
+		tangle.Ledger().DeleteLedgerDiffForMilestone(msIndex)
 	}
 
 	log.Infof("deleting ledger diffs...%d/%d (100.00%%) done. took %v", total, total, time.Since(start).Truncate(time.Millisecond))
@@ -269,8 +306,8 @@ func cleanupLedgerDiffs(info *tangle.SnapshotInfo) error {
 }
 
 // deletes all transactions which are not confirmed, not solid or
-// their confirmation milestone is newer than the last local snapshot's milestone.
-func cleanupTransactions(info *tangle.SnapshotInfo) error {
+// their confirmation milestone is newer than the target milestone.
+func cleanupTransactions(targetIndex milestone.Index) error {
 
 	start := time.Now()
 
@@ -306,7 +343,7 @@ func cleanupTransactions(info *tangle.SnapshotInfo) error {
 		}
 
 		// not confirmed or above snapshot index
-		if confirmed, by := storedTxMeta.GetConfirmed(); !confirmed || by > info.SnapshotIndex {
+		if confirmed, by := storedTxMeta.GetConfirmed(); !confirmed || by > targetIndex {
 			transactionsToDelete[string(txHash)] = struct{}{}
 			return true
 		}
@@ -745,6 +782,141 @@ func cleanupAddresses() error {
 	return nil
 }
 
+// deletes all address history entries which were confirmed by milestones newer than the target milestone.
+func cleanupAddressHistory(targetIndex milestone.Index) error {
+
+	type addressHistoryEntry struct {
+		address hornet.Hash
+		msIndex milestone.Index
+		txHash  hornet.Hash
+	}
+
+	start := time.Now()
+
+	var entriesToDelete []*addressHistoryEntry
+
+	lastStatusTime := time.Now()
+	var entriesCounter int64
+	tangle.ForEachAddressHistoryEntry(func(address hornet.Hash, msIndex milestone.Index, txHash hornet.Hash) bool {
+		entriesCounter++
+
+		if time.Since(lastStatusTime) >= printStatusInterval {
+			lastStatusTime = time.Now()
+
+			if daemon.IsStopped() {
+				return false
+			}
+
+			log.Infof("analyzed %d address history entries", entriesCounter)
+		}
+
+		// do not delete entries of older milestones
+		if msIndex > targetIndex {
+			entriesToDelete = append(entriesToDelete, &addressHistoryEntry{address: address, msIndex: msIndex, txHash: txHash})
+		}
+
+		return true
+	}, true)
+	log.Infof("analyzed %d address history entries", entriesCounter)
+
+	if daemon.IsStopped() {
+		return tangle.ErrOperationAborted
+	}
+
+	total := len(entriesToDelete)
+	var deletionCounter int64
+	for _, entry := range entriesToDelete {
+		deletionCounter++
+
+		if time.Since(lastStatusTime) >= printStatusInterval {
+			lastStatusTime = time.Now()
+
+			if daemon.IsStopped() {
+				return tangle.ErrOperationAborted
+			}
+
+			percentage, remaining := utils.EstimateRemainingTime(start, deletionCounter, int64(total))
+			log.Infof("deleting address history entries...%d/%d (%0.2f%%). %v left...", deletionCounter, total, percentage, remaining.Truncate(time.Second))
+		}
+
+		tangle.DeleteAddressHistoryEntry(entry.address, entry.msIndex, entry.txHash)
+	}
+
+	tangle.FlushAddressHistoryStorage()
+
+	log.Infof("deleting address history entries...%d/%d (100.00%%) done. took %v", total, total, time.Since(start).Truncate(time.Millisecond))
+
+	return nil
+}
+
+// deletes all transaction types where the tx doesn't exist in the database anymore.
+func cleanupTransactionTypes() error {
+
+	type transactionType struct {
+		txType  hornet.TransactionType
+		msIndex milestone.Index
+		txHash  hornet.Hash
+	}
+
+	start := time.Now()
+
+	var transactionTypesToDelete []*transactionType
+
+	lastStatusTime := time.Now()
+	var transactionTypesCounter int64
+	tangle.ForEachTransactionType(func(txType hornet.TransactionType, msIndex milestone.Index, txHash hornet.Hash) bool {
+		transactionTypesCounter++
+
+		if time.Since(lastStatusTime) >= printStatusInterval {
+			lastStatusTime = time.Now()
+
+			if daemon.IsStopped() {
+				return false
+			}
+
+			log.Infof("analyzed %d transaction types", transactionTypesCounter)
+		}
+
+		// delete transaction type if transaction doesn't exist
+		if !tangle.TransactionExistsInStore(txHash) {
+			// the same transaction may be indexed for several milestones, therefore the entries are collected in a list
+			transactionTypesToDelete = append(transactionTypesToDelete, &transactionType{txType: txType, msIndex: msIndex, txHash: txHash})
+		}
+
+		return true
+	}, true)
+	log.Infof("analyzed %d transaction types", transactionTypesCounter)
+
+	if daemon.IsStopped() {
+		return tangle.ErrOperationAborted
+	}
+
+	total := len(transactionTypesToDelete)
+	var deletionCounter int64
+	for _, transactionType := range transactionTypesToDelete {
+		deletionCounter++
+
+		if time.Since(lastStatusTime) >= printStatusInterval {
+			lastStatusTime = time.Now()
+
+			if daemon.IsStopped() {
+				return tangle.ErrOperationAborted
+			}
+
+			percentage, remaining := utils.EstimateRemainingTime(start, deletionCounter, int64(total))
+			log.Infof("deleting transaction types...%d/%d (%0.2f%%). %v left...", deletionCounter, total, percentage, remaining.Truncate(time.Second))
+		}
+
+		tangle.DeleteTransactionType(transactionType.txType, transactionType.msIndex, transactionType.txHash)
+	}
+
+	tangle.FlushTransactionTypesStorage()
+
+	log.Infof("deleting transaction types...%d/%d (100.00%%) done. took %v", total, total, time.Since(start).Truncate(time.Millisecond))
+
+	return nil
+}
+
 // deletes all unconfirmed txs that are left in the database (we do not need them since we deleted all unconfirmed txs).
 func cleanupUnconfirmedTxs() error {
 
@@ -818,7 +990,7 @@ func applySnapshotLedger(info *tangle.SnapshotInfo) error {
 	}
 
 	// Store the snapshot balances as the current valid ledger
-	if err = tangle.StoreLedgerBalancesInDatabase(snapshotBalances, snapshotIndex); err != nil {
+	if err = tangle.Ledger().StoreLedgerBalancesInDatabase(snapshotBalances, snapshotIndex); err != nil {
 		return err
 	}
 	log.Info("applying snapshot balances to the ledger state ... done!")
@@ -828,3 +1000,20 @@ func applySnapshotLedger(info *tangle.SnapshotInfo) error {
 
 	return nil
 }
+
+// apply the verified ledger of the target milestone to the database
+func applyVerifiedLedger(balances map[string]uint64, targetIndex milestone.Index) error {
+
+	log.Infof("applying verified balances of milestone %d to the ledger state...", targetIndex)
+
+	// Store the verified balances as the current valid ledger
+	if err := tangle.Ledger().StoreLedgerBalancesInDatabase(balances, targetIndex); err != nil {
+		return err
+	}
+	log.Infof("applying verified balances of milestone %d to the ledger state ... done!", targetIndex)
+
+	// Set the valid solid milestone index
+	tangle.OverwriteSolidMilestoneIndex(targetIndex)
+
+	return nil
+}
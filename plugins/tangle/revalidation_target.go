@@ -0,0 +1,136 @@
+package tangle
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iotaledger/hive.go/daemon"
+
+	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+)
+
+var (
+	// returned by the traversal if the cone of a milestone is inconsistent.
+	errMilestoneConeNotVerified = errors.New("milestone cone could not be verified")
+)
+
+// findLastVerifiedMilestone searches the newest milestone the database can be rolled back to after an unclean shutdown.
+// A milestone is verified if all transactions of its cone are stored and confirmed by it (or older milestones),
+// and if the ledger state of the milestone can be derived from the stored ledger diffs.
+// Returns the snapshot index and no balances if no milestone newer than the last local snapshot could be verified.
+func findLastVerifiedMilestone(info *tangle.SnapshotInfo) (milestone.Index, map[string]uint64, error) {
+
+	start := time.Now()
+
+	ledgerIndex := tangle.Ledger().MilestoneIndex()
+	if ledgerIndex <= info.SnapshotIndex {
+		return info.SnapshotIndex, nil, nil
+	}
+
+	log.Infof("verifying milestones %d-%d...", info.SnapshotIndex+1, ledgerIndex)
+
+	// milestones are verified in ascending order, so transactions confirmed by older milestones
+	// were already verified and don't need to be traversed again.
+	targetIndex := info.SnapshotIndex
+	lastStatusTime := time.Now()
+	for msIndex := info.SnapshotIndex + 1; msIndex <= ledgerIndex; msIndex++ {
+
+		if time.Since(lastStatusTime) >= printStatusInterval {
+			lastStatusTime = time.Now()
+
+			if daemon.IsStopped() {
+				return 0, nil, tangle.ErrOperationAborted
+			}
+
+			log.Infof("verified milestones %d-%d", info.SnapshotIndex+1, targetIndex)
+		}
+
+		verified, err := verifyMilestoneCone(msIndex)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if !verified {
+			log.Warnf("milestone %d could not be verified", msIndex)
+			break
+		}
+		targetIndex = msIndex
+	}
+
+	if targetIndex == info.SnapshotIndex {
+		return info.SnapshotIndex, nil, nil
+	}
+
+	// Get the ledger state of the last snapshot
+	snapshotBalances, snapshotIndex, err := tangle.GetAllSnapshotBalances(nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if info.SnapshotIndex != snapshotIndex {
+		return 0, nil, ErrSnapshotIndexWrong
+	}
+
+	tangle.Ledger().RLock()
+	balances, err := tangle.Ledger().VerifyLedgerStateWithoutLocking(targetIndex, snapshotBalances, snapshotIndex, nil)
+	tangle.Ledger().RUnlock()
+	if err != nil {
+		if errors.Is(err, tangle.ErrLedgerStateInvalid) {
+			log.Warnf("ledger state could not be verified: %s", err)
+			return info.SnapshotIndex, nil, nil
+		}
+		return 0, nil, err
+	}
+
+	log.Infof("verified milestones %d-%d, took %v", info.SnapshotIndex+1, targetIndex, time.Since(start).Truncate(time.Millisecond))
+
+	return targetIndex, balances, nil
+}
+
+// verifyMilestoneCone checks whether all transactions of the cone of the given milestone
+// are stored, solid and confirmed by the milestone or older ones.
+func verifyMilestoneCone(msIndex milestone.Index) (bool, error) {
+
+	cachedMs := tangle.GetCachedMilestoneOrNil(msIndex) // milestone +1
+	if cachedMs == nil {
+		return false, nil
+	}
+	defer cachedMs.Release(true) // milestone -1
+
+	err := dag.TraverseApprovees(cachedMs.GetMilestone().ID.Hash(),
+		// traversal stops if no more transactions pass the given condition
+		// Caution: condition func is not in DFS order
+		func(cachedTxMeta *tangle.CachedMetadata) (bool, error) { // meta +1
+			defer cachedTxMeta.Release(true) // meta -1
+
+			metadata := cachedTxMeta.GetMetadata()
+			confirmed, by := metadata.GetConfirmed()
+			if !confirmed || by > msIndex || !metadata.IsSolid() || !tangle.TransactionExistsInStore(metadata.GetTxHash()) {
+				return false, errMilestoneConeNotVerified
+			}
+
+			// transactions confirmed by older milestones were already verified
+			return by == msIndex, nil
+		},
+		// consumer
+		nil,
+		// called on missing approvees
+		func(approveeHash hornet.Hash) error { return errMilestoneConeNotVerified },
+		// called on solid entry points
+		nil,
+		false,
+		false,
+		nil)
+
+	if err != nil {
+		if errors.Is(err, errMilestoneConeNotVerified) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
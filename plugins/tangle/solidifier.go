@@ -4,11 +4,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"runtime"
 	"time"
 
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/syncutils"
-	"github.com/iotaledger/hive.go/workerpool"
 
 	"github.com/gohornet/hornet/pkg/dag"
 	"github.com/gohornet/hornet/pkg/metrics"
@@ -17,11 +17,15 @@ import (
 	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/pkg/utils"
 	"github.com/gohornet/hornet/pkg/whiteflag"
+	"github.com/gohornet/hornet/pkg/workerpool"
 	"github.com/gohornet/hornet/plugins/gossip"
 )
 
 const (
 	solidifierThreshold = 60 * time.Second
+
+	// futureConeSolidificationsWarningInterval is the minimum interval between two warnings about dropped future cone solidifications.
+	futureConeSolidificationsWarningInterval = 10 * time.Second
 )
 
 var (
@@ -29,6 +33,15 @@ var (
 	milestoneSolidifierQueueSize   = 2
 	milestoneSolidifierWorkerPool  *workerpool.WorkerPool
 
+	futureConeSolidifierWorkerCount = runtime.NumCPU()
+	futureConeSolidifierQueueSize   = 10000
+	futureConeSolidifierWorkerPool  *workerpool.WorkerPool
+
+	// the future cone solidifications which were dropped since the last warning.
+	futureConeSolidificationsDropped     int
+	lastFutureConeSolidificationsWarning time.Time
+	futureConeSolidificationsDroppedLock syncutils.Mutex
+
 	signalChanMilestoneStopSolidification     chan struct{}
 	signalChanMilestoneStopSolidificationLock syncutils.Mutex
 
@@ -75,6 +88,14 @@ func markTransactionAsSolid(cachedTxMeta *tangle.CachedMetadata) {
 	// update the solidity flags of this transaction
 	cachedTxMeta.GetMetadata().SetSolid(true)
 
+	// only track the latency if the node is synced, otherwise old transactions would distort the statistics.
+	// the latency is measured from the arrival at the node, since the attachment timestamps are set by the clients.
+	if cachedTxMeta.GetMetadata().IsTail() && tangle.IsNodeSynced() {
+		if arrivalTimestamp := cachedTxMeta.GetMetadata().GetArrivalTimestamp(); arrivalTimestamp != 0 {
+			metrics.SharedTangleStatistics.TimeToSolid.Observe(time.Duration(cachedTxMeta.GetMetadata().GetSolidificationTimestamp()-arrivalTimestamp) * time.Second)
+		}
+	}
+
 	Events.TransactionSolid.Trigger(cachedTxMeta.GetMetadata().GetTxHash())
 
 	if cachedTxMeta.GetMetadata().IsTail() {
@@ -116,6 +137,24 @@ func markTransactionAsSolid(cachedTxMeta *tangle.CachedMetadata) {
 	}
 }
 
+// onFutureConeSolidificationDropped counts a future cone solidification which was dropped because the queue was full.
+// the drops are logged at most once per futureConeSolidificationsWarningInterval.
+func onFutureConeSolidificationDropped() {
+	metrics.SharedServerMetrics.DroppedFutureConeSolidifications.Inc()
+
+	futureConeSolidificationsDroppedLock.Lock()
+	defer futureConeSolidificationsDroppedLock.Unlock()
+
+	futureConeSolidificationsDropped++
+	if time.Since(lastFutureConeSolidificationsWarning) < futureConeSolidificationsWarningInterval {
+		return
+	}
+
+	log.Warnf("FutureConeSolidifier queue is full, dropped %d future cone solidifications, the milestone solidifier will propagate the solidity", futureConeSolidificationsDropped)
+	futureConeSolidificationsDropped = 0
+	lastFutureConeSolidificationsWarning = time.Now()
+}
+
 // solidQueueCheck traverses a milestone and checks if it is solid
 // Missing tx are requested
 // Can be aborted with abortSignal
@@ -185,6 +224,7 @@ func solidQueueCheck(cachedTxMetas map[string]*tangle.CachedMetadata, milestoneI
 			txHashes = append(txHashes, hornet.Hash(txHash))
 		}
 		requested := gossip.RequestMultiple(txHashes, milestoneIndex, true)
+		Events.MissingTransactionsRequested.Trigger(milestoneIndex, requested, len(txHashes))
 		log.Warnf("Stopped solidifier due to missing tx -> Requested missing txs (%d/%d), collect: %v", requested, len(txHashes), tCollect.Sub(ts).Truncate(time.Millisecond))
 		return false, false
 	}
@@ -202,7 +242,7 @@ func solidQueueCheck(cachedTxMetas map[string]*tangle.CachedMetadata, milestoneI
 
 	tSolid := time.Now()
 
-	if tangle.IsNodeSyncedWithThreshold() {
+	if tangle.IsNodeAlmostSynced() {
 		// propagate solidity to the future cone (txs attached to the txs of this milestone)
 		solidifyFutureCone(cachedTxMetas, txsToSolidify, abortSignal)
 	}
@@ -489,7 +529,7 @@ func getConfirmedMilestoneMetric(cachedMsTailTx *tangle.CachedTransaction, miles
 	}
 	defer oldMilestone.Release(true) // milestone -1
 
-	oldMilestoneTailTx := tangle.GetCachedTransactionOrNil(oldMilestone.GetMilestone().Hash)
+	oldMilestoneTailTx := tangle.GetCachedTransactionOrNil(oldMilestone.GetMilestone().ID.Hash())
 	if oldMilestoneTailTx == nil {
 		return nil, ErrMilestoneNotFound
 	}
@@ -7,7 +7,6 @@ import (
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/node"
-	"github.com/iotaledger/hive.go/workerpool"
 
 	"github.com/gohornet/hornet/pkg/metrics"
 	"github.com/gohornet/hornet/pkg/model/hornet"
@@ -16,6 +15,7 @@ import (
 	"github.com/gohornet/hornet/pkg/peering/peer"
 	"github.com/gohornet/hornet/pkg/protocol/rqueue"
 	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/workerpool"
 	"github.com/gohornet/hornet/plugins/gossip"
 	metricsplugin "github.com/gohornet/hornet/plugins/metrics"
 )
@@ -35,23 +35,39 @@ func configureTangleProcessor(_ *node.Plugin) {
 	receiveTxWorkerPool = workerpool.New(func(task workerpool.Task) {
 		processIncomingTx(task.Param(0).(*hornet.Transaction), task.Param(1).(*rqueue.Request), task.Param(2).(*peer.Peer))
 		task.Return(nil)
-	}, workerpool.WorkerCount(receiveTxWorkerCount), workerpool.QueueSize(receiveTxQueueSize))
+	}, workerpool.Name("ReceiveTx"), workerpool.WorkerCount(receiveTxWorkerCount), workerpool.QueueSize(receiveTxQueueSize))
+
+	receiveTxWorkerPool.Events.QueueSaturated.Attach(events.NewClosure(func(priority workerpool.Priority, queueLength int) {
+		log.Warnf("ReceiveTx queue of %s priority is saturated: %d/%d", priority, queueLength, receiveTxQueueSize)
+	}))
 
 	processValidMilestoneWorkerPool = workerpool.New(func(task workerpool.Task) {
 		processValidMilestone(task.Param(0).(*tangle.CachedBundle)) // bundle pass +1
 		task.Return(nil)
-	}, workerpool.WorkerCount(processValidMilestoneWorkerCount), workerpool.QueueSize(processValidMilestoneQueueSize), workerpool.FlushTasksAtShutdown(true))
+	}, workerpool.Name("ProcessMilestone"), workerpool.WorkerCount(processValidMilestoneWorkerCount), workerpool.QueueSize(processValidMilestoneQueueSize), workerpool.FlushTasksAtShutdown(true))
 
 	milestoneSolidifierWorkerPool = workerpool.New(func(task workerpool.Task) {
 		solidifyMilestone(task.Param(0).(milestone.Index), task.Param(1).(bool))
 		task.Return(nil)
-	}, workerpool.WorkerCount(milestoneSolidifierWorkerCount), workerpool.QueueSize(milestoneSolidifierQueueSize))
+	}, workerpool.Name("MilestoneSolidifier"), workerpool.WorkerCount(milestoneSolidifierWorkerCount), workerpool.QueueSize(milestoneSolidifierQueueSize))
+
+	futureConeSolidifierWorkerPool = workerpool.New(func(task workerpool.Task) {
+		if err := solidifyFutureConeOfTx(task.Param(0).(*tangle.CachedMetadata)); err != nil { // meta pass +1
+			log.Warnf("solidifyFutureConeOfTx failed: %s", err)
+		}
+		task.Return(nil)
+	}, workerpool.Name("FutureConeSolidifier"), workerpool.WorkerCount(futureConeSolidifierWorkerCount), workerpool.QueueSize(futureConeSolidifierQueueSize), workerpool.FlushTasksAtShutdown(true))
 }
 
 func runTangleProcessor(_ *node.Plugin) {
 	log.Info("Starting TangleProcessor ...")
 
 	onTransactionProcessed := events.NewClosure(func(transaction *hornet.Transaction, request *rqueue.Request, p *peer.Peer) {
+		if request != nil {
+			// requested transactions are part of milestone cones and needed for the solidification
+			receiveTxWorkerPool.SubmitWithPriority(workerpool.PriorityHigh, transaction, request, p)
+			return
+		}
 		receiveTxWorkerPool.Submit(transaction, request, p)
 	})
 
@@ -120,13 +136,13 @@ func IsReceiveTxWorkerPoolBusy() bool {
 func processIncomingTx(incomingTx *hornet.Transaction, request *rqueue.Request, p *peer.Peer) {
 
 	latestMilestoneIndex := tangle.GetLatestMilestoneIndex()
-	isNodeSyncedWithThreshold := tangle.IsNodeSyncedWithThreshold()
+	isNodeAlmostSynced := tangle.IsNodeAlmostSynced()
 
 	// The tx will be added to the storage inside this function, so the transaction object automatically updates
-	cachedTx, alreadyAdded := tangle.AddTransactionToStorage(incomingTx, latestMilestoneIndex, request != nil, !isNodeSyncedWithThreshold, false) // tx +1
+	cachedTx, alreadyAdded := tangle.AddTransactionToStorage(incomingTx, latestMilestoneIndex, request != nil, !isNodeAlmostSynced, false) // tx +1
 
 	// Release shouldn't be forced, to cache the latest transactions
-	defer cachedTx.Release(!isNodeSyncedWithThreshold) // tx -1
+	defer cachedTx.Release(!isNodeAlmostSynced) // tx -1
 
 	if !alreadyAdded {
 		metrics.SharedServerMetrics.NewTransactions.Inc()
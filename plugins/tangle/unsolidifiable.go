@@ -0,0 +1,70 @@
+package tangle
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/events"
+
+	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/plugins/gossip"
+)
+
+var (
+	onRequestsExhausted *events.Closure
+)
+
+func configureUnsolidifiableEvents() {
+	onRequestsExhausted = events.NewClosure(func(txHashes hornet.Hashes) {
+		markUnsolidifiableTransactions(txHashes)
+	})
+}
+
+func attachUnsolidifiableEvents() {
+	gossip.Events.RequestsExhausted.Attach(onRequestsExhausted)
+}
+
+func detachUnsolidifiableEvents() {
+	gossip.Events.RequestsExhausted.Detach(onRequestsExhausted)
+}
+
+// markUnsolidifiableTransactions marks the future cone of the given missing transactions as unsolidifiable.
+// The transactions of the cone are neither requested again nor selected as tips,
+// and get removed from the database by the snapshot plugin.
+func markUnsolidifiableTransactions(missingTxHashes hornet.Hashes) {
+
+	var unsolidifiableTxHashes hornet.Hashes
+
+	for _, missingTxHash := range missingTxHashes {
+		for _, approverHash := range tangle.GetApproverHashes(missingTxHash) {
+			err := dag.TraverseApprovers(approverHash,
+				// traversal stops if no more transactions pass the given condition
+				func(cachedTxMeta *tangle.CachedMetadata) (bool, error) { // meta +1
+					defer cachedTxMeta.Release(true) // meta -1
+
+					// solid transactions don't reference the missing transaction
+					return !cachedTxMeta.GetMetadata().IsSolid() && !cachedTxMeta.GetMetadata().IsUnsolidifiable(), nil
+				},
+				// consumer
+				func(cachedTxMeta *tangle.CachedMetadata) error { // meta +1
+					defer cachedTxMeta.Release(true) // meta -1
+
+					cachedTxMeta.GetMetadata().SetUnsolidifiable(true)
+					unsolidifiableTxHashes = append(unsolidifiableTxHashes, cachedTxMeta.GetMetadata().GetTxHash())
+					return nil
+				}, false, nil)
+
+			if err != nil && !errors.Is(err, tangle.ErrTransactionNotFound) {
+				log.Warnf("marking the approvers of %s as unsolidifiable failed: %v", missingTxHash.Trytes(), err)
+			}
+		}
+	}
+
+	if len(unsolidifiableTxHashes) == 0 {
+		return
+	}
+
+	log.Infof("marked %d transactions as unsolidifiable, their past cone references %d transactions no neighbor answered requests for", len(unsolidifiableTxHashes), len(missingTxHashes))
+	Events.TransactionsUnsolidifiable.Trigger(unsolidifiableTxHashes)
+}
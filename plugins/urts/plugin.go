@@ -10,6 +10,7 @@ import (
 
 	"github.com/gohornet/hornet/pkg/config"
 	"github.com/gohornet/hornet/pkg/dag"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/pkg/tipselect"
@@ -23,13 +24,21 @@ var (
 
 	TipSelector *tipselect.TipSelector
 
+	// rootSnapshotIndexesSignal signals the root snapshot indexes worker that a milestone was confirmed
+	rootSnapshotIndexesSignal = make(chan struct{}, 1)
+
 	// Closures
 	onBundleSolid        *events.Closure
 	onMilestoneConfirmed *events.Closure
 )
 
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
+
+	strategy, err := tipselect.NewStrategy(config.NodeConfig.GetString(config.CfgTipSelStrategy), config.NodeConfig.GetInt(config.CfgTipSelBelowMaxDepth))
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	TipSelector = tipselect.New(
 		config.NodeConfig.GetInt(config.CfgTipSelMaxDeltaTxYoungestRootSnapshotIndexToLSMI),
@@ -45,6 +54,8 @@ func configure(plugin *node.Plugin) {
 		time.Duration(time.Second*time.Duration(config.NodeConfig.GetInt(config.CfgTipSelSemiLazy+config.CfgTipSelMaxReferencedTipAgeSeconds))),
 		config.NodeConfig.GetUint32(config.CfgTipSelSemiLazy+config.CfgTipSelMaxApprovers),
 		config.NodeConfig.GetInt(config.CfgTipSelSemiLazy+config.CfgTipSelSpammerTipsThreshold),
+
+		strategy,
 	)
 
 	configureEvents()
@@ -69,13 +80,36 @@ func run(_ *node.Plugin) {
 			}
 		}
 	}, shutdown.PriorityTipselection)
+
+	daemon.BackgroundWorker("Tipselection[RootSnapshotIndexes]", func(shutdownSignal <-chan struct{}) {
+		batchSize := config.NodeConfig.GetInt(config.CfgTipSelRootSnapshotIndexesBatchSize)
+		batchDelay := time.Duration(config.NodeConfig.GetInt(config.CfgTipSelRootSnapshotIndexesBatchDelayMs)) * time.Millisecond
+
+		for {
+			select {
+			case <-shutdownSignal:
+				return
+			case <-rootSnapshotIndexesSignal:
+				ts := time.Now()
+				processedTipCount, err := TipSelector.UpdateRootSnapshotIndexes(batchSize, batchDelay, shutdownSignal)
+				if err != nil {
+					return
+				}
+				log.Debugf("UpdateRootSnapshotIndexes finished, processed: %d, took: %v", processedTipCount, time.Since(ts).Truncate(time.Millisecond))
+
+				ts = time.Now()
+				removedTipCount := TipSelector.UpdateScores()
+				log.Debugf("UpdateScores finished, removed: %d, took: %v", removedTipCount, time.Since(ts).Truncate(time.Millisecond))
+			}
+		}
+	}, shutdown.PriorityTipselection)
 }
 
 func configureEvents() {
 	onBundleSolid = events.NewClosure(func(cachedBndl *tangle.CachedBundle) {
 		cachedBndl.ConsumeBundle(func(bndl *tangle.Bundle) { // bundle -1
 			// do not add tips during syncing, because it is not needed at all
-			if !tangle.IsNodeSyncedWithThreshold() {
+			if !tangle.IsNodeAlmostSynced() {
 				return
 			}
 
@@ -84,13 +118,21 @@ func configureEvents() {
 				return
 			}
 
+			cachedTailMeta := bndl.GetTailMetadata() // meta +1
+			unsolidifiable := cachedTailMeta.GetMetadata().IsUnsolidifiable()
+			cachedTailMeta.Release(true) // meta -1
+			if unsolidifiable {
+				// ignore bundles which were marked as unsolidifiable, they are removed from the database
+				return
+			}
+
 			TipSelector.AddTip(bndl)
 		})
 	})
 
 	onMilestoneConfirmed = events.NewClosure(func(confirmation *whiteflag.Confirmation) {
 		// do not propagate during syncing, because it is not needed at all
-		if !tangle.IsNodeSyncedWithThreshold() {
+		if !tangle.IsNodeAlmostSynced() {
 			return
 		}
 
@@ -99,9 +141,12 @@ func configureEvents() {
 		dag.UpdateTransactionRootSnapshotIndexes(confirmation.Mutations.TailsReferenced, confirmation.MilestoneIndex)
 		log.Debugf("UpdateTransactionRootSnapshotIndexes finished, took: %v", time.Since(ts).Truncate(time.Millisecond))
 
-		ts = time.Now()
-		removedTipCount := TipSelector.UpdateScores()
-		log.Debugf("UpdateScores finished, removed: %d, took: %v", removedTipCount, time.Since(ts).Truncate(time.Millisecond))
+		// recalculate the stale root snapshot indexes of the tips and update their scores in the background.
+		// if a recalculation is already pending, it will pick up the latest solid milestone.
+		select {
+		case rootSnapshotIndexesSignal <- struct{}{}:
+		default:
+		}
 	})
 }
 
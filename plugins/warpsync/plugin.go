@@ -3,16 +3,18 @@ package warpsync
 import (
 	"time"
 
+	"github.com/gohornet/hornet/pkg/app"
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/model/tangle"
+	peeringpackage "github.com/gohornet/hornet/pkg/peering"
 	"github.com/gohornet/hornet/pkg/peering/peer"
 	"github.com/gohornet/hornet/pkg/protocol/rqueue"
 	"github.com/gohornet/hornet/pkg/protocol/sting"
 	"github.com/gohornet/hornet/pkg/protocol/warpsync"
 	"github.com/gohornet/hornet/pkg/shutdown"
 	"github.com/gohornet/hornet/plugins/gossip"
-	peeringplugin "github.com/gohornet/hornet/plugins/peering"
 	tangleplugin "github.com/gohornet/hornet/plugins/tangle"
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/events"
@@ -34,8 +36,22 @@ var (
 	onDone                          *events.Closure
 )
 
+// dependencies of the plugin which are injected before it is configured.
+type dependencies struct {
+	app.In
+	PeeringManager *peeringpackage.Manager
+}
+
+var deps dependencies
+
+func init() {
+	app.Inject(PLUGIN, func(d dependencies) {
+		deps = d
+	})
+}
+
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 	warpSync = warpsync.New(config.NodeConfig.GetInt(config.CfgWarpSyncAdvancementRange))
 
 	configureEvents()
@@ -57,9 +73,16 @@ func configureEvents() {
 			return
 		}
 
-		p.Events.HeartbeatUpdated.Attach(events.NewClosure(func(hb *sting.Heartbeat) {
+		onHeartbeatUpdated := events.NewClosure(func(hb *sting.Heartbeat) {
 			warpSync.UpdateCurrent(tangle.GetSolidMilestoneIndex())
 			warpSync.UpdateTarget(hb.SolidMilestoneIndex)
+		})
+		p.Events.HeartbeatUpdated.Attach(onHeartbeatUpdated)
+
+		// the peer object is kept by the manager for reconnects,
+		// so the closure must be removed to not accumulate handlers
+		p.Conn.Events.Close.Attach(events.NewClosure(func() {
+			p.Events.HeartbeatUpdated.Detach(onHeartbeatUpdated)
 		}))
 	})
 
@@ -70,7 +93,7 @@ func configureEvents() {
 	onMilestoneSolidificationFailed = events.NewClosure(func(msIndex milestone.Index) {
 		if warpSync.CurrentCheckpoint < msIndex {
 			// rerequest since milestone requests could have been lost
-			log.Infof("Requesting missing milestones %d - %d", msIndex, msIndex+milestone.Index(warpSync.AdvancementRange))
+			log.Infof("Requesting missing milestones %d - %d", msIndex, msIndex+milestone.Index(warpSync.AdvancementRange)-1)
 			gossip.BroadcastMilestoneRequests(warpSync.AdvancementRange, nil)
 		}
 	})
@@ -112,7 +135,7 @@ func configureEvents() {
 }
 
 func attachEvents() {
-	peeringplugin.Manager().Events.PeerConnected.Attach(onPeerConnected)
+	deps.PeeringManager.Events.PeerConnected.Attach(onPeerConnected)
 	tangleplugin.Events.SolidMilestoneIndexChanged.Attach(onSolidMilestoneIndexChanged)
 	tangleplugin.Events.MilestoneSolidificationFailed.Attach(onMilestoneSolidificationFailed)
 	warpSync.Events.CheckpointUpdated.Attach(onCheckpointUpdated)
@@ -122,7 +145,7 @@ func attachEvents() {
 }
 
 func detachEvents() {
-	peeringplugin.Manager().Events.PeerConnected.Detach(onPeerConnected)
+	deps.PeeringManager.Events.PeerConnected.Detach(onPeerConnected)
 	tangleplugin.Events.SolidMilestoneIndexChanged.Detach(onSolidMilestoneIndexChanged)
 	tangleplugin.Events.MilestoneSolidificationFailed.Detach(onMilestoneSolidificationFailed)
 	warpSync.Events.CheckpointUpdated.Detach(onCheckpointUpdated)
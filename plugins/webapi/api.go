@@ -10,6 +10,11 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	// the value of the Retry-After header of submissions which were rejected because the submission queue was full
+	submissionRetryAfterSeconds = "1"
+)
+
 var (
 	// ErrNodeNotSync is returned when the node was not synced.
 	ErrNodeNotSync = errors.New("node not synced")
@@ -53,8 +58,12 @@ func webAPIRoute() {
 			return
 		}
 
-		if !networkWhitelisted(c) {
-			// network is not whitelisted, check if the command is permitted, otherwise deny it.
+		// the metrics are collected per command, unknown commands are not recorded to bound the number of routes
+		c.Set(ctxKeyAPICommand, cmd)
+		c.Set(ctxKeyAPIRequest, request)
+
+		if !privilegedAccess(c) {
+			// network is not whitelisted and no valid JWT given, check if the command is permitted, otherwise deny it.
 			if _, permitted := permittedEndpoints[cmd]; !permitted {
 				c.JSON(http.StatusForbidden, ErrorReturn{Error: fmt.Sprintf("command [%v] is protected", originCmd)})
 				return
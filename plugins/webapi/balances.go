@@ -8,8 +8,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mitchellh/mapstructure"
 
-	"github.com/iotaledger/iota.go/address"
-
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/tangle"
 )
@@ -34,13 +32,16 @@ func getBalances(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		return
 	}
 
-	for _, addr := range query.Addresses {
+	addresses := make(hornet.Hashes, len(query.Addresses))
+	for i, addr := range query.Addresses {
 		// Check if address is valid
-		if err := address.ValidAddress(addr); err != nil {
-			e.Error = fmt.Sprintf("%v: %v", err, addr)
+		addrHash, err := hornet.AddressFromTrytes(addr)
+		if err != nil {
+			e.Error = err.Error()
 			c.JSON(http.StatusBadRequest, e)
 			return
 		}
+		addresses[i] = addrHash
 	}
 
 	if !tangle.WaitForNodeSynced(waitForNodeSyncedTimeout) {
@@ -49,8 +50,8 @@ func getBalances(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		return
 	}
 
-	tangle.ReadLockLedger()
-	defer tangle.ReadUnlockLedger()
+	tangle.Ledger().RLock()
+	defer tangle.Ledger().RUnlock()
 
 	cachedLatestSolidMs := tangle.GetMilestoneOrNil(tangle.GetSolidMilestoneIndex()) // bundle +1
 	if cachedLatestSolidMs == nil {
@@ -62,9 +63,9 @@ func getBalances(i interface{}, c *gin.Context, _ <-chan struct{}) {
 
 	result := GetBalancesReturn{}
 
-	for _, addr := range query.Addresses {
+	for _, addr := range addresses {
 
-		balance, _, err := tangle.GetBalanceForAddressWithoutLocking(hornet.HashFromAddressTrytes(addr))
+		balance, _, err := tangle.Ledger().GetBalanceForAddressWithoutLocking(addr)
 		if err != nil {
 			e.Error = "Ledger state invalid"
 			c.JSON(http.StatusInternalServerError, e)
@@ -10,7 +10,6 @@ import (
 	"github.com/mitchellh/mapstructure"
 
 	"github.com/iotaledger/hive.go/daemon"
-	"github.com/iotaledger/iota.go/guards"
 
 	"github.com/gohornet/hornet/pkg/dag"
 	"github.com/gohornet/hornet/pkg/model/hornet"
@@ -21,6 +20,7 @@ import (
 
 func init() {
 	addEndpoint("getRequests", getRequests, implementedAPIcalls)
+	addEndpoint("getStorePrefixes", getStorePrefixes, implementedAPIcalls)
 	addEndpoint("searchConfirmedApprover", searchConfirmedApprover, implementedAPIcalls)
 	addEndpoint("searchEntryPoints", searchEntryPoints, implementedAPIcalls)
 	addEndpoint("triggerSolidifier", triggerSolidifier, implementedAPIcalls)
@@ -67,6 +67,23 @@ func getRequests(_ interface{}, c *gin.Context, _ <-chan struct{}) {
 	c.JSON(http.StatusOK, GetRequestsReturn{Requests: debugReqs})
 }
 
+func getStorePrefixes(_ interface{}, c *gin.Context, _ <-chan struct{}) {
+	result := GetStorePrefixesReturn{UnknownPrefixes: make(map[string][]int)}
+
+	for _, prefix := range tangle.StorePrefixes() {
+		result.Prefixes = append(result.Prefixes, &StorePrefix{Prefix: int(prefix.Prefix), Name: prefix.Name, Database: prefix.Database})
+	}
+
+	// the unknown prefixes were searched for at startup
+	for database, prefixes := range tangle.UnknownStorePrefixes() {
+		for _, prefix := range prefixes {
+			result.UnknownPrefixes[database] = append(result.UnknownPrefixes[database], int(prefix))
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 func createConfirmedApproverResult(confirmedTxHash hornet.Hash, path []bool) ([]*ApproverStruct, error) {
 
 	tanglePath := make([]*ApproverStruct, 0)
@@ -107,14 +124,15 @@ func searchConfirmedApprover(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		return
 	}
 
-	if !guards.IsTransactionHash(query.TxHash) {
+	startTxHash, err := hornet.HashFromTrytes(query.TxHash)
+	if err != nil {
 		e.Error = fmt.Sprintf("Invalid hash supplied: %s", query.TxHash)
 		c.JSON(http.StatusBadRequest, e)
 		return
 	}
 
 	txsToTraverse := make(map[string][]bool)
-	txsToTraverse[string(hornet.HashFromHashTrytes(query.TxHash))] = make([]bool, 0)
+	txsToTraverse[string(startTxHash)] = make([]bool, 0)
 
 	// Collect all tx to check by traversing the tangle
 	// Loop as long as new transactions are added in every loop cycle
@@ -207,13 +225,14 @@ func searchEntryPoints(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		return
 	}
 
-	if !guards.IsTransactionHash(query.TxHash) {
+	startTxHash, err := hornet.HashFromTrytes(query.TxHash)
+	if err != nil {
 		e.Error = fmt.Sprintf("Invalid hash supplied: %s", query.TxHash)
 		c.JSON(http.StatusBadRequest, e)
 		return
 	}
 
-	cachedStartTxMeta := tangle.GetCachedTxMetadataOrNil(hornet.HashFromHashTrytes(query.TxHash)) // meta +1
+	cachedStartTxMeta := tangle.GetCachedTxMetadataOrNil(startTxHash) // meta +1
 	if cachedStartTxMeta == nil {
 		e.Error = fmt.Sprintf("Start transaction not found: %v", query.TxHash)
 		c.JSON(http.StatusBadRequest, e)
@@ -285,7 +304,7 @@ func getFundsOnSpentAddresses(i interface{}, c *gin.Context, _ <-chan struct{})
 		return
 	}
 
-	balances, _, err := tangle.GetLedgerStateForLSMI(nil)
+	balances, _, err := tangle.Ledger().GetLedgerStateForLSMI(nil)
 	if err != nil {
 		e.Error = fmt.Sprintf("%v: %v", ErrInternalError, err)
 		c.JSON(http.StatusInternalServerError, e)
@@ -294,7 +313,13 @@ func getFundsOnSpentAddresses(i interface{}, c *gin.Context, _ <-chan struct{})
 
 	for address := range balances {
 		if tangle.WasAddressSpentFrom(hornet.Hash(address)) {
-			result.Addresses = append(result.Addresses, &AddressWithBalance{Address: hornet.Hash(address).Trytes(), Balance: balances[address]})
+			addressTrytes, err := hornet.Hash(address).ToTrytes()
+			if err != nil {
+				e.Error = fmt.Sprintf("%v: %v", ErrInternalError, err)
+				c.JSON(http.StatusInternalServerError, e)
+				return
+			}
+			result.Addresses = append(result.Addresses, &AddressWithBalance{Address: addressTrytes, Balance: balances[address]})
 		}
 	}
 
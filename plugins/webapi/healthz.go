@@ -5,21 +5,14 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/iotaledger/hive.go/daemon"
+
 	"github.com/gohornet/hornet/pkg/config"
 	"github.com/gohornet/hornet/plugins/tangle"
 )
 
 func healthzRoute() {
-	api.GET("/healthz", func(c *gin.Context) {
-
-		if !networkWhitelisted(c) {
-			// network is not whitelisted, check if the route is permitted, otherwise deny it.
-			if _, permitted := permittedRESTroutes["healthz"]; !permitted {
-				c.JSON(http.StatusForbidden, ErrorReturn{Error: "route [healthz] is protected"})
-				return
-			}
-		}
-
+	api.GET("/healthz", healthRouteHandler("healthz", func(c *gin.Context) {
 		// autopeering entrypoint mode
 		if config.NodeConfig.GetBool(config.CfgNetAutopeeringRunAsEntryNode) {
 			c.Status(http.StatusOK)
@@ -33,5 +26,48 @@ func healthzRoute() {
 		}
 
 		c.Status(http.StatusOK)
-	})
+	}))
+
+	// liveness probe, the node is alive as long as it is not shutting down
+	api.GET("/health", healthRouteHandler("health", func(c *gin.Context) {
+		if daemon.IsStopped() {
+			c.Status(http.StatusServiceUnavailable)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}))
+
+	// readiness probe, the node is ready if it meets the configured health criteria
+	api.GET("/ready", healthRouteHandler("ready", func(c *gin.Context) {
+		// autopeering entrypoint mode
+		if config.NodeConfig.GetBool(config.CfgNetAutopeeringRunAsEntryNode) {
+			c.Status(http.StatusOK)
+			return
+		}
+
+		// node mode
+		status := tangle.GetHealthStatus()
+		if !status.Healthy {
+			c.JSON(http.StatusServiceUnavailable, status)
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}))
+}
+
+// denies the access to the given health route for non whitelisted networks if the route is not permitted.
+func healthRouteHandler(route string, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !privilegedAccess(c) {
+			// network is not whitelisted and no valid JWT given, check if the route is permitted, otherwise deny it.
+			if _, permitted := permittedRESTroutes[route]; !permitted {
+				c.JSON(http.StatusForbidden, ErrorReturn{Error: "route [" + route + "] is protected"})
+				return
+			}
+		}
+
+		handler(c)
+	}
 }
@@ -7,8 +7,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mitchellh/mapstructure"
 
-	"github.com/iotaledger/iota.go/guards"
-
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/tangle"
 )
@@ -27,12 +25,15 @@ func getInclusionStates(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		return
 	}
 
-	for _, tx := range query.Transactions {
-		if !guards.IsTransactionHash(tx) {
+	txHashes := make(hornet.Hashes, len(query.Transactions))
+	for i, tx := range query.Transactions {
+		txHash, err := hornet.HashFromTrytes(tx)
+		if err != nil {
 			e.Error = fmt.Sprintf("Invalid reference hash supplied: %s", tx)
 			c.JSON(http.StatusBadRequest, e)
 			return
 		}
+		txHashes[i] = txHash
 	}
 
 	if !tangle.WaitForNodeSynced(waitForNodeSyncedTimeout) {
@@ -41,24 +42,24 @@ func getInclusionStates(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		return
 	}
 
-	tangle.ReadLockLedger()
-	defer tangle.ReadUnlockLedger()
+	tangle.Ledger().RLock()
+	defer tangle.Ledger().RUnlock()
 
-	inclusionStates := []bool{}
+	// get tx data
+	cachedTxMetas := tangle.GetCachedTxMetadataBatch(txHashes) // meta +1
+	defer cachedTxMetas.Release(true)                          // meta -1
 
-	for _, tx := range query.Transactions {
-		// get tx data
-		cachedTxMeta := tangle.GetCachedTxMetadataOrNil(hornet.HashFromHashTrytes(tx)) // meta +1
+	inclusionStates := []bool{}
 
-		if cachedTxMeta == nil {
+	for _, txHash := range txHashes {
+		cachedTxMeta, exists := cachedTxMetas[string(txHash)]
+		if !exists {
 			// if tx is unknown, return false
 			inclusionStates = append(inclusionStates, false)
 			continue
 		}
 		// check if tx is set as confirmed. Avoid passing true for conflicting tx to be backwards compatible
 		confirmed := cachedTxMeta.GetMetadata().IsConfirmed() && !cachedTxMeta.GetMetadata().IsConflicting()
-
-		cachedTxMeta.Release(true) // meta -1
 		inclusionStates = append(inclusionStates, confirmed)
 	}
 
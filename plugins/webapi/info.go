@@ -13,7 +13,6 @@ import (
 	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/plugins/cli"
 	"github.com/gohornet/hornet/plugins/gossip"
-	"github.com/gohornet/hornet/plugins/peering"
 	tangleplugin "github.com/gohornet/hornet/plugins/tangle"
 )
 
@@ -23,8 +22,14 @@ func init() {
 }
 
 func getNodeInfo(_ interface{}, c *gin.Context, _ <-chan struct{}) {
+	// Return node info
+	c.JSON(http.StatusOK, nodeInfo())
+}
+
+// nodeInfo collects the information about the node which is returned by getNodeInfo and the REST API.
+func nodeInfo() *GetNodeInfoReturn {
 	// Basic info data
-	result := GetNodeInfoReturn{
+	result := &GetNodeInfoReturn{
 		AppName:    cli.AppName,
 		AppVersion: cli.AppVersion,
 	}
@@ -35,7 +40,7 @@ func getNodeInfo(_ interface{}, c *gin.Context, _ <-chan struct{}) {
 	}
 
 	// Number of peers
-	result.Neighbors = uint(peering.Manager().ConnectedPeerCount())
+	result.Neighbors = uint(deps.PeeringManager.ConnectedPeerCount())
 
 	// Latest milestone index
 	lmi := tangle.GetLatestMilestoneIndex()
@@ -53,7 +58,7 @@ func getNodeInfo(_ interface{}, c *gin.Context, _ <-chan struct{}) {
 	smi := tangle.GetSolidMilestoneIndex()
 	result.LatestSolidSubtangleMilestoneIndex = smi
 	result.LatestSolidSubtangleMilestone = consts.NullHashTrytes
-	result.IsSynced = tangle.IsNodeSyncedWithThreshold()
+	result.IsSynced = tangle.IsNodeAlmostSynced()
 	result.Health = tangleplugin.IsNodeHealthy()
 
 	// Solid milestone hash
@@ -90,9 +95,9 @@ func getNodeInfo(_ interface{}, c *gin.Context, _ <-chan struct{}) {
 
 	// Coo addr
 	result.CoordinatorAddress = config.NodeConfig.GetString(config.CfgCoordinatorAddress)
+	result.NetworkID = config.NodeConfig.GetString(config.CfgNetNetworkID)
 
-	// Return node info
-	c.JSON(http.StatusOK, result)
+	return result
 }
 
 func getNodeAPIConfiguration(_ interface{}, c *gin.Context, _ <-chan struct{}) {
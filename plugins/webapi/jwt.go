@@ -0,0 +1,41 @@
+package webapi
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/jwt"
+)
+
+var (
+	// jwtAuth verifies the JWTs of the requests (nil if disabled).
+	jwtAuth *jwt.JWTAuth
+)
+
+// configureJWTAuth creates the JWTAuth if the JWT authentication is enabled.
+func configureJWTAuth() {
+	if !config.NodeConfig.GetBool(config.CfgWebAPIJWTAuthEnabled) {
+		return
+	}
+
+	var err error
+	if jwtAuth, err = jwt.NewAPIAuthFromConfig(); err != nil {
+		log.Fatalf("JWT authentication is enabled, but unable to create the JWTs: %s", err)
+	}
+	log.Info("JWT authentication enabled")
+}
+
+// jwtAuthorized returns whether the request carries a valid JWT.
+func jwtAuthorized(c *gin.Context) bool {
+	if jwtAuth == nil {
+		return false
+	}
+
+	return jwtAuth.VerifyAuthorizationHeader(c.GetHeader("Authorization")) == nil
+}
+
+// privilegedAccess returns whether the request may access routes and API calls which are not permitted for everyone.
+// that is the case for whitelisted networks and requests with a valid JWT.
+func privilegedAccess(c *gin.Context) bool {
+	return networkWhitelisted(c) || jwtAuthorized(c)
+}
@@ -38,7 +38,7 @@ func getLedgerDiff(i interface{}, c *gin.Context, abortSignal <-chan struct{}) {
 		return
 	}
 
-	diff, err := tangle.GetLedgerDiffForMilestone(requestedIndex, abortSignal)
+	diff, err := tangle.Ledger().GetLedgerDiffForMilestone(requestedIndex, abortSignal)
 	if err != nil {
 		e.Error = fmt.Sprintf("%v: %v", ErrInternalError, err)
 		c.JSON(http.StatusInternalServerError, e)
@@ -47,7 +47,13 @@ func getLedgerDiff(i interface{}, c *gin.Context, abortSignal <-chan struct{}) {
 
 	diffTrytes := make(map[trinary.Trytes]int64)
 	for address, balance := range diff {
-		diffTrytes[hornet.Hash(address).Trytes()] = balance
+		addressTrytes, err := hornet.Hash(address).ToTrytes()
+		if err != nil {
+			e.Error = fmt.Sprintf("%v: %v", ErrInternalError, err)
+			c.JSON(http.StatusInternalServerError, e)
+			return
+		}
+		diffTrytes[addressTrytes] = balance
 	}
 
 	c.JSON(http.StatusOK, GetLedgerDiffReturn{Diff: diffTrytes, MilestoneIndex: query.MilestoneIndex})
@@ -80,7 +86,13 @@ func getLedgerDiffExt(i interface{}, c *gin.Context, _ <-chan struct{}) {
 
 	ledgerChangesTrytes := make(map[trinary.Trytes]int64)
 	for address, balance := range ledgerChanges {
-		ledgerChangesTrytes[hornet.Hash(address).Trytes()] = balance
+		addressTrytes, err := hornet.Hash(address).ToTrytes()
+		if err != nil {
+			e.Error = fmt.Sprintf("%v: %v", ErrInternalError, err)
+			c.JSON(http.StatusInternalServerError, e)
+			return
+		}
+		ledgerChangesTrytes[addressTrytes] = balance
 	}
 
 	result := GetLedgerDiffExtReturn{}
@@ -221,7 +233,13 @@ func getLedgerState(i interface{}, c *gin.Context, abortSignal <-chan struct{})
 
 	balancesTrytes := make(map[trinary.Trytes]uint64)
 	for address, balance := range balances {
-		balancesTrytes[hornet.Hash(address).Trytes()] = balance
+		addressTrytes, err := hornet.Hash(address).ToTrytes()
+		if err != nil {
+			e.Error = fmt.Sprintf("%v: %v", ErrInternalError, err)
+			c.JSON(http.StatusInternalServerError, e)
+			return
+		}
+		balancesTrytes[addressTrytes] = balance
 	}
 
 	c.JSON(http.StatusOK, GetLedgerStateReturn{Balances: balancesTrytes, MilestoneIndex: index})
@@ -0,0 +1,64 @@
+package webapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gohornet/hornet/pkg/logging"
+)
+
+func loggerRoute() {
+	api.GET("/logger", func(c *gin.Context) {
+
+		if !privilegedAccess(c) {
+			// network is not whitelisted and no valid JWT given, check if the route is permitted, otherwise deny it.
+			if _, permitted := permittedRESTroutes["logger"]; !permitted {
+				c.JSON(http.StatusForbidden, ErrorReturn{Error: "route [logger] is protected"})
+				return
+			}
+		}
+
+		component := c.Query("component")
+
+		switch strings.ToLower(c.Query("cmd")) {
+		case "":
+			defaultLevel, componentLevels := logging.Levels()
+			c.JSON(http.StatusOK, LoggerLevelsReturn{Level: defaultLevel, Components: componentLevels})
+			return
+
+		case "set":
+			level, err := logging.ParseLevel(c.Query("level"))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorReturn{Error: fmt.Errorf("parsing level failed: %w", err).Error()})
+				return
+			}
+
+			if component == "" {
+				logging.SetLevel(level)
+				c.JSON(http.StatusOK, ResultReturn{Message: fmt.Sprintf("set the default level to %s", level)})
+				return
+			}
+
+			logging.SetComponentLevel(component, level)
+			c.JSON(http.StatusOK, ResultReturn{Message: fmt.Sprintf("set the level of %s to %s", component, level)})
+			return
+
+		case "reset":
+			if component == "" {
+				c.JSON(http.StatusBadRequest, ErrorReturn{Error: "no component given"})
+				return
+			}
+
+			logging.ResetComponentLevel(component)
+			c.JSON(http.StatusOK, ResultReturn{Message: fmt.Sprintf("reset the level of %s to the default level", component)})
+			return
+
+		default:
+			c.JSON(http.StatusBadRequest, ErrorReturn{Error: fmt.Sprintf("unknown cmd: %s", strings.ToLower(c.Query("cmd")))})
+			return
+		}
+	})
+}
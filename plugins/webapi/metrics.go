@@ -0,0 +1,91 @@
+package webapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/metrics"
+)
+
+const (
+	// the key of the context value which holds the command of a request of the JSON API.
+	ctxKeyAPICommand = "apiCommand"
+	// the key of the context value which holds the parameters of a request of the JSON API.
+	ctxKeyAPIRequest = "apiRequest"
+
+	// the route name of requests which didn't match any route.
+	unknownRoute = "unknown"
+
+	// the maximum length of the parameters of a slow request in the log.
+	slowRequestMaxParamsLength = 500
+)
+
+var (
+	// the duration after which a request is logged as slow (0 = disabled).
+	slowRequestThreshold time.Duration
+)
+
+func configureAPIMetrics() {
+	slowRequestThreshold = time.Duration(config.NodeConfig.GetInt(config.CfgWebAPISlowRequestsThresholdMilliseconds)) * time.Millisecond
+
+	api.Use(apiMetricsMiddleware)
+}
+
+// apiMetricsRoute returns the name of the route of the given request.
+// Requests of the JSON API are named after their command, since all of them share a single route.
+func apiMetricsRoute(c *gin.Context) string {
+	if cmd, exists := c.Get(ctxKeyAPICommand); exists {
+		return "command " + cmd.(string)
+	}
+
+	if c.FullPath() == "" {
+		// the path is not used, it is chosen by the client
+		return unknownRoute
+	}
+
+	return c.Request.Method + " " + c.FullPath()
+}
+
+// apiRequestParams returns the parameters of the given request for the log.
+func apiRequestParams(c *gin.Context) string {
+	params := c.Request.URL.RequestURI()
+
+	if request, exists := c.Get(ctxKeyAPIRequest); exists {
+		if requestJSON, err := json.Marshal(request); err == nil {
+			params = string(requestJSON)
+		}
+	}
+
+	if len(params) > slowRequestMaxParamsLength {
+		params = params[:slowRequestMaxParamsLength] + "..."
+	}
+
+	return params
+}
+
+// apiMetricsMiddleware measures the duration and the status code of every request per route
+// and logs the requests which took longer than the slow request threshold.
+func apiMetricsMiddleware(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	route := apiMetricsRoute(c)
+	statusCode := c.Writer.Status()
+
+	if c.FullPath() == wsRoute {
+		// the duration of a websocket connection doesn't tell anything about the load of the node
+		metrics.SharedAPIMetrics.Observe(route, statusCode, -1)
+		return
+	}
+
+	duration := time.Since(start)
+	metrics.SharedAPIMetrics.Observe(route, statusCode, duration)
+
+	if slowRequestThreshold != 0 && duration >= slowRequestThreshold {
+		log.Warnf("slow request: %s took %v, status %d, remote %s, params: %s", route, duration.Truncate(time.Millisecond), statusCode, c.ClientIP(), apiRequestParams(c))
+	}
+}
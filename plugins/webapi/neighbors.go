@@ -9,7 +9,6 @@ import (
 	"github.com/mitchellh/mapstructure"
 
 	"github.com/gohornet/hornet/pkg/config"
-	"github.com/gohornet/hornet/plugins/peering"
 )
 
 func init() {
@@ -73,7 +72,7 @@ func addNeighbors(i interface{}, c *gin.Context, _ <-chan struct{}) {
 			added = true
 		}
 
-		if err := peering.Manager().Add(uri, preferIPv6, uri); err != nil {
+		if err := deps.PeeringManager.Add(uri, preferIPv6, uri, ""); err != nil {
 			log.Warnf("can't add peer %s, Error: %s", uri, err)
 			continue
 		}
@@ -121,11 +120,12 @@ func addNeighborsWithAlias(s *AddNeighborsHornet, c *gin.Context) {
 				ID:         peer.Identity,
 				Alias:      peer.Alias,
 				PreferIPv6: peer.PreferIPv6,
+				PublicKey:  peer.PublicKey,
 			})
 			added = true
 		}
 
-		if err := peering.Manager().Add(peer.Identity, peer.PreferIPv6, peer.Alias); err != nil {
+		if err := deps.PeeringManager.Add(peer.Identity, peer.PreferIPv6, peer.Alias, peer.PublicKey); err != nil {
 			log.Warnf("Can't add peer %s, Error: %s", peer.Identity, err)
 			continue
 		}
@@ -161,7 +161,7 @@ func removeNeighbors(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		log.Warn(err)
 	}
 
-	peers := peering.Manager().PeerInfos()
+	peers := deps.PeeringManager.PeerInfos()
 	for _, uri := range query.Uris {
 		if strings.Contains(uri, "tcp://") {
 			uri = uri[6:]
@@ -182,7 +182,7 @@ func removeNeighbors(i interface{}, c *gin.Context, _ <-chan struct{}) {
 			// Remove connected neighbor
 			if p.Peer != nil {
 				if strings.EqualFold(p.Peer.ID, uri) || strings.EqualFold(p.DomainWithPort, uri) {
-					err := peering.Manager().Remove(uri)
+					err := deps.PeeringManager.Remove(uri)
 					if err != nil {
 						e.Error = fmt.Sprintf("%v: %v", ErrInternalError, err)
 						c.JSON(http.StatusInternalServerError, e)
@@ -193,7 +193,7 @@ func removeNeighbors(i interface{}, c *gin.Context, _ <-chan struct{}) {
 			} else {
 				// Remove unconnected neighbor
 				if strings.EqualFold(p.Address, uri) {
-					err := peering.Manager().Remove(uri)
+					err := deps.PeeringManager.Remove(uri)
 					if err != nil {
 						e.Error = fmt.Sprintf("%v: %v", ErrInternalError, err)
 						c.JSON(http.StatusInternalServerError, e)
@@ -216,5 +216,5 @@ func removeNeighbors(i interface{}, c *gin.Context, _ <-chan struct{}) {
 }
 
 func getNeighbors(i interface{}, c *gin.Context, _ <-chan struct{}) {
-	c.JSON(http.StatusOK, GetNeighborsReturn{Neighbors: peering.Manager().PeerInfos()})
+	c.JSON(http.StatusOK, GetNeighborsReturn{Neighbors: deps.PeeringManager.PeerInfos()})
 }
@@ -0,0 +1,266 @@
+package webapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/timeutil"
+	"github.com/iotaledger/iota.go/transaction"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/compressed"
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	outboxpackage "github.com/gohornet/hornet/pkg/outbox"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/tipselect"
+	"github.com/gohornet/hornet/plugins/gossip"
+	"github.com/gohornet/hornet/plugins/urts"
+)
+
+const (
+	// outboxCheckInterval is the interval in which the bundles of the outbox are checked.
+	outboxCheckInterval = 10 * time.Second
+)
+
+var (
+	// outbox tracks the bundles submitted via the APIs until they are confirmed (nil if disabled).
+	outbox *outboxpackage.Outbox
+	// outboxCtx is canceled at shutdown to abort the PoW of running reattachments.
+	outboxCtx       context.Context
+	outboxCtxCancel context.CancelFunc
+)
+
+// configureOutbox creates the outbox if enabled. Bundles are only reattached if the PoW for submitted transactions is enabled.
+func configureOutbox() {
+	if !config.NodeConfig.GetBool(config.CfgWebAPIOutboxEnabled) {
+		return
+	}
+
+	outboxCtx, outboxCtxCancel = context.WithCancel(context.Background())
+
+	var reattachFunc outboxpackage.ReattachFunc
+	if restPoWWorker != nil {
+		reattachFunc = outboxReattach
+	}
+
+	outbox = outboxpackage.New(outboxpackage.Opts{
+		MaxItems:            config.NodeConfig.GetInt(config.CfgWebAPIOutboxMaxItems),
+		RebroadcastInterval: time.Duration(config.NodeConfig.GetInt(config.CfgWebAPIOutboxRebroadcastIntervalSeconds)) * time.Second,
+		ReattachAfter:       time.Duration(config.NodeConfig.GetInt(config.CfgWebAPIOutboxReattachAfterSeconds)) * time.Second,
+		MaxReattachments:    config.NodeConfig.GetInt(config.CfgWebAPIOutboxMaxReattachments),
+		MaxAge:              time.Duration(config.NodeConfig.GetInt(config.CfgWebAPIOutboxMaxAgeSeconds)) * time.Second,
+	}, outboxTailState, outboxRebroadcast, reattachFunc, func(item *outboxpackage.Item, result outboxpackage.Result) {
+		log.Debugf("Removed bundle %s from the outbox: %s", item.TailHash.Trytes(), result)
+	})
+}
+
+func runOutbox() {
+	if outbox == nil {
+		return
+	}
+
+	daemon.BackgroundWorker("WebAPI[Outbox]", func(shutdownSignal <-chan struct{}) {
+		log.Info("Starting WebAPI[Outbox] ... done")
+		timeutil.Ticker(func() {
+			// the state of the bundles is unknown while the node is not synced
+			if !tangle.IsNodeAlmostSynced() {
+				return
+			}
+			outbox.Check(time.Now())
+		}, outboxCheckInterval, shutdownSignal)
+		log.Info("Stopping WebAPI[Outbox] ...")
+		outboxCtxCancel()
+		log.Info("Stopping WebAPI[Outbox] ... done")
+	}, shutdown.PriorityAPI)
+}
+
+// outboxAddTransactions adds the bundles of the given submitted transactions to the outbox.
+func outboxAddTransactions(txs []*transaction.Transaction) {
+	if outbox == nil {
+		return
+	}
+
+	now := time.Now()
+	for _, tx := range txs {
+		if tx.CurrentIndex != 0 {
+			continue
+		}
+
+		if err := outbox.Add(hornet.HashFromHashTrytes(tx.Hash), now); err != nil {
+			log.Debugf("Can't add bundle %s to the outbox: %s", tx.Hash, err)
+		}
+	}
+}
+
+// outboxAddTrytes adds the bundles of the given submitted transaction trytes to the outbox.
+// hashes may be nil, in which case the hashes of the transactions are calculated.
+func outboxAddTrytes(txsTrytes []trinary.Trytes, hashes []trinary.Hash) {
+	if outbox == nil {
+		return
+	}
+
+	txs, err := transaction.AsTransactionObjects(txsTrytes, hashes)
+	if err != nil {
+		log.Debugf("Can't add the submitted transactions to the outbox: %s", err)
+		return
+	}
+
+	txPointers := make([]*transaction.Transaction, len(txs))
+	for i := range txs {
+		txPointers[i] = &txs[i]
+	}
+	outboxAddTransactions(txPointers)
+}
+
+// outboxAddBytes adds the bundles of the given submitted truncated transaction bytes to the outbox.
+func outboxAddBytes(txsBytesTruncated [][]byte, hashes []trinary.Hash) {
+	if outbox == nil {
+		return
+	}
+
+	txs := make([]*transaction.Transaction, 0, len(txsBytesTruncated))
+	for i, txBytesTruncated := range txsBytesTruncated {
+		tx, err := compressed.TransactionFromCompressedBytes(txBytesTruncated, hashes[i])
+		if err != nil {
+			log.Debugf("Can't add the submitted transactions to the outbox: %s", err)
+			return
+		}
+		txs = append(txs, tx)
+	}
+	outboxAddTransactions(txs)
+}
+
+// outboxTailState maps the metadata and the promotion action of a tail to its state in the outbox.
+func outboxTailState(tailHash hornet.Hash) (outboxpackage.TailState, error) {
+	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(tailHash) // meta +1
+	if cachedTxMeta == nil {
+		return outboxpackage.TailStatePending, errors.Wrapf(tangle.ErrTransactionNotFound, "transaction %s", tailHash.Trytes())
+	}
+	metadata := cachedTxMeta.GetMetadata()
+	referenced, conflicting := metadata.IsReferenced(), metadata.IsConflicting()
+	cachedTxMeta.Release(true) // meta -1
+
+	if referenced {
+		if conflicting {
+			return outboxpackage.TailStateConflicting, nil
+		}
+		return outboxpackage.TailStateConfirmed, nil
+	}
+
+	action, err := urts.TipSelector.GetPromotionAction(tailHash)
+	if err != nil {
+		return outboxpackage.TailStatePending, err
+	}
+
+	if action == tipselect.PromotionActionReattach {
+		return outboxpackage.TailStateLazy, nil
+	}
+	return outboxpackage.TailStatePending, nil
+}
+
+// outboxRebroadcast sends the transactions of the bundle of the given tail to the neighbors again.
+func outboxRebroadcast(tailHash hornet.Hash) error {
+	cachedBndl := tangle.GetCachedBundleOrNil(tailHash) // bundle +1
+	if cachedBndl == nil {
+		return errors.Wrapf(ErrNotFound, "bundle of transaction %s is not complete", tailHash.Trytes())
+	}
+	defer cachedBndl.Release(true) // bundle -1
+
+	cachedTxs := cachedBndl.GetBundle().GetTransactions() // tx +1
+	defer cachedTxs.Release(true)                         // tx -1
+
+	for _, cachedTx := range cachedTxs {
+		gossip.Processor().Broadcast(cachedTx.GetTransaction())
+	}
+	return nil
+}
+
+// outboxReattach attaches the bundle of the given tail to new tips and returns the new tail.
+func outboxReattach(tailHash hornet.Hash) (hornet.Hash, error) {
+	hashes, err := reattachBundle(outboxCtx, tailHash)
+	if err != nil {
+		return nil, err
+	}
+	return hornet.HashFromHashTrytes(hashes[0]), nil
+}
+
+// restOutboxEnabled returns the outbox or an error if it is disabled.
+func restOutboxEnabled() (*outboxpackage.Outbox, error) {
+	if outbox == nil {
+		return nil, errors.Wrap(ErrNotFound, "outbox is disabled")
+	}
+	return outbox, nil
+}
+
+func restOutboxItemResponse(item *outboxpackage.Item) *RESTOutboxItemResponse {
+	result := &RESTOutboxItemResponse{
+		TailHash:       item.TailHash.Trytes(),
+		LatestTailHash: item.LatestTailHash().Trytes(),
+		Reattachments:  item.Reattachments.Trytes(),
+		Rebroadcasts:   item.Rebroadcasts,
+		SubmittedAt:    item.SubmittedAt.Unix(),
+		AttachedAt:     item.AttachedAt.Unix(),
+		BroadcastAt:    item.BroadcastAt.Unix(),
+	}
+	if item.LastError != nil {
+		result.LastError = item.LastError.Error()
+	}
+	return result
+}
+
+func restGetOutbox(_ *gin.Context) (interface{}, error) {
+	o, err := restOutboxEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	items := o.Items()
+	result := &RESTOutboxResponse{Items: make([]*RESTOutboxItemResponse, len(items))}
+	for i, item := range items {
+		result.Items[i] = restOutboxItemResponse(item)
+	}
+	return result, nil
+}
+
+func restGetOutboxItem(c *gin.Context) (interface{}, error) {
+	o, err := restOutboxEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, err := restParseMessageID(c)
+	if err != nil {
+		return nil, err
+	}
+	tailHash := messageID.Hash()
+
+	item, err := o.Item(tailHash)
+	if err != nil {
+		return nil, errors.Wrapf(ErrNotFound, "outbox item %s", tailHash.Trytes())
+	}
+	return restOutboxItemResponse(item), nil
+}
+
+func restCancelOutboxItem(c *gin.Context) (interface{}, error) {
+	o, err := restOutboxEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, err := restParseMessageID(c)
+	if err != nil {
+		return nil, err
+	}
+	tailHash := messageID.Hash()
+
+	if err := o.Cancel(tailHash); err != nil {
+		return nil, errors.Wrapf(ErrNotFound, "outbox item %s", tailHash.Trytes())
+	}
+	return nil, nil
+}
@@ -10,7 +10,10 @@ import (
 
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
+	"github.com/gohornet/hornet/pkg/app"
 	"github.com/gohornet/hornet/pkg/basicauth"
+	"github.com/gohornet/hornet/pkg/logging"
+	peeringpackage "github.com/gohornet/hornet/pkg/peering"
 	"github.com/gohornet/hornet/plugins/spammer"
 	cnet "github.com/projectcalico/libcalico-go/lib/net"
 
@@ -43,8 +46,23 @@ var (
 	serverShutdownSignal <-chan struct{}
 )
 
+// dependencies of the plugin which are injected before it is configured.
+type dependencies struct {
+	app.In
+	// the peering manager is not available if the node runs as an autopeering entry node.
+	PeeringManager *peeringpackage.Manager `optional:"true"`
+}
+
+var deps dependencies
+
+func init() {
+	app.Inject(PLUGIN, func(d dependencies) {
+		deps = d
+	})
+}
+
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
 	// Release mode
 	gin.SetMode(gin.ReleaseMode)
@@ -52,6 +70,9 @@ func configure(plugin *node.Plugin) {
 	// Recover from any panics and write a 500 if there was one
 	api.Use(gin.Recovery())
 
+	// request metrics and slow request log
+	configureAPIMetrics()
+
 	// CORS
 	corsMiddleware := func(c *gin.Context) {
 
@@ -69,8 +90,8 @@ func configure(plugin *node.Plugin) {
 	}
 	api.Use(corsMiddleware)
 
-	// GZIP
-	api.Use(gzip.Gzip(gzip.DefaultCompression))
+	// GZIP (the websocket connections are not compressed by the middleware)
+	api.Use(gzip.Gzip(gzip.DefaultCompression, gzip.WithExcludedPaths([]string{wsRoute})))
 
 	// Load allowed remote access to specific HTTP API commands
 	permittedAPIendpoints := config.NodeConfig.GetStringSlice(config.CfgWebAPIPermitRemoteAccess)
@@ -99,6 +120,8 @@ func configure(plugin *node.Plugin) {
 		whitelistedNetworks = append(whitelistedNetworks, ipnet.IPNet)
 	}
 
+	configureJWTAuth()
+
 	exclHealthCheckFromAuth := config.NodeConfig.GetBool(config.CfgWebAPIExcludeHealthCheckFromAuth)
 	if exclHealthCheckFromAuth {
 		// Handle route without auth
@@ -127,6 +150,11 @@ func configure(plugin *node.Plugin) {
 		}
 
 		api.Use(func(c *gin.Context) {
+			if jwtAuthorized(c) {
+				// a valid JWT replaces the basic auth credentials
+				return
+			}
+
 			authVal := c.Request.Header.Get("Authorization")
 			if len(authVal) <= len(basicAuthPrefix) {
 				unauthorizedReq(c)
@@ -164,6 +192,8 @@ func configure(plugin *node.Plugin) {
 
 	if !config.NodeConfig.GetBool(config.CfgNetAutopeeringRunAsEntryNode) {
 		webAPIRoute()
+		restRoute()
+		loggerRoute()
 
 		// only handle spammer api calls if the spammer plugin is enabled
 		if !node.IsSkipped(spammer.PLUGIN) {
@@ -189,6 +219,9 @@ func run(_ *node.Plugin) {
 		if tangle.GetSnapshotInfo().IsSpentAddressesEnabled() {
 			features = append(features, "WereAddressesSpentFrom")
 		}
+
+		runWebsocket()
+		runOutbox()
 	}
 
 	daemon.BackgroundWorker("WebAPI server", func(shutdownSignal <-chan struct{}) {
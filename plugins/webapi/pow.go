@@ -8,14 +8,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
 
 	"github.com/iotaledger/iota.go/consts"
 	"github.com/iotaledger/iota.go/transaction"
 	"github.com/iotaledger/iota.go/trinary"
 
-	"github.com/iotaledger/hive.go/batchhasher"
-
+	"github.com/gohornet/hornet/pkg/compressed"
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/plugins/pow"
 )
 
@@ -35,6 +36,13 @@ func attachToTangle(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		return
 	}
 
+	// transactions of an unsync node would reference old tips and might not get confirmed
+	if !tangle.IsNodeAlmostSynced() {
+		e.Error = ErrNodeNotSync.Error()
+		c.JSON(http.StatusBadRequest, e)
+		return
+	}
+
 	// mwm is an optional parameter
 	if query.MinWeightMagnitude == 0 {
 		query.MinWeightMagnitude = mwm
@@ -61,11 +69,38 @@ func attachToTangle(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		return
 	}
 
+	powedTxTrytes, err := attachTransactions(txs, query.TrunkTransaction, query.BranchTransaction, query.MinWeightMagnitude, func(trytes trinary.Trytes, mwm int) (trinary.Trytes, error) {
+		ts := time.Now()
+		nonce, err := pow.Handler().DoPoW(trytes, mwm)
+		if err != nil {
+			return "", err
+		}
+		log.Debugf("PoW method: \"%s\", MWM: %d, took %v", pow.Handler().GetPoWType(), mwm, time.Since(ts).Truncate(time.Millisecond))
+		return nonce, nil
+	})
+	if err != nil {
+		e.Error = err.Error()
+		if errors.Is(err, ErrInvalidParameter) {
+			c.JSON(http.StatusBadRequest, e)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, e)
+		return
+	}
+
+	c.JSON(http.StatusOK, AttachToTangleReturn{Trytes: powedTxTrytes})
+}
+
+// nonceFunc calculates the nonce of the given transaction trytes for the given minimum weight magnitude.
+type nonceFunc func(trytes trinary.Trytes, mwm int) (trinary.Trytes, error)
+
+// attachTransactions chains the transactions of a bundle, attaches the bundle to the given trunk and branch
+// and calculates the nonces of the transactions. The returned transaction trytes are ordered like IRI does it.
+func attachTransactions(txs []transaction.Transaction, trunk trinary.Hash, branch trinary.Hash, mwm int, doPoW nonceFunc) ([]trinary.Trytes, error) {
+
 	// Reject bundles with invalid tx amount
 	if uint64(len(txs)) != txs[0].LastIndex+1 {
-		e.Error = fmt.Sprintf("Invalid bundle length. Received txs: %v, Bundle requires: %v", len(txs), txs[0].LastIndex+1)
-		c.JSON(http.StatusBadRequest, e)
-		return
+		return nil, errors.Wrapf(ErrInvalidParameter, "Invalid bundle length. Received txs: %v, Bundle requires: %v", len(txs), txs[0].LastIndex+1)
 	}
 
 	// Sort transactions (highest to lowest index)
@@ -76,9 +111,7 @@ func attachToTangle(i interface{}, c *gin.Context, _ <-chan struct{}) {
 	// Check transaction indexes
 	for i, j := uint64(0), uint64(len(txs)-1); j > 0; i, j = i+1, j-1 {
 		if txs[i].CurrentIndex != j {
-			e.Error = fmt.Sprintf("Invalid transaction index. Got: %d, expected: %d", txs[i].CurrentIndex, j)
-			c.JSON(http.StatusBadRequest, e)
-			return
+			return nil, errors.Wrapf(ErrInvalidParameter, "Invalid transaction index. Got: %d, expected: %d", txs[i].CurrentIndex, j)
 		}
 	}
 
@@ -87,11 +120,11 @@ func attachToTangle(i interface{}, c *gin.Context, _ <-chan struct{}) {
 
 		switch {
 		case i == 0:
-			txs[i].TrunkTransaction = query.TrunkTransaction
-			txs[i].BranchTransaction = query.BranchTransaction
+			txs[i].TrunkTransaction = trunk
+			txs[i].BranchTransaction = branch
 		default:
 			txs[i].TrunkTransaction = prev
-			txs[i].BranchTransaction = query.TrunkTransaction
+			txs[i].BranchTransaction = trunk
 		}
 
 		txs[i].AttachmentTimestamp = time.Now().UnixNano() / int64(time.Millisecond)
@@ -101,40 +134,29 @@ func attachToTangle(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		// Convert tx to trytes
 		trytes, err := transaction.TransactionToTrytes(&txs[i])
 		if err != nil {
-			e.Error = err.Error()
-			c.JSON(http.StatusInternalServerError, e)
-			return
+			return nil, err
 		}
 
 		// Do the PoW
-		ts := time.Now()
-		txs[i].Nonce, err = pow.Handler().DoPoW(trytes, query.MinWeightMagnitude)
+		txs[i].Nonce, err = doPoW(trytes, mwm)
 		if err != nil {
-			e.Error = err.Error()
-			c.JSON(http.StatusInternalServerError, e)
-			return
+			return nil, err
 		}
-		log.Debugf("PoW method: \"%s\", MWM: %d, took %v", pow.Handler().GetPoWType(), mwm, time.Since(ts).Truncate(time.Millisecond))
 
 		// Convert tx to trits
 		txTrits, err := transaction.TransactionToTrits(&txs[i])
 		if err != nil {
-			e.Error = err.Error()
-			c.JSON(http.StatusInternalServerError, e)
-			return
+			return nil, err
 		}
 
-		// Calculate the transaction hash with the batched hasher
-		hashTrits := batchhasher.CURLP81.Hash(txTrits)
-		txs[i].Hash = trinary.MustTritsToTrytes(hashTrits)
+		// Calculate the transaction hash with the batched hasher (cached for the following broadcast)
+		txs[i].Hash = compressed.TransactionHash(txTrits)
 
 		prev = txs[i].Hash
 
 		// Check tx
-		if !transaction.HasValidNonce(&txs[i], uint64(query.MinWeightMagnitude)) {
-			e.Error = err.Error()
-			c.JSON(http.StatusInternalServerError, e)
-			return
+		if !transaction.HasValidNonce(&txs[i], uint64(mwm)) {
+			return nil, fmt.Errorf("invalid nonce of transaction %s", txs[i].Hash)
 		}
 	}
 
@@ -143,7 +165,5 @@ func attachToTangle(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		txs[i], txs[j] = txs[j], txs[i]
 	}
 
-	powedTxTrytes := transaction.MustTransactionsToTrytes(txs)
-
-	c.JSON(http.StatusOK, AttachToTangleReturn{Trytes: powedTxTrytes})
+	return transaction.MustTransactionsToTrytes(txs), nil
 }
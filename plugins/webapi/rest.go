@@ -0,0 +1,1423 @@
+package webapi
+
+import (
+	"context"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/iotaledger/hive.go/iputils"
+	"github.com/iotaledger/hive.go/node"
+
+	"github.com/iotaledger/iota.go/bundle"
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/guards"
+	"github.com/iotaledger/iota.go/transaction"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/compressed"
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	peeringpackage "github.com/gohornet/hornet/pkg/peering"
+	powpackage "github.com/gohornet/hornet/pkg/pow"
+	"github.com/gohornet/hornet/pkg/profile"
+	"github.com/gohornet/hornet/pkg/protocol/processor"
+	"github.com/gohornet/hornet/pkg/tipselect"
+	"github.com/gohornet/hornet/pkg/transfer"
+	"github.com/gohornet/hornet/pkg/utils"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+	"github.com/gohornet/hornet/plugins/gossip"
+	"github.com/gohornet/hornet/plugins/peering"
+	"github.com/gohornet/hornet/plugins/spammer"
+	"github.com/gohornet/hornet/plugins/urts"
+)
+
+const (
+	restAPIBase = "/api/v1"
+
+	// restPoWRoute is the route permission of the requests which let the node do the PoW.
+	// it is not permitted by default, since the PoW uses the resources of the node.
+	restPoWRoute = "api/v1/pow"
+
+	// MIMEApplicationOctetStream is the content type of binary serialized REST API requests and responses.
+	MIMEApplicationOctetStream = "application/octet-stream"
+
+	// restMaxApproversResults is the maximum amount of approvers returned by the approvers route.
+	restMaxApproversResults = 1000
+	// restMaxTransactionTypeResults is the maximum amount of transactions returned by the milestone transactions route.
+	restMaxTransactionTypeResults = 1000
+	// restMaxTagResults is the maximum amount of transactions returned per page by the tags route.
+	restMaxTagResults = 1000
+	// restMaxAddressHistoryResults is the maximum amount of entries returned by the address history route.
+	restMaxAddressHistoryResults = 1000
+
+	// restMaxRichestAddressesResults is the maximum amount of addresses returned by the richest addresses route.
+	restMaxRichestAddressesResults = 1000
+
+	// restPromotionTag is the tag of the transactions created to promote semi-lazy tails.
+	restPromotionTag = "HORNET99PROMOTION9999999999"
+
+	// restRateLimiterCleanupInterval is the interval in which idle rate limiters of remote addresses are removed.
+	restRateLimiterCleanupInterval = time.Minute
+)
+
+var (
+	// ErrInvalidParameter is returned when a parameter of a REST API request is invalid.
+	ErrInvalidParameter = errors.New("invalid parameter")
+	// ErrNotFound is returned when the requested object was not found.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimitExceeded is returned when a remote address sent too many requests.
+	ErrRateLimitExceeded = errors.New("rate limit exceeded")
+	// ErrProtectedRoute is returned when a non whitelisted address calls a route which is not permitted.
+	ErrProtectedRoute = errors.New("route is protected")
+	// ErrPoWBusy is returned when the node already does the maximum number of PoW jobs at once.
+	ErrPoWBusy = errors.New("too many PoW jobs")
+)
+
+var (
+	// restPoWWorker does the PoW for submitted transactions (nil if disabled).
+	restPoWWorker *powpackage.Worker
+	// restPoWMWM is the minimum weight magnitude used for the PoW of submitted transactions.
+	restPoWMWM int
+	// restPoWJobs holds a slot for every running PoW job, its capacity is the maximum number of PoW jobs at once.
+	restPoWJobs chan struct{}
+)
+
+// restHandlerFunc handles a REST API request and returns the object which is sent as JSON to the client.
+type restHandlerFunc func(c *gin.Context) (interface{}, error)
+
+// restErrorStatusCode maps the errors of the REST API handlers to HTTP status codes.
+func restErrorStatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidParameter):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrProtectedRoute):
+		return http.StatusForbidden
+	case errors.Is(err, ErrNotFound), errors.Is(err, peering.ErrPeerNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrRateLimitExceeded):
+		return http.StatusTooManyRequests
+	case errors.Is(err, spammer.ErrSpammerNotRunning):
+		return http.StatusConflict
+	case errors.Is(err, ErrNodeNotSync), errors.Is(err, tangle.ErrNodeNotSynced), errors.Is(err, tipselect.ErrNoTipsAvailable),
+		errors.Is(err, processor.ErrSubmissionQueueFull), errors.Is(err, ErrPoWBusy):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// restAbortWithError aborts the request and sends the error to the client.
+func restAbortWithError(c *gin.Context, err error) {
+	if errors.Is(err, processor.ErrSubmissionQueueFull) || errors.Is(err, ErrPoWBusy) {
+		c.Header("Retry-After", submissionRetryAfterSeconds)
+	}
+	c.AbortWithStatusJSON(restErrorStatusCode(err), ErrorReturn{Error: err.Error()})
+}
+
+// restHandler sends the result of the handler with the given status code or the error of the handler to the client.
+// Results which implement encoding.BinaryMarshaler are sent in their binary form if the client accepts it.
+func restHandler(statusCode int, handler restHandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := handler(c)
+		if err != nil {
+			restAbortWithError(c, err)
+			return
+		}
+
+		if marshaler, ok := result.(encoding.BinaryMarshaler); ok && c.NegotiateFormat(gin.MIMEJSON, MIMEApplicationOctetStream) == MIMEApplicationOctetStream {
+			data, err := marshaler.MarshalBinary()
+			if err != nil {
+				restAbortWithError(c, errors.Wrapf(ErrInternalError, "%v", err))
+				return
+			}
+			c.Data(statusCode, MIMEApplicationOctetStream, data)
+			return
+		}
+
+		c.JSON(statusCode, result)
+	}
+}
+
+// restRoutePermitted denies requests of non whitelisted addresses without a valid JWT if the route is not permitted.
+func restRoutePermitted(route string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := restCheckRoutePermitted(c, route); err != nil {
+			restAbortWithError(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+// restCheckRoutePermitted returns an error if the request is not allowed to use the given route.
+func restCheckRoutePermitted(c *gin.Context, route string) error {
+	if privilegedAccess(c) {
+		return nil
+	}
+
+	// network is not whitelisted and no valid JWT given, check if the route is permitted, otherwise deny it.
+	if _, permitted := permittedRESTroutes[route]; !permitted {
+		return errors.Wrapf(ErrProtectedRoute, "route [%s]", route)
+	}
+	return nil
+}
+
+// restRateLimit limits the amount of requests of non whitelisted addresses.
+func restRateLimit() gin.HandlerFunc {
+	requestsPerSecond := config.NodeConfig.GetInt(config.CfgWebAPILimitsRESTRequestsPerSecond)
+	if requestsPerSecond == 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	rateLimiter := utils.NewKeyedRateLimiter(float64(requestsPerSecond), config.NodeConfig.GetInt(config.CfgWebAPILimitsRESTBurst), restRateLimiterCleanupInterval)
+
+	return func(c *gin.Context) {
+		if !networkWhitelisted(c) {
+			remoteHost, _, _ := net.SplitHostPort(c.Request.RemoteAddr)
+			if !rateLimiter.Allow(remoteHost) {
+				restAbortWithError(c, ErrRateLimitExceeded)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// restBodyLimit limits the size of the request bodies.
+func restBodyLimit() gin.HandlerFunc {
+	maxBodyLength := int64(config.NodeConfig.GetInt(config.CfgWebAPILimitsMaxBodyLengthBytes))
+
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyLength)
+		c.Next()
+	}
+}
+
+// configureRESTPoW creates the PoW worker for submitted transactions if enabled.
+func configureRESTPoW() {
+	if !config.NodeConfig.GetBool(config.CfgWebAPIPoWEnabled) {
+		return
+	}
+
+	mwm := config.NodeConfig.GetInt(config.CfgCoordinatorMWM)
+	restPoWMWM = config.NodeConfig.GetInt(config.CfgWebAPIPoWTargetMWM)
+	if restPoWMWM == 0 {
+		restPoWMWM = mwm
+	}
+
+	if restPoWMWM < mwm {
+		log.Fatalf("'%s' must not be smaller than '%s' (%d)", config.CfgWebAPIPoWTargetMWM, config.CfgCoordinatorMWM, mwm)
+	}
+
+	maxConcurrentJobs := config.NodeConfig.GetInt(config.CfgWebAPIPoWMaxConcurrentJobs)
+	if maxConcurrentJobs < 1 {
+		log.Fatalf("'%s' must be at least 1", config.CfgWebAPIPoWMaxConcurrentJobs)
+	}
+	restPoWJobs = make(chan struct{}, maxConcurrentJobs)
+
+	restPoWWorker = powpackage.NewWorker(config.NodeConfig.GetInt(config.CfgWebAPIPoWWorkerCount))
+	log.Infof("PoW for submitted transactions enabled, MWM: %d, workers: %d, max. jobs: %d", restPoWMWM, restPoWWorker.NumWorkers(), maxConcurrentJobs)
+}
+
+// restAcquirePoWJob reserves a slot for a PoW job or returns ErrPoWBusy if all slots are taken.
+// The returned function releases the slot again.
+func restAcquirePoWJob() (func(), error) {
+	select {
+	case restPoWJobs <- struct{}{}:
+		return func() { <-restPoWJobs }, nil
+	default:
+		return nil, errors.Wrapf(ErrPoWBusy, "max. %d PoW jobs at once", cap(restPoWJobs))
+	}
+}
+
+func restRoute() {
+	configureRESTPoW()
+	configureOutbox()
+	configureWebsocket()
+
+	rest := api.Group(restAPIBase, restRateLimit(), restBodyLimit())
+
+	rest.GET("/info", restRoutePermitted("api/v1/info"), restHandler(http.StatusOK, restGetNodeInfo))
+
+	rest.POST("/transactions", restRoutePermitted("api/v1/transactions"), restHandler(http.StatusAccepted, restSubmitTransactions))
+	rest.GET("/transactions/:hash", restRoutePermitted("api/v1/transactions"), restHandler(http.StatusOK, restGetTransaction))
+	rest.GET("/transactions/:hash/metadata", restRoutePermitted("api/v1/transactions"), restHandler(http.StatusOK, restGetTransactionMetadata))
+	rest.GET("/transactions/:hash/approvers", restRoutePermitted("api/v1/transactions"), restHandler(http.StatusOK, restGetTransactionApprovers))
+	rest.GET("/transactions/:hash/inclusion", restRoutePermitted("api/v1/transactions"), restHandler(http.StatusOK, restGetTransactionInclusion))
+	rest.GET("/transactions/:hash/inclusionProof", restRoutePermitted("api/v1/transactions"), restHandler(http.StatusOK, restGetTransactionInclusionProof))
+	rest.GET("/transactions/:hash/promotion", restRoutePermitted("api/v1/transactions"), restHandler(http.StatusOK, restGetTransactionPromotion))
+	rest.POST("/transactions/:hash/promote", restRoutePermitted(restPoWRoute), restHandler(http.StatusAccepted, restPromoteTransaction))
+	rest.POST("/transactions/:hash/reattach", restRoutePermitted(restPoWRoute), restHandler(http.StatusAccepted, restReattachTransaction))
+
+	rest.GET("/milestones/:index", restRoutePermitted("api/v1/milestones"), restHandler(http.StatusOK, restGetMilestone))
+	rest.GET("/milestones/:index/transactions", restRoutePermitted("api/v1/milestones"), restHandler(http.StatusOK, restGetMilestoneTransactions))
+
+	rest.GET("/addresses/:address", restRoutePermitted("api/v1/addresses"), restHandler(http.StatusOK, restGetAddress))
+	rest.GET("/addresses/:address/history", restRoutePermitted("api/v1/addresses"), restHandler(http.StatusOK, restGetAddressHistory))
+
+	rest.GET("/ledger/richest", restRoutePermitted("api/v1/ledger"), restHandler(http.StatusOK, restGetRichestAddresses))
+	rest.GET("/ledger/distribution", restRoutePermitted("api/v1/ledger"), restHandler(http.StatusOK, restGetSupplyDistribution))
+
+	rest.GET("/outbox", restRoutePermitted("api/v1/outbox"), restHandler(http.StatusOK, restGetOutbox))
+	rest.GET("/outbox/:hash", restRoutePermitted("api/v1/outbox"), restHandler(http.StatusOK, restGetOutboxItem))
+	rest.DELETE("/outbox/:hash", restRoutePermitted("api/v1/outbox"), restHandler(http.StatusNoContent, restCancelOutboxItem))
+
+	rest.GET("/tags/:tag", restRoutePermitted("api/v1/tags"), restHandler(http.StatusOK, restGetTag))
+
+	rest.GET("/peers", restRoutePermitted("api/v1/peers"), restHandler(http.StatusOK, restGetPeers))
+	rest.POST("/peers", restRoutePermitted("api/v1/peers"), restHandler(http.StatusCreated, restAddPeer))
+	rest.GET("/peers/:peerID", restRoutePermitted("api/v1/peers"), restHandler(http.StatusOK, restGetPeer))
+	rest.DELETE("/peers/:peerID", restRoutePermitted("api/v1/peers"), restHandler(http.StatusNoContent, restRemovePeer))
+
+	// only handle spammer api calls if the spammer plugin is enabled
+	if !node.IsSkipped(spammer.PLUGIN) {
+		rest.GET("/spammer", restRoutePermitted("api/v1/spammer"), restHandler(http.StatusOK, restGetSpammerStatus))
+		rest.POST("/spammer/start", restRoutePermitted("api/v1/spammer"), restHandler(http.StatusOK, restStartSpammer))
+		rest.POST("/spammer/stop", restRoutePermitted("api/v1/spammer"), restHandler(http.StatusOK, restStopSpammer))
+		rest.PUT("/spammer/rate", restRoutePermitted("api/v1/spammer"), restHandler(http.StatusOK, restSetSpammerRate))
+	}
+
+	rest.GET("/caches", restRoutePermitted("api/v1/caches"), restHandler(http.StatusOK, restGetCaches))
+
+	rest.GET("/events", restRoutePermitted("api/v1/events"), restHandler(http.StatusOK, restGetEvents))
+
+	rest.POST("/transfers/prepare", restRoutePermitted("api/v1/transfers"), restHandler(http.StatusOK, restPrepareTransfer))
+	rest.POST("/transfers/finalize", restRoutePermitted("api/v1/transfers"), restHandler(http.StatusOK, restFinalizeTransfer))
+	rest.POST("/transfers/dryrun", restRoutePermitted("api/v1/transfers"), restHandler(http.StatusOK, restDryRunTransfer))
+
+	rest.GET("/ws", restRoutePermitted("api/v1/ws"), restWebsocket)
+}
+
+// restParseMessageID parses the transaction hash parameter of the request.
+func restParseMessageID(c *gin.Context) (hornet.MessageID, error) {
+	messageID, err := hornet.MessageIDFromTrytes(c.Param("hash"))
+	if err != nil {
+		return hornet.MessageID{}, errors.Wrapf(ErrInvalidParameter, "invalid transaction hash: %v", err)
+	}
+	return messageID, nil
+}
+
+func restGetNodeInfo(_ *gin.Context) (interface{}, error) {
+	return nodeInfo(), nil
+}
+
+func restGetCaches(_ *gin.Context) (interface{}, error) {
+	return &RESTCachesResponse{
+		Profile: profile.LoadProfile().Name,
+		Caches:  tangle.GetCacheStatistics(),
+	}, nil
+}
+
+func restGetEvents(c *gin.Context) (interface{}, error) {
+	var since time.Time
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		timestamp, err := strconv.ParseInt(sinceStr, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid since: %s", sinceStr)
+		}
+		since = time.Unix(timestamp, 0)
+	}
+
+	entries, err := tangle.GetEventLogEntries(since, c.QueryArray("type")...)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*RESTEventLogEntry, len(entries))
+	for i, entry := range entries {
+		events[i] = &RESTEventLogEntry{
+			Index:     entry.Index,
+			Timestamp: entry.Timestamp.Unix(),
+			Type:      entry.Type,
+			Message:   entry.Message,
+		}
+	}
+
+	return &RESTEventLogResponse{Events: events}, nil
+}
+
+// restTransferLedger validates transfers against the confirmed ledger of the node.
+// The read lock of the ledger must be held while it is used.
+type restTransferLedger struct {
+	// the milestone index of the ledger the balances were read from.
+	ledgerIndex milestone.Index
+}
+
+func (l *restTransferLedger) Balance(address hornet.Hash) (uint64, error) {
+	balance, ledgerIndex, err := tangle.Ledger().GetBalanceForAddressWithoutLocking(address)
+	if err != nil {
+		return 0, errors.Wrapf(ErrInternalError, "ledger state invalid: %v", err)
+	}
+	l.ledgerIndex = ledgerIndex
+	return balance, nil
+}
+
+func (l *restTransferLedger) WasSpent(address hornet.Hash) bool {
+	return tangle.WasAddressSpentFrom(address)
+}
+
+func (l *restTransferLedger) PendingSpends(address hornet.Hash) ([]trinary.Hash, error) {
+	bundleHashes := make(map[string]struct{})
+
+	maxFindTransactions := config.NodeConfig.GetInt(config.CfgWebAPILimitsMaxFindTransactions)
+	for _, txHash := range tangle.GetTransactionHashesForAddress(address, true, true, maxFindTransactions) {
+		cachedTx := tangle.GetCachedTransactionOrNil(txHash) // tx +1
+		if cachedTx == nil {
+			continue
+		}
+
+		if cachedTx.GetTransaction().Tx.Value < 0 && !cachedTx.GetMetadata().IsConfirmed() {
+			bundleHashes[cachedTx.GetTransaction().Tx.Bundle] = struct{}{}
+		}
+		cachedTx.Release(true) // tx -1
+	}
+
+	result := make([]trinary.Hash, 0, len(bundleHashes))
+	for bundleHash := range bundleHashes {
+		result = append(result, bundleHash)
+	}
+	return result, nil
+}
+
+func restPrepareTransfer(c *gin.Context) (interface{}, error) {
+	request := &RESTPrepareTransferRequest{}
+	if err := c.ShouldBindJSON(request); err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid request: %v", err)
+	}
+
+	if len(request.Inputs) > 0 && len(request.Addresses) > 0 {
+		return nil, errors.Wrap(ErrInvalidParameter, "either inputs or addresses can be given")
+	}
+
+	maxRequestsList := config.NodeConfig.GetInt(config.CfgWebAPILimitsMaxRequestsList)
+	if len(request.Inputs)+len(request.Addresses)+len(request.Outputs) > maxRequestsList {
+		return nil, errors.Wrapf(ErrInvalidParameter, "too many inputs and outputs, max. %d allowed", maxRequestsList)
+	}
+
+	securityLevel := request.SecurityLevel
+	if securityLevel == 0 {
+		securityLevel = consts.SecurityLevelMedium
+	}
+
+	t := &transfer.Transfer{RemainderAddress: request.RemainderAddress}
+	var outputsTotal uint64
+	for _, output := range request.Outputs {
+		t.Outputs = append(t.Outputs, &transfer.Output{
+			Address: output.Address,
+			Value:   output.Value,
+			Tag:     output.Tag,
+			Message: output.Message,
+		})
+		outputsTotal += output.Value
+	}
+	for _, input := range request.Inputs {
+		t.Inputs = append(t.Inputs, &transfer.Input{Address: input.Address, SecurityLevel: input.SecurityLevel})
+	}
+
+	if !tangle.WaitForNodeSynced(waitForNodeSyncedTimeout) {
+		return nil, ErrNodeNotSync
+	}
+
+	tangle.Ledger().RLock()
+	defer tangle.Ledger().RUnlock()
+
+	if len(request.Addresses) > 0 {
+		inputs, err := transfer.SelectInputs(&restTransferLedger{}, request.Addresses, securityLevel, outputsTotal)
+		if err != nil {
+			return nil, restTransferError(err)
+		}
+		t.Inputs = inputs
+	}
+
+	prepared, err := transfer.Prepare(&restTransferLedger{}, t, time.Now())
+	if err != nil {
+		return nil, restTransferError(err)
+	}
+
+	inputs := make([]*RESTTransferSignatureRequest, len(prepared.Inputs))
+	for i, input := range prepared.Inputs {
+		inputs[i] = &RESTTransferSignatureRequest{
+			Address:       input.Address,
+			SecurityLevel: input.SecurityLevel,
+			Index:         input.Index,
+			Balance:       input.Balance,
+		}
+	}
+
+	return &RESTPrepareTransferResponse{
+		BundleHash: prepared.BundleHash,
+		Trytes:     prepared.Trytes,
+		Inputs:     inputs,
+	}, nil
+}
+
+func restFinalizeTransfer(c *gin.Context) (interface{}, error) {
+	request := &RESTFinalizeTransferRequest{}
+	if err := c.ShouldBindJSON(request); err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid request: %v", err)
+	}
+
+	maxRequestsList := config.NodeConfig.GetInt(config.CfgWebAPILimitsMaxRequestsList)
+	if len(request.Trytes) > maxRequestsList {
+		return nil, errors.Wrapf(ErrInvalidParameter, "too many transactions, max. %d allowed", maxRequestsList)
+	}
+
+	signatures := make([]*transfer.Signature, len(request.Signatures))
+	for i, signature := range request.Signatures {
+		signatures[i] = &transfer.Signature{Address: signature.Address, Fragments: signature.Fragments}
+	}
+
+	if !tangle.WaitForNodeSynced(waitForNodeSyncedTimeout) {
+		return nil, ErrNodeNotSync
+	}
+
+	tangle.Ledger().RLock()
+	defer tangle.Ledger().RUnlock()
+
+	trytes, err := transfer.Finalize(&restTransferLedger{}, request.Trytes, signatures)
+	if err != nil {
+		return nil, restTransferError(err)
+	}
+
+	return &RESTFinalizeTransferResponse{Trytes: trytes}, nil
+}
+
+func restDryRunTransfer(c *gin.Context) (interface{}, error) {
+	request := &RESTDryRunTransferRequest{}
+	if err := c.ShouldBindJSON(request); err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid request: %v", err)
+	}
+
+	maxRequestsList := config.NodeConfig.GetInt(config.CfgWebAPILimitsMaxRequestsList)
+	if len(request.Trytes) > maxRequestsList {
+		return nil, errors.Wrapf(ErrInvalidParameter, "too many transactions, max. %d allowed", maxRequestsList)
+	}
+
+	if !tangle.WaitForNodeSynced(waitForNodeSyncedTimeout) {
+		return nil, ErrNodeNotSync
+	}
+
+	tangle.Ledger().RLock()
+	defer tangle.Ledger().RUnlock()
+
+	ledger := &restTransferLedger{}
+	result, err := transfer.DryRun(ledger, request.Trytes)
+	if err != nil {
+		return nil, restTransferError(err)
+	}
+
+	response := &RESTDryRunTransferResponse{
+		BundleHash:  result.BundleHash,
+		Valid:       result.Err == nil,
+		LedgerIndex: ledger.ledgerIndex,
+		Changes:     make([]*RESTDryRunAddressChange, len(result.Changes)),
+	}
+	if result.Err != nil {
+		response.Error = result.Err.Error()
+	}
+
+	for i, change := range result.Changes {
+		response.Changes[i] = &RESTDryRunAddressChange{
+			Address:            change.Address,
+			Balance:            change.Balance,
+			Change:             change.Change,
+			Spent:              change.Spent,
+			ConflictingBundles: change.ConflictingBundles,
+		}
+	}
+
+	return response, nil
+}
+
+// restTransferError maps the validation errors of a transfer to invalid parameters, other errors are passed through.
+func restTransferError(err error) error {
+	if errors.Is(err, ErrInternalError) {
+		return err
+	}
+	return errors.Wrapf(ErrInvalidParameter, "%v", err)
+}
+
+func restSubmitTransactions(c *gin.Context) (interface{}, error) {
+	if !tangle.IsNodeAlmostSynced() {
+		return nil, ErrNodeNotSync
+	}
+
+	if c.ContentType() == MIMEApplicationOctetStream {
+		return restSubmitTransactionsBinary(c)
+	}
+
+	request := &RESTSubmitTransactions{}
+	if err := c.ShouldBindJSON(request); err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid request: %v", err)
+	}
+
+	if len(request.Trytes) == 0 {
+		return nil, errors.Wrap(ErrInvalidParameter, "no trytes provided")
+	}
+
+	maxRequestsList := config.NodeConfig.GetInt(config.CfgWebAPILimitsMaxRequestsList)
+	if len(request.Trytes) > maxRequestsList {
+		return nil, errors.Wrapf(ErrInvalidParameter, "too many transactions, max. %d allowed", maxRequestsList)
+	}
+
+	if request.DoPoW {
+		trytes, err := restAttachTransactions(c, request)
+		if err != nil {
+			return nil, err
+		}
+		request.Trytes = trytes
+	}
+
+	hashes := make([]trinary.Hash, len(request.Trytes))
+	for i, trytes := range request.Trytes {
+		if !guards.IsTransactionTrytes(trytes) {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid transaction trytes at index %d", i)
+		}
+
+		txTrits, err := trinary.TrytesToTrits(trytes)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid trytes at index %d: %v", i, err)
+		}
+		hashes[i] = compressed.TransactionHash(txTrits)
+	}
+
+	if err := gossip.Processor().SubmitTransactionTrytes(request.Trytes); err != nil {
+		return nil, restSubmissionError(err)
+	}
+	outboxAddTrytes(request.Trytes, hashes)
+
+	return &RESTSubmitTransactionsResponse{Hashes: hashes}, nil
+}
+
+// restSubmissionError wraps the error of a submission, so that it is mapped to the right status code.
+func restSubmissionError(err error) error {
+	if errors.Is(err, processor.ErrSubmissionQueueFull) {
+		return err
+	}
+	return errors.Wrapf(ErrInvalidParameter, "%v", err)
+}
+
+// restAttachTransactions attaches the bundle of the request to the tangle and does the PoW for it.
+// The PoW is aborted if the client closes the connection.
+func restAttachTransactions(c *gin.Context, request *RESTSubmitTransactions) ([]trinary.Trytes, error) {
+	// the route of the submission is permitted by default, the PoW needs its own permission
+	if err := restCheckRoutePermitted(c, restPoWRoute); err != nil {
+		return nil, err
+	}
+
+	if restPoWWorker == nil {
+		return nil, errors.Wrap(ErrInvalidParameter, "PoW is disabled on this node")
+	}
+
+	for i, trytes := range request.Trytes {
+		if !guards.IsTransactionTrytes(trytes) {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid transaction trytes at index %d", i)
+		}
+	}
+
+	txs, err := transaction.AsTransactionObjects(request.Trytes, nil)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid transactions: %v", err)
+	}
+
+	trunk, branch := request.TrunkTransaction, request.BranchTransaction
+	if trunk == "" || branch == "" {
+		tips, err := urts.TipSelector.SelectTips(2)
+		if err != nil {
+			return nil, err
+		}
+		trunk, branch = tips[0].Trytes(), tips[1].Trytes()
+	} else if !guards.IsTrytesOfExactLength(trunk, consts.HashTrytesSize) || !guards.IsTrytesOfExactLength(branch, consts.HashTrytesSize) {
+		return nil, errors.Wrap(ErrInvalidParameter, "invalid trunk or branch transaction")
+	}
+
+	release, err := restAcquirePoWJob()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	ctx := c.Request.Context()
+	return attachTransactions(txs, trunk, branch, restPoWMWM, func(trytes trinary.Trytes, mwm int) (trinary.Trytes, error) {
+		return restPoWWorker.Mine(ctx, trytes, mwm)
+	})
+}
+
+// restSubmitTransactionsBinary submits the transactions of a binary request body.
+//
+// Binary request body format:
+//
+//	transactions:
+//		tx bytes length	2 bytes
+//		tx bytes		truncated transaction bytes
+func restSubmitTransactionsBinary(c *gin.Context) (interface{}, error) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid request: %v", err)
+	}
+
+	var txsBytes [][]byte
+	for offset := 0; offset < len(body); {
+		if offset+2 > len(body) {
+			return nil, errors.Wrapf(ErrInvalidParameter, "missing length of transaction at index %d", len(txsBytes))
+		}
+		txBytesLength := int(binary.LittleEndian.Uint16(body[offset : offset+2]))
+		offset += 2
+
+		if offset+txBytesLength > len(body) {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid length of transaction at index %d", len(txsBytes))
+		}
+		txsBytes = append(txsBytes, body[offset:offset+txBytesLength])
+		offset += txBytesLength
+	}
+
+	if len(txsBytes) == 0 {
+		return nil, errors.Wrap(ErrInvalidParameter, "no transactions provided")
+	}
+
+	maxRequestsList := config.NodeConfig.GetInt(config.CfgWebAPILimitsMaxRequestsList)
+	if len(txsBytes) > maxRequestsList {
+		return nil, errors.Wrapf(ErrInvalidParameter, "too many transactions, max. %d allowed", maxRequestsList)
+	}
+
+	hashes, err := gossip.Processor().SubmitTransactionBytes(txsBytes)
+	if err != nil {
+		return nil, restSubmissionError(err)
+	}
+	outboxAddBytes(txsBytes, hashes)
+
+	return &RESTSubmitTransactionsResponse{Hashes: hashes}, nil
+}
+
+func restGetTransaction(c *gin.Context) (interface{}, error) {
+	messageID, err := restParseMessageID(c)
+	if err != nil {
+		return nil, err
+	}
+	txHash := messageID.Hash()
+
+	cachedTx := tangle.GetCachedTransactionOrNil(txHash) // tx +1
+	if cachedTx == nil {
+		return nil, errors.Wrapf(ErrNotFound, "transaction %s", txHash.Trytes())
+	}
+	defer cachedTx.Release(true) // tx -1
+
+	return &RESTTransactionResponse{tx: cachedTx.GetTransaction()}, nil
+}
+
+func restGetTransactionMetadata(c *gin.Context) (interface{}, error) {
+	messageID, err := restParseMessageID(c)
+	if err != nil {
+		return nil, err
+	}
+	txHash := messageID.Hash()
+
+	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(txHash) // meta +1
+	if cachedTxMeta == nil {
+		return nil, errors.Wrapf(ErrNotFound, "transaction %s", txHash.Trytes())
+	}
+	defer cachedTxMeta.Release(true) // meta -1
+
+	metadata := cachedTxMeta.GetMetadata()
+	referenced, referencedByIndex := metadata.GetReferenced()
+	isMilestone, milestoneIndex := metadata.GetMilestone()
+
+	result := &RESTTransactionMetadataResponse{
+		Hash:                       txHash.Trytes(),
+		Solid:                      metadata.IsSolid(),
+		Referenced:                 referenced,
+		ReferencedByMilestoneIndex: referencedByIndex,
+		LedgerInclusionState:       metadata.GetLedgerInclusionState(),
+		ConflictReason:             metadata.GetConflictReason(),
+		IsMilestone:                isMilestone,
+		MilestoneIndex:             milestoneIndex,
+	}
+
+	if referenced {
+		result.ReferencedByMilestoneTimestamp = restUnixTimestamp(tangle.GetMilestoneTimestamp(referencedByIndex))
+	}
+
+	if metadata.IsSolid() && !referenced {
+		// the root snapshot indexes are only of interest for transactions which are not referenced yet
+		result.YoungestRootSnapshotIndex, result.OldestRootSnapshotIndex, _ = metadata.GetRootSnapshotIndexes()
+	}
+
+	return result, nil
+}
+
+func restGetTransactionApprovers(c *gin.Context) (interface{}, error) {
+	messageID, err := restParseMessageID(c)
+	if err != nil {
+		return nil, err
+	}
+	txHash := messageID.Hash()
+
+	approvers, err := tangle.GetApproverHashes(txHash, restMaxApproversResults).ToTrytes()
+	if err != nil {
+		return nil, errors.Wrapf(ErrInternalError, "%v", err)
+	}
+
+	return &RESTTransactionApproversResponse{
+		Hash:       txHash.Trytes(),
+		MaxResults: restMaxApproversResults,
+		Count:      len(approvers),
+		Approvers:  approvers,
+	}, nil
+}
+
+func restGetTransactionInclusion(c *gin.Context) (interface{}, error) {
+	messageID, err := restParseMessageID(c)
+	if err != nil {
+		return nil, err
+	}
+	txHash := messageID.Hash()
+
+	if !tangle.WaitForNodeSynced(waitForNodeSyncedTimeout) {
+		return nil, ErrNodeNotSync
+	}
+
+	state, err := tangle.GetTransferInclusionState(txHash)
+	if err != nil {
+		if errors.Is(err, tangle.ErrTransactionNotFound) {
+			return nil, errors.Wrapf(ErrNotFound, "transaction %s", txHash.Trytes())
+		}
+		return nil, errors.Wrapf(ErrInternalError, "%v", err)
+	}
+
+	result := &RESTTransactionInclusionResponse{
+		Hash:                       txHash.Trytes(),
+		Bundle:                     state.BundleHash.Trytes(),
+		LedgerInclusionState:       state.LedgerInclusionState,
+		ConflictReason:             state.ConflictReason,
+		ReferencedByMilestoneIndex: state.ReferencedByMilestoneIndex,
+		InputsSpent:                state.InputsSpent,
+		Bundles:                    make([]*RESTBundleInclusion, len(state.Bundles)),
+	}
+
+	for i, bundleState := range state.Bundles {
+		result.Bundles[i] = &RESTBundleInclusion{
+			TailHash:                   bundleState.TailHash.Trytes(),
+			LedgerInclusionState:       bundleState.LedgerInclusionState,
+			ConflictReason:             bundleState.ConflictReason,
+			ReferencedByMilestoneIndex: bundleState.ReferencedByMilestoneIndex,
+		}
+	}
+
+	return result, nil
+}
+
+func restGetTransactionInclusionProof(c *gin.Context) (interface{}, error) {
+	messageID, err := restParseMessageID(c)
+	if err != nil {
+		return nil, err
+	}
+	txHash := messageID.Hash()
+
+	proof, err := whiteflag.ComputeInclusionProof(txHash)
+	if err != nil {
+		switch {
+		case errors.Is(err, tangle.ErrTransactionNotFound), errors.Is(err, tangle.ErrMilestoneNotFound), errors.Is(err, whiteflag.ErrTransactionNotIncluded):
+			return nil, errors.Wrapf(ErrNotFound, "%v", err)
+		case errors.Is(err, whiteflag.ErrTransactionNotTail):
+			return nil, errors.Wrapf(ErrInvalidParameter, "%v", err)
+		default:
+			return nil, errors.Wrapf(ErrInternalError, "%v", err)
+		}
+	}
+
+	result := &RESTTransactionInclusionProofResponse{
+		Hash:           proof.TailHash.Trytes(),
+		MilestoneIndex: proof.MilestoneIndex,
+		MilestoneHash:  proof.MilestoneHash.Trytes(),
+		HashFunction:   tangle.GetMilestoneMerkleHashFunc().String(),
+		MerkleTreeHash: hex.EncodeToString(proof.MerkleTreeHash),
+		LeafIndex:      proof.LeafIndex,
+		TreeSize:       proof.TreeSize,
+		AuditPath:      make([]string, len(proof.AuditPath)),
+	}
+
+	for i, sibling := range proof.AuditPath {
+		result.AuditPath[i] = hex.EncodeToString(sibling)
+	}
+
+	return result, nil
+}
+
+// restGetPromotionAction returns the promotion action of the tail transaction of the request.
+func restGetPromotionAction(c *gin.Context) (hornet.Hash, tipselect.PromotionAction, error) {
+	messageID, err := restParseMessageID(c)
+	if err != nil {
+		return nil, "", err
+	}
+	txHash := messageID.Hash()
+
+	if !tangle.WaitForNodeSynced(waitForNodeSyncedTimeout) {
+		return nil, "", ErrNodeNotSync
+	}
+
+	action, err := urts.TipSelector.GetPromotionAction(txHash)
+	if err != nil {
+		switch {
+		case errors.Is(err, tangle.ErrTransactionNotFound):
+			return nil, "", errors.Wrapf(ErrNotFound, "transaction %s", txHash.Trytes())
+		case errors.Is(err, tipselect.ErrNotATailTransaction):
+			return nil, "", errors.Wrapf(ErrInvalidParameter, "%v", err)
+		default:
+			return nil, "", errors.Wrapf(ErrInternalError, "%v", err)
+		}
+	}
+
+	return txHash, action, nil
+}
+
+func restGetTransactionPromotion(c *gin.Context) (interface{}, error) {
+	txHash, action, err := restGetPromotionAction(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RESTTransactionPromotionResponse{
+		Hash:   txHash.Trytes(),
+		Action: action,
+	}, nil
+}
+
+// restPromoteTransaction attaches a zero value transaction which approves the semi-lazy tail of the request
+// and a non-lazy tip, so that the tail gets selectable by the tip selection again.
+func restPromoteTransaction(c *gin.Context) (interface{}, error) {
+	if restPoWWorker == nil {
+		return nil, errors.Wrap(ErrInvalidParameter, "PoW is disabled on this node")
+	}
+
+	txHash, action, err := restGetPromotionAction(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if action != tipselect.PromotionActionPromote {
+		return nil, errors.Wrapf(ErrInvalidParameter, "transaction %s can't be promoted, action: %s", txHash.Trytes(), action)
+	}
+
+	tips, err := urts.TipSelector.SelectNonLazyTips()
+	if err != nil {
+		return nil, err
+	}
+
+	promotionBundle, err := bundle.Finalize(bundle.AddEntry(nil, bundle.BundleEntry{
+		Address:   consts.NullHashTrytes,
+		Tag:       restPromotionTag,
+		Timestamp: uint64(time.Now().Unix()),
+	}))
+	if err != nil {
+		return nil, errors.Wrapf(ErrInternalError, "%v", err)
+	}
+
+	return restAttachAndSubmitTransactions(c, promotionBundle, txHash.Trytes(), tips[0].Trytes())
+}
+
+// restReattachTransaction attaches the bundle of the lazy tail of the request to new tips.
+func restReattachTransaction(c *gin.Context) (interface{}, error) {
+	if restPoWWorker == nil {
+		return nil, errors.Wrap(ErrInvalidParameter, "PoW is disabled on this node")
+	}
+
+	txHash, action, err := restGetPromotionAction(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if action != tipselect.PromotionActionReattach {
+		return nil, errors.Wrapf(ErrInvalidParameter, "transaction %s can't be reattached, action: %s", txHash.Trytes(), action)
+	}
+
+	hashes, err := reattachBundle(c.Request.Context(), txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RESTSubmitTransactionsResponse{Hashes: hashes}, nil
+}
+
+// reattachBundle attaches the bundle of the given tail to new tips, does the PoW for it
+// and submits the resulting transactions to the node. The hash of the new tail is the first of the returned hashes.
+func reattachBundle(ctx context.Context, tailHash hornet.Hash) ([]trinary.Hash, error) {
+	cachedBndl := tangle.GetCachedBundleOrNil(tailHash) // bundle +1
+	if cachedBndl == nil {
+		return nil, errors.Wrapf(ErrNotFound, "bundle of transaction %s is not complete", tailHash.Trytes())
+	}
+	defer cachedBndl.Release(true) // bundle -1
+
+	cachedTxs := cachedBndl.GetBundle().GetTransactions() // tx +1
+	defer cachedTxs.Release(true)                         // tx -1
+
+	txs := make([]transaction.Transaction, len(cachedTxs))
+	for i, cachedTx := range cachedTxs {
+		txs[i] = *cachedTx.GetTransaction().Tx
+	}
+
+	tips, err := urts.TipSelector.SelectTips(2)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachAndSubmitTransactions(ctx, txs, tips[0].Trytes(), tips[1].Trytes())
+}
+
+// restAttachAndSubmitTransactions attaches the given bundle to trunk and branch, does the PoW for it
+// and submits the resulting transactions to the node.
+func restAttachAndSubmitTransactions(c *gin.Context, txs []transaction.Transaction, trunk trinary.Hash, branch trinary.Hash) (interface{}, error) {
+	hashes, err := attachAndSubmitTransactions(c.Request.Context(), txs, trunk, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RESTSubmitTransactionsResponse{Hashes: hashes}, nil
+}
+
+// attachAndSubmitTransactions attaches the given bundle to trunk and branch, does the PoW for it
+// and submits the resulting transactions to the node. The PoW is aborted if the context is canceled.
+// Returns the hashes of the submitted transactions, starting with the tail.
+func attachAndSubmitTransactions(ctx context.Context, txs []transaction.Transaction, trunk trinary.Hash, branch trinary.Hash) ([]trinary.Hash, error) {
+	release, err := restAcquirePoWJob()
+	if err != nil {
+		return nil, err
+	}
+
+	powedTxTrytes, err := attachTransactions(txs, trunk, branch, restPoWMWM, func(trytes trinary.Trytes, mwm int) (trinary.Trytes, error) {
+		return restPoWWorker.Mine(ctx, trytes, mwm)
+	})
+	release()
+	if err != nil {
+		if errors.Is(err, ErrInvalidParameter) {
+			return nil, err
+		}
+		return nil, errors.Wrapf(ErrInternalError, "%v", err)
+	}
+
+	hashes := make([]trinary.Hash, len(powedTxTrytes))
+	for i, trytes := range powedTxTrytes {
+		hashes[i] = compressed.TransactionHash(trinary.MustTrytesToTrits(trytes))
+	}
+
+	if err := gossip.Processor().SubmitTransactionTrytes(powedTxTrytes); err != nil {
+		if errors.Is(err, processor.ErrSubmissionQueueFull) {
+			return nil, err
+		}
+		return nil, errors.Wrapf(ErrInternalError, "%v", err)
+	}
+
+	return hashes, nil
+}
+
+func restGetMilestone(c *gin.Context) (interface{}, error) {
+	msIndex, err := strconv.ParseUint(c.Param("index"), 10, 32)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid milestone index: %s", c.Param("index"))
+	}
+
+	cachedMs := tangle.GetCachedMilestoneOrNil(milestone.Index(msIndex)) // milestone +1
+	if cachedMs == nil {
+		return nil, errors.Wrapf(ErrNotFound, "milestone %d", msIndex)
+	}
+	defer cachedMs.Release(true) // milestone -1
+
+	ms := cachedMs.GetMilestone()
+
+	result := &RESTMilestoneResponse{
+		Index: ms.Index,
+		Hash:  ms.ID.Trytes(),
+	}
+
+	if !ms.Timestamp.IsZero() {
+		result.Timestamp = ms.Timestamp.Unix()
+	}
+
+	return result, nil
+}
+
+func restGetMilestoneTransactions(c *gin.Context) (interface{}, error) {
+	msIndex, err := strconv.ParseUint(c.Param("index"), 10, 32)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid milestone index: %s", c.Param("index"))
+	}
+
+	txType, err := hornet.TransactionTypeFromString(c.Query("type"))
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "%v", err)
+	}
+
+	txHashes, err := tangle.GetTransactionTypeHashes(txType, milestone.Index(msIndex), restMaxTransactionTypeResults).ToTrytes()
+	if err != nil {
+		return nil, errors.Wrapf(ErrInternalError, "%v", err)
+	}
+
+	return &RESTMilestoneTransactionsResponse{
+		Index:        milestone.Index(msIndex),
+		Type:         txType.String(),
+		MaxResults:   restMaxTransactionTypeResults,
+		Count:        len(txHashes),
+		Transactions: txHashes,
+	}, nil
+}
+
+func restGetAddress(c *gin.Context) (interface{}, error) {
+	addr, err := hornet.AddressFromTrytes(c.Param("address"))
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid address: %v", err)
+	}
+
+	if !tangle.WaitForNodeSynced(waitForNodeSyncedTimeout) {
+		return nil, ErrNodeNotSync
+	}
+
+	balance, ledgerIndex, err := tangle.Ledger().GetBalanceForAddress(addr)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInternalError, "ledger state invalid: %v", err)
+	}
+
+	result := &RESTAddressResponse{
+		Address:     addr.Trytes(),
+		Balance:     balance,
+		LedgerIndex: ledgerIndex,
+	}
+
+	if tangle.GetSnapshotInfo().IsSpentAddressesEnabled() {
+		spent := tangle.WasAddressSpentFrom(addr)
+		result.Spent = &spent
+	}
+
+	return result, nil
+}
+
+func restGetAddressHistory(c *gin.Context) (interface{}, error) {
+	if !tangle.IsAddressHistoryEnabled() {
+		return nil, errors.Wrapf(ErrNotFound, "address history is disabled (%s)", config.CfgDatabaseAddressHistoryEnabled)
+	}
+
+	addr, err := hornet.AddressFromTrytes(c.Param("address"))
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid address: %v", err)
+	}
+
+	fromIndex, toIndex := milestone.Index(0), tangle.GetSolidMilestoneIndex()
+	if fromIndexStr := c.Query("fromIndex"); fromIndexStr != "" {
+		index, err := strconv.ParseUint(fromIndexStr, 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid fromIndex: %s", fromIndexStr)
+		}
+		fromIndex = milestone.Index(index)
+	}
+	if toIndexStr := c.Query("toIndex"); toIndexStr != "" {
+		index, err := strconv.ParseUint(toIndexStr, 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid toIndex: %s", toIndexStr)
+		}
+		toIndex = milestone.Index(index)
+	}
+
+	if fromIndex > toIndex {
+		return nil, errors.Wrapf(ErrInvalidParameter, "fromIndex %d is bigger than toIndex %d", fromIndex, toIndex)
+	}
+
+	var cursor *hornet.AddressHistoryEntry
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		if cursor, err = restParseAddressHistoryCursor(addr, cursorStr); err != nil {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid cursor: %s", cursorStr)
+		}
+	}
+
+	limit := restMaxAddressHistoryResults
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit < 1 || limit > restMaxAddressHistoryResults {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid limit: %s, must be between 1 and %d", limitStr, restMaxAddressHistoryResults)
+		}
+	}
+
+	entries, nextCursor := tangle.GetAddressHistory(addr, fromIndex, toIndex, cursor, limit)
+
+	result := &RESTAddressHistoryResponse{
+		Address:    addr.Trytes(),
+		FromIndex:  fromIndex,
+		ToIndex:    toIndex,
+		MaxResults: limit,
+		Count:      len(entries),
+		History:    make([]*RESTAddressHistoryEntry, len(entries)),
+	}
+
+	if nextCursor != nil {
+		result.Cursor = strconv.FormatUint(uint64(nextCursor.GetMilestoneIndex()), 10) + ":" + nextCursor.GetTxHash().Trytes()
+	}
+
+	// the entries are sorted by milestone index, so the timestamp only has to be loaded once per milestone
+	var msTimestamp int64
+	for i, entry := range entries {
+		if i == 0 || entry.GetMilestoneIndex() != entries[i-1].GetMilestoneIndex() {
+			msTimestamp = restUnixTimestamp(tangle.GetMilestoneTimestamp(entry.GetMilestoneIndex()))
+		}
+
+		result.History[i] = &RESTAddressHistoryEntry{
+			MilestoneIndex:     entry.GetMilestoneIndex(),
+			MilestoneTimestamp: msTimestamp,
+			Hash:               entry.GetTxHash().Trytes(),
+		}
+	}
+
+	return result, nil
+}
+
+// restParseAddressHistoryCursor parses a cursor of the address history in the form "milestoneIndex:txHash".
+func restParseAddressHistoryCursor(addr hornet.Hash, cursor string) (*hornet.AddressHistoryEntry, error) {
+	parts := strings.Split(cursor, ":")
+	if len(parts) != 2 {
+		return nil, errors.New("cursor must be in the form milestoneIndex:txHash")
+	}
+
+	msIndex, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	txHash, err := hornet.HashFromTrytes(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return hornet.NewAddressHistoryEntry(addr, milestone.Index(msIndex), txHash), nil
+}
+
+func restGetRichestAddresses(c *gin.Context) (interface{}, error) {
+	if !tangle.IsLedgerAnalyticsEnabled() {
+		return nil, errors.Wrapf(ErrNotFound, "ledger analytics are disabled (%s)", config.CfgDatabaseLedgerAnalyticsEnabled)
+	}
+
+	count := restMaxRichestAddressesResults
+	if countStr := c.Query("count"); countStr != "" {
+		value, err := strconv.ParseUint(countStr, 10, 32)
+		if err != nil || value == 0 || value > restMaxRichestAddressesResults {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid count: %s, must be between 1 and %d", countStr, restMaxRichestAddressesResults)
+		}
+		count = int(value)
+	}
+
+	richest, ledgerIndex, err := tangle.Ledger().GetRichestAddresses(count)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInternalError, "ledger analytics failed: %v", err)
+	}
+
+	result := &RESTRichestAddressesResponse{
+		LedgerIndex: ledgerIndex,
+		MaxResults:  count,
+		Count:       len(richest),
+		Addresses:   make([]*RESTAddressResponse, len(richest)),
+	}
+
+	for i, addressBalance := range richest {
+		result.Addresses[i] = &RESTAddressResponse{
+			Address:     addressBalance.Address.Trytes(),
+			Balance:     addressBalance.Balance,
+			LedgerIndex: ledgerIndex,
+		}
+	}
+
+	return result, nil
+}
+
+func restGetSupplyDistribution(_ *gin.Context) (interface{}, error) {
+	if !tangle.IsLedgerAnalyticsEnabled() {
+		return nil, errors.Wrapf(ErrNotFound, "ledger analytics are disabled (%s)", config.CfgDatabaseLedgerAnalyticsEnabled)
+	}
+
+	buckets, ledgerIndex, err := tangle.Ledger().GetSupplyDistribution()
+	if err != nil {
+		return nil, errors.Wrapf(ErrInternalError, "ledger analytics failed: %v", err)
+	}
+
+	result := &RESTSupplyDistributionResponse{
+		LedgerIndex: ledgerIndex,
+		Buckets:     make([]*RESTSupplyDistributionBucket, len(buckets)),
+	}
+
+	for i, bucket := range buckets {
+		result.Buckets[i] = &RESTSupplyDistributionBucket{
+			MinBalance:   bucket.MinBalance,
+			MaxBalance:   bucket.MaxBalance,
+			AddressCount: bucket.AddressCount,
+			TotalBalance: bucket.TotalBalance,
+		}
+	}
+
+	return result, nil
+}
+
+// restUnixTimestamp returns the unix timestamp in seconds of the given time, 0 if the time is unknown.
+func restUnixTimestamp(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func restGetTag(c *gin.Context) (interface{}, error) {
+	tagTrytes := c.Param("tag")
+	if err := trinary.ValidTrytes(tagTrytes); err != nil || len(tagTrytes) > consts.TagTrinarySize/consts.TritsPerTryte {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid tag: %s", tagTrytes)
+	}
+	tagTrytes = trinary.MustPad(tagTrytes, consts.TagTrinarySize/consts.TritsPerTryte)
+
+	var cursor hornet.Hash
+	if cursorTrytes := c.Query("cursor"); cursorTrytes != "" {
+		var err error
+		if cursor, err = hornet.HashFromTrytes(cursorTrytes); err != nil {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid cursor: %v", err)
+		}
+	}
+
+	limit := restMaxTagResults
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit < 1 || limit > restMaxTagResults {
+			return nil, errors.Wrapf(ErrInvalidParameter, "invalid limit: %s, must be between 1 and %d", limitStr, restMaxTagResults)
+		}
+	}
+
+	txHashes, nextCursor := tangle.GetTagHashesPage(hornet.HashFromTagTrytes(tagTrytes), cursor, limit)
+
+	transactions, err := txHashes.ToTrytes()
+	if err != nil {
+		return nil, errors.Wrapf(ErrInternalError, "%v", err)
+	}
+
+	result := &RESTTagResponse{
+		Tag:          tagTrytes,
+		MaxResults:   limit,
+		Count:        len(transactions),
+		Transactions: transactions,
+	}
+
+	if nextCursor != nil {
+		result.Cursor = nextCursor.Trytes()
+	}
+
+	return result, nil
+}
+
+func restGetPeers(_ *gin.Context) (interface{}, error) {
+	return &RESTPeersResponse{Peers: deps.PeeringManager.PeerInfos()}, nil
+}
+
+func restGetPeer(c *gin.Context) (interface{}, error) {
+	return peering.PeerInfo(c.Param("peerID"))
+}
+
+func restAddPeer(c *gin.Context) (interface{}, error) {
+	request := &RESTAddPeerRequest{}
+	if err := c.ShouldBindJSON(request); err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid request: %v", err)
+	}
+
+	addr := strings.TrimPrefix(request.Address, "tcp://")
+	if _, err := iputils.ParseOriginAddress(addr); err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid peer address: %v", err)
+	}
+
+	preferIPv6 := config.NodeConfig.GetBool(config.CfgNetPreferIPv6)
+	if request.PreferIPv6 != nil {
+		preferIPv6 = *request.PreferIPv6
+	}
+
+	if _, err := peeringpackage.ParsePublicKey(request.PublicKey); err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "%v", err)
+	}
+
+	alias := request.Alias
+	if alias == "" {
+		alias = addr
+	}
+
+	if err := peering.AddPeer(addr, alias, preferIPv6, request.PublicKey); err != nil {
+		return nil, errors.Wrapf(ErrInternalError, "adding peer failed: %v", err)
+	}
+
+	return peering.PeerInfo(addr)
+}
+
+func restRemovePeer(c *gin.Context) (interface{}, error) {
+	info, err := peering.PeerInfo(c.Param("peerID"))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := peering.RemovePeer(info.DomainWithPort); err != nil {
+		return nil, errors.Wrapf(ErrInternalError, "removing peer failed: %v", err)
+	}
+
+	return nil, nil
+}
+
+func restGetSpammerStatus(_ *gin.Context) (interface{}, error) {
+	return spammer.GetStatus()
+}
+
+func restStartSpammer(c *gin.Context) (interface{}, error) {
+	// all settings are optional, so an empty body starts the spammer with the settings of the config
+	request := &RESTStartSpammerRequest{}
+	if err := c.ShouldBindJSON(request); err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid request: %v", err)
+	}
+
+	if request.TPSRateLimit != nil && *request.TPSRateLimit < 0.0 {
+		return nil, errors.Wrap(ErrInvalidParameter, "tpsRateLimit must not be negative")
+	}
+	if request.CPUMaxUsage != nil && *request.CPUMaxUsage < 0.0 {
+		return nil, errors.Wrap(ErrInvalidParameter, "cpuMaxUsage must not be negative")
+	}
+	if request.BundleSize != nil && *request.BundleSize < 1 {
+		return nil, errors.Wrap(ErrInvalidParameter, "bundleSize must be at least 1")
+	}
+
+	if _, _, _, _, err := spammer.Start(request.TPSRateLimit, request.CPUMaxUsage, request.BundleSize, request.ValueSpam); err != nil {
+		return nil, errors.Wrapf(ErrInternalError, "starting spammer failed: %v", err)
+	}
+
+	return spammer.GetStatus()
+}
+
+func restStopSpammer(_ *gin.Context) (interface{}, error) {
+	if err := spammer.Stop(); err != nil {
+		return nil, errors.Wrapf(ErrInternalError, "stopping spammer failed: %v", err)
+	}
+
+	return spammer.GetStatus()
+}
+
+func restSetSpammerRate(c *gin.Context) (interface{}, error) {
+	request := &RESTSetSpammerRateRequest{}
+	if err := c.ShouldBindJSON(request); err != nil {
+		return nil, errors.Wrapf(ErrInvalidParameter, "invalid request: %v", err)
+	}
+
+	if *request.TPSRateLimit < 0.0 {
+		return nil, errors.Wrap(ErrInvalidParameter, "tpsRateLimit must not be negative")
+	}
+
+	if err := spammer.SetTPSRateLimit(*request.TPSRateLimit); err != nil {
+		return nil, err
+	}
+
+	return spammer.GetStatus()
+}
@@ -0,0 +1,67 @@
+package webapi
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestAcquirePoWJob(t *testing.T) {
+	restPoWJobs = make(chan struct{}, 2)
+	defer func() { restPoWJobs = nil }()
+
+	release1, err := restAcquirePoWJob()
+	require.NoError(t, err)
+	release2, err := restAcquirePoWJob()
+	require.NoError(t, err)
+
+	// all slots are taken, so the job is rejected instead of waiting
+	_, err = restAcquirePoWJob()
+	require.True(t, errors.Is(err, ErrPoWBusy))
+	require.Equal(t, http.StatusServiceUnavailable, restErrorStatusCode(err))
+
+	release1()
+	release3, err := restAcquirePoWJob()
+	require.NoError(t, err)
+
+	release2()
+	release3()
+	require.Len(t, restPoWJobs, 0)
+}
+
+func TestRestPoWRoutePermitted(t *testing.T) {
+	permittedRESTroutes = map[string]struct{}{"api/v1/transactions": {}}
+	_, localhost, _ := net.ParseCIDR("127.0.0.1/32")
+	whitelistedNetworks = []net.IPNet{*localhost}
+	defer func() {
+		permittedRESTroutes = make(map[string]struct{})
+		whitelistedNetworks = nil
+	}()
+
+	newContext := func(remoteAddr string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/transactions", nil)
+		c.Request.RemoteAddr = remoteAddr
+		return c
+	}
+
+	// the submission is permitted, but the PoW is not
+	remote := newContext("10.0.0.1:1234")
+	require.NoError(t, restCheckRoutePermitted(remote, "api/v1/transactions"))
+	require.True(t, errors.Is(restCheckRoutePermitted(remote, restPoWRoute), ErrProtectedRoute))
+
+	_, err := restAttachTransactions(remote, &RESTSubmitTransactions{DoPoW: true})
+	require.True(t, errors.Is(err, ErrProtectedRoute))
+
+	// whitelisted addresses may use the PoW
+	local := newContext("127.0.0.1:1234")
+	require.NoError(t, restCheckRoutePermitted(local, restPoWRoute))
+
+	_, err = restAttachTransactions(local, &RESTSubmitTransactions{DoPoW: true})
+	require.False(t, errors.Is(err, ErrProtectedRoute))
+}
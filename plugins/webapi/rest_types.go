@@ -0,0 +1,414 @@
+package webapi
+
+import (
+	"encoding/json"
+
+	"github.com/iotaledger/iota.go/consts"
+	"github.com/iotaledger/iota.go/trinary"
+
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/peering/peer"
+	"github.com/gohornet/hornet/pkg/tipselect"
+)
+
+////////////////// POST /api/v1/transactions //////////////////////
+
+// RESTSubmitTransactions is the body of a transaction submission.
+type RESTSubmitTransactions struct {
+	Trytes []trinary.Trytes `json:"trytes"`
+	// DoPoW lets the node attach the bundle and calculate the nonces of the transactions.
+	DoPoW bool `json:"doPoW,omitempty"`
+	// TrunkTransaction and BranchTransaction are used to attach the bundle if DoPoW is set.
+	// If they are not given, the node selects tips.
+	TrunkTransaction  trinary.Hash `json:"trunkTransaction,omitempty"`
+	BranchTransaction trinary.Hash `json:"branchTransaction,omitempty"`
+}
+
+// RESTSubmitTransactionsResponse contains the hashes of the submitted transactions.
+type RESTSubmitTransactionsResponse struct {
+	Hashes []trinary.Hash `json:"hashes"`
+}
+
+// MarshalBinary returns the concatenated binary hashes of the submitted transactions.
+func (r *RESTSubmitTransactionsResponse) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, len(r.Hashes)*hornet.HashBinarySize)
+	for _, hash := range r.Hashes {
+		data = append(data, hornet.HashFromHashTrytes(hash)...)
+	}
+	return data, nil
+}
+
+////////////////// GET /api/v1/transactions/:hash //////////////////
+
+// RESTTransactionResponse contains a transaction.
+// It is sent as JSON or as truncated transaction bytes.
+type RESTTransactionResponse struct {
+	tx *hornet.Transaction
+}
+
+// MarshalJSON returns the JSON representation of the transaction.
+func (r *RESTTransactionResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.tx.Tx)
+}
+
+// MarshalBinary returns the truncated transaction bytes.
+func (r *RESTTransactionResponse) MarshalBinary() ([]byte, error) {
+	return r.tx.RawBytes, nil
+}
+
+////////////////// GET /api/v1/transactions/:hash/metadata ///////////
+
+// RESTTransactionMetadataResponse contains the metadata of a transaction.
+type RESTTransactionMetadataResponse struct {
+	Hash                       trinary.Hash    `json:"hash"`
+	Solid                      bool            `json:"solid"`
+	Referenced                 bool            `json:"referenced"`
+	ReferencedByMilestoneIndex milestone.Index `json:"referencedByMilestoneIndex,omitempty"`
+	// the unix timestamp in seconds of the milestone which referenced the transaction
+	ReferencedByMilestoneTimestamp int64                       `json:"referencedByMilestoneTimestamp,omitempty"`
+	LedgerInclusionState           hornet.LedgerInclusionState `json:"ledgerInclusionState"`
+	ConflictReason                 hornet.ConflictReason       `json:"conflictReason,omitempty"`
+	IsMilestone                    bool                        `json:"isMilestone"`
+	MilestoneIndex                 milestone.Index             `json:"milestoneIndex,omitempty"`
+	YoungestRootSnapshotIndex      milestone.Index             `json:"ytrsi,omitempty"`
+	OldestRootSnapshotIndex        milestone.Index             `json:"otrsi,omitempty"`
+}
+
+////////////////// GET /api/v1/transactions/:hash/approvers //////////
+
+// RESTTransactionApproversResponse contains the approvers of a transaction.
+type RESTTransactionApproversResponse struct {
+	Hash       trinary.Hash   `json:"hash"`
+	MaxResults int            `json:"maxResults"`
+	Count      int            `json:"count"`
+	Approvers  []trinary.Hash `json:"approvers"`
+}
+
+////////////////// GET /api/v1/transactions/:hash/inclusion //////////
+
+// RESTTransactionInclusionResponse contains the inclusion state of the transfer a transaction belongs to.
+type RESTTransactionInclusionResponse struct {
+	Hash                       trinary.Hash                `json:"hash"`
+	Bundle                     trinary.Hash                `json:"bundle"`
+	LedgerInclusionState       hornet.LedgerInclusionState `json:"ledgerInclusionState"`
+	ConflictReason             hornet.ConflictReason       `json:"conflictReason,omitempty"`
+	ReferencedByMilestoneIndex milestone.Index             `json:"referencedByMilestoneIndex,omitempty"`
+	// InputsSpent is true if the transfer was not referenced yet, but its inputs were already spent by other transfers.
+	InputsSpent bool                   `json:"inputsSpent,omitempty"`
+	Bundles     []*RESTBundleInclusion `json:"bundles"`
+}
+
+// RESTBundleInclusion contains the inclusion state of a bundle instance (reattachment) of a transfer.
+type RESTBundleInclusion struct {
+	TailHash                   trinary.Hash                `json:"tailHash"`
+	LedgerInclusionState       hornet.LedgerInclusionState `json:"ledgerInclusionState"`
+	ConflictReason             hornet.ConflictReason       `json:"conflictReason,omitempty"`
+	ReferencedByMilestoneIndex milestone.Index             `json:"referencedByMilestoneIndex,omitempty"`
+}
+
+////////////////// GET /api/v1/transactions/:hash/inclusionProof /////
+
+// RESTTransactionInclusionProofResponse contains the white-flag Merkle audit path which proves that the bundle
+// of a tail transaction was included in the ledger by a milestone.
+// The leaf is the hash of 0x00 and the tail hash bytes, the inner nodes are the hashes of 0x01 and both children (RFC6962).
+type RESTTransactionInclusionProofResponse struct {
+	Hash           trinary.Hash    `json:"hash"`
+	MilestoneIndex milestone.Index `json:"milestoneIndex"`
+	MilestoneHash  trinary.Hash    `json:"milestoneHash"`
+	HashFunction   string          `json:"hashFunction"`
+	MerkleTreeHash string          `json:"merkleTreeHash"`
+	LeafIndex      int             `json:"leafIndex"`
+	TreeSize       int             `json:"treeSize"`
+	// the sibling hashes from the leaf up to the root
+	AuditPath []string `json:"auditPath"`
+}
+
+////////////////// GET /api/v1/transactions/:hash/promotion //////////
+
+// RESTTransactionPromotionResponse contains the action needed to get the transfer of a tail transaction confirmed.
+type RESTTransactionPromotionResponse struct {
+	Hash   trinary.Hash              `json:"hash"`
+	Action tipselect.PromotionAction `json:"action"`
+}
+
+////////////////// GET /api/v1/milestones/:index ///////////////////
+
+// RESTMilestoneResponse contains the information about a milestone.
+type RESTMilestoneResponse struct {
+	Index     milestone.Index `json:"index"`
+	Hash      trinary.Hash    `json:"hash"`
+	Timestamp int64           `json:"timestamp,omitempty"`
+}
+
+////////////////// GET /api/v1/milestones/:index/transactions //////
+
+// RESTMilestoneTransactionsResponse contains the transactions of a type,
+// which were first seen while the milestone was the latest one.
+type RESTMilestoneTransactionsResponse struct {
+	Index        milestone.Index `json:"index"`
+	Type         string          `json:"type"`
+	MaxResults   int             `json:"maxResults"`
+	Count        int             `json:"count"`
+	Transactions []trinary.Hash  `json:"transactions"`
+}
+
+////////////////// GET /api/v1/addresses/:address ///////////////////
+
+// RESTAddressResponse contains the ledger state of an address.
+type RESTAddressResponse struct {
+	Address     trinary.Hash    `json:"address"`
+	Balance     uint64          `json:"balance"`
+	LedgerIndex milestone.Index `json:"ledgerIndex"`
+	// Spent is only set if the node keeps track of the spent addresses.
+	Spent *bool `json:"spent,omitempty"`
+}
+
+////////////////// GET /api/v1/addresses/:address/history ///////////
+
+// RESTAddressHistoryResponse contains a page of the confirmed transactions touching an address within a milestone range.
+type RESTAddressHistoryResponse struct {
+	Address    trinary.Hash               `json:"address"`
+	FromIndex  milestone.Index            `json:"fromIndex"`
+	ToIndex    milestone.Index            `json:"toIndex"`
+	MaxResults int                        `json:"maxResults"`
+	Count      int                        `json:"count"`
+	History    []*RESTAddressHistoryEntry `json:"history"`
+	// Cursor is the cursor to pass to get the next page (empty if there are no more entries).
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// RESTAddressHistoryEntry is a confirmed transaction touching an address.
+type RESTAddressHistoryEntry struct {
+	MilestoneIndex milestone.Index `json:"milestoneIndex"`
+	// the unix timestamp in seconds of the milestone which confirmed the transaction
+	MilestoneTimestamp int64        `json:"milestoneTimestamp,omitempty"`
+	Hash               trinary.Hash `json:"hash"`
+}
+
+////////////////// GET /api/v1/ledger/richest ///////////////////////
+
+// RESTRichestAddressesResponse contains the addresses with the highest balances, sorted by balance descending.
+type RESTRichestAddressesResponse struct {
+	LedgerIndex milestone.Index        `json:"ledgerIndex"`
+	MaxResults  int                    `json:"maxResults"`
+	Count       int                    `json:"count"`
+	Addresses   []*RESTAddressResponse `json:"addresses"`
+}
+
+////////////////// GET /api/v1/ledger/distribution //////////////////
+
+// RESTSupplyDistributionResponse contains the amount of addresses and their balances per decimal magnitude of the balance.
+type RESTSupplyDistributionResponse struct {
+	LedgerIndex milestone.Index                 `json:"ledgerIndex"`
+	Buckets     []*RESTSupplyDistributionBucket `json:"buckets"`
+}
+
+// RESTSupplyDistributionBucket holds the addresses whose balance is within MinBalance and MaxBalance (both inclusive).
+type RESTSupplyDistributionBucket struct {
+	MinBalance   uint64 `json:"minBalance"`
+	MaxBalance   uint64 `json:"maxBalance"`
+	AddressCount int    `json:"addressCount"`
+	TotalBalance uint64 `json:"totalBalance"`
+}
+
+////////////////// GET /api/v1/tags/:tag ///////////////////////////
+
+// RESTTagResponse contains a page of the transactions with a tag.
+type RESTTagResponse struct {
+	Tag          trinary.Trytes `json:"tag"`
+	MaxResults   int            `json:"maxResults"`
+	Count        int            `json:"count"`
+	Transactions []trinary.Hash `json:"transactions"`
+	// Cursor is the cursor to pass to get the next page (empty if there are no more transactions).
+	Cursor trinary.Hash `json:"cursor,omitempty"`
+}
+
+////////////////// GET /api/v1/peers ///////////////////////////////
+
+// RESTPeersResponse contains the connected and in the reconnect pool residing peers.
+type RESTPeersResponse struct {
+	Peers []*peer.Info `json:"peers"`
+}
+
+////////////////// POST /api/v1/peers //////////////////////////////
+
+// RESTAddPeerRequest is the body of a request to add a static peer.
+type RESTAddPeerRequest struct {
+	// Address is the address of the peer in the form host:port.
+	Address string `json:"address" binding:"required"`
+	Alias   string `json:"alias,omitempty"`
+	// PreferIPv6 defaults to the "network.preferIPv6" setting of the node.
+	PreferIPv6 *bool `json:"preferIPv6,omitempty"`
+	// PublicKey is the base58 encoded public key of the peer.
+	// If it is given, the connection is encrypted and the peer has to authenticate itself with the key.
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+////////////////// POST /api/v1/spammer/start //////////////////////
+
+// RESTStartSpammerRequest is the body of a request to start the spammer.
+// Settings which are not given are taken from the config.
+type RESTStartSpammerRequest struct {
+	TPSRateLimit *float64 `json:"tpsRateLimit,omitempty"`
+	CPUMaxUsage  *float64 `json:"cpuMaxUsage,omitempty"`
+	BundleSize   *int     `json:"bundleSize,omitempty"`
+	ValueSpam    *bool    `json:"valueSpam,omitempty"`
+}
+
+////////////////// PUT /api/v1/spammer/rate ////////////////////////
+
+// RESTSetSpammerRateRequest is the body of a request to change the rate of the running spammer.
+type RESTSetSpammerRateRequest struct {
+	// TPSRateLimit is the new target rate (0 = no limit).
+	TPSRateLimit *float64 `json:"tpsRateLimit" binding:"required"`
+}
+
+////////////////// GET /api/v1/caches //////////////////////////////
+
+// RESTCachesResponse contains the statistics of the caches of the tangle object storages.
+type RESTCachesResponse struct {
+	// Profile is the name of the profile the caches were configured with.
+	Profile string                    `json:"profile"`
+	Caches  []*tangle.CacheStatistics `json:"caches"`
+}
+
+////////////////// GET /api/v1/events //////////////////////////////
+
+// RESTEventLogResponse contains the entries of the event log of the node, ordered by index.
+type RESTEventLogResponse struct {
+	Events []*RESTEventLogEntry `json:"events"`
+}
+
+// RESTEventLogEntry is an operational incident of the node, e.g. a dropped peer or a pruning run.
+type RESTEventLogEntry struct {
+	Index uint64 `json:"index"`
+	// Timestamp is the unix timestamp in seconds of the incident.
+	Timestamp int64  `json:"timestamp"`
+	Type      string `json:"type"`
+	Message   string `json:"message"`
+}
+
+////////////////// POST /api/v1/transfers/prepare //////////////////
+
+// RESTPrepareTransferRequest is the body of a request to prepare a value bundle which is signed by the client.
+// Either the inputs or the addresses to select the inputs from have to be given.
+type RESTPrepareTransferRequest struct {
+	Inputs []*RESTTransferInput `json:"inputs,omitempty"`
+	// Addresses are used in the given order as inputs until their balances cover the outputs.
+	Addresses []trinary.Hash `json:"addresses,omitempty"`
+	// SecurityLevel is the security level of the addresses, defaults to 2.
+	SecurityLevel    consts.SecurityLevel  `json:"securityLevel,omitempty"`
+	Outputs          []*RESTTransferOutput `json:"outputs" binding:"required"`
+	RemainderAddress trinary.Hash          `json:"remainderAddress,omitempty"`
+}
+
+// RESTTransferInput is an address whose whole balance is moved by a transfer.
+type RESTTransferInput struct {
+	Address       trinary.Hash         `json:"address" binding:"required"`
+	SecurityLevel consts.SecurityLevel `json:"securityLevel" binding:"required"`
+}
+
+// RESTTransferOutput is an address which receives funds or a message by a transfer.
+type RESTTransferOutput struct {
+	Address trinary.Hash   `json:"address" binding:"required"`
+	Value   uint64         `json:"value"`
+	Tag     trinary.Trytes `json:"tag,omitempty"`
+	Message trinary.Trytes `json:"message,omitempty"`
+}
+
+// RESTPrepareTransferResponse contains the bundle without the signatures of its inputs.
+type RESTPrepareTransferResponse struct {
+	BundleHash trinary.Hash `json:"bundleHash"`
+	// Trytes are the transactions of the bundle, ordered from the head to the tail transaction.
+	Trytes []trinary.Trytes `json:"trytes"`
+	// Inputs are the signatures the client has to create for the bundle hash.
+	Inputs []*RESTTransferSignatureRequest `json:"inputs"`
+}
+
+// RESTTransferSignatureRequest describes the signature an input of a prepared bundle requires.
+type RESTTransferSignatureRequest struct {
+	Address       trinary.Hash         `json:"address"`
+	SecurityLevel consts.SecurityLevel `json:"securityLevel"`
+	// Index is the index of the first transaction of the input in the bundle.
+	Index   uint64 `json:"index"`
+	Balance uint64 `json:"balance"`
+}
+
+////////////////// POST /api/v1/transfers/finalize /////////////////
+
+// RESTFinalizeTransferRequest is the body of a request to add the signatures of the client to a prepared bundle.
+type RESTFinalizeTransferRequest struct {
+	Trytes     []trinary.Trytes         `json:"trytes" binding:"required"`
+	Signatures []*RESTTransferSignature `json:"signatures"`
+}
+
+// RESTTransferSignature contains the signature fragments of an input, one fragment per security level.
+type RESTTransferSignature struct {
+	Address   trinary.Hash     `json:"address" binding:"required"`
+	Fragments []trinary.Trytes `json:"fragments" binding:"required"`
+}
+
+// RESTFinalizeTransferResponse contains the signed bundle, which was validated against the ledger of the node.
+// It is submitted with POST /api/v1/transactions.
+type RESTFinalizeTransferResponse struct {
+	// Trytes are the transactions of the bundle, ordered from the head to the tail transaction.
+	Trytes []trinary.Trytes `json:"trytes"`
+}
+
+////////////////// POST /api/v1/transfers/dryrun ///////////////////
+
+// RESTDryRunTransferRequest is the body of a request to simulate the application of a signed bundle to the ledger.
+type RESTDryRunTransferRequest struct {
+	Trytes []trinary.Trytes `json:"trytes" binding:"required"`
+}
+
+// RESTDryRunTransferResponse is the result of simulating the application of a bundle to the ledger of the node.
+// The ledger is not changed by the simulation.
+type RESTDryRunTransferResponse struct {
+	BundleHash trinary.Hash `json:"bundleHash"`
+	// Valid is whether the bundle can be confirmed, otherwise Error contains the reason.
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+	// LedgerIndex is the milestone index of the ledger the bundle was simulated against.
+	LedgerIndex milestone.Index `json:"ledgerIndex"`
+	// Changes are the balance changes of the addresses, in the order they appear in the bundle.
+	Changes []*RESTDryRunAddressChange `json:"changes"`
+}
+
+// RESTDryRunAddressChange is the simulated change of the balance of an address.
+type RESTDryRunAddressChange struct {
+	Address trinary.Hash `json:"address"`
+	// Balance is the confirmed balance before the bundle is applied.
+	Balance uint64 `json:"balance"`
+	Change  int64  `json:"change"`
+	Spent   bool   `json:"spent"`
+	// ConflictingBundles are the hashes of the other unconfirmed bundles which spend from the address.
+	ConflictingBundles []trinary.Hash `json:"conflictingBundles,omitempty"`
+}
+
+////////////////// GET /api/v1/outbox //////////////////////////////
+
+// RESTOutboxResponse contains the bundles tracked by the outbox.
+type RESTOutboxResponse struct {
+	Items []*RESTOutboxItemResponse `json:"items"`
+}
+
+////////////////// GET /api/v1/outbox/:hash ////////////////////////
+
+// RESTOutboxItemResponse contains the state of a bundle tracked by the outbox.
+type RESTOutboxItemResponse struct {
+	// TailHash is the tail the bundle was submitted with.
+	TailHash trinary.Hash `json:"tailHash"`
+	// LatestTailHash is the tail of the latest attachment of the bundle.
+	LatestTailHash trinary.Hash   `json:"latestTailHash"`
+	Reattachments  []trinary.Hash `json:"reattachments"`
+	Rebroadcasts   int            `json:"rebroadcasts"`
+	// The unix timestamps of the submission, the latest attachment and the latest broadcast.
+	SubmittedAt int64 `json:"submittedAt"`
+	AttachedAt  int64 `json:"attachedAt"`
+	BroadcastAt int64 `json:"broadcastAt"`
+	// LastError is the error of the latest check of the bundle, if any.
+	LastError string `json:"lastError,omitempty"`
+}
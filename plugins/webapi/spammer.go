@@ -14,8 +14,8 @@ import (
 func spammerRoute() {
 	api.GET("/spammer", func(c *gin.Context) {
 
-		if !networkWhitelisted(c) {
-			// network is not whitelisted, check if the route is permitted, otherwise deny it.
+		if !privilegedAccess(c) {
+			// network is not whitelisted and no valid JWT given, check if the route is permitted, otherwise deny it.
 			if _, permitted := permittedRESTroutes["spammer"]; !permitted {
 				c.JSON(http.StatusForbidden, ErrorReturn{Error: "route [spammer] is protected"})
 				return
@@ -7,8 +7,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mitchellh/mapstructure"
 
-	"github.com/iotaledger/iota.go/address"
-
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/tangle"
 )
@@ -48,14 +46,15 @@ func wereAddressesSpentFrom(i interface{}, c *gin.Context, _ <-chan struct{}) {
 	result := WereAddressesSpentFromReturn{}
 
 	for _, addr := range query.Addresses {
-		if err := address.ValidAddress(addr); err != nil {
+		addrHash, err := hornet.AddressFromTrytes(addr)
+		if err != nil {
 			e.Error = fmt.Sprintf("Provided address invalid: %s", addr)
 			c.JSON(http.StatusBadRequest, e)
 			return
 		}
 
 		// State
-		result.States = append(result.States, tangle.WasAddressSpentFrom(hornet.HashFromAddressTrytes(addr)))
+		result.States = append(result.States, tangle.WasAddressSpentFrom(addrHash))
 	}
 
 	c.JSON(http.StatusOK, result)
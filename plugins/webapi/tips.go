@@ -34,7 +34,7 @@ func getTipInfo(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		return
 	}
 
-	if !tangle.IsNodeSyncedWithThreshold() {
+	if !tangle.IsNodeAlmostSynced() {
 		e.Error = "node is not synced"
 		c.JSON(http.StatusBadRequest, e)
 		return
@@ -48,13 +48,14 @@ func getTipInfo(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		return
 	}
 
-	if !guards.IsTransactionHash(query.TailTransaction) {
+	tailTxHash, err := hornet.HashFromTrytes(query.TailTransaction)
+	if err != nil {
 		e.Error = "invalid tail hash supplied"
 		c.JSON(http.StatusBadRequest, e)
 		return
 	}
 
-	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(hornet.HashFromHashTrytes(query.TailTransaction)) // meta +1
+	cachedTxMeta := tangle.GetCachedTxMetadataOrNil(tailTxHash) // meta +1
 	if cachedTxMeta == nil {
 		e.Error = "unknown tail transaction"
 		c.JSON(http.StatusBadRequest, e)
@@ -7,14 +7,14 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
 
-	"github.com/iotaledger/iota.go/address"
-	"github.com/iotaledger/iota.go/guards"
 	"github.com/iotaledger/iota.go/trinary"
 
 	"github.com/gohornet/hornet/pkg/config"
 	"github.com/gohornet/hornet/pkg/model/hornet"
 	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/protocol/processor"
 	"github.com/gohornet/hornet/plugins/gossip"
 )
 
@@ -34,6 +34,13 @@ func broadcastTransactions(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		return
 	}
 
+	// transactions of an unsync node would reference old tips and might not get confirmed
+	if !tangle.IsNodeAlmostSynced() {
+		e.Error = ErrNodeNotSync.Error()
+		c.JSON(http.StatusBadRequest, e)
+		return
+	}
+
 	if len(query.Trytes) == 0 {
 		e.Error = "No trytes provided"
 		c.JSON(http.StatusBadRequest, e)
@@ -48,13 +55,17 @@ func broadcastTransactions(i interface{}, c *gin.Context, _ <-chan struct{}) {
 		}
 	}
 
-	for _, trytes := range query.Trytes {
-		if err := gossip.Processor().ValidateTransactionTrytesAndEmit(trytes); err != nil {
-			e.Error = err.Error()
-			c.JSON(http.StatusBadRequest, e)
+	if err := gossip.Processor().SubmitTransactionTrytes(query.Trytes); err != nil {
+		e.Error = err.Error()
+		if errors.Is(err, processor.ErrSubmissionQueueFull) {
+			c.Header("Retry-After", submissionRetryAfterSeconds)
+			c.JSON(http.StatusServiceUnavailable, e)
 			return
 		}
+		c.JSON(http.StatusBadRequest, e)
+		return
 	}
+	outboxAddTrytes(query.Trytes, nil)
 	c.JSON(http.StatusOK, BradcastTransactionsReturn{})
 }
 
@@ -101,24 +112,23 @@ func findTransactions(i interface{}, c *gin.Context, _ <-chan struct{}) {
 	}
 
 	for _, approveeTrytes := range query.Approvees {
-		if !guards.IsTransactionHash(approveeTrytes) {
+		approveeHash, err := hornet.HashFromTrytes(approveeTrytes)
+		if err != nil {
 			e.Error = fmt.Sprintf("aprovee hash invalid: %s", approveeTrytes)
 			c.JSON(http.StatusBadRequest, e)
 			return
 		}
-		queryApproveeHashes[string(hornet.HashFromHashTrytes(approveeTrytes))] = struct{}{}
+		queryApproveeHashes[string(approveeHash)] = struct{}{}
 	}
 
 	for _, addressTrytes := range query.Addresses {
-		if err := address.ValidAddress(addressTrytes); err != nil {
+		addressHash, err := hornet.AddressFromTrytes(addressTrytes)
+		if err != nil {
 			e.Error = fmt.Sprintf("address hash invalid: %s", addressTrytes)
 			c.JSON(http.StatusBadRequest, e)
 			return
 		}
-		if len(addressTrytes) == 90 {
-			addressTrytes = addressTrytes[:81]
-		}
-		queryAddressHashes[string(hornet.HashFromHashTrytes(addressTrytes))] = struct{}{}
+		queryAddressHashes[string(addressHash)] = struct{}{}
 	}
 
 	for _, tagTrytes := range query.Tags {
@@ -236,7 +246,13 @@ func findTransactions(i interface{}, c *gin.Context, _ <-chan struct{}) {
 	var j int
 	txHashes := make([]string, len(results))
 	for r := range results {
-		txHashes[j] = hornet.Hash(r).Trytes()
+		txHash, err := hornet.Hash(r).ToTrytes()
+		if err != nil {
+			e.Error = fmt.Sprintf("%v: %v", ErrInternalError, err)
+			c.JSON(http.StatusInternalServerError, e)
+			return
+		}
+		txHashes[j] = txHash
 		j++
 	}
 
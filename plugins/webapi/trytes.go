@@ -9,7 +9,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/mitchellh/mapstructure"
 
-	"github.com/iotaledger/iota.go/guards"
 	"github.com/iotaledger/iota.go/transaction"
 
 	"github.com/gohornet/hornet/pkg/config"
@@ -41,17 +40,20 @@ func getTrytes(i interface{}, c *gin.Context, _ <-chan struct{}) {
 
 	trytes := []string{}
 
-	for _, hash := range query.Hashes {
-		if !guards.IsTransactionHash(hash) {
+	txHashes := make(hornet.Hashes, len(query.Hashes))
+	for i, hash := range query.Hashes {
+		txHash, err := hornet.HashFromTrytes(hash)
+		if err != nil {
 			e.Error = fmt.Sprintf("Invalid hash supplied: %s", hash)
 			c.JSON(http.StatusBadRequest, e)
 			return
 		}
+		txHashes[i] = txHash
 	}
 
-	for _, hash := range query.Hashes {
+	for _, txHash := range txHashes {
 
-		cachedTx := tangle.GetCachedTransactionOrNil(hornet.HashFromHashTrytes(hash)) // tx +1
+		cachedTx := tangle.GetCachedTransactionOrNil(txHash) // tx +1
 
 		if cachedTx == nil {
 			trytes = append(trytes, strings.Repeat("9", 2673))
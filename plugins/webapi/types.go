@@ -1,8 +1,10 @@
 package webapi
 
 import (
+	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/iota.go/trinary"
 
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	"github.com/gohornet/hornet/pkg/peering/peer"
 )
@@ -26,6 +28,7 @@ type Neighbor struct {
 	Identity   string `mapstructure:"identity"`
 	Alias      string `mapstructure:"alias"`
 	PreferIPv6 bool   `mapstructure:"prefer_ipv6"`
+	PublicKey  string `mapstructure:"public_key"`
 }
 
 // AddNeighborsResponse struct
@@ -84,6 +87,12 @@ type ResultReturn struct {
 	Message string `json:"message"`
 }
 
+// LoggerLevelsReturn contains the default level and the levels of the components of the logger.
+type LoggerLevelsReturn struct {
+	Level      logger.Level              `json:"level"`
+	Components []*logging.ComponentLevel `json:"components"`
+}
+
 /////////////////// findTransactions //////////////////////////////
 
 // FindTransactions struct
@@ -172,6 +181,7 @@ type GetNodeInfoReturn struct {
 	TransactionsToRequest              int             `json:"transactionsToRequest"`
 	Features                           []string        `json:"features"`
 	CoordinatorAddress                 trinary.Hash    `json:"coordinatorAddress"`
+	NetworkID                          string          `json:"networkID"`
 	Duration                           int             `json:"duration"`
 }
 
@@ -392,6 +402,22 @@ type DebugRequest struct {
 	MilestoneIndex   milestone.Index `json:"milestoneIndex"`
 }
 
+///////////////////// getStorePrefixes ////////////////////////////
+
+// GetStorePrefixesReturn struct
+type GetStorePrefixesReturn struct {
+	Prefixes []*StorePrefix `json:"prefixes"`
+	// the prefixes with entries which are not registered for the database they were found in, keyed by database
+	UnknownPrefixes map[string][]int `json:"unknownPrefixes"`
+}
+
+// StorePrefix struct
+type StorePrefix struct {
+	Prefix   int    `json:"prefix"`
+	Name     string `json:"name"`
+	Database string `json:"database"`
+}
+
 ///////////////// searchConfirmedApprover /////////////////////////
 
 // SearchConfirmedApprover struct
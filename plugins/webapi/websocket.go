@@ -0,0 +1,307 @@
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/syncutils"
+	"github.com/iotaledger/hive.go/websockethub"
+	"github.com/iotaledger/iota.go/trinary"
+	"go.uber.org/atomic"
+
+	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/model/hornet"
+	"github.com/gohornet/hornet/pkg/model/milestone"
+	"github.com/gohornet/hornet/pkg/model/tangle"
+	"github.com/gohornet/hornet/pkg/shutdown"
+	"github.com/gohornet/hornet/pkg/watch"
+	"github.com/gohornet/hornet/pkg/whiteflag"
+	tangleplugin "github.com/gohornet/hornet/plugins/tangle"
+)
+
+const (
+	// the route of the websocket streaming API
+	wsRoute = restAPIBase + "/ws"
+
+	wsBroadcastQueueSize    = 20000
+	wsClientSendChannelSize = 1000
+	wsClientReceiveChanSize = 100
+	wsHandshakeTimeout      = 3 * time.Second
+
+	// wsCmdSubscribe subscribes the client to a topic.
+	wsCmdSubscribe = "subscribe"
+	// wsCmdUnsubscribe unsubscribes the client from a topic.
+	wsCmdUnsubscribe = "unsubscribe"
+	// wsCmdWatch adds addresses to the watched addresses of the client.
+	wsCmdWatch = "watch"
+	// wsCmdUnwatch removes addresses from the watched addresses of the client.
+	wsCmdUnwatch = "unwatch"
+)
+
+// Topics of the websocket streaming API
+const (
+	wsTopicTransactions          = "transactions"
+	wsTopicTransactionsSolid     = "transactions/solid"
+	wsTopicTransactionsConfirmed = "transactions/confirmed"
+	wsTopicMilestonesLatest      = "milestones/latest"
+	wsTopicMilestonesConfirmed   = "milestones/confirmed"
+
+	// the changes of the watched addresses are sent to the watching client only, without a subscription
+	wsTopicAddressesWatched = "addresses/watched"
+)
+
+var (
+	wsHub *websockethub.Hub
+
+	// the address sets watched by the websocket clients
+	wsWatchRegistry *watch.Registry
+	wsClientCounter atomic.Uint64
+
+	wsTopics = map[string]struct{}{
+		wsTopicTransactions:          {},
+		wsTopicTransactionsSolid:     {},
+		wsTopicTransactionsConfirmed: {},
+		wsTopicMilestonesLatest:      {},
+		wsTopicMilestonesConfirmed:   {},
+	}
+)
+
+// WSCommand is sent by websocket clients to (un)subscribe to a topic or to (un)watch addresses.
+type WSCommand struct {
+	Cmd       string         `json:"cmd"`
+	Topic     string         `json:"topic,omitempty"`
+	Addresses []trinary.Hash `json:"addresses,omitempty"`
+}
+
+// WSMessage is sent to websocket clients which are subscribed to the topic of the message.
+type WSMessage struct {
+	Topic string      `json:"topic"`
+	Data  interface{} `json:"data"`
+}
+
+// WSTransactionSolid is the data of a message of the solid transactions topic.
+type WSTransactionSolid struct {
+	TxHash trinary.Hash `json:"txHash"`
+}
+
+// WSTransactionConfirmed is the data of a message of the confirmed transactions topic.
+type WSTransactionConfirmed struct {
+	TxHash               trinary.Hash                `json:"txHash"`
+	MilestoneIndex       milestone.Index             `json:"milestoneIndex"`
+	ConfirmationTime     int64                       `json:"confirmationTime"`
+	LedgerInclusionState hornet.LedgerInclusionState `json:"ledgerInclusionState"`
+}
+
+// WSAddressChange is an element of the data of a message of the watched addresses topic.
+type WSAddressChange struct {
+	Address        trinary.Hash    `json:"address"`
+	Balance        uint64          `json:"balance"`
+	Delta          int64           `json:"delta"`
+	MilestoneIndex milestone.Index `json:"milestoneIndex"`
+}
+
+func configureWebsocket() {
+	upgrader := &websocket.Upgrader{
+		HandshakeTimeout:  wsHandshakeTimeout,
+		CheckOrigin:       func(r *http.Request) bool { return true }, // allow any origin for websocket connections
+		EnableCompression: true,
+	}
+
+	wsHub = websockethub.NewHub(log, upgrader, wsBroadcastQueueSize, wsClientSendChannelSize)
+	wsWatchRegistry = watch.NewRegistry(config.NodeConfig.GetInt(config.CfgWebAPILimitsWatchAddresses))
+}
+
+// restWebsocket upgrades the connection to a websocket and streams the messages of the subscribed topics to the client.
+func restWebsocket(c *gin.Context) {
+	topicsLock := syncutils.RWMutex{}
+	subscribedTopics := make(map[string]struct{})
+	watchID := fmt.Sprintf("ws-%d", wsClientCounter.Inc())
+
+	wsHub.ServeWebsocket(c.Writer, c.Request,
+		// onCreate gets called when the client is created
+		func(client *websockethub.Client) {
+			client.FilterCallback = func(_ *websockethub.Client, data interface{}) bool {
+				msg, ok := data.(*WSMessage)
+				if !ok {
+					return false
+				}
+
+				topicsLock.RLock()
+				_, subscribed := subscribedTopics[msg.Topic]
+				topicsLock.RUnlock()
+				return subscribed
+			}
+			client.ReceiveChan = make(chan *websockethub.WebsocketMsg, wsClientReceiveChanSize)
+
+			wsWatchRegistry.Register(watchID, func(changes []*watch.AddressChange) {
+				client.Send(&WSMessage{Topic: wsTopicAddressesWatched, Data: wsAddressChanges(changes)})
+			})
+
+			go func() {
+				defer wsWatchRegistry.Unregister(watchID)
+
+				for {
+					select {
+					case <-client.ExitSignal:
+						// client was disconnected
+						return
+
+					case msg, ok := <-client.ReceiveChan:
+						if !ok {
+							// client was disconnected
+							return
+						}
+
+						if msg.MsgType != websockethub.TextMessage {
+							continue
+						}
+
+						cmd := &WSCommand{}
+						if err := json.Unmarshal(msg.Data, cmd); err != nil {
+							continue
+						}
+
+						switch cmd.Cmd {
+						case wsCmdWatch, wsCmdUnwatch:
+							addresses, err := wsParseAddresses(cmd.Addresses)
+							if err != nil {
+								log.Debugf("WebSocket client sent invalid addresses: %s", err)
+								continue
+							}
+
+							if cmd.Cmd == wsCmdUnwatch {
+								wsWatchRegistry.Unwatch(watchID, addresses...)
+								continue
+							}
+
+							if err := wsWatchRegistry.Watch(watchID, addresses...); err != nil {
+								log.Debugf("WebSocket client can't watch addresses: %s", err)
+							}
+							continue
+						}
+
+						if _, exists := wsTopics[cmd.Topic]; !exists {
+							continue
+						}
+
+						switch cmd.Cmd {
+						case wsCmdSubscribe:
+							topicsLock.Lock()
+							subscribedTopics[cmd.Topic] = struct{}{}
+							topicsLock.Unlock()
+
+						case wsCmdUnsubscribe:
+							topicsLock.Lock()
+							delete(subscribedTopics, cmd.Topic)
+							topicsLock.Unlock()
+						}
+					}
+				}
+			}()
+		},
+
+		// onConnect gets called when the client was registered
+		func(_ *websockethub.Client) {
+			log.Debug("WebSocket client connection established")
+		})
+}
+
+func runWebsocket() {
+
+	onReceivedNewTransaction := events.NewClosure(func(cachedTx *tangle.CachedTransaction, _ milestone.Index, _ milestone.Index) {
+		cachedTx.ConsumeTransaction(func(tx *hornet.Transaction) { // tx -1
+			if !tangle.IsNodeAlmostSynced() {
+				return
+			}
+			wsHub.BroadcastMsg(&WSMessage{Topic: wsTopicTransactions, Data: tx.Tx})
+		})
+	})
+
+	onTransactionSolid := events.NewClosure(func(txHash hornet.Hash) {
+		if !tangle.IsNodeAlmostSynced() {
+			return
+		}
+		wsHub.BroadcastMsg(&WSMessage{Topic: wsTopicTransactionsSolid, Data: &WSTransactionSolid{TxHash: txHash.Trytes()}})
+	})
+
+	onTransactionConfirmed := events.NewClosure(func(cachedMeta *tangle.CachedMetadata, msIndex milestone.Index, confTime int64) {
+		cachedMeta.ConsumeMetadata(func(metadata *hornet.TransactionMetadata) { // meta -1
+			if !tangle.IsNodeAlmostSynced() {
+				return
+			}
+			wsHub.BroadcastMsg(&WSMessage{Topic: wsTopicTransactionsConfirmed, Data: &WSTransactionConfirmed{
+				TxHash:               metadata.GetTxHash().Trytes(),
+				MilestoneIndex:       msIndex,
+				ConfirmationTime:     confTime,
+				LedgerInclusionState: metadata.GetLedgerInclusionState(),
+			}})
+		})
+	})
+
+	onLatestMilestoneChanged := events.NewClosure(func(cachedBndl *tangle.CachedBundle) {
+		cachedBndl.ConsumeBundle(func(bndl *tangle.Bundle) { // bundle -1
+			wsHub.BroadcastMsg(&WSMessage{Topic: wsTopicMilestonesLatest, Data: &RESTMilestoneResponse{
+				Index: bndl.GetMilestoneIndex(),
+				Hash:  bndl.GetMilestoneHash().Trytes(),
+			}})
+		})
+	})
+
+	onMilestoneConfirmed := events.NewClosure(func(confirmation *whiteflag.Confirmation) {
+		wsWatchRegistry.OnMilestoneConfirmed(confirmation)
+		wsHub.BroadcastMsg(&WSMessage{Topic: wsTopicMilestonesConfirmed, Data: &RESTMilestoneResponse{
+			Index: confirmation.MilestoneIndex,
+			Hash:  confirmation.MilestoneHash.Trytes(),
+		}})
+	})
+
+	daemon.BackgroundWorker("WebAPI[WebSocket]", func(shutdownSignal <-chan struct{}) {
+		go wsHub.Run(shutdownSignal)
+		tangleplugin.Events.ReceivedNewTransaction.Attach(onReceivedNewTransaction)
+		tangleplugin.Events.TransactionSolid.Attach(onTransactionSolid)
+		tangleplugin.Events.TransactionConfirmed.Attach(onTransactionConfirmed)
+		tangleplugin.Events.LatestMilestoneChanged.Attach(onLatestMilestoneChanged)
+		tangleplugin.Events.MilestoneConfirmed.Attach(onMilestoneConfirmed)
+		<-shutdownSignal
+		log.Info("Stopping WebAPI[WebSocket] ...")
+		tangleplugin.Events.ReceivedNewTransaction.Detach(onReceivedNewTransaction)
+		tangleplugin.Events.TransactionSolid.Detach(onTransactionSolid)
+		tangleplugin.Events.TransactionConfirmed.Detach(onTransactionConfirmed)
+		tangleplugin.Events.LatestMilestoneChanged.Detach(onLatestMilestoneChanged)
+		tangleplugin.Events.MilestoneConfirmed.Detach(onMilestoneConfirmed)
+		log.Info("Stopping WebAPI[WebSocket] ... done")
+	}, shutdown.PriorityAPI)
+}
+
+// wsParseAddresses validates the given addresses and converts them to their binary representation.
+func wsParseAddresses(addresses []trinary.Hash) (hornet.Hashes, error) {
+	result := make(hornet.Hashes, len(addresses))
+	for i, addr := range addresses {
+		addrHash, err := hornet.AddressFromTrytes(addr)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = addrHash
+	}
+	return result, nil
+}
+
+func wsAddressChanges(changes []*watch.AddressChange) []*WSAddressChange {
+	result := make([]*WSAddressChange, len(changes))
+	for i, change := range changes {
+		result[i] = &WSAddressChange{
+			Address:        change.Address.Trytes(),
+			Balance:        change.Balance,
+			Delta:          change.Delta,
+			MilestoneIndex: change.MilestoneIndex,
+		}
+	}
+	return result
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/iotaledger/hive.go/workerpool"
 
 	"github.com/gohornet/hornet/pkg/config"
+	"github.com/gohornet/hornet/pkg/logging"
 	"github.com/gohornet/hornet/pkg/model/milestone"
 	tanglePackage "github.com/gohornet/hornet/pkg/model/tangle"
 	"github.com/gohornet/hornet/pkg/shutdown"
@@ -50,7 +51,7 @@ var (
 
 // Configure the zmq plugin
 func configure(plugin *node.Plugin) {
-	log = logger.NewLogger(plugin.Name)
+	log = logging.NewLogger(plugin.Name)
 
 	newTxWorkerPool = workerpool.New(func(task workerpool.Task) {
 		onNewTx(task.Param(0).(*tanglePackage.CachedTransaction)) // tx pass +1
@@ -84,7 +85,7 @@ func run(_ *node.Plugin) {
 
 	onReceivedNewTransaction := events.NewClosure(func(cachedTx *tanglePackage.CachedTransaction, latestMilestoneIndex milestone.Index, latestSolidMilestoneIndex milestone.Index) {
 		if !wasSyncBefore {
-			if !tanglePackage.IsNodeSyncedWithThreshold() {
+			if !tanglePackage.IsNodeAlmostSynced() {
 				cachedTx.Release(true) // tx -1
 				return
 			}